@@ -0,0 +1,290 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/errcode"
+	"github.com/sammcj/skint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// annotationAlias marks the commands AddAliasCommands adds for aliases.
+const annotationAlias = "skint:alias"
+
+// NewAliasCmd creates the alias command
+func NewAliasCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "alias",
+		Short: "Manage shortcuts for provider and model combinations",
+		Long: `Aliases name a set of 'skint use' arguments, such as a provider and model,
+so 'skint glm' (or 'skint run glm') launches Claude with them. Arguments
+after the alias are added to the end.
+
+Aliases are stored in the config's aliases section. An alias can't share a
+name with a skint command.`,
+		Example: `  skint alias add glm "zai --model glm-5-air"
+  skint alias add review ollama --preset corp-proxy --continue
+  skint glm
+  skint run glm --continue
+  skint alias remove glm`,
+		Args: cobra.NoArgs,
+		RunE: runAliasList,
+	}
+	cmd.AddCommand(NewAliasListCmd())
+	cmd.AddCommand(NewAliasAddCmd())
+	cmd.AddCommand(NewAliasRemoveCmd())
+	return cmd
+}
+
+// NewAliasListCmd creates the alias list command
+func NewAliasListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List aliases",
+		Args:    cobra.NoArgs,
+		RunE:    runAliasList,
+	}
+}
+
+// NewAliasAddCmd creates the alias add command
+func NewAliasAddCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add <name> <provider> [args...]",
+		Short: "Add or replace an alias",
+		Long: `Add an alias for 'skint use' arguments. They can be given as one quoted
+string or as separate arguments.`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: runAliasAdd,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+			if len(args) == 1 {
+				return providerCompletions(completionConfig(cmd), toComplete), cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+	// Flags after the name belong to the alias, not to skint
+	cmd.Flags().SetInterspersed(false)
+	return cmd
+}
+
+// NewAliasRemoveCmd creates the alias remove command
+func NewAliasRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "remove <name>",
+		Aliases:           []string{"rm"},
+		Short:             "Remove an alias",
+		Args:              cobra.ExactArgs(1),
+		RunE:              runAliasRemove,
+		ValidArgsFunction: completeAlias,
+	}
+}
+
+// NewRunCmd creates the run command
+func NewRunCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "run <alias> [args...]",
+		Short: "Launch Claude with an alias",
+		Long: `Launch Claude with an alias's 'skint use' arguments, followed by any
+arguments given here. 'skint <alias>' does the same.`,
+		Example: `  skint run glm
+  skint run glm --continue`,
+		Args:               cobra.MinimumNArgs(1),
+		RunE:               func(cmd *cobra.Command, args []string) error { return runAlias(cmd, args[0], args[1:]) },
+		ValidArgsFunction:  completeAlias,
+		DisableFlagParsing: true,
+	}
+}
+
+// AddAliasCommands adds a command for each alias in the config, so 'skint
+// <alias>' works. Call it once every built-in command has been added: aliases
+// named like one of them are left out. The config isn't loaded yet, so the
+// aliases are read from the file given with --config, or the default one.
+func (r *RootCmd) AddAliasCommands() {
+	aliases := config.ReadAliases(configFlag(os.Args[1:]))
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		if config.ValidateAliasName(name) != nil || isReservedName(r.Command, name) {
+			continue
+		}
+		r.AddCommand(&cobra.Command{
+			Use:         name + " [args...]",
+			Short:       "Alias for skint use " + config.JoinArgs(aliases[name]),
+			Annotations: map[string]string{annotationAlias: "true"},
+			RunE:        func(cmd *cobra.Command, args []string) error { return runAlias(cmd, name, args) },
+			// Like skint use, everything after the alias passes through
+			DisableFlagParsing: true,
+		})
+	}
+}
+
+// configFlag returns the value of a --config flag in args, or "".
+func configFlag(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if v, ok := strings.CutPrefix(arg, "--config="); ok {
+			return v
+		}
+		if arg == "--config" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// isReservedName reports whether name is taken by one of root's commands,
+// including the help and completion commands cobra adds when run, but not
+// by an alias.
+func isReservedName(root *cobra.Command, name string) bool {
+	switch name {
+	case "help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return true
+	}
+	for _, c := range root.Commands() {
+		if c.Annotations[annotationAlias] == "" && (c.Name() == name || c.HasAlias(name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// runAlias runs skint use with the alias's arguments followed by args.
+func runAlias(cmd *cobra.Command, name string, args []string) error {
+	cc := GetContext(cmd)
+	aliasArgs, ok := cc.Cfg.Aliases[name]
+	if !ok {
+		return errcode.New(errcode.Usage, "unknown alias %s. Run 'skint alias list' to see them", name)
+	}
+	return runUse(cmd, append(slices.Clone(aliasArgs), args...))
+}
+
+func runAliasList(cmd *cobra.Command, args []string) error {
+	cc := GetContext(cmd)
+	names := make([]string, 0, len(cc.Cfg.Aliases))
+	for name := range cc.Cfg.Aliases {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	switch cc.Cfg.OutputFormat {
+	case config.FormatJSON:
+		aliases := cc.Cfg.Aliases
+		if aliases == nil {
+			aliases = map[string][]string{}
+		}
+		return cc.Output(map[string]any{"aliases": aliases})
+	case config.FormatPlain:
+		for _, name := range names {
+			fmt.Printf("%s\t%s\n", name, config.JoinArgs(cc.Cfg.Aliases[name]))
+		}
+		return nil
+	}
+
+	if len(names) == 0 {
+		ui.Info("No aliases. Add one with 'skint alias add <name> <provider> [args...]'")
+		return nil
+	}
+	fmt.Println()
+	rows := make([][]string, 0, len(names))
+	for _, name := range names {
+		rows = append(rows, []string{name, "skint use " + config.JoinArgs(cc.Cfg.Aliases[name])})
+	}
+	ui.Table([]string{"ALIAS", "RUNS"}, rows)
+	fmt.Println()
+	return nil
+}
+
+func runAliasAdd(cmd *cobra.Command, args []string) error {
+	cc := GetContext(cmd)
+	name, aliasArgs := args[0], args[1:]
+	if err := config.ValidateAliasName(name); err != nil {
+		return err
+	}
+	if isReservedName(cmd.Root(), name) {
+		return fmt.Errorf("%s is a skint command, so it can't be an alias", name)
+	}
+	// One argument is the quoted form: "zai --model glm-5-air"
+	if len(aliasArgs) == 1 {
+		split, err := config.SplitArgs(aliasArgs[0])
+		if err != nil {
+			return fmt.Errorf("invalid alias arguments: %w", err)
+		}
+		aliasArgs = split
+	}
+	if len(aliasArgs) == 0 {
+		return fmt.Errorf("alias %s needs a provider or arguments for 'skint use'", name)
+	}
+	if provider := aliasArgs[0]; !strings.HasPrefix(provider, "-") && provider != "native" && cc.Cfg.GetProvider(provider) == nil {
+		return errcode.New(errcode.ProviderNotFound, "provider %s is not configured. Run 'skint config %s' first", provider, provider)
+	}
+
+	_, replaced := cc.Cfg.Aliases[name]
+	if cc.Cfg.Aliases == nil {
+		cc.Cfg.Aliases = map[string][]string{}
+	}
+	cc.Cfg.Aliases[name] = aliasArgs
+	if err := cc.SaveConfig(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	switch cc.Cfg.OutputFormat {
+	case config.FormatJSON:
+		return cc.Output(map[string]any{"alias": name, "args": aliasArgs, "replaced": replaced})
+	case config.FormatPlain:
+		fmt.Println(name)
+		return nil
+	}
+	verb := "Added"
+	if replaced {
+		verb = "Updated"
+	}
+	ui.Success("%s alias %s: skint use %s", verb, ui.Yellow(name), config.JoinArgs(aliasArgs))
+	ui.Dim("Run it with 'skint %s' or 'skint run %s'\n", name, name)
+	return nil
+}
+
+func runAliasRemove(cmd *cobra.Command, args []string) error {
+	cc := GetContext(cmd)
+	name := args[0]
+	if _, ok := cc.Cfg.Aliases[name]; !ok {
+		return fmt.Errorf("alias not found: %s", name)
+	}
+	delete(cc.Cfg.Aliases, name)
+	if err := cc.SaveConfig(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	if cc.Cfg.OutputFormat == config.FormatJSON {
+		return cc.Output(map[string]any{"alias": name, "removed": true})
+	}
+	ui.Success("Removed alias: %s", name)
+	return nil
+}
+
+// completeAlias completes the first argument with an alias name.
+func completeAlias(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+	var out []cobra.Completion
+	if cfg := completionConfig(cmd); cfg != nil {
+		for name, aliasArgs := range cfg.Aliases {
+			if strings.HasPrefix(name, toComplete) {
+				out = append(out, cobra.CompletionWithDesc(name, "skint use "+config.JoinArgs(aliasArgs)))
+			}
+		}
+	}
+	slices.Sort(out)
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestConfigFlag(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"glm", "--continue"}, ""},
+		{[]string{"--config", "a.yaml", "glm"}, "a.yaml"},
+		{[]string{"glm", "--config=b.yaml"}, "b.yaml"},
+		{[]string{"exec", "--", "tool", "--config", "c.yaml"}, ""},
+	}
+	for _, tt := range tests {
+		if got := configFlag(tt.args); got != tt.want {
+			t.Errorf("configFlag(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestIsReservedName(t *testing.T) {
+	root := &cobra.Command{Use: "skint"}
+	root.AddCommand(&cobra.Command{Use: "list", Aliases: []string{"ls"}})
+	root.AddCommand(&cobra.Command{Use: "glm", Annotations: map[string]string{annotationAlias: "true"}})
+
+	for name, want := range map[string]bool{"list": true, "ls": true, "help": true, "completion": true, "glm": false, "zai": false} {
+		if got := isReservedName(root, name); got != want {
+			t.Errorf("isReservedName(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
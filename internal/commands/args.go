@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/sammcj/skint/internal/errcode"
+)
+
+// extractFlag removes every "--name value" and "--name=value" occurrence from
+// args, returning the collected values and the remaining args. Used by
+// commands that disable cobra flag parsing so unknown flags pass through to
+// claude. Scanning stops at "--"; everything after it is passed through as-is.
+func extractFlag(args []string, name string) (values, rest []string, err error) {
+	flag := "--" + name
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			return values, append(rest, args[i:]...), nil
+		case arg == flag:
+			if i+1 >= len(args) {
+				return nil, nil, errcode.New(errcode.Usage, "flag needs an argument: %s", flag)
+			}
+			values = append(values, args[i+1])
+			i++
+		case strings.HasPrefix(arg, flag+"="):
+			values = append(values, strings.TrimPrefix(arg, flag+"="))
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return values, rest, nil
+}
+
+// extractBoolFlag removes every "--name" from args, reporting whether there
+// was one. Like extractFlag, scanning stops at "--".
+func extractBoolFlag(args []string, name string) (set bool, rest []string) {
+	flag := "--" + name
+	for i, arg := range args {
+		switch arg {
+		case "--":
+			return set, append(rest, args[i:]...)
+		case flag:
+			set = true
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return set, rest
+}
@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestExtractFlag(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantValues []string
+		wantRest   []string
+		wantErr    bool
+	}{
+		{
+			name:     "flag absent",
+			args:     []string{"zai", "--model", "glm-5"},
+			wantRest: []string{"zai", "--model", "glm-5"},
+		},
+		{
+			name:       "separate value before provider",
+			args:       []string{"--preset", "corp-proxy", "zai"},
+			wantValues: []string{"corp-proxy"},
+			wantRest:   []string{"zai"},
+		},
+		{
+			name:       "equals form and repeated",
+			args:       []string{"zai", "--preset=a", "--continue", "--preset", "b"},
+			wantValues: []string{"a", "b"},
+			wantRest:   []string{"zai", "--continue"},
+		},
+		{
+			name:     "scanning stops at double dash",
+			args:     []string{"zai", "--", "--preset", "x"},
+			wantRest: []string{"zai", "--", "--preset", "x"},
+		},
+		{
+			name:    "missing value",
+			args:    []string{"zai", "--preset"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			values, rest, err := extractFlag(tc.args, "preset")
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("extractFlag: %v", err)
+			}
+			if !slices.Equal(values, tc.wantValues) {
+				t.Errorf("values = %v, want %v", values, tc.wantValues)
+			}
+			if !slices.Equal(rest, tc.wantRest) {
+				t.Errorf("rest = %v, want %v", rest, tc.wantRest)
+			}
+		})
+	}
+}
+
+func TestExtractBoolFlag(t *testing.T) {
+	set, rest := extractBoolFlag([]string{"zai", "--save", "--continue", "--", "--save"}, "save")
+	if !set || !slices.Equal(rest, []string{"zai", "--continue", "--", "--save"}) {
+		t.Errorf("got %v, %v", set, rest)
+	}
+	if set, _ := extractBoolFlag([]string{"zai", "--saved"}, "save"); set {
+		t.Error("--saved is not --save")
+	}
+}
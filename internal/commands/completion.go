@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/providers"
+	"github.com/spf13/cobra"
+)
+
+// isCompletionCmd reports whether cmd generates a completion script or
+// answers a completion request from one. These must never prompt, and should
+// still work with a broken config.
+func isCompletionCmd(cmd *cobra.Command) bool {
+	switch cmd.Name() {
+	case cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return true
+	}
+	return cmd.HasParent() && cmd.Parent().Name() == "completion"
+}
+
+// completionConfig returns the loaded config for a completion function, or
+// nil if it couldn't be loaded.
+func completionConfig(cmd *cobra.Command) *config.Config {
+	if cmd.Context() == nil {
+		return nil
+	}
+	cc, ok := cmd.Context().Value(ctxKey).(*CmdContext)
+	if !ok {
+		return nil
+	}
+	return cc.Cfg
+}
+
+// providerCompletions returns the configured providers starting with
+// toComplete, described by their display names.
+func providerCompletions(cfg *config.Config, toComplete string) []cobra.Completion {
+	var out []cobra.Completion
+	if cfg == nil {
+		return out
+	}
+	for _, p := range cfg.Providers {
+		if strings.HasPrefix(p.Name, toComplete) {
+			out = append(out, cobra.CompletionWithDesc(p.Name, p.DisplayName))
+		}
+	}
+	return out
+}
+
+// builtinCompletions returns the built-in providers starting with toComplete
+// that aren't configured yet, sorted by name.
+func builtinCompletions(cfg *config.Config, toComplete string) []cobra.Completion {
+	var out []cobra.Completion
+	defs := providers.NewRegistry().List()
+	slices.SortFunc(defs, func(a, b *providers.Definition) int { return strings.Compare(a.Name, b.Name) })
+	for _, def := range defs {
+		if cfg != nil && cfg.GetProvider(def.Name) != nil {
+			continue
+		}
+		if strings.HasPrefix(def.Name, toComplete) {
+			out = append(out, cobra.CompletionWithDesc(def.Name, def.DisplayName))
+		}
+	}
+	return out
+}
+
+// completeProvider completes the first argument with a configured provider.
+func completeProvider(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return providerCompletions(completionConfig(cmd), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeAnyProvider completes the first argument with a configured
+// provider, then the built-in ones not yet configured.
+func completeAnyProvider(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	cfg := completionConfig(cmd)
+	return append(providerCompletions(cfg, toComplete), builtinCompletions(cfg, toComplete)...), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeNewProvider completes the first argument with a built-in provider
+// that isn't configured yet, or a custom one.
+func completeNewProvider(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	out := builtinCompletions(completionConfig(cmd), toComplete)
+	if strings.HasPrefix("custom", toComplete) {
+		out = append(out, cobra.CompletionWithDesc("custom", "Custom Anthropic- or OpenAI-compatible endpoint"))
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeUse completes skint use, which parses its own flags: the provider
+// first, preset names after --preset, and files for claude's arguments.
+func completeUse(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	cfg := completionConfig(cmd)
+	if n := len(args); n > 0 && args[n-1] == "--preset" {
+		var out []cobra.Completion
+		if cfg != nil {
+			for name := range cfg.EnvPresets {
+				if strings.HasPrefix(name, toComplete) {
+					out = append(out, name)
+				}
+			}
+		}
+		slices.Sort(out)
+		return out, cobra.ShellCompDirectiveNoFileComp
+	}
+	if len(args) == 0 && !strings.HasPrefix(toComplete, "-") {
+		return providerCompletions(cfg, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+	return nil, cobra.ShellCompDirectiveDefault
+}
+
+// registerFlagCompletions completes the root's persistent flag values.
+func registerFlagCompletions(root *cobra.Command) {
+	_ = root.RegisterFlagCompletionFunc("output", cobra.FixedCompletions(
+		[]cobra.Completion{config.FormatHuman, config.FormatJSON, config.FormatPlain, config.FormatTable},
+		cobra.ShellCompDirectiveNoFileComp,
+	))
+	_ = root.MarkPersistentFlagFilename("config", "yaml", "yml", "json", "toml")
+	_ = root.MarkPersistentFlagDirname("bin-dir")
+}
@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sammcj/skint/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// completionCmd returns a command carrying cfg, as completion functions see it.
+func completionCmd(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.WithValue(context.Background(), ctxKey, &CmdContext{Cfg: cfg}))
+	return cmd
+}
+
+// completionNames strips the descriptions from completions.
+func completionNames(completions []cobra.Completion) []string {
+	var names []string
+	for _, c := range completions {
+		name, _, _ := strings.Cut(c, "\t")
+		names = append(names, name)
+	}
+	return names
+}
+
+func TestProviderCompletion(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Providers = []*config.Provider{
+		{Name: "ollama", Type: config.ProviderTypeLocal, DisplayName: "Ollama"},
+		{Name: "zai", Type: config.ProviderTypeBuiltin, DisplayName: "Z.AI"},
+	}
+	cfg.EnvPresets = map[string]map[string]string{"corp": {"HTTP_PROXY": "x"}, "beta": {"X": "1"}}
+	cmd := completionCmd(cfg)
+
+	tests := []struct {
+		name       string
+		fn         cobra.CompletionFunc
+		args       []string
+		toComplete string
+		want       []string
+		directive  cobra.ShellCompDirective
+	}{
+		{"configured providers", completeProvider, nil, "", []string{"ollama", "zai"}, cobra.ShellCompDirectiveNoFileComp},
+		{"prefix", completeProvider, nil, "o", []string{"ollama"}, cobra.ShellCompDirectiveNoFileComp},
+		{"only the first argument", completeProvider, []string{"zai"}, "", nil, cobra.ShellCompDirectiveNoFileComp},
+		{"use provider", completeUse, nil, "z", []string{"zai"}, cobra.ShellCompDirectiveNoFileComp},
+		{"use preset", completeUse, []string{"zai", "--preset"}, "", []string{"beta", "corp"}, cobra.ShellCompDirectiveNoFileComp},
+		{"use claude args", completeUse, []string{"zai"}, "", nil, cobra.ShellCompDirectiveDefault},
+		{"new provider skips configured", completeNewProvider, nil, "z", nil, cobra.ShellCompDirectiveNoFileComp},
+		{"new custom provider", completeNewProvider, nil, "cus", []string{"custom"}, cobra.ShellCompDirectiveNoFileComp},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, directive := tc.fn(cmd, tc.args, tc.toComplete)
+			if names := completionNames(got); !slices.Equal(names, tc.want) || directive != tc.directive {
+				t.Errorf("got %v (directive %d), want %v (directive %d)", names, directive, tc.want, tc.directive)
+			}
+		})
+	}
+
+	// Built-in providers follow the configured ones for skint config
+	got, _ := completeAnyProvider(cmd, nil, "")
+	names := completionNames(got)
+	if len(names) < 3 || names[0] != "ollama" || names[1] != "zai" || slices.Contains(names[2:], "zai") {
+		t.Errorf("config completions = %v, want configured providers first, without duplicates", names)
+	}
+
+	// Without a loaded config nothing is offered rather than panicking
+	bare := &cobra.Command{}
+	bare.SetContext(context.Background())
+	if got, _ := completeProvider(bare, nil, ""); len(got) != 0 {
+		t.Errorf("without a config: %v", got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/sammcj/skint/internal/errcode"
 	"github.com/sammcj/skint/internal/providers"
 	"github.com/sammcj/skint/internal/tui"
 	"github.com/sammcj/skint/internal/ui"
@@ -21,11 +22,15 @@ Launch an interactive TUI to configure providers, or specify a provider name to
 		Example: `  skint config           # Interactive TUI
   skint config zai       # Configure Z.AI
   skint config openrouter # Configure OpenRouter`,
-		RunE: runConfig,
+		RunE:              runConfig,
+		ValidArgsFunction: completeAnyProvider,
 	}
 
 	cmd.AddCommand(NewConfigAddCmd())
 	cmd.AddCommand(NewConfigRemoveCmd())
+	cmd.AddCommand(NewConfigExportCmd())
+	cmd.AddCommand(NewConfigImportCmd())
+	cmd.AddCommand(NewConfigImportEnvCmd())
 
 	return cmd
 }
@@ -39,18 +44,24 @@ func runConfig(cmd *cobra.Command, args []string) error {
 	}
 
 	// Always use TUI
-	return tui.RunInteractive(cc.Cfg, cc.SecretsMgr, cc.SaveConfig, cc.LaunchClaude)
+	if cc.NoInput {
+		return errcode.New(errcode.InputRequired, "skint config is interactive. Edit %s or use SKINT_* overrides instead", cc.ConfigMgr.ConfigFile())
+	}
+	return tui.RunInteractive(cc.Cfg, cc.SecretsMgr, cc.SaveConfig, cc.LaunchClaude, cc.Inline)
 }
 
 func configureProviderWithTUI(cc *CmdContext, name string) error {
-	// Check if it's a valid provider
+	// Check if it's a valid provider: a built-in, or a configured custom one
 	registry := providers.NewRegistry()
-	if _, ok := registry.Get(name); !ok && name != "openrouter" && name != "custom" {
+	if _, ok := registry.Get(name); !ok && name != "openrouter" && name != "custom" && cc.Cfg.GetProvider(name) == nil {
 		return fmt.Errorf("unknown provider: %s", name)
 	}
 
-	// Run TUI with pre-selected provider
-	result, err := tui.RunConfigTUI(cc.Cfg, cc.SecretsMgr)
+	// Run TUI on the provider's config form
+	if cc.NoInput {
+		return errcode.New(errcode.InputRequired, "configuring %s is interactive. Edit %s instead", name, cc.ConfigMgr.ConfigFile())
+	}
+	result, err := tui.RunConfigTUI(cc.Cfg, cc.SecretsMgr, name, cc.Inline)
 	if err != nil {
 		return err
 	}
@@ -72,6 +83,8 @@ func NewConfigAddCmd() *cobra.Command {
 		Short: "Add a new provider",
 		Long:  "Add a new provider configuration using the interactive TUI.",
 		Args:  cobra.ExactArgs(1),
+
+		ValidArgsFunction: completeNewProvider,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cc := GetContext(cmd)
 			return configureProviderWithTUI(cc, args[0])
@@ -86,11 +99,16 @@ func NewConfigRemoveCmd() *cobra.Command {
 		Aliases: []string{"rm"},
 		Short:   "Remove a provider configuration",
 		Args:    cobra.ExactArgs(1),
+
+		ValidArgsFunction: completeProvider,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cc := GetContext(cmd)
 			name := args[0]
 
 			if !cc.YesMode {
+				if cc.NoInput {
+					return errcode.New(errcode.InputRequired, "removing %s needs confirmation. Use --yes", name)
+				}
 				if !ui.Confirm(fmt.Sprintf("Remove provider '%s'?", name), false) {
 					ui.Info("Cancelled")
 					return nil
@@ -0,0 +1,431 @@
+package commands
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/errcode"
+	"github.com/sammcj/skint/internal/secrets"
+	"github.com/sammcj/skint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// Backup archive layout. The tar is encrypted with secrets.SealWithPassphrase,
+// so a backup is a .tar.age file.
+const (
+	backupFormatVersion = 1
+	backupManifestFile  = "manifest.json"
+	backupConfigFile    = "config.yaml"
+	backupKeysFile      = "keys.json"
+
+	// backupPassphraseEnv supplies the passphrase non-interactively
+	backupPassphraseEnv = "SKINT_BACKUP_PASSPHRASE"
+)
+
+// backupManifest describes a backup archive
+type backupManifest struct {
+	Version      int       `json:"version"`
+	Created      time.Time `json:"created"`
+	IncludesKeys bool      `json:"includes_keys"`
+}
+
+// backup is the decoded content of a backup archive
+type backup struct {
+	Manifest backupManifest
+	Config   []byte
+	Keys     map[string]string // provider name -> API key
+}
+
+// NewConfigExportCmd creates the config export command
+func NewConfigExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export --output <file>",
+		Short: "Export the config to an encrypted backup",
+		Long: `Export the user config to a passphrase-encrypted backup file.
+
+With --include-keys the provider API keys are resolved from the keyring (or
+encrypted file store) and included, so 'skint config import' on another
+machine restores a fully working setup. The backup is a tar archive encrypted
+with age using the passphrase, so 'age -d backup.tar.age | tar x' also opens it.
+
+The passphrase is read from --passphrase-file, then $` + backupPassphraseEnv + `,
+otherwise it is prompted for.`,
+		Example: `  skint config export --output backup.tar.age
+  skint config export --include-keys --output backup.tar.age
+  skint config export -o backup.tar.age --passphrase-file ~/.backup-pass`,
+		Args: cobra.NoArgs,
+		RunE: runConfigExport,
+	}
+
+	cmd.Flags().StringP("output", "o", "", "file to write the backup to (e.g. backup.tar.age)")
+	_ = cmd.MarkFlagRequired("output")
+	cmd.Flags().Bool("include-keys", false, "include provider API keys in the backup")
+	cmd.Flags().String("passphrase-file", "", "read the passphrase from a file")
+	cmd.Flags().Bool("force", false, "overwrite an existing file")
+
+	return cmd
+}
+
+// NewConfigImportCmd creates the config import command
+func NewConfigImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Restore the config from an encrypted backup",
+		Long: `Restore the user config from a backup made with 'skint config export'.
+
+The current config is replaced (after confirmation). API keys in the backup
+are stored in this machine's keyring, or the encrypted file store when no
+keyring is available.`,
+		Example: `  skint config import backup.tar.age
+  skint config import backup.tar.age --yes --passphrase-file ~/.backup-pass`,
+		Args: cobra.ExactArgs(1),
+		RunE: runConfigImport,
+	}
+
+	cmd.Flags().String("passphrase-file", "", "read the passphrase from a file")
+
+	return cmd
+}
+
+func runConfigExport(cmd *cobra.Command, args []string) error {
+	cc := GetContext(cmd)
+	path, _ := cmd.Flags().GetString("output")
+	includeKeys, _ := cmd.Flags().GetBool("include-keys")
+	passFile, _ := cmd.Flags().GetString("passphrase-file")
+	force, _ := cmd.Flags().GetBool("force")
+
+	if _, err := os.Lstat(path); err == nil && !force {
+		return fmt.Errorf("%s already exists. Use --force to overwrite", path)
+	}
+
+	cfgData, err := cc.ConfigMgr.Export()
+	if err != nil {
+		return err
+	}
+
+	var keys map[string]string
+	var missing []string
+	if includeKeys {
+		keys, missing = cc.exportKeys()
+	}
+
+	archive, err := writeBackupArchive(cfgData, keys, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	passphrase, err := backupPassphrase(cc, passFile, true)
+	if err != nil {
+		return err
+	}
+	sealed, err := secrets.SealWithPassphrase(archive, passphrase)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt backup: %w", err)
+	}
+
+	if err := writeFileMode(path, sealed, 0600); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+
+	// JSON output
+	if cc.Cfg.OutputFormat == config.FormatJSON {
+		return cc.Output(map[string]any{
+			"file":         path,
+			"include_keys": includeKeys,
+			"keys":         len(keys),
+			"missing_keys": missing,
+		})
+	}
+
+	// Plain output
+	if cc.Cfg.OutputFormat == config.FormatPlain {
+		fmt.Println(path)
+		return nil
+	}
+
+	// Human-readable output
+	ui.Success("Exported config to %s", path)
+	if includeKeys {
+		ui.Success("Included %d API key(s)", len(keys))
+		for _, name := range missing {
+			ui.Warning("No API key found for %s", name)
+		}
+		ui.Dim("The backup contains API keys - keep it and the passphrase safe\n")
+	}
+	return nil
+}
+
+func runConfigImport(cmd *cobra.Command, args []string) error {
+	cc := GetContext(cmd)
+	path := args[0]
+	passFile, _ := cmd.Flags().GetString("passphrase-file")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	passphrase, err := backupPassphrase(cc, passFile, false)
+	if err != nil {
+		return err
+	}
+	archive, err := secrets.OpenWithPassphrase(data, passphrase)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt backup: %w", err)
+	}
+	b, err := readBackupArchive(archive)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.ParseConfig(b.Config)
+	if err != nil {
+		return fmt.Errorf("backup config: %w", err)
+	}
+
+	if cc.CfgFileExists() && !cc.YesMode {
+		if cc.NoInput {
+			return errcode.New(errcode.InputRequired, "a config already exists at %s. Use --yes to replace it", cc.ConfigMgr.ConfigFile())
+		}
+		if !ui.Confirm("Replace the current config with the backup?", false) {
+			ui.Info("Cancelled")
+			return nil
+		}
+	}
+
+	// Backups made before MCP values were kept in them have them redacted;
+	// keep the ones already here and leave out the rest
+	cfg.RestoreRedacted(cc.Cfg)
+	warnDroppedRedacted(cfg)
+
+	// Store keys under this machine's backend and point providers at them
+	names := make([]string, 0, len(b.Keys))
+	for name := range b.Keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ref, err := cc.SecretsMgr.StoreWithReference(name, b.Keys[name])
+		if err != nil {
+			return fmt.Errorf("failed to store key for %s: %w", name, err)
+		}
+		if p := cfg.GetProvider(name); p != nil {
+			p.APIKeyRef = ref
+			p.SetResolvedAPIKey(b.Keys[name])
+		}
+	}
+
+	// Providers whose key was not in the backup and is not already here
+	var missing []string
+	for _, p := range cfg.Providers {
+		if !p.NeedsAPIKey() || p.APIKeyRef == "" || p.GetAPIKey() != "" {
+			continue
+		}
+		if _, err := cc.SecretsMgr.RetrieveByReference(p.APIKeyRef); err != nil {
+			missing = append(missing, p.Name)
+		}
+	}
+
+	// Output stays in the format asked for on this run, not the backup's
+	outputFormat := cc.Cfg.OutputFormat
+	cc.ConfigMgr.Replace(cfg)
+	if err := cc.SaveConfig(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	cc.Cfg = cfg
+	cc.ConfigMgr.Override("OutputFormat", outputFormat)
+
+	// JSON output
+	if cc.Cfg.OutputFormat == config.FormatJSON {
+		return cc.Output(map[string]any{
+			"config_file":  cc.ConfigMgr.ConfigFile(),
+			"providers":    len(cfg.Providers),
+			"keys":         len(b.Keys),
+			"missing_keys": missing,
+		})
+	}
+
+	// Plain output
+	if cc.Cfg.OutputFormat == config.FormatPlain {
+		fmt.Println(cc.ConfigMgr.ConfigFile())
+		return nil
+	}
+
+	// Human-readable output
+	ui.Success("Restored config with %d provider(s) from %s", len(cfg.Providers), path)
+	if len(b.Keys) > 0 {
+		ui.Success("Stored %d API key(s)", len(b.Keys))
+	}
+	if len(missing) > 0 {
+		steps := make([]string, 0, len(missing))
+		for _, name := range missing {
+			steps = append(steps, "Set the API key for "+name+": "+ui.Green("skint config "+name))
+		}
+		ui.NextSteps(steps)
+	}
+	return nil
+}
+
+// exportKeys resolves the API keys of configured providers, returning them
+// by provider name along with the names of providers whose key could not be
+// found.
+func (cc *CmdContext) exportKeys() (map[string]string, []string) {
+	keys := make(map[string]string)
+	var missing []string
+	for _, p := range cc.Cfg.Providers {
+		if !p.NeedsAPIKey() || p.APIKeyRef == "" {
+			continue
+		}
+		key := p.GetAPIKey()
+		if key == "" {
+			key, _ = cc.SecretsMgr.RetrieveByReference(p.APIKeyRef)
+		}
+		if key == "" {
+			missing = append(missing, p.Name)
+			continue
+		}
+		keys[p.Name] = key
+	}
+	return keys, missing
+}
+
+// backupPassphrase returns the backup passphrase from file, the environment,
+// or a hidden prompt (entered twice when confirm is set).
+func backupPassphrase(cc *CmdContext, file string, confirm bool) ([]byte, error) {
+	var pass string
+	switch {
+	case file != "":
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read passphrase file: %w", err)
+		}
+		pass = strings.TrimRight(string(data), "\r\n")
+	case os.Getenv(backupPassphraseEnv) != "":
+		pass = os.Getenv(backupPassphraseEnv)
+	case cc.NoInput:
+		return nil, errcode.New(errcode.InputRequired, "a passphrase is required. Use --passphrase-file or set %s", backupPassphraseEnv)
+	default:
+		var err error
+		if pass, err = ui.PromptSecret("Backup passphrase"); err != nil {
+			return nil, err
+		}
+		if confirm && pass != "" {
+			again, err := ui.PromptSecret("Repeat passphrase")
+			if err != nil {
+				return nil, err
+			}
+			if again != pass {
+				return nil, fmt.Errorf("passphrases do not match")
+			}
+		}
+	}
+
+	if pass == "" {
+		return nil, fmt.Errorf("passphrase must not be empty")
+	}
+	return []byte(pass), nil
+}
+
+// writeBackupArchive builds the tar archive holding the manifest, config and
+// (when keys is non-empty) the API keys.
+func writeBackupArchive(cfgData []byte, keys map[string]string, created time.Time) ([]byte, error) {
+	manifest, err := json.MarshalIndent(backupManifest{
+		Version:      backupFormatVersion,
+		Created:      created,
+		IncludesKeys: len(keys) > 0,
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{backupManifestFile, manifest},
+		{backupConfigFile, cfgData},
+	}
+	if len(keys) > 0 {
+		keyData, err := json.MarshalIndent(keys, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, struct {
+			name string
+			data []byte
+		}{backupKeysFile, keyData})
+	}
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, f := range files {
+		hdr := &tar.Header{
+			Name:    f.name,
+			Mode:    0600,
+			Size:    int64(len(f.data)),
+			ModTime: created,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return nil, fmt.Errorf("failed to write backup archive: %w", err)
+		}
+		if _, err := tw.Write(f.data); err != nil {
+			return nil, fmt.Errorf("failed to write backup archive: %w", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write backup archive: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// readBackupArchive decodes an archive built by writeBackupArchive.
+func readBackupArchive(data []byte) (*backup, error) {
+	b := &backup{}
+	var haveManifest, haveConfig bool
+
+	tr := tar.NewReader(bytes.NewReader(data))
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read backup archive: %w", err)
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read backup archive: %w", err)
+		}
+
+		switch hdr.Name {
+		case backupManifestFile:
+			if err := json.Unmarshal(content, &b.Manifest); err != nil {
+				return nil, fmt.Errorf("invalid backup manifest: %w", err)
+			}
+			haveManifest = true
+		case backupConfigFile:
+			b.Config = content
+			haveConfig = true
+		case backupKeysFile:
+			if err := json.Unmarshal(content, &b.Keys); err != nil {
+				return nil, fmt.Errorf("invalid backup keys: %w", err)
+			}
+		}
+	}
+
+	if !haveManifest || !haveConfig {
+		return nil, fmt.Errorf("not a skint backup: missing %s or %s", backupManifestFile, backupConfigFile)
+	}
+	if b.Manifest.Version > backupFormatVersion {
+		return nil, fmt.Errorf("backup format version %d is newer than this skint supports (%d). Upgrade skint", b.Manifest.Version, backupFormatVersion)
+	}
+	return b, nil
+}
@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/sammcj/skint/internal/config"
+)
+
+func TestBackupArchiveRoundTrip(t *testing.T) {
+	created := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	keys := map[string]string{"zai": "sk-zai", "openrouter": "sk-or"}
+
+	data, err := writeBackupArchive([]byte("version: \"2.0\"\n"), keys, created)
+	if err != nil {
+		t.Fatalf("writeBackupArchive: %v", err)
+	}
+	b, err := readBackupArchive(data)
+	if err != nil {
+		t.Fatalf("readBackupArchive: %v", err)
+	}
+
+	if string(b.Config) != "version: \"2.0\"\n" {
+		t.Errorf("Config = %q", b.Config)
+	}
+	if !b.Manifest.IncludesKeys || b.Manifest.Version != backupFormatVersion || !b.Manifest.Created.Equal(created) {
+		t.Errorf("Manifest = %+v", b.Manifest)
+	}
+	if len(b.Keys) != 2 || b.Keys["zai"] != "sk-zai" || b.Keys["openrouter"] != "sk-or" {
+		t.Errorf("Keys = %v", b.Keys)
+	}
+
+	// Without keys there is no keys file
+	data, err = writeBackupArchive([]byte("{}"), nil, created)
+	if err != nil {
+		t.Fatalf("writeBackupArchive: %v", err)
+	}
+	if b, err = readBackupArchive(data); err != nil {
+		t.Fatalf("readBackupArchive: %v", err)
+	}
+	if b.Manifest.IncludesKeys || b.Keys != nil {
+		t.Errorf("unexpected keys: manifest=%+v keys=%v", b.Manifest, b.Keys)
+	}
+
+	if _, err := readBackupArchive([]byte("not a tar")); err == nil {
+		t.Error("expected an error for a non-archive")
+	}
+}
+
+func TestBackupPassphrase(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "pass")
+	if err := os.WriteFile(file, []byte("from file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(backupPassphraseEnv, "from env")
+	cc := &CmdContext{NoInput: true}
+
+	got, err := backupPassphrase(cc, file, true)
+	if err != nil || string(got) != "from file" {
+		t.Errorf("file: got %q, %v", got, err)
+	}
+	got, err = backupPassphrase(cc, "", true)
+	if err != nil || string(got) != "from env" {
+		t.Errorf("env: got %q, %v", got, err)
+	}
+
+	t.Setenv(backupPassphraseEnv, "")
+	if _, err := backupPassphrase(cc, "", true); err == nil {
+		t.Error("expected an error with --no-input and no passphrase source")
+	}
+}
+
+func TestConfigExportReplacesFileMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows has no Unix file modes")
+	}
+	mgr, err := config.NewManagerWithPath(filepath.Join(t.TempDir(), "config.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.Load(); err != nil {
+		t.Fatal(err)
+	}
+	mgr.Get().OutputFormat = config.FormatPlain
+	file := filepath.Join(t.TempDir(), "backup.tar.age")
+	if err := os.WriteFile(file, []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(backupPassphraseEnv, "correct horse")
+
+	cmd := NewConfigExportCmd()
+	cmd.SetContext(context.WithValue(context.Background(), ctxKey, &CmdContext{Cfg: mgr.Get(), ConfigMgr: mgr, NoInput: true}))
+	cmd.SetArgs([]string{"--output", file, "--force"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("config export: %v", err)
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("mode = %o, want 600 for a backup", perm)
+	}
+}
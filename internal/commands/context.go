@@ -3,10 +3,14 @@ package commands
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
+	"time"
 
 	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/errcode"
 	"github.com/sammcj/skint/internal/launcher"
 	"github.com/sammcj/skint/internal/providers"
 	"github.com/sammcj/skint/internal/secrets"
@@ -29,12 +33,31 @@ type CmdContext struct {
 	NoInput      bool
 	NoColor      bool
 	NoBanner     bool
+	Inline       bool
 	OutputFormat string
+	Timeout      string
 	BinDir       string
+	LogFile      string
+
+	// Project is the nearest .skint.yaml above the working directory, or nil
+	Project *config.ProjectConfig
+
+	// DirRule is the config's directory rule matching the working directory, or nil
+	DirRule *config.DirectoryRule
+
+	// ConfigErr is why the config file couldn't be loaded, for commands
+	// annotated with annotationTolerateConfigErr; Cfg is then the defaults
+	ConfigErr error
 
 	// cfgFile is the user-supplied config path (empty = default)
 	cfgFile string
 
+	// tolerateConfigErr is set when the running command tolerates ConfigErr
+	tolerateConfigErr bool
+
+	// skipSecrets is set when the running command never needs API keys
+	skipSecrets bool
+
 	// ClaudeExtraArgs holds additional arguments to pass through to claude (e.g. --resume, --continue)
 	ClaudeExtraArgs []string
 }
@@ -88,7 +111,7 @@ func (cc *CmdContext) ResolveProvider(name string) (*config.Provider, error) {
 		registry := providers.NewRegistry()
 		def, ok := registry.Get(name)
 		if !ok {
-			return nil, fmt.Errorf("unknown provider: %s. Run 'skint list' to see available providers", name)
+			return nil, errcode.New(errcode.ProviderNotFound, "unknown provider: %s. Run 'skint list' to see available providers", name)
 		}
 
 		p = &config.Provider{
@@ -97,7 +120,7 @@ func (cc *CmdContext) ResolveProvider(name string) (*config.Provider, error) {
 			DisplayName:   def.DisplayName,
 			Description:   def.Description,
 			BaseURL:       def.BaseURL,
-			DefaultModel:  def.DefaultModel,
+			Model:         def.DefaultModel,
 			ModelMappings: def.ModelMappings,
 			AuthToken:     def.AuthToken,
 			KeyEnvVar:     def.KeyEnvVar,
@@ -106,14 +129,10 @@ func (cc *CmdContext) ResolveProvider(name string) (*config.Provider, error) {
 
 		// For non-local providers, try to load a stored key
 		if def.Type != config.ProviderTypeLocal && def.KeyVar != "" {
-			ref := secrets.StorageTypeKeyring + ":" + name
-			if !cc.SecretsMgr.IsKeyringAvailable() {
-				ref = secrets.StorageTypeFile + ":" + name
-			}
-			p.APIKeyRef = ref
+			p.APIKeyRef = cc.SecretsMgr.Reference(name)
 			key, err := cc.SecretsMgr.Retrieve(name)
 			if err != nil {
-				return nil, fmt.Errorf("provider %s not configured. Run 'skint config %s' to set it up", name, name)
+				return nil, errcode.New(errcode.KeyMissing, "provider %s not configured. Run 'skint config %s' to set it up", name, name)
 			}
 			p.SetResolvedAPIKey(key)
 		}
@@ -123,14 +142,59 @@ func (cc *CmdContext) ResolveProvider(name string) (*config.Provider, error) {
 	if p.NeedsAPIKey() && p.GetAPIKey() == "" && p.APIKeyRef != "" {
 		key, err := cc.SecretsMgr.RetrieveByReference(p.APIKeyRef)
 		if err != nil {
-			return nil, fmt.Errorf("failed to load API key for %s: %w", name, err)
+			return nil, errcode.New(errcode.KeyMissing, "failed to load API key for %s: %w", name, err)
 		}
 		p.SetResolvedAPIKey(key)
 	}
 
+	p = cc.withProject(p)
+	slog.Debug("resolved provider", "provider", p.Name, "type", p.Type, "base_url", p.BaseURL, "model", p.EffectiveModel(), "key", p.KeyBackend())
 	return p, nil
 }
 
+// DefaultProviderName returns the provider to use when none is named: the
+// project config's provider if there is one, then the matching directory
+// rule's, otherwise the configured default.
+func (cc *CmdContext) DefaultProviderName() string {
+	if cc.Project != nil && cc.Project.Provider != "" {
+		return cc.Project.Provider
+	}
+	if cc.DirRule != nil {
+		return cc.DirRule.Provider
+	}
+	return cc.Cfg.DefaultProvider
+}
+
+// withProject returns p with the directory rule's env presets and the project
+// config's model and env presets applied when they use p. A copy is returned
+// so these settings never leak into the saved user config.
+func (cc *CmdContext) withProject(p *config.Provider) *config.Provider {
+	rule := cc.DirRule != nil && cc.DirRule.Provider == p.Name
+	project := cc.Project != nil && cc.Project.Provider == p.Name
+	if !rule && !project {
+		return p
+	}
+	cp := *p
+	cp.EnvPresets = append([]string{}, p.EnvPresets...)
+	if rule {
+		cp.EnvPresets = append(cp.EnvPresets, cc.DirRule.EnvPresets...)
+	}
+	if project {
+		if cc.Project.Model != "" {
+			cp.Model = cc.Project.Model
+		}
+		cp.EnvPresets = append(cp.EnvPresets, cc.Project.EnvPresets...)
+	}
+	return &cp
+}
+
+// PresetEnv returns the env vars from the presets attached to p followed by
+// any extra presets named at launch (e.g. via --preset).
+func (cc *CmdContext) PresetEnv(p *config.Provider, extra ...string) (map[string]string, error) {
+	names := append(append([]string{}, p.EnvPresets...), extra...)
+	return cc.Cfg.PresetEnv(names...)
+}
+
 // LoadProviderKeys loads API keys for all configured providers.
 func (cc *CmdContext) LoadProviderKeys() {
 	for _, p := range cc.Cfg.Providers {
@@ -140,6 +204,7 @@ func (cc *CmdContext) LoadProviderKeys() {
 
 		key, err := cc.SecretsMgr.RetrieveByReference(p.APIKeyRef)
 		if err != nil {
+			slog.Warn("failed to load API key", "provider", p.Name, "ref", p.APIKeyRef, "err", err)
 			if cc.Verbose {
 				ui.Warning("Failed to load key for %s: %v", p.Name, err)
 			}
@@ -221,41 +286,170 @@ func (cc *CmdContext) RunMigration() error {
 	return nil
 }
 
-// LaunchClaude launches Claude Code with the specified provider's env vars.
-// If providerName is empty, launches claude without any provider overrides (native).
-// Uses cfg.ClaudeArgs as default arguments to the claude command.
+// LaunchClaude launches Claude Code (or the provider's target) with the
+// specified provider's env vars. If providerName is empty, launches claude
+// without any provider overrides (native). Uses cfg.ClaudeArgs and the
+// provider's claude_args as default arguments to the claude command. With the
+// watchdog on, a failing provider is replaced by the next fallback provider.
 func (cc *CmdContext) LaunchClaude(providerName string) error {
-	if err := launcher.CheckClaude(); err != nil {
-		return err
+	if providerName == "" {
+		providerName = "native"
 	}
+	return cc.withFallback(providerName, nil, cc.launchProvider(providerName, nil, true))
+}
 
-	args := append([]string{}, cc.Cfg.ClaudeArgs...)
-	args = append(args, cc.ClaudeExtraArgs...)
+// launchProvider launches Claude with provider name ("native" for no
+// provider) and its configured presets and arguments followed by extra,
+// asking first if confirm is set and confirm_before_launch is on.
+func (cc *CmdContext) launchProvider(name string, extra []string, confirm bool) error {
+	l, err := launcher.New(cc.Cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create launcher: %w", err)
+	}
 
-	if providerName == "" {
+	if name == "native" {
 		// Native: launch claude without provider env vars
-		l, err := launcher.New(cc.Cfg)
-		if err != nil {
-			return fmt.Errorf("failed to create launcher: %w", err)
+		if err := launcher.CheckClaude(); err != nil {
+			return err
 		}
-		return l.LaunchNative(args)
+		if confirm {
+			if ok, err := cc.confirmLaunch(name); !ok {
+				if err == nil {
+					ui.Info("Cancelled")
+				}
+				return err
+			}
+		}
+		l.SetOnExit(cc.recordUse("native", "", "claude"))
+		return l.LaunchNative(append(append(cc.Cfg.LaunchArgs(nil), cc.ClaudeExtraArgs...), extra...))
 	}
 
 	// Resolve provider and launch
-	p, err := cc.ResolveProvider(providerName)
+	p, err := cc.ResolveProvider(name)
+	if err != nil {
+		return err
+	}
+	args, err := cc.launchArgs(p, true)
 	if err != nil {
 		return err
 	}
+	args = append(args, extra...)
+	if p.TargetName() == config.TargetClaude {
+		if err := launcher.CheckClaude(); err != nil {
+			return err
+		}
+	}
 
 	provider, err := providers.FromConfig(p)
 	if err != nil {
-		return fmt.Errorf("failed to create provider %s: %w", providerName, err)
+		return fmt.Errorf("failed to create provider %s: %w", name, err)
 	}
 
-	l, err := launcher.New(cc.Cfg)
+	presetEnv, err := cc.PresetEnv(p)
 	if err != nil {
-		return fmt.Errorf("failed to create launcher: %w", err)
+		return err
 	}
+	l.SetExtraEnv(presetEnv)
 
+	if confirm {
+		if ok, err := cc.confirmLaunch(name); !ok {
+			if err == nil {
+				ui.Info("Cancelled")
+			}
+			return err
+		}
+	}
+	l.SetOnExit(cc.recordUse(name, p.EffectiveModel(), provider.Target().Command))
 	return l.Launch(provider, args)
 }
+
+// withFallback handles err from launching Claude with provider name: while
+// the watchdog blames the provider, it relaunches with the next of
+// fallback_providers and extra after its usual arguments, asking first in
+// "ask" mode (--yes skips the question; with --no-input the failure is
+// returned). Other errors are returned as they are.
+func (cc *CmdContext) withFallback(name string, extra []string, err error) error {
+	tried := map[string]bool{}
+	for {
+		var failed *launcher.ProviderFailedError
+		if !errors.As(err, &failed) {
+			return err
+		}
+		tried[name] = true
+		next := cc.Cfg.NextFallback(name, tried)
+		if next == "" {
+			ui.Warning("%s failed (%s) and there is no fallback provider left", name, failed.Reason)
+			return err
+		}
+		ui.Warning("%s failed: %s", name, failed.Reason)
+		if cc.Cfg.Watchdog == config.WatchdogAsk && !cc.YesMode {
+			if cc.NoInput || !ui.Confirm(fmt.Sprintf("Relaunch with %s?", next), true) {
+				return err
+			}
+		}
+		ui.Info("Relaunching with %s", next)
+		name = next
+		err = cc.launchProvider(name, extra, false)
+	}
+}
+
+// launchArgs returns the arguments for launching p: its configured ones,
+// those loading its MCP servers, then the claude ones from -c and --resume
+// (for a claude target only). With write set the MCP config file is written
+// too; a dry run only names it.
+func (cc *CmdContext) launchArgs(p *config.Provider, write bool) ([]string, error) {
+	mcp, err := p.MCPArgs()
+	if write {
+		mcp, err = config.WriteMCPConfig(p)
+	}
+	if err != nil {
+		return nil, err
+	}
+	args := append(cc.Cfg.LaunchArgs(p), mcp...)
+	if p.TargetName() == config.TargetClaude {
+		args = append(args, cc.ClaudeExtraArgs...)
+	}
+	return args, nil
+}
+
+// confirmLaunch asks before launching Claude when confirm_before_launch is
+// set. --yes skips the question; with --no-input it is an error.
+func (cc *CmdContext) confirmLaunch(name string) (bool, error) {
+	if !cc.Cfg.ConfirmBeforeLaunch || cc.YesMode {
+		return true, nil
+	}
+	if cc.NoInput {
+		return false, errcode.New(errcode.InputRequired, "confirm_before_launch is set. Use --yes to launch without asking")
+	}
+	return ui.Confirm(fmt.Sprintf("Launch Claude with %s?", name), true), nil
+}
+
+// recordUse notes that a provider is being launched, for 'skint list', the
+// TUI and 'skint history', and returns a function to record how the launch
+// ended. It must run before launching, as Launch replaces the process on Unix.
+func (cc *CmdContext) recordUse(name, model, command string) func(exitCode int, d time.Duration) {
+	if err := config.RecordUsage(name); err != nil {
+		slog.Warn("failed to record provider use", "provider", name, "err", err)
+		if cc.Verbose {
+			ui.Warning("Failed to record use of %s: %v", name, err)
+		}
+	}
+
+	dir, _ := os.Getwd()
+	id, err := config.RecordLaunch(config.HistoryEntry{Provider: name, Model: model, Command: command, Dir: dir})
+	if err != nil {
+		slog.Warn("failed to record launch history", "err", err)
+		if cc.Verbose {
+			ui.Warning("Failed to record launch history: %v", err)
+		}
+		return func(int, time.Duration) {}
+	}
+	return func(exitCode int, d time.Duration) {
+		if err := config.RecordExit(id, exitCode, d); err != nil {
+			slog.Warn("failed to record launch history", "err", err)
+			if cc.Verbose {
+				ui.Warning("Failed to record launch history: %v", err)
+			}
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/sammcj/skint/internal/config"
@@ -66,3 +67,75 @@ func TestClaudeExtraArgsMergedWithClaudeArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestLaunchArgsClaudeOnlyForClaudeTarget(t *testing.T) {
+	cc := &CmdContext{
+		Cfg:             &config.Config{ClaudeArgs: []string{"--verbose"}},
+		ClaudeExtraArgs: []string{"--continue"},
+	}
+
+	codex := &config.Provider{Name: "gpt", Target: config.TargetCodex, ClaudeArgs: []string{"--full-auto"}}
+	args, err := cc.launchArgs(codex, false)
+	if err != nil {
+		t.Fatalf("launchArgs: %v", err)
+	}
+	if want := []string{"--full-auto"}; !slices.Equal(args, want) {
+		t.Errorf("codex args = %v, want %v", args, want)
+	}
+
+	claude := &config.Provider{Name: "zai", ClaudeArgs: []string{"--debug"}}
+	args, err = cc.launchArgs(claude, false)
+	if err != nil {
+		t.Fatalf("launchArgs: %v", err)
+	}
+	if want := []string{"--verbose", "--debug", "--continue"}; !slices.Equal(args, want) {
+		t.Errorf("claude args = %v, want %v", args, want)
+	}
+}
+
+func TestDefaultProviderNamePrecedence(t *testing.T) {
+	rule := &config.DirectoryRule{Path: "/work/**", Provider: "rule", EnvPresets: []string{"proxy"}}
+	project := &config.ProjectConfig{Provider: "project", Model: "m", EnvPresets: []string{"extra"}}
+
+	tests := []struct {
+		name    string
+		rule    *config.DirectoryRule
+		project *config.ProjectConfig
+		want    string
+	}{
+		{name: "config default", want: "default"},
+		{name: "directory rule", rule: rule, want: "rule"},
+		{name: "project beats rule", rule: rule, project: project, want: "project"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cc := &CmdContext{
+				Cfg:     &config.Config{DefaultProvider: "default"},
+				DirRule: tc.rule,
+				Project: tc.project,
+			}
+			if got := cc.DefaultProviderName(); got != tc.want {
+				t.Errorf("DefaultProviderName: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWithProjectAppliesDirectoryRulePresets(t *testing.T) {
+	cc := &CmdContext{
+		Cfg:     &config.Config{},
+		DirRule: &config.DirectoryRule{Path: "/work/**", Provider: "zai", EnvPresets: []string{"proxy"}},
+	}
+	p := &config.Provider{Name: "zai", EnvPresets: []string{"base"}}
+
+	got := cc.withProject(p)
+	if want := []string{"base", "proxy"}; !slices.Equal(got.EnvPresets, want) {
+		t.Errorf("EnvPresets: got %v, want %v", got.EnvPresets, want)
+	}
+	if len(p.EnvPresets) != 1 {
+		t.Errorf("original provider was modified: %v", p.EnvPresets)
+	}
+	if other := cc.withProject(&config.Provider{Name: "ollama"}); len(other.EnvPresets) != 0 {
+		t.Errorf("rule presets applied to another provider: %v", other.EnvPresets)
+	}
+}
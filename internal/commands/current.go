@@ -0,0 +1,115 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/template"
+
+	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/providers"
+	"github.com/spf13/cobra"
+)
+
+// currentProvider is what skint current reports, and the data for --format.
+type currentProvider struct {
+	Name        string `json:"provider"`
+	DisplayName string `json:"display_name"`
+	Model       string `json:"model,omitempty"`
+	// Source is where the provider was chosen: "env", "project", "rule" or "default"
+	Source string `json:"source"`
+}
+
+// NewCurrentCmd creates the current command
+func NewCurrentCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "current",
+		Short: "Print the active provider, for shell prompts",
+		Long: `Print the provider skint would use here, on one line, for a shell
+prompt segment (starship, powerlevel10k and the like).
+
+It only reads the config: API keys are never loaded and the keyring is not
+touched, so it is fast enough to run on every prompt.
+
+--format takes a Go template with the fields .Name, .DisplayName, .Model and
+.Source (env, project, rule or default).`,
+		Example: `  skint current                          # zai
+  skint current --model                  # zai/glm-4.7
+  skint current --format '{{.DisplayName}}{{with .Model}} ({{.}}){{end}}'
+
+  # starship.toml
+  [custom.skint]
+  command = "skint current"
+  when = true`,
+		Args:        cobra.NoArgs,
+		Annotations: map[string]string{annotationSkipSecrets: "true"},
+		RunE:        runCurrent,
+	}
+	cmd.Flags().Bool("model", false, "include the model")
+	cmd.Flags().String("format", "", "Go template for the output")
+	return cmd
+}
+
+func runCurrent(cmd *cobra.Command, args []string) error {
+	cc := GetContext(cmd)
+	cur := cc.currentProvider()
+
+	if cc.Cfg.OutputFormat == config.FormatJSON {
+		return cc.Output(cur)
+	}
+	format, _ := cmd.Flags().GetString("format")
+	withModel, _ := cmd.Flags().GetBool("model")
+	return writeCurrent(cmd.OutOrStdout(), cur, format, withModel)
+}
+
+// currentProvider works out the provider in use for the working directory
+// from the config alone, without resolving API keys.
+func (cc *CmdContext) currentProvider() currentProvider {
+	cur := currentProvider{Name: cc.DefaultProviderName(), Source: "default"}
+	switch {
+	case cc.Project != nil && cc.Project.Provider != "":
+		cur.Source = "project"
+	case cc.DirRule != nil:
+		cur.Source = "rule"
+	case os.Getenv("SKINT_DEFAULT_PROVIDER") != "" && cur.Name == cc.Cfg.DefaultProvider:
+		cur.Source = "env"
+	}
+	if cur.Name == "" {
+		cur.Name = "native"
+	}
+
+	if p := cc.Cfg.GetProvider(cur.Name); p != nil {
+		p = cc.withProject(p)
+		cur.DisplayName = p.DisplayName
+		cur.Model = p.EffectiveModel()
+	} else if def, ok := providers.NewRegistry().Get(cur.Name); ok {
+		cur.DisplayName = def.DisplayName
+		cur.Model = def.DefaultModel
+	}
+	if cur.DisplayName == "" {
+		cur.DisplayName = cur.Name
+	}
+	return cur
+}
+
+// writeCurrent writes cur as one line: the provider name, with the model when
+// withModel is set, or as format, a text/template, if given.
+func writeCurrent(w io.Writer, cur currentProvider, format string, withModel bool) error {
+	if format != "" {
+		tmpl, err := template.New("current").Option("missingkey=error").Parse(format)
+		if err != nil {
+			return fmt.Errorf("invalid --format: %w", err)
+		}
+		if err := tmpl.Execute(w, cur); err != nil {
+			return fmt.Errorf("invalid --format: %w", err)
+		}
+		_, err = fmt.Fprintln(w)
+		return err
+	}
+	line := cur.Name
+	if withModel && cur.Model != "" {
+		line += "/" + cur.Model
+	}
+	_, err := fmt.Fprintln(w, line)
+	return err
+}
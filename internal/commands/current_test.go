@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sammcj/skint/internal/config"
+)
+
+func TestCurrentProvider(t *testing.T) {
+	t.Setenv("SKINT_DEFAULT_PROVIDER", "")
+	cfg := config.NewDefaultConfig()
+	cfg.Providers = []*config.Provider{
+		{Name: "zai", Type: config.ProviderTypeBuiltin, DisplayName: "Z.AI", Model: "glm-4.7"},
+		{Name: "ollama", Type: config.ProviderTypeLocal, DisplayName: "Ollama", Model: "qwen3"},
+	}
+	cfg.DefaultProvider = "zai"
+	cc := &CmdContext{Cfg: cfg}
+
+	if got := cc.currentProvider(); got != (currentProvider{Name: "zai", DisplayName: "Z.AI", Model: "glm-4.7", Source: "default"}) {
+		t.Errorf("default: %+v", got)
+	}
+
+	// A project's provider and model win
+	cc.Project = &config.ProjectConfig{Provider: "ollama", Model: "qwen3-coder"}
+	if got := cc.currentProvider(); got != (currentProvider{Name: "ollama", DisplayName: "Ollama", Model: "qwen3-coder", Source: "project"}) {
+		t.Errorf("project: %+v", got)
+	}
+
+	// No default means the Claude subscription
+	cc.Project = nil
+	cfg.DefaultProvider = ""
+	if got := cc.currentProvider(); got.Name != "native" || got.DisplayName != "Claude Subscription" {
+		t.Errorf("no default: %+v", got)
+	}
+}
+
+func TestWriteCurrent(t *testing.T) {
+	cur := currentProvider{Name: "zai", DisplayName: "Z.AI", Model: "glm-4.7", Source: "default"}
+	tests := []struct {
+		name      string
+		format    string
+		withModel bool
+		want      string
+	}{
+		{"name", "", false, "zai\n"},
+		{"with model", "", true, "zai/glm-4.7\n"},
+		{"template", "{{.DisplayName}} ({{.Model}})", false, "Z.AI (glm-4.7)\n"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeCurrent(&buf, cur, tc.format, tc.withModel); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tc.want {
+				t.Errorf("got %q, want %q", buf.String(), tc.want)
+			}
+		})
+	}
+
+	if err := writeCurrent(&bytes.Buffer{}, cur, "{{.Nope}}", false); err == nil {
+		t.Error("an unknown field should be an error")
+	}
+	// The model is left off when there is none
+	var buf bytes.Buffer
+	if err := writeCurrent(&buf, currentProvider{Name: "native"}, "", true); err != nil || buf.String() != "native\n" {
+		t.Errorf("no model: %q, %v", buf.String(), err)
+	}
+}
@@ -0,0 +1,117 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/detect"
+	"github.com/sammcj/skint/internal/providers"
+	"github.com/sammcj/skint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// NewDetectCmd creates the detect command
+func NewDetectCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "detect",
+		Short: "Detect locally running inference servers",
+		Long: `Probe common local ports for running inference servers
+(Ollama on 11434, LM Studio on 1234, llama.cpp on 8000/8080) and offer
+to configure any that are not set up yet.`,
+		Args: cobra.NoArgs,
+		RunE: runDetect,
+	}
+}
+
+func runDetect(cmd *cobra.Command, args []string) error {
+	cc := GetContext(cmd)
+	servers := detect.Detect()
+
+	// JSON output
+	if cc.Cfg.OutputFormat == config.FormatJSON {
+		type serverJSON struct {
+			Provider   string `json:"provider"`
+			Name       string `json:"name"`
+			BaseURL    string `json:"base_url"`
+			Configured bool   `json:"configured"`
+		}
+		result := make([]serverJSON, 0, len(servers))
+		for _, s := range servers {
+			result = append(result, serverJSON{
+				Provider:   s.Provider,
+				Name:       s.Name,
+				BaseURL:    s.BaseURL,
+				Configured: cc.Cfg.GetProvider(s.Provider) != nil,
+			})
+		}
+		return cc.Output(map[string]any{"servers": result})
+	}
+
+	// Plain output
+	if cc.Cfg.OutputFormat == config.FormatPlain {
+		for _, s := range servers {
+			fmt.Printf("%s %s\n", s.Provider, s.BaseURL)
+		}
+		return nil
+	}
+
+	// Human-readable output
+	if len(servers) == 0 {
+		ui.Info("No local inference servers detected")
+		return nil
+	}
+
+	fmt.Println()
+	ui.Log("%s", ui.Bold("Local Servers"))
+	ui.Separator(40)
+	for _, s := range servers {
+		configured := cc.Cfg.GetProvider(s.Provider) != nil
+		ui.ListItem(configured, "%-10s %s", s.Name, ui.DimString(s.BaseURL))
+	}
+	fmt.Println()
+
+	added := 0
+	for _, s := range servers {
+		if cc.Cfg.GetProvider(s.Provider) != nil {
+			continue
+		}
+		if cc.NoInput && !cc.YesMode {
+			continue
+		}
+		if !cc.YesMode && !ui.Confirm(fmt.Sprintf("%s detected but not configured - set it up?", s.Name), true) {
+			continue
+		}
+		if err := addDetectedProvider(cc.Cfg, s); err != nil {
+			ui.Warning("Failed to add %s: %v", s.Name, err)
+			continue
+		}
+		ui.Success("Configured %s at %s", s.Name, s.BaseURL)
+		added++
+	}
+
+	if added > 0 {
+		if err := cc.SaveConfig(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// addDetectedProvider adds a local provider for a detected server, using the
+// registry definition with the detected base URL.
+func addDetectedProvider(cfg *config.Config, s detect.Server) error {
+	def, ok := providers.NewRegistry().Get(s.Provider)
+	if !ok {
+		return fmt.Errorf("unknown provider: %s", s.Provider)
+	}
+	return cfg.AddProvider(&config.Provider{
+		Name:        def.Name,
+		Type:        def.Type,
+		DisplayName: def.DisplayName,
+		Description: def.Description,
+		BaseURL:     s.BaseURL,
+		Model:       def.DefaultModel,
+		AuthToken:   def.AuthToken,
+	})
+}
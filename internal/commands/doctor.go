@@ -0,0 +1,439 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/launcher"
+	"github.com/sammcj/skint/internal/providers"
+	"github.com/sammcj/skint/internal/secrets"
+	"github.com/sammcj/skint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// Doctor check results
+const (
+	checkOK   = "ok"
+	checkWarn = "warn"
+	checkFail = "fail"
+)
+
+// doctorCheck is the result of one skint doctor check, with a suggested fix
+// when it didn't pass.
+type doctorCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Fix     string `json:"fix,omitempty"`
+}
+
+// NewDoctorCmd creates the doctor command
+func NewDoctorCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose problems with the installation",
+		Long: `Check the things Skint depends on and suggest how to fix any problems:
+
+  - the claude binary and its version
+  - the bin directory being on PATH, and the scripts generated there
+  - the config file and the providers' API keys
+  - the OS keyring
+  - ANTHROPIC_* and OPENAI_* variables set in the current shell
+  - whether the default provider is reachable
+  - permissions of the config and secrets files
+
+Exits non-zero when a check fails. Use --output json for machine-readable
+results.`,
+		Args:        cobra.NoArgs,
+		Annotations: map[string]string{annotationTolerateConfigErr: "true"},
+		RunE:        runDoctor,
+	}
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	cc := GetContext(cmd)
+
+	binDir, binErr := config.GetBinDir()
+	checks := []doctorCheck{checkClaude()}
+	if binErr != nil {
+		checks = append(checks, doctorCheck{Name: "PATH", Status: checkFail, Message: binErr.Error()})
+	} else {
+		checks = append(checks, checkPath(binDir, os.Getenv("PATH")), checkScripts(cc, binDir))
+	}
+	checks = append(checks,
+		checkConfig(cc),
+		checkKeyring(cc.SecretsMgr),
+		checkEnvConflicts(os.LookupEnv),
+		checkDefaultProvider(cc),
+		checkPermissions(cc.ConfigMgr),
+	)
+
+	var warned, failed int
+	for _, c := range checks {
+		switch c.Status {
+		case checkWarn:
+			warned++
+		case checkFail:
+			failed++
+		}
+	}
+
+	switch cc.Cfg.OutputFormat {
+	case config.FormatJSON:
+		if err := cc.Output(map[string]any{
+			"checks":   checks,
+			"ok":       len(checks) - warned - failed,
+			"warnings": warned,
+			"failed":   failed,
+		}); err != nil {
+			return err
+		}
+	case config.FormatPlain:
+		for _, c := range checks {
+			fmt.Printf("%s: %s %s\n", c.Name, c.Status, c.Message)
+		}
+	default:
+		printDoctorChecks(checks, warned, failed)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	return nil
+}
+
+// printDoctorChecks prints the checks in human-readable form.
+func printDoctorChecks(checks []doctorCheck, warned, failed int) {
+	fmt.Println()
+	ui.Box("SKINT DOCTOR", 50)
+	fmt.Println()
+
+	// Pad the symbols to one width, as the ASCII ones differ
+	width := max(utf8.RuneCountInString(ui.Sym.OK), utf8.RuneCountInString(ui.Sym.Warning), utf8.RuneCountInString(ui.Sym.Error))
+	pad := func(s string) string { return s + strings.Repeat(" ", width-utf8.RuneCountInString(s)) }
+	indent := strings.Repeat(" ", width+14)
+
+	for _, c := range checks {
+		var symbol string
+		switch c.Status {
+		case checkOK:
+			symbol = ui.Green(pad(ui.Sym.OK))
+		case checkWarn:
+			symbol = ui.Yellow(pad(ui.Sym.Warning))
+		default:
+			symbol = ui.Red(pad(ui.Sym.Error))
+		}
+		ui.Log("  %s %-12s %s", symbol, c.Name, c.Message)
+		for line := range strings.Lines(c.Fix) {
+			ui.Log("  %s%s", indent, ui.DimString(ui.Sym.Arrow+" "+strings.TrimSuffix(line, "\n")))
+		}
+	}
+
+	fmt.Println()
+	ui.Log("  %s, %s, %s",
+		ui.Green(fmt.Sprintf("%d ok", len(checks)-warned-failed)),
+		ui.Yellow(fmt.Sprintf("%d warning(s)", warned)),
+		ui.Red(fmt.Sprintf("%d failed", failed)))
+	fmt.Println()
+}
+
+// checkClaude checks that claude is on PATH and reports its version.
+func checkClaude() doctorCheck {
+	c := doctorCheck{Name: "Claude"}
+	claude, err := launcher.DetectClaude()
+	if err != nil {
+		c.Status = checkFail
+		c.Message = "claude not found on PATH"
+		c.Fix = claude.UpdateHint()
+		return c
+	}
+
+	switch {
+	case claude.Version == "":
+		c.Status = checkWarn
+		c.Message = fmt.Sprintf("%s --version failed", claude.Path)
+		c.Fix = "Reinstall Claude Code: curl -fsSL https://claude.ai/install.sh | bash"
+	case !claude.Supported():
+		c.Status = checkWarn
+		c.Message = fmt.Sprintf("%s (%s) is older than %s, so model tiers may not apply", claude.Version, claude.Path, launcher.MinClaudeVersion)
+		c.Fix = claude.UpdateHint()
+	default:
+		c.Status = checkOK
+		c.Message = fmt.Sprintf("%s (%s)", claude.Version, claude.Path)
+	}
+	return c
+}
+
+// checkPath checks that binDir, where generated scripts go, is on pathEnv.
+func checkPath(binDir, pathEnv string) doctorCheck {
+	c := doctorCheck{Name: "PATH"}
+	if slices.Contains(filepath.SplitList(pathEnv), binDir) {
+		c.Status = checkOK
+		c.Message = binDir + " is on PATH"
+		return c
+	}
+	c.Status = checkWarn
+	c.Message = binDir + " is not on PATH, so generated scripts can't be run by name"
+	if runtime.GOOS == "windows" {
+		c.Fix = "Add " + binDir + " to your user PATH"
+	} else {
+		c.Fix = fmt.Sprintf("Run: skint setup-path (or add to your shell profile: export PATH=\"%s:$PATH\")", binDir)
+	}
+	return c
+}
+
+// checkScripts checks the skint-<provider> scripts in binDir: scripts for
+// providers that are no longer configured, and scripts that no longer match
+// their provider's config.
+func checkScripts(cc *CmdContext, binDir string) doctorCheck {
+	c := doctorCheck{Name: "Scripts"}
+	entries, err := os.ReadDir(binDir)
+	if err != nil && !os.IsNotExist(err) {
+		c.Status = checkWarn
+		c.Message = fmt.Sprintf("failed to read %s: %v", binDir, err)
+		return c
+	}
+
+	var count int
+	var orphaned, stale []string
+	for _, e := range entries {
+		name, ok := scriptProvider(e.Name())
+		if !ok || e.IsDir() {
+			continue
+		}
+		count++
+		p := cc.Cfg.GetProvider(name)
+		if p == nil {
+			orphaned = append(orphaned, filepath.Join(binDir, e.Name()))
+			continue
+		}
+		if scripts, ok := expectedScripts(cc, p); ok {
+			got, err := os.ReadFile(filepath.Join(binDir, e.Name()))
+			if want, ok := scripts[e.Name()]; !ok || (err == nil && string(got) != want) {
+				stale = append(stale, e.Name())
+			}
+		}
+	}
+
+	var fixes []string
+	switch {
+	case count == 0:
+		c.Status = checkOK
+		c.Message = "none generated (optional, see skint generate-scripts)"
+		return c
+	case len(orphaned) > 0 || len(stale) > 0:
+		c.Status = checkWarn
+		var problems []string
+		if len(orphaned) > 0 {
+			problems = append(problems, fmt.Sprintf("%d for providers no longer configured", len(orphaned)))
+			fixes = append(fixes, "Remove them: rm "+strings.Join(orphaned, " "))
+		}
+		if len(stale) > 0 {
+			problems = append(problems, fmt.Sprintf("%d out of date (%s)", len(stale), strings.Join(stale, ", ")))
+			fixes = append(fixes, "Regenerate them: skint generate-scripts")
+		}
+		c.Message = fmt.Sprintf("%d in %s: %s", count, binDir, strings.Join(problems, ", "))
+	default:
+		c.Status = checkOK
+		c.Message = fmt.Sprintf("%d in %s, up to date", count, binDir)
+	}
+	c.Fix = strings.Join(fixes, "\n")
+	return c
+}
+
+// expectedScripts returns the scripts skint generate-scripts would write for
+// p in any shell, by file name, or false if it can't tell (e.g. the API key isn't
+// available).
+func expectedScripts(cc *CmdContext, p *config.Provider) (map[string]string, bool) {
+	if p.NeedsAPIKey() && p.GetAPIKey() == "" {
+		return nil, false
+	}
+	provider, err := providers.FromConfig(p)
+	if err != nil {
+		return nil, false
+	}
+	presetEnv, err := cc.PresetEnv(p)
+	if err != nil {
+		return nil, false
+	}
+	mcp, err := p.MCPArgs()
+	if err != nil {
+		return nil, false
+	}
+	scripts, err := launcher.Scripts(provider, presetEnv, append(cc.Cfg.LaunchArgs(p), mcp...), launcher.ScriptShells...)
+	return scripts, err == nil
+}
+
+// checkConfig checks that the config file loaded, and that every provider
+// that needs an API key has one.
+func checkConfig(cc *CmdContext) doctorCheck {
+	c := doctorCheck{Name: "Config"}
+	file := cc.ConfigMgr.ConfigFile()
+	if cc.ConfigErr != nil {
+		c.Status = checkFail
+		c.Message = cc.ConfigErr.Error()
+		c.Fix = "Fix or move aside " + file + "; skint starts from the defaults without it"
+		return c
+	}
+	if !cc.ConfigMgr.Exists() {
+		c.Status = checkOK
+		c.Message = "no config file yet, using the defaults"
+		return c
+	}
+
+	var missing []string
+	for _, p := range cc.Cfg.Providers {
+		if p.NeedsAPIKey() && p.GetAPIKey() == "" {
+			missing = append(missing, p.Name)
+		}
+	}
+	if len(missing) > 0 {
+		c.Status = checkWarn
+		c.Message = fmt.Sprintf("%s is valid, but no API key could be loaded for %s", file, strings.Join(missing, ", "))
+		c.Fix = fmt.Sprintf("Set the key again: skint config %s", missing[0])
+		return c
+	}
+	c.Status = checkOK
+	c.Message = fmt.Sprintf("%s is valid, %d provider(s)", file, len(cc.Cfg.Providers))
+	return c
+}
+
+// checkKeyring checks whether API keys are kept in the OS keyring.
+func checkKeyring(sm *secrets.Manager) doctorCheck {
+	c := doctorCheck{Name: "Keyring"}
+	if sm != nil && sm.IsKeyringAvailable() {
+		c.Status = checkOK
+		c.Message = "available, API keys are kept in the OS keyring"
+		return c
+	}
+	c.Status = checkWarn
+	c.Message = "unavailable, API keys are kept in the encrypted file store"
+	switch runtime.GOOS {
+	case "linux":
+		c.Fix = "Install and unlock a Secret Service provider (e.g. gnome-keyring or KWallet), then set keys again with skint config"
+	case "darwin":
+		c.Fix = "Check that the login keychain is unlocked (Keychain Access)"
+	default:
+		c.Fix = "Check that the Windows Credential Manager is available"
+	}
+	return c
+}
+
+// checkEnvConflicts reports provider variables set in the current shell.
+// Skint clears them when launching, but they affect claude run directly.
+func checkEnvConflicts(lookup func(string) (string, bool)) doctorCheck {
+	c := doctorCheck{Name: "Environment"}
+	var set []string
+	for _, name := range launcher.Conflicting() {
+		if _, ok := lookup(name); ok {
+			set = append(set, name)
+		}
+	}
+	if len(set) == 0 {
+		c.Status = checkOK
+		c.Message = "no provider variables set"
+		return c
+	}
+	c.Status = checkWarn
+	c.Message = fmt.Sprintf("set in this shell: %s (skint replaces them, but claude run directly uses them)", strings.Join(set, ", "))
+	c.Fix = "Remove them from your shell profile, or run: unset " + strings.Join(set, " ")
+	return c
+}
+
+// checkDefaultProvider checks that the default provider's endpoint answers.
+func checkDefaultProvider(cc *CmdContext) doctorCheck {
+	c := doctorCheck{Name: "Default"}
+	name := cc.DefaultProviderName()
+	var p *config.Provider
+	if name == "" || name == "native" {
+		name = "native"
+		p = &config.Provider{Name: name, Type: config.ProviderTypeBuiltin}
+	} else if p = cc.Cfg.GetProvider(name); p == nil {
+		c.Status = checkFail
+		c.Message = fmt.Sprintf("default provider %s is not configured", name)
+		c.Fix = fmt.Sprintf("Configure it with skint config %s, or choose another with skint use", name)
+		return c
+	}
+
+	if p.NeedsAPIKey() && p.GetAPIKey() == "" {
+		c.Status = checkFail
+		c.Message = fmt.Sprintf("%s has no API key", name)
+		c.Fix = fmt.Sprintf("Set one with skint config %s", name)
+		return c
+	}
+	if p.BaseURL == "" && name != "native" {
+		c.Status = checkWarn
+		c.Message = fmt.Sprintf("%s has no endpoint to test", name)
+		return c
+	}
+	result := testProvider(p, cc.Cfg.NetworkTimeout())
+	if !result.reachable {
+		c.Status = checkFail
+		c.Message = fmt.Sprintf("%s is unreachable: %s", name, result.errMsg)
+		switch {
+		case p.Type == config.ProviderTypeLocal:
+			c.Fix = fmt.Sprintf("Start the server at %s, or check its base_url", p.BaseURL)
+		case name == "native":
+			c.Fix = "Check your network connection"
+		default:
+			c.Fix = fmt.Sprintf("Check your network and %s's base_url, then retry with skint test %s", name, name)
+		}
+		return c
+	}
+	if result.server != nil && !result.server.Loaded {
+		c.Status = checkWarn
+		c.Message = fmt.Sprintf("%s is reachable but its model isn't loaded yet (HTTP %d)", name, result.statusCode)
+		c.Fix = "Wait for llama-server to finish loading the model"
+		return c
+	}
+	c.Status = checkOK
+	c.Message = fmt.Sprintf("%s is reachable (HTTP %d)", name, result.statusCode)
+	if result.server != nil {
+		c.Message = fmt.Sprintf("%s is reachable (HTTP %d, %s)", name, result.statusCode, result.server)
+	}
+	return c
+}
+
+// checkPermissions checks that the config and secrets are private to the user.
+func checkPermissions(mgr *config.Manager) doctorCheck {
+	c := doctorCheck{Name: "Permissions"}
+	issues, err := mgr.CheckPermissions()
+	if err != nil {
+		c.Status = checkWarn
+		c.Message = err.Error()
+		return c
+	}
+	if len(issues) == 0 {
+		c.Status = checkOK
+		c.Message = "config and secrets are private to you"
+		if runtime.GOOS == "windows" {
+			c.Message = "not checked on Windows"
+		}
+		return c
+	}
+
+	var problems []string
+	var fixable, symlinks bool
+	for _, issue := range issues {
+		problems = append(problems, issue.Path+" "+issue.Problem())
+		fixable = fixable || !issue.Symlink
+		symlinks = symlinks || issue.Symlink
+	}
+	c.Status = checkFail
+	c.Message = strings.Join(problems, "; ")
+	var fixes []string
+	if fixable {
+		fixes = append(fixes, "Restrict them: skint status --fix")
+	}
+	if symlinks {
+		fixes = append(fixes, "Replace symlinks with the real files; skint will not follow them")
+	}
+	c.Fix = strings.Join(fixes, "\n")
+	return c
+}
@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/launcher"
+	"github.com/sammcj/skint/internal/providers"
+)
+
+func TestCheckPath(t *testing.T) {
+	bin := filepath.Join(t.TempDir(), "bin")
+	other := t.TempDir()
+
+	if c := checkPath(bin, strings.Join([]string{other, bin}, string(os.PathListSeparator))); c.Status != checkOK {
+		t.Errorf("bin dir on PATH: status %s, want ok", c.Status)
+	}
+	c := checkPath(bin, other)
+	if c.Status != checkWarn || !strings.Contains(c.Fix, bin) {
+		t.Errorf("bin dir not on PATH: status %s, fix %q; want a warning suggesting %s", c.Status, c.Fix, bin)
+	}
+}
+
+func TestCheckEnvConflicts(t *testing.T) {
+	env := map[string]string{"ANTHROPIC_BASE_URL": "http://x", "HOME": "/home/me"}
+	lookup := func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+	c := checkEnvConflicts(lookup)
+	if c.Status != checkWarn || !strings.Contains(c.Message, "ANTHROPIC_BASE_URL") || strings.Contains(c.Message, "HOME") {
+		t.Errorf("status %s, message %q; want a warning naming only ANTHROPIC_BASE_URL", c.Status, c.Message)
+	}
+	if !strings.Contains(c.Fix, "unset ANTHROPIC_BASE_URL") {
+		t.Errorf("fix %q should unset the variable", c.Fix)
+	}
+
+	delete(env, "ANTHROPIC_BASE_URL")
+	if c := checkEnvConflicts(lookup); c.Status != checkOK {
+		t.Errorf("nothing set: status %s, want ok", c.Status)
+	}
+}
+
+func TestCheckScripts(t *testing.T) {
+	binDir := t.TempDir()
+	cfg := config.NewDefaultConfig()
+	ollama := &config.Provider{Name: "ollama", Type: config.ProviderTypeLocal, DisplayName: "Ollama", BaseURL: "http://localhost:11434", Model: "qwen3"}
+	cfg.Providers = []*config.Provider{ollama}
+	cc := &CmdContext{Cfg: cfg}
+
+	if c := checkScripts(cc, binDir); c.Status != checkOK || !strings.Contains(c.Message, "none") {
+		t.Errorf("no scripts: status %s, message %q", c.Status, c.Message)
+	}
+
+	prov, err := providers.FromConfig(ollama)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := launcher.GenerateScript(prov, binDir, nil, nil, false); err != nil {
+		t.Fatal(err)
+	}
+	if c := checkScripts(cc, binDir); c.Status != checkOK {
+		t.Errorf("up-to-date script: status %s, message %q", c.Status, c.Message)
+	}
+
+	// A changed model makes the script stale
+	ollama.Model = "llama4"
+	c := checkScripts(cc, binDir)
+	if c.Status != checkWarn || !strings.Contains(c.Message, "out of date") || !strings.Contains(c.Fix, "skint generate-scripts") {
+		t.Errorf("stale script: status %s, message %q, fix %q", c.Status, c.Message, c.Fix)
+	}
+
+	// A script for a removed provider is orphaned
+	ollama.Model = "qwen3"
+	orphan := launcher.ScriptPath(binDir, "gone")
+	if err := os.WriteFile(orphan, []byte("#!/bin/sh\n"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	c = checkScripts(cc, binDir)
+	if c.Status != checkWarn || !strings.Contains(c.Message, "no longer configured") || !strings.Contains(c.Fix, orphan) {
+		t.Errorf("orphaned script: status %s, message %q, fix %q", c.Status, c.Message, c.Fix)
+	}
+}
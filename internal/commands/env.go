@@ -24,8 +24,9 @@ Add this to your shell profile to have Claude always use the configured provider
 Or for a specific provider:
 
   eval "$(skint env openrouter)"`,
-		Args: cobra.MaximumNArgs(1),
-		RunE: runEnv,
+		Args:              cobra.MaximumNArgs(1),
+		RunE:              runEnv,
+		ValidArgsFunction: completeProvider,
 	}
 
 	cmd.Flags().Bool("unset", false, "print unset statements instead (to clear provider env vars)")
@@ -42,7 +43,7 @@ func runEnv(cmd *cobra.Command, args []string) error {
 	}
 
 	// Determine which provider to use
-	providerName := cc.Cfg.DefaultProvider
+	providerName := cc.DefaultProviderName()
 	if len(args) > 0 {
 		providerName = args[0]
 	}
@@ -65,8 +66,15 @@ func runEnv(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create provider %s: %w", providerName, err)
 	}
 
-	// Get env vars
+	// Get env vars, with any env presets attached to the provider on top
 	envVars := provider.GetEnvVars()
+	presetEnv, err := cc.PresetEnv(p)
+	if err != nil {
+		return err
+	}
+	for k, v := range presetEnv {
+		envVars[k] = v
+	}
 
 	// Print in sorted order for deterministic output
 	keys := make([]string, 0, len(envVars))
@@ -91,20 +99,23 @@ func runEnv(cmd *cobra.Command, args []string) error {
 	default:
 		fmt.Printf("# skint: provider %s\n", provider.DisplayName())
 		for _, k := range keys {
-			v := envVars[k]
-			if v == "" {
-				fmt.Printf("unset %s\n", k)
-			} else {
-				// Escape single quotes for safe shell eval
-				escaped := strings.ReplaceAll(v, "'", "'\"'\"'")
-				fmt.Printf("export %s='%s'\n", k, escaped)
-			}
+			fmt.Println(exportStatement(k, envVars[k]))
 		}
 	}
 
 	return nil
 }
 
+// exportStatement returns the shell statement setting name to value, or
+// unsetting it if value is empty.
+func exportStatement(name, value string) string {
+	if value == "" {
+		return "unset " + name
+	}
+	// Escape single quotes for safe shell eval
+	return fmt.Sprintf("export %s='%s'", name, strings.ReplaceAll(value, "'", `'"'"'`))
+}
+
 func printUnsetStatements() error {
 	vars := []string{
 		"ANTHROPIC_BASE_URL",
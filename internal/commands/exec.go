@@ -3,11 +3,16 @@ package commands
 import (
 	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/exec"
+	"strings"
+	"time"
 
+	"github.com/sammcj/skint/internal/errcode"
 	"github.com/sammcj/skint/internal/launcher"
 	"github.com/sammcj/skint/internal/providers"
+	"github.com/sammcj/skint/internal/pty"
 	"github.com/sammcj/skint/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -15,13 +20,42 @@ import (
 // NewExecCmd creates the exec command
 func NewExecCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "exec <command> [args...]",
+		Use:   "exec [-p provider] [--model model] [--isolated] [--pty] [--sandbox docker[:image]] [--inherit-env name] <command> [args...]",
 		Short: "Execute a command with provider environment",
 		Long: `Execute any command with the configured provider's environment variables set.
 
 This allows you to run any command (not just Claude) with the provider's
-API keys and endpoints configured in the environment.`,
+API keys and endpoints configured in the environment.
+
+The provider is the one for the current directory (see 'skint use') unless
+-p/--provider names another; --model overrides its model. Both only apply to
+this command and go before it.
+
+--isolated passes on only a minimal environment (PATH, HOME, USER, SHELL,
+TERM, LANG, TMPDIR and the like) plus the provider's and env presets'
+variables, rather than everything in yours, so other keys and tokens aren't
+exposed to the command.
+
+--pty runs the command in a pseudo-terminal of skint's (Linux and macOS),
+so resizes, colours and prompts behave as if it were run directly even when
+skint itself runs under another wrapper. The pty setting does the same for
+every launch.
+
+--sandbox docker[:image] (or podman) runs the command in a throwaway
+container instead, with only the provider's and env presets' variables and
+the current directory mounted at /workspace, to limit what an untrusted
+endpoint, or the agent talking to it, can reach. The image (default
+node:lts-slim) must have the command in it. Servers on localhost are
+reached through host.docker.internal.
+
+--inherit-env <name> (repeatable, or comma-separated) keeps a variable from
+your environment that skint would otherwise remove or replace, like the
+provider's preserve_env setting.`,
 		Example: `  skint exec claude --continue
+  skint exec -p ollama --model qwen3 claude
+  skint exec --isolated claude
+  skint exec --pty claude
+  skint exec --sandbox docker:node:22 npx -y @anthropic-ai/claude-code
   skint exec claude --dangerously-skip-permissions
   skint exec env | grep ANTHROPIC
   skint exec /bin/bash -c "echo \$ANTHROPIC_BASE_URL"`,
@@ -36,12 +70,22 @@ API keys and endpoints configured in the environment.`,
 func runExec(cmd *cobra.Command, args []string) error {
 	cc := GetContext(cmd)
 
+	opts, args, err := parseExecArgs(args)
+	if err != nil {
+		return err
+	}
+	if opts.help {
+		return cmd.Help()
+	}
 	if len(args) == 0 {
-		return fmt.Errorf("no command specified")
+		return errcode.New(errcode.Usage, "no command specified")
 	}
 
-	// Get the default provider or the one specified
-	providerName := cc.Cfg.DefaultProvider
+	// Get the provider named with --provider, or the default
+	providerName := opts.provider
+	if providerName == "" {
+		providerName = cc.DefaultProviderName()
+	}
 	if providerName == "" {
 		if len(cc.Cfg.Providers) == 0 {
 			return fmt.Errorf("no providers configured. Run 'skint config' to add one")
@@ -58,6 +102,10 @@ func runExec(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	p = modelOverride{model: opts.model}.apply(p)
+	if opts.inheritEnv != "" {
+		p = withInheritEnv(p, []string{opts.inheritEnv})
+	}
 
 	// Convert to provider interface
 	provider, err := providers.FromConfig(p)
@@ -65,40 +113,79 @@ func runExec(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create provider %s: %w", providerName, err)
 	}
 
-	// Build environment -- remove conflicting vars first
-	env := launcher.FilterEnvVars(os.Environ(), launcher.ConflictingEnvVars...)
-
-	// Add provider-specific variables
-	providerVars := provider.GetEnvVars()
-	for key, value := range providerVars {
-		env = append(env, fmt.Sprintf("%s=%s", key, value))
-	}
-
-	// Show banner if enabled
-	if !cc.Cfg.NoBanner && !cc.Quiet {
-		ui.Log("Executing with %s", ui.Green(provider.DisplayName()))
+	presetEnv, err := cc.PresetEnv(p)
+	if err != nil {
+		return err
 	}
 
 	// Get the command to execute
 	command := args[0]
 	commandArgs := args[1:]
 
-	// If the command is "claude", check if it exists
-	if command == "claude" {
-		_, err := exec.LookPath("claude")
+	var execCmd *exec.Cmd
+	if opts.sandbox != "" {
+		sb, err := parseSandbox(opts.sandbox)
 		if err != nil {
-			return fmt.Errorf("claude command not found. Please install Claude Code: https://claude.ai/install.sh")
+			return err
+		}
+		dir, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get working directory: %w", err)
+		}
+		if execCmd, err = sb.command(provider, presetEnv, dir, args); err != nil {
+			return err
+		}
+		if !cc.Cfg.NoBanner && !cc.Quiet {
+			ui.Log("Executing with %s in %s (%s)", ui.Green(provider.DisplayName()), sb.runtime, sb.image)
+		}
+	} else {
+		// Build environment -- conflicting vars removed, provider and preset vars added
+		env := launcher.BuildEnv(provider, presetEnv)
+		if opts.isolated {
+			env = launcher.IsolatedEnv(provider, presetEnv)
 		}
+
+		// Show banner if enabled
+		if !cc.Cfg.NoBanner && !cc.Quiet {
+			ui.Log("Executing with %s", ui.Green(provider.DisplayName()))
+		}
+
+		// If the command is "claude", check if it exists
+		if command == "claude" {
+			_, err := exec.LookPath("claude")
+			if err != nil {
+				return errcode.New(errcode.ClaudeNotFound, "claude command not found. Please install Claude Code: https://claude.ai/install.sh")
+			}
+		}
+
+		execCmd = exec.Command(command, commandArgs...)
+		execCmd.Env = env
 	}
 
 	// Execute the command
-	execCmd := exec.Command(command, commandArgs...)
-	execCmd.Env = env
 	execCmd.Stdin = os.Stdin
 	execCmd.Stdout = os.Stdout
 	execCmd.Stderr = os.Stderr
 
-	if err := execCmd.Run(); err != nil {
+	usePTY := opts.pty || cc.Cfg.PTY
+	slog.Info("running command", "command", command, "args", commandArgs, "provider", providerName, "isolated", opts.isolated, "pty", usePTY, "sandbox", opts.sandbox)
+	start := time.Now()
+	wait := execCmd.Wait
+	if usePTY {
+		session, err := pty.Start(execCmd, os.Stdout)
+		if err != nil {
+			return err
+		}
+		wait = session.Wait
+	} else if err := execCmd.Start(); err != nil {
+		return err
+	}
+	recordExit := cc.recordUse(providerName, p.EffectiveModel(), command)
+	err = wait()
+	d := time.Since(start)
+	slog.Info("command exited", "command", command, "status", execCmd.ProcessState.ExitCode(), "duration", d)
+	recordExit(execCmd.ProcessState.ExitCode(), d)
+	if err != nil {
 		var exitErr *exec.ExitError
 		if errors.As(err, &exitErr) {
 			os.Exit(exitErr.ExitCode())
@@ -107,3 +194,67 @@ func runExec(cmd *cobra.Command, args []string) error {
 	}
 	return nil
 }
+
+// execOptions are skint exec's own flags.
+type execOptions struct {
+	provider string
+	model    string
+	isolated bool
+	pty      bool
+	sandbox  string
+	// inheritEnv is the comma-separated --inherit-env names
+	inheritEnv string
+	help       bool
+}
+
+// parseExecArgs splits args into skint exec's flags, which come before the
+// command, and the command with its arguments, which are left alone. "--"
+// ends the flags.
+func parseExecArgs(args []string) (execOptions, []string, error) {
+	var opts execOptions
+	for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+		arg := args[0]
+		args = args[1:]
+		if arg == "--" {
+			break
+		}
+		switch arg {
+		case "-h", "--help":
+			opts.help = true
+			continue
+		case "--isolated":
+			opts.isolated = true
+			continue
+		case "--pty":
+			opts.pty = true
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(arg, "=")
+		var dest *string
+		switch name {
+		case "-p", "--provider":
+			dest = &opts.provider
+		case "--model":
+			dest = &opts.model
+		case "--sandbox":
+			dest = &opts.sandbox
+		case "--inherit-env":
+			dest = new(string)
+		default:
+			return execOptions{}, nil, errcode.New(errcode.Usage, "unknown flag %s (flags for the command go after it)", arg)
+		}
+		if !hasValue {
+			if len(args) == 0 {
+				return execOptions{}, nil, errcode.New(errcode.Usage, "flag needs an argument: %s", name)
+			}
+			value, args = args[0], args[1:]
+		}
+		*dest = value
+		if name == "--inherit-env" {
+			// Repeats add to the list
+			opts.inheritEnv = strings.Trim(opts.inheritEnv+","+value, ",")
+		}
+	}
+	return opts, args, nil
+}
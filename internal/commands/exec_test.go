@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseExecArgs(t *testing.T) {
+	tests := []struct {
+		args     []string
+		want     execOptions
+		wantRest []string
+	}{
+		{[]string{"claude", "-p", "hi"}, execOptions{}, []string{"claude", "-p", "hi"}},
+		{[]string{"-p", "ollama", "claude", "-p", "hi"}, execOptions{provider: "ollama"}, []string{"claude", "-p", "hi"}},
+		{[]string{"--provider=zai", "--model", "glm-5", "env"}, execOptions{provider: "zai", model: "glm-5"}, []string{"env"}},
+		{[]string{"-p=zai", "--", "--odd-command"}, execOptions{provider: "zai"}, []string{"--odd-command"}},
+		{[]string{"--isolated", "-p", "zai", "claude", "--isolated"}, execOptions{provider: "zai", isolated: true}, []string{"claude", "--isolated"}},
+		{[]string{"--pty", "claude"}, execOptions{pty: true}, []string{"claude"}},
+		{[]string{"--sandbox", "docker:node:22", "claude"}, execOptions{sandbox: "docker:node:22"}, []string{"claude"}},
+		{[]string{"--inherit-env", "A,B", "--inherit-env=C", "env"}, execOptions{inheritEnv: "A,B,C"}, []string{"env"}},
+		{[]string{"--help"}, execOptions{help: true}, nil},
+	}
+	for _, tc := range tests {
+		opts, rest, err := parseExecArgs(tc.args)
+		if err != nil {
+			t.Errorf("parseExecArgs(%q): %v", tc.args, err)
+			continue
+		}
+		if opts != tc.want || !slices.Equal(rest, tc.wantRest) {
+			t.Errorf("parseExecArgs(%q) = %+v, %q; want %+v, %q", tc.args, opts, rest, tc.want, tc.wantRest)
+		}
+	}
+
+	for _, args := range [][]string{{"-p"}, {"--model"}, {"--sandbox"}, {"--continue", "claude"}} {
+		if _, _, err := parseExecArgs(args); err == nil {
+			t.Errorf("parseExecArgs(%q) should fail", args)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/errcode"
+	"github.com/sammcj/skint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// exportFormats are the formats skint export writes.
+var exportFormats = []string{"litellm"}
+
+// NewExportCmd creates the export command
+func NewExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export [provider...]",
+		Short: "Export providers to another tool's config",
+		Long: `Export configured providers (all of them, or those named) in another
+tool's config format.
+
+--format litellm writes a LiteLLM proxy config.yaml with a model_list entry
+per provider, named after it. API keys are never written: entries read them
+from the environment, as os.environ/OPENROUTER_API_KEY for OpenRouter
+providers and os.environ/<NAME>_API_KEY for the rest. With --file, an
+existing LiteLLM config is updated in place, keeping its other entries and
+settings, so it can be re-run to keep a gateway in sync; otherwise the
+config is printed.
+
+Use 'skint migrate --from litellm --file config.yaml' for the other
+direction.`,
+		Example: `  skint export --format litellm > litellm.yaml
+  skint export --format litellm --file /etc/litellm/config.yaml
+  skint export zai or-kimi-k2 --format litellm`,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+			return providerCompletions(completionConfig(cmd), toComplete), cobra.ShellCompDirectiveNoFileComp
+		},
+		Annotations: map[string]string{annotationSkipSecrets: "true"},
+		RunE:        runExport,
+	}
+	cmd.Flags().String("format", "litellm", "export format: "+strings.Join(exportFormats, ", "))
+	cmd.Flags().String("file", "", "update this file instead of printing")
+	_ = cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(exportFormats, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	cc := GetContext(cmd)
+
+	format, _ := cmd.Flags().GetString("format")
+	if !slices.Contains(exportFormats, format) {
+		return errcode.New(errcode.Usage, "unknown export format %q: use %s", format, strings.Join(exportFormats, ", "))
+	}
+	file, _ := cmd.Flags().GetString("file")
+
+	providers := cc.Cfg.Providers
+	if len(args) > 0 {
+		providers = nil
+		for _, name := range args {
+			p := cc.Cfg.GetProvider(name)
+			if p == nil {
+				return errcode.New(errcode.ProviderNotFound, "provider %s is not configured", name)
+			}
+			providers = append(providers, p)
+		}
+	}
+	if len(providers) == 0 {
+		return fmt.Errorf("no providers configured")
+	}
+
+	var existing []byte
+	if file != "" {
+		var err error
+		if existing, err = os.ReadFile(file); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read %s: %w", file, err)
+		}
+	}
+	data, skipped, err := config.ExportLiteLLM(providers, existing)
+	if err != nil {
+		return err
+	}
+	exported := len(providers) - len(skipped)
+	if exported == 0 {
+		for _, s := range skipped {
+			ui.Warning("Skipped %s", s)
+		}
+		return fmt.Errorf("none of the providers can be exported to %s", format)
+	}
+
+	if file == "" {
+		if cc.Cfg.OutputFormat == config.FormatHuman {
+			for _, s := range skipped {
+				ui.Warning("Skipped %s", s)
+			}
+		}
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+
+	if err := os.WriteFile(file, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", file, err)
+	}
+	switch cc.Cfg.OutputFormat {
+	case config.FormatJSON:
+		return cc.Output(map[string]any{"format": format, "file": file, "exported": exported, "skipped": skipped})
+	case config.FormatPlain:
+		fmt.Println(file)
+		return nil
+	}
+	for _, s := range skipped {
+		ui.Warning("Skipped %s", s)
+	}
+	ui.Success("Exported %d provider(s) to %s", exported, file)
+	return nil
+}
@@ -0,0 +1,116 @@
+package commands
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/launcher"
+	"github.com/sammcj/skint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// NewExportEnvCmd creates the export-env command
+func NewExportEnvCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export-env <provider>",
+		Short: "Write a provider's environment as a dotenv file",
+		Long: `Write the environment variables skint use would set for a provider, env
+presets included, as a dotenv file for docker compose (env_file) and other
+tools that read one.
+
+The API key and auth token are left out, with a comment in their place,
+unless --include-key is given. The file is printed, or written to --file
+(mode 0600 when it holds a key); an existing file is only replaced with
+--force.`,
+		Example: `  skint export-env zai > zai.env
+  skint export-env zai --file provider.env --include-key`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeProvider,
+		RunE:              runExportEnv,
+	}
+	cmd.Flags().String("file", "", "write to this file instead of printing")
+	cmd.Flags().Bool("include-key", false, "include the API key and auth token")
+	cmd.Flags().Bool("force", false, "overwrite an existing file")
+	return cmd
+}
+
+func runExportEnv(cmd *cobra.Command, args []string) error {
+	cc := GetContext(cmd)
+	name := args[0]
+	file, _ := cmd.Flags().GetString("file")
+	includeKey, _ := cmd.Flags().GetBool("include-key")
+	force, _ := cmd.Flags().GetBool("force")
+
+	if file != "" && !force {
+		if _, err := os.Lstat(file); err == nil {
+			return fmt.Errorf("%s already exists. Use --force to overwrite", file)
+		}
+	}
+
+	p, err := cc.ResolveProvider(name)
+	if err != nil {
+		return err
+	}
+	plan, err := cc.launchPlan(name)
+	if err != nil {
+		return err
+	}
+	data, omitted := dotenvFile(p, plan, includeKey)
+
+	if file == "" {
+		_, err := os.Stdout.WriteString(data)
+		return err
+	}
+	perm := os.FileMode(0o644)
+	if includeKey {
+		perm = 0o600
+	}
+	if err := writeFileMode(file, []byte(data), perm); err != nil {
+		return fmt.Errorf("failed to write %s: %w", file, err)
+	}
+
+	switch cc.Cfg.OutputFormat {
+	case config.FormatJSON:
+		return cc.Output(map[string]any{
+			"provider":    p.Name,
+			"file":        file,
+			"include_key": includeKey,
+			"vars":        len(plan.Env) - len(omitted),
+			"omitted":     omitted,
+		})
+	case config.FormatPlain:
+		fmt.Println(file)
+		return nil
+	}
+	ui.Success("Wrote %s's environment to %s", p.Name, file)
+	switch {
+	case includeKey:
+		ui.Dim("The file contains the API key - keep it out of version control\n")
+	case len(omitted) > 0:
+		ui.Dim("Left out %s. Use --include-key to include it\n", strings.Join(omitted, ", "))
+	}
+	return nil
+}
+
+// dotenvFile returns plan's environment as a dotenv file, in name order,
+// and the variables left out because they hold p's API key or auth token.
+// Variables the plan clears are written empty.
+func dotenvFile(p *config.Provider, plan *launcher.Plan, includeKey bool) (string, []string) {
+	var b strings.Builder
+	var omitted []string
+	fmt.Fprintf(&b, "# %s, written by skint export-env\n", p.Name)
+	for _, name := range slices.Sorted(maps.Keys(plan.Env)) {
+		value := plan.Env[name]
+		if !includeKey && value != "" && (value == p.GetAPIKey() || value == p.AuthToken) {
+			omitted = append(omitted, name)
+			fmt.Fprintf(&b, "# %s left out: use --include-key\n", name)
+			continue
+		}
+		b.WriteString(config.DotenvLine(name, value) + "\n")
+	}
+	return b.String(), omitted
+}
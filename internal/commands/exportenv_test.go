@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/launcher"
+)
+
+func TestDotenvFile(t *testing.T) {
+	p := &config.Provider{Name: "zai"}
+	p.SetResolvedAPIKey("sk-zai")
+	plan := &launcher.Plan{Env: map[string]string{
+		"ANTHROPIC_BASE_URL":   "https://api.z.ai/api/anthropic",
+		"ANTHROPIC_AUTH_TOKEN": "sk-zai",
+		"ANTHROPIC_API_KEY":    "",
+		"API_TIMEOUT_MS":       "300000 # slow",
+	}}
+
+	got, omitted := dotenvFile(p, plan, false)
+	want := `# zai, written by skint export-env
+ANTHROPIC_API_KEY=
+# ANTHROPIC_AUTH_TOKEN left out: use --include-key
+ANTHROPIC_BASE_URL=https://api.z.ai/api/anthropic
+API_TIMEOUT_MS='300000 # slow'
+`
+	if got != want {
+		t.Errorf("dotenvFile() =\n%s\nwant\n%s", got, want)
+	}
+	if strings.Join(omitted, ",") != "ANTHROPIC_AUTH_TOKEN" {
+		t.Errorf("omitted = %v", omitted)
+	}
+
+	got, omitted = dotenvFile(p, plan, true)
+	env, err := config.ParseDotenv([]byte(got))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(omitted) != 0 || len(env) != len(plan.Env) || env["ANTHROPIC_AUTH_TOKEN"] != "sk-zai" || env["API_TIMEOUT_MS"] != "300000 # slow" {
+		t.Errorf("with the key: omitted %v, env %v", omitted, env)
+	}
+}
+
+func TestExportEnvReplacesFileMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows has no Unix file modes")
+	}
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	file := filepath.Join(t.TempDir(), "provider.env")
+	if err := os.WriteFile(file, []byte("OLD=1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.NewDefaultConfig()
+	cfg.OutputFormat = config.FormatPlain
+	cfg.Providers = []*config.Provider{{
+		Name: "gpu", Type: config.ProviderTypeLocal, BaseURL: "http://gpu-box:8080", AuthToken: "secret-token",
+	}}
+	cmd := NewExportEnvCmd()
+	cmd.SetContext(context.WithValue(context.Background(), ctxKey, &CmdContext{Cfg: cfg}))
+	cmd.SetArgs([]string{"gpu", "--file", file, "--force", "--include-key"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("export-env: %v", err)
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("mode = %o, want 600 for a file holding a key", perm)
+	}
+	if data, _ := os.ReadFile(file); !strings.Contains(string(data), "secret-token") || strings.Contains(string(data), "OLD=1") {
+		t.Errorf("file not replaced with the provider's environment:\n%s", data)
+	}
+}
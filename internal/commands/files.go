@@ -0,0 +1,32 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// writeFileMode writes data to file with exactly perm, replacing any existing
+// file. os.WriteFile only applies perm when it creates the file, so secrets
+// (a key in export-env's output, a backup) written over an existing 0644
+// file would be left world-readable.
+func writeFileMode(file string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(file), "."+filepath.Base(file)+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer func() { _ = os.Remove(tmpPath) }() // no-op after a successful rename
+
+	if err := tmp.Chmod(perm); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, file)
+}
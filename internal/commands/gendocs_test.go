@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenDocs(t *testing.T) {
+	root := NewRootCmd("1.2.3")
+	root.AddCommand(NewListCmd(), NewGenDocsCmd())
+
+	t.Run("man", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "man")
+		files, err := genDocs(root.Command, "man", dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []string{filepath.Join(dir, "skint-gen-docs.1"), filepath.Join(dir, "skint-list.1"), filepath.Join(dir, "skint.1")}
+		if strings.Join(files, ",") != strings.Join(want, ",") {
+			t.Errorf("files = %v, want %v", files, want)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, "skint-list.1"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		page := string(data)
+		if !strings.Contains(page, `.TH "SKINT" "1"`) || !strings.Contains(page, "skint 1.2.3") {
+			t.Errorf("man page header missing:\n%s", page)
+		}
+		if !strings.Contains(page, "configured") {
+			t.Errorf("man page missing the list flags:\n%s", page)
+		}
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		dir := t.TempDir()
+		files, err := genDocs(root.Command, "markdown", dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(files) != 3 {
+			t.Fatalf("files = %v, want 3", files)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, "skint_list.md"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(data), "Auto generated") {
+			t.Error("markdown has the generated-on footer, so builds aren't reproducible")
+		}
+	})
+}
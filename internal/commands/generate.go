@@ -1,32 +1,69 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
 
 	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/errcode"
 	"github.com/sammcj/skint/internal/launcher"
 	"github.com/sammcj/skint/internal/providers"
 	"github.com/sammcj/skint/internal/ui"
 	"github.com/spf13/cobra"
 )
 
-// NewGenerateCmd creates the generate-scripts command
-func NewGenerateCmd() *cobra.Command {
-	return &cobra.Command{
+// NewGenerateScriptsCmd creates the generate-scripts command
+func NewGenerateScriptsCmd() *cobra.Command {
+	cmd := &cobra.Command{
 		Use:   "generate-scripts",
 		Short: "Generate shell scripts for providers",
 		Long: `Generate legacy shell scripts for all configured providers.
 
-This creates scripts like 'skintai' in your bin directory for
-backward compatibility with the old bash version.`,
+This creates scripts like 'skint-zai' in your bin directory for
+backward compatibility with the old bash version. On Windows each provider
+gets a skint-<provider>.cmd for cmd.exe and a .ps1 for PowerShell instead.
+
+--shell picks the shells to write scripts for, side by side: bash
+(skint-<provider>), fish (skint-<provider>.fish), powershell
+(skint-<provider>.ps1, which PowerShell also runs as skint-<provider>) and
+cmd (skint-<provider>.cmd).
+
+Scripts skint wrote for providers that are no longer configured are removed.
+Scripts changed by hand since they were generated are left alone, with a
+warning, unless --force is given; --prune also removes scripts for removed
+providers that were changed, or written before skint kept track of them.`,
+		Example: `  skint generate-scripts
+  skint generate-scripts --shell bash,fish
+  skint generate-scripts --shell powershell
+  skint generate-scripts --prune`,
+		Args: cobra.NoArgs,
 		RunE: runGenerate,
 	}
+	cmd.Flags().StringSlice("shell", nil, "shells to write scripts for: "+strings.Join(launcher.ScriptShells, ", ")+" (default "+strings.Join(launcher.DefaultShells(), ",")+")")
+	cmd.Flags().Bool("force", false, "replace scripts changed by hand")
+	cmd.Flags().Bool("prune", false, "remove every script for a provider that is no longer configured")
+	_ = cmd.RegisterFlagCompletionFunc("shell", cobra.FixedCompletions(launcher.ScriptShells, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
 }
 
 func runGenerate(cmd *cobra.Command, args []string) error {
 	cc := GetContext(cmd)
+	shells, _ := cmd.Flags().GetStringSlice("shell")
+	for _, shell := range shells {
+		if !slices.Contains(launcher.ScriptShells, shell) {
+			return errcode.New(errcode.Usage, "invalid --shell %q (valid: %s)", shell, strings.Join(launcher.ScriptShells, ", "))
+		}
+	}
+	if len(shells) == 0 {
+		shells = launcher.DefaultShells()
+	}
+	force, _ := cmd.Flags().GetBool("force")
+	prune, _ := cmd.Flags().GetBool("prune")
 
 	// Get bin directory
 	binDir, err := config.GetBinDir()
@@ -39,9 +76,14 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create bin directory: %w", err)
 	}
 
+	if _, err := launcher.LoadManifest(binDir); err != nil {
+		ui.Warning("%v: scripts written before are no longer tracked", err)
+	}
+
 	// Generate scripts for all providers
 	generated := 0
 	failed := 0
+	var modified []string
 
 	for _, p := range cc.Cfg.Providers {
 		// Load API key if needed
@@ -66,7 +108,30 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 			failed++
 			continue
 		}
-		if err := launcher.GenerateScript(provider, binDir); err != nil {
+		presetEnv, err := cc.PresetEnv(p)
+		if err != nil {
+			if cc.Verbose {
+				ui.Warning("Skipping %s: %v", p.Name, err)
+			}
+			failed++
+			continue
+		}
+		mcp, err := config.WriteMCPConfig(p)
+		if err != nil {
+			if cc.Verbose {
+				ui.Warning("Skipping %s: %v", p.Name, err)
+			}
+			failed++
+			continue
+		}
+		err = launcher.GenerateScript(provider, binDir, presetEnv, append(cc.Cfg.LaunchArgs(p), mcp...), force, shells...)
+		var edited *launcher.ModifiedError
+		if errors.As(err, &edited) {
+			ui.Warning("Skipping %s: %v (--force replaces it)", p.Name, err)
+			modified = append(modified, edited.Names...)
+			continue
+		}
+		if err != nil {
 			if cc.Verbose {
 				ui.Warning("Failed to generate script for %s: %v", p.Name, err)
 			}
@@ -77,6 +142,11 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 		generated++
 	}
 
+	removed, left, err := cleanScripts(cc.Cfg, binDir, prune)
+	if err != nil {
+		return err
+	}
+
 	// Save banner
 	if err := saveBanner(); err != nil && cc.Verbose {
 		ui.Warning("Failed to save banner: %v", err)
@@ -84,10 +154,19 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 
 	// Output results
 	if cc.Cfg.OutputFormat == config.FormatJSON {
+		for _, list := range []*[]string{&modified, &removed, &left} {
+			if *list == nil {
+				*list = []string{}
+			}
+		}
 		return cc.Output(map[string]any{
 			"generated": generated,
 			"failed":    failed,
 			"bin_dir":   binDir,
+			"shells":    shells,
+			"modified":  modified,
+			"removed":   removed,
+			"left":      left,
 		})
 	}
 
@@ -108,6 +187,14 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 		ui.Warning("Failed to generate %d scripts", failed)
 	}
 
+	if len(removed) > 0 {
+		ui.Info("Removed %d scripts for providers no longer configured: %s", len(removed), strings.Join(removed, ", "))
+	}
+	if len(left) > 0 {
+		ui.Warning("Left %d scripts for providers no longer configured, changed by hand or not written by this version: %s", len(left), strings.Join(left, ", "))
+		ui.Info("Remove them with: skint generate-scripts --prune")
+	}
+
 	// Check PATH
 	path := os.Getenv("PATH")
 	containsBinDir := false
@@ -120,30 +207,75 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 
 	if !containsBinDir {
 		ui.Warning("\n'%s' is not in your PATH.", binDir)
-		ui.Info("Add it to your shell profile:")
-		ui.Dim("  export PATH=\"%s:$PATH\"\n", binDir)
+		if runtime.GOOS == "windows" {
+			ui.Info("Add it to your user PATH, e.g. from PowerShell:")
+			ui.Dim("  [Environment]::SetEnvironmentVariable('Path', [Environment]::GetEnvironmentVariable('Path', 'User') + ';%s', 'User')\n", binDir)
+		} else {
+			ui.Info("Add it to your shell profile with: %s", ui.Green("skint setup-path"))
+		}
 	}
 
 	return nil
 }
 
+// scriptProvider returns the provider a skint-<provider> script in the bin
+// directory is for, from its file name.
+func scriptProvider(file string) (string, bool) {
+	name, ok := strings.CutPrefix(file, "skint-")
+	if !ok {
+		return "", false
+	}
+	// Scripts for shells other than bash have extensions
+	if ext := filepath.Ext(name); ext == ".fish" || ext == ".cmd" || ext == ".ps1" {
+		name = strings.TrimSuffix(name, ext)
+	}
+	return name, true
+}
+
+// cleanScripts removes the scripts in binDir for providers cfg no longer
+// has: those skint wrote, unchanged since, or with prune any of them. It
+// returns the names of those removed and of those left.
+func cleanScripts(cfg *config.Config, binDir string, prune bool) (removed, left []string, err error) {
+	manifest, _ := launcher.LoadManifest(binDir)
+	entries, err := os.ReadDir(binDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", binDir, err)
+	}
+	for _, e := range entries {
+		name := e.Name()
+		provider, ok := scriptProvider(name)
+		if !ok || e.IsDir() || cfg.GetProvider(provider) != nil {
+			continue
+		}
+		if !prune && (!manifest.Tracked(name) || manifest.Modified(name)) {
+			left = append(left, name)
+			continue
+		}
+		if err := os.Remove(filepath.Join(binDir, name)); err != nil {
+			return removed, left, fmt.Errorf("failed to remove %s: %w", name, err)
+		}
+		removed = append(removed, name)
+		manifest.Forget(name)
+	}
+	// Forget scripts removed by other means
+	for _, name := range manifest.Names() {
+		if _, err := os.Lstat(filepath.Join(binDir, name)); os.IsNotExist(err) {
+			manifest.Forget(name)
+		}
+	}
+	return removed, left, manifest.Save()
+}
+
 func saveBanner() error {
 	dataDir, err := config.GetDataDir()
 	if err != nil {
 		return err
 	}
 
-	if err := os.MkdirAll(dataDir, 0755); err != nil {
+	if err := os.MkdirAll(dataDir, 0700); err != nil {
 		return err
 	}
 
-	banner := `  ____ _       _   _
- / ___| | ___ | |_| |__   ___ _ __
-| |   | |/ _ \| __| '_ \ / _ \ '__|
-| |___| | (_) | |_| | | |  __/ |
- \____|_|\___/ \__|_| |_|\___|_|
-`
-
 	bannerPath := filepath.Join(dataDir, "banner")
-	return os.WriteFile(bannerPath, []byte(banner), 0644)
+	return os.WriteFile(bannerPath, []byte(ui.Banner), 0644)
 }
@@ -0,0 +1,173 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/launcher"
+	"github.com/sammcj/skint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// claudeCodeFeature is the dev container feature that installs Claude Code.
+const claudeCodeFeature = "ghcr.io/anthropics/devcontainer-features/claude-code:1"
+
+// dockerHost is how a container reaches servers on its host.
+const dockerHost = "host.docker.internal"
+
+// NewGenerateCmd creates the generate command
+func NewGenerateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate files for using providers elsewhere",
+	}
+	cmd.AddCommand(NewGenerateDockerCmd())
+	cmd.AddCommand(NewGenerateEnvrcCmd())
+	return cmd
+}
+
+// NewGenerateDockerCmd creates the generate docker command
+func NewGenerateDockerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "docker <provider>",
+		Short: "Generate a docker env file and devcontainer.json snippet",
+		Long: `Write a docker --env-file for a provider to --dir (default .devcontainer)
+and print a devcontainer.json snippet that installs Claude Code in the
+container and passes it that file.
+
+The API key is not written: the env file names its variable without a
+value, so docker copies it from the environment it runs in, and neither the
+file nor the image holds the key. Run docker (or the dev container CLI, or
+your editor) under 'skint exec' so the key is there. Servers on localhost,
+such as Ollama, are reached through host.docker.internal.`,
+		Example: `  skint generate docker zai
+  skint exec -p zai docker run --rm -it --env-file .devcontainer/zai.env node:22 npx @anthropic-ai/claude-code
+  skint exec -p zai devcontainer up --workspace-folder .`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeProvider,
+		RunE:              runGenerateDocker,
+	}
+	cmd.Flags().String("dir", ".devcontainer", "directory to write the env file to")
+	cmd.Flags().Bool("force", false, "overwrite an existing env file")
+	_ = cmd.MarkFlagDirname("dir")
+	return cmd
+}
+
+// devcontainerSnippet is the part of a devcontainer.json that wires a
+// provider's env file into the container.
+type devcontainerSnippet struct {
+	Features map[string]any `json:"features"`
+	RunArgs  []string       `json:"runArgs"`
+}
+
+func runGenerateDocker(cmd *cobra.Command, args []string) error {
+	cc := GetContext(cmd)
+	name := args[0]
+	dir, _ := cmd.Flags().GetString("dir")
+	force, _ := cmd.Flags().GetBool("force")
+
+	p, err := cc.ResolveProvider(name)
+	if err != nil {
+		return err
+	}
+	plan, err := cc.launchPlan(name)
+	if err != nil {
+		return err
+	}
+	data, passThrough, usesHost, err := dockerEnvFile(p, plan)
+	if err != nil {
+		return err
+	}
+
+	file := filepath.Join(dir, p.Name+".env")
+	if _, err := os.Lstat(file); err == nil && !force {
+		return fmt.Errorf("%s already exists. Use --force to overwrite", file)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	if err := os.WriteFile(file, []byte(data), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", file, err)
+	}
+
+	// Relative paths are from the workspace, where the command is run
+	envFile := filepath.ToSlash(file)
+	if !filepath.IsAbs(file) {
+		envFile = "${localWorkspaceFolder}/" + envFile
+	}
+	snippet := devcontainerSnippet{
+		Features: map[string]any{claudeCodeFeature: map[string]any{}},
+		RunArgs:  []string{"--env-file", envFile},
+	}
+	if usesHost {
+		// Docker Desktop resolves the name itself; Linux needs it mapped
+		snippet.RunArgs = append(snippet.RunArgs, "--add-host="+dockerHost+":host-gateway")
+	}
+
+	switch cc.Cfg.OutputFormat {
+	case config.FormatJSON:
+		return cc.Output(map[string]any{"env_file": file, "pass_through": passThrough, "devcontainer": snippet})
+	}
+	out, err := json.MarshalIndent(snippet, "", "  ")
+	if err != nil {
+		return err
+	}
+	if cc.Cfg.OutputFormat == config.FormatPlain {
+		fmt.Println(string(out))
+		return nil
+	}
+	ui.Success("Wrote %s", file)
+	ui.Info("Merge into .devcontainer/devcontainer.json:")
+	fmt.Println(string(out))
+	if len(passThrough) > 0 {
+		ui.Dim("The key (%s) is taken from the environment docker runs in. Start it with skint exec -p %s, e.g.\n", strings.Join(passThrough, ", "), p.Name)
+		ui.Dim("  skint exec -p %s devcontainer up --workspace-folder .\n", p.Name)
+	}
+	return nil
+}
+
+// dockerEnvFile returns plan's environment as a docker --env-file, in name
+// order, and the variables holding p's API key or auth token, which are
+// written without a value so docker passes them through from its own
+// environment. Base URLs on localhost are pointed at the docker host, and
+// usesHost reports whether any were.
+func dockerEnvFile(p *config.Provider, plan *launcher.Plan) (data string, passThrough []string, usesHost bool, err error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s, written by skint generate docker. Docker reads values literally.\n", p.Name)
+	for _, name := range slices.Sorted(maps.Keys(plan.Env)) {
+		value := plan.Env[name]
+		if value != "" && (value == p.GetAPIKey() || value == p.AuthToken) {
+			passThrough = append(passThrough, name)
+			b.WriteString(name + "\n")
+			continue
+		}
+		if strings.HasSuffix(name, "_BASE_URL") {
+			var local bool
+			if value, local = dockerHostURL(value); local {
+				usesHost = true
+			}
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return "", nil, false, fmt.Errorf("%s has a line break, which docker env files can't hold", name)
+		}
+		b.WriteString(name + "=" + value + "\n")
+	}
+	return b.String(), passThrough, usesHost, nil
+}
+
+// dockerHostURL returns url pointing at the docker host instead of localhost,
+// and whether it was on localhost.
+func dockerHostURL(url string) (string, bool) {
+	for _, local := range []string{"://localhost", "://127.0.0.1", "://[::1]"} {
+		if strings.Contains(url, local) {
+			return strings.Replace(url, local, "://"+dockerHost, 1), true
+		}
+	}
+	return url, false
+}
@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/launcher"
+)
+
+func TestDockerEnvFile(t *testing.T) {
+	p := &config.Provider{Name: "ollama", AuthToken: "ollama"}
+	plan := &launcher.Plan{Env: map[string]string{
+		"ANTHROPIC_BASE_URL":   "http://localhost:11434",
+		"ANTHROPIC_AUTH_TOKEN": "ollama",
+		"ANTHROPIC_API_KEY":    "",
+		"ANTHROPIC_MODEL":      "qwen3 coder",
+	}}
+
+	data, passThrough, usesHost, err := dockerEnvFile(p, plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# ollama, written by skint generate docker. Docker reads values literally.
+ANTHROPIC_API_KEY=
+ANTHROPIC_AUTH_TOKEN
+ANTHROPIC_BASE_URL=http://host.docker.internal:11434
+ANTHROPIC_MODEL=qwen3 coder
+`
+	if data != want {
+		t.Errorf("dockerEnvFile() =\n%s\nwant\n%s", data, want)
+	}
+	if strings.Join(passThrough, ",") != "ANTHROPIC_AUTH_TOKEN" || !usesHost {
+		t.Errorf("passThrough = %v, usesHost = %v", passThrough, usesHost)
+	}
+
+	plan.Env["API_NOTE"] = "two\nlines"
+	if _, _, _, err := dockerEnvFile(p, plan); err == nil {
+		t.Error("expected an error for a multi-line value")
+	}
+}
@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/errcode"
+	"github.com/sammcj/skint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// NewGenerateEnvrcCmd creates the generate envrc command
+func NewGenerateEnvrcCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "envrc <provider>",
+		Short: "Write a direnv .envrc that sets up a provider",
+		Long: `Write a .envrc for direnv that runs 'skint env <provider>' whenever you
+enter the directory, so tools there use the provider. Keys are read from the
+keyring each time rather than written to the file, so the .envrc can be
+committed.
+
+An existing .envrc is kept: its 'skint env' line is switched to the
+provider, or one is added at the end. Run 'direnv allow' afterwards.`,
+		Example: `  skint generate envrc zai
+  skint generate envrc ollama --dir ~/src/app`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeProvider,
+		RunE:              runGenerateEnvrc,
+	}
+	cmd.Flags().String("dir", ".", "directory to write the .envrc in")
+	_ = cmd.MarkFlagDirname("dir")
+	return cmd
+}
+
+func runGenerateEnvrc(cmd *cobra.Command, args []string) error {
+	cc := GetContext(cmd)
+	name := args[0]
+	dir, _ := cmd.Flags().GetString("dir")
+
+	if name != "native" && cc.Cfg.GetProvider(name) == nil {
+		return errcode.New(errcode.ProviderNotFound, "provider %s is not configured", name)
+	}
+
+	file := filepath.Join(dir, ".envrc")
+	existing, err := os.ReadFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	data, updated := envrc(string(existing), name)
+	if err := os.WriteFile(file, []byte(data), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", file, err)
+	}
+
+	switch cc.Cfg.OutputFormat {
+	case config.FormatJSON:
+		return cc.Output(map[string]any{"file": file, "provider": name, "updated": updated})
+	case config.FormatPlain:
+		fmt.Println(file)
+		return nil
+	}
+	if updated {
+		ui.Success("Switched %s to %s", file, name)
+	} else {
+		ui.Success("Wrote %s for %s", file, name)
+	}
+	ui.NextSteps([]string{"Allow it: " + ui.Green("direnv allow "+dir)})
+	return nil
+}
+
+// envrc returns existing with its 'skint env' line switched to provider, and
+// true, or with one added at the end, and false.
+func envrc(existing, provider string) (string, bool) {
+	// Keeps a json or plain format, from the config or the environment, from
+	// breaking eval
+	line := fmt.Sprintf(`eval "$(SKINT_OUTPUT_FORMAT=human skint env %s)"`, provider)
+
+	lines := strings.Split(existing, "\n")
+	for i, l := range lines {
+		if strings.Contains(l, "skint env") && !strings.HasPrefix(strings.TrimSpace(l), "#") {
+			lines[i] = line
+			return strings.Join(lines, "\n"), true
+		}
+	}
+
+	var b strings.Builder
+	if existing != "" {
+		b.WriteString(strings.TrimRight(existing, "\n") + "\n\n")
+	}
+	b.WriteString("# Provider for Claude Code, from skint generate envrc. Keys come from the keyring.\n")
+	b.WriteString(line + "\n")
+	return b.String(), false
+}
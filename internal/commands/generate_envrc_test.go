@@ -0,0 +1,23 @@
+package commands
+
+import "testing"
+
+func TestEnvrc(t *testing.T) {
+	const line = `eval "$(SKINT_OUTPUT_FORMAT=human skint env zai)"`
+
+	got, updated := envrc("", "zai")
+	want := "# Provider for Claude Code, from skint generate envrc. Keys come from the keyring.\n" + line + "\n"
+	if got != want || updated {
+		t.Errorf("envrc(empty) = %q, %v; want %q, false", got, updated, want)
+	}
+
+	got, updated = envrc("use nix\n", "zai")
+	if got != "use nix\n\n"+want || updated {
+		t.Errorf("envrc(other) = %q, %v", got, updated)
+	}
+
+	got, updated = envrc("use nix\n# eval \"$(skint env old)\"\neval \"$(skint env ollama)\"\nlayout go\n", "zai")
+	if got != "use nix\n# eval \"$(skint env old)\"\n"+line+"\nlayout go\n" || !updated {
+		t.Errorf("envrc(switch) = %q, %v", got, updated)
+	}
+}
@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/launcher"
+	"github.com/sammcj/skint/internal/providers"
+)
+
+func TestScriptProvider(t *testing.T) {
+	tests := map[string]string{
+		"skint-zai":           "zai",
+		"skint-zai.fish":      "zai",
+		"skint-my-llm.ps1":    "my-llm",
+		"skint-my-llm.cmd":    "my-llm",
+		"skint-or-gpt-4.1":    "or-gpt-4.1",
+		launcher.ManifestFile: "",
+		"skint":               "",
+	}
+	for file, want := range tests {
+		got, ok := scriptProvider(file)
+		if ok != (want != "") || got != want {
+			t.Errorf("scriptProvider(%q) = %q, %v, want %q", file, got, ok, want)
+		}
+	}
+}
+
+func TestCleanScripts(t *testing.T) {
+	binDir := t.TempDir()
+	cfg := config.NewDefaultConfig()
+	for _, name := range []string{"kept", "gone", "edited"} {
+		prov, err := providers.FromConfig(&config.Provider{Name: name, Type: config.ProviderTypeLocal, BaseURL: "http://localhost:11434"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := launcher.GenerateScript(prov, binDir, nil, nil, false, launcher.ShellBash); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg.Providers = []*config.Provider{{Name: "kept", Type: config.ProviderTypeLocal}}
+	write := func(name string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(binDir, name), []byte("#!/bin/sh\n"), 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("skint-edited")
+	write("skint-untracked")
+	write("other-tool")
+
+	removed, left, err := cleanScripts(cfg, binDir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(removed, []string{"skint-gone"}) || !slices.Equal(left, []string{"skint-edited", "skint-untracked"}) {
+		t.Errorf("cleanScripts = %v, %v; want [skint-gone], [skint-edited skint-untracked]", removed, left)
+	}
+
+	removed, left, err = cleanScripts(cfg, binDir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(removed, []string{"skint-edited", "skint-untracked"}) || len(left) != 0 {
+		t.Errorf("cleanScripts with prune = %v, %v; want [skint-edited skint-untracked], []", removed, left)
+	}
+	for _, name := range []string{"skint-kept", "other-tool"} {
+		if _, err := os.Stat(filepath.Join(binDir, name)); err != nil {
+			t.Errorf("%s was removed: %v", name, err)
+		}
+	}
+	m, err := launcher.LoadManifest(binDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m.Names(); !slices.Equal(got, []string{"skint-kept"}) {
+		t.Errorf("manifest = %v, want [skint-kept]", got)
+	}
+}
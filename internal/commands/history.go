@@ -0,0 +1,180 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/errcode"
+	"github.com/sammcj/skint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// NewHistoryCmd creates the history command
+func NewHistoryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Show recent launches",
+		Long: `Show the providers Claude was launched with, newest first: when, from
+which directory, with which model, and how the session ended.
+
+Every launch from skint use, skint exec and the TUI is recorded in
+history.jsonl in the data directory. The exit code and duration are only
+known when skint waits for Claude, which it does for skint exec and when
+exit_summary or the watchdog is on; otherwise Claude replaces skint's process.`,
+		Example: `  skint history
+  skint history --provider zai --since 7d
+  skint history --here --limit 5
+  skint history --output json`,
+		Args:        cobra.NoArgs,
+		Annotations: map[string]string{annotationSkipSecrets: "true"},
+		RunE:        runHistory,
+	}
+	cmd.Flags().String("provider", "", "only show launches with this provider")
+	cmd.Flags().String("since", "", "only show launches after this long ago (e.g. 12h, 7d) or date (2006-01-02)")
+	cmd.Flags().Bool("here", false, "only show launches from this directory or below")
+	cmd.Flags().IntP("limit", "n", 20, "show at most this many launches (0 for all)")
+	_ = cmd.RegisterFlagCompletionFunc("provider", func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		return providerCompletions(completionConfig(cmd), toComplete), cobra.ShellCompDirectiveNoFileComp
+	})
+	return cmd
+}
+
+// historyFilter selects launches for skint history. Zero fields match
+// everything.
+type historyFilter struct {
+	provider string
+	since    time.Time
+	dir      string
+	limit    int
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	cc := GetContext(cmd)
+
+	var f historyFilter
+	f.provider, _ = cmd.Flags().GetString("provider")
+	f.limit, _ = cmd.Flags().GetInt("limit")
+	if since, _ := cmd.Flags().GetString("since"); since != "" {
+		t, err := parseSince(since, time.Now())
+		if err != nil {
+			return err
+		}
+		f.since = t
+	}
+	if here, _ := cmd.Flags().GetBool("here"); here {
+		dir, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get working directory: %w", err)
+		}
+		f.dir = dir
+	}
+
+	entries, err := config.LoadHistory()
+	if err != nil {
+		return err
+	}
+	shown := filterHistory(entries, f)
+
+	switch cc.Cfg.OutputFormat {
+	case config.FormatJSON:
+		if shown == nil {
+			shown = []config.HistoryEntry{}
+		}
+		return cc.Output(map[string]any{"history": shown})
+	case config.FormatPlain:
+		for _, e := range shown {
+			exit := ""
+			if e.Finished() {
+				exit = strconv.Itoa(*e.ExitCode)
+			}
+			fmt.Printf("%s\t%s\t%s\t%s\t%d\t%s\n", e.Time.Format(time.RFC3339), e.Provider, e.Model, e.Dir, e.Seconds, exit)
+		}
+		return nil
+	}
+
+	if len(shown) == 0 {
+		ui.Info("No launches recorded")
+		return nil
+	}
+	fmt.Println()
+	printHistory(shown, time.Now())
+	fmt.Println()
+	return nil
+}
+
+// parseSince parses --since: a duration before now, which may be in days
+// (e.g. 7d), or a date.
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, errcode.New(errcode.Usage, "invalid --since %q: use a duration such as 12h or 7d, or a date such as 2006-01-02", s)
+}
+
+// filterHistory returns the entries matching f, newest first.
+func filterHistory(entries []config.HistoryEntry, f historyFilter) []config.HistoryEntry {
+	var out []config.HistoryEntry
+	for _, e := range slices.Backward(entries) {
+		if f.limit > 0 && len(out) == f.limit {
+			break
+		}
+		if f.provider != "" && e.Provider != f.provider {
+			continue
+		}
+		if !f.since.IsZero() && e.Time.Before(f.since) {
+			continue
+		}
+		if f.dir != "" && !withinDir(e.Dir, f.dir) {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// withinDir reports whether path is dir or below it.
+func withinDir(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// printHistory prints entries as a table, with the home directory shortened
+// to ~ and unknown durations and exit codes left blank.
+func printHistory(entries []config.HistoryEntry, now time.Time) {
+	home, _ := os.UserHomeDir()
+	rows := make([][]string, 0, len(entries))
+	for _, e := range entries {
+		dir := e.Dir
+		if home != "" && withinDir(dir, home) {
+			dir = "~" + strings.TrimPrefix(dir, home)
+		}
+		duration, exit := "", ""
+		if e.Finished() {
+			duration = e.Duration().String()
+			exit = strconv.Itoa(*e.ExitCode)
+			if *e.ExitCode != 0 {
+				exit = ui.Red(exit)
+			}
+		}
+		provider := e.Provider
+		if e.Command != "" && e.Command != "claude" {
+			provider += " (" + e.Command + ")"
+		}
+		rows = append(rows, []string{config.Ago(e.Time, now), provider, e.Model, dir, duration, exit})
+	}
+	ui.Table([]string{"WHEN", "PROVIDER", "MODEL", "DIRECTORY", "DURATION", "EXIT"}, rows)
+}
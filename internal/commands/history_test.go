@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sammcj/skint/internal/config"
+)
+
+func TestFilterHistory(t *testing.T) {
+	at := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	entries := []config.HistoryEntry{
+		{ID: "1", Time: at, Provider: "zai", Dir: "/work/api"},
+		{ID: "2", Time: at.Add(time.Hour), Provider: "ollama", Dir: "/work/api/cmd"},
+		{ID: "3", Time: at.Add(2 * time.Hour), Provider: "zai", Dir: "/work/apiary"},
+		{ID: "4", Time: at.Add(3 * time.Hour), Provider: "zai"},
+	}
+
+	ids := func(f historyFilter) string {
+		var s string
+		for _, e := range filterHistory(entries, f) {
+			s += e.ID
+		}
+		return s
+	}
+	tests := map[string]struct {
+		filter historyFilter
+		want   string
+	}{
+		"all, newest first": {historyFilter{}, "4321"},
+		"limit":             {historyFilter{limit: 2}, "43"},
+		"provider":          {historyFilter{provider: "zai"}, "431"},
+		"provider, limit":   {historyFilter{provider: "zai", limit: 2}, "43"},
+		"since":             {historyFilter{since: at.Add(time.Hour)}, "432"},
+		"dir and below":     {historyFilter{dir: "/work/api"}, "21"},
+	}
+	for name, tt := range tests {
+		if got := ids(tt.filter); got != tt.want {
+			t.Errorf("%s: got %q, want %q", name, got, tt.want)
+		}
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.Local)
+	tests := map[string]time.Time{
+		"12h":        now.Add(-12 * time.Hour),
+		"7d":         now.AddDate(0, 0, -7),
+		"2026-10-01": time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local),
+	}
+	for in, want := range tests {
+		if got, err := parseSince(in, now); err != nil || !got.Equal(want) {
+			t.Errorf("parseSince(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"yesterday", "-1d", "-2h"} {
+		if _, err := parseSince(in, now); err == nil {
+			t.Errorf("parseSince(%q) should fail", in)
+		}
+	}
+}
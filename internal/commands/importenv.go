@@ -0,0 +1,224 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/errcode"
+	"github.com/sammcj/skint/internal/providers"
+	"github.com/sammcj/skint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// NewConfigImportEnvCmd creates the config import-env command
+func NewConfigImportEnvCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import-env <file>",
+		Short: "Create a provider from a .env file",
+		Long: `Create a provider from a dotenv file of the variables Claude Code reads.
+
+ANTHROPIC_BASE_URL, ANTHROPIC_AUTH_TOKEN or ANTHROPIC_API_KEY, ANTHROPIC_MODEL
+and the tier variables (ANTHROPIC_DEFAULT_HAIKU_MODEL and so on) make an
+Anthropic API provider, or an OpenRouter one when the base URL is
+OpenRouter's. Without them, OPENAI_BASE_URL, OPENAI_API_KEY and OPENAI_MODEL
+make an OpenAI API provider. Other variables are ignored.
+
+The key goes into the keyring (or encrypted file store), not the config, so
+the .env file can be deleted afterwards. The provider is named with --name,
+or after the file: zai.env and .env.zai both make "zai".`,
+		Example: `  skint config import-env .env.zai
+  skint config import-env ~/keys/deepseek.env --name deepseek --force`,
+		Args: cobra.ExactArgs(1),
+		RunE: runConfigImportEnv,
+	}
+	cmd.Flags().String("name", "", "provider name (default from the file name)")
+	cmd.Flags().Bool("force", false, "replace a provider with the same name")
+	return cmd
+}
+
+func runConfigImportEnv(cmd *cobra.Command, args []string) error {
+	cc := GetContext(cmd)
+	file := args[0]
+	name, _ := cmd.Flags().GetString("name")
+	force, _ := cmd.Flags().GetBool("force")
+
+	if name == "" {
+		name = envFileProviderName(file)
+		if name == "" {
+			return errcode.New(errcode.Usage, "can't name a provider after %s. Use --name", file)
+		}
+	}
+	if !isProviderName(name) {
+		return errcode.New(errcode.Usage, "invalid provider name %q: use lower case letters, digits, hyphens and underscores", name)
+	}
+	if cc.Cfg.GetProvider(name) != nil && !force {
+		return fmt.Errorf("provider %s already exists. Use --force to replace it", name)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	env, err := config.ParseDotenv(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	p, key, ignored, err := providerFromEnv(name, env)
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+
+	cc.Cfg.RemoveProvider(name)
+	keys := map[string]string{}
+	if key != "" {
+		keyName := name
+		if p.Type == config.ProviderTypeOpenRouter {
+			keyName = "openrouter"
+		}
+		keys[keyName] = key
+	}
+	if _, err := cc.importProviders([]*config.Provider{p}, keys); err != nil {
+		return err
+	}
+	if err := cc.SaveConfig(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	switch cc.Cfg.OutputFormat {
+	case config.FormatJSON:
+		return cc.Output(map[string]any{
+			"name":     p.Name,
+			"type":     p.Type,
+			"api_type": p.APIType,
+			"base_url": p.BaseURL,
+			"model":    p.Model,
+			"key":      p.KeyBackend(),
+			"ignored":  ignored,
+		})
+	case config.FormatPlain:
+		fmt.Println(p.Name)
+		return nil
+	}
+	ui.Success("Added provider %s from %s", p.Name, file)
+	if len(ignored) > 0 {
+		ui.Dim("Ignored %s\n", strings.Join(ignored, ", "))
+	}
+	if key != "" {
+		ui.Info("The key is stored in the %s store; %s can be deleted", p.KeyBackend(), file)
+	} else if p.NeedsAPIKey() {
+		ui.Warning("%s has no API key. Set one with: skint config %s", file, p.Name)
+	}
+	return nil
+}
+
+// providerFromEnv makes the provider called name from a .env file's
+// variables, returning it with its API key and the variables it ignored,
+// sorted.
+func providerFromEnv(name string, env map[string]string) (*config.Provider, string, []string, error) {
+	p := &config.Provider{
+		Name:        name,
+		DisplayName: name,
+		Description: "Imported from a .env file",
+	}
+	used := map[string]bool{}
+	get := func(k string) string {
+		if v, ok := env[k]; ok {
+			used[k] = true
+			return v
+		}
+		return ""
+	}
+
+	var key string
+	anthropic := slices.ContainsFunc([]string{"ANTHROPIC_BASE_URL", "ANTHROPIC_AUTH_TOKEN", "ANTHROPIC_API_KEY"}, func(k string) bool { return env[k] != "" })
+	switch {
+	case anthropic:
+		p.Type = config.ProviderTypeCustom
+		p.APIType = config.APITypeAnthropic
+		p.BaseURL = strings.TrimRight(get("ANTHROPIC_BASE_URL"), "/")
+		if key = get("ANTHROPIC_AUTH_TOKEN"); key == "" {
+			if key = get("ANTHROPIC_API_KEY"); key != "" {
+				p.KeyEnvVar = "ANTHROPIC_API_KEY"
+			}
+		}
+		if p.BaseURL == "" {
+			p.BaseURL = "https://api.anthropic.com"
+		}
+		if strings.Contains(p.BaseURL, "openrouter.ai") {
+			p.Type = config.ProviderTypeOpenRouter
+			p.APIType = ""
+			p.KeyEnvVar = ""
+			p.BaseURL = config.OpenRouterBaseURL
+		}
+		p.Model = get("ANTHROPIC_MODEL")
+		for tier, envVar := range providers.TierEnvVars {
+			// OpenRouter providers use the model for every tier anyway
+			if v := get(envVar); v != "" && (v != p.Model || p.Type != config.ProviderTypeOpenRouter) {
+				if p.ModelMappings == nil {
+					p.ModelMappings = map[string]string{}
+				}
+				p.ModelMappings[tier] = v
+			}
+		}
+	case env["OPENAI_BASE_URL"] != "" || env["OPENAI_API_KEY"] != "":
+		p.Type = config.ProviderTypeCustom
+		p.APIType = config.APITypeOpenAI
+		p.BaseURL = strings.TrimRight(get("OPENAI_BASE_URL"), "/")
+		if p.BaseURL == "" {
+			p.BaseURL = "https://api.openai.com/v1"
+		}
+		key = get("OPENAI_API_KEY")
+		p.Model = get("OPENAI_MODEL")
+	default:
+		return nil, "", nil, fmt.Errorf("no ANTHROPIC_BASE_URL, ANTHROPIC_AUTH_TOKEN, ANTHROPIC_API_KEY, OPENAI_BASE_URL or OPENAI_API_KEY")
+	}
+	if err := p.Validate(); err != nil {
+		return nil, "", nil, err
+	}
+
+	var ignored []string
+	for k := range env {
+		if !used[k] {
+			ignored = append(ignored, k)
+		}
+	}
+	slices.Sort(ignored)
+	return p, key, ignored, nil
+}
+
+// envFileProviderName names a provider after a .env file: "zai" for zai.env
+// and .env.zai, or "" for a plain .env.
+func envFileProviderName(file string) string {
+	base := filepath.Base(file)
+	switch {
+	case strings.HasPrefix(base, ".env."):
+		base = strings.TrimPrefix(base, ".env.")
+	case strings.HasSuffix(base, ".env"):
+		base = strings.TrimSuffix(base, ".env")
+	default:
+		return ""
+	}
+	base = strings.ToLower(base)
+	if !isProviderName(base) {
+		return ""
+	}
+	return base
+}
+
+// isProviderName reports whether name can name a provider: lower case
+// letters, digits, hyphens and underscores, as the TUI accepts.
+func isProviderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sammcj/skint/internal/config"
+)
+
+func TestProviderFromEnv(t *testing.T) {
+	t.Run("anthropic", func(t *testing.T) {
+		p, key, ignored, err := providerFromEnv("zai", map[string]string{
+			"ANTHROPIC_BASE_URL":             "https://api.z.ai/api/anthropic/",
+			"ANTHROPIC_AUTH_TOKEN":           "sk-zai",
+			"ANTHROPIC_MODEL":                "glm-5",
+			"ANTHROPIC_DEFAULT_HAIKU_MODEL":  "glm-4.5-air",
+			"ANTHROPIC_DEFAULT_SONNET_MODEL": "glm-5",
+			"API_TIMEOUT_MS":                 "3000000",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Type != config.ProviderTypeCustom || p.APIType != config.APITypeAnthropic || p.BaseURL != "https://api.z.ai/api/anthropic" || p.Model != "glm-5" {
+			t.Errorf("provider = %+v", p)
+		}
+		if p.ModelMappings["haiku"] != "glm-4.5-air" || p.ModelMappings["sonnet"] != "glm-5" || len(p.ModelMappings) != 2 {
+			t.Errorf("ModelMappings = %v", p.ModelMappings)
+		}
+		if key != "sk-zai" || p.KeyEnvVar != "" {
+			t.Errorf("key = %q, KeyEnvVar = %q", key, p.KeyEnvVar)
+		}
+		if strings.Join(ignored, ",") != "API_TIMEOUT_MS" {
+			t.Errorf("ignored = %v", ignored)
+		}
+	})
+
+	t.Run("anthropic api key", func(t *testing.T) {
+		p, key, _, err := providerFromEnv("work", map[string]string{"ANTHROPIC_API_KEY": "sk-ant"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.BaseURL != "https://api.anthropic.com" || p.KeyEnvVar != "ANTHROPIC_API_KEY" || key != "sk-ant" {
+			t.Errorf("provider = %+v, key %q", p, key)
+		}
+	})
+
+	t.Run("openrouter", func(t *testing.T) {
+		p, key, _, err := providerFromEnv("or-kimi", map[string]string{
+			"ANTHROPIC_BASE_URL":            "https://openrouter.ai/api",
+			"ANTHROPIC_AUTH_TOKEN":          "sk-or",
+			"ANTHROPIC_MODEL":               "moonshotai/kimi-k2",
+			"ANTHROPIC_DEFAULT_HAIKU_MODEL": "moonshotai/kimi-k2",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Type != config.ProviderTypeOpenRouter || p.APIType != "" || len(p.ModelMappings) != 0 || key != "sk-or" {
+			t.Errorf("provider = %+v, key %q", p, key)
+		}
+	})
+
+	t.Run("openai", func(t *testing.T) {
+		p, key, _, err := providerFromEnv("deepseek", map[string]string{
+			"OPENAI_BASE_URL": "https://api.deepseek.com/v1",
+			"OPENAI_API_KEY":  "sk-ds",
+			"OPENAI_MODEL":    "deepseek-chat",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.APIType != config.APITypeOpenAI || p.BaseURL != "https://api.deepseek.com/v1" || p.Model != "deepseek-chat" || key != "sk-ds" {
+			t.Errorf("provider = %+v, key %q", p, key)
+		}
+	})
+
+	t.Run("nothing usable", func(t *testing.T) {
+		if _, _, _, err := providerFromEnv("x", map[string]string{"ANTHROPIC_MODEL": "m"}); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestEnvFileProviderName(t *testing.T) {
+	tests := map[string]string{
+		"zai.env":           "zai",
+		"/keys/.env.zai":    "zai",
+		"dir/DeepSeek.env":  "deepseek",
+		".env":              "",
+		"secrets.txt":       "",
+		"my keys.env":       "",
+		".env.local_ollama": "local_ollama",
+	}
+	for file, want := range tests {
+		if got := envFileProviderName(file); got != want {
+			t.Errorf("envFileProviderName(%q) = %q, want %q", file, got, want)
+		}
+	}
+}
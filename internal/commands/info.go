@@ -2,21 +2,41 @@ package commands
 
 import (
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 
 	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/errcode"
+	"github.com/sammcj/skint/internal/launcher"
+	"github.com/sammcj/skint/internal/providers"
 	"github.com/sammcj/skint/internal/ui"
 	"github.com/spf13/cobra"
 )
 
 // NewInfoCmd creates the info command
 func NewInfoCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "info <provider>",
 		Short: "Show provider details",
-		Long:  "Display detailed information about a specific provider.",
-		Args:  cobra.ExactArgs(1),
-		RunE:  runInfo,
+		Long: `Display detailed information about a specific provider.
+
+--env adds the exact variables 'skint use' would set for it, and the
+inherited ones it would remove, with the API key masked unless --reveal is
+given. --copy puts them on the clipboard as export statements to paste into
+a shell (through the terminal, OSC 52, when there is no clipboard tool).`,
+		Example: `  skint info zai
+  skint info zai --env
+  skint info zai --copy --reveal`,
+		Args: cobra.ExactArgs(1),
+		RunE: runInfo,
+
+		ValidArgsFunction: completeProvider,
 	}
+	cmd.Flags().Bool("env", false, "show the environment variables set at launch")
+	cmd.Flags().Bool("reveal", false, "show the API key unmasked")
+	cmd.Flags().Bool("copy", false, "copy the variables to the clipboard as export statements (implies --env)")
+	return cmd
 }
 
 func runInfo(cmd *cobra.Command, args []string) error {
@@ -25,7 +45,28 @@ func runInfo(cmd *cobra.Command, args []string) error {
 
 	p := cc.Cfg.GetProvider(name)
 	if p == nil {
-		return fmt.Errorf("provider not found: %s", name)
+		return errcode.New(errcode.ProviderNotFound, "provider not found: %s", name)
+	}
+
+	showEnv, _ := cmd.Flags().GetBool("env")
+	reveal, _ := cmd.Flags().GetBool("reveal")
+	copyEnv, _ := cmd.Flags().GetBool("copy")
+	var plan *launcher.Plan
+	if showEnv || copyEnv {
+		var err error
+		if plan, err = cc.launchPlan(name); err != nil {
+			return err
+		}
+		if !reveal {
+			plan.Env = maskedEnv(p, plan.Env)
+		}
+	}
+	var viaTerminal bool
+	if copyEnv {
+		var err error
+		if viaTerminal, err = ui.CopyToClipboard(exportLines(plan)); err != nil {
+			return fmt.Errorf("failed to copy: %w", err)
+		}
 	}
 
 	// JSON output
@@ -35,22 +76,31 @@ func runInfo(cmd *cobra.Command, args []string) error {
 			configured = false
 		}
 
-		return cc.Output(map[string]any{
+		result := map[string]any{
 			"name":           p.Name,
 			"display_name":   p.DisplayName,
 			"description":    p.Description,
 			"type":           p.Type,
 			"base_url":       p.BaseURL,
 			"api_key_ref":    p.APIKeyRef,
-			"default_model":  p.DefaultModel,
-			"model":          p.Model,
+			"model":          p.EffectiveModel(),
 			"model_mappings": p.ModelMappings,
+			"target":         p.TargetName(),
 			"configured":     configured,
-		})
+		}
+		if plan != nil {
+			result["env"] = plan.Env
+			result["removed_env"] = append([]string{}, plan.Removed...)
+		}
+		return cc.Output(result)
 	}
 
 	// Plain output
 	if cc.Cfg.OutputFormat == config.FormatPlain {
+		if plan != nil {
+			fmt.Print(exportLines(plan))
+			return nil
+		}
 		fmt.Printf("Name: %s\n", p.Name)
 		fmt.Printf("Type: %s\n", p.Type)
 		fmt.Printf("BaseURL: %s\n", p.BaseURL)
@@ -72,6 +122,10 @@ func runInfo(cmd *cobra.Command, args []string) error {
 
 	ui.Log("Type:         %s", p.Type)
 
+	if cc.ConfigMgr.IsSystemProvider(p.Name) {
+		ui.Log("Source:       %s", ui.DimString("system config"))
+	}
+
 	if p.BaseURL != "" {
 		ui.Log("Base URL:     %s", p.BaseURL)
 	}
@@ -81,6 +135,12 @@ func runInfo(cmd *cobra.Command, args []string) error {
 		ui.Log("Model:        %s", model)
 	}
 
+	if target := p.TargetName(); target == config.TargetCustom {
+		ui.Log("Launches:     %s", p.TargetCommand)
+	} else if target != config.TargetClaude {
+		ui.Log("Launches:     %s", target)
+	}
+
 	if p.NeedsAPIKey() {
 		if p.GetAPIKey() != "" {
 			ui.Log("API Key:      %s", ui.Green("configured"))
@@ -97,13 +157,72 @@ func runInfo(cmd *cobra.Command, args []string) error {
 	}
 
 	if len(p.ModelMappings) > 0 {
-		ui.Log("Model Mappings:")
+		ui.Log("Tier Overrides:")
 		for tier, model := range p.ModelMappings {
 			ui.Dim("  %s: %s\n", tier, model)
 		}
 	}
 
+	if plan != nil {
+		ui.Log("Environment:")
+		for _, line := range strings.Split(strings.TrimSuffix(exportLines(plan), "\n"), "\n") {
+			ui.Dim("  %s\n", line)
+		}
+	}
+
+	if copyEnv {
+		fmt.Println()
+		if viaTerminal {
+			ui.Success("Sent the export statements to the terminal's clipboard")
+		} else {
+			ui.Success("Copied the export statements to the clipboard")
+		}
+		if !reveal && p.GetAPIKey()+p.AuthToken != "" {
+			ui.Info("Keys are masked: add --reveal to copy them")
+		}
+	}
+
 	fmt.Println()
 
 	return nil
 }
+
+// launchPlan resolves name as skint use does and returns what launching it
+// would do.
+func (cc *CmdContext) launchPlan(name string) (*launcher.Plan, error) {
+	p, err := cc.ResolveProvider(name)
+	if err != nil {
+		return nil, err
+	}
+	provider, err := providers.FromConfig(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create provider %s: %w", name, err)
+	}
+	presetEnv, err := cc.PresetEnv(p)
+	if err != nil {
+		return nil, err
+	}
+	l, err := launcher.New(cc.Cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create launcher: %w", err)
+	}
+	l.SetExtraEnv(presetEnv)
+	mcp, err := p.MCPArgs()
+	if err != nil {
+		return nil, err
+	}
+	return l.Plan(provider, append(cc.Cfg.LaunchArgs(p), mcp...))
+}
+
+// exportLines returns the shell statements reproducing plan's environment:
+// unsets for the variables it removes, then exports in name order.
+func exportLines(plan *launcher.Plan) string {
+	var b strings.Builder
+	for _, name := range plan.Removed {
+		b.WriteString(exportStatement(name, "") + "\n")
+	}
+	for _, name := range slices.Sorted(maps.Keys(plan.Env)) {
+		b.WriteString(exportStatement(name, plan.Env[name]) + "\n")
+	}
+	return b.String()
+}
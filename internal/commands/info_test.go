@@ -0,0 +1,28 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/sammcj/skint/internal/launcher"
+)
+
+func TestExportLines(t *testing.T) {
+	plan := &launcher.Plan{
+		Env: map[string]string{
+			"ANTHROPIC_MODEL":      "glm-4.7",
+			"ANTHROPIC_BASE_URL":   "https://api.z.ai/api/anthropic",
+			"ANTHROPIC_AUTH_TOKEN": "it's-secret",
+			"ANTHROPIC_API_KEY":    "",
+		},
+		Removed: []string{"OPENAI_API_KEY"},
+	}
+	want := `unset OPENAI_API_KEY
+unset ANTHROPIC_API_KEY
+export ANTHROPIC_AUTH_TOKEN='it'"'"'s-secret'
+export ANTHROPIC_BASE_URL='https://api.z.ai/api/anthropic'
+export ANTHROPIC_MODEL='glm-4.7'
+`
+	if got := exportLines(plan); got != want {
+		t.Errorf("exportLines() =\n%s\nwant\n%s", got, want)
+	}
+}
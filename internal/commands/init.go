@@ -0,0 +1,218 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// claudeMDMarker delimits the section `skint init` adds to CLAUDE.md, so
+// re-running it updates the section instead of appending another.
+const claudeMDMarker = "<!-- skint -->"
+
+// NewInitCmd creates the init command
+func NewInitCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "init [provider]",
+		Short: "Set up skint for the current project",
+		Long: `Set up skint for the project in the current directory.
+
+This writes .skint.yaml naming the provider (and optionally model) to use
+here, adds .skint.local.yaml to .gitignore for personal overrides, and checks
+that the provider is reachable. Inside the project, 'skint exec' and
+'skint env' use the project's provider instead of the default, and
+'skint use' applies the project's model.
+
+The provider defaults to the current default provider.`,
+		Example: `  skint init zai
+  skint init ollama --model qwen3-coder
+  skint init zai --claude-md`,
+		Args:              cobra.MaximumNArgs(1),
+		RunE:              runInit,
+		ValidArgsFunction: completeProvider,
+	}
+
+	cmd.Flags().String("model", "", "model to use for this project")
+	cmd.Flags().Bool("claude-md", false, "add a note on how to launch Claude to CLAUDE.md")
+	cmd.Flags().Bool("no-gitignore", false, "do not add .skint.local.yaml to .gitignore")
+	cmd.Flags().Bool("no-verify", false, "skip the provider connectivity check")
+	cmd.Flags().Bool("force", false, "overwrite an existing .skint.yaml")
+
+	return cmd
+}
+
+func runInit(cmd *cobra.Command, args []string) error {
+	cc := GetContext(cmd)
+	model, _ := cmd.Flags().GetString("model")
+	claudeMD, _ := cmd.Flags().GetBool("claude-md")
+	noGitignore, _ := cmd.Flags().GetBool("no-gitignore")
+	noVerify, _ := cmd.Flags().GetBool("no-verify")
+	force, _ := cmd.Flags().GetBool("force")
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	projectFile := filepath.Join(dir, config.ProjectFile)
+	if _, err := os.Lstat(projectFile); err == nil && !force {
+		return fmt.Errorf("%s already exists. Use --force to overwrite", config.ProjectFile)
+	}
+
+	// Work out the provider
+	providerName := cc.Cfg.DefaultProvider
+	if len(args) > 0 {
+		providerName = args[0]
+	} else if !cc.NoInput && !cc.YesMode && cc.Cfg.OutputFormat == config.FormatHuman {
+		providerName = ui.Prompt("Provider for this project", providerName)
+	}
+	if providerName == "" {
+		return fmt.Errorf("no provider given and no default provider set. Run 'skint init <provider>'")
+	}
+	var resolved *config.Provider
+	if providerName != "native" {
+		if resolved, err = cc.ResolveProvider(providerName); err != nil {
+			return err
+		}
+	}
+
+	pc := &config.ProjectConfig{Provider: providerName, Model: model}
+	if err := pc.Save(dir); err != nil {
+		return err
+	}
+
+	gitignoreUpdated := false
+	if !noGitignore {
+		gitignoreUpdated, err = ensureLineInFile(filepath.Join(dir, ".gitignore"), config.ProjectLocalFile)
+		if err != nil {
+			return fmt.Errorf("failed to update .gitignore: %w", err)
+		}
+	}
+
+	if claudeMD {
+		if err := writeClaudeMDHint(filepath.Join(dir, "CLAUDE.md"), pc); err != nil {
+			return fmt.Errorf("failed to update CLAUDE.md: %w", err)
+		}
+	}
+
+	// Verify the provider; a failure is reported but the project is still set up
+	var result *testResult
+	if !noVerify && resolved != nil && resolved.BaseURL != "" {
+		r := testProvider(resolved, cc.Cfg.NetworkTimeout())
+		result = &r
+	}
+
+	// JSON output
+	if cc.Cfg.OutputFormat == config.FormatJSON {
+		out := map[string]any{
+			"project_file": projectFile,
+			"provider":     providerName,
+			"model":        model,
+			"gitignore":    gitignoreUpdated,
+			"claude_md":    claudeMD,
+		}
+		if result != nil {
+			out["reachable"] = result.reachable
+			out["error"] = result.errMsg
+		}
+		return cc.Output(out)
+	}
+
+	// Plain output
+	if cc.Cfg.OutputFormat == config.FormatPlain {
+		fmt.Println(projectFile)
+		return nil
+	}
+
+	// Human-readable output
+	ui.Success("Wrote %s (provider: %s)", config.ProjectFile, ui.Yellow(providerName))
+	if gitignoreUpdated {
+		ui.Success("Added %s to .gitignore", config.ProjectLocalFile)
+	}
+	if claudeMD {
+		ui.Success("Updated CLAUDE.md")
+	}
+	if result != nil {
+		if result.reachable {
+			ui.Success("%s is reachable", providerName)
+		} else {
+			ui.Warning("%s is not reachable: %s", providerName, result.errMsg)
+		}
+	}
+	ui.NextSteps([]string{
+		"Launch Claude: " + ui.Green("skint use "+providerName),
+		"Personal overrides: " + ui.Green(config.ProjectLocalFile),
+	})
+
+	return nil
+}
+
+// ensureLineInFile appends line to the file at path unless it is already
+// present, creating the file if needed. Returns true if the file was changed.
+func ensureLineInFile(path, line string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+
+	for _, existing := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(existing) == line {
+			return false, nil
+		}
+	}
+
+	content := string(data)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	content += line + "\n"
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// writeClaudeMDHint adds (or replaces) a marked section in CLAUDE.md telling
+// Claude which provider the project is set up with.
+func writeClaudeMDHint(path string, pc *config.ProjectConfig) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	var section strings.Builder
+	section.WriteString(claudeMDMarker + "\n")
+	section.WriteString("## Skint\n\n")
+	fmt.Fprintf(&section, "This project is set up for skint with the `%s` provider", pc.Provider)
+	if pc.Model != "" {
+		fmt.Fprintf(&section, " and model `%s`", pc.Model)
+	}
+	section.WriteString(" (see `.skint.yaml`).\n")
+	section.WriteString("Launch Claude with `skint use " + pc.Provider + "`, and run other tools with the provider's environment via `skint exec <command>`.\n")
+	section.WriteString(claudeMDMarker + "\n")
+
+	content := string(data)
+	if start := strings.Index(content, claudeMDMarker); start >= 0 {
+		rest := content[start+len(claudeMDMarker):]
+		if end := strings.Index(rest, claudeMDMarker); end >= 0 {
+			after := strings.TrimPrefix(rest[end+len(claudeMDMarker):], "\n")
+			content = content[:start] + section.String() + after
+			return os.WriteFile(path, []byte(content), 0644)
+		}
+	}
+
+	if content != "" {
+		if !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		content += "\n"
+	}
+	content += section.String()
+	return os.WriteFile(path, []byte(content), 0644)
+}
@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sammcj/skint/internal/config"
+)
+
+func TestEnsureLineInFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".gitignore")
+	if err := os.WriteFile(path, []byte("node_modules"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	changed, err := ensureLineInFile(path, config.ProjectLocalFile)
+	if err != nil || !changed {
+		t.Fatalf("first call: changed=%v err=%v", changed, err)
+	}
+	changed, err = ensureLineInFile(path, config.ProjectLocalFile)
+	if err != nil || changed {
+		t.Fatalf("second call: changed=%v err=%v", changed, err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if got, want := string(data), "node_modules\n"+config.ProjectLocalFile+"\n"; got != want {
+		t.Errorf("content: got %q, want %q", got, want)
+	}
+}
+
+func TestWriteClaudeMDHint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "CLAUDE.md")
+	if err := os.WriteFile(path, []byte("# Project\n\nExisting notes.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writeClaudeMDHint(path, &config.ProjectConfig{Provider: "zai"}); err != nil {
+		t.Fatalf("first write: %v", err)
+	}
+	if err := writeClaudeMDHint(path, &config.ProjectConfig{Provider: "ollama", Model: "qwen3"}); err != nil {
+		t.Fatalf("second write: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	content := string(data)
+	if !strings.HasPrefix(content, "# Project\n\nExisting notes.\n") {
+		t.Errorf("existing content not preserved:\n%s", content)
+	}
+	if n := strings.Count(content, "## Skint"); n != 1 {
+		t.Errorf("expected one skint section, got %d:\n%s", n, content)
+	}
+	if strings.Contains(content, "`zai`") || !strings.Contains(content, "`ollama` provider and model `qwen3`") {
+		t.Errorf("section not replaced:\n%s", content)
+	}
+}
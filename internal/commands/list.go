@@ -2,107 +2,248 @@ package commands
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/errcode"
 	"github.com/sammcj/skint/internal/ui"
 	"github.com/spf13/cobra"
 )
 
 // NewListCmd creates the list command
 func NewListCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:     "list",
 		Aliases: []string{"ls"},
 		Short:   "List configured providers",
-		Long:    "Display a list of all configured LLM providers.",
-		RunE:    runList,
+		Long: `Display a list of all configured LLM providers. Providers listed in
+provider_order come first, in that order.
+
+--configured shows only providers ready to launch (--configured=false the
+rest), --type only those of a type, and --tag only those with every tag
+given (tags are set with a provider's tags setting).
+
+With --output json each provider has the same fields whether or not they
+are set, so scripts and other frontends can rely on them.`,
+		Example: `  skint list
+  skint list --configured --type local
+  skint list --tag work --output json`,
+		Args: cobra.NoArgs,
+		RunE: runList,
 	}
+	cmd.Flags().Bool("configured", false, "only show providers with an API key, if they need one")
+	cmd.Flags().String("type", "", "only show providers of this type: "+strings.Join(config.ProviderTypes, ", "))
+	cmd.Flags().StringArray("tag", nil, "only show providers with this tag (repeatable)")
+	_ = cmd.RegisterFlagCompletionFunc("type", cobra.FixedCompletions(config.ProviderTypes, cobra.ShellCompDirectiveNoFileComp))
+	_ = cmd.RegisterFlagCompletionFunc("tag", func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		var out []cobra.Completion
+		if cfg := completionConfig(cmd); cfg != nil {
+			for _, p := range cfg.Providers {
+				for _, tag := range p.Tags {
+					if strings.HasPrefix(tag, toComplete) && !slices.Contains(out, tag) {
+						out = append(out, tag)
+					}
+				}
+			}
+		}
+		slices.Sort(out)
+		return out, cobra.ShellCompDirectiveNoFileComp
+	})
+	return cmd
 }
 
-func runList(cmd *cobra.Command, args []string) error {
-	cc := GetContext(cmd)
+// listFilter selects providers for skint list. Zero fields match everything.
+type listFilter struct {
+	configured *bool
+	typ        string
+	tags       []string
+}
 
-	if len(cc.Cfg.Providers) == 0 {
-		if cc.Cfg.OutputFormat == config.FormatJSON {
-			fmt.Println(`{"providers":[]}`)
-			return nil
+// match reports whether p passes the filter.
+func (f listFilter) match(p *config.Provider) bool {
+	if f.configured != nil && p.IsConfigured() != *f.configured {
+		return false
+	}
+	if f.typ != "" && p.Type != f.typ {
+		return false
+	}
+	for _, tag := range f.tags {
+		if !slices.Contains(p.Tags, tag) {
+			return false
 		}
-		ui.Warning("No providers configured")
-		ui.NextSteps([]string{
-			"Configure a provider: " + ui.Green("skint config"),
-		})
-		return nil
 	}
+	return true
+}
 
-	// JSON output
-	if cc.Cfg.OutputFormat == config.FormatJSON {
-		type providerJSON struct {
-			Name        string `json:"name"`
-			DisplayName string `json:"display_name"`
-			Type        string `json:"type"`
-			BaseURL     string `json:"base_url,omitempty"`
-			Model       string `json:"model,omitempty"`
-			Configured  bool   `json:"configured"`
-		}
+// providerJSON is a provider in skint list's JSON output. Every field is
+// always present.
+type providerJSON struct {
+	Name        string     `json:"name"`
+	DisplayName string     `json:"display_name"`
+	Description string     `json:"description"`
+	Type        string     `json:"type"`
+	BaseURL     string     `json:"base_url"`
+	Model       string     `json:"model"`
+	Tags        []string   `json:"tags"`
+	Default     bool       `json:"default"`
+	Configured  bool       `json:"configured"`
+	NeedsKey    bool       `json:"needs_key"`
+	KeyStorage  string     `json:"key_storage"`
+	Source      string     `json:"source"`
+	LastUsed    *time.Time `json:"last_used"`
+}
 
-		var result []providerJSON
-		for _, p := range cc.Cfg.Providers {
-			configured := true
-			if p.NeedsAPIKey() && p.GetAPIKey() == "" {
-				configured = false
-			}
+func runList(cmd *cobra.Command, args []string) error {
+	cc := GetContext(cmd)
 
-			model := p.EffectiveModel()
+	var f listFilter
+	if cmd.Flags().Changed("configured") {
+		configured, _ := cmd.Flags().GetBool("configured")
+		f.configured = &configured
+	}
+	f.typ, _ = cmd.Flags().GetString("type")
+	if f.typ != "" && !slices.Contains(config.ProviderTypes, f.typ) {
+		return errcode.New(errcode.Usage, "unknown provider type %q: use %s", f.typ, strings.Join(config.ProviderTypes, ", "))
+	}
+	f.tags, _ = cmd.Flags().GetStringArray("tag")
 
-			result = append(result, providerJSON{
-				Name:        p.Name,
-				DisplayName: p.DisplayName,
-				Type:        p.Type,
-				BaseURL:     p.BaseURL,
-				Model:       model,
-				Configured:  configured,
-			})
+	var providers []*config.Provider
+	for _, p := range cc.Cfg.OrderedProviders() {
+		if f.match(p) {
+			providers = append(providers, p)
 		}
+	}
 
+	usage, err := config.LoadUsage()
+	if err != nil && cc.Verbose {
+		ui.Warning("Failed to load provider usage: %v", err)
+	}
+
+	// JSON output
+	if cc.Cfg.OutputFormat == config.FormatJSON {
+		result := make([]providerJSON, 0, len(providers))
+		for _, p := range providers {
+			result = append(result, cc.providerJSON(p, usage))
+		}
 		return cc.Output(map[string]any{"providers": result})
 	}
 
 	// Plain output
 	if cc.Cfg.OutputFormat == config.FormatPlain {
-		for _, p := range cc.Cfg.Providers {
+		for _, p := range providers {
 			fmt.Println(p.Name)
 		}
 		return nil
 	}
 
+	// Table output
+	if cc.Cfg.OutputFormat == config.FormatTable {
+		printProviderTable(os.Stdout, providers, usage, cc.DefaultProviderName(), time.Now())
+		return nil
+	}
+
 	// Human-readable output
-	ui.Log("\n%s (%d):\n", ui.Bold("Available Providers"), len(cc.Cfg.Providers))
+	if len(cc.Cfg.Providers) == 0 {
+		ui.Warning("No providers configured")
+		ui.NextSteps([]string{
+			"Configure a provider: " + ui.Green("skint config"),
+		})
+		return nil
+	}
+	if len(providers) == 0 {
+		ui.Info("No providers match")
+		return nil
+	}
 
-	for _, p := range cc.Cfg.Providers {
-		// Check if configured
-		configured := true
-		if p.NeedsAPIKey() && p.GetAPIKey() == "" {
-			configured = false
-		}
+	count := fmt.Sprint(len(providers))
+	if len(providers) < len(cc.Cfg.Providers) {
+		count = fmt.Sprintf("%d of %d", len(providers), len(cc.Cfg.Providers))
+	}
+	ui.Log("\n%s (%s):\n", ui.Bold("Available Providers"), count)
+	printProviderTable(os.Stderr, providers, usage, cc.DefaultProviderName(), time.Now())
+	ui.Log("")
+	ui.Log("Run: %s", ui.Green("skint use <name>"))
 
-		ui.ListItem(configured, "%s", ui.Yellow(p.Name))
+	return nil
+}
 
-		if p.DisplayName != "" && p.DisplayName != p.Name {
-			ui.Dim("          %s\n", p.DisplayName)
-		}
+// providerJSON describes p for skint list's JSON output.
+func (cc *CmdContext) providerJSON(p *config.Provider, usage config.Usage) providerJSON {
+	keyStorage := p.KeyBackend()
+	if keyStorage == "" {
+		keyStorage = "none"
+	}
+	source := "user"
+	if cc.ConfigMgr != nil && cc.ConfigMgr.IsSystemProvider(p.Name) {
+		source = "system"
+	}
+	tags := p.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	var lastUsed *time.Time
+	if t, ok := usage[p.Name]; ok {
+		lastUsed = &t
+	}
+	return providerJSON{
+		Name:        p.Name,
+		DisplayName: p.DisplayName,
+		Description: p.Description,
+		Type:        p.Type,
+		BaseURL:     p.BaseURL,
+		Model:       p.EffectiveModel(),
+		Tags:        tags,
+		Default:     p.Name == cc.DefaultProviderName(),
+		Configured:  p.IsConfigured(),
+		NeedsKey:    p.NeedsAPIKey(),
+		KeyStorage:  keyStorage,
+		Source:      source,
+		LastUsed:    lastUsed,
+	}
+}
 
-		if p.Description != "" {
-			ui.Dim("          %s\n", p.Description)
-		}
+// printProviderTable prints providers as a table to w, marking the default
+// with a *. The tags column is only shown when a provider has tags.
+func printProviderTable(w io.Writer, providers []*config.Provider, usage config.Usage, defaultName string, now time.Time) {
+	headers := []string{"NAME", "TYPE", "MODEL", "KEY", "LAST USED"}
+	withTags := slices.ContainsFunc(providers, func(p *config.Provider) bool { return len(p.Tags) > 0 })
+	if withTags {
+		headers = append(headers, "TAGS")
+	}
 
-		model := p.EffectiveModel()
-		if model != "" {
-			ui.Dim("          Model: %s\n", model)
+	rows := make([][]string, 0, len(providers))
+	for _, p := range providers {
+		name := p.Name
+		if name == defaultName {
+			name += " *"
 		}
+		lastUsed := usage.LastUsed(p.Name, now)
+		if lastUsed == "" {
+			lastUsed = "never"
+		}
+		row := []string{name, p.Type, p.EffectiveModel(), keyState(p), lastUsed}
+		if withTags {
+			row = append(row, strings.Join(p.Tags, ","))
+		}
+		rows = append(rows, row)
 	}
+	ui.TableTo(w, headers, rows)
+}
 
-	ui.Log("")
-	ui.Log("Run: %s", ui.Green("skint use <name>"))
-
-	return nil
+// keyState describes p's API key for the list: where it is kept, "not set",
+// or "-" if p doesn't need one.
+func keyState(p *config.Provider) string {
+	switch {
+	case !p.NeedsAPIKey():
+		return "-"
+	case !p.IsConfigured():
+		return "not set"
+	case p.KeyBackend() == "":
+		return "set"
+	}
+	return p.KeyBackend()
 }
@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/sammcj/skint/internal/config"
+)
+
+func TestListFilter(t *testing.T) {
+	zai := &config.Provider{Name: "zai", Type: config.ProviderTypeBuiltin, Tags: []string{"work", "cloud"}}
+	zai.SetResolvedAPIKey("sk-test")
+	kimi := &config.Provider{Name: "kimi", Type: config.ProviderTypeBuiltin, Tags: []string{"cloud"}}
+	ollama := &config.Provider{Name: "ollama", Type: config.ProviderTypeLocal, Tags: []string{"work"}}
+	all := []*config.Provider{zai, kimi, ollama}
+
+	yes, no := true, false
+	tests := []struct {
+		name   string
+		filter listFilter
+		want   []string
+	}{
+		{"none", listFilter{}, []string{"zai", "kimi", "ollama"}},
+		{"configured", listFilter{configured: &yes}, []string{"zai", "ollama"}},
+		{"not configured", listFilter{configured: &no}, []string{"kimi"}},
+		{"type", listFilter{typ: config.ProviderTypeLocal}, []string{"ollama"}},
+		{"tag", listFilter{tags: []string{"work"}}, []string{"zai", "ollama"}},
+		{"every tag", listFilter{tags: []string{"work", "cloud"}}, []string{"zai"}},
+		{"combined", listFilter{configured: &yes, tags: []string{"cloud"}}, []string{"zai"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			for _, p := range all {
+				if tc.filter.match(p) {
+					got = append(got, p.Name)
+				}
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Fatalf("got %v, want %v", got, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestKeyState(t *testing.T) {
+	stored := &config.Provider{Name: "zai", Type: config.ProviderTypeBuiltin, APIKeyRef: "keyring:zai"}
+	if got := keyState(stored); got != "keyring" {
+		t.Errorf("stored key = %q, want keyring", got)
+	}
+	if got := keyState(&config.Provider{Name: "kimi", Type: config.ProviderTypeBuiltin}); got != "not set" {
+		t.Errorf("missing key = %q, want not set", got)
+	}
+	if got := keyState(&config.Provider{Name: "ollama", Type: config.ProviderTypeLocal}); got != "-" {
+		t.Errorf("local provider = %q, want -", got)
+	}
+}
@@ -2,28 +2,55 @@ package commands
 
 import (
 	"fmt"
+	"os"
+	"slices"
+	"strings"
 
 	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/errcode"
 	"github.com/sammcj/skint/internal/ui"
 	"github.com/spf13/cobra"
 )
 
+// migrateSources are what skint migrate imports from: the old bash version,
+// claude-code-router (ccr) and LiteLLM.
+var migrateSources = []string{"bash", "ccr", "litellm"}
+
 // NewMigrateCmd creates the migrate command
 func NewMigrateCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "migrate",
-		Short: "Migrate from old bash version",
+		Short: "Migrate from old bash version, claude-code-router or LiteLLM",
 		Long: `Migrate configuration and API keys from the old bash version of Skint.
 
 This imports:
   - API keys from ~/.local/share/skint/secrets.env
   - Provider configurations
-  - Creates new YAML config file`,
+  - Creates new YAML config file
+
+With --from ccr, providers are imported from a claude-code-router
+config.json (~/.claude-code-router/config.json, or --file) instead:
+OpenRouter providers become an or-* provider per model, sharing one key,
+and the rest become custom providers. Keys given as $VAR are read from the
+environment and stored in the keyring. Providers that already exist are
+left alone, and the router's transformers have no skint equivalent.
+
+With --from litellm, providers are imported from the model_list of the
+LiteLLM proxy config given with --file, one per entry, named after its
+model_name. Keys given as os.environ/VAR are read from the environment.
+'skint export --format litellm' goes the other way.`,
+		Example: `  skint migrate
+  skint migrate --from ccr
+  skint migrate --from ccr --file ./config.json --yes
+  skint migrate --from litellm --file litellm.yaml`,
 		RunE: runMigrate,
 	}
 
 	cmd.Flags().Bool("import-secrets", true, "Import secrets from old installation")
 	cmd.Flags().Bool("keep-old", false, "Keep old files after migration")
+	cmd.Flags().String("from", "bash", "what to migrate from: "+strings.Join(migrateSources, ", "))
+	cmd.Flags().String("file", "", "config to import (default ~/.claude-code-router/config.json for ccr)")
+	_ = cmd.RegisterFlagCompletionFunc("from", cobra.FixedCompletions(migrateSources, cobra.ShellCompDirectiveNoFileComp))
 
 	return cmd
 }
@@ -33,6 +60,15 @@ func runMigrate(cmd *cobra.Command, args []string) error {
 	importSecrets, _ := cmd.Flags().GetBool("import-secrets")
 	keepOld, _ := cmd.Flags().GetBool("keep-old")
 
+	from, _ := cmd.Flags().GetString("from")
+	if !slices.Contains(migrateSources, from) {
+		return errcode.New(errcode.Usage, "unknown migration source %q: use %s", from, strings.Join(migrateSources, ", "))
+	}
+	if from != "bash" {
+		file, _ := cmd.Flags().GetString("file")
+		return runMigrateImport(cc, from, file)
+	}
+
 	// Check for old installation
 	migration, err := config.NewMigration()
 	if err != nil {
@@ -79,6 +115,9 @@ func runMigrate(cmd *cobra.Command, args []string) error {
 	if importSecrets {
 		// Confirm
 		if !cc.YesMode {
+			if cc.NoInput {
+				return errcode.New(errcode.InputRequired, "migrating needs confirmation. Use --yes")
+			}
 			if !ui.Confirm("Proceed with migration?", true) {
 				ui.Info("Cancelled")
 				return nil
@@ -90,8 +129,8 @@ func runMigrate(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		// Clean up old files if requested
-		if !keepOld && !cc.YesMode {
+		// Clean up old files if requested. Without prompts they are kept.
+		if !keepOld && !cc.YesMode && !cc.NoInput {
 			fmt.Println()
 			if ui.Confirm("Remove old installation files?", true) {
 				if err := migration.Cleanup(); err != nil {
@@ -107,3 +146,136 @@ func runMigrate(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// runMigrateImport imports the providers in another tool's config: from is
+// ccr (claude-code-router) or litellm.
+func runMigrateImport(cc *CmdContext, from, file string) error {
+	tool := "claude-code-router"
+	parse := config.ImportCCR
+	if from == "litellm" {
+		tool, parse = "LiteLLM", config.ImportLiteLLM
+		if file == "" {
+			return errcode.New(errcode.Usage, "--file is required with --from litellm")
+		}
+	}
+	if file == "" {
+		var err error
+		if file, err = config.CCRConfigPath(); err != nil {
+			return err
+		}
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("no %s config found at %s", tool, file)
+		}
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	imp, err := parse(data, os.Getenv)
+	if err != nil {
+		return err
+	}
+
+	// Providers that already exist are kept as they are
+	var providers []*config.Provider
+	for _, p := range imp.Providers {
+		if cc.Cfg.GetProvider(p.Name) != nil {
+			imp.Skipped = append(imp.Skipped, fmt.Sprintf("%s: already configured", p.Name))
+			continue
+		}
+		providers = append(providers, p)
+	}
+	if len(providers) == 0 {
+		for _, s := range imp.Skipped {
+			ui.Warning("Skipped %s", s)
+		}
+		return fmt.Errorf("nothing to import from %s", file)
+	}
+
+	if !cc.YesMode {
+		if cc.NoInput {
+			return errcode.New(errcode.InputRequired, "migrating needs confirmation. Use --yes")
+		}
+		ui.Log("Found %d provider(s) to import from %s", len(providers), file)
+		if !ui.Confirm("Proceed with migration?", true) {
+			ui.Info("Cancelled")
+			return nil
+		}
+	}
+
+	names, err := cc.importProviders(providers, imp.Keys)
+	if err != nil {
+		return err
+	}
+	defaultSet := imp.Default != "" && cc.Cfg.DefaultProvider == "" && slices.Contains(names, imp.Default)
+	if defaultSet {
+		cc.Cfg.DefaultProvider = imp.Default
+	}
+	if err := cc.SaveConfig(); err != nil {
+		return err
+	}
+
+	switch cc.Cfg.OutputFormat {
+	case config.FormatJSON:
+		out := map[string]any{"imported": names, "skipped": imp.Skipped}
+		if defaultSet {
+			out["default"] = imp.Default
+		}
+		return cc.Output(out)
+	case config.FormatPlain:
+		for _, name := range names {
+			fmt.Println(name)
+		}
+		return nil
+	}
+	for _, s := range imp.Skipped {
+		ui.Warning("Skipped %s", s)
+	}
+	ui.Success("Imported %d provider(s) from %s", len(names), tool)
+	if defaultSet {
+		ui.Info("Default provider set to %s", imp.Default)
+	}
+	return nil
+}
+
+// importProviders adds providers to the config and stores their keys,
+// returning their names. Each provider is added before its key is stored, so
+// a provider the config rejects leaves no key behind. The or-* providers use
+// the stored OpenRouter key if there is one.
+func (cc *CmdContext) importProviders(providers []*config.Provider, keys map[string]string) ([]string, error) {
+	openRouterRef := ""
+	for _, p := range cc.Cfg.Providers {
+		if p.Type == config.ProviderTypeOpenRouter && p.APIKeyRef != "" {
+			openRouterRef = p.APIKeyRef
+			break
+		}
+	}
+
+	var names []string
+	for _, p := range providers {
+		if err := cc.Cfg.AddProvider(p); err != nil {
+			return nil, err
+		}
+		keyName := p.Name
+		switch {
+		case p.Type == config.ProviderTypeOpenRouter && openRouterRef != "":
+			p.APIKeyRef = openRouterRef
+			keyName = ""
+		case p.Type == config.ProviderTypeOpenRouter:
+			keyName = "openrouter"
+		}
+		if key := keys[keyName]; keyName != "" && key != "" {
+			ref, err := cc.SecretsMgr.StoreWithReference(keyName, key)
+			if err != nil {
+				cc.Cfg.RemoveProvider(p.Name)
+				return nil, fmt.Errorf("failed to store key for %s: %w", keyName, err)
+			}
+			p.APIKeyRef = ref
+			if p.Type == config.ProviderTypeOpenRouter {
+				openRouterRef = ref
+			}
+		}
+		names = append(names, p.Name)
+	}
+	return names, nil
+}
@@ -0,0 +1,453 @@
+package commands
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/errcode"
+	"github.com/sammcj/skint/internal/models"
+	"github.com/sammcj/skint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// NewModelsCmd creates the models command
+func NewModelsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "models [provider]",
+		Short: "List a provider's models",
+		Long: `List the models a provider offers, as the TUI's model picker does.
+Without a provider, the provider for the current directory is used.
+
+Lists are cached for model_cache_ttl (an hour by default); --refresh
+fetches a fresh one. --set makes a model the provider's default and saves
+the config.
+
+Where the provider reports them (OpenRouter), each model's context length
+and price per million input/output tokens are shown. --prices lists only
+the priced models, cheapest first; with --output plain it prints each ID
+with its input and output prices, tab-separated.
+
+Where the provider reports what models support (OpenRouter), --tools-only
+leaves out those without tool calling, which Claude Code needs.
+
+--recommended lists the models recommended for the provider instead, from
+a list built into skint; with --refresh it first updates that list from
+skint's repository.
+
+For Ollama, --pull downloads the provider's model, or the one named by
+--set, showing its progress, and the listing warns when the configured
+model hasn't been pulled.`,
+		Example: `  skint models ollama
+  skint models openrouter --filter claude
+  skint models zai --set glm-4.7
+  skint models openrouter --prices --filter coder
+  skint models openrouter --tools-only
+  skint models ollama --recommended
+  skint models ollama --pull --set qwen3-coder
+  skint models --output json`,
+		Args:              cobra.MaximumNArgs(1),
+		RunE:              runModels,
+		ValidArgsFunction: completeProvider,
+	}
+	cmd.Flags().String("filter", "", "only list models whose ID or name contains this (case-insensitive)")
+	cmd.Flags().Bool("refresh", false, "fetch the list again instead of using the cache")
+	cmd.Flags().Bool("prices", false, "list only priced models, cheapest first")
+	cmd.Flags().Bool("tools-only", false, "leave out models known not to support tool calling")
+	cmd.Flags().Bool("recommended", false, "list the models recommended for the provider")
+	cmd.Flags().String("set", "", "set the provider's model and save the config")
+	cmd.Flags().Bool("pull", false, "pull the model into Ollama first (Ollama only)")
+	return cmd
+}
+
+// modelList is a provider's models as listed by skint models.
+type modelList struct {
+	models  []models.ModelInfo
+	fetched time.Time
+	cached  bool
+}
+
+func runModels(cmd *cobra.Command, args []string) error {
+	cc := GetContext(cmd)
+	name := cc.DefaultProviderName()
+	if len(args) > 0 {
+		name = args[0]
+	}
+	if name == "" || name == "native" {
+		return fmt.Errorf("the Claude subscription's models are not listed; name a provider, e.g. 'skint models anthropic'")
+	}
+
+	p, err := cc.ResolveProvider(name)
+	if err != nil {
+		return err
+	}
+	filter, _ := cmd.Flags().GetString("filter")
+	refresh, _ := cmd.Flags().GetBool("refresh")
+	set, _ := cmd.Flags().GetString("set")
+	prices, _ := cmd.Flags().GetBool("prices")
+	toolsOnly, _ := cmd.Flags().GetBool("tools-only")
+	recommended, _ := cmd.Flags().GetBool("recommended")
+	pull, _ := cmd.Flags().GetBool("pull")
+
+	if recommended {
+		return cc.printRecommended(cmd.Context(), p, refresh)
+	}
+	if pull {
+		if p.Name != "ollama" {
+			return errcode.New(errcode.Usage, "--pull only works with Ollama")
+		}
+		model := cmp.Or(set, p.EffectiveModel())
+		if model == "" {
+			return errcode.New(errcode.Usage, "no model to pull: name one with --set <model>")
+		}
+		if err := cc.pullModel(cmd.Context(), p, model); err != nil {
+			return err
+		}
+		refresh = true
+	}
+
+	list, err := cc.providerModels(cmd.Context(), p, refresh)
+	if err != nil {
+		return err
+	}
+
+	if set != "" {
+		offered := func(l modelList) bool {
+			return slices.ContainsFunc(l.models, func(m models.ModelInfo) bool { return m.ID == set })
+		}
+		// A cached list may predate the model
+		if !offered(list) && list.cached {
+			if list, err = cc.providerModels(cmd.Context(), p, true); err != nil {
+				return err
+			}
+		}
+		if !offered(list) && p.Name == "ollama" {
+			return fmt.Errorf("%s hasn't been pulled. Run 'skint models ollama --pull --set %s' to download it", set, set)
+		}
+		if !offered(list) {
+			return fmt.Errorf("%s does not offer %s. Run 'skint models %s' to list its models", name, set, name)
+		}
+		if err := cc.setProviderModel(name, set); err != nil {
+			return err
+		}
+		notLoaded := func(l modelList) bool {
+			i := slices.IndexFunc(l.models, func(m models.ModelInfo) bool { return m.ID == set })
+			return i >= 0 && l.models[i].NotLoaded()
+		}
+		// Load states change often, so don't warn from a cached list
+		if notLoaded(list) && list.cached {
+			list, _ = cc.providerModels(cmd.Context(), p, true)
+		}
+		if notLoaded(list) && cc.Cfg.OutputFormat != config.FormatJSON {
+			ui.Warning("%s isn't loaded in %s: load it there, or turn on JIT (just-in-time) model loading", set, p.DisplayName)
+		}
+		return nil
+	}
+
+	shown := filterModels(list.models, filter)
+	if toolsOnly {
+		shown = models.ToolCapable(shown)
+	}
+	if prices {
+		shown = byPrice(shown)
+	}
+	current := p.EffectiveModel()
+
+	switch cc.Cfg.OutputFormat {
+	case config.FormatJSON:
+		return cc.Output(map[string]any{
+			"provider": name,
+			"current":  current,
+			"fetched":  list.fetched,
+			"cached":   list.cached,
+			"models":   shown,
+		})
+	case config.FormatPlain:
+		for _, m := range shown {
+			if prices {
+				fmt.Printf("%s\t%g\t%g\n", m.ID, m.Pricing.Input, m.Pricing.Output)
+				continue
+			}
+			fmt.Println(m.ID)
+		}
+		return nil
+	case config.FormatTable:
+		printModels(os.Stdout, shown, current)
+		return nil
+	}
+
+	if len(shown) == 0 && prices && filter == "" {
+		ui.Warning("%s does not report model prices", p.DisplayName)
+		return nil
+	}
+	if len(shown) == 0 && toolsOnly && filter == "" {
+		ui.Warning("None of %s's models support tool calling", p.DisplayName)
+		return nil
+	}
+	if len(shown) == 0 {
+		ui.Warning("No models match %q", filter)
+		return nil
+	}
+	// A cached list may predate pulling the model
+	if p.Name == "ollama" && current != "" && !models.OllamaHas(list.models, current) && list.cached {
+		if fresh, err := cc.providerModels(cmd.Context(), p, true); err == nil {
+			list = fresh
+		}
+	}
+	if p.Name == "ollama" && current != "" && !models.OllamaHas(list.models, current) {
+		ui.Warning("%s hasn't been pulled: run 'skint models ollama --pull' to download it", current)
+	}
+	fmt.Println()
+	age := ""
+	if list.cached {
+		age = ui.DimString(fmt.Sprintf(" (cached %s, --refresh to update)", config.Ago(list.fetched, time.Now())))
+	}
+	ui.Log("%s%s", ui.Bold(fmt.Sprintf("Models for %s", p.DisplayName)), age)
+	ui.Separator(40)
+	printModels(os.Stderr, shown, current)
+	fmt.Println()
+	return nil
+}
+
+// providerModels returns p's models, from the cache unless refresh is set or
+// the cached list is old.
+func (cc *CmdContext) providerModels(ctx context.Context, p *config.Provider, refresh bool) (modelList, error) {
+	fetchName, baseURL := p.Name, p.BaseURL
+	if strings.HasPrefix(p.Name, config.OpenRouterPrefix) {
+		// OpenRouter model providers all share OpenRouter's list
+		fetchName = "openrouter"
+	}
+
+	cacheDir, cacheErr := config.GetCacheDir()
+	if !refresh && cacheErr == nil {
+		if list, fetched, ok := models.LoadCache(cacheDir, fetchName, baseURL, cc.Cfg.ModelCacheMaxAge()); ok {
+			return modelList{models: list, fetched: fetched, cached: true}, nil
+		}
+	}
+
+	var spinner *ui.Spinner
+	if cc.Cfg.OutputFormat == config.FormatHuman && !cc.Quiet {
+		spinner = ui.NewSpinner(fmt.Sprintf("Fetching models from %s...", p.DisplayName))
+		spinner.Start()
+	}
+	ctx, cancel := context.WithTimeout(ctx, cc.Cfg.NetworkTimeout())
+	defer cancel()
+	result := models.FetchModels(ctx, baseURL, p.GetAPIKey(), fetchName)
+	if spinner != nil {
+		spinner.Stop(result.Err == nil)
+	}
+	if result.Err != nil {
+		return modelList{}, fmt.Errorf("failed to fetch models from %s: %w", p.Name, result.Err)
+	}
+	if len(result.Models) == 0 {
+		return modelList{}, fmt.Errorf("%s does not list its models", p.Name)
+	}
+
+	if cacheErr == nil {
+		if err := models.SaveCache(cacheDir, fetchName, baseURL, result.Models); err != nil && cc.Verbose {
+			ui.Warning("Failed to cache models: %v", err)
+		}
+	}
+	return modelList{models: result.Models, fetched: time.Now()}, nil
+}
+
+// printRecommended prints the models recommended for p, after updating the
+// recommendations if refresh is set.
+func (cc *CmdContext) printRecommended(ctx context.Context, p *config.Provider, refresh bool) error {
+	name := p.Name
+	if strings.HasPrefix(name, config.OpenRouterPrefix) {
+		name = "openrouter"
+	}
+	cacheDir, err := config.GetCacheDir()
+	if err != nil {
+		return fmt.Errorf("failed to get cache directory: %w", err)
+	}
+	recs := models.LoadRecommendations(cacheDir)
+	if refresh {
+		ctx, cancel := context.WithTimeout(ctx, cc.Cfg.NetworkTimeout())
+		defer cancel()
+		if recs, err = models.RefreshRecommendations(ctx, cacheDir, models.RecommendedURL); err != nil {
+			return fmt.Errorf("failed to update the recommended models: %w", err)
+		}
+	}
+	ids := recs.For(name)
+	current := p.EffectiveModel()
+
+	switch cc.Cfg.OutputFormat {
+	case config.FormatJSON:
+		return cc.Output(map[string]any{"provider": p.Name, "current": current, "recommended": ids})
+	case config.FormatPlain:
+		for _, id := range ids {
+			fmt.Println(id)
+		}
+		return nil
+	}
+	list := make([]models.ModelInfo, 0, len(ids))
+	for _, id := range ids {
+		list = append(list, models.ModelInfo{ID: id})
+	}
+	if cc.Cfg.OutputFormat == config.FormatTable {
+		printModels(os.Stdout, list, current)
+		return nil
+	}
+
+	if len(ids) == 0 {
+		ui.Info("No models are recommended for %s: 'skint models %s' lists all of them", p.DisplayName, p.Name)
+		return nil
+	}
+	fmt.Println()
+	ui.Log("%s", ui.Bold(fmt.Sprintf("Recommended models for %s", p.DisplayName)))
+	ui.Separator(40)
+	printModels(os.Stderr, list, current)
+	fmt.Println()
+	return nil
+}
+
+// setProviderModel makes model the configured provider's model and saves.
+func (cc *CmdContext) setProviderModel(name, model string) error {
+	p := cc.Cfg.GetProvider(name)
+	if p == nil {
+		return errcode.New(errcode.ProviderNotFound, "provider %s is not configured. Run 'skint config %s' to set it up", name, name)
+	}
+	p.Model = model
+	if err := cc.SaveConfig(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	if cc.Cfg.OutputFormat == config.FormatJSON {
+		return cc.Output(map[string]any{"provider": name, "model": model})
+	}
+	ui.Success("%s now uses %s", name, model)
+	return nil
+}
+
+// pullModel pulls model into p's Ollama server, showing its progress.
+func (cc *CmdContext) pullModel(ctx context.Context, p *config.Provider, model string) error {
+	show := cc.Cfg.OutputFormat == config.FormatHuman && !cc.Quiet
+	status := ""
+	err := models.PullOllama(ctx, p.BaseURL, model, func(progress models.PullProgress) {
+		switch {
+		case !show:
+		case ui.Colors.Enabled:
+			fmt.Fprintf(os.Stderr, "\r\033[K%s %s", ui.Sym.Arrow, progress)
+		case progress.Status != status:
+			// Without colours, e.g. piped, only show each step once
+			ui.Info("%s", progress.Status)
+		}
+		status = progress.Status
+	})
+	if show && ui.Colors.Enabled {
+		fmt.Fprint(os.Stderr, "\r\033[K")
+	}
+	if err != nil {
+		return err
+	}
+	if show {
+		ui.Success("Pulled %s", model)
+	}
+	return nil
+}
+
+// filterModels returns the models whose ID or display name contains filter,
+// ignoring case.
+func filterModels(list []models.ModelInfo, filter string) []models.ModelInfo {
+	filter = strings.ToLower(strings.TrimSpace(filter))
+	if filter == "" {
+		return list
+	}
+	var out []models.ModelInfo
+	for _, m := range list {
+		if strings.Contains(strings.ToLower(m.ID), filter) || strings.Contains(strings.ToLower(m.DisplayName), filter) {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// byPrice returns the models in list with a known price, cheapest first:
+// by input price, then output price, then ID.
+func byPrice(list []models.ModelInfo) []models.ModelInfo {
+	priced := slices.DeleteFunc(slices.Clone(list), func(m models.ModelInfo) bool { return m.Pricing == nil })
+	slices.SortStableFunc(priced, func(a, b models.ModelInfo) int {
+		return cmp.Or(
+			cmp.Compare(a.Pricing.Input, b.Pricing.Input),
+			cmp.Compare(a.Pricing.Output, b.Pricing.Output),
+			cmp.Compare(a.ID, b.ID),
+		)
+	})
+	return priced
+}
+
+// printModels prints list as a table to w, marking the current model with *.
+// Columns no model has a value for are left out.
+func printModels(w io.Writer, list []models.ModelInfo, current string) {
+	var names, context, pricing, capabilities, params, quantization, state bool
+	for _, m := range list {
+		params = params || m.ParameterSize != ""
+		quantization = quantization || m.Quantization != ""
+		state = state || m.State != ""
+		names = names || (m.DisplayName != "" && m.DisplayName != m.ID)
+		context = context || m.ContextLength > 0
+		pricing = pricing || m.Pricing != nil
+		capabilities = capabilities || m.Capabilities != nil
+	}
+
+	headers := []string{"  ID"}
+	if names {
+		headers = append(headers, "NAME")
+	}
+	if context {
+		headers = append(headers, "CONTEXT")
+	}
+	if pricing {
+		headers = append(headers, "$/M IN/OUT")
+	}
+	if capabilities {
+		headers = append(headers, "SUPPORTS")
+	}
+	if params {
+		headers = append(headers, "PARAMS")
+	}
+	if quantization {
+		headers = append(headers, "QUANT")
+	}
+	if state {
+		headers = append(headers, "STATE")
+	}
+	rows := make([][]string, 0, len(list))
+	for _, m := range list {
+		row := []string{"  " + m.ID}
+		if m.ID == current {
+			row[0] = "* " + m.ID
+		}
+		if names {
+			row = append(row, m.DisplayName)
+		}
+		if context {
+			row = append(row, models.FormatContext(m.ContextLength))
+		}
+		if pricing {
+			row = append(row, models.FormatPricing(m.Pricing))
+		}
+		if capabilities {
+			row = append(row, models.FormatCapabilities(m.Capabilities))
+		}
+		if params {
+			row = append(row, m.ParameterSize)
+		}
+		if quantization {
+			row = append(row, m.Quantization)
+		}
+		if state {
+			row = append(row, models.FormatState(m.State))
+		}
+		rows = append(rows, row)
+	}
+	ui.TableTo(w, headers, rows)
+}
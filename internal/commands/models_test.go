@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/sammcj/skint/internal/models"
+)
+
+func TestByPrice(t *testing.T) {
+	list := []models.ModelInfo{
+		{ID: "openrouter/auto"},
+		{ID: "anthropic/claude-sonnet-4", Pricing: &models.Pricing{Input: 3, Output: 15}},
+		{ID: "qwen/qwen3-coder:free", Pricing: &models.Pricing{}},
+		{ID: "z-ai/glm-4.7", Pricing: &models.Pricing{Input: 0.4, Output: 1.75}},
+		{ID: "qwen/qwen3-coder", Pricing: &models.Pricing{Input: 0.4, Output: 1.6}},
+	}
+	var got []string
+	for _, m := range byPrice(list) {
+		got = append(got, m.ID)
+	}
+	want := []string{"qwen/qwen3-coder:free", "qwen/qwen3-coder", "z-ai/glm-4.7", "anthropic/claude-sonnet-4"}
+	if !slices.Equal(got, want) {
+		t.Errorf("byPrice = %v, want %v", got, want)
+	}
+	if list[0].ID != "openrouter/auto" {
+		t.Error("byPrice should not change its argument")
+	}
+}
+
+func TestFilterModels(t *testing.T) {
+	list := []models.ModelInfo{
+		{ID: "anthropic/claude-sonnet-4", DisplayName: "Anthropic: Claude Sonnet 4"},
+		{ID: "qwen/qwen3-coder", DisplayName: "Qwen3 Coder"},
+		{ID: "z-ai/glm-4.7"},
+	}
+	ids := func(l []models.ModelInfo) []string {
+		var out []string
+		for _, m := range l {
+			out = append(out, m.ID)
+		}
+		return out
+	}
+
+	for filter, want := range map[string][]string{
+		"":        {"anthropic/claude-sonnet-4", "qwen/qwen3-coder", "z-ai/glm-4.7"},
+		"CODER":   {"qwen/qwen3-coder"},
+		"sonnet":  {"anthropic/claude-sonnet-4"},
+		" glm ":   {"z-ai/glm-4.7"},
+		"nothing": nil,
+	} {
+		if got := ids(filterModels(list, filter)); !slices.Equal(got, want) {
+			t.Errorf("filter %q = %v, want %v", filter, got, want)
+		}
+	}
+}
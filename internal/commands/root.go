@@ -2,10 +2,19 @@ package commands
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
+	"os/exec"
+	"strings"
 
 	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/errcode"
+	"github.com/sammcj/skint/internal/httpretry"
+	"github.com/sammcj/skint/internal/launcher"
+	"github.com/sammcj/skint/internal/logging"
 	"github.com/sammcj/skint/internal/secrets"
 	"github.com/sammcj/skint/internal/tui"
 	"github.com/sammcj/skint/internal/ui"
@@ -15,6 +24,7 @@ import (
 // RootCmd is the root command
 type RootCmd struct {
 	*cobra.Command
+	cc *CmdContext
 }
 
 // NewRootCmd creates the root command
@@ -35,9 +45,18 @@ Skint makes it easy to use Claude Code with different LLM providers
 like Z.AI, MiniMax, Kimi, DeepSeek, OpenRouter, and local models via
 Ollama, LM Studio, or llama.cpp.`,
 		Version: version,
+		// Run reports errors, with their codes
+		SilenceErrors: true,
+		SilenceUsage:  true,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 			// Ensure context is set before initialize runs
 			cmd.SetContext(context.WithValue(cmd.Context(), ctxKey, cc))
+			cc.tolerateConfigErr = cmd.Annotations[annotationTolerateConfigErr] == "true"
+			cc.skipSecrets = cmd.Annotations[annotationSkipSecrets] == "true"
+			if isCompletionCmd(cmd) {
+				cc.NoInput = true
+				cc.tolerateConfigErr = true
+			}
 
 			// Build passthrough args for claude (reset first to avoid accumulation)
 			cc.ClaudeExtraArgs = nil
@@ -52,7 +71,11 @@ Ollama, LM Studio, or llama.cpp.`,
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cc := GetContext(cmd)
-			return tui.RunInteractive(cc.Cfg, cc.SecretsMgr, cc.SaveConfig, cc.LaunchClaude)
+			if cc.NoInput {
+				return errcode.New(errcode.InputRequired, "the interactive TUI can't run with --no-input. Use 'skint use <provider>' instead")
+			}
+			logging.StopTerminal()
+			return tui.RunInteractive(cc.Cfg, cc.SecretsMgr, cc.SaveConfig, cc.LaunchClaude, cc.Inline)
 		},
 	}
 
@@ -60,23 +83,104 @@ Ollama, LM Studio, or llama.cpp.`,
 	root.SetContext(context.Background())
 
 	// Bind flags directly to CmdContext fields
-	root.PersistentFlags().StringVar(&cc.cfgFile, "config", "", "config file (default is $XDG_CONFIG_HOME/skint/config.yaml)")
+	root.PersistentFlags().StringVar(&cc.cfgFile, "config", "", "config file, YAML/JSON/TOML (default is $XDG_CONFIG_HOME/skint/config.yaml)")
 	root.PersistentFlags().BoolVarP(&cc.Verbose, "verbose", "v", false, "verbose output")
 	root.PersistentFlags().BoolVarP(&cc.Quiet, "quiet", "q", false, "minimal output")
 	root.PersistentFlags().BoolVarP(&cc.YesMode, "yes", "y", false, "auto-confirm prompts")
 	root.PersistentFlags().BoolVar(&cc.NoInput, "no-input", false, "non-interactive mode")
 	root.PersistentFlags().BoolVar(&cc.NoColor, "no-color", false, "disable colours")
 	root.PersistentFlags().BoolVar(&cc.NoBanner, "no-banner", false, "hide banner")
-	root.PersistentFlags().StringVar(&cc.OutputFormat, "output", "human", "output format: human, json, plain")
-	root.PersistentFlags().StringVar(&cc.BinDir, "bin-dir", "", "binary directory (default is ~/.local/bin on Linux, ~/bin on macOS)")
+	root.PersistentFlags().BoolVar(&cc.Inline, "inline", false, "draw the TUI inline, keeping it in the scrollback, instead of full screen")
+	root.PersistentFlags().StringVar(&cc.OutputFormat, "output", "human", "output format: human, json, plain, table")
+	root.PersistentFlags().StringVar(&cc.Timeout, "timeout", "", "timeout for network requests such as provider tests and model lists (default 5s)")
+	root.PersistentFlags().StringVar(&cc.LogFile, "log-file", "", "append a debug log to this file (level from SKINT_LOG, default debug)")
+	root.PersistentFlags().StringVar(&cc.BinDir, "bin-dir", "", "binary directory (default is ~/.local/bin on Linux, ~/bin on macOS, %LOCALAPPDATA%\\Programs\\skint\\bin on Windows)")
 
 	// Claude passthrough flags
 	root.PersistentFlags().StringVar(&resumeSession, "resume", "", "resume a Claude session by ID")
 	root.PersistentFlags().BoolVarP(&continueSession, "continue", "c", false, "continue the most recent Claude session")
 
-	return &RootCmd{root}
+	registerFlagCompletions(root)
+	root.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return errcode.Wrap(errcode.Usage, err)
+	})
+
+	return &RootCmd{root, cc}
+}
+
+// Run executes the command line and returns skint's exit status. Errors are
+// printed to stderr, as JSON objects with --output json, and exit with their
+// code's status; a command skint ran that exited non-zero passes its own
+// status through.
+func (r *RootCmd) Run() int {
+	defer logging.Close()
+	tagArgErrors(r.Command)
+	cmd, err := r.ExecuteC()
+	if err == nil {
+		slog.Debug("command finished", "command", cmd.CommandPath())
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		slog.Info("command exited", "command", cmd.CommandPath(), "status", exitErr.ExitCode())
+		return exitErr.ExitCode()
+	}
+	// cobra reports unknown commands without a hook to tag them
+	if strings.HasPrefix(err.Error(), "unknown command ") {
+		err = errcode.Wrap(errcode.Usage, err)
+	}
+
+	code := errcode.Of(err)
+	slog.Error("command failed", "command", cmd.CommandPath(), "code", code, "err", err)
+	if r.outputFormat() == config.FormatJSON {
+		enc := json.NewEncoder(os.Stderr)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(map[string]any{"error": map[string]any{
+			"code":      code,
+			"message":   err.Error(),
+			"exit_code": code.ExitCode(),
+		}})
+	} else {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		if code == errcode.Usage {
+			fmt.Fprintf(os.Stderr, "Run '%s --help' for usage.\n", cmd.CommandPath())
+		}
+	}
+	return code.ExitCode()
+}
+
+// outputFormat returns the output format in effect, which is only in the
+// config once it has loaded.
+func (r *RootCmd) outputFormat() string {
+	if r.cc.Cfg != nil {
+		return r.cc.Cfg.OutputFormat
+	}
+	return r.cc.OutputFormat
+}
+
+// tagArgErrors gives the errors from cmd's and its subcommands' argument
+// checks the usage code.
+func tagArgErrors(cmd *cobra.Command) {
+	if args := cmd.Args; args != nil {
+		cmd.Args = func(cmd *cobra.Command, a []string) error {
+			return errcode.Wrap(errcode.Usage, args(cmd, a))
+		}
+	}
+	for _, c := range cmd.Commands() {
+		tagArgErrors(c)
+	}
 }
 
+// annotationTolerateConfigErr marks a command that runs with the default
+// config when the config file can't be loaded, e.g. to diagnose it. The error
+// is left in CmdContext.ConfigErr.
+const annotationTolerateConfigErr = "skint:tolerate-config-error"
+
+// annotationSkipSecrets marks a command that only reads the config, so the
+// secrets manager (which probes the keyring), migration and API keys are
+// skipped to keep it fast. CmdContext.SecretsMgr is then nil.
+const annotationSkipSecrets = "skint:skip-secrets"
+
 // initialize sets up the configuration and secrets managers
 func initialize(cc *CmdContext) error {
 	// Handle environment variable overrides
@@ -98,43 +202,122 @@ func initialize(cc *CmdContext) error {
 	if os.Getenv("SKINT_NO_BANNER") == "1" {
 		cc.NoBanner = true
 	}
+	if os.Getenv("SKINT_INLINE") == "1" {
+		cc.Inline = true
+	}
 	if v := os.Getenv("SKINT_OUTPUT_FORMAT"); v != "" {
 		cc.OutputFormat = v
 	}
+	if err := cc.setupLogging(); err != nil {
+		return err
+	}
 
 	// Create config manager
 	var err error
-	if cc.cfgFile != "" {
-		cc.ConfigMgr, err = config.NewManagerWithPath(cc.cfgFile)
-	} else {
-		cc.ConfigMgr, err = config.NewManager()
-	}
-	if err != nil {
+	if cc.ConfigMgr, err = cc.newConfigManager(); err != nil {
 		return fmt.Errorf("failed to initialise config: %w", err)
 	}
 
 	// Load config
+	cc.ConfigErr = nil
 	if err := cc.ConfigMgr.Load(); err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+		if !cc.tolerateConfigErr {
+			return errcode.New(errcode.ConfigInvalid, "failed to load config: %w", err)
+		}
+		// Start again from the defaults, as a failed load may have applied part of the file
+		cc.ConfigErr = err
+		if cc.ConfigMgr, err = cc.newConfigManager(); err != nil {
+			return fmt.Errorf("failed to initialise config: %w", err)
+		}
 	}
 
 	cc.Cfg = cc.ConfigMgr.Get()
+	slog.Debug("config loaded", "file", cc.ConfigMgr.ConfigFile(), "providers", len(cc.Cfg.Providers), "err", cc.ConfigErr)
 
-	// Apply CLI flags to config
+	// Apply CLI flags to config, for this run only
 	if cc.NoColor {
-		cc.Cfg.ColorEnabled = false
+		cc.ConfigMgr.Override("ColorEnabled", false)
 	}
 	if cc.NoBanner {
-		cc.Cfg.NoBanner = true
+		cc.ConfigMgr.Override("NoBanner", true)
 	}
 	if cc.OutputFormat != "" {
-		cc.Cfg.OutputFormat = cc.OutputFormat
+		cc.ConfigMgr.Override("OutputFormat", cc.OutputFormat)
+	}
+	if cc.Timeout != "" {
+		d, err := config.ParseTimeout(cc.Timeout)
+		if err != nil {
+			return errcode.Wrap(errcode.Usage, err)
+		}
+		cc.ConfigMgr.Override("Timeout", config.Duration(d))
 	}
 
 	// Initialise UI
 	ui.Init(cc.Cfg)
+	launcher.Init(cc.Cfg)
+	httpretry.Init(httpretry.Policy{Retries: cc.Cfg.NetworkRetries(), Backoff: cc.Cfg.NetworkRetryBackoff()})
+	if cc.NoInput {
+		ui.DisableInput()
+	}
+
+	if !cc.skipSecrets {
+		if err := cc.initSecrets(); err != nil {
+			return err
+		}
+	}
+
+	// Pick up a project config (.skint.yaml) and directory rule for the
+	// working directory. A broken project file should not stop skint working
+	// elsewhere in the tree.
+	if wd, err := os.Getwd(); err == nil {
+		project, err := config.FindProjectConfig(wd)
+		if err != nil {
+			ui.Warning("Ignoring project config: %v", err)
+		}
+		cc.Project = project
+		cc.DirRule = cc.Cfg.MatchDirectoryRule(wd)
+	}
+
+	// Make the directory's provider the default for this run, so the TUI shows
+	// and launches it too. SKINT_DEFAULT_PROVIDER is more explicit and wins.
+	if name := cc.DefaultProviderName(); name != cc.Cfg.DefaultProvider && os.Getenv("SKINT_DEFAULT_PROVIDER") == "" {
+		if err := cc.ConfigMgr.OverrideDefaultProvider(name); err != nil && cc.Verbose {
+			ui.Warning("Not using %s as the default here: %v", name, err)
+		}
+	}
+
+	// Load API keys for providers
+	if !cc.skipSecrets {
+		cc.LoadProviderKeys()
+	}
+
+	return nil
+}
+
+// setupLogging starts the debug log from --log-file (or SKINT_LOG_FILE) and
+// SKINT_LOG. A bad SKINT_LOG is ignored with a warning, like other SKINT_*
+// overrides; a log file that can't be opened is an error.
+func (cc *CmdContext) setupLogging() error {
+	path := cc.LogFile
+	if path == "" {
+		path = os.Getenv("SKINT_LOG_FILE")
+	}
+	level := os.Getenv("SKINT_LOG")
+	if _, _, err := logging.ParseLevel(level); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: ignoring SKINT_LOG: %v\n", err)
+		level = ""
+	}
+	if err := logging.Setup(level, path); err != nil {
+		return err
+	}
+	slog.Debug("starting", "args", os.Args[1:])
+	return nil
+}
 
-	// Create secrets manager
+// initSecrets creates the secrets manager, then offers to migrate an old
+// installation, which stores its keys there.
+func (cc *CmdContext) initSecrets() error {
+	var err error
 	cc.SecretsMgr, err = secrets.NewManager()
 	if err != nil {
 		return fmt.Errorf("failed to initialise secrets: %w", err)
@@ -160,9 +343,13 @@ func initialize(cc *CmdContext) error {
 			}
 		}
 	}
-
-	// Load API keys for providers
-	cc.LoadProviderKeys()
-
 	return nil
 }
+
+// newConfigManager creates a config manager for --config, or the default path.
+func (cc *CmdContext) newConfigManager() (*config.Manager, error) {
+	if cc.cfgFile != "" {
+		return config.NewManagerWithPath(cc.cfgFile)
+	}
+	return config.NewManager()
+}
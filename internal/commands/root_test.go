@@ -2,8 +2,13 @@ package commands
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/sammcj/skint/internal/errcode"
 	"github.com/spf13/cobra"
 )
 
@@ -90,3 +95,71 @@ func TestClaudePassthroughFlags(t *testing.T) {
 		})
 	}
 }
+
+func TestRunExitCodes(t *testing.T) {
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{[]string{"ok"}, 0},
+		{[]string{"fail"}, errcode.KeyMissing.ExitCode()},
+		{[]string{"ok", "extra"}, errcode.Usage.ExitCode()},
+		{[]string{"ok", "--nope"}, errcode.Usage.ExitCode()},
+		{[]string{"bogus"}, errcode.Usage.ExitCode()},
+		{[]string{"plain"}, 1},
+	}
+	for _, tc := range tests {
+		root := NewRootCmd("test")
+		root.PersistentPreRunE = func(cmd *cobra.Command, args []string) error { return nil }
+		root.AddCommand(
+			&cobra.Command{Use: "ok", Args: cobra.NoArgs, RunE: func(*cobra.Command, []string) error { return nil }},
+			&cobra.Command{Use: "fail", RunE: func(*cobra.Command, []string) error {
+				return fmt.Errorf("launch: %w", errcode.New(errcode.KeyMissing, "API key is required"))
+			}},
+			&cobra.Command{Use: "plain", RunE: func(*cobra.Command, []string) error { return errors.New("boom") }},
+		)
+		root.SetArgs(tc.args)
+		if got := root.Run(); got != tc.want {
+			t.Errorf("Run(%q) = %d, want %d", tc.args, got, tc.want)
+		}
+	}
+}
+
+func TestNoInputFailsFast(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "config"))
+	t.Setenv("XDG_DATA_HOME", filepath.Join(dir, "data"))
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(dir, "cache"))
+	cfgFile := filepath.Join(dir, "config", "skint", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(cfgFile), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	cfg := "confirm_before_launch: true\nproviders:\n  - name: ollama\n    type: local\n    base_url: http://localhost:11434\n"
+	if err := os.WriteFile(cfgFile, []byte(cfg), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][]string{
+		{"--no-input"},
+		{"--no-input", "config"},
+		{"--no-input", "config", "ollama"},
+		{"--no-input", "config", "remove", "ollama"},
+		{"--no-input", "uninstall"},
+		{"use", "ollama"},
+	} {
+		// use takes its own flags, so it gets SKINT_NO_INPUT
+		if args[0] == "use" {
+			t.Setenv("SKINT_NO_INPUT", "1")
+		}
+		root := NewRootCmd("test")
+		root.AddCommand(NewConfigCmd(), NewUninstallCmd(), NewUseCmd())
+		root.SetArgs(args)
+		if got, want := root.Run(), errcode.InputRequired.ExitCode(); got != want {
+			t.Errorf("Run(%q) = %d, want %d", args, got, want)
+		}
+	}
+	if _, err := os.Stat(cfgFile); err != nil {
+		t.Errorf("config file gone after refused commands: %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/sammcj/skint/internal/errcode"
+	"github.com/sammcj/skint/internal/launcher"
+	"github.com/sammcj/skint/internal/providers"
+	"golang.org/x/term"
+)
+
+// defaultSandboxImage is the image skint exec --sandbox uses when none is
+// given: Node, so Claude Code and most agents can be installed with npx.
+const defaultSandboxImage = "node:lts-slim"
+
+// sandboxWorkdir is where the current directory is mounted in the container.
+const sandboxWorkdir = "/workspace"
+
+// sandboxRuntimes are the container runtimes --sandbox accepts.
+var sandboxRuntimes = []string{"docker", "podman"}
+
+// sandbox is a parsed --sandbox value: runtime[:image].
+type sandbox struct {
+	runtime string
+	image   string
+}
+
+// parseSandbox parses a --sandbox value such as docker or
+// docker:ghcr.io/me/agent:1.2. The image may contain colons of its own.
+func parseSandbox(spec string) (sandbox, error) {
+	runtime, image, _ := strings.Cut(spec, ":")
+	if !slices.Contains(sandboxRuntimes, runtime) {
+		return sandbox{}, errcode.New(errcode.Usage, "invalid --sandbox %q: use %s, optionally followed by :<image>", spec, strings.Join(sandboxRuntimes, " or "))
+	}
+	if image == "" {
+		image = defaultSandboxImage
+	}
+	return sandbox{runtime: runtime, image: image}, nil
+}
+
+// command returns the container run command that runs argv in s's image
+// with only provider's variables (and extra's) and dir mounted at
+// sandboxWorkdir. The values are passed through the runtime's own
+// environment rather than its arguments, so keys don't show in the process
+// list. Base URLs on localhost are pointed at the host.
+func (s sandbox) command(provider providers.Provider, extra map[string]string, dir string, argv []string) (*exec.Cmd, error) {
+	path, err := exec.LookPath(s.runtime)
+	if err != nil {
+		return nil, fmt.Errorf("%s not found: install it to use --sandbox %s", s.runtime, s.runtime)
+	}
+
+	vars := launcher.LaunchVars(provider, extra)
+	args := []string{"run", "--rm", "-i"}
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		args = append(args, "-t")
+	}
+	usesHost := false
+	for name, value := range vars {
+		if strings.HasSuffix(name, "_BASE_URL") || strings.HasSuffix(name, "_API_BASE") {
+			var local bool
+			if vars[name], local = dockerHostURL(value); local {
+				usesHost = true
+			}
+		}
+	}
+	if usesHost {
+		args = append(args, "--add-host="+dockerHost+":host-gateway")
+	}
+	if runtime.GOOS != "windows" {
+		// Files written to the mount stay the user's; HOME must then be
+		// somewhere that user can write
+		args = append(args, "--user", strconv.Itoa(os.Getuid())+":"+strconv.Itoa(os.Getgid()), "-e", "HOME=/tmp")
+	}
+	for _, name := range slices.Sorted(maps.Keys(vars)) {
+		args = append(args, "-e", name)
+	}
+	args = append(args, "-v", dir+":"+sandboxWorkdir, "-w", sandboxWorkdir, s.image)
+	args = append(args, argv...)
+
+	cmd := exec.Command(path, args...)
+	cmd.Env = launcher.FilterEnvVars(os.Environ(), append(slices.Collect(maps.Keys(vars)), launcher.Conflicting()...)...)
+	for name, value := range vars {
+		cmd.Env = append(cmd.Env, name+"="+value)
+	}
+	return cmd, nil
+}
@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/providers"
+)
+
+func TestParseSandbox(t *testing.T) {
+	tests := []struct {
+		spec string
+		want sandbox
+	}{
+		{"docker", sandbox{runtime: "docker", image: defaultSandboxImage}},
+		{"docker:node:22", sandbox{runtime: "docker", image: "node:22"}},
+		{"podman:ghcr.io/me/agent:1.2", sandbox{runtime: "podman", image: "ghcr.io/me/agent:1.2"}},
+	}
+	for _, tc := range tests {
+		got, err := parseSandbox(tc.spec)
+		if err != nil || got != tc.want {
+			t.Errorf("parseSandbox(%q) = %+v, %v; want %+v", tc.spec, got, err, tc.want)
+		}
+	}
+	for _, spec := range []string{"", "claude", "lxc:ubuntu"} {
+		if _, err := parseSandbox(spec); err == nil {
+			t.Errorf("parseSandbox(%q) should fail", spec)
+		}
+	}
+}
+
+func TestSandboxCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("stub runtime is a shell script")
+	}
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "docker"), []byte("#!/bin/sh\n"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+	t.Setenv("OPENAI_API_KEY", "stale")
+
+	provider, err := providers.FromConfig(&config.Provider{
+		Name: "ollama", Type: config.ProviderTypeLocal, BaseURL: "http://localhost:11434", AuthToken: "ollama",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, err := sandbox{runtime: "docker", image: "node:22"}.command(provider, map[string]string{"HTTPS_PROXY": "http://proxy:3128"}, "/src/app", []string{"claude", "-p", "hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := strings.Join(cmd.Args[1:], " ")
+	for _, want := range []string{
+		"run --rm -i ",
+		"--add-host=host.docker.internal:host-gateway",
+		"-e ANTHROPIC_AUTH_TOKEN ",
+		"-e HTTPS_PROXY ",
+		"-v /src/app:/workspace -w /workspace node:22 claude -p hi",
+	} {
+		if !strings.Contains(args, want) {
+			t.Errorf("args %q missing %q", args, want)
+		}
+	}
+	if strings.Contains(args, "ollama") {
+		t.Errorf("args %q hold a value; values go in the environment", args)
+	}
+	for _, want := range []string{"ANTHROPIC_BASE_URL=http://host.docker.internal:11434", "ANTHROPIC_AUTH_TOKEN=ollama", "HTTPS_PROXY=http://proxy:3128"} {
+		if !slices.Contains(cmd.Env, want) {
+			t.Errorf("env missing %s", want)
+		}
+	}
+	if slices.Contains(cmd.Env, "OPENAI_API_KEY=stale") {
+		t.Error("inherited conflicting variable passed to the runtime")
+	}
+}
@@ -0,0 +1,180 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/errcode"
+	"github.com/sammcj/skint/internal/tmux"
+	"github.com/sammcj/skint/internal/ui"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// NewSessionsCmd creates the sessions command
+func NewSessionsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sessions",
+		Short: "List and attach to named tmux sessions",
+		Long: `'skint use <provider> --session <name>' launches Claude in a tmux session
+called <name>, which keeps running when you detach (Ctrl-b d). These commands
+find the sessions skint started, with the provider each one uses, and attach
+to them again.`,
+		Example: `  skint use zai --session api-work
+  skint sessions
+  skint sessions attach api-work`,
+		Args:        cobra.NoArgs,
+		Annotations: map[string]string{annotationSkipSecrets: "true"},
+		RunE:        runSessionsList,
+	}
+	cmd.AddCommand(NewSessionsListCmd())
+	cmd.AddCommand(NewSessionsAttachCmd())
+	return cmd
+}
+
+// NewSessionsListCmd creates the sessions list command
+func NewSessionsListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:         "list",
+		Aliases:     []string{"ls"},
+		Short:       "List running sessions",
+		Args:        cobra.NoArgs,
+		Annotations: map[string]string{annotationSkipSecrets: "true"},
+		RunE:        runSessionsList,
+	}
+}
+
+// NewSessionsAttachCmd creates the sessions attach command
+func NewSessionsAttachCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "attach <name>",
+		Aliases:           []string{"a"},
+		Short:             "Attach to a running session",
+		Long:              `Attach to a session started with --session, or switch to it from inside tmux.`,
+		Args:              cobra.ExactArgs(1),
+		Annotations:       map[string]string{annotationSkipSecrets: "true"},
+		RunE:              runSessionsAttach,
+		ValidArgsFunction: completeSession,
+	}
+}
+
+func runSessionsList(cmd *cobra.Command, args []string) error {
+	cc := GetContext(cmd)
+	sessions, err := tmux.List()
+	if err != nil {
+		return err
+	}
+
+	switch cc.Cfg.OutputFormat {
+	case config.FormatJSON:
+		if sessions == nil {
+			sessions = []tmux.Session{}
+		}
+		return cc.Output(map[string]any{"sessions": sessions})
+	case config.FormatPlain:
+		for _, s := range sessions {
+			fmt.Printf("%s\t%s\t%d\t%s\t%s\n", s.Name, s.Provider, s.Attached, s.Created.Format(time.RFC3339), s.Dir)
+		}
+		return nil
+	}
+
+	if len(sessions) == 0 {
+		ui.Info("No sessions running. Start one with 'skint use <provider> --session <name>'")
+		return nil
+	}
+	now := time.Now()
+	rows := make([][]string, 0, len(sessions))
+	for _, s := range sessions {
+		attached := ""
+		if s.Attached > 0 {
+			attached = ui.Green("attached")
+			if s.Attached > 1 {
+				attached += " (" + strconv.Itoa(s.Attached) + ")"
+			}
+		}
+		rows = append(rows, []string{s.Name, s.Provider, config.Ago(s.Created, now), s.Dir, attached})
+	}
+	fmt.Println()
+	ui.Table([]string{"SESSION", "PROVIDER", "STARTED", "DIRECTORY", ""}, rows)
+	fmt.Println()
+	return nil
+}
+
+func runSessionsAttach(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	s, err := tmux.Find(name)
+	if err != nil {
+		return err
+	}
+	if s == nil {
+		return errcode.New(errcode.Usage, "no session called %s. Run 'skint sessions' to see them", name)
+	}
+	return tmux.Attach(name)
+}
+
+// completeSession completes the names of running sessions.
+func completeSession(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	sessions, _ := tmux.List()
+	var names []cobra.Completion
+	for _, s := range sessions {
+		if strings.HasPrefix(s.Name, toComplete) {
+			names = append(names, cobra.CompletionWithDesc(s.Name, s.Provider))
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// sessionArgv returns the command line a session runs: skint at exe with
+// this run's --config, --resume and --continue, then 'use' with useArgs.
+func (cc *CmdContext) sessionArgv(exe string, useArgs []string) []string {
+	argv := []string{exe}
+	if cc.cfgFile != "" {
+		argv = append(argv, "--config", cc.cfgFile)
+	}
+	// ClaudeExtraArgs holds skint's own --resume and --continue flags
+	argv = append(argv, cc.ClaudeExtraArgs...)
+	return append(append(argv, "use"), useArgs...)
+}
+
+// startSession starts 'skint use' with useArgs in a tmux session called name
+// and attaches to it, unless output is for scripts, there's no terminal to
+// attach or --no-input is set.
+func (cc *CmdContext) startSession(name, provider string, useArgs []string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find skint: %w", err)
+	}
+	argv := cc.sessionArgv(exe, useArgs)
+	dir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+	// The session's skint launches even with auto_launch_after_use off
+	env := []string{"SKINT_AUTO_LAUNCH_AFTER_USE=true"}
+	if err := tmux.Start(name, provider, dir, env, argv); err != nil {
+		return err
+	}
+
+	switch cc.Cfg.OutputFormat {
+	case config.FormatJSON:
+		return cc.Output(map[string]any{"session": name, "provider": provider, "dir": dir})
+	case config.FormatPlain:
+		fmt.Println(name)
+		return nil
+	}
+	if cc.NoInput || !term.IsTerminal(int(os.Stdin.Fd())) {
+		if !cc.Quiet {
+			ui.Success("Started session %s with %s", ui.Yellow(name), provider)
+			ui.NextSteps([]string{"Attach to it: " + ui.Green("skint sessions attach "+name)})
+		}
+		return nil
+	}
+	return tmux.Attach(name)
+}
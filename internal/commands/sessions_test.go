@@ -0,0 +1,15 @@
+package commands
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSessionArgvForwardsResume(t *testing.T) {
+	cc := &CmdContext{cfgFile: "/tmp/skint.yaml", ClaudeExtraArgs: []string{"--resume", "abc-123"}}
+	got := cc.sessionArgv("/usr/bin/skint", []string{"zai", "--model", "glm-5"})
+	want := []string{"/usr/bin/skint", "--config", "/tmp/skint.yaml", "--resume", "abc-123", "use", "zai", "--model", "glm-5"}
+	if !slices.Equal(got, want) {
+		t.Errorf("sessionArgv = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,260 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/errcode"
+	"github.com/sammcj/skint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// pathShells are the shells setup-path can add the bin directory to PATH for.
+var pathShells = []string{"bash", "zsh", "fish", "powershell"}
+
+// Markers around the lines setup-path adds, so they can be found again.
+const (
+	pathBlockStart = "# >>> skint setup-path >>>"
+	pathBlockEnd   = "# <<< skint setup-path <<<"
+)
+
+// NewSetupPathCmd creates the setup-path command
+func NewSetupPathCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "setup-path",
+		Short: "Add the bin directory for generated scripts to PATH",
+		Long: `Add the bin directory, where generate-scripts writes skint-<provider>
+scripts, to PATH in your shell's startup file: ~/.bashrc (~/.bash_profile on
+macOS), ~/.zshrc, fish's config.fish or the PowerShell profile.
+
+The shell is detected from $SHELL (PowerShell on Windows); --shell picks
+another. The lines are added between marker comments, so running it again
+changes nothing, and --undo removes them.`,
+		Example: `  skint setup-path
+  skint setup-path --shell fish
+  skint setup-path --dry-run
+  skint setup-path --undo`,
+		Args:        cobra.NoArgs,
+		Annotations: map[string]string{annotationSkipSecrets: "true"},
+		RunE:        runSetupPath,
+	}
+	cmd.Flags().String("shell", "", "shell to set up: "+strings.Join(pathShells, ", ")+" (default: detected)")
+	cmd.Flags().Bool("undo", false, "remove the lines added before")
+	cmd.Flags().Bool("dry-run", false, "show the change without writing it")
+	_ = cmd.RegisterFlagCompletionFunc("shell", cobra.FixedCompletions(pathShells, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+func runSetupPath(cmd *cobra.Command, args []string) error {
+	cc := GetContext(cmd)
+	shell, _ := cmd.Flags().GetString("shell")
+	undo, _ := cmd.Flags().GetBool("undo")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	if shell == "" {
+		shell = detectShell(os.Getenv("SHELL"))
+		if shell == "" {
+			return errcode.New(errcode.Usage, "couldn't tell your shell from $SHELL: pick one with --shell (%s)", strings.Join(pathShells, ", "))
+		}
+	} else if !slices.Contains(pathShells, shell) {
+		return errcode.New(errcode.Usage, "invalid --shell %q (valid: %s)", shell, strings.Join(pathShells, ", "))
+	}
+
+	binDir, err := config.GetBinDir()
+	if err != nil {
+		return fmt.Errorf("failed to get bin directory: %w", err)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to get home directory: %w", err)
+	}
+	file := rcFile(shell, home)
+
+	existing, err := os.ReadFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	block := pathBlock(shell, binDir)
+	var data string
+	var changed bool
+	switch {
+	case undo:
+		data, changed = withoutPathBlock(string(existing))
+	case !strings.Contains(string(existing), pathBlockStart) && slices.Contains(filepath.SplitList(os.Getenv("PATH")), binDir):
+		// Already on PATH from somewhere else
+		data = string(existing)
+	default:
+		data, changed = withPathBlock(string(existing), block)
+	}
+
+	if changed && !dryRun {
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(file), err)
+		}
+		mode := os.FileMode(0o644)
+		if info, err := os.Stat(file); err == nil {
+			mode = info.Mode().Perm()
+		}
+		if err := os.WriteFile(file, []byte(data), mode); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file, err)
+		}
+	}
+
+	switch cc.Cfg.OutputFormat {
+	case config.FormatJSON:
+		return cc.Output(map[string]any{
+			"shell":   shell,
+			"file":    file,
+			"bin_dir": binDir,
+			"undo":    undo,
+			"changed": changed,
+			"dry_run": dryRun,
+		})
+	case config.FormatPlain:
+		fmt.Println(file)
+		return nil
+	}
+
+	switch {
+	case !changed && undo:
+		ui.Info("Nothing to remove: %s has no lines from skint setup-path", file)
+	case !changed && !strings.Contains(string(existing), pathBlockStart):
+		ui.Success("%s is already on PATH", binDir)
+	case !changed:
+		ui.Success("%s already adds %s to PATH", file, binDir)
+	case dryRun && undo:
+		ui.Info("Would remove the skint setup-path lines from %s", file)
+	case dryRun:
+		ui.Info("Would add to %s:", file)
+		ui.Dim("%s\n", block)
+	case undo:
+		ui.Success("Removed the skint setup-path lines from %s", file)
+	default:
+		ui.Success("Added %s to PATH in %s", binDir, file)
+		ui.NextSteps([]string{"Open a new terminal, or run: " + ui.Green(reloadCommand(shell, file))})
+	}
+	return nil
+}
+
+// detectShell returns the pathShells entry for the shell in $SHELL, or
+// PowerShell on Windows, or "" if it isn't one of them.
+func detectShell(shellEnv string) string {
+	if runtime.GOOS == "windows" {
+		return "powershell"
+	}
+	name := filepath.Base(shellEnv)
+	if name == "pwsh" {
+		return "powershell"
+	}
+	if slices.Contains(pathShells, name) {
+		return name
+	}
+	return ""
+}
+
+// rcFile returns the startup file setup-path changes for shell.
+func rcFile(shell, home string) string {
+	switch shell {
+	case "zsh":
+		if dir := os.Getenv("ZDOTDIR"); dir != "" {
+			return filepath.Join(dir, ".zshrc")
+		}
+		return filepath.Join(home, ".zshrc")
+	case "fish":
+		dir := os.Getenv("XDG_CONFIG_HOME")
+		if dir == "" {
+			dir = filepath.Join(home, ".config")
+		}
+		return filepath.Join(dir, "fish", "config.fish")
+	case "powershell":
+		if runtime.GOOS == "windows" {
+			return filepath.Join(home, "Documents", "PowerShell", "Microsoft.PowerShell_profile.ps1")
+		}
+		return filepath.Join(home, ".config", "powershell", "Microsoft.PowerShell_profile.ps1")
+	}
+	// macOS terminals start login shells, which read .bash_profile
+	if runtime.GOOS == "darwin" {
+		return filepath.Join(home, ".bash_profile")
+	}
+	return filepath.Join(home, ".bashrc")
+}
+
+// pathBlock returns the lines, markers included, that add binDir to PATH
+// in shell.
+func pathBlock(shell, binDir string) string {
+	var line string
+	switch shell {
+	case "fish":
+		line = "fish_add_path -g '" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(binDir) + "'"
+	case "powershell":
+		line = "$env:PATH = '" + strings.ReplaceAll(binDir, "'", "''") + "' + [IO.Path]::PathSeparator + $env:PATH"
+	default:
+		line = `export PATH="` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "`", "\\`").Replace(binDir) + `:$PATH"`
+	}
+	return pathBlockStart + "\n" +
+		"# Scripts from skint generate-scripts; skint setup-path --undo removes this\n" +
+		line + "\n" +
+		pathBlockEnd
+}
+
+// withPathBlock returns existing with its setup-path lines replaced by
+// block, or block added at the end, and whether that changed it.
+func withPathBlock(existing, block string) (string, bool) {
+	if start, end, ok := findPathBlock(existing); ok {
+		if existing[start:end] == block {
+			return existing, false
+		}
+		return existing[:start] + block + existing[end:], true
+	}
+	var b strings.Builder
+	if existing != "" {
+		b.WriteString(strings.TrimRight(existing, "\n") + "\n\n")
+	}
+	b.WriteString(block + "\n")
+	return b.String(), true
+}
+
+// withoutPathBlock returns existing without its setup-path lines and the
+// blank lines before them, and whether it had them.
+func withoutPathBlock(existing string) (string, bool) {
+	start, end, ok := findPathBlock(existing)
+	if !ok {
+		return existing, false
+	}
+	before := strings.TrimRight(existing[:start], "\n")
+	after := strings.TrimPrefix(existing[end:], "\n")
+	if before == "" {
+		return after, true
+	}
+	if after == "" {
+		return before + "\n", true
+	}
+	return before + "\n" + after, true
+}
+
+// findPathBlock returns where the setup-path lines are in s, from the start
+// marker to the end of the end marker.
+func findPathBlock(s string) (start, end int, ok bool) {
+	start = strings.Index(s, pathBlockStart)
+	if start < 0 {
+		return 0, 0, false
+	}
+	n := strings.Index(s[start:], pathBlockEnd)
+	if n < 0 {
+		return 0, 0, false
+	}
+	return start, start + n + len(pathBlockEnd), true
+}
+
+// reloadCommand returns the command that applies file to the current shell.
+func reloadCommand(shell, file string) string {
+	if shell == "powershell" {
+		return ". $PROFILE"
+	}
+	return "source " + file
+}
@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestPathBlockRoundTrip(t *testing.T) {
+	block := pathBlock("bash", "/home/me/.local/bin")
+	for _, existing := range []string{"", "alias ll='ls -l'\n", "alias ll='ls -l'"} {
+		added, changed := withPathBlock(existing, block)
+		if !changed || !strings.Contains(added, `export PATH="/home/me/.local/bin:$PATH"`) {
+			t.Fatalf("withPathBlock(%q) = %q, %v", existing, added, changed)
+		}
+		if again, changed := withPathBlock(added, block); changed || again != added {
+			t.Errorf("adding the block twice changed %q to %q", added, again)
+		}
+		removed, changed := withoutPathBlock(added)
+		if want := strings.TrimRight(existing, "\n"); !changed || strings.TrimRight(removed, "\n") != want {
+			t.Errorf("withoutPathBlock(%q) = %q, %v; want %q", added, removed, changed, want)
+		}
+	}
+
+	// A moved bin directory replaces the block rather than adding another
+	added, _ := withPathBlock("# rc\n", block)
+	moved, changed := withPathBlock(added+"alias x=y\n", pathBlock("bash", "/opt/bin"))
+	if !changed || strings.Count(moved, pathBlockStart) != 1 || !strings.Contains(moved, "/opt/bin") || !strings.HasSuffix(moved, "alias x=y\n") {
+		t.Errorf("replacing the block gave %q", moved)
+	}
+
+	if _, changed := withoutPathBlock("# rc\n"); changed {
+		t.Error("withoutPathBlock changed a file without the block")
+	}
+}
+
+func TestPathBlockQuoting(t *testing.T) {
+	tests := map[string]string{
+		"bash":       `export PATH="/a \"b\" \$c:$PATH"`,
+		"fish":       `fish_add_path -g '/a "b" $c'`,
+		"powershell": `$env:PATH = '/a "b" $c' + [IO.Path]::PathSeparator + $env:PATH`,
+	}
+	for shell, want := range tests {
+		if got := pathBlock(shell, `/a "b" $c`); !strings.Contains(got, "\n"+want+"\n") {
+			t.Errorf("pathBlock(%s) = %q, want a line %q", shell, got, want)
+		}
+	}
+}
+
+func TestDetectShell(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("always PowerShell on Windows")
+	}
+	tests := map[string]string{
+		"/bin/bash":          "bash",
+		"/usr/bin/zsh":       "zsh",
+		"/opt/homebrew/fish": "fish",
+		"/usr/bin/pwsh":      "powershell",
+		"/bin/tcsh":          "",
+		"":                   "",
+	}
+	for env, want := range tests {
+		if got := detectShell(env); got != want {
+			t.Errorf("detectShell(%q) = %q, want %q", env, got, want)
+		}
+	}
+}
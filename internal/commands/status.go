@@ -2,22 +2,30 @@ package commands
 
 import (
 	"fmt"
-	"os/exec"
+	"os"
 	"runtime"
 
 	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/launcher"
 	"github.com/sammcj/skint/internal/ui"
 	"github.com/spf13/cobra"
 )
 
 // NewStatusCmd creates the status command
 func NewStatusCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "status",
 		Short: "Show installation status",
-		Long:  "Display information about the current Skint installation.",
-		RunE:  runStatus,
+		Long: `Display information about the current Skint installation, including
+where claude was found and whether its version is one skint supports.
+
+Also checks that the config file, the secrets file and their directories are
+private to you (0600 files, 0700 directories) and not symlinks. Use --fix to
+restrict any that are group or world accessible.`,
+		RunE: runStatus,
 	}
+	cmd.Flags().Bool("fix", false, "restrict config and secrets permissions to owner-only")
+	return cmd
 }
 
 func runStatus(cmd *cobra.Command, args []string) error {
@@ -30,8 +38,24 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	cacheDir, _ := config.GetCacheDir()
 	binDir, _ := config.GetBinDir()
 
-	// Check if Claude is installed
-	claudePath, claudeErr := exec.LookPath("claude")
+	// Check if Claude is installed, and its version
+	claude, claudeErr := launcher.DetectClaude()
+
+	// Check file permissions, fixing them first if asked
+	fix, _ := cmd.Flags().GetBool("fix")
+	issues, err := cc.ConfigMgr.CheckPermissions()
+	if err != nil {
+		return err
+	}
+	var fixed int
+	if fix && len(issues) > 0 {
+		if fixed, err = config.FixPermissions(issues); err != nil {
+			return err
+		}
+		if issues, err = cc.ConfigMgr.CheckPermissions(); err != nil {
+			return err
+		}
+	}
 
 	// JSON output
 	if cc.Cfg.OutputFormat == config.FormatJSON {
@@ -41,20 +65,28 @@ func runStatus(cmd *cobra.Command, args []string) error {
 			"data_dir":         dataDir,
 			"cache_dir":        cacheDir,
 			"bin_dir":          binDir,
+			"system_configs":   cc.ConfigMgr.SystemConfigFiles(),
 			"provider_count":   len(cc.Cfg.Providers),
 			"default_provider": cc.Cfg.DefaultProvider,
 			"color_enabled":    cc.Cfg.ColorEnabled,
 			"output_format":    cc.Cfg.OutputFormat,
 			"go_version":       runtime.Version(),
 			"platform":         fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
+			"permissions":      permissionsJSON(issues),
+		}
+		if fix {
+			result["permissions_fixed"] = fixed
 		}
 
 		if claudeErr == nil {
 			result["claude_installed"] = true
-			result["claude_path"] = claudePath
+			result["claude_path"] = claude.Path
+			result["claude_version"] = claude.Version
+			result["claude_supported"] = claude.Supported()
 		} else {
 			result["claude_installed"] = false
 		}
+		result["claude_min_version"] = launcher.MinClaudeVersion
 
 		return cc.Output(result)
 	}
@@ -64,6 +96,43 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Version: %s\n", version)
 		fmt.Printf("Config: %s\n", configDir)
 		fmt.Printf("Providers: %d\n", len(cc.Cfg.Providers))
+		for _, issue := range issues {
+			fmt.Printf("Permissions: %s %s\n", issue.Path, issue.Problem())
+		}
+		return nil
+	}
+
+	// Table output
+	if cc.Cfg.OutputFormat == config.FormatTable {
+		rows := [][]string{
+			{"version", version},
+			{"config_dir", configDir},
+		}
+		for _, f := range cc.ConfigMgr.SystemConfigFiles() {
+			rows = append(rows, []string{"system_config", f})
+		}
+		rows = append(rows,
+			[]string{"data_dir", dataDir},
+			[]string{"cache_dir", cacheDir},
+			[]string{"bin_dir", binDir},
+			[]string{"platform", fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH)},
+			[]string{"providers", fmt.Sprint(len(cc.Cfg.Providers))},
+		)
+		if cc.Cfg.DefaultProvider != "" {
+			rows = append(rows, []string{"default_provider", cc.Cfg.DefaultProvider})
+		}
+		if claudeErr == nil {
+			rows = append(rows, []string{"claude_path", claude.Path})
+			if claude.Version != "" {
+				rows = append(rows, []string{"claude_version", claude.Version})
+			}
+		} else {
+			rows = append(rows, []string{"claude_path", "not found"})
+		}
+		for _, issue := range issues {
+			rows = append(rows, []string{"permissions", issue.Path + " " + issue.Problem()})
+		}
+		ui.TableTo(os.Stdout, []string{"SETTING", "VALUE"}, rows)
 		return nil
 	}
 
@@ -74,6 +143,9 @@ func runStatus(cmd *cobra.Command, args []string) error {
 
 	ui.Log("  Version:     %s", ui.Bold(version))
 	ui.Log("  Config:      %s", configDir)
+	for _, f := range cc.ConfigMgr.SystemConfigFiles() {
+		ui.Log("  System:      %s", f)
+	}
 	ui.Log("  Data:        %s", dataDir)
 	ui.Log("  Cache:       %s", cacheDir)
 	ui.Log("  Bin:         %s", binDir)
@@ -86,10 +158,17 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		ui.Log("  Default:     %s", ui.Yellow(cc.Cfg.DefaultProvider))
 	}
 
-	if claudeErr == nil {
-		ui.Log("  Claude:      %s (%s)", ui.Green("installed"), claudePath)
-	} else {
+	switch {
+	case claudeErr != nil:
 		ui.Log("  Claude:      %s", ui.Red("not found"))
+		ui.Log("               %s", ui.DimString(claude.UpdateHint()))
+	case !claude.Supported():
+		ui.Log("  Claude:      %s (%s)", ui.Yellow(claude.Version+", older than "+launcher.MinClaudeVersion), claude.Path)
+		ui.Log("               %s", ui.DimString(claude.UpdateHint()))
+	case claude.Version != "":
+		ui.Log("  Claude:      %s %s (%s)", ui.Green("installed"), claude.Version, claude.Path)
+	default:
+		ui.Log("  Claude:      %s (%s)", ui.Green("installed"), claude.Path)
 	}
 
 	// Keyring status
@@ -99,7 +178,45 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		ui.Log("  Keyring:     %s (using file store)", ui.Yellow("unavailable"))
 	}
 
+	// Permissions
+	switch {
+	case runtime.GOOS == "windows":
+		// Unix permission bits don't apply
+	case len(issues) == 0 && fixed > 0:
+		ui.Log("  Permissions: %s (fixed %d)", ui.Green("ok"), fixed)
+	case len(issues) == 0:
+		ui.Log("  Permissions: %s", ui.Green("ok"))
+	default:
+		ui.Log("  Permissions: %s", ui.Red(fmt.Sprintf("%d problem(s)", len(issues))))
+		var fixable, symlinks bool
+		for _, issue := range issues {
+			ui.Log("    %s %s", issue.Path, ui.DimString(issue.Problem()))
+			fixable = fixable || !issue.Symlink
+			symlinks = symlinks || issue.Symlink
+		}
+		if fixable {
+			ui.Log("    Run %s to restrict them", ui.Green("skint status --fix"))
+		}
+		if symlinks {
+			ui.Log("    Replace symlinks with the real files; skint will not follow them")
+		}
+	}
+
 	fmt.Println()
 
 	return nil
 }
+
+// permissionsJSON converts permission issues for JSON output.
+func permissionsJSON(issues []config.PermissionIssue) []map[string]any {
+	out := make([]map[string]any, 0, len(issues))
+	for _, issue := range issues {
+		out = append(out, map[string]any{
+			"path":     issue.Path,
+			"mode":     fmt.Sprintf("%04o", issue.Mode),
+			"expected": fmt.Sprintf("%04o", issue.Want),
+			"symlink":  issue.Symlink,
+		})
+	}
+	return out
+}
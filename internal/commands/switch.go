@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/errcode"
+	"github.com/sammcj/skint/internal/tui"
+	"github.com/sammcj/skint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// NewSwitchCmd creates the switch command
+func NewSwitchCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "switch [query]",
+		Aliases: []string{"sw"},
+		Short:   "Pick a provider with a fuzzy finder and launch Claude",
+		Long: `Show a small fuzzy finder over the configured providers, most recently
+used first, and launch Claude with the one picked. Type to filter, use the
+arrow keys (or ctrl+n/ctrl+p) to move and enter to launch; esc cancels.
+
+A query fills in the filter to start with.`,
+		Example: `  skint switch
+  skint switch gl`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runSwitch,
+	}
+}
+
+func runSwitch(cmd *cobra.Command, args []string) error {
+	cc := GetContext(cmd)
+	if cc.NoInput || !tui.CheckTerminal() {
+		return errcode.New(errcode.InputRequired, "skint switch needs a terminal. Use 'skint use <provider>' instead")
+	}
+
+	usage, err := config.LoadUsage()
+	if err != nil && cc.Verbose {
+		ui.Warning("Failed to read provider usage: %v", err)
+	}
+	name, err := tui.RunSwitcher(cc.Cfg, usage, strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+	switch name {
+	case "":
+		return nil // cancelled
+	case "native":
+		return cc.LaunchClaude("")
+	}
+	return cc.LaunchClaude(name)
+}
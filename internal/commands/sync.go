@@ -0,0 +1,288 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/gitsync"
+	"github.com/sammcj/skint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// NewSyncCmd creates the sync command
+func NewSyncCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Sync the config between machines via git",
+		Long: `Keep the config in sync between machines through a git repository.
+
+'skint sync push' commits the config to the remote and 'skint sync pull'
+applies the remote config here. API keys are never synced: keys, key
+references and the sync settings themselves stay on each machine, so set up
+keys with 'skint config <provider>' after pulling a new provider.
+
+The remote is given with --remote (and remembered as sync_remote), and the
+branch defaults to main (sync_branch). Pull refuses to overwrite local
+changes made since the last sync when the remote has changed too.`,
+		Example: `  skint sync push --remote git@github.com:me/skint-config.git
+  skint sync pull
+  skint sync pull --force     # Discard local changes, take the remote`,
+	}
+
+	cmd.PersistentFlags().String("remote", "", "git remote URL (saved as sync_remote)")
+
+	cmd.AddCommand(NewSyncPushCmd())
+	cmd.AddCommand(NewSyncPullCmd())
+
+	return cmd
+}
+
+// NewSyncPushCmd creates the sync push command
+func NewSyncPushCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "push",
+		Short: "Push the config to the sync remote",
+		Args:  cobra.NoArgs,
+		RunE:  runSyncPush,
+	}
+	cmd.Flags().Bool("force", false, "overwrite changes pushed from another machine")
+	return cmd
+}
+
+// NewSyncPullCmd creates the sync pull command
+func NewSyncPullCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pull",
+		Short: "Apply the config from the sync remote",
+		Args:  cobra.NoArgs,
+		RunE:  runSyncPull,
+	}
+	cmd.Flags().Bool("force", false, "discard local changes made since the last sync")
+	return cmd
+}
+
+func runSyncPush(cmd *cobra.Command, args []string) error {
+	cc := GetContext(cmd)
+	force, _ := cmd.Flags().GetBool("force")
+
+	repo, remote, err := openSyncRepo(cc, cmd)
+	if err != nil {
+		return err
+	}
+
+	changed, err := repo.RemoteChanged()
+	if err != nil {
+		return err
+	}
+	local, err := cc.ConfigMgr.ExportShared()
+	if err != nil {
+		return err
+	}
+	if changed && !force {
+		if current, ok, _ := repo.Remote(); !ok || !bytes.Equal(current, local) {
+			return fmt.Errorf("the remote config has changed since this machine last synced. Run 'skint sync pull' first, or 'skint sync push --force' to overwrite it")
+		}
+	}
+
+	host, _ := os.Hostname()
+	pushed, err := repo.Push(local, "Update skint config from "+host)
+	if err != nil {
+		return err
+	}
+	if err := rememberSyncRemote(cc, remote); err != nil {
+		return err
+	}
+
+	// JSON output
+	if cc.Cfg.OutputFormat == config.FormatJSON {
+		return cc.Output(map[string]any{
+			"remote": remote,
+			"pushed": pushed,
+		})
+	}
+
+	// Plain output
+	if cc.Cfg.OutputFormat == config.FormatPlain {
+		fmt.Println(pushed)
+		return nil
+	}
+
+	// Human-readable output
+	if pushed {
+		ui.Success("Pushed config to %s", remote)
+	} else {
+		ui.Success("Remote is already up to date")
+	}
+	return nil
+}
+
+func runSyncPull(cmd *cobra.Command, args []string) error {
+	cc := GetContext(cmd)
+	force, _ := cmd.Flags().GetBool("force")
+
+	repo, remote, err := openSyncRepo(cc, cmd)
+	if err != nil {
+		return err
+	}
+
+	data, ok, err := repo.Remote()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("nothing to pull: %s has no config yet. Run 'skint sync push' first", remote)
+	}
+
+	local, err := cc.ConfigMgr.ExportShared()
+	if err != nil {
+		return err
+	}
+	base, synced, err := repo.Synced()
+	if err != nil {
+		return err
+	}
+
+	var updated bool
+	var missing []string
+	switch {
+	case bytes.Equal(local, data):
+		// Already identical, e.g. the same change made on both machines
+	case synced && bytes.Equal(base, data):
+		// Nothing new on the remote; local changes are left for push
+	default:
+		localChanged := cc.CfgFileExists()
+		if synced {
+			localChanged = !bytes.Equal(local, base)
+		}
+		if localChanged && !force {
+			return fmt.Errorf("conflict: the config changed both here and on the remote since the last sync. Use 'skint sync pull --force' to take the remote config, or 'skint sync push --force' to keep this one")
+		}
+		if missing, err = applySyncedConfig(cc, data); err != nil {
+			return err
+		}
+		updated = true
+	}
+
+	if err := repo.MarkSynced(); err != nil {
+		return err
+	}
+	if err := rememberSyncRemote(cc, remote); err != nil {
+		return err
+	}
+
+	// JSON output
+	if cc.Cfg.OutputFormat == config.FormatJSON {
+		return cc.Output(map[string]any{
+			"remote":       remote,
+			"updated":      updated,
+			"missing_keys": missing,
+		})
+	}
+
+	// Plain output
+	if cc.Cfg.OutputFormat == config.FormatPlain {
+		fmt.Println(updated)
+		return nil
+	}
+
+	// Human-readable output
+	if !updated {
+		ui.Success("Config is already up to date")
+		if synced && !bytes.Equal(local, base) {
+			ui.Dim("Local changes not yet pushed - run 'skint sync push'\n")
+		}
+		return nil
+	}
+	ui.Success("Pulled config from %s", remote)
+	if len(missing) > 0 {
+		steps := make([]string, 0, len(missing))
+		for _, name := range missing {
+			steps = append(steps, "Set the API key for "+name+": "+ui.Green("skint config "+name))
+		}
+		ui.NextSteps(steps)
+	}
+	return nil
+}
+
+// openSyncRepo opens the local sync repository for the remote given by
+// --remote or sync_remote, and fetches it.
+func openSyncRepo(cc *CmdContext, cmd *cobra.Command) (*gitsync.Repo, string, error) {
+	remote, _ := cmd.Flags().GetString("remote")
+	if remote == "" {
+		remote = cc.Cfg.SyncRemote
+	}
+	if remote == "" {
+		return nil, "", fmt.Errorf("no sync remote set. Use --remote <url> or set sync_remote in the config")
+	}
+
+	dataDir, err := config.GetDataDir()
+	if err != nil {
+		return nil, "", err
+	}
+	repo, err := gitsync.Open(filepath.Join(dataDir, "sync"), remote, cc.Cfg.SyncBranchName())
+	if err != nil {
+		return nil, "", err
+	}
+	if err := repo.Fetch(); err != nil {
+		return nil, "", err
+	}
+	return repo, remote, nil
+}
+
+// rememberSyncRemote saves remote as sync_remote if it is new.
+func rememberSyncRemote(cc *CmdContext, remote string) error {
+	if cc.Cfg.SyncRemote == remote {
+		return nil
+	}
+	cc.Cfg.SyncRemote = remote
+	if err := cc.SaveConfig(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
+
+// warnDroppedRedacted leaves out of cfg the MCP server values that are still
+// redacted, warning about each.
+func warnDroppedRedacted(cfg *config.Config) {
+	for _, value := range cfg.DropRedacted() {
+		ui.Warning("Left out %s: it was redacted and isn't set here", value)
+	}
+}
+
+// applySyncedConfig replaces the config with one pulled from the sync remote,
+// keeping this machine's key references and sync settings. Returns the
+// providers that need an API key set up here.
+func applySyncedConfig(cc *CmdContext, data []byte) ([]string, error) {
+	cfg, err := config.ParseConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("remote config: %w", err)
+	}
+	cfg.RestoreLocalSettings(cc.Cfg)
+	warnDroppedRedacted(cfg)
+
+	// New providers may already have a key stored here
+	var missing []string
+	for _, p := range cfg.Providers {
+		if !p.NeedsAPIKey() || p.APIKeyRef != "" {
+			continue
+		}
+		if _, err := cc.SecretsMgr.Retrieve(p.Name); err == nil {
+			p.APIKeyRef = cc.SecretsMgr.Reference(p.Name)
+		} else {
+			missing = append(missing, p.Name)
+		}
+	}
+
+	// Output stays in the format asked for on this run, not the remote's
+	outputFormat := cc.Cfg.OutputFormat
+	cc.ConfigMgr.Replace(cfg)
+	if err := cc.SaveConfig(); err != nil {
+		return nil, fmt.Errorf("failed to save config: %w", err)
+	}
+	cc.Cfg = cfg
+	cc.ConfigMgr.Override("OutputFormat", outputFormat)
+	return missing, nil
+}
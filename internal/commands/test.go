@@ -1,11 +1,18 @@
 package commands
 
 import (
+	"cmp"
+	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/errcode"
+	"github.com/sammcj/skint/internal/httpretry"
+	"github.com/sammcj/skint/internal/models"
 	"github.com/sammcj/skint/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -16,8 +23,12 @@ func NewTestCmd() *cobra.Command {
 		Use:   "test [provider]",
 		Short: "Test provider connectivity",
 		Long: `Test connectivity to LLM providers by making HTTP requests
-to their API endpoints.`,
-		RunE: runTest,
+to their API endpoints.
+
+For llama.cpp, llama-server's /health and /props endpoints are used
+instead, to report whether its model is loaded and its context size.`,
+		RunE:              runTest,
+		ValidArgsFunction: completeProvider,
 	}
 }
 
@@ -29,7 +40,7 @@ func runTest(cmd *cobra.Command, args []string) error {
 		// Test specific provider
 		p := cc.Cfg.GetProvider(args[0])
 		if p == nil {
-			return fmt.Errorf("provider not found: %s", args[0])
+			return errcode.New(errcode.ProviderNotFound, "provider not found: %s", args[0])
 		}
 		providersToTest = []*config.Provider{p}
 	} else {
@@ -45,31 +56,63 @@ func runTest(cmd *cobra.Command, args []string) error {
 	// JSON output
 	if cc.Cfg.OutputFormat == config.FormatJSON {
 		results := make([]map[string]any, 0, len(providersToTest))
-
+		fail := 0
 		for _, p := range providersToTest {
-			result := testProvider(p)
-			results = append(results, map[string]any{
+			result := testProvider(p, cc.Cfg.NetworkTimeout())
+			if !result.reachable {
+				fail++
+			}
+			entry := map[string]any{
 				"name":        p.Name,
 				"reachable":   result.reachable,
 				"status_code": result.statusCode,
 				"error":       result.errMsg,
-			})
+			}
+			if result.server != nil {
+				entry["server"] = result.server
+			}
+			results = append(results, entry)
 		}
 
-		return cc.Output(map[string]any{"results": results})
+		if err := cc.Output(map[string]any{"results": results}); err != nil {
+			return err
+		}
+		return unreachableError(fail)
 	}
 
 	// Plain output
 	if cc.Cfg.OutputFormat == config.FormatPlain {
+		fail := 0
 		for _, p := range providersToTest {
-			result := testProvider(p)
+			result := testProvider(p, cc.Cfg.NetworkTimeout())
 			status := "ok"
 			if !result.reachable {
 				status = "fail"
+				fail++
 			}
 			fmt.Printf("%s: %s\n", p.Name, status)
 		}
-		return nil
+		return unreachableError(fail)
+	}
+
+	// Table output
+	if cc.Cfg.OutputFormat == config.FormatTable {
+		rows := make([][]string, 0, len(providersToTest))
+		fail := 0
+		for _, p := range providersToTest {
+			result := testProvider(p, cc.Cfg.NetworkTimeout())
+			status, code := "ok", "-"
+			if !result.reachable {
+				status = "fail"
+				fail++
+			}
+			if result.statusCode != 0 {
+				code = fmt.Sprint(result.statusCode)
+			}
+			rows = append(rows, []string{p.Name, status, code, cmp.Or(result.errMsg, result.detail())})
+		}
+		ui.TableTo(os.Stdout, []string{"NAME", "STATUS", "HTTP", "DETAIL"}, rows)
+		return unreachableError(fail)
 	}
 
 	// Human-readable output
@@ -100,10 +143,17 @@ func runTest(cmd *cobra.Command, args []string) error {
 		}
 
 		// Test connectivity
-		result := testProvider(p)
+		result := testProvider(p, cc.Cfg.NetworkTimeout())
 
 		if result.reachable {
-			fmt.Printf("  Testing %-15s %s %s\n", p.Name, ui.Green(ui.Sym.OK+" reachable"), ui.DimString(fmt.Sprintf("(HTTP %d)", result.statusCode)))
+			colour, detail := ui.Green, fmt.Sprintf("(HTTP %d)", result.statusCode)
+			if result.server != nil {
+				detail = fmt.Sprintf("(HTTP %d, %s)", result.statusCode, result.server)
+				if !result.server.Loaded {
+					colour = ui.Yellow
+				}
+			}
+			fmt.Printf("  Testing %-15s %s %s\n", p.Name, colour(ui.Sym.OK+" reachable"), ui.DimString(detail))
 			ok++
 		} else {
 			if result.errMsg != "" {
@@ -121,16 +171,51 @@ func runTest(cmd *cobra.Command, args []string) error {
 		ui.Dim(", %d skipped\n", skip)
 	}
 
-	return nil
+	return unreachableError(fail)
+}
+
+// unreachableError returns an error if any of the providers tested failed,
+// so skint test exits non-zero.
+func unreachableError(failed int) error {
+	if failed == 0 {
+		return nil
+	}
+	if failed == 1 {
+		return errcode.New(errcode.ProviderUnreachable, "1 provider failed the test")
+	}
+	return errcode.New(errcode.ProviderUnreachable, "%d providers failed the test", failed)
 }
 
 type testResult struct {
 	reachable  bool
 	statusCode int
 	errMsg     string
+	// server is what llama-server reported, for llama.cpp
+	server *models.LlamaCppStatus
 }
 
-func testProvider(p *config.Provider) testResult {
+// detail describes what the server reported, or "".
+func (r testResult) detail() string {
+	if r.server == nil {
+		return ""
+	}
+	return r.server.String()
+}
+
+// testProvider requests p's endpoint, giving up after timeout. llama.cpp's
+// health endpoint is asked instead when it answers, for the model's state.
+func testProvider(p *config.Provider, timeout time.Duration) testResult {
+	if p.Name == "llamacpp" && p.BaseURL != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		status, err := models.LlamaCppServerStatus(ctx, p.BaseURL)
+		if err == nil {
+			slog.Debug("provider test", "provider", p.Name, "health", status.StatusCode, "loaded", status.Loaded)
+			return testResult{reachable: true, statusCode: status.StatusCode, server: &status}
+		}
+		slog.Debug("llama.cpp health check failed, trying the base URL", "err", err)
+	}
+
 	testURL := p.BaseURL
 	if testURL == "" {
 		if p.Type == config.ProviderTypeBuiltin && p.Name == "native" {
@@ -142,18 +227,24 @@ func testProvider(p *config.Provider) testResult {
 
 	// Create HTTP client with timeout
 	client := &http.Client{
-		Timeout: 5 * time.Second,
+		Timeout: timeout,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			return http.ErrUseLastResponse // Don't follow redirects
 		},
 	}
 
-	// Make request
-	resp, err := client.Get(testURL)
+	// Make request, retrying a server that is busy or still starting
+	req, err := http.NewRequest(http.MethodGet, testURL, nil)
+	if err != nil {
+		return testResult{reachable: false, errMsg: err.Error()}
+	}
+	resp, err := httpretry.Do(client, req)
 	if err != nil {
+		slog.Debug("provider test failed", "provider", p.Name, "url", testURL, "err", err)
 		return testResult{reachable: false, errMsg: err.Error()}
 	}
 	defer resp.Body.Close()
+	slog.Debug("provider test", "provider", p.Name, "url", testURL, "status", resp.StatusCode)
 
 	// Any HTTP response means reachable
 	return testResult{
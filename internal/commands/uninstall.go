@@ -4,57 +4,84 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/errcode"
+	"github.com/sammcj/skint/internal/secrets"
 	"github.com/sammcj/skint/internal/ui"
 	"github.com/spf13/cobra"
 )
 
 // NewUninstallCmd creates the uninstall command
 func NewUninstallCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "uninstall",
 		Short: "Remove Skint completely",
 		Long: `Remove all Skint configuration, data, and generated files.
 
 This will delete:
-  - Configuration directory (~/.config/skint
-  - Data directory (~/.local/share/skint
-  - Cache directory (~/.cache/skint
-  - Generated scripts (skint-*)`,
+  - Configuration directory (~/.config/skint)
+  - Data directory (~/.local/share/skint), including the encrypted key file
+  - Cache directory (~/.cache/skint)
+  - Generated scripts (skint-*)
+
+--purge also deletes every provider's API key from the OS keyring and the
+lines 'skint setup-path' added to your shell's rc files. --dry-run lists
+exactly what would be removed without removing anything.`,
+		Example: `  skint uninstall --dry-run --purge
+  skint uninstall --purge --yes`,
+		Args: cobra.NoArgs,
 		RunE: runUninstall,
 	}
+	cmd.Flags().Bool("purge", false, "also delete API keys from the keyring and the PATH lines from rc files")
+	cmd.Flags().Bool("dry-run", false, "list what would be removed without removing it")
+	return cmd
+}
+
+// uninstallPlan is what uninstall removes, limited to what exists.
+type uninstallPlan struct {
+	// Paths are directories and generated scripts
+	Paths []string `json:"paths"`
+	// Keys are providers with an API key in the OS keyring (--purge)
+	Keys []string `json:"keyring_keys"`
+	// RCFiles have lines from skint setup-path (--purge)
+	RCFiles []string `json:"rc_files"`
 }
 
 func runUninstall(cmd *cobra.Command, args []string) error {
 	cc := GetContext(cmd)
+	purge, _ := cmd.Flags().GetBool("purge")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
 
-	// Get directories
-	configDir := cc.ConfigMgr.ConfigDir()
-	dataDir, _ := config.GetDataDir()
-	cacheDir, _ := config.GetCacheDir()
-	binDir, _ := config.GetBinDir()
+	plan := planUninstall(cc, purge)
 
-	// JSON output
-	if cc.Cfg.OutputFormat == config.FormatJSON {
-		return cc.Output(map[string]any{
-			"would_remove": []string{
-				configDir,
-				dataDir,
-				cacheDir,
-				binDir + "/skint*",
-			},
-		})
-	}
-
-	// Plain output
-	if cc.Cfg.OutputFormat == config.FormatPlain {
-		fmt.Println("Would remove:")
-		fmt.Printf("  %s\n", configDir)
-		fmt.Printf("  %s\n", dataDir)
-		fmt.Printf("  %s\n", cacheDir)
-		fmt.Printf("  %s/skint*\n", binDir)
+	// Output for scripts only removes with --yes, as it can't be confirmed
+	if cc.Cfg.OutputFormat == config.FormatJSON || cc.Cfg.OutputFormat == config.FormatPlain {
+		removed := !dryRun && cc.YesMode
+		failed := []string{}
+		if removed {
+			failed = append(failed, removeUninstallPlan(cc, plan)...)
+		}
+		if cc.Cfg.OutputFormat == config.FormatJSON {
+			key := "would_remove"
+			if removed {
+				key = "removed"
+			}
+			return cc.Output(map[string]any{key: plan, "purge": purge, "failed": failed})
+		}
+		for _, f := range failed {
+			fmt.Fprintln(os.Stderr, f)
+		}
+		if removed {
+			fmt.Println("Removed:")
+		} else {
+			fmt.Println("Would remove:")
+		}
+		for _, item := range plan.items() {
+			fmt.Printf("  %s\n", item)
+		}
 		return nil
 	}
 
@@ -62,15 +89,32 @@ func runUninstall(cmd *cobra.Command, args []string) error {
 	fmt.Println()
 	ui.Log("%s", ui.Bold("Uninstall Skint"))
 	fmt.Println()
-	ui.Log("This will remove:")
-	ui.Dim("  %s %s\n", ui.Sym.Arrow, configDir)
-	ui.Dim("  %s %s\n", ui.Sym.Arrow, dataDir)
-	ui.Dim("  %s %s\n", ui.Sym.Arrow, cacheDir)
-	ui.Dim("  %s %s/skint*\n", ui.Sym.Arrow, binDir)
+	items := plan.items()
+	if len(items) == 0 {
+		ui.Info("Nothing to remove")
+		return nil
+	}
+	if dryRun {
+		ui.Log("Would remove:")
+	} else {
+		ui.Log("This will remove:")
+	}
+	for _, item := range items {
+		ui.Dim("  %s %s\n", ui.Sym.Arrow, item)
+	}
 	fmt.Println()
+	if dryRun {
+		return nil
+	}
+	if !purge && cc.SecretsMgr != nil && cc.SecretsMgr.IsKeyringAvailable() {
+		ui.Info("API keys in the keyring are kept: add --purge to delete them too")
+	}
 
 	// Confirm
 	if !cc.YesMode {
+		if cc.NoInput {
+			return errcode.New(errcode.InputRequired, "uninstalling needs confirmation. Use --yes")
+		}
 		if !ui.ConfirmDanger("Remove all Skint files", "delete skint") {
 			ui.Info("Cancelled")
 			return nil
@@ -80,28 +124,121 @@ func runUninstall(cmd *cobra.Command, args []string) error {
 	// Spinner
 	spinner := ui.NewSpinner("Removing files...")
 	spinner.Start()
+	failed := removeUninstallPlan(cc, plan)
+	spinner.Stop(len(failed) == 0)
 
-	// Remove directories
-	dirs := []string{configDir, dataDir, cacheDir}
-	for _, dir := range dirs {
-		if dir != "" {
-			_ = os.RemoveAll(dir)
+	for _, f := range failed {
+		ui.Warning("%s", f)
+	}
+	ui.Success("Skint uninstalled")
+	return nil
+}
+
+// planUninstall returns what uninstall removes: the config, data and cache
+// directories and generated scripts, and with purge keyring keys and
+// setup-path lines.
+func planUninstall(cc *CmdContext, purge bool) uninstallPlan {
+	plan := uninstallPlan{Paths: []string{}, Keys: []string{}, RCFiles: []string{}}
+
+	// Get directories
+	configDir := cc.ConfigMgr.ConfigDir()
+	dataDir, _ := config.GetDataDir()
+	cacheDir, _ := config.GetCacheDir()
+	binDir, _ := config.GetBinDir()
+	for _, dir := range []string{configDir, dataDir, cacheDir} {
+		if dir == "" || slices.Contains(plan.Paths, dir) {
+			continue
+		}
+		if _, err := os.Stat(dir); err == nil {
+			plan.Paths = append(plan.Paths, dir)
 		}
 	}
 
-	// Remove scripts from bin directory
+	// Scripts from the bin directory, and their manifest
 	if binDir != "" {
 		entries, _ := os.ReadDir(binDir)
 		for _, entry := range entries {
 			name := entry.Name()
-			if strings.HasPrefix(name, "skint-") || name == "skint" {
-				_ = os.Remove(filepath.Join(binDir, name))
+			if strings.HasPrefix(name, "skint-") || name == "skint" || strings.HasPrefix(name, ".skint-") {
+				plan.Paths = append(plan.Paths, filepath.Join(binDir, name))
 			}
 		}
 	}
 
-	spinner.Stop(true)
+	if !purge {
+		return plan
+	}
+	if cc.SecretsMgr != nil && cc.SecretsMgr.IsKeyringAvailable() {
+		for _, name := range keyringNames(cc.Cfg) {
+			if _, err := cc.SecretsMgr.Retrieve(name); err == nil {
+				plan.Keys = append(plan.Keys, name)
+			}
+		}
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		for _, shell := range pathShells {
+			file := rcFile(shell, home)
+			if data, err := os.ReadFile(file); err == nil && strings.Contains(string(data), pathBlockStart) && !slices.Contains(plan.RCFiles, file) {
+				plan.RCFiles = append(plan.RCFiles, file)
+			}
+		}
+	}
+	return plan
+}
 
-	ui.Success("Skint uninstalled")
-	return nil
+// keyringNames returns the names keys may be stored under in the keyring:
+// those the providers refer to, and each provider's own name.
+func keyringNames(cfg *config.Config) []string {
+	var names []string
+	add := func(name string) {
+		if name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	for _, p := range cfg.Providers {
+		if ref, ok := strings.CutPrefix(p.APIKeyRef, secrets.StorageTypeKeyring+":"); ok {
+			add(ref)
+		}
+		add(p.Name)
+	}
+	return names
+}
+
+// items describes each thing in the plan, one per line.
+func (p uninstallPlan) items() []string {
+	items := slices.Clone(p.Paths)
+	for _, name := range p.Keys {
+		items = append(items, fmt.Sprintf("API key for %s in the %s keyring", name, secrets.ServiceName))
+	}
+	for _, file := range p.RCFiles {
+		items = append(items, "skint setup-path lines in "+file)
+	}
+	return items
+}
+
+// removeUninstallPlan removes everything in plan, returning what it failed
+// to remove.
+func removeUninstallPlan(cc *CmdContext, plan uninstallPlan) []string {
+	var failed []string
+	for _, name := range plan.Keys {
+		if err := cc.SecretsMgr.Delete(name); err != nil {
+			failed = append(failed, fmt.Sprintf("failed to delete the API key for %s: %v", name, err))
+		}
+	}
+	for _, file := range plan.RCFiles {
+		data, err := os.ReadFile(file)
+		if err == nil {
+			updated, _ := withoutPathBlock(string(data))
+			err = os.WriteFile(file, []byte(updated), 0o644)
+		}
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("failed to update %s: %v", file, err))
+		}
+	}
+	for _, path := range plan.Paths {
+		if err := os.RemoveAll(path); err != nil {
+			failed = append(failed, fmt.Sprintf("failed to remove %s: %v", path, err))
+		}
+	}
+	return failed
 }
@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/sammcj/skint/internal/config"
+)
+
+func TestKeyringNames(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Providers = []*config.Provider{
+		{Name: "zai", APIKeyRef: "keyring:zai"},
+		{Name: "or-gpt", APIKeyRef: "keyring:openrouter"},
+		{Name: "local", APIKeyRef: "file:local"},
+	}
+	want := []string{"zai", "openrouter", "or-gpt", "local"}
+	if got := keyringNames(cfg); !slices.Equal(got, want) {
+		t.Errorf("keyringNames = %v, want %v", got, want)
+	}
+}
+
+func TestRemoveUninstallPlanRCFiles(t *testing.T) {
+	rc := filepath.Join(t.TempDir(), ".zshrc")
+	block := pathBlock("zsh", "/home/me/.local/bin")
+	data, _ := withPathBlock("alias a=b\n", block)
+	if err := os.WriteFile(rc, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	script := filepath.Join(t.TempDir(), "skint-zai")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\n"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	plan := uninstallPlan{Paths: []string{script}, RCFiles: []string{rc}}
+	if failed := removeUninstallPlan(&CmdContext{}, plan); len(failed) != 0 {
+		t.Fatalf("removeUninstallPlan failed: %v", failed)
+	}
+	if got, _ := os.ReadFile(rc); string(got) != "alias a=b\n" {
+		t.Errorf("rc file = %q, want the setup-path lines removed", got)
+	}
+	if info, err := os.Stat(rc); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("rc file mode changed: %v", err)
+	}
+	if _, err := os.Stat(script); !os.IsNotExist(err) {
+		t.Errorf("script was kept: %v", err)
+	}
+}
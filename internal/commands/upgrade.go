@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// NewUpgradeConfigCmd creates the upgrade-config command
+func NewUpgradeConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upgrade-config",
+		Short: "Upgrade the config file to the current schema version",
+		Long: `Upgrade the config file to the current schema version.
+
+Older configs are read transparently, and upgraded on disk the next time
+skint saves them. This command performs the upgrade now. The original file
+is kept alongside as <config>.v<version>.bak.`,
+		Example: `  skint upgrade-config
+  skint upgrade-config --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: runUpgradeConfig,
+	}
+
+	cmd.Flags().Bool("dry-run", false, "Show what would be upgraded without writing")
+
+	return cmd
+}
+
+func runUpgradeConfig(cmd *cobra.Command, args []string) error {
+	cc := GetContext(cmd)
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	mgr := cc.ConfigMgr
+	from := mgr.FileVersion()
+	needed := mgr.NeedsUpgrade()
+	backup := ""
+
+	if needed && !dryRun {
+		backup = mgr.BackupPath()
+		if err := cc.SaveConfig(); err != nil {
+			return err
+		}
+	}
+
+	// JSON output
+	if cc.Cfg.OutputFormat == config.FormatJSON {
+		return cc.Output(map[string]any{
+			"config":   mgr.ConfigFile(),
+			"from":     from,
+			"to":       config.ConfigVersion,
+			"upgraded": needed && !dryRun,
+			"dry_run":  dryRun,
+			"backup":   backup,
+		})
+	}
+
+	// Plain output
+	if cc.Cfg.OutputFormat == config.FormatPlain {
+		switch {
+		case from == "":
+			fmt.Println("no config file")
+		case !needed:
+			fmt.Printf("up to date: %s\n", from)
+		case dryRun:
+			fmt.Printf("would upgrade: %s -> %s\n", from, config.ConfigVersion)
+		default:
+			fmt.Printf("upgraded: %s -> %s\n", from, config.ConfigVersion)
+		}
+		return nil
+	}
+
+	// Human-readable output
+	switch {
+	case from == "":
+		ui.Info("No config file at %s - nothing to upgrade", mgr.ConfigFile())
+	case !needed:
+		ui.Success("Config is up to date (version %s)", from)
+	case dryRun:
+		ui.Info("Config would be upgraded from %s to %s", from, config.ConfigVersion)
+		ui.Dim("  Backup would be written to %s\n", mgr.BackupPath())
+	default:
+		ui.Success("Config upgraded from %s to %s", from, config.ConfigVersion)
+		ui.Dim("  Backup: %s\n", backup)
+	}
+
+	return nil
+}
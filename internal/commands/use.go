@@ -2,26 +2,64 @@ package commands
 
 import (
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 
+	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/errcode"
 	"github.com/sammcj/skint/internal/launcher"
 	"github.com/sammcj/skint/internal/providers"
+	"github.com/sammcj/skint/internal/tmux"
+	"github.com/sammcj/skint/internal/ui"
 	"github.com/spf13/cobra"
 )
 
 // NewUseCmd creates the use command
 func NewUseCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:   "use <provider> [args...]",
+		Use:   "use [provider] [args...]",
 		Short: "Launch Claude with a specific provider",
 		Long: `Launch Claude Code using the specified provider.
 
 This sets the appropriate environment variables and execs Claude.
-Any additional arguments are passed directly to Claude.`,
+Any additional arguments are passed directly to Claude.
+
+Without a provider (or when the first argument is a flag), the provider for
+the current directory is used: the project's .skint.yaml, then the first
+matching directory_rules entry, then the default provider.
+
+Use --preset <name> (repeatable) to apply env presets from the config's
+env_presets section on top of the provider's own variables.
+
+--model <model> and --tier <tier>=<model> (repeatable; tiers are opus,
+sonnet, haiku and small) override the provider's model and model_mappings
+for this launch only. Add --save to keep them in the provider's config.
+
+--inherit-env <name> (repeatable, or comma-separated) keeps a variable from
+your environment that skint would otherwise remove or replace, like the
+provider's preserve_env setting.
+
+--session <name> launches Claude in a tmux session called <name>, which
+keeps running when you detach; 'skint sessions' lists and attaches to them.
+
+--dry-run shows what would be launched instead of launching: the provider,
+the claude binary and arguments, the variables skint sets (keys masked) and
+the inherited ones it removes.
+
+With auto_launch_after_use: false, 'skint use <provider>' only sets the
+default provider, and 'skint use' launches it. confirm_before_launch: true
+asks before launching.`,
 		Example: `  skint use zai                    # Use Z.AI
-  skint use zai --model glm-4.7    # Override model
-  skint use ollama --model qwen3   # Use local Ollama`,
-		Args: cobra.MinimumNArgs(1),
-		RunE: runUse,
+  skint use zai --model glm-4.7    # Override model for this launch
+  skint use zai --tier haiku=glm-4.5-air --save
+  skint use ollama --model qwen3   # Use local Ollama
+  skint use zai --preset corp-proxy
+  skint use zai --dry-run          # Show the launch without running it
+  skint use zai --session api-work # Launch in a tmux session
+  skint use --continue             # Provider for this directory`,
+		RunE:              runUse,
+		ValidArgsFunction: completeUse,
 		// Disable flag parsing so provider flags (e.g. --model) pass through to
 		// claude rather than being rejected by cobra. Mirrors the exec command.
 		DisableFlagParsing: true,
@@ -30,19 +68,105 @@ Any additional arguments are passed directly to Claude.`,
 
 func runUse(cmd *cobra.Command, args []string) error {
 	cc := GetContext(cmd)
-	providerName := args[0]
-	claudeArgs := args[1:]
 
-	// Check if claude is installed
-	if err := launcher.CheckClaude(); err != nil {
+	// Pull out skint's own flags before the rest pass through to claude
+	presets, args, err := extractFlag(args, "preset")
+	if err != nil {
+		return err
+	}
+	modelFlags, args, err := extractFlag(args, "model")
+	if err != nil {
+		return err
+	}
+	tierFlags, args, err := extractFlag(args, "tier")
+	if err != nil {
+		return err
+	}
+	inherit, args, err := extractFlag(args, "inherit-env")
+	if err != nil {
+		return err
+	}
+	sessions, args, err := extractFlag(args, "session")
+	if err != nil {
+		return err
+	}
+	save, args := extractBoolFlag(args, "save")
+	dryRun, args := extractBoolFlag(args, "dry-run")
+	override, err := parseModelOverride(modelFlags, tierFlags)
+	if err != nil {
 		return err
 	}
+	if save && override.empty() {
+		return errcode.New(errcode.Usage, "--save needs --model or --tier")
+	}
+	if save && dryRun {
+		return errcode.New(errcode.Usage, "--dry-run can't be combined with --save")
+	}
+	var session string
+	if len(sessions) > 0 {
+		session = sessions[len(sessions)-1]
+		if dryRun {
+			return errcode.New(errcode.Usage, "--dry-run can't be combined with --session")
+		}
+		if err := tmux.ValidateName(session); err != nil {
+			return err
+		}
+	}
+	var providerName string
+	claudeArgs := args
+	named := len(args) > 0 && !strings.HasPrefix(args[0], "-")
+	if named {
+		providerName, claudeArgs = args[0], args[1:]
+	} else {
+		providerName = cc.DefaultProviderName()
+	}
+	if providerName == "" {
+		return errcode.New(errcode.Usage, "requires a provider name (no default provider set)")
+	}
+
+	if save {
+		if err := cc.saveModelOverride(providerName, override); err != nil {
+			return err
+		}
+	}
+
+	// With auto-launch off, naming a provider only makes it the default
+	if named && !cc.Cfg.AutoLaunchAfterUse && !dryRun && session == "" {
+		return setDefaultProvider(cc, providerName)
+	}
 
 	// Resolve provider config and load API key
 	p, err := cc.ResolveProvider(providerName)
 	if err != nil {
 		return err
 	}
+	p = withInheritEnv(override.apply(p), inherit)
+
+	// Check if claude is installed (a dry run reports it instead; other
+	// targets are looked for at launch)
+	if !dryRun && p.TargetName() == config.TargetClaude {
+		if err := launcher.CheckClaude(); err != nil {
+			return err
+		}
+	}
+
+	// The session runs this same launch, minus --session and --save
+	if session != "" {
+		useArgs := []string{providerName}
+		for _, name := range presets {
+			useArgs = append(useArgs, "--preset", name)
+		}
+		for _, m := range modelFlags {
+			useArgs = append(useArgs, "--model", m)
+		}
+		for _, t := range tierFlags {
+			useArgs = append(useArgs, "--tier", t)
+		}
+		for _, name := range inherit {
+			useArgs = append(useArgs, "--inherit-env", name)
+		}
+		return cc.startSession(session, providerName, append(useArgs, claudeArgs...))
+	}
 
 	// Convert to provider interface
 	provider, err := providers.FromConfig(p)
@@ -50,15 +174,253 @@ func runUse(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create provider %s: %w", providerName, err)
 	}
 
+	presetEnv, err := cc.PresetEnv(p, presets...)
+	if err != nil {
+		return err
+	}
+
 	// Create launcher
 	l, err := launcher.New(cc.Cfg)
 	if err != nil {
 		return fmt.Errorf("failed to create launcher: %w", err)
 	}
+	l.SetExtraEnv(presetEnv)
+
+	// Configured default args and MCP servers, then passthrough args (e.g.
+	// --resume, --continue), then any trailing args
+	trailing := claudeArgs
+	launchArgs, err := cc.launchArgs(p, !dryRun)
+	if err != nil {
+		return err
+	}
+	claudeArgs = append(launchArgs, claudeArgs...)
+
+	if dryRun {
+		plan, err := l.Plan(provider, claudeArgs)
+		if err != nil {
+			return err
+		}
+		return printLaunchPlan(cc, p, plan)
+	}
+
+	if ok, err := cc.confirmLaunch(providerName); !ok {
+		if err == nil {
+			ui.Info("Cancelled")
+		}
+		return err
+	}
+
+	// Launch Claude - replaces the current process on Unix unless skint
+	// waits for it, as the watchdog does
+	l.SetOnExit(cc.recordUse(providerName, p.EffectiveModel(), provider.Target().Command))
+	return cc.withFallback(providerName, trailing, l.Launch(provider, claudeArgs))
+}
+
+// maskedEnv returns a copy of env with the values that are p's API key or
+// auth token masked.
+func maskedEnv(p *config.Provider, env map[string]string) map[string]string {
+	env = maps.Clone(env)
+	for k, v := range env {
+		if v != "" && (v == p.GetAPIKey() || v == p.AuthToken) {
+			env[k] = ui.MaskKey(v)
+		}
+	}
+	return env
+}
+
+// printLaunchPlan shows what launching p would do, with its API key and auth
+// token masked.
+func printLaunchPlan(cc *CmdContext, p *config.Provider, plan *launcher.Plan) error {
+	env := maskedEnv(p, plan.Env)
+	names := slices.Sorted(maps.Keys(env))
+	argv := append([]string{plan.Command}, plan.Args...)
+	removed := plan.Removed
+	if removed == nil {
+		removed = []string{}
+	}
+
+	switch cc.Cfg.OutputFormat {
+	case config.FormatJSON:
+		return cc.Output(map[string]any{
+			"provider":    p.Name,
+			"model":       p.EffectiveModel(),
+			"command":     plan.Command,
+			"claude_path": plan.ClaudePath,
+			"argv":        argv,
+			"env":         env,
+			"removed_env": removed,
+			"exec":        plan.Exec,
+		})
+	case config.FormatPlain:
+		for _, name := range removed {
+			fmt.Printf("unset %s\n", name)
+		}
+		for _, name := range names {
+			fmt.Printf("%s=%s\n", name, env[name])
+		}
+		fmt.Println(config.JoinArgs(argv))
+		return nil
+	}
+
+	claudePath := plan.ClaudePath
+	label := "Claude:      "
+	if plan.Command != "claude" {
+		label = "Launches:    "
+	}
+	if claudePath == "" {
+		claudePath = ui.Red("not found on PATH")
+	}
+	process := "exec (Claude replaces skint)"
+	if !plan.Exec {
+		process = "child process (skint waits for Claude)"
+	}
+	fmt.Println()
+	ui.Log("%s: %s", ui.Bold("Dry run"), ui.Yellow(p.Name))
+	ui.Separator(40)
+	if p.DisplayName != "" {
+		ui.Log("Display Name: %s", p.DisplayName)
+	}
+	if model := p.EffectiveModel(); model != "" {
+		ui.Log("Model:        %s", model)
+	}
+	ui.Log("%s %s", label, claudePath)
+	ui.Log("Command:      %s", config.JoinArgs(argv))
+	ui.Log("Process:      %s", process)
+	if len(names) > 0 {
+		ui.Log("Sets:")
+		for _, name := range names {
+			ui.Dim("  %s=%s\n", name, env[name])
+		}
+	} else {
+		ui.Log("Sets:         %s", ui.DimString("nothing"))
+	}
+	if len(removed) > 0 {
+		ui.Log("Removes (inherited from your environment):")
+		for _, name := range removed {
+			ui.Dim("  %s\n", name)
+		}
+	}
+	fmt.Println()
+	return nil
+}
+
+// setDefaultProvider saves name as the default provider without launching.
+func setDefaultProvider(cc *CmdContext, name string) error {
+	if name != "native" && cc.Cfg.GetProvider(name) == nil {
+		return errcode.New(errcode.ProviderNotFound, "provider %s is not configured. Run 'skint config %s' first", name, name)
+	}
+
+	cc.ConfigMgr.SetDefaultProvider(name)
+	if err := cc.SaveConfig(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	// JSON output
+	if cc.Cfg.OutputFormat == config.FormatJSON {
+		return cc.Output(map[string]any{
+			"default_provider": name,
+			"launched":         false,
+		})
+	}
+
+	// Plain output
+	if cc.Cfg.OutputFormat == config.FormatPlain {
+		fmt.Println(name)
+		return nil
+	}
+
+	// Human-readable output
+	ui.Success("Default provider set to %s", ui.Yellow(name))
+	ui.Dim("Launch Claude with 'skint use' (auto_launch_after_use is off)\n")
+	return nil
+}
+
+// modelOverride is a one-off choice of model from skint use --model and
+// --tier.
+type modelOverride struct {
+	model string
+	tiers map[string]string
+}
+
+// parseModelOverride parses --model values (the last wins) and --tier
+// values of the form tier=model.
+func parseModelOverride(models, tiers []string) (modelOverride, error) {
+	var o modelOverride
+	if len(models) > 0 {
+		o.model = models[len(models)-1]
+	}
+	for _, t := range tiers {
+		tier, model, ok := strings.Cut(t, "=")
+		if !ok || model == "" {
+			return modelOverride{}, errcode.New(errcode.Usage, "invalid --tier %q: use <tier>=<model>, e.g. haiku=glm-4.5-air", t)
+		}
+		if _, known := providers.TierEnvVars[tier]; !known {
+			return modelOverride{}, errcode.New(errcode.Usage, "unknown model tier %q: use opus, sonnet, haiku or small", tier)
+		}
+		if o.tiers == nil {
+			o.tiers = map[string]string{}
+		}
+		o.tiers[tier] = model
+	}
+	return o, nil
+}
+
+func (o modelOverride) empty() bool {
+	return o.model == "" && len(o.tiers) == 0
+}
+
+// apply returns a copy of p using the override's models, or p itself if
+// there is nothing to override.
+func (o modelOverride) apply(p *config.Provider) *config.Provider {
+	if o.empty() {
+		return p
+	}
+	cp := *p
+	if o.model != "" {
+		cp.Model = o.model
+	}
+	if len(o.tiers) > 0 {
+		cp.ModelMappings = maps.Clone(p.ModelMappings)
+		if cp.ModelMappings == nil {
+			cp.ModelMappings = map[string]string{}
+		}
+		maps.Copy(cp.ModelMappings, o.tiers)
+	}
+	return &cp
+}
 
-	// Merge passthrough args (e.g. --resume, --continue) with any trailing args
-	claudeArgs = append(cc.ClaudeExtraArgs, claudeArgs...)
+// withInheritEnv returns a copy of p that also preserves the variables named
+// with --inherit-env, each of which may be a comma-separated list, or p
+// itself if there are none.
+func withInheritEnv(p *config.Provider, inherit []string) *config.Provider {
+	if len(inherit) == 0 {
+		return p
+	}
+	cp := *p
+	cp.PreserveEnv = slices.Clone(p.PreserveEnv)
+	for _, names := range inherit {
+		for name := range strings.SplitSeq(names, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				cp.PreserveEnv = append(cp.PreserveEnv, name)
+			}
+		}
+	}
+	return &cp
+}
 
-	// Launch Claude - replaces the current process on Unix
-	return l.Launch(provider, claudeArgs)
+// saveModelOverride makes o's models the configured provider's own and
+// saves the config.
+func (cc *CmdContext) saveModelOverride(name string, o modelOverride) error {
+	p := cc.Cfg.GetProvider(name)
+	if p == nil {
+		return errcode.New(errcode.ProviderNotFound, "provider %s is not configured, so --save has nowhere to save the model. Run 'skint config %s' first", name, name)
+	}
+	*p = *o.apply(p)
+	if err := cc.SaveConfig(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	if cc.Cfg.OutputFormat == config.FormatHuman && !cc.Quiet {
+		ui.Success("Saved %s's models", name)
+	}
+	return nil
 }
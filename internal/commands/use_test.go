@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/sammcj/skint/internal/config"
+)
+
+func TestModelOverride(t *testing.T) {
+	o, err := parseModelOverride([]string{"a", "glm-5"}, []string{"haiku=glm-4.5-air", "small=tiny"})
+	if err != nil {
+		t.Fatalf("parseModelOverride: %v", err)
+	}
+	p := &config.Provider{Name: "zai", Model: "glm-4.7", ModelMappings: map[string]string{"haiku": "old", "opus": "big"}}
+	got := o.apply(p)
+	if got.Model != "glm-5" {
+		t.Errorf("model = %q, want the last --model", got.Model)
+	}
+	if want := map[string]string{"haiku": "glm-4.5-air", "opus": "big", "small": "tiny"}; !maps.Equal(got.ModelMappings, want) {
+		t.Errorf("mappings = %v, want %v", got.ModelMappings, want)
+	}
+	if p.Model != "glm-4.7" || p.ModelMappings["haiku"] != "old" {
+		t.Errorf("apply changed the provider: %+v", p)
+	}
+
+	if o, _ := parseModelOverride(nil, nil); !o.empty() || o.apply(p) != p {
+		t.Error("no flags should leave the provider alone")
+	}
+	for _, tier := range []string{"haiku", "haiku=", "fast=x"} {
+		if _, err := parseModelOverride(nil, []string{tier}); err == nil {
+			t.Errorf("--tier %q should be rejected", tier)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+)
+
+// aliasName is a valid alias name: something that can be typed as a command.
+var aliasName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// ValidateAliasName checks name can be used as an alias.
+func ValidateAliasName(name string) error {
+	if !aliasName.MatchString(name) {
+		return fmt.Errorf("invalid alias name %q: use letters, digits, '-', '_' and '.', starting with a letter or digit", name)
+	}
+	return nil
+}
+
+// ReadAliases returns the aliases in the system config files and configFile
+// (the default user config if ""), without loading the rest of the config.
+// It is used to register alias commands before flags are parsed, so files
+// that can't be read are skipped: loading the config reports them.
+func ReadAliases(configFile string) map[string][]string {
+	files := systemConfigFiles()
+	if configFile == "" {
+		dir, err := getConfigDir()
+		if err != nil {
+			return nil
+		}
+		configFile = defaultConfigFile(dir)
+	}
+	files = append(files, configFile)
+
+	aliases := map[string][]string{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		var layer struct {
+			Aliases map[string][]string `yaml:"aliases" json:"aliases" toml:"aliases"`
+		}
+		if err := decodeConfig(detectFormat(file, data), data, &layer); err != nil {
+			continue
+		}
+		for name, args := range layer.Aliases {
+			aliases[name] = args
+		}
+	}
+	return aliases
+}
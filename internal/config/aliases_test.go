@@ -0,0 +1,38 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestReadAliases(t *testing.T) {
+	t.Setenv("XDG_CONFIG_DIRS", t.TempDir())
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := "version: \"2.0\"\naliases:\n  glm: [zai, --model, glm-5-air]\n"
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if got := ReadAliases(path)["glm"]; !slices.Equal(got, []string{"zai", "--model", "glm-5-air"}) {
+		t.Errorf("ReadAliases = %q", got)
+	}
+	if got := ReadAliases(filepath.Join(t.TempDir(), "missing.yaml")); len(got) != 0 {
+		t.Errorf("missing file: ReadAliases = %v", got)
+	}
+}
+
+func TestValidateAliases(t *testing.T) {
+	for name, valid := range map[string]bool{"glm": true, "glm-5.air": true, "-x": false, "a b": false, "": false} {
+		cfg := NewDefaultConfig()
+		cfg.Aliases = map[string][]string{name: {"native"}}
+		if err := cfg.Validate(); (err == nil) != valid {
+			t.Errorf("alias %q: Validate() = %v", name, err)
+		}
+	}
+	cfg := NewDefaultConfig()
+	cfg.Aliases = map[string][]string{"empty": nil}
+	if err := cfg.Validate(); err == nil {
+		t.Error("an empty alias should be invalid")
+	}
+}
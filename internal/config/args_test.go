@@ -0,0 +1,17 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitArgsRoundTrip(t *testing.T) {
+	args := []string{"--continue", "be brief", "it's", `back\slash`, ""}
+	got, err := SplitArgs(JoinArgs(args))
+	if err != nil {
+		t.Fatalf("SplitArgs: %v", err)
+	}
+	if !slices.Equal(got, args) {
+		t.Errorf("round trip = %q, want %q", got, args)
+	}
+}
@@ -0,0 +1,155 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ccrConfig is the part of a claude-code-router config.json skint reads.
+type ccrConfig struct {
+	Providers []ccrProvider `json:"Providers"`
+	// Older versions used a lower-case key
+	LegacyProviders []ccrProvider `json:"providers"`
+	Router          struct {
+		Default string `json:"default"`
+	} `json:"Router"`
+}
+
+type ccrProvider struct {
+	Name        string          `json:"name"`
+	APIBaseURL  string          `json:"api_base_url"`
+	APIKey      string          `json:"api_key"`
+	Models      []string        `json:"models"`
+	Transformer json.RawMessage `json:"transformer"`
+}
+
+// ccrUnsupported are transformers for APIs that are neither Anthropic nor
+// OpenAI compatible, so Claude Code can't use the provider without the router.
+var ccrUnsupported = []string{"gemini", "vertex-gemini", "vertex-claude"}
+
+// ccrEnvRef matches an api_key that refers to an environment variable, as
+// $VAR or ${VAR}.
+var ccrEnvRef = regexp.MustCompile(`^\$\{?([A-Za-z_][A-Za-z0-9_]*)\}?$`)
+
+// CCRConfigPath returns where claude-code-router keeps its config.
+func CCRConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".claude-code-router", "config.json"), nil
+}
+
+// ImportCCR converts the providers in a claude-code-router config.json to
+// skint providers: OpenRouter ones to or-* providers, one per model, and the
+// rest to custom providers using the model of the default route, or their
+// first. Keys given as $VAR are looked up with getenv. The router's
+// transformers have no skint equivalent and are dropped.
+func ImportCCR(data []byte, getenv func(string) string) (*ProviderImport, error) {
+	var cfg ccrConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse claude-code-router config: %w", err)
+	}
+	providers := cfg.Providers
+	if len(providers) == 0 {
+		providers = cfg.LegacyProviders
+	}
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("no providers in the claude-code-router config")
+	}
+	defaultProvider, defaultModel, _ := strings.Cut(cfg.Router.Default, ",")
+
+	imp := &ProviderImport{Keys: map[string]string{}}
+	for _, cp := range providers {
+		key := cp.APIKey
+		if m := ccrEnvRef.FindStringSubmatch(key); m != nil {
+			if key = getenv(m[1]); key == "" {
+				imp.Skipped = append(imp.Skipped, fmt.Sprintf("%s's key: $%s is not set", cp.Name, m[1]))
+			}
+		}
+		transformers := ccrTransformers(cp.Transformer)
+		if i := slices.IndexFunc(transformers, func(t string) bool { return slices.Contains(ccrUnsupported, t) }); i >= 0 {
+			imp.Skipped = append(imp.Skipped, fmt.Sprintf("%s: the %s API isn't Anthropic or OpenAI compatible", cp.Name, transformers[i]))
+			continue
+		}
+		if len(cp.Models) == 0 {
+			imp.Skipped = append(imp.Skipped, fmt.Sprintf("%s: no models", cp.Name))
+			continue
+		}
+
+		if strings.Contains(cp.APIBaseURL, "openrouter.ai") || slices.Contains(transformers, "openrouter") {
+			for _, model := range cp.Models {
+				p := &Provider{
+					Name:        OpenRouterPrefix + importName(model[strings.LastIndex(model, "/")+1:]),
+					Type:        ProviderTypeOpenRouter,
+					DisplayName: "OpenRouter " + model,
+					BaseURL:     OpenRouterBaseURL,
+					Model:       model,
+				}
+				if imp.add(p) && cp.Name == defaultProvider && model == defaultModel {
+					imp.Default = p.Name
+				}
+			}
+			if key != "" {
+				imp.Keys["openrouter"] = key
+			}
+			continue
+		}
+
+		model := cp.Models[0]
+		if cp.Name == defaultProvider && slices.Contains(cp.Models, defaultModel) {
+			model = defaultModel
+		}
+		p := &Provider{
+			Name:        importName(cp.Name),
+			Type:        ProviderTypeCustom,
+			DisplayName: cp.Name,
+			Description: "Imported from claude-code-router",
+			APIType:     APITypeOpenAI,
+			Model:       model,
+		}
+		if slices.Contains(transformers, "anthropic") {
+			p.APIType = APITypeAnthropic
+		}
+		p.BaseURL = importBaseURL(cp.APIBaseURL, p.APIType)
+		if !imp.add(p) {
+			continue
+		}
+		if key != "" {
+			imp.Keys[p.Name] = key
+		}
+		if cp.Name == defaultProvider {
+			imp.Default = p.Name
+		}
+	}
+	return imp, nil
+}
+
+// ccrTransformers returns the names of the transformers a provider uses for
+// all its models. Options given with a transformer are ignored.
+func ccrTransformers(raw json.RawMessage) []string {
+	var t struct {
+		Use []json.RawMessage `json:"use"`
+	}
+	if len(raw) == 0 || json.Unmarshal(raw, &t) != nil {
+		return nil
+	}
+	var names []string
+	for _, u := range t.Use {
+		// Each is a name, or [name, options]
+		var name string
+		var withOptions []json.RawMessage
+		if json.Unmarshal(u, &name) != nil && json.Unmarshal(u, &withOptions) == nil && len(withOptions) > 0 {
+			_ = json.Unmarshal(withOptions[0], &name)
+		}
+		if name != "" {
+			names = append(names, strings.ToLower(name))
+		}
+	}
+	return names
+}
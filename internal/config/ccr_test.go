@@ -0,0 +1,104 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+const ccrSample = `{
+  "APIKEY": "router-secret",
+  "Providers": [
+    {
+      "name": "openrouter",
+      "api_base_url": "https://openrouter.ai/api/v1/chat/completions",
+      "api_key": "sk-or-1",
+      "models": ["google/gemini-2.5-pro-preview", "anthropic/claude-sonnet-4"],
+      "transformer": {"use": ["openrouter"]}
+    },
+    {
+      "name": "deepseek",
+      "api_base_url": "https://api.deepseek.com/chat/completions",
+      "api_key": "$DEEPSEEK_KEY",
+      "models": ["deepseek-chat", "deepseek-reasoner"],
+      "transformer": {"use": ["deepseek"], "deepseek-chat": {"use": ["tooluse"]}}
+    },
+    {
+      "name": "Local_Ollama",
+      "api_base_url": "http://localhost:11434/v1/chat/completions",
+      "api_key": "ollama",
+      "models": ["qwen2.5-coder:latest"]
+    },
+    {
+      "name": "claude",
+      "api_base_url": "https://api.anthropic.com/v1/messages",
+      "api_key": "${MISSING_KEY}",
+      "models": ["claude-sonnet-4"],
+      "transformer": {"use": [["Anthropic", {"UseBearer": true}]]}
+    },
+    {
+      "name": "gemini",
+      "api_base_url": "https://generativelanguage.googleapis.com/v1beta/models/",
+      "api_key": "g",
+      "models": ["gemini-2.5-flash"],
+      "transformer": {"use": ["gemini"]}
+    }
+  ],
+  "Router": {"default": "deepseek,deepseek-reasoner", "background": "Local_Ollama,qwen2.5-coder:latest"}
+}`
+
+func TestImportCCR(t *testing.T) {
+	env := map[string]string{"DEEPSEEK_KEY": "sk-ds"}
+	imp, err := ImportCCR([]byte(ccrSample), func(k string) string { return env[k] })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]Provider{
+		"or-gemini-2-5-pro-preview": {Type: ProviderTypeOpenRouter, BaseURL: "https://openrouter.ai/api", Model: "google/gemini-2.5-pro-preview"},
+		"or-claude-sonnet-4":        {Type: ProviderTypeOpenRouter, BaseURL: "https://openrouter.ai/api", Model: "anthropic/claude-sonnet-4"},
+		"deepseek":                  {Type: ProviderTypeCustom, APIType: APITypeOpenAI, BaseURL: "https://api.deepseek.com", Model: "deepseek-reasoner"},
+		"local-ollama":              {Type: ProviderTypeCustom, APIType: APITypeOpenAI, BaseURL: "http://localhost:11434/v1", Model: "qwen2.5-coder:latest"},
+		"claude":                    {Type: ProviderTypeCustom, APIType: APITypeAnthropic, BaseURL: "https://api.anthropic.com", Model: "claude-sonnet-4"},
+	}
+	if len(imp.Providers) != len(want) {
+		t.Errorf("got %d providers, want %d", len(imp.Providers), len(want))
+	}
+	for _, p := range imp.Providers {
+		w, ok := want[p.Name]
+		if !ok {
+			t.Errorf("unexpected provider %q", p.Name)
+			continue
+		}
+		if p.Type != w.Type || p.APIType != w.APIType || p.BaseURL != w.BaseURL || p.Model != w.Model {
+			t.Errorf("%s = %s/%s %s %s, want %s/%s %s %s", p.Name, p.Type, p.APIType, p.BaseURL, p.Model, w.Type, w.APIType, w.BaseURL, w.Model)
+		}
+	}
+
+	wantKeys := map[string]string{"openrouter": "sk-or-1", "deepseek": "sk-ds", "local-ollama": "ollama"}
+	if len(imp.Keys) != len(wantKeys) {
+		t.Errorf("Keys = %v, want %v", imp.Keys, wantKeys)
+	}
+	for name, key := range wantKeys {
+		if imp.Keys[name] != key {
+			t.Errorf("Keys[%s] = %q, want %q", name, imp.Keys[name], key)
+		}
+	}
+	if imp.Default != "deepseek" {
+		t.Errorf("Default = %q, want deepseek", imp.Default)
+	}
+
+	skipped := strings.Join(imp.Skipped, "\n")
+	for _, want := range []string{"claude's key: $MISSING_KEY is not set", "gemini: the gemini API"} {
+		if !strings.Contains(skipped, want) {
+			t.Errorf("Skipped missing %q:\n%s", want, skipped)
+		}
+	}
+}
+
+func TestImportCCRErrors(t *testing.T) {
+	for _, data := range []string{`not json`, `{"Providers": []}`} {
+		if _, err := ImportCCR([]byte(data), func(string) string { return "" }); err == nil {
+			t.Errorf("ImportCCR(%q) succeeded", data)
+		}
+	}
+}
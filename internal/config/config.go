@@ -5,8 +5,6 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
-
-	"gopkg.in/yaml.v3"
 )
 
 // Manager handles configuration loading and saving
@@ -15,33 +13,24 @@ type Manager struct {
 	configFile string
 	config     *Config
 	overrides  envOverrides
-}
-
-// envOverrides records persisted config values that were replaced by SKINT_*
-// environment overrides at Load time. Save reverts to these so transient env
-// settings are never written to disk. A nil pointer means the field was not
-// overridden.
-type envOverrides struct {
-	defaultProvider *fieldOverride[string]
-	outputFormat    *fieldOverride[string]
-	colorEnabled    *fieldOverride[bool]
-	noBanner        *fieldOverride[bool]
-}
-
-// fieldOverride pairs the persisted value with the env value that replaced it.
-type fieldOverride[T comparable] struct {
-	persisted T
-	applied   T
-}
-
-// revert returns the value Save should persist: the pre-override value while the
-// runtime value still equals the applied override, otherwise the runtime value -
-// a deliberate change (e.g. the TUI setting a new default provider) must win.
-func (o *fieldOverride[T]) revert(current T) T {
-	if o != nil && current == o.applied {
-		return o.persisted
-	}
-	return current
+	// format is the config file format (FileFormat*), preserved on Save
+	format string
+	// fileVersion is the schema version the user config was written as; Save
+	// backs the file up before first overwriting an older version.
+	fileVersion string
+
+	// systemFiles are lower-precedence config layers read before the user
+	// config, lowest precedence first (see systemConfigFiles).
+	systemFiles []string
+	// systemLayers records which system files were actually loaded and the
+	// providers they defined, so Save can leave those out of the user config.
+	systemLayers layerState
+
+	// diskData and base are the user config file as last read or written, raw
+	// and as persisted settings. Save uses them to detect and merge changes
+	// made by another skint process in the meantime.
+	diskData []byte
+	base     *Config
 }
 
 // NewManager creates a new configuration manager
@@ -51,16 +40,20 @@ func NewManager() (*Manager, error) {
 		return nil, fmt.Errorf("failed to get config dir: %w", err)
 	}
 
+	configFile := defaultConfigFile(configDir)
 	m := &Manager{
-		configDir:  configDir,
-		configFile: filepath.Join(configDir, "config.yaml"),
-		config:     NewDefaultConfig(),
+		configDir:   configDir,
+		configFile:  configFile,
+		config:      NewDefaultConfig(),
+		format:      formatFromPath(configFile),
+		systemFiles: systemConfigFiles(),
 	}
 
 	return m, nil
 }
 
-// NewManagerWithPath creates a manager with custom config path
+// NewManagerWithPath creates a manager with custom config path. System-wide
+// layers are not read: an explicit path means "use exactly this file".
 func NewManagerWithPath(configPath string) (*Manager, error) {
 	configDir := filepath.Dir(configPath)
 
@@ -68,49 +61,52 @@ func NewManagerWithPath(configPath string) (*Manager, error) {
 		configDir:  configDir,
 		configFile: configPath,
 		config:     NewDefaultConfig(),
+		format:     formatFromPath(configPath),
 	}
 
 	return m, nil
 }
 
-// Load reads the configuration from disk
+// Load reads the configuration from disk. System-wide layers are applied
+// first, then the user config on top.
 func (m *Manager) Load() error {
 	// Ensure config directory exists
 	if err := os.MkdirAll(m.configDir, 0700); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	// Check if file exists
-	if _, err := os.Stat(m.configFile); os.IsNotExist(err) {
-		// No config file yet, use defaults
-		return nil
-	}
-
-	// Check for symlink before reading (security)
-	info, err := os.Lstat(m.configFile)
-	if err != nil {
-		return fmt.Errorf("failed to stat config file: %w", err)
-	}
-	if info.Mode()&os.ModeSymlink != 0 {
-		return fmt.Errorf("config file is a symlink - refusing for security")
+	if err := m.loadSystemLayers(); err != nil {
+		return err
 	}
 
-	// Read file
-	data, err := os.ReadFile(m.configFile)
-	if err != nil {
-		return fmt.Errorf("failed to read config file: %w", err)
-	}
+	// Check if file exists; with no user config, defaults plus any system
+	// layers are used as-is
+	if _, err := os.Stat(m.configFile); err == nil {
+		// Check for symlink before reading (security)
+		info, err := os.Lstat(m.configFile)
+		if err != nil {
+			return fmt.Errorf("failed to stat config file: %w", err)
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("config file is a symlink - refusing for security")
+		}
 
-	// Parse YAML
-	if err := yaml.Unmarshal(data, m.config); err != nil {
-		return fmt.Errorf("failed to parse config file: %w", err)
-	}
+		// Read file
+		data, err := os.ReadFile(m.configFile)
+		if err != nil {
+			return fmt.Errorf("failed to read config file: %w", err)
+		}
 
-	// Clear any legacy plaintext API keys (migration artifact)
-	for _, p := range m.config.Providers {
-		if p.APIKey != "" && p.APIKeyRef != "" {
-			p.APIKey = ""
+		// Parse in whichever format the file uses; Save writes it back the same way
+		m.diskData = data
+		m.format = detectFormat(m.configFile, data)
+		if m.fileVersion, err = m.applyLayer(m.format, data); err != nil {
+			return fmt.Errorf("failed to parse config file: %w", err)
 		}
+	} else if len(m.systemLayers.files) == 0 {
+		// No config file yet, use defaults
+		m.base = cloneConfig(m.config)
+		return nil
 	}
 
 	// Apply environment overrides
@@ -124,6 +120,8 @@ func (m *Manager) Load() error {
 		return fmt.Errorf("invalid configuration: %w", err)
 	}
 
+	base := m.configForSave()
+	m.base = cloneConfig(&base)
 	return nil
 }
 
@@ -146,16 +144,51 @@ func (m *Manager) Save() error {
 		}
 	}
 
+	// Hold the config lock from here until the new file is in place, and
+	// pick up anything another skint process saved since we loaded
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	theirs, theirVersion, err := m.readIfChanged()
+	if err != nil {
+		return err
+	}
+	if theirs != nil {
+		m.mergeConcurrentChanges(theirs)
+		m.fileVersion = theirVersion
+		if err := m.config.Validate(); err != nil {
+			return fmt.Errorf("invalid configuration after merging concurrent changes: %w", err)
+		}
+	}
+
 	// Revert env overrides so transient settings are not persisted.
 	toSave := m.configForSave()
 
-	// Marshal to YAML
-	data, err := yaml.Marshal(&toSave)
+	// Marshal in the file's original format (YAML for new files)
+	format := m.format
+	if format == "" {
+		format = FileFormatYAML
+	}
+	data, err := encodeConfig(format, m.systemLayers.userSettings(&toSave))
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	return m.writeAtomic(data)
+	// Keep a copy of a config written by an older schema before replacing it
+	if err := m.backupBeforeUpgrade(); err != nil {
+		return err
+	}
+
+	if err := m.writeAtomic(data, format); err != nil {
+		return err
+	}
+	m.fileVersion = ConfigVersion
+	m.diskData = data
+	m.base = cloneConfig(&toSave)
+	return nil
 }
 
 // Get returns the current configuration
@@ -168,11 +201,25 @@ func (m *Manager) Set(cfg *Config) {
 	m.config = cfg
 }
 
+// Replace swaps in a whole new configuration, such as one restored from a
+// backup. Overrides recorded against the old config are dropped, so Save
+// writes cfg exactly as given.
+func (m *Manager) Replace(cfg *Config) {
+	m.config = cfg
+	m.overrides = envOverrides{}
+}
+
 // ConfigFile returns the path to the config file
 func (m *Manager) ConfigFile() string {
 	return m.configFile
 }
 
+// Format returns the config file format (FileFormatYAML, FileFormatJSON or
+// FileFormatTOML), or "" if not yet known.
+func (m *Manager) Format() string {
+	return m.format
+}
+
 // ConfigDir returns the configuration directory
 func (m *Manager) ConfigDir() string {
 	return m.configDir
@@ -184,70 +231,24 @@ func (m *Manager) Exists() bool {
 	return err == nil
 }
 
-// applyEnvOverrides applies SKINT_* environment variable overrides, recording
-// the pre-override values so Save can revert them (see envOverrides).
-func (m *Manager) applyEnvOverrides() {
-	m.overrides = envOverrides{}
-
-	if v := os.Getenv("SKINT_DEFAULT_PROVIDER"); v != "" {
-		m.overrides.defaultProvider = &fieldOverride[string]{persisted: m.config.DefaultProvider, applied: v}
-		m.config.DefaultProvider = v
-	}
-	if v := os.Getenv("SKINT_OUTPUT_FORMAT"); v != "" {
-		switch v {
-		case FormatHuman, FormatJSON, FormatPlain:
-			m.overrides.outputFormat = &fieldOverride[string]{persisted: m.config.OutputFormat, applied: v}
-			m.config.OutputFormat = v
-		default:
-			fmt.Fprintf(os.Stderr, "warning: ignoring invalid SKINT_OUTPUT_FORMAT=%q (valid: %s, %s, %s)\n",
-				v, FormatHuman, FormatJSON, FormatPlain)
-		}
-	}
-	if os.Getenv("SKINT_NO_COLOR") != "" || os.Getenv("NO_COLOR") != "" {
-		m.overrides.colorEnabled = &fieldOverride[bool]{persisted: m.config.ColorEnabled, applied: false}
-		m.config.ColorEnabled = false
-	}
-	if os.Getenv("SKINT_NO_BANNER") != "" {
-		m.overrides.noBanner = &fieldOverride[bool]{persisted: m.config.NoBanner, applied: true}
-		m.config.NoBanner = true
-	}
-}
-
-// resolveDefaultProviderOverride handles a SKINT_DEFAULT_PROVIDER that names an
-// unknown provider: rather than failing validation, warn and fall back to the
-// persisted default.
-func (m *Manager) resolveDefaultProviderOverride() {
-	if m.overrides.defaultProvider == nil {
-		return
-	}
-	name := m.config.DefaultProvider
-	if name == "native" || m.config.GetProvider(name) != nil {
-		return
-	}
-	fmt.Fprintf(os.Stderr, "warning: SKINT_DEFAULT_PROVIDER=%q not found in config; using %q\n",
-		name, m.overrides.defaultProvider.persisted)
-	m.config.DefaultProvider = m.overrides.defaultProvider.persisted
-	m.overrides.defaultProvider = nil
-}
-
 // configForSave returns a copy of the config with env overrides reverted to
 // their persisted values, so transient env settings are not written to disk.
 // Fields deliberately changed at runtime since the override was applied are
-// kept (see fieldOverride.revert).
+// kept (see fieldOverride.revert). Providers inherited unchanged from a system
+// layer are dropped so they stay owned by that layer; Save likewise leaves out
+// unchanged system settings (see layerState.userSettings).
 func (m *Manager) configForSave() Config {
 	c := *m.config
-	c.DefaultProvider = m.overrides.defaultProvider.revert(c.DefaultProvider)
-	c.OutputFormat = m.overrides.outputFormat.revert(c.OutputFormat)
-	c.ColorEnabled = m.overrides.colorEnabled.revert(c.ColorEnabled)
-	c.NoBanner = m.overrides.noBanner.revert(c.NoBanner)
+	m.overrides.revert(&c)
+	c.Providers = m.systemLayers.userProviders(c.Providers)
 	return c
 }
 
 // writeAtomic writes data to the config file atomically: it writes to a temp
 // file in the same directory, syncs, then renames over the target. A crash
 // mid-write leaves the existing config intact.
-func (m *Manager) writeAtomic(data []byte) error {
-	tmp, err := os.CreateTemp(m.configDir, ".config-*.yaml.tmp")
+func (m *Manager) writeAtomic(data []byte, format string) error {
+	tmp, err := os.CreateTemp(m.configDir, ".config-*."+format+".tmp")
 	if err != nil {
 		return fmt.Errorf("failed to create temp config file: %w", err)
 	}
@@ -272,16 +273,40 @@ func (m *Manager) writeAtomic(data []byte) error {
 	if err := os.Rename(tmpPath, m.configFile); err != nil {
 		return fmt.Errorf("failed to replace config file: %w", err)
 	}
+	// Sync the directory so the rename itself survives a crash
+	syncDir(m.configDir)
 	return nil
 }
 
-// getConfigDir returns the XDG-compliant config directory
+// syncDir fsyncs a directory, making a rename within it durable. Errors are
+// ignored: not every platform or filesystem supports syncing directories, and
+// the file contents are already on disk.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	_ = d.Sync()
+	_ = d.Close()
+}
+
+// getConfigDir returns the XDG-compliant config directory, or %APPDATA%\skint
+// on Windows
 func getConfigDir() (string, error) {
 	// Check XDG_CONFIG_HOME
 	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
 		return filepath.Join(xdg, "skint"), nil
 	}
 
+	// Windows: roaming app data, so the config follows the user's profile
+	if runtime.GOOS == "windows" {
+		dir, err := windowsDir("APPDATA", "AppData", "Roaming")
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(dir, "skint"), nil
+	}
+
 	// Fall back to ~/.config
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -291,13 +316,23 @@ func getConfigDir() (string, error) {
 	return filepath.Join(home, ".config", "skint"), nil
 }
 
-// GetDataDir returns the XDG-compliant data directory
+// GetDataDir returns the XDG-compliant data directory, or
+// %LOCALAPPDATA%\skint on Windows
 func GetDataDir() (string, error) {
 	// Check XDG_DATA_HOME
 	if xdg := os.Getenv("XDG_DATA_HOME"); xdg != "" {
 		return filepath.Join(xdg, "skint"), nil
 	}
 
+	// Windows: local app data, as secrets and usage are per-machine
+	if runtime.GOOS == "windows" {
+		dir, err := windowsDir("LOCALAPPDATA", "AppData", "Local")
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(dir, "skint"), nil
+	}
+
 	// Fall back to ~/.local/share
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -307,13 +342,23 @@ func GetDataDir() (string, error) {
 	return filepath.Join(home, ".local", "share", "skint"), nil
 }
 
-// GetCacheDir returns the XDG-compliant cache directory
+// GetCacheDir returns the XDG-compliant cache directory, or
+// %LOCALAPPDATA%\skint\cache on Windows
 func GetCacheDir() (string, error) {
 	// Check XDG_CACHE_HOME
 	if xdg := os.Getenv("XDG_CACHE_HOME"); xdg != "" {
 		return filepath.Join(xdg, "skint"), nil
 	}
 
+	// Windows: alongside the data directory in local app data
+	if runtime.GOOS == "windows" {
+		dir, err := windowsDir("LOCALAPPDATA", "AppData", "Local")
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(dir, "skint", "cache"), nil
+	}
+
 	// Fall back to ~/.cache
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -330,6 +375,15 @@ func GetBinDir() (string, error) {
 		return bin, nil
 	}
 
+	// Windows: %LOCALAPPDATA%\Programs\skint\bin, where per-user installs go
+	if runtime.GOOS == "windows" {
+		dir, err := windowsDir("LOCALAPPDATA", "AppData", "Local")
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(dir, "Programs", "skint", "bin"), nil
+	}
+
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return "", fmt.Errorf("failed to get home directory: %w", err)
@@ -342,3 +396,16 @@ func GetBinDir() (string, error) {
 
 	return filepath.Join(home, ".local", "bin"), nil
 }
+
+// windowsDir returns the Windows known folder in envVar (e.g. APPDATA), or
+// the default location under the user's profile if it is unset.
+func windowsDir(envVar string, fallback ...string) (string, error) {
+	if dir := os.Getenv(envVar); dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(append([]string{home}, fallback...)...), nil
+}
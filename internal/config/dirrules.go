@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// DirectoryRule makes Provider the default for working directories matching
+// Path, a glob where ~ is the home directory and ** matches any number of
+// directories (e.g. ~/work/**). A path without wildcards matches that
+// directory and everything below it.
+type DirectoryRule struct {
+	Path     string `yaml:"path" json:"path" toml:"path" mapstructure:"path"`
+	Provider string `yaml:"provider" json:"provider" toml:"provider" mapstructure:"provider"`
+
+	// Names of Config.EnvPresets added when Provider is launched from a
+	// matching directory
+	EnvPresets []string `yaml:"env_presets,omitempty" json:"env_presets,omitempty" toml:"env_presets,omitempty" mapstructure:"env_presets"`
+}
+
+// validate checks a rule's path is absolute and it names a provider.
+func (r *DirectoryRule) validate() error {
+	if r.Path == "" {
+		return fmt.Errorf("path is required")
+	}
+	if !filepath.IsAbs(expandHome(r.Path)) {
+		return fmt.Errorf("path %q must be absolute or start with ~", r.Path)
+	}
+	if r.Provider == "" {
+		return fmt.Errorf("provider is required")
+	}
+	return nil
+}
+
+// Matches reports whether dir matches the rule's path.
+func (r *DirectoryRule) Matches(dir string) bool {
+	pattern := expandHome(r.Path)
+	if !strings.ContainsAny(pattern, "*?[") {
+		pattern = filepath.Join(pattern, "**")
+	}
+	return matchSegments(pathSegments(pattern), pathSegments(dir))
+}
+
+// MatchDirectoryRule returns the first directory rule matching dir, or nil.
+func (c *Config) MatchDirectoryRule(dir string) *DirectoryRule {
+	for i := range c.DirectoryRules {
+		if c.DirectoryRules[i].Matches(dir) {
+			return &c.DirectoryRules[i]
+		}
+	}
+	return nil
+}
+
+// OverrideDefaultProvider makes name the default provider for this process
+// only. Like SKINT_DEFAULT_PROVIDER, it is not saved unless the default is
+// changed again. The provider must be configured (or "native").
+func (m *Manager) OverrideDefaultProvider(name string) error {
+	if name != "native" && m.config.GetProvider(name) == nil {
+		return fmt.Errorf("provider %s not found in config", name)
+	}
+	m.overrides.set(reflect.ValueOf(m.config).Elem(), "", "DefaultProvider", reflect.ValueOf(name))
+	return nil
+}
+
+// expandHome replaces a leading ~ with the user's home directory.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, `~\`) {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
+
+// pathSegments splits a cleaned path into its slash-separated segments.
+func pathSegments(path string) []string {
+	return strings.Split(filepath.ToSlash(filepath.Clean(path)), "/")
+}
+
+// matchSegments matches path segments against glob segments, where a **
+// segment matches zero or more path segments.
+func matchSegments(pattern, segs []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(segs); i++ {
+				if matchSegments(pattern[1:], segs[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segs) == 0 {
+			return false
+		}
+		if ok, _ := filepath.Match(pattern[0], segs[0]); !ok {
+			return false
+		}
+		pattern, segs = pattern[1:], segs[1:]
+	}
+	return len(segs) == 0
+}
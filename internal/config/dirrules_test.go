@@ -0,0 +1,122 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDirectoryRuleMatches(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	tests := []struct {
+		path string
+		dir  string
+		want bool
+	}{
+		{path: "~/work/**", dir: filepath.Join(home, "work"), want: true},
+		{path: "~/work/**", dir: filepath.Join(home, "work", "a", "b"), want: true},
+		{path: "~/work/**", dir: filepath.Join(home, "workshop"), want: false},
+		{path: "~/work", dir: filepath.Join(home, "work", "a"), want: true},
+		{path: "~/work/*", dir: filepath.Join(home, "work", "a"), want: true},
+		{path: "~/work/*", dir: filepath.Join(home, "work", "a", "b"), want: false},
+		{path: "~/**/oss/**", dir: filepath.Join(home, "src", "oss", "skint"), want: true},
+		{path: "~/src/skint-*", dir: filepath.Join(home, "src", "skint-cli"), want: true},
+		{path: "~/src/skint-*", dir: filepath.Join(home, "src", "other"), want: false},
+		{path: "/opt/**", dir: filepath.Join(home, "work"), want: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.path+" "+tc.dir, func(t *testing.T) {
+			r := DirectoryRule{Path: tc.path, Provider: "x"}
+			if got := r.Matches(tc.dir); got != tc.want {
+				t.Errorf("Matches: got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMatchDirectoryRuleFirstWins(t *testing.T) {
+	cfg := &Config{DirectoryRules: []DirectoryRule{
+		{Path: "/work/client/**", Provider: "client"},
+		{Path: "/work/**", Provider: "work"},
+	}}
+	if r := cfg.MatchDirectoryRule("/work/client/app"); r == nil || r.Provider != "client" {
+		t.Errorf("got %+v, want the client rule", r)
+	}
+	if r := cfg.MatchDirectoryRule("/work/other"); r == nil || r.Provider != "work" {
+		t.Errorf("got %+v, want the work rule", r)
+	}
+	if r := cfg.MatchDirectoryRule("/home"); r != nil {
+		t.Errorf("got %+v, want no match", r)
+	}
+}
+
+func TestValidateDirectoryRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    DirectoryRule
+		wantErr bool
+	}{
+		{name: "valid", rule: DirectoryRule{Path: "~/work/**", Provider: "zai"}},
+		{name: "provider need not be configured", rule: DirectoryRule{Path: "/srv", Provider: "gone"}},
+		{name: "relative path", rule: DirectoryRule{Path: "work/**", Provider: "zai"}, wantErr: true},
+		{name: "missing provider", rule: DirectoryRule{Path: "/srv"}, wantErr: true},
+		{name: "unknown preset", rule: DirectoryRule{Path: "/srv", Provider: "zai", EnvPresets: []string{"nope"}}, wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := NewDefaultConfig()
+			cfg.DirectoryRules = []DirectoryRule{tc.rule}
+			err := cfg.Validate()
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Validate: got %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestOverrideDefaultProviderNotPersisted(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := `version: "` + ConfigVersion + `"
+default_provider: home
+providers:
+  - name: home
+    type: local
+    base_url: http://localhost:1
+  - name: work
+    type: local
+    base_url: http://localhost:2
+`
+	if err := os.WriteFile(cfgPath, []byte(content), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	m := loadManager(t, cfgPath)
+	if err := m.OverrideDefaultProvider("missing"); err == nil {
+		t.Error("expected an error for an unconfigured provider")
+	}
+	if err := m.OverrideDefaultProvider("work"); err != nil {
+		t.Fatalf("OverrideDefaultProvider: %v", err)
+	}
+	if got := m.Get().DefaultProvider; got != "work" {
+		t.Errorf("runtime DefaultProvider: got %q, want work", got)
+	}
+	if err := m.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if got := loadManager(t, cfgPath).Get().DefaultProvider; got != "home" {
+		t.Errorf("persisted DefaultProvider: got %q, want home", got)
+	}
+
+	// Explicitly choosing the overridden provider does persist it
+	m.SetDefaultProvider("work")
+	if err := m.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if got := loadManager(t, cfgPath).Get().DefaultProvider; got != "work" {
+		t.Errorf("DefaultProvider after SetDefaultProvider: got %q, want work", got)
+	}
+}
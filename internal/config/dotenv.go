@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// dotenvKey matches a variable name in a dotenv file.
+var dotenvKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// ParseDotenv parses a dotenv file: KEY=value lines, optionally prefixed with
+// "export", with blank lines and # comments ignored. Values may be single
+// quoted (taken literally), double quoted (with \n, \t, \" and \\ escapes)
+// or bare, where a " #" starts a comment. Later assignments win. Variables
+// are not expanded.
+func ParseDotenv(data []byte) (map[string]string, error) {
+	env := map[string]string{}
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(line, "export "); ok {
+			line = strings.TrimSpace(rest)
+		}
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !dotenvKey.MatchString(key) {
+			return nil, fmt.Errorf("line %d: expected KEY=value", i+1)
+		}
+		value, err := dotenvValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		env[key] = value
+	}
+	return env, nil
+}
+
+// dotenvValue unquotes a dotenv value.
+func dotenvValue(v string) (string, error) {
+	switch {
+	case strings.HasPrefix(v, "'"):
+		end := strings.IndexByte(v[1:], '\'')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated ' quote")
+		}
+		return v[1 : end+1], dotenvTrailing(v[end+2:])
+	case strings.HasPrefix(v, `"`):
+		var b strings.Builder
+		for i := 1; i < len(v); i++ {
+			switch c := v[i]; {
+			case c == '\\' && i+1 < len(v):
+				i++
+				switch v[i] {
+				case 'n':
+					b.WriteByte('\n')
+				case 't':
+					b.WriteByte('\t')
+				case '"', '\\':
+					b.WriteByte(v[i])
+				default:
+					b.WriteByte('\\')
+					b.WriteByte(v[i])
+				}
+			case c == '"':
+				return b.String(), dotenvTrailing(v[i+1:])
+			default:
+				b.WriteByte(c)
+			}
+		}
+		return "", fmt.Errorf(`unterminated " quote`)
+	}
+	if i := strings.Index(v, " #"); i >= 0 {
+		v = strings.TrimSpace(v[:i])
+	}
+	return v, nil
+}
+
+// dotenvTrailing checks what follows a quoted value, which may only be a
+// comment.
+func dotenvTrailing(rest string) error {
+	if rest = strings.TrimSpace(rest); rest != "" && !strings.HasPrefix(rest, "#") {
+		return fmt.Errorf("unexpected text after quoted value")
+	}
+	return nil
+}
+
+// dotenvBare matches values written without quotes.
+var dotenvBare = regexp.MustCompile(`^[A-Za-z0-9_./:@,+=-]*$`)
+
+// DotenvLine returns a dotenv assignment of value to name, quoted so
+// ParseDotenv and docker compose read value back unchanged: bare when it is
+// safe, single quoted when it has no single quote, otherwise double quoted.
+func DotenvLine(name, value string) string {
+	switch {
+	case dotenvBare.MatchString(value):
+		return name + "=" + value
+	case !strings.ContainsAny(value, "'\n"):
+		return name + "='" + value + "'"
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`)
+	return name + `="` + r.Replace(value) + `"`
+}
@@ -0,0 +1,63 @@
+package config
+
+import "testing"
+
+func TestParseDotenv(t *testing.T) {
+	data := "# Z.AI\r\n" +
+		"export ANTHROPIC_BASE_URL=https://api.z.ai/api/anthropic # endpoint\n" +
+		"ANTHROPIC_AUTH_TOKEN = 'sk-#not-a-comment'\n" +
+		"\n" +
+		`ANTHROPIC_MODEL="glm \"5\"\tx\\y"` + "\n" +
+		"EMPTY=\n" +
+		"HASH=a#b\n" +
+		"ANTHROPIC_MODEL=\"glm-5\" # later wins\n"
+	got, err := ParseDotenv([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"ANTHROPIC_BASE_URL":   "https://api.z.ai/api/anthropic",
+		"ANTHROPIC_AUTH_TOKEN": "sk-#not-a-comment",
+		"ANTHROPIC_MODEL":      "glm-5",
+		"EMPTY":                "",
+		"HASH":                 "a#b",
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %q, want %q", k, got[k], v)
+		}
+	}
+
+	escaped, err := ParseDotenv([]byte(`M="glm \"5\"\tx\\y"`))
+	if err != nil || escaped["M"] != "glm \"5\"\tx\\y" {
+		t.Errorf("escapes = %q, %v", escaped["M"], err)
+	}
+
+	for _, bad := range []string{"just text", "1KEY=x", `KEY="open`, "KEY='a' b"} {
+		if _, err := ParseDotenv([]byte(bad)); err == nil {
+			t.Errorf("ParseDotenv(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestDotenvLine(t *testing.T) {
+	tests := map[string]string{
+		"https://api.z.ai/api/anthropic": "K=https://api.z.ai/api/anthropic",
+		"":                               "K=",
+		"a b #c":                         "K='a b #c'",
+		"it's \"x\"\n":                   `K="it's \"x\"\n"`,
+	}
+	for value, want := range tests {
+		line := DotenvLine("K", value)
+		if line != want {
+			t.Errorf("DotenvLine(%q) = %s, want %s", value, line, want)
+		}
+		env, err := ParseDotenv([]byte(line))
+		if err != nil || env["K"] != value {
+			t.Errorf("%s reads back as %q, %v; want %q", line, env["K"], err, value)
+		}
+	}
+}
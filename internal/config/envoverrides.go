@@ -0,0 +1,342 @@
+package config
+
+import (
+	"encoding"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Env override prefixes. Top-level settings map to SKINT_<KEY> and provider
+// settings to SKINT_PROVIDER_<NAME>_<KEY>, where KEY is the upper-cased config
+// file key (e.g. SKINT_NO_BANNER, SKINT_PROVIDER_ZAI_MODEL).
+const (
+	envPrefix         = "SKINT_"
+	providerEnvPrefix = "SKINT_PROVIDER_"
+)
+
+// envNameInvalid matches characters that cannot appear in an env var name.
+var envNameInvalid = regexp.MustCompile(`[^A-Z0-9_]`)
+
+// envValidators check env values for fields whose invalid values would
+// otherwise fail validation and block every command. Invalid values are
+// ignored with a warning.
+var envValidators = map[string]func(string) error{
+	"OutputFormat": func(v string) error {
+		switch v {
+		case FormatHuman, FormatJSON, FormatPlain, FormatTable:
+			return nil
+		}
+		return fmt.Errorf("valid: %s, %s, %s, %s", FormatHuman, FormatJSON, FormatPlain, FormatTable)
+	},
+	"Timeout": func(v string) error {
+		_, err := ParseTimeout(v)
+		return err
+	},
+	"Retries": func(v string) error {
+		_, err := ParseRetries(v)
+		return err
+	},
+	"RetryBackoff": func(v string) error {
+		_, err := ParseTimeout(v)
+		return err
+	},
+	"ModelCacheTTL": func(v string) error {
+		_, err := ParseCacheTTL(v)
+		return err
+	},
+	"Watchdog": validateWatchdog,
+	"Target":   validateTarget,
+	"APIType": func(v string) error {
+		switch v {
+		case APITypeAnthropic, APITypeOpenAI:
+			return nil
+		}
+		return fmt.Errorf("valid: %s, %s", APITypeAnthropic, APITypeOpenAI)
+	},
+}
+
+// envOverrides records persisted config values that were replaced by SKINT_*
+// environment overrides at Load time. Save reverts to these so transient env
+// settings are never written to disk.
+type envOverrides []*fieldOverride
+
+// fieldOverride pairs the persisted value of one field with the env value
+// that replaced it.
+type fieldOverride struct {
+	provider  string // provider name, or "" for a top-level setting
+	field     string // Go struct field name
+	persisted any
+	applied   any
+}
+
+// revert restores the persisted value in target while the field still equals
+// the applied override. A deliberate change (e.g. the TUI setting a new
+// default provider) must win, so any other value is left alone.
+func (o *fieldOverride) revert(target reflect.Value) {
+	f := target.FieldByName(o.field)
+	if reflect.DeepEqual(f.Interface(), o.applied) {
+		f.Set(reflect.ValueOf(o.persisted))
+	}
+}
+
+// find returns the override for a field, or nil if it was not overridden.
+func (o envOverrides) find(provider, field string) *fieldOverride {
+	for _, fo := range o {
+		if fo.provider == provider && fo.field == field {
+			return fo
+		}
+	}
+	return nil
+}
+
+// remove drops an override, e.g. once it has been undone at Load time.
+func (o *envOverrides) remove(target *fieldOverride) {
+	for i, fo := range *o {
+		if fo == target {
+			*o = append((*o)[:i], (*o)[i+1:]...)
+			return
+		}
+	}
+}
+
+// set applies value to a field of target and records the override. The first
+// recorded persisted value is kept if the field is overridden more than once.
+func (o *envOverrides) set(target reflect.Value, provider, field string, value reflect.Value) {
+	f := target.FieldByName(field)
+	if existing := o.find(provider, field); existing != nil {
+		existing.applied = value.Interface()
+	} else {
+		*o = append(*o, &fieldOverride{
+			provider:  provider,
+			field:     field,
+			persisted: f.Interface(),
+			applied:   value.Interface(),
+		})
+	}
+	f.Set(value)
+}
+
+// revert returns c's overridden fields to their persisted values. Overridden
+// providers are copied first so the runtime config is left untouched.
+func (o envOverrides) revert(c *Config) {
+	if len(o) == 0 {
+		return
+	}
+
+	c.Providers = append([]*Provider(nil), c.Providers...)
+	for _, fo := range o {
+		if fo.provider == "" {
+			fo.revert(reflect.ValueOf(c).Elem())
+			continue
+		}
+		for i, p := range c.Providers {
+			if p.Name == fo.provider {
+				cp := *p
+				fo.revert(reflect.ValueOf(&cp).Elem())
+				c.Providers[i] = &cp
+				break
+			}
+		}
+	}
+}
+
+// envField is a config struct field that can be set from the environment.
+type envField struct {
+	name string // Go struct field name
+	key  string // env var suffix, from the config file key
+}
+
+// envFields returns the fields of struct type t that can be overridden:
+// string, bool, int, Duration and []string fields (or pointers to them) with
+// a config file key, excluding any tagged `env:"-"`.
+func envFields(t reflect.Type) []envField {
+	var fields []envField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || f.Tag.Get("env") == "-" {
+			continue
+		}
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		switch ft.Kind() {
+		case reflect.String, reflect.Bool, reflect.Int:
+		case reflect.Int64:
+			if ft != reflect.TypeFor[Duration]() {
+				continue
+			}
+		case reflect.Slice:
+			if ft.Elem().Kind() != reflect.String {
+				continue
+			}
+		default:
+			continue
+		}
+		key, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if key == "" || key == "-" {
+			continue
+		}
+		fields = append(fields, envField{name: f.Name, key: strings.ToUpper(key)})
+	}
+	return fields
+}
+
+// parseEnvValue converts an env var value to type t. Booleans accept the
+// usual true/false spellings; lists are comma-separated; types with an
+// UnmarshalText method (e.g. Duration) parse the value themselves. For a
+// pointer type the value is parsed as what it points to.
+func parseEnvValue(t reflect.Type, raw string) (reflect.Value, error) {
+	if t.Kind() == reflect.Pointer {
+		v, err := parseEnvValue(t.Elem(), raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		ptr := reflect.New(t.Elem())
+		ptr.Elem().Set(v)
+		return ptr, nil
+	}
+	if u, ok := reflect.New(t).Interface().(encoding.TextUnmarshaler); ok {
+		if err := u.UnmarshalText([]byte(raw)); err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(u).Elem(), nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		switch strings.ToLower(strings.TrimSpace(raw)) {
+		case "1", "true", "yes", "on":
+			return reflect.ValueOf(true), nil
+		case "0", "false", "no", "off":
+			return reflect.ValueOf(false), nil
+		}
+		return reflect.Value{}, fmt.Errorf("expected true or false")
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return reflect.ValueOf(items), nil
+	case reflect.Int:
+		n, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("expected a whole number")
+		}
+		return reflect.ValueOf(n).Convert(t), nil
+	default:
+		return reflect.ValueOf(raw).Convert(t), nil
+	}
+}
+
+// providerEnvName returns the env var name segment for a provider name,
+// e.g. "my-llm" becomes "MY_LLM".
+func providerEnvName(name string) string {
+	return envNameInvalid.ReplaceAllString(strings.ToUpper(name), "_")
+}
+
+// Override sets a top-level config field (by Go field name) for this run
+// only, e.g. from a command-line flag. Like an env override, Save and Export
+// keep the persisted value.
+func (m *Manager) Override(field string, value any) {
+	m.overrides.set(reflect.ValueOf(m.config).Elem(), "", field, reflect.ValueOf(value))
+}
+
+// SetDefaultProvider makes name the saved default provider, dropping any
+// runtime override of it (SKINT_DEFAULT_PROVIDER or a directory rule) so Save
+// keeps it.
+func (m *Manager) SetDefaultProvider(name string) {
+	if o := m.overrides.find("", "DefaultProvider"); o != nil {
+		m.overrides.remove(o)
+	}
+	m.config.DefaultProvider = name
+}
+
+// applyEnvOverrides applies SKINT_* environment variable overrides to every
+// top-level setting and to each configured provider, recording the
+// pre-override values so Save can revert them (see envOverrides).
+func (m *Manager) applyEnvOverrides() {
+	m.overrides = envOverrides{}
+
+	// Top-level keys such as provider_order share the provider prefix
+	known := make(map[string]bool)
+	for _, env := range m.applyEnvFields(reflect.ValueOf(m.config).Elem(), "", envPrefix) {
+		known[env] = true
+	}
+
+	// NO_COLOR (https://no-color.org) and SKINT_NO_COLOR are kept as aliases
+	if os.Getenv("SKINT_NO_COLOR") != "" || os.Getenv("NO_COLOR") != "" {
+		m.overrides.set(reflect.ValueOf(m.config).Elem(), "", "ColorEnabled", reflect.ValueOf(false))
+	}
+
+	for _, p := range m.config.Providers {
+		prefix := providerEnvPrefix + providerEnvName(p.Name) + "_"
+		for _, env := range m.applyEnvFields(reflect.ValueOf(p).Elem(), p.Name, prefix) {
+			known[env] = true
+		}
+	}
+
+	// Flag SKINT_PROVIDER_* vars that match nothing, as a typo would otherwise
+	// be silently ignored
+	for _, kv := range os.Environ() {
+		env, _, _ := strings.Cut(kv, "=")
+		if strings.HasPrefix(env, providerEnvPrefix) && !known[env] {
+			fmt.Fprintf(os.Stderr, "warning: ignoring %s: no matching provider setting\n", env)
+		}
+	}
+}
+
+// applyEnvFields applies prefix+KEY env vars to the overridable fields of
+// target, returning every env var name it checked.
+func (m *Manager) applyEnvFields(target reflect.Value, provider, prefix string) []string {
+	fields := envFields(target.Type())
+	names := make([]string, 0, len(fields))
+
+	for _, field := range fields {
+		env := prefix + field.key
+		names = append(names, env)
+
+		raw := os.Getenv(env)
+		if raw == "" {
+			continue
+		}
+		value, err := parseEnvValue(target.FieldByName(field.name).Type(), raw)
+		if err == nil {
+			if validate, ok := envValidators[field.name]; ok {
+				err = validate(raw)
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: ignoring invalid %s=%q (%v)\n", env, raw, err)
+			continue
+		}
+		m.overrides.set(target, provider, field.name, value)
+	}
+
+	return names
+}
+
+// resolveDefaultProviderOverride handles a SKINT_DEFAULT_PROVIDER that names an
+// unknown provider: rather than failing validation, warn and fall back to the
+// persisted default.
+func (m *Manager) resolveDefaultProviderOverride() {
+	o := m.overrides.find("", "DefaultProvider")
+	if o == nil {
+		return
+	}
+	name := m.config.DefaultProvider
+	if name == "native" || m.config.GetProvider(name) != nil {
+		return
+	}
+	persisted, _ := o.persisted.(string)
+	fmt.Fprintf(os.Stderr, "warning: SKINT_DEFAULT_PROVIDER=%q not found in config; using %q\n",
+		name, persisted)
+	m.config.DefaultProvider = persisted
+	m.overrides.remove(o)
+}
@@ -0,0 +1,216 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+)
+
+// DefaultSyncBranch is the branch skint sync uses when sync_branch is unset
+const DefaultSyncBranch = "main"
+
+// Export returns the user config as Save would write it, encoded as YAML.
+// API keys are never included: resolved keys are not serialised and legacy
+// plaintext api_key values are dropped.
+func (m *Manager) Export() ([]byte, error) {
+	c := m.configForSave()
+	withoutSecrets(&c, false)
+	return encodeExport(&c)
+}
+
+// ExportShared is Export without machine-specific settings (API key
+// references and the sync settings) and with MCP server env and header
+// values redacted. It is what skint sync shares between machines; see
+// RestoreLocalSettings for the reverse.
+func (m *Manager) ExportShared() ([]byte, error) {
+	c := m.configForSave()
+	withoutSecrets(&c, true)
+	for _, p := range c.Providers {
+		p.MCPServers = redactMCPServers(p.MCPServers)
+	}
+	c.SyncRemote = ""
+	c.SyncBranch = ""
+	return encodeExport(&c)
+}
+
+// Redacted stands in for secrets in a provider exported by ExportProvider.
+const Redacted = "REDACTED"
+
+// ExportProvider returns provider name as a YAML config fragment to share
+// with others: the provider, with its plaintext API key, auth token and MCP
+// server env and header values redacted and its key reference dropped, and
+// the env presets it uses.
+func (c *Config) ExportProvider(name string) ([]byte, error) {
+	p := c.GetProvider(name)
+	if p == nil {
+		return nil, fmt.Errorf("provider %s is not configured", name)
+	}
+	cp := *p
+	cp.APIKeyRef = ""
+	if cp.APIKey != "" {
+		cp.APIKey = Redacted
+	}
+	if cp.AuthToken != "" {
+		cp.AuthToken = Redacted
+	}
+	cp.MCPServers = redactMCPServers(p.MCPServers)
+
+	shared := struct {
+		Providers  []*Provider                  `yaml:"providers"`
+		EnvPresets map[string]map[string]string `yaml:"env_presets,omitempty"`
+	}{Providers: []*Provider{&cp}}
+	for _, preset := range p.EnvPresets {
+		if env, ok := c.EnvPresets[preset]; ok {
+			if shared.EnvPresets == nil {
+				shared.EnvPresets = map[string]map[string]string{}
+			}
+			shared.EnvPresets[preset] = env
+		}
+	}
+	return encodeExport(&shared)
+}
+
+// RestoreLocalSettings copies the machine-specific settings dropped by
+// ExportShared from local into c: the sync settings, the API key reference
+// of every provider local also has, and redacted values (see
+// RestoreRedacted).
+func (c *Config) RestoreLocalSettings(local *Config) {
+	c.SyncRemote = local.SyncRemote
+	c.SyncBranch = local.SyncBranch
+	for _, p := range c.Providers {
+		if lp := local.GetProvider(p.Name); lp != nil {
+			p.APIKeyRef = lp.APIKeyRef
+		}
+	}
+	c.RestoreRedacted(local)
+}
+
+// RestoreRedacted replaces MCP server env and header values redacted by an
+// export with local's values for the same provider, server and key. Values
+// local doesn't have stay redacted.
+func (c *Config) RestoreRedacted(local *Config) {
+	for _, p := range c.Providers {
+		lp := local.GetProvider(p.Name)
+		if lp == nil {
+			continue
+		}
+		for name, s := range p.MCPServers {
+			ls, ok := lp.MCPServers[name]
+			if !ok {
+				continue
+			}
+			restoreValues(s.Env, ls.Env)
+			restoreValues(s.Headers, ls.Headers)
+		}
+	}
+}
+
+// DropRedacted removes MCP server env and header values still redacted
+// after RestoreRedacted, so the placeholder is never saved or passed to a
+// server. It returns them as provider/server/key, sorted.
+func (c *Config) DropRedacted() []string {
+	var dropped []string
+	for _, p := range c.Providers {
+		for name, s := range p.MCPServers {
+			for _, values := range []map[string]string{s.Env, s.Headers} {
+				for k, v := range values {
+					if v == Redacted {
+						delete(values, k)
+						dropped = append(dropped, p.Name+"/"+name+"/"+k)
+					}
+				}
+			}
+		}
+	}
+	slices.Sort(dropped)
+	return dropped
+}
+
+// restoreValues sets each redacted value in values to local's value for
+// the key, if it has one.
+func restoreValues(values, local map[string]string) {
+	for k, v := range values {
+		if lv, ok := local[k]; ok && v == Redacted {
+			values[k] = lv
+		}
+	}
+}
+
+// SyncBranchName returns the branch skint sync uses.
+func (c *Config) SyncBranchName() string {
+	if c.SyncBranch != "" {
+		return c.SyncBranch
+	}
+	return DefaultSyncBranch
+}
+
+// withoutSecrets clears plaintext API keys (and key references when refs is
+// set) from c. Providers are copied so the runtime config is left untouched.
+func withoutSecrets(c *Config, refs bool) {
+	providers := make([]*Provider, len(c.Providers))
+	for i, p := range c.Providers {
+		cp := *p
+		cp.APIKey = ""
+		if refs {
+			cp.APIKeyRef = ""
+		}
+		providers[i] = &cp
+	}
+	c.Providers = providers
+}
+
+// redactMCPServers returns a copy of servers with their env and header
+// values, which may hold tokens, replaced by Redacted. The keys are kept.
+func redactMCPServers(servers map[string]MCPServer) map[string]MCPServer {
+	if servers == nil {
+		return nil
+	}
+	out := make(map[string]MCPServer, len(servers))
+	for name, s := range servers {
+		s.Env = redactValues(s.Env)
+		s.Headers = redactValues(s.Headers)
+		out[name] = s
+	}
+	return out
+}
+
+// redactValues returns a copy of values with every value replaced by
+// Redacted.
+func redactValues(values map[string]string) map[string]string {
+	if values == nil {
+		return nil
+	}
+	out := make(map[string]string, len(values))
+	for k := range values {
+		out[k] = Redacted
+	}
+	return out
+}
+
+func encodeExport(c any) ([]byte, error) {
+	data, err := encodeConfig(FileFormatYAML, c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	return data, nil
+}
+
+// ParseConfig decodes a config document in any supported format, upgrading
+// older schema versions, and validates the result. It is used to read
+// configs that did not come from the config file, such as backups.
+func ParseConfig(data []byte) (*Config, error) {
+	format := detectFormat("", data)
+	data, _, err := upgradeData(format, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upgrade config: %w", err)
+	}
+
+	cfg := NewDefaultConfig()
+	if err := decodeConfig(format, data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	foldDefaultModels(cfg.Providers)
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,171 @@
+package config
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExportParseRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	m := loadManager(t, path)
+
+	p := &Provider{Name: "zai", Type: ProviderTypeBuiltin, BaseURL: "https://api.z.ai/api/anthropic", Model: "glm-4.7", APIKeyRef: "keyring:zai"}
+	p.SetResolvedAPIKey("sk-secret")
+	m.Get().Providers = append(m.Get().Providers, p)
+	m.Get().DefaultProvider = "zai"
+	if err := m.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// Runtime overrides (e.g. --output json) are not exported
+	m.Override("OutputFormat", FormatJSON)
+
+	data, err := m.Export()
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if strings.Contains(string(data), "sk-secret") {
+		t.Error("export contains the resolved API key")
+	}
+
+	cfg, err := ParseConfig(data)
+	if err != nil {
+		t.Fatalf("ParseConfig: %v", err)
+	}
+	got := cfg.GetProvider("zai")
+	if got == nil || got.Model != "glm-4.7" || got.APIKeyRef != "keyring:zai" {
+		t.Errorf("round-tripped provider = %+v", got)
+	}
+	if cfg.OutputFormat != FormatHuman {
+		t.Errorf("OutputFormat = %q, want the persisted %q", cfg.OutputFormat, FormatHuman)
+	}
+	if cfg.DefaultProvider != "zai" {
+		t.Errorf("DefaultProvider = %q, want zai", cfg.DefaultProvider)
+	}
+}
+
+func TestParseConfigUpgradesOldSchema(t *testing.T) {
+	data := []byte(`version: "1.1"
+providers:
+  - name: zai
+    type: builtin
+    base_url: https://api.z.ai/api/anthropic
+    default_model: glm-4.7
+`)
+	cfg, err := ParseConfig(data)
+	if err != nil {
+		t.Fatalf("ParseConfig: %v", err)
+	}
+	if cfg.Version != ConfigVersion {
+		t.Errorf("Version = %q, want %q", cfg.Version, ConfigVersion)
+	}
+	if p := cfg.GetProvider("zai"); p == nil || p.Model != "glm-4.7" {
+		t.Errorf("provider = %+v, want model glm-4.7", p)
+	}
+
+	if _, err := ParseConfig([]byte("providers:\n  - type: builtin\n")); err == nil {
+		t.Error("expected an error for an invalid config")
+	}
+}
+
+func TestExportSharedDropsLocalSettings(t *testing.T) {
+	m := loadManager(t, filepath.Join(t.TempDir(), "config.yaml"))
+	m.Get().SyncRemote = "git@example.com:me/skint.git"
+	m.Get().Providers = append(m.Get().Providers,
+		&Provider{Name: "zai", Type: ProviderTypeBuiltin, BaseURL: "https://api.z.ai/api/anthropic", Model: "glm-4.7", APIKeyRef: "keyring:zai", APIKey: "sk-legacy",
+			MCPServers: map[string]MCPServer{"fs": {Command: "mcp-fs", Env: map[string]string{"FS_TOKEN": "mcp-env"}}}})
+
+	data, err := m.ExportShared()
+	if err != nil {
+		t.Fatalf("ExportShared: %v", err)
+	}
+	for _, s := range []string{"keyring:zai", "sk-legacy", "sync_remote", "mcp-env"} {
+		if strings.Contains(string(data), s) {
+			t.Errorf("shared export contains %q:\n%s", s, data)
+		}
+	}
+	if m.Get().Providers[0].APIKeyRef != "keyring:zai" {
+		t.Error("ExportShared modified the runtime config")
+	}
+
+	cfg, err := ParseConfig(data)
+	if err != nil {
+		t.Fatalf("ParseConfig: %v", err)
+	}
+	cfg.RestoreLocalSettings(m.Get())
+	if cfg.SyncRemote != "git@example.com:me/skint.git" || cfg.GetProvider("zai").APIKeyRef != "keyring:zai" {
+		t.Errorf("RestoreLocalSettings: remote=%q ref=%q", cfg.SyncRemote, cfg.GetProvider("zai").APIKeyRef)
+	}
+	if got := cfg.GetProvider("zai").MCPServers["fs"].Env["FS_TOKEN"]; got != "mcp-env" {
+		t.Errorf("RestoreLocalSettings: FS_TOKEN = %q, want the local value", got)
+	}
+
+	// Export is for encrypted backups, so it keeps MCP values
+	full, err := m.Export()
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if !strings.Contains(string(full), "mcp-env") {
+		t.Errorf("Export redacted an MCP env value:\n%s", full)
+	}
+	if cfg.SyncBranchName() != DefaultSyncBranch {
+		t.Errorf("SyncBranchName = %q", cfg.SyncBranchName())
+	}
+}
+
+func TestExportProvider(t *testing.T) {
+	c := NewDefaultConfig()
+	c.EnvPresets = map[string]map[string]string{
+		"corp-proxy": {"HTTPS_PROXY": "http://proxy:3128"},
+		"unused":     {"FOO": "bar"},
+	}
+	p := &Provider{Name: "ollama", Type: ProviderTypeLocal, BaseURL: "http://localhost:11434", AuthToken: "tok-secret", APIKeyRef: "keyring:ollama", EnvPresets: []string{"corp-proxy"}}
+	p.MCPServers = map[string]MCPServer{
+		"docs": {Type: MCPTypeHTTP, URL: "https://mcp.example.com", Headers: map[string]string{"Authorization": "Bearer mcp-header"}},
+		"fs":   {Command: "mcp-fs", Env: map[string]string{"FS_TOKEN": "mcp-env"}},
+	}
+	p.SetResolvedAPIKey("sk-secret")
+	c.Providers = append(c.Providers, p)
+
+	data, err := c.ExportProvider("ollama")
+	if err != nil {
+		t.Fatalf("ExportProvider: %v", err)
+	}
+	out := string(data)
+	for _, want := range []string{"name: ollama", "base_url: http://localhost:11434", "auth_token: " + Redacted, "corp-proxy", "HTTPS_PROXY", "Authorization: " + Redacted, "FS_TOKEN: " + Redacted} {
+		if !strings.Contains(out, want) {
+			t.Errorf("export missing %q:\n%s", want, out)
+		}
+	}
+	for _, secret := range []string{"tok-secret", "sk-secret", "keyring:", "unused", "default_provider", "mcp-header", "mcp-env"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("export must not contain %q:\n%s", secret, out)
+		}
+	}
+	if p.AuthToken != "tok-secret" || p.APIKeyRef != "keyring:ollama" || p.MCPServers["fs"].Env["FS_TOKEN"] != "mcp-env" {
+		t.Error("ExportProvider must not change the config")
+	}
+
+	if _, err := c.ExportProvider("missing"); err == nil {
+		t.Error("expected an error for a provider that isn't configured")
+	}
+}
+
+func TestDropRedacted(t *testing.T) {
+	c := NewDefaultConfig()
+	c.Providers = append(c.Providers, &Provider{Name: "zai", MCPServers: map[string]MCPServer{
+		"docs": {URL: "https://mcp.example.com", Headers: map[string]string{"Authorization": Redacted, "Accept": "application/json"}},
+		"fs":   {Command: "mcp-fs", Env: map[string]string{"FS_TOKEN": Redacted, "FS_ROOT": "${HOME}"}},
+	}})
+
+	dropped := c.DropRedacted()
+	if want := []string{"zai/docs/Authorization", "zai/fs/FS_TOKEN"}; !slices.Equal(dropped, want) {
+		t.Errorf("DropRedacted = %v, want %v", dropped, want)
+	}
+	servers := c.GetProvider("zai").MCPServers
+	if _, ok := servers["fs"].Env["FS_TOKEN"]; ok || servers["fs"].Env["FS_ROOT"] != "${HOME}" || servers["docs"].Headers["Accept"] != "application/json" {
+		t.Errorf("servers after DropRedacted = %+v", servers)
+	}
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Config file formats
+const (
+	FileFormatYAML = "yaml"
+	FileFormatJSON = "json"
+	FileFormatTOML = "toml"
+)
+
+// configFileNames are the default config file names, in lookup order. YAML
+// comes first so it remains the default for new installs.
+var configFileNames = []string{"config.yaml", "config.yml", "config.json", "config.toml"}
+
+// tomlLine matches a TOML table header or key/value assignment at the start of
+// a line, which YAML never produces.
+var tomlLine = regexp.MustCompile(`(?m)^\s*(\[\[?[A-Za-z0-9_.\-]+\]\]?|[A-Za-z0-9_\-]+\s*=)`)
+
+// defaultConfigFile returns the first existing config file in dir, or
+// config.yaml if none exists yet.
+func defaultConfigFile(dir string) string {
+	for _, name := range configFileNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Lstat(path); err == nil {
+			return path
+		}
+	}
+	return filepath.Join(dir, configFileNames[0])
+}
+
+// formatFromPath returns the config format implied by the file extension, or
+// "" if the extension is not recognised.
+func formatFromPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FileFormatYAML
+	case ".json":
+		return FileFormatJSON
+	case ".toml":
+		return FileFormatTOML
+	default:
+		return ""
+	}
+}
+
+// detectFormat returns the format of a config file, from its extension when
+// recognised, otherwise by sniffing the content. YAML is the fallback.
+func detectFormat(path string, data []byte) string {
+	if format := formatFromPath(path); format != "" {
+		return format
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		return FileFormatJSON
+	}
+	if tomlLine.Match(trimmed) {
+		return FileFormatTOML
+	}
+	return FileFormatYAML
+}
+
+// decodeConfig parses data in the given format into v.
+func decodeConfig(format string, data []byte, v any) error {
+	switch format {
+	case FileFormatJSON:
+		return json.Unmarshal(data, v)
+	case FileFormatTOML:
+		return toml.Unmarshal(data, v)
+	default:
+		return yaml.Unmarshal(data, v)
+	}
+}
+
+// encodeConfig serialises v in the given format.
+func encodeConfig(format string, v any) ([]byte, error) {
+	switch format {
+	case FileFormatJSON:
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	case FileFormatTOML:
+		return toml.Marshal(v)
+	case FileFormatYAML:
+		return yaml.Marshal(v)
+	default:
+		return nil, fmt.Errorf("unknown config format: %s", format)
+	}
+}
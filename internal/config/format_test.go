@@ -0,0 +1,130 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		data string
+		want string
+	}{
+		{"yaml extension", "config.yaml", `{"version": "1.0"}`, FileFormatYAML},
+		{"yml extension", "config.yml", "", FileFormatYAML},
+		{"json extension", "config.json", "", FileFormatJSON},
+		{"toml extension", "config.TOML", "", FileFormatTOML},
+		{"json content", "skintrc", "  {\n  \"version\": \"1.0\"\n}", FileFormatJSON},
+		{"toml content", "skintrc", "version = \"1.0\"\n\n[[providers]]\nname = \"x\"\n", FileFormatTOML},
+		{"toml table only", "skintrc", "[env_presets.proxy]\nHTTPS_PROXY = \"x\"\n", FileFormatTOML},
+		{"yaml content", "skintrc", "version: \"1.0\"\nproviders: []\n", FileFormatYAML},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := detectFormat(tc.path, []byte(tc.data)); got != tc.want {
+				t.Errorf("detectFormat(%q): got %q, want %q", tc.path, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestConfigFormatRoundTrip(t *testing.T) {
+	for _, format := range []string{FileFormatJSON, FileFormatTOML} {
+		t.Run(format, func(t *testing.T) {
+			dir := t.TempDir()
+			cfgPath := filepath.Join(dir, "config."+format)
+
+			m, err := NewManagerWithPath(cfgPath)
+			if err != nil {
+				t.Fatalf("NewManagerWithPath: %v", err)
+			}
+			cfg := m.Get()
+			cfg.DefaultProvider = "my-local"
+			cfg.ClaudeArgs = []string{"--continue"}
+			cfg.EnvPresets = map[string]map[string]string{"proxy": {"HTTPS_PROXY": "http://proxy:3128"}}
+			cfg.Providers = []*Provider{
+				{
+					Name:          "my-local",
+					Type:          ProviderTypeLocal,
+					BaseURL:       "http://localhost:8080",
+					ModelMappings: map[string]string{"sonnet": "qwen3"},
+					EnvPresets:    []string{"proxy"},
+				},
+			}
+			if err := m.Save(); err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+
+			m2, _ := NewManagerWithPath(cfgPath)
+			if err := m2.Load(); err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if m2.Format() != format {
+				t.Errorf("Format: got %q, want %q", m2.Format(), format)
+			}
+			loaded := m2.Get()
+			if loaded.DefaultProvider != "my-local" || len(loaded.ClaudeArgs) != 1 {
+				t.Errorf("top-level settings not preserved: %+v", loaded)
+			}
+			p := loaded.GetProvider("my-local")
+			if p == nil || p.ModelMappings["sonnet"] != "qwen3" || len(p.EnvPresets) != 1 {
+				t.Errorf("provider not preserved: %+v", p)
+			}
+			if loaded.EnvPresets["proxy"]["HTTPS_PROXY"] != "http://proxy:3128" {
+				t.Errorf("env presets not preserved: %v", loaded.EnvPresets)
+			}
+		})
+	}
+}
+
+func TestSavePreservesSniffedFormat(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "skintrc")
+	if err := os.WriteFile(cfgPath, []byte("version = \"1.0\"\nno_banner = true\n"), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	m, _ := NewManagerWithPath(cfgPath)
+	if err := m.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	m.Get().DefaultProvider = "native"
+	if err := m.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	data, err := os.ReadFile(cfgPath)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if !strings.Contains(string(data), "default_provider = 'native'") &&
+		!strings.Contains(string(data), `default_provider = "native"`) {
+		t.Errorf("expected TOML output, got:\n%s", data)
+	}
+}
+
+func TestDefaultConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	if got := defaultConfigFile(dir); got != filepath.Join(dir, "config.yaml") {
+		t.Errorf("no config: got %q, want config.yaml", got)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "config.toml"), []byte(""), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if got := defaultConfigFile(dir); got != filepath.Join(dir, "config.toml") {
+		t.Errorf("toml only: got %q, want config.toml", got)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(""), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if got := defaultConfigFile(dir); got != filepath.Join(dir, "config.yaml") {
+		t.Errorf("yaml and toml: got %q, want config.yaml to win", got)
+	}
+}
@@ -0,0 +1,192 @@
+package config
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// HistoryFile holds the launch history in the data directory, one JSON
+// object per line. A launch is appended when it starts; its exit code and
+// duration follow in a second line with the same ID when skint is still
+// around to see Claude exit.
+const HistoryFile = "history.jsonl"
+
+// Once the history file passes historyCompactSize it is rewritten with only
+// the newest historyKeep launches.
+const (
+	historyCompactSize = 1 << 20
+	historyKeep        = 2000
+)
+
+// HistoryEntry is one launch of Claude (or, for skint exec, another command)
+// with a provider.
+type HistoryEntry struct {
+	ID       string    `json:"id"`
+	Time     time.Time `json:"time,omitzero"`
+	Provider string    `json:"provider,omitempty"`
+	Model    string    `json:"model,omitempty"`
+	Command  string    `json:"command,omitempty"`
+	Dir      string    `json:"dir,omitempty"`
+	// ExitCode and Seconds are unknown (nil and 0) when Claude replaced
+	// skint's process, which it does unless exit_summary or the watchdog is on
+	ExitCode *int  `json:"exit_code,omitempty"`
+	Seconds  int64 `json:"duration_seconds,omitempty"`
+}
+
+// Duration is how long the launch ran, or 0 if unknown.
+func (e HistoryEntry) Duration() time.Duration {
+	return time.Duration(e.Seconds) * time.Second
+}
+
+// Finished reports whether the launch's exit was recorded.
+func (e HistoryEntry) Finished() bool {
+	return e.ExitCode != nil
+}
+
+// RecordLaunch appends e to the history, setting its ID and time, and
+// returns the ID for RecordExit.
+func RecordLaunch(e HistoryEntry) (string, error) {
+	path, err := historyPath()
+	if err != nil {
+		return "", err
+	}
+	id := make([]byte, 6)
+	_, _ = rand.Read(id)
+	e.ID = hex.EncodeToString(id)
+	e.Time = time.Now().UTC().Truncate(time.Second)
+	e.ExitCode, e.Seconds = nil, 0
+	if info, err := os.Stat(path); err == nil && info.Size() > historyCompactSize {
+		_ = compactHistory(path, historyKeep)
+	}
+	return e.ID, appendHistory(path, e)
+}
+
+// RecordExit records how the launch with id ended.
+func RecordExit(id string, exitCode int, d time.Duration) error {
+	path, err := historyPath()
+	if err != nil {
+		return err
+	}
+	return appendHistory(path, HistoryEntry{ID: id, ExitCode: &exitCode, Seconds: int64(d.Round(time.Second) / time.Second)})
+}
+
+// LoadHistory reads the launch history, oldest first. A missing file is an
+// empty history.
+func LoadHistory() ([]HistoryEntry, error) {
+	path, err := historyPath()
+	if err != nil {
+		return nil, err
+	}
+	return loadHistory(path)
+}
+
+func historyPath() (string, error) {
+	dir, err := GetDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, HistoryFile), nil
+}
+
+// loadHistory reads path, merging each exit line into its launch. Lines that
+// don't parse (e.g. one cut short by a full disk) are skipped.
+func loadHistory(path string) ([]HistoryEntry, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	defer f.Close()
+
+	var entries []HistoryEntry
+	index := map[string]int{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil || e.ID == "" {
+			continue
+		}
+		if i, ok := index[e.ID]; ok {
+			entries[i].ExitCode, entries[i].Seconds = e.ExitCode, e.Seconds
+			continue
+		}
+		if e.Time.IsZero() {
+			continue // an exit whose launch was compacted away
+		}
+		index[e.ID] = len(entries)
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	return entries, nil
+}
+
+// appendHistory appends e to path as one line. Appends this small are
+// atomic, so concurrent launches don't interleave.
+func appendHistory(path string, e HistoryEntry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return f.Close()
+}
+
+// compactHistory rewrites path atomically with only the newest keep
+// launches, each on one line with its exit merged in.
+func compactHistory(path string, keep int) error {
+	entries, err := loadHistory(path)
+	if err != nil {
+		return err
+	}
+	if len(entries) > keep {
+		entries = entries[len(entries)-keep:]
+	}
+
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, ".history-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer func() { _ = os.Remove(tmpPath) }() // no-op after a successful rename
+
+	w := bufio.NewWriter(tmp)
+	enc := json.NewEncoder(w)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			tmp.Close()
+			return fmt.Errorf("failed to write history: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return nil
+}
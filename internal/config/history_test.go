@@ -0,0 +1,64 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", HistoryFile)
+	at := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	if h, err := loadHistory(path); err != nil || len(h) != 0 {
+		t.Fatalf("missing file: got %v, %v", h, err)
+	}
+
+	exit := 2
+	for _, e := range []HistoryEntry{
+		{ID: "a", Time: at, Provider: "zai", Model: "glm-4.7"},
+		{ID: "b", Time: at.Add(time.Hour), Provider: "ollama"},
+		{ID: "a", ExitCode: &exit, Seconds: 90},
+		{ID: "gone", ExitCode: &exit},
+	} {
+		if err := appendHistory(path, e); err != nil {
+			t.Fatalf("appendHistory: %v", err)
+		}
+	}
+	// A line cut short is skipped
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(`{"id":"c","time":`)
+	f.Close()
+
+	h, err := loadHistory(path)
+	if err != nil {
+		t.Fatalf("loadHistory: %v", err)
+	}
+	if len(h) != 2 || h[0].ID != "a" || h[1].ID != "b" {
+		t.Fatalf("history = %+v", h)
+	}
+	if !h[0].Finished() || *h[0].ExitCode != 2 || h[0].Duration() != 90*time.Second || h[0].Model != "glm-4.7" {
+		t.Errorf("finished launch = %+v", h[0])
+	}
+	if h[1].Finished() || h[1].Duration() != 0 {
+		t.Errorf("unfinished launch = %+v", h[1])
+	}
+
+	// Compacting keeps the newest launches, with their exits merged in
+	if err := compactHistory(path, 1); err != nil {
+		t.Fatalf("compactHistory: %v", err)
+	}
+	if h, err := loadHistory(path); err != nil || len(h) != 1 || h[0].ID != "b" {
+		t.Errorf("after compacting: got %+v, %v", h, err)
+	}
+	if err := compactHistory(path, 10); err != nil {
+		t.Fatalf("compactHistory: %v", err)
+	}
+	if h, _ := loadHistory(path); len(h) != 1 {
+		t.Errorf("after compacting again: got %+v", h)
+	}
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ProviderImport is the providers read from another tool's config, such as
+// claude-code-router's or LiteLLM's.
+type ProviderImport struct {
+	Providers []*Provider
+	// Keys are the API keys by provider name. The or-* providers share the
+	// one stored under "openrouter", as 'skint config openrouter' does.
+	Keys map[string]string
+	// Default is the provider the other tool used by default, or ""
+	Default string
+	// Skipped says what couldn't be imported, and why
+	Skipped []string
+}
+
+// add adds p unless its name is taken or it is invalid, noting why it was
+// skipped.
+func (imp *ProviderImport) add(p *Provider) bool {
+	if p.Name == "" || p.Name == OpenRouterPrefix {
+		imp.Skipped = append(imp.Skipped, fmt.Sprintf("%s: no usable name", p.DisplayName))
+		return false
+	}
+	if slices.ContainsFunc(imp.Providers, func(o *Provider) bool { return o.Name == p.Name }) {
+		imp.Skipped = append(imp.Skipped, fmt.Sprintf("%s: another provider is already named %s", p.DisplayName, p.Name))
+		return false
+	}
+	if err := p.Validate(); err != nil {
+		imp.Skipped = append(imp.Skipped, fmt.Sprintf("%s: %v", p.DisplayName, err))
+		return false
+	}
+	imp.Providers = append(imp.Providers, p)
+	return true
+}
+
+// importBaseURL turns an endpoint, which may be the full URL requests are
+// posted to, into a base URL for apiType. Anthropic base URLs leave out the
+// version; OpenAI ones keep it.
+func importBaseURL(url, apiType string) string {
+	url = strings.TrimSuffix(strings.TrimRight(url, "/"), "/chat/completions")
+	if apiType == APITypeAnthropic {
+		url = strings.TrimSuffix(strings.TrimSuffix(url, "/messages"), "/v1")
+	}
+	return url
+}
+
+// importName makes a provider name from s: lower case letters, digits and
+// hyphens.
+func importName(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-':
+			b.WriteRune(r)
+		case r == '_' || r == ' ' || r == '.' || r == ':':
+			b.WriteRune('-')
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
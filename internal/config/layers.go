@@ -0,0 +1,249 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// systemConfigDir is the admin-managed config location, always the lowest layer.
+// On Windows it is %ProgramData%\skint instead (see systemConfigFiles).
+const systemConfigDir = "/etc/skint"
+
+// layerState tracks what the system-wide layers contributed at Load time.
+type layerState struct {
+	// files lists the system config files that were found and applied.
+	files []string
+	// providers maps provider name to its YAML encoding after all system
+	// layers were applied, before the user config.
+	providers map[string][]byte
+	// settings is the config after all system layers were applied, and
+	// settingKeys the top-level keys (as in the YAML) those layers set.
+	settings    *Config
+	settingKeys map[string]bool
+}
+
+// systemConfigFiles returns the system-wide config files to layer beneath the
+// user config, lowest precedence first: /etc/skint/config.yaml, then each
+// $XDG_CONFIG_DIRS entry from least to most preferred. Windows uses
+// %ProgramData%\skint\config.yaml, and XDG_CONFIG_DIRS only if it is set.
+func systemConfigFiles() []string {
+	var files []string
+	xdgDirs := os.Getenv("XDG_CONFIG_DIRS")
+	if runtime.GOOS == "windows" {
+		if programData := os.Getenv("ProgramData"); programData != "" {
+			files = append(files, filepath.Join(programData, "skint", "config.yaml"))
+		}
+	} else {
+		files = append(files, filepath.Join(systemConfigDir, "config.yaml"))
+		if xdgDirs == "" {
+			xdgDirs = "/etc/xdg"
+		}
+	}
+	dirs := filepath.SplitList(xdgDirs)
+	// XDG_CONFIG_DIRS is ordered most important first
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if dirs[i] == "" || !filepath.IsAbs(dirs[i]) {
+			continue
+		}
+		files = append(files, filepath.Join(dirs[i], "skint", "config.yaml"))
+	}
+
+	return files
+}
+
+// loadSystemLayers applies each existing system config file in turn. Missing
+// files are skipped; unreadable or unparsable ones are errors, since silently
+// ignoring an admin's config would be surprising.
+func (m *Manager) loadSystemLayers() error {
+	m.systemLayers = layerState{}
+
+	for _, file := range m.systemFiles {
+		// System files are root-owned, so symlinks (common with config
+		// management tools) are allowed here, unlike the user config.
+		data, err := os.ReadFile(file)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read system config %s: %w", file, err)
+		}
+		format := detectFormat(file, data)
+		if _, err := m.applyLayer(format, data); err != nil {
+			return fmt.Errorf("failed to parse system config %s: %w", file, err)
+		}
+		if err := m.systemLayers.addSettingKeys(format, data); err != nil {
+			return fmt.Errorf("failed to parse system config %s: %w", file, err)
+		}
+		m.systemLayers.files = append(m.systemLayers.files, file)
+	}
+
+	if len(m.systemLayers.files) == 0 {
+		return nil
+	}
+	m.systemLayers.settings = cloneConfig(m.config)
+
+	m.systemLayers.providers = make(map[string][]byte, len(m.config.Providers))
+	for _, p := range m.config.Providers {
+		data, err := yaml.Marshal(p)
+		if err != nil {
+			return fmt.Errorf("failed to snapshot system provider %s: %w", p.Name, err)
+		}
+		m.systemLayers.providers[p.Name] = data
+	}
+
+	return nil
+}
+
+// applyLayer parses a config document in the given format over the current
+// config, upgrading it to the current schema first, and returns the schema
+// version it was written as. Top-level settings present in the document
+// replace lower layers; providers are merged by name, with this layer's
+// definition replacing any lower one.
+func (m *Manager) applyLayer(format string, data []byte) (string, error) {
+	data, version, err := upgradeData(format, data)
+	if err != nil {
+		return version, err
+	}
+
+	lower := m.config.Providers
+	m.config.Providers = nil
+
+	if err := decodeConfig(format, data, m.config); err != nil {
+		m.config.Providers = lower
+		return version, err
+	}
+	foldDefaultModels(m.config.Providers)
+
+	m.config.Providers = mergeProviders(lower, m.config.Providers)
+	return version, nil
+}
+
+// mergeProviders overlays upper onto lower by provider name. Lower-layer order
+// is kept, with replaced entries updated in place and new ones appended.
+func mergeProviders(lower, upper []*Provider) []*Provider {
+	if len(lower) == 0 {
+		return upper
+	}
+
+	merged := append([]*Provider{}, lower...)
+	for _, p := range upper {
+		replaced := false
+		for i, existing := range merged {
+			if existing.Name == p.Name {
+				merged[i] = p
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, p)
+		}
+	}
+	return merged
+}
+
+// userProviders filters out providers that are unchanged from the system
+// layers, so Save writes only the user's own providers and overrides. A system
+// provider the user has modified (e.g. by adding an API key) is kept in full.
+func (s layerState) userProviders(all []*Provider) []*Provider {
+	if len(s.providers) == 0 {
+		return all
+	}
+
+	var result []*Provider
+	for _, p := range all {
+		if system, ok := s.providers[p.Name]; ok {
+			if data, err := yaml.Marshal(p); err == nil && bytes.Equal(data, system) {
+				continue
+			}
+		}
+		result = append(result, p)
+	}
+	return result
+}
+
+// addSettingKeys records the top-level settings a system config document sets,
+// after upgrading it to the current schema.
+func (s *layerState) addSettingKeys(format string, data []byte) error {
+	data, _, err := upgradeData(format, data)
+	if err != nil {
+		return err
+	}
+	var doc map[string]any
+	if err := decodeConfig(format, data, &doc); err != nil {
+		return err
+	}
+	if s.settingKeys == nil {
+		s.settingKeys = make(map[string]bool, len(doc))
+	}
+	for key := range doc {
+		s.settingKeys[key] = true
+	}
+	return nil
+}
+
+// defaults returns the config a user config file is read over: the settings
+// from the system layers, without their providers, or the built-in defaults
+// when there are none.
+func (s layerState) defaults() *Config {
+	if s.settings == nil {
+		return NewDefaultConfig()
+	}
+	c := cloneConfig(s.settings)
+	c.Providers = []*Provider{}
+	return c
+}
+
+// userSettings returns c for writing to the user config file, leaving out the
+// top-level settings a system layer set and the user has not changed, so later
+// changes to the system layer still apply. With no such settings c itself is
+// returned; otherwise it is a copy with those fields removed from the struct.
+func (s layerState) userSettings(c *Config) any {
+	if len(s.settingKeys) == 0 || s.settings == nil {
+		return c
+	}
+
+	cv := reflect.ValueOf(c).Elem()
+	sv := reflect.ValueOf(s.settings).Elem()
+	var fields []reflect.StructField
+	var values []reflect.Value
+	for i := 0; i < cv.NumField(); i++ {
+		field := cv.Type().Field(i)
+		key, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		switch {
+		case field.Name == "Version" || field.Name == "Providers":
+		case s.settingKeys[key] && reflect.DeepEqual(cv.Field(i).Interface(), sv.Field(i).Interface()):
+			continue
+		}
+		fields = append(fields, field)
+		values = append(values, cv.Field(i))
+	}
+	if len(fields) == cv.NumField() {
+		return c
+	}
+
+	out := reflect.New(reflect.StructOf(fields)).Elem()
+	for i, v := range values {
+		out.Field(i).Set(v)
+	}
+	return out.Addr().Interface()
+}
+
+// SystemConfigFiles returns the system-wide config files applied by the last Load.
+func (m *Manager) SystemConfigFiles() []string {
+	return m.systemLayers.files
+}
+
+// IsSystemProvider returns true if the named provider was defined by a
+// system-wide config layer.
+func (m *Manager) IsSystemProvider(name string) bool {
+	_, ok := m.systemLayers.providers[name]
+	return ok
+}
@@ -0,0 +1,270 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// litellmEnvPrefix marks a LiteLLM value read from an environment variable.
+const litellmEnvPrefix = "os.environ/"
+
+// litellmModel is an entry in a LiteLLM proxy config's model_list.
+type litellmModel struct {
+	ModelName string        `yaml:"model_name"`
+	Params    litellmParams `yaml:"litellm_params"`
+}
+
+type litellmParams struct {
+	Model   string `yaml:"model"`
+	APIBase string `yaml:"api_base,omitempty"`
+	APIKey  string `yaml:"api_key,omitempty"`
+}
+
+// litellmOpenAI are LiteLLM providers that speak the OpenAI API, so skint can
+// use them as custom providers.
+var litellmOpenAI = []string{"openai", "hosted_vllm", "lm_studio", "custom_openai", "openai_like"}
+
+// LiteLLMKeyVar is the environment variable an exported LiteLLM entry reads
+// p's API key from: OPENROUTER_API_KEY for OpenRouter providers, which share
+// a key, otherwise the provider name as NAME_API_KEY.
+func LiteLLMKeyVar(p *Provider) string {
+	if p.Type == ProviderTypeOpenRouter {
+		return "OPENROUTER_API_KEY"
+	}
+	return strings.ToUpper(strings.ReplaceAll(p.Name, "-", "_")) + "_API_KEY"
+}
+
+// litellmEntry converts p to a LiteLLM model_list entry named after it. Keys
+// are never written: entries read them from the environment.
+func litellmEntry(p *Provider) (litellmModel, error) {
+	if p.Name == "native" {
+		return litellmModel{}, fmt.Errorf("it uses a Claude subscription, not an API")
+	}
+	model := p.EffectiveModel()
+	if model == "" {
+		return litellmModel{}, fmt.Errorf("no model set")
+	}
+	e := litellmModel{ModelName: p.Name}
+	if p.NeedsAPIKey() {
+		e.Params.APIKey = litellmEnvPrefix + LiteLLMKeyVar(p)
+	}
+
+	switch p.Type {
+	case ProviderTypeOpenRouter:
+		e.Params.Model = "openrouter/" + model
+	case ProviderTypeBuiltin:
+		e.Params.Model = "anthropic/" + model
+		e.Params.APIBase = p.BaseURL
+	case ProviderTypeLocal:
+		e.Params.Model = "anthropic/" + model
+		e.Params.APIBase = p.BaseURL
+		e.Params.APIKey = p.AuthToken
+	case ProviderTypeCustom:
+		e.Params.Model = "anthropic/" + model
+		if p.APIType == APITypeOpenAI {
+			e.Params.Model = "openai/" + model
+		}
+		e.Params.APIBase = p.BaseURL
+	default:
+		return litellmModel{}, fmt.Errorf("unknown provider type %s", p.Type)
+	}
+	return e, nil
+}
+
+// ExportLiteLLM returns providers as a LiteLLM proxy config.yaml, with a
+// model_list entry per provider named after it. When existing is a LiteLLM
+// config, it is updated instead: entries with the same model_name have
+// their model, api_base and api_key replaced, new ones are appended, and
+// everything else is kept. skipped says which providers were left out, and
+// why.
+func ExportLiteLLM(providers []*Provider, existing []byte) (data []byte, skipped []string, err error) {
+	var doc yaml.Node
+	if len(bytes.TrimSpace(existing)) > 0 {
+		if err := yaml.Unmarshal(existing, &doc); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse LiteLLM config: %w", err)
+		}
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("LiteLLM config is not a mapping")
+	}
+	list := yamlMapValue(root, "model_list")
+	if list == nil {
+		list = &yaml.Node{Kind: yaml.SequenceNode}
+		root.Content = append([]*yaml.Node{{Kind: yaml.ScalarNode, Value: "model_list"}, list}, root.Content...)
+	} else if list.Kind != yaml.SequenceNode {
+		return nil, nil, fmt.Errorf("LiteLLM model_list is not a list")
+	}
+
+	for _, p := range providers {
+		e, err := litellmEntry(p)
+		if err != nil {
+			skipped = append(skipped, fmt.Sprintf("%s: %v", p.Name, err))
+			continue
+		}
+		var entry *yaml.Node
+		for _, n := range list.Content {
+			if name := yamlMapValue(n, "model_name"); name != nil && name.Value == e.ModelName {
+				entry = n
+				break
+			}
+		}
+		if entry == nil {
+			var n yaml.Node
+			if err := n.Encode(e); err != nil {
+				return nil, nil, err
+			}
+			list.Content = append(list.Content, &n)
+			continue
+		}
+		params := yamlMapValue(entry, "litellm_params")
+		if params == nil || params.Kind != yaml.MappingNode {
+			params = &yaml.Node{Kind: yaml.MappingNode}
+			yamlSetMapValue(entry, "litellm_params", params)
+		}
+		for _, kv := range [][2]string{{"model", e.Params.Model}, {"api_base", e.Params.APIBase}, {"api_key", e.Params.APIKey}} {
+			if kv[1] == "" {
+				yamlDeleteMapValue(params, kv[0])
+				continue
+			}
+			yamlSetMapValue(params, kv[0], &yaml.Node{Kind: yaml.ScalarNode, Value: kv[1]})
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to encode LiteLLM config: %w", err)
+	}
+	return buf.Bytes(), skipped, nil
+}
+
+// ImportLiteLLM converts the model_list of a LiteLLM proxy config.yaml to
+// skint providers named after each entry's model_name: openrouter/ models to
+// or-* providers, anthropic/ ones to custom Anthropic API providers, ollama/
+// ones to local providers and OpenAI-compatible ones to custom OpenAI
+// providers. Keys given as os.environ/VAR are looked up with getenv.
+func ImportLiteLLM(data []byte, getenv func(string) string) (*ProviderImport, error) {
+	var cfg struct {
+		ModelList []litellmModel `yaml:"model_list"`
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse LiteLLM config: %w", err)
+	}
+	if len(cfg.ModelList) == 0 {
+		return nil, fmt.Errorf("no model_list in the LiteLLM config")
+	}
+
+	imp := &ProviderImport{Keys: map[string]string{}}
+	for _, e := range cfg.ModelList {
+		key := e.Params.APIKey
+		if v, ok := strings.CutPrefix(key, litellmEnvPrefix); ok {
+			if key = getenv(v); key == "" {
+				imp.Skipped = append(imp.Skipped, fmt.Sprintf("%s's key: $%s is not set", e.ModelName, v))
+			}
+		}
+		prefix, model, ok := strings.Cut(e.Params.Model, "/")
+		if !ok || model == "" {
+			imp.Skipped = append(imp.Skipped, fmt.Sprintf("%s: model %q has no provider prefix", e.ModelName, e.Params.Model))
+			continue
+		}
+
+		p := &Provider{
+			Name:        importName(e.ModelName),
+			DisplayName: e.ModelName,
+			Description: "Imported from LiteLLM",
+			Model:       model,
+		}
+		keyName := p.Name
+		switch {
+		case prefix == "openrouter":
+			if p.Name != "openrouter" && !strings.HasPrefix(p.Name, OpenRouterPrefix) {
+				p.Name = OpenRouterPrefix + p.Name
+			}
+			p.Type = ProviderTypeOpenRouter
+			p.DisplayName = "OpenRouter " + model
+			p.Description = ""
+			p.BaseURL = OpenRouterBaseURL
+			keyName = "openrouter"
+		case prefix == "anthropic":
+			p.Type = ProviderTypeCustom
+			p.APIType = APITypeAnthropic
+			p.BaseURL = "https://api.anthropic.com"
+			if e.Params.APIBase != "" {
+				p.BaseURL = importBaseURL(e.Params.APIBase, APITypeAnthropic)
+			}
+		case prefix == "ollama" || prefix == "ollama_chat":
+			p.Type = ProviderTypeLocal
+			p.BaseURL = "http://localhost:11434"
+			if e.Params.APIBase != "" {
+				p.BaseURL = strings.TrimSuffix(strings.TrimRight(e.Params.APIBase, "/"), "/v1")
+			}
+			p.AuthToken, key = key, ""
+		case slices.Contains(litellmOpenAI, prefix):
+			p.Type = ProviderTypeCustom
+			p.APIType = APITypeOpenAI
+			p.BaseURL = e.Params.APIBase
+			if p.BaseURL == "" && prefix == "openai" {
+				p.BaseURL = "https://api.openai.com/v1"
+			}
+			if p.BaseURL == "" {
+				imp.Skipped = append(imp.Skipped, fmt.Sprintf("%s: no api_base", e.ModelName))
+				continue
+			}
+			p.BaseURL = importBaseURL(p.BaseURL, APITypeOpenAI)
+		default:
+			imp.Skipped = append(imp.Skipped, fmt.Sprintf("%s: the %s API isn't Anthropic or OpenAI compatible", e.ModelName, prefix))
+			continue
+		}
+
+		if !imp.add(p) {
+			continue
+		}
+		if key != "" {
+			imp.Keys[keyName] = key
+		}
+	}
+	return imp, nil
+}
+
+// yamlMapValue returns the value for key in mapping node m, or nil.
+func yamlMapValue(m *yaml.Node, key string) *yaml.Node {
+	if m.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// yamlSetMapValue sets key in mapping node m to v, adding it if needed.
+func yamlSetMapValue(m *yaml.Node, key string, v *yaml.Node) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content[i+1] = v
+			return
+		}
+	}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, v)
+}
+
+// yamlDeleteMapValue removes key from mapping node m.
+func yamlDeleteMapValue(m *yaml.Node, key string) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content = append(m.Content[:i], m.Content[i+2:]...)
+			return
+		}
+	}
+}
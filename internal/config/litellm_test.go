@@ -0,0 +1,141 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExportLiteLLM(t *testing.T) {
+	providers := []*Provider{
+		{Name: "zai", Type: ProviderTypeBuiltin, BaseURL: "https://api.z.ai/api/anthropic", Model: "glm-5", APIKeyRef: "keyring:zai"},
+		{Name: "or-kimi-k2", Type: ProviderTypeOpenRouter, BaseURL: "https://openrouter.ai/api", Model: "moonshotai/kimi-k2"},
+		{Name: "ollama", Type: ProviderTypeLocal, BaseURL: "http://localhost:11434", AuthToken: "ollama", Model: "qwen3"},
+		{Name: "my-vllm", Type: ProviderTypeCustom, APIType: APITypeOpenAI, BaseURL: "http://gpu:8000/v1", Model: "glm"},
+		{Name: "native", Type: ProviderTypeBuiltin},
+		{Name: "minimax", Type: ProviderTypeBuiltin, BaseURL: "https://api.minimax.io/anthropic"},
+	}
+
+	t.Run("new", func(t *testing.T) {
+		data, skipped, err := ExportLiteLLM(providers, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := string(data)
+		for _, want := range []string{
+			"model_name: zai\n    litellm_params:\n      model: anthropic/glm-5\n      api_base: https://api.z.ai/api/anthropic\n      api_key: os.environ/ZAI_API_KEY",
+			"model: openrouter/moonshotai/kimi-k2\n      api_key: os.environ/OPENROUTER_API_KEY",
+			"model: anthropic/qwen3\n      api_base: http://localhost:11434\n      api_key: ollama",
+			"model: openai/glm\n      api_base: http://gpu:8000/v1\n      api_key: os.environ/MY_VLLM_API_KEY",
+		} {
+			if !strings.Contains(out, want) {
+				t.Errorf("export missing %q:\n%s", want, out)
+			}
+		}
+		if strings.Contains(out, "native") || strings.Contains(out, "minimax") {
+			t.Errorf("export has providers it can't express:\n%s", out)
+		}
+		if len(skipped) != 2 {
+			t.Errorf("skipped = %v, want native and minimax", skipped)
+		}
+
+		// The export reads back as the same providers
+		imp, err := ImportLiteLLM(data, func(k string) string { return "key-" + k })
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(imp.Providers) != 4 || imp.Keys["openrouter"] != "key-OPENROUTER_API_KEY" || imp.Keys["zai"] != "key-ZAI_API_KEY" {
+			t.Errorf("re-import = %d providers, keys %v", len(imp.Providers), imp.Keys)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		existing := `# gateway
+model_list:
+  - model_name: zai
+    litellm_params:
+      model: anthropic/glm-4.6
+      rpm: 60
+  - model_name: gpt-4o
+    litellm_params:
+      model: openai/gpt-4o
+litellm_settings:
+  drop_params: true
+`
+		data, _, err := ExportLiteLLM(providers[:1], []byte(existing))
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := string(data)
+		for _, want := range []string{"# gateway", "model: anthropic/glm-5", "rpm: 60", "model_name: gpt-4o", "drop_params: true"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("updated config missing %q:\n%s", want, out)
+			}
+		}
+		if strings.Count(out, "model_name: zai") != 1 || strings.Contains(out, "glm-4.6") {
+			t.Errorf("zai entry not replaced in place:\n%s", out)
+		}
+	})
+}
+
+func TestImportLiteLLM(t *testing.T) {
+	data := `model_list:
+  - model_name: claude-sonnet
+    litellm_params:
+      model: anthropic/claude-sonnet-4-5
+      api_key: os.environ/ANTHROPIC_API_KEY
+  - model_name: deepseek
+    litellm_params:
+      model: openai/deepseek-chat
+      api_base: https://api.deepseek.com/v1
+      api_key: sk-plain
+  - model_name: kimi
+    litellm_params:
+      model: openrouter/moonshotai/kimi-k2
+      api_key: os.environ/OPENROUTER_API_KEY
+  - model_name: local
+    litellm_params:
+      model: ollama_chat/qwen3
+      api_base: http://box:11434
+  - model_name: vllm
+    litellm_params:
+      model: hosted_vllm/glm
+  - model_name: gemini
+    litellm_params:
+      model: gemini/gemini-2.5-pro
+`
+	env := map[string]string{"OPENROUTER_API_KEY": "sk-or"}
+	imp, err := ImportLiteLLM([]byte(data), func(k string) string { return env[k] })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]Provider{
+		"claude-sonnet": {Type: ProviderTypeCustom, APIType: APITypeAnthropic, BaseURL: "https://api.anthropic.com", Model: "claude-sonnet-4-5"},
+		"deepseek":      {Type: ProviderTypeCustom, APIType: APITypeOpenAI, BaseURL: "https://api.deepseek.com/v1", Model: "deepseek-chat"},
+		"or-kimi":       {Type: ProviderTypeOpenRouter, BaseURL: "https://openrouter.ai/api", Model: "moonshotai/kimi-k2"},
+		"local":         {Type: ProviderTypeLocal, BaseURL: "http://box:11434", Model: "qwen3"},
+	}
+	if len(imp.Providers) != len(want) {
+		t.Errorf("got %d providers, want %d", len(imp.Providers), len(want))
+	}
+	for _, p := range imp.Providers {
+		w, ok := want[p.Name]
+		if !ok {
+			t.Errorf("unexpected provider %q", p.Name)
+			continue
+		}
+		if p.Type != w.Type || p.APIType != w.APIType || p.BaseURL != w.BaseURL || p.Model != w.Model {
+			t.Errorf("%s = %s/%s %s %s, want %s/%s %s %s", p.Name, p.Type, p.APIType, p.BaseURL, p.Model, w.Type, w.APIType, w.BaseURL, w.Model)
+		}
+	}
+	if len(imp.Keys) != 2 || imp.Keys["openrouter"] != "sk-or" || imp.Keys["deepseek"] != "sk-plain" {
+		t.Errorf("Keys = %v", imp.Keys)
+	}
+
+	skipped := strings.Join(imp.Skipped, "\n")
+	for _, want := range []string{"$ANTHROPIC_API_KEY is not set", "vllm: no api_base", "gemini: the gemini API"} {
+		if !strings.Contains(skipped, want) {
+			t.Errorf("Skipped missing %q:\n%s", want, skipped)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// lockTimeout is how long Save waits for another skint process to finish
+// writing the config before giving up.
+var lockTimeout = 5 * time.Second
+
+// lockPath returns the advisory lock file guarding writes to the config file.
+// The lock file is left in place after use; removing it would let a waiting
+// process lock an unlinked file.
+func (m *Manager) lockPath() string {
+	return m.configFile + ".lock"
+}
+
+// lock takes the advisory config lock, retrying until lockTimeout. It returns
+// a function that releases the lock.
+//
+// Only writers lock: Save replaces the config with an atomic rename, so Load
+// never sees a partial file.
+func (m *Manager) lock() (func(), error) {
+	path := m.lockPath()
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open config lock: %w", err)
+	}
+
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		if err := lockFile(f); err == nil {
+			return func() {
+				_ = unlockFile(f)
+				_ = f.Close()
+			}, nil
+		}
+		if time.Now().After(deadline) {
+			_ = f.Close()
+			return nil, fmt.Errorf("config is locked by another skint process (%s)", path)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// loadManager loads the config at path with a fresh manager.
+func loadManager(t *testing.T, path string) *Manager {
+	t.Helper()
+	m, err := NewManagerWithPath(path)
+	if err != nil {
+		t.Fatalf("NewManagerWithPath: %v", err)
+	}
+	if err := m.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return m
+}
+
+func TestSaveWaitsForLock(t *testing.T) {
+	old := lockTimeout
+	lockTimeout = 100 * time.Millisecond
+	t.Cleanup(func() { lockTimeout = old })
+
+	cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+	holder := loadManager(t, cfgPath)
+	unlock, err := holder.lock()
+	if err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+
+	m := loadManager(t, cfgPath)
+	err = m.Save()
+	if err == nil || !strings.Contains(err.Error(), "locked by another skint process") {
+		t.Fatalf("Save while locked: got %v, want lock error", err)
+	}
+
+	unlock()
+	if err := m.Save(); err != nil {
+		t.Fatalf("Save after unlock: %v", err)
+	}
+}
+
+func TestSaveMergesConcurrentChanges(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+	initial := `version: "` + ConfigVersion + `"
+output_format: human
+providers:
+  - name: kept
+    type: local
+    base_url: http://localhost:1
+  - name: edited-by-b
+    type: local
+    base_url: http://localhost:2
+  - name: removed-by-a
+    type: local
+    base_url: http://localhost:3
+`
+	if err := os.WriteFile(cfgPath, []byte(initial), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	// Two processes load the same file, e.g. the TUI and `skint config`
+	a := loadManager(t, cfgPath)
+	b := loadManager(t, cfgPath)
+
+	cfgA := a.Get()
+	cfgA.OutputFormat = FormatJSON
+	cfgA.RemoveProvider("removed-by-a")
+	if err := cfgA.AddProvider(&Provider{Name: "added-by-a", Type: ProviderTypeLocal, BaseURL: "http://localhost:4"}); err != nil {
+		t.Fatalf("AddProvider: %v", err)
+	}
+	if err := a.Save(); err != nil {
+		t.Fatalf("Save a: %v", err)
+	}
+
+	cfgB := b.Get()
+	cfgB.NoBanner = true
+	cfgB.GetProvider("edited-by-b").Model = "qwen3"
+	if err := cfgB.AddProvider(&Provider{Name: "added-by-b", Type: ProviderTypeLocal, BaseURL: "http://localhost:5"}); err != nil {
+		t.Fatalf("AddProvider: %v", err)
+	}
+	if err := b.Save(); err != nil {
+		t.Fatalf("Save b: %v", err)
+	}
+
+	// b's in-memory config picked up a's changes too
+	if b.Get().OutputFormat != FormatJSON || b.Get().GetProvider("added-by-a") == nil {
+		t.Error("b's config did not pick up a's changes")
+	}
+
+	got := loadManager(t, cfgPath).Get()
+	if got.OutputFormat != FormatJSON {
+		t.Errorf("OutputFormat: got %q, want a's %q", got.OutputFormat, FormatJSON)
+	}
+	if !got.NoBanner {
+		t.Error("NoBanner: b's change was lost")
+	}
+	for _, name := range []string{"kept", "edited-by-b", "added-by-a", "added-by-b"} {
+		if got.GetProvider(name) == nil {
+			t.Errorf("provider %s missing after concurrent saves", name)
+		}
+	}
+	if got.GetProvider("removed-by-a") != nil {
+		t.Error("provider removed-by-a came back")
+	}
+	if p := got.GetProvider("edited-by-b"); p != nil && p.Model != "qwen3" {
+		t.Errorf("edited-by-b Model: got %q, want qwen3", p.Model)
+	}
+}
+
+func TestSaveConflictPrefersOwnChange(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+	initial := `version: "` + ConfigVersion + `"
+providers:
+  - name: shared
+    type: local
+    base_url: http://localhost:1
+`
+	if err := os.WriteFile(cfgPath, []byte(initial), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	a := loadManager(t, cfgPath)
+	b := loadManager(t, cfgPath)
+
+	a.Get().GetProvider("shared").Model = "from-a"
+	if err := a.Save(); err != nil {
+		t.Fatalf("Save a: %v", err)
+	}
+	b.Get().GetProvider("shared").Model = "from-b"
+	if err := b.Save(); err != nil {
+		t.Fatalf("Save b: %v", err)
+	}
+
+	if got := loadManager(t, cfgPath).Get().GetProvider("shared").Model; got != "from-b" {
+		t.Errorf("Model: got %q, want the last writer's from-b", got)
+	}
+}
@@ -3,8 +3,13 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
+	"strings"
 	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 // ---------------------------------------------------------------------------
@@ -557,6 +562,82 @@ func TestApplyEnvOverrides(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "SKINT_COLOR_ENABLED maps to color_enabled",
+			envVars: map[string]string{
+				"SKINT_COLOR_ENABLED": "false",
+			},
+			check: func(t *testing.T, cfg *Config) {
+				t.Helper()
+				if cfg.ColorEnabled {
+					t.Error("ColorEnabled: expected false when SKINT_COLOR_ENABLED=false")
+				}
+			},
+		},
+		{
+			name: "SKINT_CLAUDE_ARGS is split on commas",
+			envVars: map[string]string{
+				"SKINT_CLAUDE_ARGS": "--verbose, --model=opus",
+			},
+			check: func(t *testing.T, cfg *Config) {
+				t.Helper()
+				want := []string{"--verbose", "--model=opus"}
+				if !reflect.DeepEqual(cfg.ClaudeArgs, want) {
+					t.Errorf("ClaudeArgs: got %v, want %v", cfg.ClaudeArgs, want)
+				}
+			},
+		},
+		{
+			name: "SKINT_RETRIES and SKINT_MODEL_CACHE_TTL are typed",
+			envVars: map[string]string{
+				"SKINT_RETRIES":         "0",
+				"SKINT_RETRY_BACKOFF":   "2s",
+				"SKINT_MODEL_CACHE_TTL": "0",
+			},
+			check: func(t *testing.T, cfg *Config) {
+				t.Helper()
+				if cfg.NetworkRetries() != 0 || cfg.NetworkRetryBackoff() != 2*time.Second || cfg.ModelCacheMaxAge() != 0 {
+					t.Errorf("got %d, %v, %v; want 0, 2s, 0", cfg.NetworkRetries(), cfg.NetworkRetryBackoff(), cfg.ModelCacheMaxAge())
+				}
+			},
+		},
+		{
+			name: "invalid number and duration are ignored",
+			envVars: map[string]string{
+				"SKINT_RETRIES": "many",
+				"SKINT_TIMEOUT": "0s",
+			},
+			check: func(t *testing.T, cfg *Config) {
+				t.Helper()
+				if cfg.Retries != nil || cfg.Timeout != 0 {
+					t.Errorf("Retries = %v, Timeout = %v; want both unset", cfg.Retries, cfg.Timeout)
+				}
+			},
+		},
+		{
+			name: "invalid boolean is ignored",
+			envVars: map[string]string{
+				"SKINT_NO_BANNER": "maybe",
+			},
+			check: func(t *testing.T, cfg *Config) {
+				t.Helper()
+				if cfg.NoBanner {
+					t.Error("NoBanner: expected false (invalid value should be ignored)")
+				}
+			},
+		},
+		{
+			name: "SKINT_VERSION is not overridable",
+			envVars: map[string]string{
+				"SKINT_VERSION": "9.9",
+			},
+			check: func(t *testing.T, cfg *Config) {
+				t.Helper()
+				if cfg.Version != ConfigVersion {
+					t.Errorf("Version: got %q, want %q", cfg.Version, ConfigVersion)
+				}
+			},
+		},
 		{
 			name:    "no env vars leaves defaults untouched",
 			envVars: map[string]string{},
@@ -634,6 +715,124 @@ providers:
 	})
 }
 
+// TestProviderOrderEnvNotFlagged covers SKINT_PROVIDER_ORDER, a top-level
+// setting that shares the provider prefix, being applied without the
+// warning for an unknown provider setting.
+func TestProviderOrderEnvNotFlagged(t *testing.T) {
+	t.Setenv("SKINT_PROVIDER_ORDER", "kimi,zai")
+
+	stderr, err := os.CreateTemp(t.TempDir(), "stderr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stderr.Close()
+	saved := os.Stderr
+	os.Stderr = stderr
+	t.Cleanup(func() { os.Stderr = saved })
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("version: \"2.0\"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	m := loadManager(t, path)
+	os.Stderr = saved
+
+	if got := m.Get().ProviderOrder; !reflect.DeepEqual(got, []string{"kimi", "zai"}) {
+		t.Errorf("ProviderOrder = %v, want [kimi zai]", got)
+	}
+	if out, _ := os.ReadFile(stderr.Name()); strings.Contains(string(out), "SKINT_PROVIDER_ORDER") {
+		t.Errorf("unexpected warning: %s", out)
+	}
+}
+
+func TestProviderEnvOverrides(t *testing.T) {
+	t.Setenv("SKINT_PROVIDER_MY_LLM_MODEL", "env-model")
+	t.Setenv("SKINT_PROVIDER_MY_LLM_BASE_URL", "http://env.example.com")
+	t.Setenv("SKINT_PROVIDER_MY_LLM_NAME", "renamed")
+	t.Setenv("SKINT_PROVIDER_OTHER_API_TYPE", "soap")
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	yamlContent := `version: "1.0"
+output_format: "human"
+providers:
+  - name: my-llm
+    type: local
+    base_url: "http://file.example.com"
+    model: "file-model"
+  - name: other
+    type: custom
+    base_url: "https://other.example.com"
+    api_type: "openai"
+`
+	if err := os.WriteFile(cfgPath, []byte(yamlContent), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	m, err := NewManagerWithPath(cfgPath)
+	if err != nil {
+		t.Fatalf("NewManagerWithPath: %v", err)
+	}
+	if err := m.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	p := m.Get().GetProvider("my-llm")
+	if p == nil {
+		t.Fatal("provider my-llm not found (name must not be overridable)")
+	}
+	if p.Model != "env-model" {
+		t.Errorf("Model: got %q, want %q", p.Model, "env-model")
+	}
+	if p.BaseURL != "http://env.example.com" {
+		t.Errorf("BaseURL: got %q, want %q", p.BaseURL, "http://env.example.com")
+	}
+	if got := m.Get().GetProvider("other").APIType; got != APITypeOpenAI {
+		t.Errorf("APIType: got %q, want %q (invalid value should be ignored)", got, APITypeOpenAI)
+	}
+
+	// A deliberate runtime change to one overridden field is persisted; the
+	// other override is reverted.
+	p.BaseURL = "http://edited.example.com"
+	if err := m.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if p.Model != "env-model" {
+		t.Errorf("Save must not modify the runtime config: Model = %q", p.Model)
+	}
+
+	os.Unsetenv("SKINT_PROVIDER_MY_LLM_MODEL")
+	os.Unsetenv("SKINT_PROVIDER_MY_LLM_BASE_URL")
+	m2, err := NewManagerWithPath(cfgPath)
+	if err != nil {
+		t.Fatalf("NewManagerWithPath (reload): %v", err)
+	}
+	if err := m2.Load(); err != nil {
+		t.Fatalf("Load (reload): %v", err)
+	}
+	persisted := m2.Get().GetProvider("my-llm")
+	if persisted.Model != "file-model" {
+		t.Errorf("persisted Model: got %q, want %q", persisted.Model, "file-model")
+	}
+	if persisted.BaseURL != "http://edited.example.com" {
+		t.Errorf("persisted BaseURL: got %q, want %q", persisted.BaseURL, "http://edited.example.com")
+	}
+}
+
+func TestProviderEnvName(t *testing.T) {
+	tests := map[string]string{
+		"zai":        "ZAI",
+		"my-llm":     "MY_LLM",
+		"local.box":  "LOCAL_BOX",
+		"already_ok": "ALREADY_OK",
+	}
+	for in, want := range tests {
+		if got := providerEnvName(in); got != want {
+			t.Errorf("providerEnvName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
 // ---------------------------------------------------------------------------
 // XDG directory functions
 // ---------------------------------------------------------------------------
@@ -653,6 +852,9 @@ func TestGetConfigDir(t *testing.T) {
 	})
 
 	t.Run("falls back to ~/.config/skint when XDG_CONFIG_HOME is unset", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("Windows uses known folders, see TestWindowsDir")
+		}
 		t.Setenv("XDG_CONFIG_HOME", "")
 		got, err := getConfigDir()
 		if err != nil {
@@ -681,6 +883,9 @@ func TestGetDataDir(t *testing.T) {
 	})
 
 	t.Run("falls back to ~/.local/share/skint when XDG_DATA_HOME is unset", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("Windows uses known folders, see TestWindowsDir")
+		}
 		t.Setenv("XDG_DATA_HOME", "")
 		got, err := GetDataDir()
 		if err != nil {
@@ -709,6 +914,9 @@ func TestGetCacheDir(t *testing.T) {
 	})
 
 	t.Run("falls back to ~/.cache/skint when XDG_CACHE_HOME is unset", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("Windows uses known folders, see TestWindowsDir")
+		}
 		t.Setenv("XDG_CACHE_HOME", "")
 		got, err := GetCacheDir()
 		if err != nil {
@@ -743,9 +951,13 @@ func TestGetBinDir(t *testing.T) {
 		}
 		home, _ := os.UserHomeDir()
 		var want string
-		if runtime.GOOS == "darwin" {
+		switch runtime.GOOS {
+		case "darwin":
 			want = filepath.Join(home, "bin")
-		} else {
+		case "windows":
+			local, _ := windowsDir("LOCALAPPDATA", "AppData", "Local")
+			want = filepath.Join(local, "Programs", "skint", "bin")
+		default:
 			want = filepath.Join(home, ".local", "bin")
 		}
 		if got != want {
@@ -754,6 +966,33 @@ func TestGetBinDir(t *testing.T) {
 	})
 }
 
+func TestWindowsDir(t *testing.T) {
+	t.Run("uses the environment variable when set", func(t *testing.T) {
+		appData := t.TempDir()
+		t.Setenv("APPDATA", appData)
+		got, err := windowsDir("APPDATA", "AppData", "Roaming")
+		if err != nil {
+			t.Fatalf("windowsDir: %v", err)
+		}
+		if got != appData {
+			t.Errorf("got %q, want %q", got, appData)
+		}
+	})
+
+	t.Run("falls back to the profile default when unset", func(t *testing.T) {
+		t.Setenv("LOCALAPPDATA", "")
+		got, err := windowsDir("LOCALAPPDATA", "AppData", "Local")
+		if err != nil {
+			t.Fatalf("windowsDir: %v", err)
+		}
+		home, _ := os.UserHomeDir()
+		want := filepath.Join(home, "AppData", "Local")
+		if got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+}
+
 // ---------------------------------------------------------------------------
 // NewManager (default constructor)
 // ---------------------------------------------------------------------------
@@ -776,3 +1015,209 @@ func TestNewManager(t *testing.T) {
 		}
 	})
 }
+
+// ---------------------------------------------------------------------------
+// System-wide config layers
+// ---------------------------------------------------------------------------
+
+func TestSystemConfigLayers(t *testing.T) {
+	writeFile := func(t *testing.T, path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatalf("MkdirAll: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+
+	systemYAML := `version: "1.0"
+default_provider: corp
+no_banner: true
+providers:
+  - name: corp
+    type: custom
+    base_url: https://llm.corp.example
+    api_type: anthropic
+  - name: corp-local
+    type: local
+    base_url: http://gpu-box:11434
+`
+
+	t.Run("system providers are merged beneath user config", func(t *testing.T) {
+		dir := t.TempDir()
+		systemFile := filepath.Join(dir, "etc", "skint", "config.yaml")
+		writeFile(t, systemFile, systemYAML)
+		cfgPath := filepath.Join(dir, "user", "config.yaml")
+		writeFile(t, cfgPath, `version: "1.0"
+providers:
+  - name: corp-local
+    type: local
+    base_url: http://localhost:11434
+  - name: mine
+    type: local
+    base_url: http://localhost:1234
+`)
+
+		m, _ := NewManagerWithPath(cfgPath)
+		m.systemFiles = []string{systemFile}
+		if err := m.Load(); err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+		cfg := m.Get()
+
+		if cfg.DefaultProvider != "corp" {
+			t.Errorf("DefaultProvider: got %q, want %q from system layer", cfg.DefaultProvider, "corp")
+		}
+		if !cfg.NoBanner {
+			t.Error("NoBanner: expected true from system layer")
+		}
+		if len(cfg.Providers) != 3 {
+			t.Fatalf("Providers count: got %d, want 3", len(cfg.Providers))
+		}
+		if got := cfg.GetProvider("corp-local").BaseURL; got != "http://localhost:11434" {
+			t.Errorf("user layer should override system provider: got base_url %q", got)
+		}
+		if !m.IsSystemProvider("corp") || m.IsSystemProvider("mine") {
+			t.Error("IsSystemProvider: wrong provenance")
+		}
+		if files := m.SystemConfigFiles(); len(files) != 1 || files[0] != systemFile {
+			t.Errorf("SystemConfigFiles: got %v", files)
+		}
+	})
+
+	t.Run("later layers take precedence and missing files are skipped", func(t *testing.T) {
+		dir := t.TempDir()
+		low := filepath.Join(dir, "low.yaml")
+		high := filepath.Join(dir, "high.yaml")
+		writeFile(t, low, systemYAML)
+		writeFile(t, high, "default_provider: corp-local\n")
+
+		m, _ := NewManagerWithPath(filepath.Join(dir, "user", "config.yaml"))
+		m.systemFiles = []string{low, filepath.Join(dir, "missing.yaml"), high}
+		if err := m.Load(); err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+		if got := m.Get().DefaultProvider; got != "corp-local" {
+			t.Errorf("DefaultProvider: got %q, want %q", got, "corp-local")
+		}
+		if got := len(m.SystemConfigFiles()); got != 2 {
+			t.Errorf("SystemConfigFiles: got %d files, want 2", got)
+		}
+	})
+
+	t.Run("save omits unchanged system providers", func(t *testing.T) {
+		dir := t.TempDir()
+		systemFile := filepath.Join(dir, "system.yaml")
+		writeFile(t, systemFile, systemYAML)
+		cfgPath := filepath.Join(dir, "user", "config.yaml")
+
+		m, _ := NewManagerWithPath(cfgPath)
+		m.systemFiles = []string{systemFile}
+		if err := m.Load(); err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+		// User adds a key to one system provider and leaves the other alone.
+		m.Get().GetProvider("corp").APIKeyRef = "keyring:corp"
+		if err := m.Save(); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+
+		plain, _ := NewManagerWithPath(cfgPath)
+		if err := plain.Load(); err != nil {
+			t.Fatalf("Load user config alone: %v", err)
+		}
+		saved := plain.Get()
+		if len(saved.Providers) != 1 || saved.Providers[0].Name != "corp" {
+			t.Fatalf("saved providers: got %v, want only the modified corp provider", saved.Providers)
+		}
+		if saved.Providers[0].APIKeyRef != "keyring:corp" {
+			t.Errorf("APIKeyRef: got %q", saved.Providers[0].APIKeyRef)
+		}
+	})
+
+	t.Run("save omits unchanged system settings", func(t *testing.T) {
+		dir := t.TempDir()
+		systemFile := filepath.Join(dir, "system.yaml")
+		writeFile(t, systemFile, systemYAML+"claude_args: [--verbose]\n")
+		cfgPath := filepath.Join(dir, "user", "config.yaml")
+
+		m, _ := NewManagerWithPath(cfgPath)
+		m.systemFiles = []string{systemFile}
+		if err := m.Load(); err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+		// User changes one system setting and leaves the rest alone.
+		m.Get().NoBanner = false
+		if err := m.Save(); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+
+		data, err := os.ReadFile(cfgPath)
+		if err != nil {
+			t.Fatalf("ReadFile: %v", err)
+		}
+		var saved map[string]any
+		if err := yaml.Unmarshal(data, &saved); err != nil {
+			t.Fatalf("Unmarshal: %v", err)
+		}
+		for _, key := range []string{"default_provider", "claude_args"} {
+			if _, ok := saved[key]; ok {
+				t.Errorf("%s from the system layer was saved to the user config:\n%s", key, data)
+			}
+		}
+		if got, ok := saved["no_banner"]; !ok || got != false {
+			t.Errorf("no_banner: got %v, want the user's false", got)
+		}
+		if _, ok := saved["output_format"]; !ok {
+			t.Error("output_format not set by the system layer should still be saved")
+		}
+
+		// An admin change to the system layer now applies to the user
+		writeFile(t, systemFile, strings.Replace(systemYAML, "default_provider: corp", "default_provider: corp-local", 1))
+		m2, _ := NewManagerWithPath(cfgPath)
+		m2.systemFiles = []string{systemFile}
+		if err := m2.Load(); err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+		if got := m2.Get().DefaultProvider; got != "corp-local" {
+			t.Errorf("DefaultProvider: got %q, want the system layer's new %q", got, "corp-local")
+		}
+		if m2.Get().NoBanner {
+			t.Error("NoBanner: the user's saved false should override the system layer")
+		}
+	})
+
+	t.Run("invalid system config is an error", func(t *testing.T) {
+		dir := t.TempDir()
+		systemFile := filepath.Join(dir, "system.yaml")
+		writeFile(t, systemFile, "{{not yaml")
+
+		m, _ := NewManagerWithPath(filepath.Join(dir, "user", "config.yaml"))
+		m.systemFiles = []string{systemFile}
+		if err := m.Load(); err == nil {
+			t.Fatal("expected error for invalid system config, got nil")
+		}
+	})
+}
+
+func TestSystemConfigFiles(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows uses %ProgramData%")
+	}
+	t.Setenv("XDG_CONFIG_DIRS", "/opt/first:relative:/opt/second")
+	got := systemConfigFiles()
+	want := []string{
+		"/etc/skint/config.yaml",
+		"/opt/second/skint/config.yaml",
+		"/opt/first/skint/config.yaml",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("systemConfigFiles: got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("systemConfigFiles[%d]: got %q, want %q", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// MCPServer is an MCP server passed to Claude when a provider is launched,
+// in the form of an entry in Claude's --mcp-config file. Command starts a
+// stdio server; URL reaches an http or sse one. Values may use ${VAR}, which
+// Claude expands from the launch environment.
+type MCPServer struct {
+	Type    string            `yaml:"type,omitempty" json:"type,omitempty" toml:"type,omitempty" mapstructure:"type"`
+	Command string            `yaml:"command,omitempty" json:"command,omitempty" toml:"command,omitempty" mapstructure:"command"`
+	Args    []string          `yaml:"args,omitempty" json:"args,omitempty" toml:"args,omitempty" mapstructure:"args"`
+	Env     map[string]string `yaml:"env,omitempty" json:"env,omitempty" toml:"env,omitempty" mapstructure:"env"`
+	URL     string            `yaml:"url,omitempty" json:"url,omitempty" toml:"url,omitempty" mapstructure:"url"`
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty" toml:"headers,omitempty" mapstructure:"headers"`
+}
+
+// MCP server types
+const (
+	MCPTypeStdio = "stdio"
+	MCPTypeHTTP  = "http"
+	MCPTypeSSE   = "sse"
+)
+
+func (s *MCPServer) validate() error {
+	switch s.Type {
+	case "":
+	case MCPTypeStdio:
+		if s.Command == "" {
+			return fmt.Errorf("type %s needs a command", s.Type)
+		}
+	case MCPTypeHTTP, MCPTypeSSE:
+		if s.URL == "" {
+			return fmt.Errorf("type %s needs a url", s.Type)
+		}
+	default:
+		return fmt.Errorf("invalid type %q: valid: %s, %s, %s", s.Type, MCPTypeStdio, MCPTypeHTTP, MCPTypeSSE)
+	}
+	if (s.Command == "") == (s.URL == "") {
+		return fmt.Errorf("needs either a command or a url")
+	}
+	return nil
+}
+
+// MCPConfigFile returns where provider name's MCP servers are written for
+// Claude: mcp/<name>.json in the data directory.
+func MCPConfigFile(name string) (string, error) {
+	dataDir, err := GetDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, "mcp", name+".json"), nil
+}
+
+// MCPArgs returns the claude arguments that load p's MCP servers from its
+// MCPConfigFile, or none if it has no servers. The file is only written by
+// WriteMCPConfig.
+func (p *Provider) MCPArgs() ([]string, error) {
+	if len(p.MCPServers) == 0 {
+		return nil, nil
+	}
+	file, err := MCPConfigFile(p.Name)
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"--mcp-config", file}
+	if p.MCPStrict {
+		args = append(args, "--strict-mcp-config")
+	}
+	return args, nil
+}
+
+// WriteMCPConfig writes p's MCP servers to its MCPConfigFile, owner-only as
+// headers and env may hold tokens, and returns MCPArgs.
+func WriteMCPConfig(p *Provider) ([]string, error) {
+	args, err := p.MCPArgs()
+	if err != nil || args == nil {
+		return args, err
+	}
+	data, err := json.MarshalIndent(map[string]any{"mcpServers": p.MCPServers}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	file := args[1]
+	if err := os.MkdirAll(filepath.Dir(file), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create MCP config directory: %w", err)
+	}
+	if err := os.WriteFile(file, append(data, '\n'), 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write MCP config: %w", err)
+	}
+	return args, nil
+}
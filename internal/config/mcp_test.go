@@ -0,0 +1,89 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestMCPServerValidate(t *testing.T) {
+	tests := []struct {
+		server  MCPServer
+		wantErr string
+	}{
+		{MCPServer{Command: "npx", Args: []string{"-y", "@modelcontextprotocol/server-github"}}, ""},
+		{MCPServer{Type: MCPTypeHTTP, URL: "https://mcp.example.com/mcp"}, ""},
+		{MCPServer{URL: "https://mcp.example.com/sse"}, ""},
+		{MCPServer{}, "either a command or a url"},
+		{MCPServer{Command: "x", URL: "https://x"}, "either a command or a url"},
+		{MCPServer{Type: MCPTypeStdio, URL: "https://x"}, "needs a command"},
+		{MCPServer{Type: MCPTypeSSE, Command: "x"}, "needs a url"},
+		{MCPServer{Type: "websocket", URL: "wss://x"}, "invalid type"},
+	}
+	for _, tc := range tests {
+		err := tc.server.validate()
+		if tc.wantErr == "" && err != nil {
+			t.Errorf("%+v: unexpected error %v", tc.server, err)
+		}
+		if tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)) {
+			t.Errorf("%+v: got %v, want %q", tc.server, err, tc.wantErr)
+		}
+	}
+
+	p := &Provider{Name: "zai", Type: ProviderTypeBuiltin, BaseURL: "https://api.z.ai/api/anthropic", MCPServers: map[string]MCPServer{"bad": {}}}
+	if err := p.Validate(); err == nil || !strings.Contains(err.Error(), "MCP server bad") {
+		t.Errorf("Validate: got %v, want an MCP server error", err)
+	}
+}
+
+func TestWriteMCPConfig(t *testing.T) {
+	dataDir := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataDir)
+
+	if args, err := WriteMCPConfig(&Provider{Name: "plain"}); err != nil || args != nil {
+		t.Errorf("no servers: got %v, %v; want no arguments", args, err)
+	}
+
+	p := &Provider{
+		Name: "zai",
+		MCPServers: map[string]MCPServer{
+			"github": {Command: "npx", Args: []string{"-y", "@modelcontextprotocol/server-github"}, Env: map[string]string{"GITHUB_TOKEN": "${GITHUB_TOKEN}"}},
+			"docs":   {Type: MCPTypeHTTP, URL: "https://mcp.example.com/mcp"},
+		},
+		MCPStrict: true,
+	}
+	args, err := WriteMCPConfig(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dataDir, "skint", "mcp", "zai.json")
+	if strings.Join(args, " ") != "--mcp-config "+file+" --strict-mcp-config" {
+		t.Errorf("args = %v", args)
+	}
+	if dryRun, _ := p.MCPArgs(); strings.Join(dryRun, " ") != strings.Join(args, " ") {
+		t.Errorf("MCPArgs = %v, want %v", dryRun, args)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		MCPServers map[string]map[string]any `json:"mcpServers"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.MCPServers["github"]["command"] != "npx" || got.MCPServers["docs"]["url"] != "https://mcp.example.com/mcp" {
+		t.Errorf("written config = %s", data)
+	}
+	if _, ok := got.MCPServers["docs"]["command"]; ok {
+		t.Errorf("empty fields should be left out: %s", data)
+	}
+	if info, err := os.Stat(file); err == nil && runtime.GOOS != "windows" && info.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+// cloneConfig returns a deep copy of c's persisted fields.
+func cloneConfig(c *Config) *Config {
+	clone := &Config{}
+	if data, err := yaml.Marshal(c); err == nil {
+		_ = yaml.Unmarshal(data, clone)
+	}
+	return clone
+}
+
+// Clone returns a deep copy of c, keeping its providers' resolved API keys.
+func (c *Config) Clone() *Config {
+	clone := cloneConfig(c)
+	for i, p := range clone.Providers {
+		if i < len(c.Providers) {
+			p.resolvedAPIKey = c.Providers[i].resolvedAPIKey
+		}
+	}
+	return clone
+}
+
+// readIfChanged returns the user config on disk if another process has
+// written it since this manager last read or wrote it, or nil if it is
+// unchanged (or gone).
+func (m *Manager) readIfChanged() (*Config, string, error) {
+	data, err := os.ReadFile(m.configFile)
+	if os.IsNotExist(err) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read config file: %w", err)
+	}
+	if bytes.Equal(data, m.diskData) {
+		return nil, "", nil
+	}
+
+	format := detectFormat(m.configFile, data)
+	upgraded, version, err := upgradeData(format, data)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to parse config file: %w", err)
+	}
+	// Start from defaults and the system layers, as Load does, so settings
+	// missing from the file don't look like changes
+	theirs := m.systemLayers.defaults()
+	if err := decodeConfig(format, upgraded, theirs); err != nil {
+		return nil, "", fmt.Errorf("failed to parse config file: %w", err)
+	}
+	foldDefaultModels(theirs.Providers)
+	return theirs, version, nil
+}
+
+// mergeConcurrentChanges applies changes another process saved since this
+// manager's base snapshot to the current config, for every setting and
+// provider this process has not changed itself. Where both changed the same
+// thing, this process wins.
+func (m *Manager) mergeConcurrentChanges(theirs *Config) {
+	base := m.base
+	if base == nil {
+		base = &Config{}
+	}
+	ours := m.configForSave()
+
+	// Top-level settings
+	bv := reflect.ValueOf(base).Elem()
+	ov := reflect.ValueOf(&ours).Elem()
+	tv := reflect.ValueOf(theirs).Elem()
+	cv := reflect.ValueOf(m.config).Elem()
+	for i := 0; i < bv.NumField(); i++ {
+		switch bv.Type().Field(i).Name {
+		case "Version", "Providers":
+			continue
+		}
+		b, o, t := bv.Field(i).Interface(), ov.Field(i).Interface(), tv.Field(i).Interface()
+		if reflect.DeepEqual(o, b) && !reflect.DeepEqual(t, b) {
+			cv.Field(i).Set(tv.Field(i))
+		}
+	}
+
+	// Providers, compared by their persisted form
+	baseP := providerSnapshots(base.Providers)
+	oursP := providerSnapshots(ours.Providers)
+	theirNames := make(map[string]bool, len(theirs.Providers))
+	for _, t := range theirs.Providers {
+		theirNames[t.Name] = true
+		b, inBase := baseP[t.Name]
+		o, inOurs := oursP[t.Name]
+		switch {
+		case !inBase && !inOurs:
+			// Added by them
+			m.replaceProvider(t)
+		case inBase && inOurs && bytes.Equal(o, b) && !bytes.Equal(providerSnapshot(t), b):
+			// Changed by them only
+			m.replaceProvider(t)
+		}
+	}
+	for name, b := range baseP {
+		if o, inOurs := oursP[name]; !theirNames[name] && inOurs && bytes.Equal(o, b) {
+			// Removed by them, unchanged by us
+			m.config.RemoveProvider(name)
+		}
+	}
+}
+
+// replaceProvider puts p in the current config in place of the provider with
+// the same name, keeping a resolved API key if the key reference is unchanged,
+// or appends it.
+func (m *Manager) replaceProvider(p *Provider) {
+	existing := m.config.GetProvider(p.Name)
+	if existing == nil {
+		m.config.Providers = append(m.config.Providers, p)
+		return
+	}
+	key := existing.resolvedAPIKey
+	sameRef := existing.APIKeyRef == p.APIKeyRef
+	*existing = *p
+	if sameRef {
+		existing.resolvedAPIKey = key
+	}
+}
+
+// providerSnapshots returns the persisted form of each provider by name.
+func providerSnapshots(providers []*Provider) map[string][]byte {
+	snapshots := make(map[string][]byte, len(providers))
+	for _, p := range providers {
+		snapshots[p.Name] = providerSnapshot(p)
+	}
+	return snapshots
+}
+
+// providerSnapshot returns the persisted form of a provider.
+func providerSnapshot(p *Provider) []byte {
+	data, _ := yaml.Marshal(p)
+	return data
+}
@@ -213,7 +213,7 @@ func (m *Migration) Import() (*Config, map[string]string, error) {
 			Type:          ProviderTypeBuiltin,
 			DisplayName:   def.DisplayName,
 			BaseURL:       def.BaseURL,
-			DefaultModel:  def.Model,
+			Model:         def.Model,
 			ModelMappings: def.ModelOpts,
 		}
 
@@ -246,12 +246,12 @@ func (m *Migration) Import() (*Config, map[string]string, error) {
 
 		matches := orPattern.FindStringSubmatch(key)
 		if matches != nil {
-			name := "or-" + strings.ToLower(strings.ReplaceAll(matches[1], "_", "-"))
+			name := OpenRouterPrefix + strings.ToLower(strings.ReplaceAll(matches[1], "_", "-"))
 			provider := &Provider{
 				Name:        name,
 				Type:        ProviderTypeOpenRouter,
 				DisplayName: fmt.Sprintf("OpenRouter %s", matches[1]),
-				BaseURL:     "https://openrouter.ai/api",
+				BaseURL:     OpenRouterBaseURL,
 				Model:       value,
 			}
 			// Use the main OpenRouter API key
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// PermissionIssue is a config or data path that other users could read, or
+// that has been replaced by a symlink.
+type PermissionIssue struct {
+	Path    string
+	Mode    os.FileMode // current permission bits
+	Want    os.FileMode
+	Symlink bool
+}
+
+// Problem describes the issue for display.
+func (i PermissionIssue) Problem() string {
+	if i.Symlink {
+		return "is a symlink"
+	}
+	return fmt.Sprintf("mode %04o, expected %04o", i.Mode, i.Want)
+}
+
+// permTarget is a path that should be private to the user.
+type permTarget struct {
+	path string
+	want os.FileMode
+}
+
+// CheckPermissions reports config and data paths that are group or world
+// accessible, or are symlinks. Missing paths are skipped. Unix permission
+// bits mean nothing on Windows, so nothing is reported there.
+func (m *Manager) CheckPermissions() ([]PermissionIssue, error) {
+	if runtime.GOOS == "windows" {
+		return nil, nil
+	}
+	targets, err := m.permTargets()
+	if err != nil {
+		return nil, err
+	}
+	return checkPermissions(targets)
+}
+
+// FixPermissions restricts the paths in issues to their expected modes.
+// Symlinks are left alone: chmod would follow them to the target.
+func FixPermissions(issues []PermissionIssue) (fixed int, err error) {
+	for _, issue := range issues {
+		if issue.Symlink {
+			continue
+		}
+		if err := os.Chmod(issue.Path, issue.Want); err != nil {
+			return fixed, fmt.Errorf("failed to fix permissions: %w", err)
+		}
+		fixed++
+	}
+	return fixed, nil
+}
+
+// permTargets lists the paths holding the config and secrets. The config
+// directory is only checked when it is skint's own, not the directory of a
+// file given with --config.
+func (m *Manager) permTargets() ([]permTarget, error) {
+	var targets []permTarget
+	if dir, err := getConfigDir(); err == nil && dir == m.configDir {
+		targets = append(targets, permTarget{m.configDir, 0700})
+	}
+	targets = append(targets, permTarget{m.configFile, 0600})
+
+	dataDir, err := GetDataDir()
+	if err != nil {
+		return nil, err
+	}
+	// secrets.enc is the secrets file store (see package secrets)
+	targets = append(targets,
+		permTarget{dataDir, 0700},
+		permTarget{filepath.Join(dataDir, "secrets.enc"), 0600},
+	)
+	return targets, nil
+}
+
+func checkPermissions(targets []permTarget) ([]PermissionIssue, error) {
+	var issues []PermissionIssue
+	for _, t := range targets {
+		info, err := os.Lstat(t.path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat %s: %w", t.path, err)
+		}
+
+		mode := info.Mode().Perm()
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			issues = append(issues, PermissionIssue{Path: t.path, Mode: mode, Want: t.want, Symlink: true})
+		case mode&0077 != 0:
+			issues = append(issues, PermissionIssue{Path: t.path, Mode: mode, Want: t.want})
+		}
+	}
+	return issues, nil
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestCheckAndFixPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix permissions")
+	}
+
+	dir := filepath.Join(t.TempDir(), "skint")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	cfgFile := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(cfgFile, []byte("version: \"1.0\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	private := filepath.Join(dir, "private.yaml")
+	if err := os.WriteFile(private, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "secrets.enc")
+	if err := os.Symlink(private, link); err != nil {
+		t.Fatal(err)
+	}
+
+	targets := []permTarget{
+		{dir, 0700},
+		{cfgFile, 0600},
+		{private, 0600},
+		{link, 0600},
+		{filepath.Join(dir, "missing"), 0600},
+	}
+	issues, err := checkPermissions(targets)
+	if err != nil {
+		t.Fatalf("checkPermissions: %v", err)
+	}
+	if len(issues) != 3 {
+		t.Fatalf("issues = %+v, want dir, config file and symlink", issues)
+	}
+	if issues[1].Path != cfgFile || issues[1].Mode != 0644 || issues[1].Want != 0600 {
+		t.Errorf("config file issue = %+v", issues[1])
+	}
+	if !issues[2].Symlink {
+		t.Errorf("symlink not reported: %+v", issues[2])
+	}
+
+	fixed, err := FixPermissions(issues)
+	if err != nil {
+		t.Fatalf("FixPermissions: %v", err)
+	}
+	if fixed != 2 {
+		t.Errorf("fixed = %d, want 2 (symlinks are skipped)", fixed)
+	}
+	issues, err = checkPermissions(targets)
+	if err != nil {
+		t.Fatalf("checkPermissions: %v", err)
+	}
+	if len(issues) != 1 || !issues[0].Symlink {
+		t.Errorf("after fix: issues = %+v, want only the symlink", issues)
+	}
+	if info, _ := os.Stat(dir); info.Mode().Perm() != 0700 {
+		t.Errorf("dir mode = %04o, want 0700", info.Mode().Perm())
+	}
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Project config file names. ProjectFile is committed with the project;
+// ProjectLocalFile holds per-user overrides and is kept out of version control.
+const (
+	ProjectFile      = ".skint.yaml"
+	ProjectLocalFile = ".skint.local.yaml"
+)
+
+// ProjectConfig is the per-project config written by `skint init`. It only
+// names things defined in the user config (a provider, env presets) plus a
+// model, so a checked-out repository cannot redirect API keys to an endpoint
+// of its choosing.
+type ProjectConfig struct {
+	Provider   string   `yaml:"provider"`
+	Model      string   `yaml:"model,omitempty"`
+	EnvPresets []string `yaml:"env_presets,omitempty"`
+
+	// dir is the directory the project config was found in
+	dir string
+}
+
+// Dir returns the directory the project config was loaded from.
+func (pc *ProjectConfig) Dir() string {
+	return pc.dir
+}
+
+// FindProjectConfig looks for a project config in dir and each parent
+// directory, returning the nearest one with any local overrides applied, or
+// nil if there is none.
+func FindProjectConfig(dir string) (*ProjectConfig, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if _, err := os.Lstat(filepath.Join(dir, ProjectFile)); err == nil {
+			return LoadProjectConfig(dir)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// LoadProjectConfig reads the project config in dir, with ProjectLocalFile
+// (if present) layered over ProjectFile.
+func LoadProjectConfig(dir string) (*ProjectConfig, error) {
+	pc := &ProjectConfig{dir: dir}
+	for _, name := range []string{ProjectFile, ProjectLocalFile} {
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if err := yaml.Unmarshal(data, pc); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+	return pc, nil
+}
+
+// Save writes the project config to ProjectFile in dir.
+func (pc *ProjectConfig) Save(dir string) error {
+	data, err := yaml.Marshal(pc)
+	if err != nil {
+		return fmt.Errorf("failed to marshal project config: %w", err)
+	}
+	path := filepath.Join(dir, ProjectFile)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	pc.dir = dir
+	return nil
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindProjectConfig(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("none found", func(t *testing.T) {
+		pc, err := FindProjectConfig(sub)
+		if err != nil {
+			t.Fatalf("FindProjectConfig: %v", err)
+		}
+		if pc != nil && pc.Dir() == root {
+			t.Errorf("unexpected project config in %s", pc.Dir())
+		}
+	})
+
+	pc := &ProjectConfig{Provider: "zai", Model: "glm-5"}
+	if err := pc.Save(root); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	t.Run("found in parent", func(t *testing.T) {
+		got, err := FindProjectConfig(sub)
+		if err != nil {
+			t.Fatalf("FindProjectConfig: %v", err)
+		}
+		if got == nil {
+			t.Fatal("expected a project config")
+		}
+		if got.Provider != "zai" || got.Model != "glm-5" {
+			t.Errorf("got %+v", got)
+		}
+		if got.Dir() != root {
+			t.Errorf("Dir: got %q, want %q", got.Dir(), root)
+		}
+	})
+
+	t.Run("local overrides applied", func(t *testing.T) {
+		local := "model: glm-4.7\n"
+		if err := os.WriteFile(filepath.Join(root, ProjectLocalFile), []byte(local), 0600); err != nil {
+			t.Fatal(err)
+		}
+		got, err := FindProjectConfig(sub)
+		if err != nil {
+			t.Fatalf("FindProjectConfig: %v", err)
+		}
+		if got.Provider != "zai" {
+			t.Errorf("Provider: got %q, want zai", got.Provider)
+		}
+		if got.Model != "glm-4.7" {
+			t.Errorf("Model: got %q, want glm-4.7", got.Model)
+		}
+	})
+
+	t.Run("invalid file is an error", func(t *testing.T) {
+		if err := os.WriteFile(filepath.Join(root, ProjectFile), []byte("provider: [\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := FindProjectConfig(sub); err == nil {
+			t.Error("expected a parse error")
+		}
+	})
+}
@@ -2,53 +2,153 @@ package config
 
 import (
 	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // ConfigVersion is the current configuration file format version
-const ConfigVersion = "1.0"
+const ConfigVersion = "2.0"
 
 // Config represents the complete Skint configuration
 type Config struct {
-	Version         string      `yaml:"version" mapstructure:"version"`
-	DefaultProvider string      `yaml:"default_provider" mapstructure:"default_provider"`
-	OutputFormat    string      `yaml:"output_format" mapstructure:"output_format"`
-	ColorEnabled    bool        `yaml:"color_enabled" mapstructure:"color_enabled"`
-	NoBanner        bool        `yaml:"no_banner" mapstructure:"no_banner"`
-	ClaudeArgs      []string    `yaml:"claude_args,omitempty" mapstructure:"claude_args"`
-	Providers       []*Provider `yaml:"providers" mapstructure:"providers"`
+	Version         string   `yaml:"version" json:"version" toml:"version" mapstructure:"version" env:"-"`
+	DefaultProvider string   `yaml:"default_provider" json:"default_provider" toml:"default_provider" mapstructure:"default_provider"`
+	OutputFormat    string   `yaml:"output_format" json:"output_format" toml:"output_format" mapstructure:"output_format"`
+	ColorEnabled    bool     `yaml:"color_enabled" json:"color_enabled" toml:"color_enabled" mapstructure:"color_enabled"`
+	NoBanner        bool     `yaml:"no_banner" json:"no_banner" toml:"no_banner" mapstructure:"no_banner"`
+	ClaudeArgs      []string `yaml:"claude_args,omitempty" json:"claude_args,omitempty" toml:"claude_args,omitempty" mapstructure:"claude_args"`
+	ExitSummary     bool     `yaml:"exit_summary,omitempty" json:"exit_summary,omitempty" toml:"exit_summary,omitempty" mapstructure:"exit_summary"`
+
+	// Timeout bounds network requests such as provider tests and model
+	// lists; zero means DefaultTimeout.
+	Timeout Duration `yaml:"timeout,omitempty" json:"timeout,omitempty" toml:"timeout,omitempty" mapstructure:"timeout"`
+
+	// Retries is how many times a network request that failed transiently
+	// (a reset connection, a 429 or a 503) is retried, waiting RetryBackoff
+	// before the first retry and twice as long before each after. Unset
+	// means DefaultRetries, and a zero RetryBackoff DefaultRetryBackoff.
+	Retries      *int     `yaml:"retries,omitempty" json:"retries,omitempty" toml:"retries,omitempty" mapstructure:"retries"`
+	RetryBackoff Duration `yaml:"retry_backoff,omitempty" json:"retry_backoff,omitempty" toml:"retry_backoff,omitempty" mapstructure:"retry_backoff"`
+
+	// ModelCacheTTL is how long a provider's cached model list is used
+	// before it is fetched again; unset means DefaultModelCacheTTL and 0
+	// always fetches.
+	ModelCacheTTL *Duration `yaml:"model_cache_ttl,omitempty" json:"model_cache_ttl,omitempty" toml:"model_cache_ttl,omitempty" mapstructure:"model_cache_ttl"`
+
+	// AutoLaunchAfterUse makes 'skint use <provider>' launch Claude; when
+	// false it only sets the default provider. ConfirmBeforeLaunch asks
+	// before launching.
+	AutoLaunchAfterUse  bool `yaml:"auto_launch_after_use" json:"auto_launch_after_use" toml:"auto_launch_after_use" mapstructure:"auto_launch_after_use"`
+	ConfirmBeforeLaunch bool `yaml:"confirm_before_launch,omitempty" json:"confirm_before_launch,omitempty" toml:"confirm_before_launch,omitempty" mapstructure:"confirm_before_launch"`
+
+	// Watchdog supervises Claude: when it exits soon after starting with an
+	// authentication or connection error, skint asks to relaunch ("ask") or
+	// relaunches ("auto") with the next of FallbackProviders. Empty or "off"
+	// launches Claude unsupervised.
+	Watchdog          string   `yaml:"watchdog,omitempty" json:"watchdog,omitempty" toml:"watchdog,omitempty" mapstructure:"watchdog"`
+	FallbackProviders []string `yaml:"fallback_providers,omitempty" json:"fallback_providers,omitempty" toml:"fallback_providers,omitempty" mapstructure:"fallback_providers"`
+
+	// PTY runs Claude in a pseudo-terminal of skint's instead of handing it
+	// skint's terminal (Linux and macOS), for when skint itself runs under a
+	// wrapper that gets in the way.
+	PTY bool `yaml:"pty,omitempty" json:"pty,omitempty" toml:"pty,omitempty" mapstructure:"pty"`
+
+	SyncRemote string      `yaml:"sync_remote,omitempty" json:"sync_remote,omitempty" toml:"sync_remote,omitempty" mapstructure:"sync_remote"`
+	SyncBranch string      `yaml:"sync_branch,omitempty" json:"sync_branch,omitempty" toml:"sync_branch,omitempty" mapstructure:"sync_branch"`
+	Providers  []*Provider `yaml:"providers" json:"providers" toml:"providers" mapstructure:"providers"`
+
+	// ProviderOrder lists providers to show first in the TUI and 'skint
+	// list', in this order; the rest follow in the usual order.
+	ProviderOrder []string `yaml:"provider_order,omitempty" json:"provider_order,omitempty" toml:"provider_order,omitempty" mapstructure:"provider_order"`
+
+	// EnvPresets are named bundles of extra env vars (e.g. "corp-proxy") that
+	// can be attached to any provider or selected at launch.
+	EnvPresets map[string]map[string]string `yaml:"env_presets,omitempty" json:"env_presets,omitempty" toml:"env_presets,omitempty" mapstructure:"env_presets"`
+
+	// Aliases name 'skint use' arguments (e.g. glm: [zai, --model, glm-5-air])
+	// run with 'skint glm' or 'skint run glm'.
+	Aliases map[string][]string `yaml:"aliases,omitempty" json:"aliases,omitempty" toml:"aliases,omitempty" mapstructure:"aliases"`
+
+	// DirectoryRules pick the default provider by working directory; the
+	// first matching rule applies.
+	DirectoryRules []DirectoryRule `yaml:"directory_rules,omitempty" json:"directory_rules,omitempty" toml:"directory_rules,omitempty" mapstructure:"directory_rules"`
+
+	// ClearEnv and KeepEnv adjust the variables removed from the environment
+	// before launching (the ANTHROPIC_* and OPENAI_* ones skint sets):
+	// ClearEnv adds more, such as a gateway's own key, and KeepEnv leaves
+	// some alone. Providers still replace any they set.
+	ClearEnv []string `yaml:"clear_env,omitempty" json:"clear_env,omitempty" toml:"clear_env,omitempty" mapstructure:"clear_env"`
+	KeepEnv  []string `yaml:"keep_env,omitempty" json:"keep_env,omitempty" toml:"keep_env,omitempty" mapstructure:"keep_env"`
+
+	// Theme sets the TUI colours; nil uses the default palette.
+	Theme *Theme `yaml:"theme,omitempty" json:"theme,omitempty" toml:"theme,omitempty" mapstructure:"theme"`
 }
 
 // Provider represents a single LLM provider configuration
 type Provider struct {
 	// Core identification
-	Name        string `yaml:"name" mapstructure:"name"`
-	Type        string `yaml:"type" mapstructure:"type"`
-	DisplayName string `yaml:"display_name" mapstructure:"display_name"`
-	Description string `yaml:"description" mapstructure:"description"`
+	Name        string `yaml:"name" json:"name" toml:"name" mapstructure:"name" env:"-"`
+	Type        string `yaml:"type" json:"type" toml:"type" mapstructure:"type" env:"-"`
+	DisplayName string `yaml:"display_name" json:"display_name" toml:"display_name" mapstructure:"display_name"`
+	Description string `yaml:"description" json:"description" toml:"description" mapstructure:"description"`
+
+	// Tags are free-form labels (e.g. work, local-gpu) for filtering 'skint
+	// list'
+	Tags []string `yaml:"tags,omitempty" json:"tags,omitempty" toml:"tags,omitempty" mapstructure:"tags"`
 
 	// Connection details
-	BaseURL string `yaml:"base_url,omitempty" mapstructure:"base_url"`
-	APIKey  string `yaml:"api_key,omitempty" mapstructure:"api_key"` // For migration only
+	BaseURL string `yaml:"base_url,omitempty" json:"base_url,omitempty" toml:"base_url,omitempty" mapstructure:"base_url"`
+	APIKey  string `yaml:"api_key,omitempty" json:"api_key,omitempty" toml:"api_key,omitempty" mapstructure:"api_key" env:"-"` // For migration only
 
 	// API key reference format: "keyring:<name>" or "file:<name>"
-	APIKeyRef string `yaml:"api_key_ref,omitempty" mapstructure:"api_key_ref"`
+	APIKeyRef string `yaml:"api_key_ref,omitempty" json:"api_key_ref,omitempty" toml:"api_key_ref,omitempty" mapstructure:"api_key_ref"`
 
 	// Model configuration
-	// DefaultModel is the primary model for builtin providers (the provider's default offering).
-	// Model is the specific model ID for OpenRouter/custom providers (user-selected).
-	// Use EffectiveModel() to get whichever is set.
-	DefaultModel  string            `yaml:"default_model,omitempty" mapstructure:"default_model"`
-	Model         string            `yaml:"model,omitempty" mapstructure:"model"`
-	ModelMappings map[string]string `yaml:"model_mappings,omitempty" mapstructure:"model_mappings"`
+	// Model is the model to use, whether the registry default or one the user
+	// picked. ModelMappings are per-tier overrides (haiku, sonnet, opus,
+	// small) on top of it.
+	Model         string            `yaml:"model,omitempty" json:"model,omitempty" toml:"model,omitempty" mapstructure:"model"`
+	ModelMappings map[string]string `yaml:"model_mappings,omitempty" json:"model_mappings,omitempty" toml:"model_mappings,omitempty" mapstructure:"model_mappings"`
+
+	// Deprecated: DefaultModel is the pre-2.0 registry default model. It is
+	// still read for backwards compatibility and folded into Model on load.
+	DefaultModel string `yaml:"default_model,omitempty" json:"default_model,omitempty" toml:"default_model,omitempty" mapstructure:"default_model" env:"-"`
 
 	// Local provider specific
-	AuthToken string `yaml:"auth_token,omitempty" mapstructure:"auth_token"`
+	AuthToken string `yaml:"auth_token,omitempty" json:"auth_token,omitempty" toml:"auth_token,omitempty" mapstructure:"auth_token"`
 
 	// Custom provider specific
-	APIType string `yaml:"api_type,omitempty" mapstructure:"api_type"` // "anthropic" or "openai"
+	APIType string `yaml:"api_type,omitempty" json:"api_type,omitempty" toml:"api_type,omitempty" mapstructure:"api_type"` // "anthropic" or "openai"
 
 	// Env var override for API key (e.g. ANTHROPIC_API_KEY instead of ANTHROPIC_AUTH_TOKEN)
-	KeyEnvVar string `yaml:"key_env_var,omitempty" mapstructure:"key_env_var"`
+	KeyEnvVar string `yaml:"key_env_var,omitempty" json:"key_env_var,omitempty" toml:"key_env_var,omitempty" mapstructure:"key_env_var"`
+
+	// Names of Config.EnvPresets applied whenever this provider is launched
+	EnvPresets []string `yaml:"env_presets,omitempty" json:"env_presets,omitempty" toml:"env_presets,omitempty" mapstructure:"env_presets"`
+
+	// Arguments passed to claude when launching this provider, after Config.ClaudeArgs
+	ClaudeArgs []string `yaml:"claude_args,omitempty" json:"claude_args,omitempty" toml:"claude_args,omitempty" mapstructure:"claude_args"`
+
+	// MCP servers given to claude when launching this provider, by name.
+	// MCPStrict makes them the only ones, ignoring those from Claude's own
+	// settings.
+	MCPServers map[string]MCPServer `yaml:"mcp_servers,omitempty" json:"mcp_servers,omitempty" toml:"mcp_servers,omitempty" mapstructure:"mcp_servers"`
+	MCPStrict  bool                 `yaml:"mcp_strict,omitempty" json:"mcp_strict,omitempty" toml:"mcp_strict,omitempty" mapstructure:"mcp_strict"`
+
+	// Target is the coding agent launched with this provider: claude (the
+	// default), codex, opencode, aider, or custom to run TargetCommand. The
+	// provider's variables are mapped to the ones the target reads, and
+	// ClaudeArgs are passed to it.
+	Target        string `yaml:"target,omitempty" json:"target,omitempty" toml:"target,omitempty" mapstructure:"target"`
+	TargetCommand string `yaml:"target_command,omitempty" json:"target_command,omitempty" toml:"target_command,omitempty" mapstructure:"target_command"`
+
+	// PreserveEnv names variables kept from the environment Claude is
+	// launched from: never removed as conflicting, and not replaced by the
+	// provider's values. Env presets still set them.
+	PreserveEnv []string `yaml:"preserve_env,omitempty" json:"preserve_env,omitempty" toml:"preserve_env,omitempty" mapstructure:"preserve_env"`
 
 	// Internal: loaded from keyring/file
 	resolvedAPIKey string
@@ -62,6 +162,17 @@ const (
 	ProviderTypeCustom     = "custom"
 )
 
+// OpenRouterBaseURL is the endpoint OpenRouter providers use, and
+// OpenRouterPrefix starts the name of each per-model OpenRouter provider
+// (or-<name>), as created by 'skint config openrouter'.
+const (
+	OpenRouterBaseURL = "https://openrouter.ai/api"
+	OpenRouterPrefix  = "or-"
+)
+
+// ProviderTypes lists the provider types, for flags and completions.
+var ProviderTypes = []string{ProviderTypeBuiltin, ProviderTypeOpenRouter, ProviderTypeLocal, ProviderTypeCustom}
+
 // API types for custom providers
 const (
 	APITypeAnthropic = "anthropic"
@@ -73,8 +184,147 @@ const (
 	FormatHuman = "human"
 	FormatJSON  = "json"
 	FormatPlain = "plain"
+	FormatTable = "table"
+)
+
+// Duration is a duration setting, written as a Go duration such as "30s"
+// or "2m" in the config file and SKINT_* overrides.
+type Duration time.Duration
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// MarshalText writes d as a Go duration.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText parses a Go duration.
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(strings.TrimSpace(string(text)))
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: use one such as 30s or 2m", text)
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// DefaultTimeout is the network timeout when the config doesn't set one.
+const DefaultTimeout = 5 * time.Second
+
+// ParseTimeout parses a timeout setting, which must be a positive duration.
+func ParseTimeout(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid timeout %q: use a duration such as 30s or 2m", s)
+	}
+	return d, nil
+}
+
+// Network retry defaults, when the config doesn't set them.
+const (
+	DefaultRetries      = 2
+	DefaultRetryBackoff = 500 * time.Millisecond
 )
 
+// maxRetries bounds the retries setting, so a typo can't hang every request.
+const maxRetries = 10
+
+// ParseRetries parses a retries setting, a whole number from 0 to 10.
+func ParseRetries(s string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || n < 0 || n > maxRetries {
+		return 0, fmt.Errorf("invalid retries %q: use a number from 0 to %d", s, maxRetries)
+	}
+	return n, nil
+}
+
+// DefaultModelCacheTTL is how long model lists are cached when the config
+// doesn't say.
+const DefaultModelCacheTTL = time.Hour
+
+// ParseCacheTTL parses a model_cache_ttl setting, which must be a duration
+// of zero or more.
+func ParseCacheTTL(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid model_cache_ttl %q: use a duration such as 30m or 24h, or 0 to always fetch", s)
+	}
+	return d, nil
+}
+
+// Watchdog modes
+const (
+	WatchdogOff  = "off"
+	WatchdogAsk  = "ask"
+	WatchdogAuto = "auto"
+)
+
+func validateWatchdog(mode string) error {
+	switch mode {
+	case "", WatchdogOff, WatchdogAsk, WatchdogAuto:
+		return nil
+	}
+	return fmt.Errorf("invalid watchdog %q: valid: %s, %s, %s", mode, WatchdogOff, WatchdogAsk, WatchdogAuto)
+}
+
+// Supervised reports whether the watchdog is on.
+func (c *Config) Supervised() bool {
+	return c.Watchdog == WatchdogAsk || c.Watchdog == WatchdogAuto
+}
+
+// NextFallback returns the provider to relaunch with after name failed: the
+// first of FallbackProviders after name (or from the start, if name isn't in
+// the list) that is configured, or "native", and not in tried. It returns ""
+// when there is none. Unknown names are skipped rather than rejected by
+// Validate, so removing a provider doesn't make the config unloadable.
+func (c *Config) NextFallback(name string, tried map[string]bool) string {
+	start := slices.Index(c.FallbackProviders, name) + 1
+	for _, next := range c.FallbackProviders[start:] {
+		if tried[next] || next == name {
+			continue
+		}
+		if next == "native" || c.GetProvider(next) != nil {
+			return next
+		}
+	}
+	return ""
+}
+
+// NetworkTimeout returns the timeout for network requests.
+func (c *Config) NetworkTimeout() time.Duration {
+	if c.Timeout > 0 {
+		return time.Duration(c.Timeout)
+	}
+	return DefaultTimeout
+}
+
+// NetworkRetries returns how many times transient network failures are
+// retried.
+func (c *Config) NetworkRetries() int {
+	if c.Retries != nil && *c.Retries >= 0 && *c.Retries <= maxRetries {
+		return *c.Retries
+	}
+	return DefaultRetries
+}
+
+// NetworkRetryBackoff returns the wait before the first retry.
+func (c *Config) NetworkRetryBackoff() time.Duration {
+	if c.RetryBackoff > 0 {
+		return time.Duration(c.RetryBackoff)
+	}
+	return DefaultRetryBackoff
+}
+
+// ModelCacheMaxAge returns how long cached model lists are used.
+func (c *Config) ModelCacheMaxAge() time.Duration {
+	if c.ModelCacheTTL != nil && *c.ModelCacheTTL >= 0 {
+		return time.Duration(*c.ModelCacheTTL)
+	}
+	return DefaultModelCacheTTL
+}
+
 // Validate checks if the configuration is valid
 func (c *Config) Validate() error {
 	if c.Version == "" {
@@ -85,10 +335,68 @@ func (c *Config) Validate() error {
 		c.OutputFormat = FormatHuman
 	}
 
-	if c.OutputFormat != FormatHuman && c.OutputFormat != FormatJSON && c.OutputFormat != FormatPlain {
+	if c.OutputFormat != FormatHuman && c.OutputFormat != FormatJSON && c.OutputFormat != FormatPlain && c.OutputFormat != FormatTable {
 		return fmt.Errorf("invalid output format: %s", c.OutputFormat)
 	}
 
+	if c.Timeout < 0 {
+		return fmt.Errorf("invalid timeout %s: use a duration such as 30s or 2m", c.Timeout)
+	}
+
+	if c.Retries != nil && (*c.Retries < 0 || *c.Retries > maxRetries) {
+		return fmt.Errorf("invalid retries %d: use a number from 0 to %d", *c.Retries, maxRetries)
+	}
+
+	if c.RetryBackoff < 0 {
+		return fmt.Errorf("invalid retry_backoff %s: use a duration such as 500ms or 2s", c.RetryBackoff)
+	}
+
+	if c.ModelCacheTTL != nil && *c.ModelCacheTTL < 0 {
+		return fmt.Errorf("invalid model_cache_ttl %s: use a duration such as 30m or 24h, or 0 to always fetch", *c.ModelCacheTTL)
+	}
+
+	if err := validateWatchdog(c.Watchdog); err != nil {
+		return err
+	}
+
+	// Validate env presets
+	for name, vars := range c.EnvPresets {
+		if name == "" {
+			return fmt.Errorf("env preset has no name")
+		}
+		for key := range vars {
+			if key == "" || strings.ContainsAny(key, "= ") {
+				return fmt.Errorf("env preset %s: invalid variable name %q", name, key)
+			}
+		}
+	}
+
+	for _, list := range []struct {
+		key   string
+		names []string
+	}{{"clear_env", c.ClearEnv}, {"keep_env", c.KeepEnv}} {
+		for _, name := range list.names {
+			if name == "" || strings.ContainsAny(name, "= ") {
+				return fmt.Errorf("invalid %s variable name %q", list.key, name)
+			}
+		}
+	}
+
+	for name, args := range c.Aliases {
+		if err := ValidateAliasName(name); err != nil {
+			return err
+		}
+		if len(args) == 0 {
+			return fmt.Errorf("alias %s is empty", name)
+		}
+	}
+
+	if c.Theme != nil {
+		if err := c.Theme.validate(); err != nil {
+			return fmt.Errorf("theme: %w", err)
+		}
+	}
+
 	// Validate providers
 	names := make(map[string]bool)
 	for i, p := range c.Providers {
@@ -103,6 +411,26 @@ func (c *Config) Validate() error {
 		if err := p.Validate(); err != nil {
 			return fmt.Errorf("provider %s: %w", p.Name, err)
 		}
+
+		for _, preset := range p.EnvPresets {
+			if _, ok := c.EnvPresets[preset]; !ok {
+				return fmt.Errorf("provider %s: unknown env preset %q", p.Name, preset)
+			}
+		}
+	}
+
+	// Validate directory rules. The provider is checked when a rule is
+	// applied, so removing a provider doesn't make the config unloadable.
+	for i := range c.DirectoryRules {
+		r := &c.DirectoryRules[i]
+		if err := r.validate(); err != nil {
+			return fmt.Errorf("directory rule %d: %w", i+1, err)
+		}
+		for _, preset := range r.EnvPresets {
+			if _, ok := c.EnvPresets[preset]; !ok {
+				return fmt.Errorf("directory rule %d: unknown env preset %q", i+1, preset)
+			}
+		}
 	}
 
 	// Validate default provider exists in the providers list.
@@ -143,6 +471,37 @@ func (p *Provider) Validate() error {
 		return fmt.Errorf("invalid api_type %q: must be %q or %q", p.APIType, APITypeAnthropic, APITypeOpenAI)
 	}
 
+	for _, tag := range p.Tags {
+		if tag == "" || strings.ContainsAny(tag, ", \t") {
+			return fmt.Errorf("invalid tag %q: tags can't be empty or contain spaces or commas", tag)
+		}
+	}
+
+	if err := validateTarget(p.Target); err != nil {
+		return fmt.Errorf("invalid target %q: %w", p.Target, err)
+	}
+	if p.Target == TargetCustom && p.TargetCommand == "" {
+		return fmt.Errorf("target %s needs a target_command", p.Target)
+	}
+	for _, name := range p.PreserveEnv {
+		if name == "" || strings.ContainsAny(name, "= ") {
+			return fmt.Errorf("invalid preserve_env variable name %q", name)
+		}
+	}
+
+	if len(p.MCPServers) > 0 && p.TargetName() != TargetClaude {
+		return fmt.Errorf("mcp_servers are only passed to claude, not %s", p.TargetName())
+	}
+
+	for name, s := range p.MCPServers {
+		if name == "" {
+			return fmt.Errorf("MCP server has no name")
+		}
+		if err := s.validate(); err != nil {
+			return fmt.Errorf("MCP server %s: %w", name, err)
+		}
+	}
+
 	return nil
 }
 
@@ -179,6 +538,61 @@ func (c *Config) RemoveProvider(name string) bool {
 	return false
 }
 
+// PresetEnv returns the combined variables of the named env presets. Later
+// presets override earlier ones; an unknown name is an error.
+func (c *Config) PresetEnv(names ...string) (map[string]string, error) {
+	env := make(map[string]string)
+	for _, name := range names {
+		vars, ok := c.EnvPresets[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown env preset: %s", name)
+		}
+		for k, v := range vars {
+			env[k] = v
+		}
+	}
+	return env, nil
+}
+
+// ProviderRank returns the position of name in ProviderOrder, or -1 if it is
+// not listed.
+func (c *Config) ProviderRank(name string) int {
+	for i, n := range c.ProviderOrder {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// OrderedProviders returns the providers with those in ProviderOrder first,
+// in that order, followed by the rest in config order.
+func (c *Config) OrderedProviders() []*Provider {
+	ordered := append([]*Provider{}, c.Providers...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		ri, rj := c.ProviderRank(ordered[i].Name), c.ProviderRank(ordered[j].Name)
+		if ri < 0 || rj < 0 {
+			return ri >= 0 && rj < 0
+		}
+		return ri < rj
+	})
+	return ordered
+}
+
+// LaunchArgs returns the default arguments for launching p: the global
+// ClaudeArgs, unless p launches another target, followed by the provider's
+// own. p may be nil (native).
+func (c *Config) LaunchArgs(p *Provider) []string {
+	args := []string{}
+	if p == nil || p.TargetName() == TargetClaude {
+		args = append(args, c.ClaudeArgs...)
+	}
+	if p != nil {
+		args = append(args, p.ClaudeArgs...)
+	}
+	return args
+}
+
 // SetResolvedAPIKey sets the resolved API key (from keyring/file)
 func (p *Provider) SetResolvedAPIKey(key string) {
 	p.resolvedAPIKey = key
@@ -189,9 +603,9 @@ func (p *Provider) GetAPIKey() string {
 	return p.resolvedAPIKey
 }
 
-// EffectiveModel returns the model to use. A user-selected Model always takes
-// precedence over the provider's registry DefaultModel; returns empty if neither
-// is set.
+// EffectiveModel returns the model to use. Model takes precedence over a
+// legacy DefaultModel that has not been folded in yet; returns empty if
+// neither is set.
 func (p *Provider) EffectiveModel() string {
 	if p.Model != "" {
 		return p.Model
@@ -199,12 +613,47 @@ func (p *Provider) EffectiveModel() string {
 	return p.DefaultModel
 }
 
+// foldDefaultModel moves a legacy DefaultModel into Model (unless Model is
+// already set) and clears it, so it is not written back.
+func (p *Provider) foldDefaultModel() {
+	if p.Model == "" {
+		p.Model = p.DefaultModel
+	}
+	p.DefaultModel = ""
+}
+
+// foldDefaultModels folds each provider's legacy DefaultModel into Model. It is
+// applied to providers as they are read, since a current-version file can still
+// carry a hand-written default_model that the schema migration did not see.
+func foldDefaultModels(providers []*Provider) {
+	for _, p := range providers {
+		p.foldDefaultModel()
+	}
+}
+
 // NeedsAPIKey returns true if this provider requires an API key.
 // Local providers and the native Anthropic provider do not need one.
 func (p *Provider) NeedsAPIKey() bool {
 	return p.Type != ProviderTypeLocal && p.Name != "native"
 }
 
+// KeyBackend names where the API key is kept: the backend in its key
+// reference (keyring, file, env...), "config" for a plain-text key, or "" if
+// it has none or doesn't need one.
+func (p *Provider) KeyBackend() string {
+	if !p.NeedsAPIKey() {
+		return ""
+	}
+	if p.APIKeyRef != "" {
+		backend, _, _ := strings.Cut(p.APIKeyRef, ":")
+		return backend
+	}
+	if p.APIKey != "" {
+		return "config"
+	}
+	return ""
+}
+
 // IsConfigured returns true if this provider has been fully configured.
 // Checks APIKeyRef (persisted reference) rather than the runtime-resolved key,
 // so it works correctly for providers configured during the current session.
@@ -218,10 +667,11 @@ func (p *Provider) IsConfigured() bool {
 // NewDefaultConfig creates a new configuration with sensible defaults
 func NewDefaultConfig() *Config {
 	return &Config{
-		Version:      ConfigVersion,
-		OutputFormat: FormatHuman,
-		ColorEnabled: true,
-		NoBanner:     false,
-		Providers:    []*Provider{},
+		Version:            ConfigVersion,
+		OutputFormat:       FormatHuman,
+		ColorEnabled:       true,
+		NoBanner:           false,
+		AutoLaunchAfterUse: true,
+		Providers:          []*Provider{},
 	}
 }
@@ -1,7 +1,9 @@
 package config
 
 import (
+	"strings"
 	"testing"
+	"time"
 )
 
 // TestProviderValidate covers validation rules for individual providers.
@@ -116,6 +118,42 @@ func TestProviderValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "unknown target is invalid",
+			p: Provider{
+				Name: "native", Type: ProviderTypeBuiltin, Target: "cursor",
+			},
+			wantErr: true,
+		},
+		{
+			name: "custom target needs a command",
+			p: Provider{
+				Name: "native", Type: ProviderTypeBuiltin, Target: TargetCustom,
+			},
+			wantErr: true,
+		},
+		{
+			name: "custom target with a command is valid",
+			p: Provider{
+				Name: "native", Type: ProviderTypeBuiltin, Target: TargetCustom, TargetCommand: "my-agent",
+			},
+			wantErr: false,
+		},
+		{
+			name: "preserve_env names must be variable names",
+			p: Provider{
+				Name: "native", Type: ProviderTypeBuiltin, PreserveEnv: []string{"A=B"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "MCP servers only go to claude",
+			p: Provider{
+				Name: "native", Type: ProviderTypeBuiltin, Target: TargetCodex,
+				MCPServers: map[string]MCPServer{"gh": {Command: "gh-mcp"}},
+			},
+			wantErr: true,
+		},
 		{
 			name: "empty provider type is invalid",
 			p: Provider{
@@ -156,6 +194,121 @@ func TestConfigValidateDuplicateProviders(t *testing.T) {
 	}
 }
 
+// TestNetworkTimeout checks the timeout setting's default and validation.
+func TestNetworkTimeout(t *testing.T) {
+	cfg := &Config{Version: ConfigVersion, OutputFormat: FormatHuman}
+	if got := cfg.NetworkTimeout(); got != DefaultTimeout {
+		t.Errorf("unset timeout = %v, want %v", got, DefaultTimeout)
+	}
+	cfg.Timeout = Duration(90 * time.Second)
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if got := cfg.NetworkTimeout(); got != 90*time.Second {
+		t.Errorf("timeout = %v, want 90s", got)
+	}
+	cfg.Timeout = Duration(-5 * time.Second)
+	if err := cfg.Validate(); err == nil {
+		t.Error("timeout -5s should be invalid")
+	}
+}
+
+// TestNetworkRetries checks the retries and retry_backoff settings' defaults
+// and validation.
+func TestNetworkRetries(t *testing.T) {
+	cfg := &Config{Version: ConfigVersion, OutputFormat: FormatHuman}
+	if cfg.NetworkRetries() != DefaultRetries || cfg.NetworkRetryBackoff() != DefaultRetryBackoff {
+		t.Errorf("unset = %d, %v; want %d, %v", cfg.NetworkRetries(), cfg.NetworkRetryBackoff(), DefaultRetries, DefaultRetryBackoff)
+	}
+	zero := 0
+	cfg.Retries, cfg.RetryBackoff = &zero, Duration(2*time.Second)
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if cfg.NetworkRetries() != 0 || cfg.NetworkRetryBackoff() != 2*time.Second {
+		t.Errorf("got %d, %v; want 0, 2s", cfg.NetworkRetries(), cfg.NetworkRetryBackoff())
+	}
+	for _, bad := range []int{-1, 11} {
+		cfg.Retries = &bad
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("retries %d should be invalid", bad)
+		}
+	}
+	cfg.Retries, cfg.RetryBackoff = nil, Duration(-time.Second)
+	if err := cfg.Validate(); err == nil {
+		t.Error("retry_backoff -1s should be invalid")
+	}
+}
+
+// TestModelCacheMaxAge checks the model_cache_ttl setting's default and
+// validation.
+func TestModelCacheMaxAge(t *testing.T) {
+	cfg := &Config{Version: ConfigVersion, OutputFormat: FormatHuman}
+	if got := cfg.ModelCacheMaxAge(); got != DefaultModelCacheTTL {
+		t.Errorf("unset TTL = %v, want %v", got, DefaultModelCacheTTL)
+	}
+	for _, want := range []time.Duration{24 * time.Hour, 0} {
+		ttl := Duration(want)
+		cfg.ModelCacheTTL = &ttl
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("Validate(%v): %v", want, err)
+		}
+		if got := cfg.ModelCacheMaxAge(); got != want {
+			t.Errorf("TTL %v = %v", want, got)
+		}
+	}
+	ttl := Duration(-time.Hour)
+	cfg.ModelCacheTTL = &ttl
+	if err := cfg.Validate(); err == nil {
+		t.Error("TTL -1h should be invalid")
+	}
+}
+
+// TestNetworkSettingsFormats checks the network settings decode the same
+// from every config format, and that durations must be Go durations.
+func TestNetworkSettingsFormats(t *testing.T) {
+	docs := map[string]string{
+		"yaml": "version: \"2.0\"\ntimeout: 90s\nretries: 0\nretry_backoff: 2s\nmodel_cache_ttl: 0\n",
+		"json": `{"version": "2.0", "timeout": "90s", "retries": 0, "retry_backoff": "2s", "model_cache_ttl": "0"}`,
+		"toml": "version = \"2.0\"\ntimeout = \"90s\"\nretries = 0\nretry_backoff = \"2s\"\nmodel_cache_ttl = \"0\"\n",
+	}
+	for format, doc := range docs {
+		cfg, err := ParseConfig([]byte(doc))
+		if err != nil {
+			t.Fatalf("%s: ParseConfig: %v", format, err)
+		}
+		if cfg.NetworkTimeout() != 90*time.Second || cfg.NetworkRetries() != 0 || cfg.NetworkRetryBackoff() != 2*time.Second || cfg.ModelCacheMaxAge() != 0 {
+			t.Errorf("%s: got %v, %d, %v, %v", format, cfg.NetworkTimeout(), cfg.NetworkRetries(), cfg.NetworkRetryBackoff(), cfg.ModelCacheMaxAge())
+		}
+
+		data, err := encodeConfig(format, cfg)
+		if err != nil {
+			t.Fatalf("%s: encode: %v", format, err)
+		}
+		if !strings.Contains(string(data), "1m30s") {
+			t.Errorf("%s: timeout not written as a duration:\n%s", format, data)
+		}
+	}
+
+	for _, bad := range []string{"timeout: soon\n", "retry_backoff: 2 seconds\n", "model_cache_ttl: daily\n", "retries: two\n"} {
+		if _, err := ParseConfig([]byte("version: \"2.0\"\n" + bad)); err == nil {
+			t.Errorf("%q should be invalid", bad)
+		}
+	}
+}
+
+// TestConfigValidateEnvLists checks the clear_env and keep_env names.
+func TestConfigValidateEnvLists(t *testing.T) {
+	cfg := &Config{Version: ConfigVersion, OutputFormat: FormatHuman, ClearEnv: []string{"PORTKEY_API_KEY"}, KeepEnv: []string{"OPENAI_API_KEY"}}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	cfg.KeepEnv = []string{"OPENAI API KEY"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for an invalid keep_env name, got nil")
+	}
+}
+
 // TestConfigValidateEmptyProviderName checks that a provider with an empty
 // name is rejected by Config.Validate.
 func TestConfigValidateEmptyProviderName(t *testing.T) {
@@ -271,3 +424,163 @@ func TestEffectiveModel(t *testing.T) {
 		})
 	}
 }
+
+func TestEnvPresets(t *testing.T) {
+	cfg := NewDefaultConfig()
+	cfg.EnvPresets = map[string]map[string]string{
+		"corp-proxy":    {"HTTPS_PROXY": "http://proxy:3128", "NO_PROXY": "localhost"},
+		"debug-logging": {"ANTHROPIC_LOG": "debug", "NO_PROXY": "*"},
+	}
+	cfg.Providers = []*Provider{
+		{Name: "ollama", Type: ProviderTypeLocal, EnvPresets: []string{"corp-proxy"}},
+	}
+
+	t.Run("valid presets pass validation", func(t *testing.T) {
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("Validate: %v", err)
+		}
+	})
+
+	t.Run("later presets override earlier ones", func(t *testing.T) {
+		env, err := cfg.PresetEnv("corp-proxy", "debug-logging")
+		if err != nil {
+			t.Fatalf("PresetEnv: %v", err)
+		}
+		if env["HTTPS_PROXY"] != "http://proxy:3128" || env["ANTHROPIC_LOG"] != "debug" {
+			t.Errorf("PresetEnv missing vars: %v", env)
+		}
+		if env["NO_PROXY"] != "*" {
+			t.Errorf("NO_PROXY: got %q, want %q", env["NO_PROXY"], "*")
+		}
+	})
+
+	t.Run("unknown preset is an error", func(t *testing.T) {
+		if _, err := cfg.PresetEnv("nope"); err == nil {
+			t.Fatal("expected error for unknown preset, got nil")
+		}
+	})
+
+	t.Run("provider referencing unknown preset fails validation", func(t *testing.T) {
+		bad := *cfg
+		bad.Providers = []*Provider{
+			{Name: "ollama", Type: ProviderTypeLocal, EnvPresets: []string{"missing"}},
+		}
+		if err := bad.Validate(); err == nil {
+			t.Fatal("expected validation error, got nil")
+		}
+	})
+
+	t.Run("invalid variable name fails validation", func(t *testing.T) {
+		bad := *cfg
+		bad.EnvPresets = map[string]map[string]string{"broken": {"A=B": "x"}}
+		bad.Providers = nil
+		if err := bad.Validate(); err == nil {
+			t.Fatal("expected validation error, got nil")
+		}
+	})
+}
+
+// TestFoldDefaultModel checks default_model is folded into model on load, and
+// that Validate alone leaves the provider as it is.
+func TestFoldDefaultModel(t *testing.T) {
+	tests := []struct {
+		name, model, defaultModel, want string
+	}{
+		{name: "default only", defaultModel: "glm-5", want: "glm-5"},
+		{name: "model wins", model: "glm-4.7", defaultModel: "glm-5", want: "glm-4.7"},
+		{name: "neither", want: ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &Provider{Name: "x", Type: ProviderTypeLocal, Model: tc.model, DefaultModel: tc.defaultModel}
+			if err := p.Validate(); err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			if p.Model != tc.model || p.DefaultModel != tc.defaultModel {
+				t.Fatalf("Validate changed the provider: Model %q, DefaultModel %q", p.Model, p.DefaultModel)
+			}
+			foldDefaultModels([]*Provider{p})
+			if p.Model != tc.want || p.DefaultModel != "" {
+				t.Errorf("got Model %q, DefaultModel %q; want Model %q", p.Model, p.DefaultModel, tc.want)
+			}
+		})
+	}
+}
+
+func TestOrderedProviders(t *testing.T) {
+	cfg := &Config{
+		Providers: []*Provider{
+			{Name: "zai"}, {Name: "minimax"}, {Name: "ollama"}, {Name: "kimi"},
+		},
+		ProviderOrder: []string{"ollama", "missing", "kimi"},
+	}
+
+	var got []string
+	for _, p := range cfg.OrderedProviders() {
+		got = append(got, p.Name)
+	}
+	want := []string{"ollama", "kimi", "zai", "minimax"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("OrderedProviders = %v, want %v", got, want)
+	}
+	if cfg.Providers[0].Name != "zai" {
+		t.Error("OrderedProviders reordered the config")
+	}
+	if cfg.ProviderRank("kimi") != 2 || cfg.ProviderRank("zai") != -1 {
+		t.Errorf("ProviderRank: kimi=%d zai=%d", cfg.ProviderRank("kimi"), cfg.ProviderRank("zai"))
+	}
+}
+
+func TestNextFallback(t *testing.T) {
+	cfg := &Config{
+		Providers:         []*Provider{{Name: "zai"}, {Name: "minimax"}, {Name: "ollama"}},
+		FallbackProviders: []string{"zai", "missing", "minimax", "native"},
+	}
+
+	tests := []struct {
+		name  string
+		tried []string
+		want  string
+	}{
+		{"zai", nil, "minimax"},
+		{"minimax", []string{"zai"}, "native"},
+		{"native", []string{"zai", "minimax"}, ""},
+		{"ollama", nil, "zai"},
+		{"ollama", []string{"zai"}, "minimax"},
+	}
+	for _, tc := range tests {
+		tried := map[string]bool{}
+		for _, n := range tc.tried {
+			tried[n] = true
+		}
+		if got := cfg.NextFallback(tc.name, tried); got != tc.want {
+			t.Errorf("NextFallback(%s, %v) = %q, want %q", tc.name, tc.tried, got, tc.want)
+		}
+	}
+
+	cfg.Watchdog = "sometimes"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate accepted an invalid watchdog")
+	}
+}
+
+// TestClone covers Clone making a deep copy that keeps resolved API keys.
+func TestClone(t *testing.T) {
+	c := NewDefaultConfig()
+	p := &Provider{Name: "zai", Type: ProviderTypeBuiltin, ModelMappings: map[string]string{"haiku": "glm-4.5-air"}}
+	p.SetResolvedAPIKey("sk-zai")
+	c.Providers = []*Provider{p}
+
+	clone := c.Clone()
+	p.ModelMappings["haiku"] = "changed"
+	got := clone.GetProvider("zai")
+	if got == nil || got == p {
+		t.Fatal("clone should have its own copy of the provider")
+	}
+	if got.ModelMappings["haiku"] != "glm-4.5-air" {
+		t.Errorf("clone mappings = %v, want the original", got.ModelMappings)
+	}
+	if got.GetAPIKey() != "sk-zai" {
+		t.Errorf("clone key = %q, want the resolved key kept", got.GetAPIKey())
+	}
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Target CLIs: the coding agent launched with a provider
+const (
+	TargetClaude   = "claude"
+	TargetCodex    = "codex"
+	TargetOpenCode = "opencode"
+	TargetAider    = "aider"
+	TargetCustom   = "custom"
+)
+
+// Targets lists the target CLIs, for validation and completions.
+var Targets = []string{TargetClaude, TargetCodex, TargetOpenCode, TargetAider, TargetCustom}
+
+func validateTarget(v string) error {
+	if v == "" || slices.Contains(Targets, v) {
+		return nil
+	}
+	return fmt.Errorf("valid: %s", strings.Join(Targets, ", "))
+}
+
+// TargetName returns the CLI launched with p, claude unless Target says
+// otherwise.
+func (p *Provider) TargetName() string {
+	if p.Target == "" {
+		return TargetClaude
+	}
+	return p.Target
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Theme presets
+const (
+	ThemeAuto         = "auto"
+	ThemeDark         = "dark"
+	ThemeLight        = "light"
+	ThemeHighContrast = "high-contrast"
+	ThemeMono         = "mono"
+)
+
+// ThemePresets lists the valid Theme.Preset values.
+var ThemePresets = []string{ThemeAuto, ThemeDark, ThemeLight, ThemeHighContrast, ThemeMono}
+
+// Theme sets the TUI colours. Preset picks a built-in palette; auto (or
+// empty) follows the terminal's light or dark background. Primary, Success
+// and Error replace single colours of it. Colours are hex ("#7C3AED") or
+// ANSI 256 numbers ("135").
+type Theme struct {
+	Preset  string `yaml:"preset,omitempty" json:"preset,omitempty" toml:"preset,omitempty" mapstructure:"preset"`
+	Primary string `yaml:"primary,omitempty" json:"primary,omitempty" toml:"primary,omitempty" mapstructure:"primary"`
+	Success string `yaml:"success,omitempty" json:"success,omitempty" toml:"success,omitempty" mapstructure:"success"`
+	Error   string `yaml:"error,omitempty" json:"error,omitempty" toml:"error,omitempty" mapstructure:"error"`
+}
+
+// hexColor matches #RGB and #RRGGBB colours.
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// validate checks the preset name and colour values.
+func (t *Theme) validate() error {
+	if t.Preset != "" && !slices.Contains(ThemePresets, t.Preset) {
+		return fmt.Errorf("unknown preset %q (valid: %s)", t.Preset, strings.Join(ThemePresets, ", "))
+	}
+	for _, c := range []struct{ name, value string }{
+		{"primary", t.Primary},
+		{"success", t.Success},
+		{"error", t.Error},
+	} {
+		if c.value != "" && !validColor(c.value) {
+			return fmt.Errorf("%s: invalid colour %q (use #RRGGBB or 0-255)", c.name, c.value)
+		}
+	}
+	return nil
+}
+
+// validColor reports whether s is a hex colour or an ANSI 256 colour number.
+func validColor(s string) bool {
+	if hexColor.MatchString(s) {
+		return true
+	}
+	n, err := strconv.Atoi(s)
+	return err == nil && n >= 0 && n <= 255
+}
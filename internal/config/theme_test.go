@@ -0,0 +1,28 @@
+package config
+
+import "testing"
+
+func TestThemeValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		theme   Theme
+		wantErr bool
+	}{
+		{"empty", Theme{}, false},
+		{"preset", Theme{Preset: ThemeHighContrast}, false},
+		{"unknown preset", Theme{Preset: "solarized"}, true},
+		{"hex colours", Theme{Primary: "#0af", Success: "#10B981"}, false},
+		{"ansi colour", Theme{Error: "196"}, false},
+		{"ansi out of range", Theme{Error: "256"}, true},
+		{"colour name", Theme{Primary: "violet"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewDefaultConfig()
+			c.Theme = &tt.theme
+			if err := c.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// baseConfigVersion is the schema version assumed for documents that do not
+// declare one; every config written before versioned migrations was 1.0.
+const baseConfigVersion = "1.0"
+
+// schemaMigration upgrades a raw config document from one schema version to
+// the next. Migrations work on the decoded document rather than Config so
+// they can read fields that no longer exist in the current schema.
+type schemaMigration struct {
+	from, to string
+	apply    func(doc map[string]any) error
+}
+
+// schemaMigrations is the upgrade pipeline, applied in order. Each entry's
+// from must equal the previous entry's to, and the last to is ConfigVersion.
+var schemaMigrations = []schemaMigration{
+	{from: "1.0", to: "1.1", apply: migrateLegacyAPIKeys},
+	{from: "1.1", to: "2.0", apply: migrateModelFields},
+}
+
+// migrateLegacyAPIKeys drops plaintext api_key values left behind by the bash
+// version import once the key has been moved to secure storage (api_key_ref).
+func migrateLegacyAPIKeys(doc map[string]any) error {
+	for _, p := range docProviders(doc) {
+		if ref, _ := p["api_key_ref"].(string); ref != "" {
+			delete(p, "api_key")
+		}
+	}
+	return nil
+}
+
+// migrateModelFields merges default_model into model. Before 2.0 builtin
+// providers carried the registry default in default_model and the user's
+// choice in model; model now holds whichever applies.
+func migrateModelFields(doc map[string]any) error {
+	for _, p := range docProviders(doc) {
+		def, ok := p["default_model"]
+		if !ok {
+			continue
+		}
+		if model, _ := p["model"].(string); model == "" {
+			p["model"] = def
+		}
+		delete(p, "default_model")
+	}
+	return nil
+}
+
+// docProviders returns the provider entries of a raw config document.
+func docProviders(doc map[string]any) []map[string]any {
+	var providers []map[string]any
+	switch list := doc["providers"].(type) {
+	case []any:
+		for _, item := range list {
+			if p, ok := item.(map[string]any); ok {
+				providers = append(providers, p)
+			}
+		}
+	case []map[string]any:
+		providers = list
+	}
+	return providers
+}
+
+// docVersion returns the schema version declared by a raw config document.
+// An unquoted YAML/TOML/JSON number (version: 1.0) is accepted.
+func docVersion(doc map[string]any) string {
+	switch v := doc["version"].(type) {
+	case string:
+		if v != "" {
+			return v
+		}
+	case float64:
+		return strconv.FormatFloat(v, 'f', 1, 64)
+	case int:
+		return strconv.Itoa(v) + ".0"
+	case int64:
+		return strconv.FormatInt(v, 10) + ".0"
+	}
+	return baseConfigVersion
+}
+
+// upgradeDocument runs the migrations needed to bring doc from version from to
+// ConfigVersion. A version this build does not know (e.g. written by a newer
+// skint) is an error, since saving over it could silently drop settings.
+func upgradeDocument(doc map[string]any, from string) error {
+	version := from
+	for version != ConfigVersion {
+		step := findMigration(version)
+		if step == nil {
+			return fmt.Errorf("unsupported config version %s (this skint supports up to %s)", version, ConfigVersion)
+		}
+		if err := step.apply(doc); err != nil {
+			return fmt.Errorf("failed to migrate config from %s to %s: %w", step.from, step.to, err)
+		}
+		version = step.to
+		doc["version"] = version
+	}
+	return nil
+}
+
+// findMigration returns the migration that upgrades from version, or nil.
+func findMigration(version string) *schemaMigration {
+	for i := range schemaMigrations {
+		if schemaMigrations[i].from == version {
+			return &schemaMigrations[i]
+		}
+	}
+	return nil
+}
+
+// upgradeData returns data upgraded to ConfigVersion, re-encoded in the same
+// format, along with the version it was originally written as. Current
+// documents are returned unchanged.
+func upgradeData(format string, data []byte) ([]byte, string, error) {
+	var doc map[string]any
+	if err := decodeConfig(format, data, &doc); err != nil {
+		return nil, "", err
+	}
+	if doc == nil {
+		return data, ConfigVersion, nil
+	}
+
+	from := docVersion(doc)
+	if from == ConfigVersion {
+		return data, from, nil
+	}
+	if err := upgradeDocument(doc, from); err != nil {
+		return nil, from, err
+	}
+
+	upgraded, err := encodeConfig(format, doc)
+	if err != nil {
+		return nil, from, fmt.Errorf("failed to re-encode migrated config: %w", err)
+	}
+	return upgraded, from, nil
+}
+
+// FileVersion returns the schema version the user config file was written
+// as, before any in-memory migration. It is ConfigVersion once Save has
+// written the upgraded config, and "" if no config file was loaded.
+func (m *Manager) FileVersion() string {
+	return m.fileVersion
+}
+
+// NeedsUpgrade returns true if the user config file was written with an older
+// schema version and will be upgraded on the next Save.
+func (m *Manager) NeedsUpgrade() bool {
+	return m.fileVersion != "" && m.fileVersion != ConfigVersion
+}
+
+// BackupPath returns the path Save backs the pre-upgrade config up to.
+func (m *Manager) BackupPath() string {
+	return fmt.Sprintf("%s.v%s.bak", m.configFile, m.fileVersion)
+}
+
+// backupBeforeUpgrade copies the on-disk config to BackupPath before it is
+// first overwritten with a newer schema version. An existing backup is kept,
+// as it holds the oldest original.
+func (m *Manager) backupBeforeUpgrade() error {
+	if !m.NeedsUpgrade() {
+		return nil
+	}
+
+	backup := m.BackupPath()
+	if _, err := os.Lstat(backup); err == nil {
+		return nil
+	}
+
+	data, err := os.ReadFile(m.configFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read config for backup: %w", err)
+	}
+	if err := os.WriteFile(backup, data, 0600); err != nil {
+		return fmt.Errorf("failed to back up config: %w", err)
+	}
+	return nil
+}
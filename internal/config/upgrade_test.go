@@ -0,0 +1,209 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUpgradeLegacyConfig(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	original := `version: "1.0"
+output_format: "human"
+providers:
+  - name: zai
+    type: builtin
+    base_url: "https://api.z.ai/api/anthropic"
+    api_key: "sk-plaintext"
+    api_key_ref: "keyring:zai"
+`
+	if err := os.WriteFile(cfgPath, []byte(original), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	m, err := NewManagerWithPath(cfgPath)
+	if err != nil {
+		t.Fatalf("NewManagerWithPath: %v", err)
+	}
+	if err := m.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if got := m.Get().Version; got != ConfigVersion {
+		t.Errorf("Version: got %q, want %q", got, ConfigVersion)
+	}
+	if got := m.Get().GetProvider("zai").APIKey; got != "" {
+		t.Errorf("APIKey: got %q, want it cleared by migration", got)
+	}
+	if got := m.FileVersion(); got != "1.0" {
+		t.Errorf("FileVersion: got %q, want %q", got, "1.0")
+	}
+	if !m.NeedsUpgrade() {
+		t.Fatal("NeedsUpgrade: expected true for a 1.0 config")
+	}
+
+	if err := m.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if m.NeedsUpgrade() {
+		t.Error("NeedsUpgrade: expected false after Save")
+	}
+
+	backup, err := os.ReadFile(cfgPath + ".v1.0.bak")
+	if err != nil {
+		t.Fatalf("reading backup: %v", err)
+	}
+	if string(backup) != original {
+		t.Errorf("backup content differs from original:\n%s", backup)
+	}
+
+	saved, err := os.ReadFile(cfgPath)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if !strings.Contains(string(saved), `version: "`+ConfigVersion+`"`) {
+		t.Errorf("saved config does not declare version %s:\n%s", ConfigVersion, saved)
+	}
+	if strings.Contains(string(saved), "sk-plaintext") {
+		t.Error("saved config still contains the legacy plaintext key")
+	}
+}
+
+func TestUpgradeDocumentVersions(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		content string
+		from    string
+		wantErr bool
+	}{
+		{name: "current version is untouched", format: FileFormatYAML, content: "version: \"" + ConfigVersion + "\"\n", from: ConfigVersion},
+		{name: "missing version is treated as 1.0", format: FileFormatYAML, content: "output_format: json\n", from: "1.0"},
+		{name: "unquoted YAML number", format: FileFormatYAML, content: "version: 1.0\n", from: "1.0"},
+		{name: "unquoted TOML number", format: FileFormatTOML, content: "version = 1.0\n", from: "1.0"},
+		{name: "TOML providers", format: FileFormatTOML, content: "version = \"1.0\"\n[[providers]]\nname = \"x\"\ntype = \"local\"\napi_key = \"a\"\napi_key_ref = \"keyring:x\"\n", from: "1.0"},
+		{name: "JSON string", format: FileFormatJSON, content: `{"version": "1.0"}`, from: "1.0"},
+		{name: "newer version is rejected", format: FileFormatYAML, content: "version: \"99.0\"\n", from: "99.0", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			data, from, err := upgradeData(tc.format, []byte(tc.content))
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("upgradeData: %v", err)
+			}
+			if from != tc.from {
+				t.Errorf("from: got %q, want %q", from, tc.from)
+			}
+
+			var cfg Config
+			if err := decodeConfig(tc.format, data, &cfg); err != nil {
+				t.Fatalf("decoding upgraded data: %v", err)
+			}
+			if cfg.Version != ConfigVersion {
+				t.Errorf("upgraded Version: got %q, want %q", cfg.Version, ConfigVersion)
+			}
+		})
+	}
+}
+
+func TestSchemaMigrationsChain(t *testing.T) {
+	version := baseConfigVersion
+	for _, step := range schemaMigrations {
+		if step.from != version {
+			t.Fatalf("migration %s -> %s does not follow %s", step.from, step.to, version)
+		}
+		version = step.to
+	}
+	if version != ConfigVersion {
+		t.Errorf("migrations end at %s, want ConfigVersion %s", version, ConfigVersion)
+	}
+}
+
+func TestMigrateModelFields(t *testing.T) {
+	content := `version: "1.1"
+providers:
+  - name: zai
+    type: builtin
+    base_url: "https://api.z.ai/api/anthropic"
+    default_model: glm-5
+  - name: zai-picked
+    type: builtin
+    base_url: "https://api.z.ai/api/anthropic"
+    default_model: glm-5
+    model: glm-4.7
+  - name: or
+    type: openrouter
+    base_url: "https://openrouter.ai/api"
+    model: anthropic/claude-sonnet-4
+`
+	data, from, err := upgradeData(FileFormatYAML, []byte(content))
+	if err != nil {
+		t.Fatalf("upgradeData: %v", err)
+	}
+	if from != "1.1" {
+		t.Errorf("from: got %q, want %q", from, "1.1")
+	}
+	if strings.Contains(string(data), "default_model") {
+		t.Errorf("default_model survived migration:\n%s", data)
+	}
+
+	var cfg Config
+	if err := decodeConfig(FileFormatYAML, data, &cfg); err != nil {
+		t.Fatalf("decoding upgraded data: %v", err)
+	}
+	want := map[string]string{"zai": "glm-5", "zai-picked": "glm-4.7", "or": "anthropic/claude-sonnet-4"}
+	for name, model := range want {
+		if got := cfg.GetProvider(name).Model; got != model {
+			t.Errorf("%s Model: got %q, want %q", name, got, model)
+		}
+	}
+}
+
+func TestLoadFoldsDefaultModel(t *testing.T) {
+	// A current-version file with a hand-written default_model still loads,
+	// and the field is not written back.
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	content := `version: "` + ConfigVersion + `"
+providers:
+  - name: zai
+    type: builtin
+    base_url: "https://api.z.ai/api/anthropic"
+    default_model: glm-5
+`
+	if err := os.WriteFile(cfgPath, []byte(content), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	m, err := NewManagerWithPath(cfgPath)
+	if err != nil {
+		t.Fatalf("NewManagerWithPath: %v", err)
+	}
+	if err := m.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	p := m.Get().GetProvider("zai")
+	if p.Model != "glm-5" || p.DefaultModel != "" {
+		t.Errorf("got Model %q, DefaultModel %q; want glm-5 folded into Model", p.Model, p.DefaultModel)
+	}
+
+	if err := m.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	saved, err := os.ReadFile(cfgPath)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if strings.Contains(string(saved), "default_model") {
+		t.Errorf("saved config still contains default_model:\n%s", saved)
+	}
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// UsageFile holds provider usage in the data directory. It is kept out of
+// the config so launching a provider never rewrites the config file (or
+// creates spurious changes for skint sync).
+const UsageFile = "usage.json"
+
+// Usage maps provider names to when they were last launched or exec'd.
+type Usage map[string]time.Time
+
+// LoadUsage reads provider usage. A missing file is an empty Usage.
+func LoadUsage() (Usage, error) {
+	path, err := usagePath()
+	if err != nil {
+		return Usage{}, err
+	}
+	return loadUsage(path)
+}
+
+// RecordUsage marks provider as used now.
+func RecordUsage(provider string) error {
+	path, err := usagePath()
+	if err != nil {
+		return err
+	}
+	return recordUsage(path, provider, time.Now())
+}
+
+// LastUsed describes when provider was last used relative to now (e.g.
+// "3h ago"), or "" if it never has been.
+func (u Usage) LastUsed(provider string, now time.Time) string {
+	t, ok := u[provider]
+	if !ok {
+		return ""
+	}
+	return Ago(t, now)
+}
+
+// Ago describes t relative to now, e.g. "just now", "3h ago" or, for a
+// month or more, the date.
+func Ago(t, now time.Time) string {
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d/time.Minute))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d/time.Hour))
+	case d < 30*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(d/(24*time.Hour)))
+	default:
+		return t.Local().Format("2006-01-02")
+	}
+}
+
+func usagePath() (string, error) {
+	dir, err := GetDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, UsageFile), nil
+}
+
+func loadUsage(path string) (Usage, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Usage{}, nil
+	}
+	if err != nil {
+		return Usage{}, fmt.Errorf("failed to read usage: %w", err)
+	}
+
+	u := Usage{}
+	if err := json.Unmarshal(data, &u); err != nil {
+		return Usage{}, fmt.Errorf("failed to parse usage: %w", err)
+	}
+	return u, nil
+}
+
+// recordUsage sets provider's last use to at and rewrites the file
+// atomically. A corrupt file is replaced rather than blocking the launch.
+func recordUsage(path, provider string, at time.Time) error {
+	u, _ := loadUsage(path)
+	u[provider] = at.UTC().Truncate(time.Second)
+
+	data, err := json.MarshalIndent(u, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".usage-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write usage: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer func() { _ = os.Remove(tmpPath) }() // no-op after a successful rename
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write usage: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write usage: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to write usage: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecordUsage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", UsageFile)
+	at := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	if u, err := loadUsage(path); err != nil || len(u) != 0 {
+		t.Fatalf("missing file: got %v, %v", u, err)
+	}
+
+	if err := recordUsage(path, "zai", at); err != nil {
+		t.Fatalf("recordUsage: %v", err)
+	}
+	if err := recordUsage(path, "ollama", at.Add(time.Hour)); err != nil {
+		t.Fatalf("recordUsage: %v", err)
+	}
+
+	u, err := loadUsage(path)
+	if err != nil {
+		t.Fatalf("loadUsage: %v", err)
+	}
+	if !u["zai"].Equal(at) || !u["ollama"].Equal(at.Add(time.Hour)) {
+		t.Errorf("usage = %v", u)
+	}
+
+	// A corrupt file is replaced rather than failing the launch
+	if err := os.WriteFile(path, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := recordUsage(path, "zai", at); err != nil {
+		t.Fatalf("recordUsage over corrupt file: %v", err)
+	}
+	if u, err := loadUsage(path); err != nil || len(u) != 1 {
+		t.Errorf("after corrupt file: got %v, %v", u, err)
+	}
+}
+
+func TestUsageLastUsed(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	u := Usage{
+		"a": now.Add(-10 * time.Second),
+		"b": now.Add(-5 * time.Minute),
+		"c": now.Add(-3 * time.Hour),
+		"d": now.Add(-2 * 24 * time.Hour),
+	}
+
+	tests := map[string]string{"a": "just now", "b": "5m ago", "c": "3h ago", "d": "2d ago", "never": ""}
+	for name, want := range tests {
+		if got := u.LastUsed(name, now); got != want {
+			t.Errorf("LastUsed(%s) = %q, want %q", name, got, want)
+		}
+	}
+
+	old := now.Add(-90 * 24 * time.Hour)
+	u["e"] = old
+	if got, want := u.LastUsed("e", now), old.Local().Format("2006-01-02"); got != want {
+		t.Errorf("LastUsed(e) = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,85 @@
+// Package detect probes well-known local ports for running inference servers.
+package detect
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// probeTimeout bounds each probe. Local servers answer in milliseconds, and
+// detection runs on TUI start, so this is kept short.
+const probeTimeout = 500 * time.Millisecond
+
+// Server is a local inference server found listening on a known port.
+type Server struct {
+	Provider string // registry provider name (e.g. "ollama")
+	Name     string // human-readable server name
+	BaseURL  string
+}
+
+// candidate is a port to probe and the endpoint that identifies the server.
+type candidate struct {
+	provider string
+	name     string
+	baseURL  string
+	path     string
+}
+
+// candidates lists the ports probed, in display order. llama.cpp's
+// llama-server listens on 8080 by default, but skint's registry uses 8000,
+// so both are checked.
+var candidates = []candidate{
+	{provider: "ollama", name: "Ollama", baseURL: "http://localhost:11434", path: "/api/tags"},
+	{provider: "lmstudio", name: "LM Studio", baseURL: "http://localhost:1234", path: "/v1/models"},
+	{provider: "llamacpp", name: "llama.cpp", baseURL: "http://localhost:8000", path: "/health"},
+	{provider: "llamacpp", name: "llama.cpp", baseURL: "http://localhost:8080", path: "/health"},
+}
+
+// Detect probes all candidate ports concurrently and returns the servers that
+// responded, in candidate order.
+func Detect() []Server {
+	return detect(candidates)
+}
+
+func detect(cands []candidate) []Server {
+	client := &http.Client{
+		Timeout: probeTimeout,
+		CheckRedirect: func(_ *http.Request, _ []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	found := make([]bool, len(cands))
+	var wg sync.WaitGroup
+	for i, c := range cands {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			found[i] = probe(client, c.baseURL+c.path)
+		}()
+	}
+	wg.Wait()
+
+	var servers []Server
+	seen := make(map[string]bool)
+	for i, c := range cands {
+		// Report each provider once, preferring the earlier (registry) port
+		if !found[i] || seen[c.provider] {
+			continue
+		}
+		seen[c.provider] = true
+		servers = append(servers, Server{Provider: c.provider, Name: c.name, BaseURL: c.baseURL})
+	}
+	return servers
+}
+
+// probe reports whether url answers with HTTP 200.
+func probe(client *http.Client, url string) bool {
+	resp, err := client.Get(url)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
@@ -0,0 +1,58 @@
+package detect
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/tags" && r.URL.Path != "/health" {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ok.Close()
+
+	notFound := httptest.NewServer(http.NotFoundHandler())
+	defer notFound.Close()
+
+	// A closed server gives a port with nothing listening.
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closedURL := closed.URL
+	closed.Close()
+
+	servers := detect([]candidate{
+		{provider: "ollama", name: "Ollama", baseURL: ok.URL, path: "/api/tags"},
+		{provider: "lmstudio", name: "LM Studio", baseURL: notFound.URL, path: "/v1/models"},
+		{provider: "llamacpp", name: "llama.cpp", baseURL: closedURL, path: "/health"},
+		{provider: "llamacpp", name: "llama.cpp", baseURL: ok.URL, path: "/health"},
+	})
+
+	if len(servers) != 2 {
+		t.Fatalf("got %d servers, want 2: %+v", len(servers), servers)
+	}
+	if servers[0].Provider != "ollama" || servers[0].BaseURL != ok.URL {
+		t.Errorf("servers[0]: got %+v", servers[0])
+	}
+	if servers[1].Provider != "llamacpp" || servers[1].BaseURL != ok.URL {
+		t.Errorf("servers[1]: got %+v, want llamacpp on the fallback port", servers[1])
+	}
+}
+
+func TestDetectReportsProviderOnce(t *testing.T) {
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ok.Close()
+
+	servers := detect([]candidate{
+		{provider: "llamacpp", baseURL: ok.URL + "/first", path: "/health"},
+		{provider: "llamacpp", baseURL: ok.URL + "/second", path: "/health"},
+	})
+	if len(servers) != 1 || servers[0].BaseURL != ok.URL+"/first" {
+		t.Errorf("got %+v, want only the first llamacpp match", servers)
+	}
+}
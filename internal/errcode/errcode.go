@@ -0,0 +1,98 @@
+// Package errcode gives skint's errors stable codes for scripts that run it:
+// each code has its own exit status, and with --output json errors are
+// printed as objects carrying the code.
+package errcode
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Code identifies a kind of failure. Codes are part of skint's interface:
+// don't rename them or change their exit codes.
+type Code string
+
+const (
+	// General is any error without a more specific code
+	General Code = "E_GENERAL"
+	// Usage is a bad command line: an unknown command or flag, or the wrong
+	// number of arguments
+	Usage Code = "E_USAGE"
+	// ConfigInvalid is a config file that can't be read, parsed or validated
+	ConfigInvalid Code = "E_CONFIG_INVALID"
+	// ProviderNotFound is a provider that is neither configured nor built in
+	ProviderNotFound Code = "E_PROVIDER_NOT_FOUND"
+	// KeyMissing is a provider whose API key isn't set or can't be loaded
+	KeyMissing Code = "E_KEY_MISSING"
+	// ProviderUnreachable is a provider that didn't answer skint test
+	ProviderUnreachable Code = "E_PROVIDER_UNREACHABLE"
+	// ClaudeNotFound is a missing claude binary
+	ClaudeNotFound Code = "E_CLAUDE_NOT_FOUND"
+	// InputRequired is a prompt that can't be shown, with --no-input or
+	// without a terminal
+	InputRequired Code = "E_INPUT_REQUIRED"
+)
+
+// exitCodes are the exit statuses for each code. 1 stays the catch-all, and
+// codes from 126 up are left to the shell and to commands skint runs.
+var exitCodes = map[Code]int{
+	General:             1,
+	Usage:               2,
+	ConfigInvalid:       3,
+	ProviderNotFound:    4,
+	KeyMissing:          5,
+	ProviderUnreachable: 6,
+	ClaudeNotFound:      7,
+	InputRequired:       8,
+}
+
+// Codes returns every code, in exit code order.
+func Codes() []Code {
+	return []Code{General, Usage, ConfigInvalid, ProviderNotFound, KeyMissing, ProviderUnreachable, ClaudeNotFound, InputRequired}
+}
+
+// ExitCode returns the exit status for c, or 1 for an unknown code.
+func (c Code) ExitCode() int {
+	if n, ok := exitCodes[c]; ok {
+		return n
+	}
+	return 1
+}
+
+// Error is an error with a code. Wrapping it with fmt.Errorf's %w keeps the
+// code.
+type Error struct {
+	Code Code
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New returns an error with code and a message formatted as by fmt.Errorf,
+// so %w works.
+func New(code Code, format string, a ...any) error {
+	return &Error{Code: code, Err: fmt.Errorf(format, a...)}
+}
+
+// Wrap gives err a code, or returns nil if err is nil.
+func Wrap(code Code, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Code: code, Err: err}
+}
+
+// Of returns the code of the outermost Error in err's chain, or General.
+func Of(err error) Code {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return General
+}
@@ -0,0 +1,49 @@
+package errcode
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestOf(t *testing.T) {
+	err := New(KeyMissing, "API key is required for %s", "zai")
+	wrapped := fmt.Errorf("provider validation failed: %w", err)
+	if got := Of(wrapped); got != KeyMissing {
+		t.Errorf("Of(wrapped) = %s, want %s", got, KeyMissing)
+	}
+	if wrapped.Error() != "provider validation failed: API key is required for zai" {
+		t.Errorf("message = %q", wrapped.Error())
+	}
+	if Of(errors.New("plain")) != General || Of(nil) != General {
+		t.Error("errors without a code should be General")
+	}
+	if Wrap(Usage, nil) != nil {
+		t.Error("Wrap(nil) should be nil")
+	}
+
+	// The outermost code wins
+	if got := Of(Wrap(ConfigInvalid, wrapped)); got != ConfigInvalid {
+		t.Errorf("Of = %s, want %s", got, ConfigInvalid)
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	seen := map[int]Code{}
+	for _, c := range Codes() {
+		n := c.ExitCode()
+		if other, dup := seen[n]; dup {
+			t.Errorf("%s and %s share exit code %d", c, other, n)
+		}
+		seen[n] = c
+		if n < 1 || n >= 126 {
+			t.Errorf("%s has exit code %d, outside 1-125", c, n)
+		}
+	}
+	if len(seen) != len(exitCodes) {
+		t.Errorf("Codes() lists %d codes, exitCodes has %d", len(seen), len(exitCodes))
+	}
+	if Code("E_NOPE").ExitCode() != 1 {
+		t.Error("unknown codes should exit 1")
+	}
+}
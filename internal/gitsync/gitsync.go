@@ -0,0 +1,198 @@
+// Package gitsync keeps a config file in sync with a git remote using a bare
+// local repository, so no working tree is ever checked out.
+package gitsync
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// File is the name the config is stored under in the repository.
+const File = "config.yaml"
+
+// syncedRef marks the commit this machine last pushed or pulled. Comparing
+// it with the remote branch tells whether another machine has pushed since.
+const syncedRef = "refs/skint/synced"
+
+// Repo is a local bare mirror of the sync remote.
+type Repo struct {
+	dir    string
+	branch string
+	env    []string
+}
+
+// Open returns the sync repository in dir, creating it if needed, with
+// origin pointing at remote.
+func Open(dir, remote, branch string) (*Repo, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("git not found in PATH")
+	}
+	r := &Repo{dir: dir, branch: branch, env: os.Environ()}
+	defer r.setIdentity()
+
+	if _, err := os.Stat(filepath.Join(dir, "HEAD")); os.IsNotExist(err) {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return nil, fmt.Errorf("failed to create sync directory: %w", err)
+		}
+		if _, err := r.git(nil, "init", "--quiet", "--bare"); err != nil {
+			return nil, err
+		}
+		if _, err := r.git(nil, "remote", "add", "origin", remote); err != nil {
+			return nil, err
+		}
+		return r, nil
+	}
+
+	current, err := r.git(nil, "remote", "get-url", "origin")
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(string(current)) != remote {
+		if _, err := r.git(nil, "remote", "set-url", "origin", remote); err != nil {
+			return nil, err
+		}
+		// Sync state is meaningless against a different remote
+		_, _ = r.git(nil, "update-ref", "-d", syncedRef)
+	}
+	return r, nil
+}
+
+// Fetch updates the local copy of the remote branch.
+func (r *Repo) Fetch() error {
+	_, err := r.git(nil, "fetch", "--quiet", "--prune", "origin")
+	return err
+}
+
+// Remote returns the config on the remote branch as of the last Fetch. ok is
+// false if the branch or file does not exist yet.
+func (r *Repo) Remote() (data []byte, ok bool, err error) {
+	return r.show(r.remoteRef())
+}
+
+// Synced returns the config as of the last push or pull from this machine.
+// ok is false if this machine has never synced.
+func (r *Repo) Synced() (data []byte, ok bool, err error) {
+	return r.show(syncedRef)
+}
+
+// RemoteChanged reports whether the remote branch has moved since this
+// machine last synced.
+func (r *Repo) RemoteChanged() (bool, error) {
+	remote, ok := r.resolve(r.remoteRef())
+	if !ok {
+		return false, nil
+	}
+	synced, ok := r.resolve(syncedRef)
+	return !ok || synced != remote, nil
+}
+
+// Push commits data on top of the remote branch and pushes it. It returns
+// false without pushing when the remote already has identical content.
+func (r *Repo) Push(data []byte, message string) (bool, error) {
+	parent, hasParent := r.resolve(r.remoteRef())
+	if hasParent {
+		if current, ok, err := r.Remote(); err == nil && ok && bytes.Equal(current, data) {
+			return false, r.MarkSynced()
+		}
+	}
+
+	blob, err := r.git(data, "hash-object", "-w", "--stdin")
+	if err != nil {
+		return false, err
+	}
+	entry := fmt.Sprintf("100644 blob %s\t%s\n", strings.TrimSpace(string(blob)), File)
+	tree, err := r.git([]byte(entry), "mktree")
+	if err != nil {
+		return false, err
+	}
+
+	args := []string{"commit-tree", strings.TrimSpace(string(tree)), "-m", message}
+	if hasParent {
+		args = append(args, "-p", parent)
+	}
+	out, err := r.git(nil, args...)
+	if err != nil {
+		return false, err
+	}
+	commit := strings.TrimSpace(string(out))
+
+	if _, err := r.git(nil, "push", "--quiet", "origin", commit+":refs/heads/"+r.branch); err != nil {
+		return false, err
+	}
+	if _, err := r.git(nil, "update-ref", r.remoteRef(), commit); err != nil {
+		return false, err
+	}
+	return true, r.MarkSynced()
+}
+
+// MarkSynced records the fetched remote branch as this machine's sync point.
+func (r *Repo) MarkSynced() error {
+	commit, ok := r.resolve(r.remoteRef())
+	if !ok {
+		return nil
+	}
+	_, err := r.git(nil, "update-ref", syncedRef, commit)
+	return err
+}
+
+func (r *Repo) remoteRef() string {
+	return "refs/remotes/origin/" + r.branch
+}
+
+// resolve returns the commit a ref points to, if it exists.
+func (r *Repo) resolve(ref string) (string, bool) {
+	out, err := r.git(nil, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(out)), true
+}
+
+// show returns File as of ref.
+func (r *Repo) show(ref string) ([]byte, bool, error) {
+	if _, ok := r.resolve(ref); !ok {
+		return nil, false, nil
+	}
+	out, err := r.git(nil, "show", ref+":"+File)
+	if err != nil {
+		return nil, false, nil
+	}
+	return out, true, nil
+}
+
+// setIdentity attributes commits to skint when the user has no git identity
+// configured, as commit-tree would otherwise fail.
+func (r *Repo) setIdentity() {
+	if _, err := r.git(nil, "var", "GIT_AUTHOR_IDENT"); err == nil {
+		return
+	}
+	r.env = append(r.env,
+		"GIT_AUTHOR_NAME=skint", "GIT_AUTHOR_EMAIL=skint@localhost",
+		"GIT_COMMITTER_NAME=skint", "GIT_COMMITTER_EMAIL=skint@localhost",
+	)
+}
+
+// git runs a git command in the repository, returning stdout.
+func (r *Repo) git(stdin []byte, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", r.dir}, args...)...)
+	cmd.Env = r.env
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return nil, fmt.Errorf("git %s: %s", args[0], msg)
+	}
+	return stdout.Bytes(), nil
+}
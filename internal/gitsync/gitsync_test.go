@@ -0,0 +1,111 @@
+package gitsync
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// newRemote creates an empty bare repository to act as the sync remote.
+func newRemote(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := filepath.Join(t.TempDir(), "remote.git")
+	if out, err := exec.Command("git", "init", "--quiet", "--bare", dir).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	return dir
+}
+
+func openRepo(t *testing.T, remote string) *Repo {
+	t.Helper()
+	r, err := Open(filepath.Join(t.TempDir(), "sync"), remote, "main")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if err := r.Fetch(); err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	return r
+}
+
+func TestPushAndPull(t *testing.T) {
+	remote := newRemote(t)
+	a := openRepo(t, remote)
+	b := openRepo(t, remote)
+
+	if _, ok, err := a.Remote(); err != nil || ok {
+		t.Fatalf("Remote on empty remote: ok=%v err=%v", ok, err)
+	}
+	if changed, _ := a.RemoteChanged(); changed {
+		t.Error("empty remote reported as changed")
+	}
+
+	pushed, err := a.Push([]byte("one\n"), "first")
+	if err != nil || !pushed {
+		t.Fatalf("Push: pushed=%v err=%v", pushed, err)
+	}
+	if changed, _ := a.RemoteChanged(); changed {
+		t.Error("remote changed right after our own push")
+	}
+
+	// b has never synced, so the pushed config is a change for it
+	if err := b.Fetch(); err != nil {
+		t.Fatal(err)
+	}
+	if changed, _ := b.RemoteChanged(); !changed {
+		t.Error("b should see the remote as changed")
+	}
+	data, ok, err := b.Remote()
+	if err != nil || !ok || string(data) != "one\n" {
+		t.Fatalf("b.Remote = %q, %v, %v", data, ok, err)
+	}
+	if err := b.MarkSynced(); err != nil {
+		t.Fatal(err)
+	}
+	if synced, ok, _ := b.Synced(); !ok || string(synced) != "one\n" {
+		t.Errorf("b.Synced = %q, %v", synced, ok)
+	}
+	if changed, _ := b.RemoteChanged(); changed {
+		t.Error("b still sees a change after MarkSynced")
+	}
+
+	// Pushing identical content is a no-op
+	if pushed, err := b.Push([]byte("one\n"), "same"); err != nil || pushed {
+		t.Errorf("identical Push: pushed=%v err=%v", pushed, err)
+	}
+
+	// b pushes; a now sees the change
+	if _, err := b.Push([]byte("two\n"), "second"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Fetch(); err != nil {
+		t.Fatal(err)
+	}
+	if changed, _ := a.RemoteChanged(); !changed {
+		t.Error("a should see b's push")
+	}
+}
+
+func TestOpenResetsSyncStateOnNewRemote(t *testing.T) {
+	first, second := newRemote(t), newRemote(t)
+	dir := filepath.Join(t.TempDir(), "sync")
+
+	r, err := Open(dir, first, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Push([]byte("x\n"), "x"); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err = Open(dir, second, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := r.Synced(); ok {
+		t.Error("sync state kept after switching remote")
+	}
+}
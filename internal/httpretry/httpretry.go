@@ -0,0 +1,110 @@
+// Package httpretry sends HTTP requests, retrying failures that are likely
+// to pass: a connection reset, or a 429 or 503 from a server that is busy or
+// still loading a model. Other errors and statuses are returned at once, so
+// an unreachable provider is still reported quickly.
+package httpretry
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// maxWait caps how long a Retry-After header can make Do wait.
+const maxWait = 10 * time.Second
+
+// Policy is how often Do retries and how long it waits first.
+type Policy struct {
+	// Retries is how many times a request is retried after the first attempt
+	Retries int
+	// Backoff is the wait before the first retry, doubled for each one after
+	Backoff time.Duration
+}
+
+// policy is the Policy Do uses, set by Init. Until then nothing is retried.
+var policy Policy
+
+// Init makes Do use p, e.g. the config's retries and retry_backoff.
+func Init(p Policy) {
+	policy = p
+}
+
+// Do sends req with client, retrying transient failures as Init set.
+func Do(client *http.Client, req *http.Request) (*http.Response, error) {
+	return policy.Do(client, req)
+}
+
+// Do sends req with client, retrying transient failures up to p.Retries
+// times. It gives up early when req's context is done or its deadline
+// would pass while waiting, returning the last response or error. Requests
+// with a body are only retried if it can be read again (GetBody).
+func (p Policy) Do(client *http.Client, req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	wait := p.Backoff
+	for attempt := 0; ; attempt++ {
+		resp, err := client.Do(req)
+		if attempt >= p.Retries || !transient(resp, err) || (req.Body != nil && req.GetBody == nil) {
+			return resp, err
+		}
+
+		delay := min(max(wait, retryAfter(resp)), maxWait)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return resp, err
+		}
+		slog.Debug("retrying request", "url", req.URL.Redacted(), "attempt", attempt+1, "status", status(resp), "err", err, "wait", delay)
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		wait *= 2
+
+		req = req.Clone(ctx)
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// transient reports whether a request that got resp or err may succeed if
+// sent again.
+func transient(resp *http.Response, err error) bool {
+	if err != nil {
+		return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable
+}
+
+// retryAfter returns how long resp's Retry-After header, in seconds, asks
+// to wait, or 0.
+func retryAfter(resp *http.Response) time.Duration {
+	if resp == nil {
+		return 0
+	}
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// status returns resp's status code, or 0 without a response.
+func status(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+	return resp.StatusCode
+}
@@ -0,0 +1,93 @@
+package httpretry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// server returns a server that answers with statuses in turn, then 200, and
+// counts the requests it got.
+func server(t *testing.T, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1))
+		if n <= len(statuses) {
+			w.WriteHeader(statuses[n-1])
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func get(t *testing.T, ctx context.Context, p Policy, url string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := p.Do(http.DefaultClient, req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	resp.Body.Close()
+	return resp
+}
+
+func TestDoRetries(t *testing.T) {
+	p := Policy{Retries: 2, Backoff: time.Millisecond}
+	tests := []struct {
+		name      string
+		statuses  []int
+		want      int
+		wantCalls int32
+	}{
+		{"success", nil, http.StatusOK, 1},
+		{"loading", []int{http.StatusServiceUnavailable}, http.StatusOK, 2},
+		{"rate limited", []int{http.StatusTooManyRequests, http.StatusServiceUnavailable}, http.StatusOK, 3},
+		{"gives up", []int{503, 503, 503, 503}, http.StatusServiceUnavailable, 3},
+		{"not transient", []int{http.StatusUnauthorized}, http.StatusUnauthorized, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, calls := server(t, tt.statuses...)
+			resp := get(t, context.Background(), p, srv.URL)
+			if resp.StatusCode != tt.want || calls.Load() != tt.wantCalls {
+				t.Errorf("status %d after %d requests, want %d after %d", resp.StatusCode, calls.Load(), tt.want, tt.wantCalls)
+			}
+		})
+	}
+}
+
+// TestDoDeadline checks a wait that would outlast the context isn't started.
+func TestDoDeadline(t *testing.T) {
+	srv, calls := server(t, http.StatusServiceUnavailable)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	resp := get(t, ctx, Policy{Retries: 2, Backoff: time.Minute}, srv.URL)
+	if resp.StatusCode != http.StatusServiceUnavailable || calls.Load() != 1 {
+		t.Errorf("status %d after %d requests, want the first 503", resp.StatusCode, calls.Load())
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Error("Do waited although the deadline would pass first")
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"3"}}}
+	if got := retryAfter(resp); got != 3*time.Second {
+		t.Errorf("retryAfter = %v, want 3s", got)
+	}
+	resp.Header.Set("Retry-After", "Wed, 21 Oct 2015 07:28:00 GMT")
+	if got := retryAfter(resp); got != 0 {
+		t.Errorf("retryAfter(date) = %v, want 0", got)
+	}
+}
@@ -0,0 +1,166 @@
+package launcher
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/errcode"
+	"github.com/sammcj/skint/internal/ui"
+)
+
+// MinClaudeVersion is the oldest Claude Code skint fully supports: 1.0.88
+// added the ANTHROPIC_DEFAULT_{HAIKU,SONNET,OPUS}_MODEL variables skint sets
+// for model tiers. Older versions still launch, but use their own models for
+// the tiers.
+const MinClaudeVersion = "1.0.88"
+
+// claudeInstallHint is how to install or update Claude Code.
+const claudeInstallHint = "curl -fsSL https://claude.ai/install.sh | bash"
+
+// claudeVersionTimeout bounds 'claude --version'.
+const claudeVersionTimeout = 10 * time.Second
+
+// claudeVersionPattern finds the version in 'claude --version' output, e.g.
+// "2.0.14 (Claude Code)", at the start of a line.
+var claudeVersionPattern = regexp.MustCompile(`(?m)^\s*v?(\d+\.\d+\.\d+)`)
+
+// ClaudeInstall is the claude found on PATH. ModTime and Size are of the
+// file it resolves to, so the version is only asked for again after an
+// install or update.
+type ClaudeInstall struct {
+	Path string `json:"path"`
+	// Version is empty if claude --version couldn't be run or read
+	Version string    `json:"version,omitempty"`
+	ModTime time.Time `json:"mod_time"`
+	Size    int64     `json:"size"`
+}
+
+// Supported reports whether the version is at least MinClaudeVersion. An
+// unknown version is given the benefit of the doubt.
+func (c *ClaudeInstall) Supported() bool {
+	return c.Version == "" || compareVersions(c.Version, MinClaudeVersion) >= 0
+}
+
+// UpdateHint returns how to fix an unsupported or missing claude.
+func (c *ClaudeInstall) UpdateHint() string {
+	if c == nil {
+		return "Install Claude Code: " + claudeInstallHint
+	}
+	return "Update Claude Code: claude update (or " + claudeInstallHint + ")"
+}
+
+// DetectClaude finds claude on PATH and its version. The version is cached
+// in claude.json in the cache directory until the install changes, as
+// running claude --version takes a moment.
+func DetectClaude() (*ClaudeInstall, error) {
+	path, err := exec.LookPath("claude")
+	if err != nil {
+		return nil, errcode.New(errcode.ClaudeNotFound, "claude command not found. Please install Claude Code first:\n  %s", claudeInstallHint)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return &ClaudeInstall{Path: path}, nil
+	}
+	found := &ClaudeInstall{Path: path, ModTime: info.ModTime(), Size: info.Size()}
+
+	cacheFile := claudeCacheFile()
+	if cached := readClaudeCache(cacheFile); cached != nil && cached.Path == found.Path &&
+		cached.ModTime.Equal(found.ModTime) && cached.Size == found.Size && cached.Version != "" {
+		return cached, nil
+	}
+
+	found.Version = claudeVersion(path)
+	slog.Debug("detected claude", "path", path, "version", found.Version)
+	if found.Version != "" && cacheFile != "" {
+		if data, err := json.Marshal(found); err == nil {
+			if err := os.MkdirAll(filepath.Dir(cacheFile), 0o700); err == nil {
+				_ = os.WriteFile(cacheFile, data, 0o600)
+			}
+		}
+	}
+	return found, nil
+}
+
+// claudeVersion runs claude --version and returns the version it reports,
+// or "" if it fails.
+func claudeVersion(path string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), claudeVersionTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, path, "--version").Output()
+	if err != nil {
+		slog.Warn("claude --version failed", "path", path, "err", err)
+		return ""
+	}
+	if m := claudeVersionPattern.FindStringSubmatch(string(out)); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+func claudeCacheFile() string {
+	dir, err := config.GetCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "claude.json")
+}
+
+func readClaudeCache(file string) *ClaudeInstall {
+	if file == "" {
+		return nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil
+	}
+	var c ClaudeInstall
+	if json.Unmarshal(data, &c) != nil {
+		return nil
+	}
+	return &c
+}
+
+// compareVersions compares dotted version numbers, returning -1, 0 or 1.
+// Missing or non-numeric parts count as 0.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := range max(len(as), len(bs)) {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
+
+// CheckClaude verifies that Claude CLI is installed, and warns if it is
+// older than MinClaudeVersion.
+func CheckClaude() error {
+	c, err := DetectClaude()
+	if err != nil {
+		return err
+	}
+	if !c.Supported() {
+		slog.Warn("claude is older than supported", "version", c.Version, "min", MinClaudeVersion)
+		ui.Warning("Claude Code %s is older than %s, so model tiers may not apply. %s", c.Version, MinClaudeVersion, c.UpdateHint())
+	}
+	return nil
+}
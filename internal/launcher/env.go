@@ -1,10 +1,21 @@
 package launcher
 
-import "strings"
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
 
-// ConflictingEnvVars is the list of environment variable names that are
-// removed before setting provider-specific values. Both the Launcher and
-// the exec command use this list to avoid stale values leaking through.
+	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/providers"
+)
+
+// ConflictingEnvVars is the default list of environment variable names that
+// are removed before setting provider-specific values. Both the Launcher and
+// the exec command remove them to avoid stale values leaking through; Init
+// adjusts the list from the config.
 var ConflictingEnvVars = []string{
 	"ANTHROPIC_BASE_URL",
 	"ANTHROPIC_AUTH_TOKEN",
@@ -19,6 +30,29 @@ var ConflictingEnvVars = []string{
 	"OPENAI_MODEL",
 }
 
+// conflictingEnvVars is ConflictingEnvVars as adjusted by Init.
+var conflictingEnvVars = ConflictingEnvVars
+
+// Init applies cfg's ClearEnv and KeepEnv to the variables removed before
+// launching.
+func Init(cfg *config.Config) {
+	vars := slices.DeleteFunc(slices.Clone(ConflictingEnvVars), func(name string) bool {
+		return slices.Contains(cfg.KeepEnv, name)
+	})
+	for _, name := range cfg.ClearEnv {
+		if !slices.Contains(vars, name) {
+			vars = append(vars, name)
+		}
+	}
+	conflictingEnvVars = vars
+}
+
+// Conflicting returns the variables removed before launching:
+// ConflictingEnvVars as adjusted by Init.
+func Conflicting() []string {
+	return slices.Clone(conflictingEnvVars)
+}
+
 // FilterEnvVars removes the named variables from an environment slice.
 // Entries without '=' are preserved as-is.
 func FilterEnvVars(env []string, vars ...string) []string {
@@ -42,3 +76,108 @@ func FilterEnvVars(env []string, vars ...string) []string {
 
 	return result
 }
+
+// BuildEnv returns the current environment with conflicting variables removed
+// and the provider's variables applied, followed by any extra variables (e.g.
+// from env presets), which override provider values of the same name.
+func BuildEnv(provider providers.Provider, extra map[string]string) []string {
+	return withVars(os.Environ(), LaunchVars(provider, extra))
+}
+
+// IsolatedEnvVars are the variables IsolatedEnv keeps from the current
+// environment: enough for most programs to find their files, draw in the
+// terminal and, on Windows, start at all.
+var IsolatedEnvVars = []string{
+	"PATH",
+	"HOME",
+	"USER",
+	"LOGNAME",
+	"SHELL",
+	"TERM",
+	"COLORTERM",
+	"LANG",
+	"LC_ALL",
+	"TMPDIR",
+	"TZ",
+	// Windows
+	"SYSTEMROOT",
+	"COMSPEC",
+	"PATHEXT",
+	"USERPROFILE",
+	"APPDATA",
+	"LOCALAPPDATA",
+	"TEMP",
+	"TMP",
+}
+
+// IsolatedEnv is like BuildEnv, but starts from only the IsolatedEnvVars in
+// the current environment, so other keys and tokens aren't passed on.
+func IsolatedEnv(provider providers.Provider, extra map[string]string) []string {
+	var base []string
+	for _, e := range os.Environ() {
+		name, _, _ := strings.Cut(e, "=")
+		if slices.ContainsFunc(IsolatedEnvVars, func(v string) bool { return envNameEqual(v, name) }) {
+			base = append(base, e)
+		}
+	}
+	return withVars(base, LaunchVars(provider, extra))
+}
+
+// withVars returns env with conflicting variables removed and vars added.
+func withVars(env []string, vars map[string]string) []string {
+	names := make([]string, 0, len(vars))
+	for k := range vars {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	// Drop inherited copies of every variable we set: with duplicate entries,
+	// getenv(3) returns the first match, which would be the stale value.
+	env = FilterEnvVars(env, append(Conflicting(), names...)...)
+	for _, k := range names {
+		env = append(env, fmt.Sprintf("%s=%s", k, vars[k]))
+	}
+
+	return env
+}
+
+// envNameEqual reports whether two variable names are the same: ignoring
+// case on Windows, where Path and PATH are one variable.
+func envNameEqual(a, b string) bool {
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+// LaunchVars returns the variables set for provider: its own, then the
+// inherited values of those it preserves, then extra.
+func LaunchVars(provider providers.Provider, extra map[string]string) map[string]string {
+	vars := provider.GetEnvVars()
+	for _, k := range provider.PreserveEnv() {
+		if v, ok := os.LookupEnv(k); ok {
+			vars[k] = v
+		}
+	}
+	for k, v := range extra {
+		vars[k] = v
+	}
+	return vars
+}
+
+// RemovedEnvVars returns the sorted names of the conflicting variables in the
+// current environment that a launch setting vars removes rather than
+// replaces.
+func RemovedEnvVars(vars map[string]string) []string {
+	var removed []string
+	for _, name := range conflictingEnvVars {
+		if _, set := vars[name]; set {
+			continue
+		}
+		if _, ok := os.LookupEnv(name); ok {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+	return removed
+}
@@ -0,0 +1,58 @@
+package launcher
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/sammcj/skint/internal/providers"
+)
+
+// fishQuote quotes s for fish: inside single quotes only \ and ' are special.
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+// FishScript returns a fish wrapper script that launches claude (or the
+// provider's target) with provider the same way Script does. Unlike Script
+// it doesn't load secrets.env, which only the old bash version's scripts
+// read.
+func FishScript(provider providers.Provider, extra map[string]string, args []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `#!/usr/bin/env fish
+# Generated by Skint - Multi-provider launcher for Claude CLI
+
+# Show banner
+if test "$SKINT_NO_BANNER" != 1; and isatty stdout
+    set -l data_home $XDG_DATA_HOME
+    test -n "$data_home"; or set data_home $HOME/.local/share
+    cat $data_home/skint/banner 2>/dev/null; or echo ' ____  _    _       _'
+    echo %s
+    echo
+end
+
+# Set environment variables
+`, fishQuote("    + "+provider.DisplayName()))
+
+	vars, unset, keep := scriptVars(provider, extra)
+	for _, k := range unset {
+		fmt.Fprintf(&b, "set -e %s\n", k)
+	}
+	for _, k := range slices.Sorted(maps.Keys(vars)) {
+		if keep[k] {
+			// Preserved: only set if the environment doesn't have it
+			fmt.Fprintf(&b, "set -q %s; or ", k)
+		}
+		fmt.Fprintf(&b, "set -gx %s %s\n", k, fishQuote(vars[k]))
+	}
+
+	command, targetArgs := scriptCommand(provider, fishQuote)
+	b.WriteString("\nexec " + command)
+	for _, arg := range append(targetArgs, args...) {
+		b.WriteString(" " + fishQuote(arg))
+	}
+	b.WriteString(" $argv\n")
+
+	return b.String()
+}
@@ -2,15 +2,26 @@ package launcher
 
 import (
 	"fmt"
+	"io"
+	"log/slog"
+	"maps"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"regexp"
 	"runtime"
+	"slices"
+	"sort"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/errcode"
 	"github.com/sammcj/skint/internal/providers"
+	"github.com/sammcj/skint/internal/pty"
+	"github.com/sammcj/skint/internal/ui"
 )
 
 // shellEscape escapes a string for safe inclusion inside single quotes in shell scripts.
@@ -18,10 +29,25 @@ func shellEscape(s string) string {
 	return strings.ReplaceAll(s, "'", "'\"'\"'")
 }
 
+// plainWord matches a command name that needs no quoting in a script.
+var plainWord = regexp.MustCompile(`^[A-Za-z0-9_./-]+$`)
+
+// scriptCommand returns the command word a script runs for provider's
+// target, quoted by quote if it needs it, and the target's arguments.
+func scriptCommand(provider providers.Provider, quote func(string) string) (string, []string) {
+	target := provider.Target()
+	if plainWord.MatchString(target.Command) {
+		return target.Command, target.Args
+	}
+	return quote(target.Command), target.Args
+}
+
 // Launcher handles spawning Claude with the correct environment
 type Launcher struct {
-	config  *config.Config
-	dataDir string
+	config   *config.Config
+	dataDir  string
+	extraEnv map[string]string
+	onExit   func(exitCode int, d time.Duration)
 }
 
 // New creates a new launcher
@@ -37,21 +63,38 @@ func New(cfg *config.Config) (*Launcher, error) {
 	}, nil
 }
 
-// Launch launches Claude with the specified provider
+// SetExtraEnv sets additional env vars (e.g. from env presets) applied on top
+// of the provider's variables at launch.
+func (l *Launcher) SetExtraEnv(env map[string]string) {
+	l.extraEnv = env
+}
+
+// SetOnExit sets a function called with Claude's exit code and the session's
+// length when it exits. It is only called when skint waits for Claude (see
+// exec); otherwise Claude replaces skint's process.
+func (l *Launcher) SetOnExit(fn func(exitCode int, d time.Duration)) {
+	l.onExit = fn
+}
+
+// Launch launches Claude, or the provider's other target CLI, with the
+// specified provider
 func (l *Launcher) Launch(provider providers.Provider, args []string) error {
 	// Validate provider
 	if err := provider.Validate(); err != nil {
 		return fmt.Errorf("provider validation failed: %w", err)
 	}
 
-	// Check if claude command exists
-	claudePath, err := exec.LookPath("claude")
+	// Check if the command exists
+	target := provider.Target()
+	path, err := lookTarget(target)
 	if err != nil {
-		return fmt.Errorf("claude command not found. Please install Claude Code: https://claude.ai/install.sh")
+		return err
 	}
 
 	// Build environment
 	env := l.buildEnvironment(provider)
+	vars := LaunchVars(provider, l.extraEnv)
+	slog.Debug("launch environment", "set", slices.Sorted(maps.Keys(vars)), "removed", RemovedEnvVars(vars))
 
 	// Show banner if enabled and not disabled via env
 	if !l.config.NoBanner && os.Getenv("SKINT_NO_BANNER") != "1" {
@@ -59,21 +102,57 @@ func (l *Launcher) Launch(provider providers.Provider, args []string) error {
 	}
 
 	// Launch Claude
-	return l.exec(claudePath, args, env)
+	return l.exec(target.Command, path, append(target.Args, args...), env, provider.Name(), provider.GetModel())
 }
 
-// buildEnvironment builds the environment variables for Claude
-func (l *Launcher) buildEnvironment(provider providers.Provider) []string {
-	// Start with current environment, remove conflicting vars
-	env := FilterEnvVars(os.Environ(), ConflictingEnvVars...)
+// lookTarget returns the path to target's command.
+func lookTarget(target providers.Target) (string, error) {
+	path, err := exec.LookPath(target.Command)
+	if err != nil && target.Name == config.TargetClaude {
+		return "", errcode.New(errcode.ClaudeNotFound, "claude command not found. Please install Claude Code: https://claude.ai/install.sh")
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s command not found (the provider's target is %s): %w", target.Command, target.Name, err)
+	}
+	return path, nil
+}
 
-	// Add provider-specific variables
-	providerVars := provider.GetEnvVars()
-	for key, value := range providerVars {
-		env = append(env, fmt.Sprintf("%s=%s", key, value))
+// Plan describes a launch without performing it, for skint use --dry-run.
+type Plan struct {
+	// Command is the CLI launched: claude or the provider's target.
+	// ClaudePath is where it was found, or "" if it isn't on PATH
+	Command    string
+	ClaudePath string
+	Args       []string
+	// Env is the variables skint sets; Removed is the inherited ones it
+	// removes without replacing
+	Env     map[string]string
+	Removed []string
+	// Exec is whether Claude would replace skint's process
+	Exec bool
+}
+
+// Plan returns what Launch would do with provider and args.
+func (l *Launcher) Plan(provider providers.Provider, args []string) (*Plan, error) {
+	if err := provider.Validate(); err != nil {
+		return nil, fmt.Errorf("provider validation failed: %w", err)
 	}
+	target := provider.Target()
+	claudePath, _ := exec.LookPath(target.Command)
+	env := LaunchVars(provider, l.extraEnv)
+	return &Plan{
+		Command:    target.Command,
+		ClaudePath: claudePath,
+		Args:       append(target.Args, args...),
+		Env:        env,
+		Removed:    RemovedEnvVars(env),
+		Exec:       !l.waits(),
+	}, nil
+}
 
-	return env
+// buildEnvironment builds the environment variables for Claude
+func (l *Launcher) buildEnvironment(provider providers.Provider) []string {
+	return BuildEnv(provider, l.extraEnv)
 }
 
 // showBanner displays the Skint banner
@@ -84,31 +163,110 @@ func (l *Launcher) showBanner(provider providers.Provider) {
 		fmt.Fprint(os.Stderr, string(data))
 	} else {
 		// Default banner
-		fmt.Fprintln(os.Stderr, "  ____ _       _   _")
-		fmt.Fprintln(os.Stderr, " / ___| | ___ | |_| |__   ___ _ __")
-		fmt.Fprintln(os.Stderr, "| |   | |/ _ \\| __| '_ \\ / _ \\ '__|")
-		fmt.Fprintln(os.Stderr, "| |___| | (_) | |_| | | |  __/ |")
-		fmt.Fprintln(os.Stderr, " \\____|_|\\___/ \\__|_| |_|\\___|_|")
+		fmt.Fprint(os.Stderr, ui.Banner)
 	}
 
 	fmt.Fprintf(os.Stderr, "    + %s\n\n", provider.DisplayName())
 }
 
-// exec executes Claude with the given environment
-func (l *Launcher) exec(claudePath string, args []string, env []string) error {
-	if runtime.GOOS == "windows" {
-		// Windows doesn't support syscall.Exec, use exec.Command
-		cmd := exec.Command(claudePath, args...)
-		cmd.Env = env
-		cmd.Stdin = os.Stdin
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-		return cmd.Run()
+// exec executes Claude (or another command) with the given environment.
+// providerName and model are only used for the exit summary.
+func (l *Launcher) exec(command, path string, args []string, env []string, providerName, model string) error {
+	slog.Info("launching "+command, "path", path, "args", args, "provider", providerName, "model", model, "wait", l.waits())
+	if l.waits() {
+		return l.run(path, args, env, providerName, model)
 	}
 
 	// Unix: Use syscall.Exec to replace current process
 	// This is important so signals are properly passed to Claude
-	return syscall.Exec(claudePath, append([]string{"claude"}, args...), env)
+	return syscall.Exec(path, append([]string{command}, args...), env)
+}
+
+// waits reports whether skint runs Claude as a child process rather than
+// replacing itself: Windows doesn't support syscall.Exec, and the exit
+// summary, the watchdog and the pseudo-terminal need skint to still be
+// around while Claude runs.
+func (l *Launcher) waits() bool {
+	return runtime.GOOS == "windows" || l.config.ExitSummary || l.config.Supervised() || l.config.PTY
+}
+
+// run runs Claude as a child process, printing the exit summary afterwards if
+// enabled. Returns an *exec.ExitError if Claude exits non-zero, wrapped in a
+// *ProviderFailedError if the watchdog blames the provider.
+func (l *Launcher) run(claudePath string, args []string, env []string, providerName, model string) error {
+	cmd := exec.Command(claudePath, args...)
+	cmd.Env = env
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	// The watchdog reads Claude's error output as it passes through; stdout
+	// is left alone so Claude still sees a terminal
+	var tail *tailBuffer
+	if l.config.Supervised() {
+		tail = &tailBuffer{max: watchdogTail}
+		cmd.Stderr = io.MultiWriter(os.Stderr, tail)
+	}
+
+	start := time.Now()
+	wait := cmd.Wait
+	if l.config.PTY {
+		// The pseudo-terminal carries stderr too, so the watchdog reads it all
+		out := io.Writer(os.Stdout)
+		if tail != nil {
+			out = io.MultiWriter(os.Stdout, tail)
+		}
+		session, err := pty.Start(cmd, out)
+		if err != nil {
+			return fmt.Errorf("failed to start claude: %w", err)
+		}
+		wait = session.Wait
+	} else if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start claude: %w", err)
+	}
+
+	// Ctrl-C reaches Claude directly from the terminal, so skint ignores it
+	// rather than exiting underneath; other termination signals are passed on
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, append([]os.Signal{os.Interrupt}, forwardedSignals...)...)
+	defer func() {
+		signal.Stop(sigs)
+		close(sigs)
+	}()
+	go func() {
+		for sig := range sigs {
+			if sig != os.Interrupt {
+				_ = cmd.Process.Signal(sig)
+			}
+		}
+	}()
+
+	err := wait()
+	d := time.Since(start)
+	slog.Info("claude exited", "status", cmd.ProcessState.ExitCode(), "duration", d)
+	if l.onExit != nil {
+		l.onExit(cmd.ProcessState.ExitCode(), d)
+	}
+	if err != nil && tail != nil && d < watchdogWindow {
+		if reason := providerFailure(tail.String()); reason != "" {
+			slog.Warn("provider failed", "provider", providerName, "reason", reason)
+			return &ProviderFailedError{Provider: providerName, Reason: reason, Err: err}
+		}
+	}
+	if l.config.ExitSummary {
+		fmt.Fprintln(os.Stderr, sessionSummary(providerName, model, d))
+	}
+	return err
+}
+
+// sessionSummary returns the one-line summary printed when a session ends.
+func sessionSummary(providerName, model string, d time.Duration) string {
+	parts := []string{"Session ended after " + d.Round(time.Second).String(), providerName}
+	if model != "" {
+		parts = append(parts, model)
+	}
+	parts = append(parts, "resume: skint use "+providerName+" --continue")
+	return strings.Join(parts, " · ")
 }
 
 // LaunchNative launches Claude without any provider env var overrides.
@@ -116,35 +274,26 @@ func (l *Launcher) exec(claudePath string, args []string, env []string) error {
 func (l *Launcher) LaunchNative(args []string) error {
 	claudePath, err := exec.LookPath("claude")
 	if err != nil {
-		return fmt.Errorf("claude command not found. Please install Claude Code: https://claude.ai/install.sh")
+		return errcode.New(errcode.ClaudeNotFound, "claude command not found. Please install Claude Code: https://claude.ai/install.sh")
 	}
 
 	env := os.Environ()
-	return l.exec(claudePath, args, env)
+	return l.exec("claude", claudePath, args, env, "native", "")
 }
 
-// CheckClaude verifies that Claude CLI is installed
-func CheckClaude() error {
-	_, err := exec.LookPath("claude")
-	if err != nil {
-		return fmt.Errorf("claude command not found. Please install Claude Code first:\n  curl -fsSL https://claude.ai/install.sh | bash")
-	}
-	return nil
-}
-
-// GenerateScript generates a shell script for the provider (backward compatibility)
-func GenerateScript(provider providers.Provider, binDir string) error {
-	name := provider.Name()
-	scriptPath := filepath.Join(binDir, fmt.Sprintf("skint-%s", name))
-
-	// Build script content
-	script := fmt.Sprintf(`#!/usr/bin/env bash
+// Script returns a bash wrapper script that launches claude (or the
+// provider's target) with provider the same way Launch does: conflicting
+// variables are unset, the provider's variables and extra (e.g. env presets)
+// exported, and args passed before any arguments given to the script.
+func Script(provider providers.Provider, extra map[string]string, args []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `#!/usr/bin/env bash
 # Generated by Skint - Multi-provider launcher for Claude CLI
 set -euo pipefail
 
 # Show banner
 if [[ "${SKINT_NO_BANNER:-}" != "1" && -t 1 ]]; then
-  cat "${XDG_DATA_HOME:-$HOME/.local/share}/skint/banner" 2>/dev/null || echo "  ____ _       _   _"
+  cat "${XDG_DATA_HOME:-$HOME/.local/share}/skint/banner" 2>/dev/null || echo " ____  _    _       _"
   echo '    + %s'
   echo
 fi
@@ -159,30 +308,158 @@ fi
 # Set environment variables
 `, shellEscape(provider.DisplayName()))
 
-	// Add provider-specific exports
-	envVars := provider.GetEnvVars()
-	for key, value := range envVars {
-		if value == "" {
-			script += fmt.Sprintf("export %s=''\n", key)
-		} else {
-			script += fmt.Sprintf("export %s='%s'\n", key, shellEscape(value))
+	vars, unset, keep := scriptVars(provider, extra)
+	names := make([]string, 0, len(vars))
+	for k := range vars {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	// Clear inherited values the provider doesn't set, as BuildEnv does
+	if len(unset) > 0 {
+		fmt.Fprintf(&b, "unset %s\n", strings.Join(unset, " "))
+	}
+	for _, k := range names {
+		if keep[k] {
+			// Preserved: only set if the environment doesn't have it
+			fmt.Fprintf(&b, "[[ -n \"${%s+set}\" ]] || ", k)
+		}
+		fmt.Fprintf(&b, "export %s='%s'\n", k, shellEscape(vars[k]))
+	}
+
+	command, targetArgs := scriptCommand(provider, func(s string) string { return "'" + shellEscape(s) + "'" })
+	b.WriteString("\nexec " + command)
+	for _, arg := range append(targetArgs, args...) {
+		fmt.Fprintf(&b, " '%s'", shellEscape(arg))
+	}
+	b.WriteString(" \"$@\"\n")
+
+	return b.String()
+}
+
+// ScriptPath returns where GenerateScript writes provider name's script: the
+// bash script, or on Windows the .cmd one.
+func ScriptPath(binDir, name string) string {
+	path := filepath.Join(binDir, fmt.Sprintf("skint-%s", name))
+	if runtime.GOOS == "windows" {
+		path += ".cmd"
+	}
+	return path
+}
+
+// Shells wrapper scripts can be written for
+const (
+	ShellBash       = "bash"
+	ShellFish       = "fish"
+	ShellPowerShell = "powershell"
+	ShellCmd        = "cmd"
+)
+
+// ScriptShells lists the shells Scripts can write for.
+var ScriptShells = []string{ShellBash, ShellFish, ShellPowerShell, ShellCmd}
+
+// scriptExts are the extensions of each shell's script file names.
+var scriptExts = map[string]string{ShellBash: "", ShellFish: ".fish", ShellPowerShell: ".ps1", ShellCmd: ".cmd"}
+
+// ScriptNames returns the file names of provider name's scripts in shells.
+func ScriptNames(name string, shells ...string) []string {
+	names := make([]string, 0, len(shells))
+	for _, shell := range shells {
+		names = append(names, "skint-"+name+scriptExts[shell])
+	}
+	return names
+}
+
+// DefaultShells returns the shells scripts are written for when none are
+// given: bash, or on Windows cmd and PowerShell.
+func DefaultShells() []string {
+	if runtime.GOOS == "windows" {
+		return []string{ShellCmd, ShellPowerShell}
+	}
+	return []string{ShellBash}
+}
+
+// Scripts returns the wrapper scripts GenerateScript writes for provider in
+// shells (DefaultShells if none), by file name: skint-<name> for bash, and
+// skint-<name>.fish, .ps1 and .cmd for the others.
+func Scripts(provider providers.Provider, extra map[string]string, args []string, shells ...string) (map[string]string, error) {
+	if len(shells) == 0 {
+		shells = DefaultShells()
+	}
+	scripts := make(map[string]string, len(shells))
+	for _, shell := range shells {
+		var script string
+		switch shell {
+		case ShellBash:
+			script = Script(provider, extra, args)
+		case ShellFish:
+			script = FishScript(provider, extra, args)
+		case ShellPowerShell:
+			script = PowerShellScript(provider, extra, args)
+		case ShellCmd:
+			var err error
+			if script, err = CmdScript(provider, extra, args); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unknown shell %q (valid: %s)", shell, strings.Join(ScriptShells, ", "))
 		}
+		scripts[ScriptNames(provider.Name(), shell)[0]] = script
 	}
+	return scripts, nil
+}
+
+// ModifiedError is returned by GenerateScript when scripts it would replace
+// were changed since skint wrote them.
+type ModifiedError struct {
+	Names []string
+}
+
+func (e *ModifiedError) Error() string {
+	return strings.Join(e.Names, ", ") + " changed since it was generated"
+}
 
-	// Add exec claude
-	script += `
-exec claude "$@"
-`
+// GenerateScript writes the Scripts for provider in shells to binDir
+// (backward compatibility with the bash version), recording them in binDir's
+// Manifest. Unless force is set, nothing is written if any of them was
+// changed since skint wrote it (see Manifest.Modified); the error is a
+// *ModifiedError naming them.
+func GenerateScript(provider providers.Provider, binDir string, extra map[string]string, args []string, force bool, shells ...string) error {
+	scripts, err := Scripts(provider, extra, args, shells...)
+	if err != nil {
+		return err
+	}
 
 	// Ensure bin directory exists
 	if err := os.MkdirAll(binDir, 0755); err != nil {
 		return fmt.Errorf("failed to create bin directory: %w", err)
 	}
 
-	// Write script with owner-only permissions: it embeds the provider's API key.
-	if err := os.WriteFile(scriptPath, []byte(script), 0700); err != nil {
-		return fmt.Errorf("failed to write script: %w", err)
+	manifest, err := LoadManifest(binDir)
+	if err != nil {
+		slog.Warn("replacing script manifest", "err", err)
+	}
+
+	if !force {
+		var edited []string
+		for name := range scripts {
+			if manifest.Modified(name) {
+				edited = append(edited, name)
+			}
+		}
+		if len(edited) > 0 {
+			slices.Sort(edited)
+			return &ModifiedError{Names: edited}
+		}
+	}
+
+	// Write scripts with owner-only permissions: they embed the provider's API key.
+	for name, data := range scripts {
+		if err := os.WriteFile(filepath.Join(binDir, name), []byte(data), 0700); err != nil {
+			return fmt.Errorf("failed to write script: %w", err)
+		}
+		manifest.Record(name, data)
 	}
 
-	return nil
+	return manifest.Save()
 }
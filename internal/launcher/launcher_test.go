@@ -1,10 +1,16 @@
 package launcher
 
 import (
+	"errors"
+	"maps"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
 	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/sammcj/skint/internal/config"
 	"github.com/sammcj/skint/internal/providers"
@@ -22,7 +28,7 @@ func TestGenerateScriptPermissions(t *testing.T) {
 	}
 	p.SetAPIKey("secret-key")
 
-	if err := GenerateScript(p, dir); err != nil {
+	if err := GenerateScript(p, dir, nil, nil, false); err != nil {
 		t.Fatalf("GenerateScript: %v", err)
 	}
 
@@ -171,3 +177,381 @@ func TestConflictingEnvVars(t *testing.T) {
 		}
 	}
 }
+
+func TestInitConflictingEnvVars(t *testing.T) {
+	t.Cleanup(func() { Init(&config.Config{}) })
+	Init(&config.Config{ClearEnv: []string{"PORTKEY_API_KEY", "OPENAI_MODEL"}, KeepEnv: []string{"OPENAI_API_KEY"}})
+
+	got := Conflicting()
+	if !slices.Contains(got, "PORTKEY_API_KEY") || slices.Contains(got, "OPENAI_API_KEY") {
+		t.Errorf("Conflicting() = %v, want PORTKEY_API_KEY added and OPENAI_API_KEY removed", got)
+	}
+	if len(got) != len(ConflictingEnvVars) {
+		t.Errorf("Conflicting() has %d entries, want %d", len(got), len(ConflictingEnvVars))
+	}
+
+	env := withVars([]string{"PORTKEY_API_KEY=stale", "OPENAI_API_KEY=mine"}, nil)
+	if slices.Contains(env, "PORTKEY_API_KEY=stale") {
+		t.Error("PORTKEY_API_KEY was passed on")
+	}
+	if !slices.Contains(env, "OPENAI_API_KEY=mine") {
+		t.Error("OPENAI_API_KEY was removed")
+	}
+}
+
+func TestBuildEnvAppliesExtraVars(t *testing.T) {
+	t.Setenv("HTTPS_PROXY", "http://stale:1")
+	t.Setenv("ANTHROPIC_MODEL", "stale-model")
+
+	p, err := providers.FromConfig(&config.Provider{
+		Name:    "ollama",
+		Type:    config.ProviderTypeLocal,
+		BaseURL: "http://localhost:11434",
+		Model:   "qwen3",
+	})
+	if err != nil {
+		t.Fatalf("FromConfig: %v", err)
+	}
+
+	env := BuildEnv(p, map[string]string{"HTTPS_PROXY": "http://proxy:3128"})
+
+	count := func(name string) (n int, last string) {
+		for _, e := range env {
+			if k, v, ok := strings.Cut(e, "="); ok && k == name {
+				n++
+				last = v
+			}
+		}
+		return n, last
+	}
+
+	if n, v := count("HTTPS_PROXY"); n != 1 || v != "http://proxy:3128" {
+		t.Errorf("HTTPS_PROXY: %d entries, value %q; want exactly one preset value", n, v)
+	}
+	if n, v := count("ANTHROPIC_MODEL"); n != 1 || v != "qwen3" {
+		t.Errorf("ANTHROPIC_MODEL: %d entries, value %q; want exactly one provider value", n, v)
+	}
+}
+
+func TestIsolatedEnv(t *testing.T) {
+	t.Setenv("PATH", "/usr/bin")
+	t.Setenv("GITHUB_TOKEN", "ghp-secret")
+	t.Setenv("ANTHROPIC_MODEL", "stale-model")
+
+	p, err := providers.FromConfig(&config.Provider{
+		Name:    "ollama",
+		Type:    config.ProviderTypeLocal,
+		BaseURL: "http://localhost:11434",
+		Model:   "qwen3",
+	})
+	if err != nil {
+		t.Fatalf("FromConfig: %v", err)
+	}
+
+	env := IsolatedEnv(p, map[string]string{"HTTPS_PROXY": "http://proxy:3128"})
+	for _, want := range []string{"PATH=/usr/bin", "ANTHROPIC_MODEL=qwen3", "HTTPS_PROXY=http://proxy:3128"} {
+		if !slices.Contains(env, want) {
+			t.Errorf("env is missing %s: %q", want, env)
+		}
+	}
+	for _, e := range env {
+		name, _, _ := strings.Cut(e, "=")
+		if name == "GITHUB_TOKEN" || e == "ANTHROPIC_MODEL=stale-model" {
+			t.Errorf("env kept %s", e)
+		}
+	}
+}
+
+func TestPlan(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "sk-stale")
+	t.Setenv("ANTHROPIC_MODEL", "stale-model")
+	t.Setenv("OPENAI_MODEL", "")
+
+	p, err := providers.FromConfig(&config.Provider{
+		Name:    "ollama",
+		Type:    config.ProviderTypeLocal,
+		BaseURL: "http://localhost:11434",
+		Model:   "qwen3",
+	})
+	if err != nil {
+		t.Fatalf("FromConfig: %v", err)
+	}
+	l := &Launcher{config: &config.Config{ExitSummary: true}}
+	l.SetExtraEnv(map[string]string{"HTTPS_PROXY": "http://proxy:3128"})
+
+	plan, err := l.Plan(p, []string{"--continue"})
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	if plan.Env["ANTHROPIC_MODEL"] != "qwen3" || plan.Env["HTTPS_PROXY"] != "http://proxy:3128" {
+		t.Errorf("env = %v, want the provider's and extra variables", plan.Env)
+	}
+	// ANTHROPIC_MODEL is replaced, not removed; an empty value still counts
+	if want := []string{"OPENAI_API_KEY", "OPENAI_MODEL"}; !slices.Equal(plan.Removed, want) {
+		t.Errorf("removed = %v, want %v", plan.Removed, want)
+	}
+	if plan.Exec {
+		t.Error("with exit_summary skint should wait for Claude, not exec it")
+	}
+	if !slices.Equal(plan.Args, []string{"--continue"}) {
+		t.Errorf("args = %v", plan.Args)
+	}
+}
+
+func TestSessionSummary(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		model    string
+		d        time.Duration
+		want     string
+	}{
+		{
+			name:     "with model",
+			provider: "zai", model: "glm-5", d: 83*time.Minute + 2400*time.Millisecond,
+			want: "Session ended after 1h23m2s · zai · glm-5 · resume: skint use zai --continue",
+		},
+		{
+			name:     "without model",
+			provider: "native", d: 5 * time.Second,
+			want: "Session ended after 5s · native · resume: skint use native --continue",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := sessionSummary(tc.provider, tc.model, tc.d); got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRunPassesExitCode(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+	l := &Launcher{config: &config.Config{ExitSummary: true}}
+
+	err = l.run(sh, []string{"-c", "exit 3"}, os.Environ(), "zai", "")
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+		t.Fatalf("run: got %v, want exit status 3", err)
+	}
+	if err := l.run(sh, []string{"-c", "exit 0"}, os.Environ(), "zai", ""); err != nil {
+		t.Errorf("run: unexpected error %v", err)
+	}
+}
+
+func TestRunWatchdogPTY(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil || (runtime.GOOS != "linux" && runtime.GOOS != "darwin") {
+		t.Skip("needs sh and pseudo-terminals")
+	}
+	l := &Launcher{config: &config.Config{Watchdog: config.WatchdogAsk, PTY: true}}
+
+	// Under the pseudo-terminal stderr arrives with the rest of the output
+	err = l.run(sh, []string{"-c", "test -t 2 && echo 'connect ECONNREFUSED 127.0.0.1:11434' >&2; exit 1"}, os.Environ(), "ollama", "")
+	var failed *ProviderFailedError
+	if !errors.As(err, &failed) || !strings.Contains(failed.Reason, "ECONNREFUSED") {
+		t.Errorf("run: got %v, want a ProviderFailedError", err)
+	}
+}
+
+func TestRunWatchdog(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+	l := &Launcher{config: &config.Config{Watchdog: config.WatchdogAuto}}
+
+	err = l.run(sh, []string{"-c", "echo 'API Error: 401 invalid x-api-key' >&2; exit 1"}, os.Environ(), "zai", "")
+	var failed *ProviderFailedError
+	if !errors.As(err, &failed) || failed.Provider != "zai" || !strings.Contains(failed.Reason, "401") {
+		t.Fatalf("run: got %v, want a ProviderFailedError", err)
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		t.Errorf("run: %v doesn't wrap exit status 1", err)
+	}
+
+	// Other failures are Claude's own
+	err = l.run(sh, []string{"-c", "echo 'unknown option' >&2; exit 1"}, os.Environ(), "zai", "")
+	if errors.As(err, &failed) || !errors.As(err, &exitErr) {
+		t.Errorf("run: got %v, want a plain exit error", err)
+	}
+}
+
+func TestProviderFailure(t *testing.T) {
+	tests := map[string]string{
+		"Starting\nAPI Error: 401 {\"error\":\"Unauthorized\"}\n": `API Error: 401 {"error":"Unauthorized"}`,
+		"Error: connect ECONNREFUSED 127.0.0.1:11434":             "Error: connect ECONNREFUSED 127.0.0.1:11434",
+		"Invalid API key · Please run /login":                     "Invalid API key · Please run /login",
+		"getaddrinfo ENOTFOUND api.z.ai":                          "getaddrinfo ENOTFOUND api.z.ai",
+		"error: unknown option '--bogus'":                         "",
+		"read 4012 files":                                         "",
+	}
+	for output, want := range tests {
+		if got := providerFailure(output); got != want {
+			t.Errorf("providerFailure(%q) = %q, want %q", output, got, want)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.88", "1.0.88", 0},
+		{"1.0.9", "1.0.88", -1},
+		{"2.0.0", "1.0.88", 1},
+		{"1.1", "1.0.88", 1},
+		{"1.0", "1.0.0", 0},
+	}
+	for _, tc := range tests {
+		if got := compareVersions(tc.a, tc.b); got != tc.want {
+			t.Errorf("compareVersions(%s, %s) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestDetectClaude(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as claude")
+	}
+	binDir := t.TempDir()
+	count := filepath.Join(t.TempDir(), "count")
+	stub := "#!/bin/sh\necho x >> " + count + "\necho '1.0.51 (Claude Code)'\n"
+	claude := filepath.Join(binDir, "claude")
+	if err := os.WriteFile(claude, []byte(stub), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	c, err := DetectClaude()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Path != claude || c.Version != "1.0.51" || c.Supported() {
+		t.Errorf("DetectClaude = %+v, supported %v", c, c.Supported())
+	}
+	if _, err := DetectClaude(); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(count); strings.Count(string(data), "x") != 1 {
+		t.Errorf("claude --version ran %d times, want once (cached)", strings.Count(string(data), "x"))
+	}
+
+	// An update invalidates the cache
+	stub = strings.Replace(stub, "1.0.51", "2.0.14", 1) + "# updated\n"
+	if err := os.WriteFile(claude, []byte(stub), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if c, err := DetectClaude(); err != nil || c.Version != "2.0.14" || !c.Supported() {
+		t.Errorf("after update: %+v, %v", c, err)
+	}
+
+	t.Setenv("PATH", t.TempDir())
+	if _, err := DetectClaude(); err == nil {
+		t.Error("DetectClaude succeeded without claude on PATH")
+	}
+}
+
+func TestLaunchVarsPreserveEnv(t *testing.T) {
+	p, err := providers.FromConfig(&config.Provider{
+		Name: "zai", Type: config.ProviderTypeBuiltin, BaseURL: "https://api.z.ai/api/anthropic", Model: "glm-5",
+		PreserveEnv: []string{"ANTHROPIC_MODEL", "ANTHROPIC_CUSTOM_HEADERS", "UNSET_VAR"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("ANTHROPIC_MODEL", "mine")
+	t.Setenv("ANTHROPIC_CUSTOM_HEADERS", "X-Team: a")
+	os.Unsetenv("UNSET_VAR")
+
+	vars := LaunchVars(p, map[string]string{"ANTHROPIC_CUSTOM_HEADERS": "X-Team: preset"})
+	if vars["ANTHROPIC_MODEL"] != "mine" {
+		t.Errorf("ANTHROPIC_MODEL = %q, want the inherited value", vars["ANTHROPIC_MODEL"])
+	}
+	if vars["ANTHROPIC_CUSTOM_HEADERS"] != "X-Team: preset" {
+		t.Errorf("ANTHROPIC_CUSTOM_HEADERS = %q, want the preset's value", vars["ANTHROPIC_CUSTOM_HEADERS"])
+	}
+	if _, ok := vars["UNSET_VAR"]; ok {
+		t.Error("a preserved variable missing from the environment was set")
+	}
+}
+
+func TestScriptsShells(t *testing.T) {
+	p, err := providers.FromConfig(&config.Provider{Name: "ollama", Type: config.ProviderTypeLocal, BaseURL: "http://localhost:11434", AuthToken: "ollama"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	scripts, err := Scripts(p, nil, nil, ScriptShells...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := slices.Sorted(maps.Keys(scripts))
+	want := []string{"skint-ollama", "skint-ollama.cmd", "skint-ollama.fish", "skint-ollama.ps1"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Scripts = %v, want %v", got, want)
+	}
+	if _, err := Scripts(p, nil, nil, "zsh"); err == nil {
+		t.Error("Scripts accepted an unknown shell")
+	}
+}
+
+func TestManifest(t *testing.T) {
+	dir := t.TempDir()
+	p, err := providers.FromConfig(&config.Provider{Name: "ollama", Type: config.ProviderTypeLocal, BaseURL: "http://localhost:11434", AuthToken: "ollama"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := GenerateScript(p, dir, nil, nil, false, ShellBash, ShellFish); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := LoadManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m.Names(); !slices.Equal(got, []string{"skint-ollama", "skint-ollama.fish"}) {
+		t.Fatalf("Names() = %v", got)
+	}
+	if m.Modified("skint-ollama") {
+		t.Error("a generated script counts as modified")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "skint-ollama"), []byte("#!/bin/sh\nexec claude\n"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if !m.Modified("skint-ollama") {
+		t.Error("an edited script doesn't count as modified")
+	}
+	if m.Modified("skint-other") || m.Tracked("skint-other") {
+		t.Error("an untracked script counts as tracked or modified")
+	}
+
+	var edited *ModifiedError
+	if err := GenerateScript(p, dir, nil, nil, false, ShellBash, ShellFish); !errors.As(err, &edited) || !slices.Equal(edited.Names, []string{"skint-ollama"}) {
+		t.Fatalf("GenerateScript over an edited script = %v, want a ModifiedError naming it", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "skint-ollama")); string(data) != "#!/bin/sh\nexec claude\n" {
+		t.Error("GenerateScript replaced an edited script without force")
+	}
+	if err := GenerateScript(p, dir, nil, nil, true, ShellBash, ShellFish); err != nil {
+		t.Fatalf("GenerateScript with force: %v", err)
+	}
+	if m, _ := LoadManifest(dir); m.Modified("skint-ollama") {
+		t.Error("force didn't replace the edited script")
+	}
+
+	m.Forget("skint-ollama")
+	m.Forget("skint-ollama.fish")
+	if err := m.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ManifestFile)); !os.IsNotExist(err) {
+		t.Errorf("empty manifest was kept: %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package launcher
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// ManifestFile lists the scripts GenerateScript wrote to a bin directory,
+// so they can be told apart from other files there and from scripts edited
+// since.
+const ManifestFile = ".skint-scripts.json"
+
+// Manifest maps the names of the scripts skint wrote to a bin directory to
+// the SHA-256 of what it wrote.
+type Manifest struct {
+	dir     string
+	Scripts map[string]string `json:"scripts"`
+}
+
+// LoadManifest reads binDir's manifest. A missing file is an empty manifest.
+func LoadManifest(binDir string) (*Manifest, error) {
+	m := &Manifest{dir: binDir, Scripts: map[string]string{}}
+	data, err := os.ReadFile(filepath.Join(binDir, ManifestFile))
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return m, fmt.Errorf("failed to read script manifest: %w", err)
+	}
+	if err := json.Unmarshal(data, m); err != nil {
+		return &Manifest{dir: binDir, Scripts: map[string]string{}}, fmt.Errorf("failed to parse script manifest: %w", err)
+	}
+	if m.Scripts == nil {
+		m.Scripts = map[string]string{}
+	}
+	return m, nil
+}
+
+// Names returns the sorted names of the scripts in the manifest.
+func (m *Manifest) Names() []string {
+	return slices.Sorted(maps.Keys(m.Scripts))
+}
+
+// Tracked reports whether skint wrote the script called name.
+func (m *Manifest) Tracked(name string) bool {
+	_, ok := m.Scripts[name]
+	return ok
+}
+
+// Modified reports whether the script called name was changed since skint
+// wrote it. Untracked and missing scripts aren't modified.
+func (m *Manifest) Modified(name string) bool {
+	want, ok := m.Scripts[name]
+	if !ok {
+		return false
+	}
+	data, err := os.ReadFile(filepath.Join(m.dir, name))
+	if err != nil {
+		return false
+	}
+	return hashScript(string(data)) != want
+}
+
+// Record notes that skint wrote content to the script called name.
+func (m *Manifest) Record(name, content string) {
+	m.Scripts[name] = hashScript(content)
+}
+
+// Forget removes the script called name from the manifest.
+func (m *Manifest) Forget(name string) {
+	delete(m.Scripts, name)
+}
+
+// Save writes the manifest to its bin directory, or removes it when empty.
+func (m *Manifest) Save() error {
+	path := filepath.Join(m.dir, ManifestFile)
+	if len(m.Scripts) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove script manifest: %w", err)
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write script manifest: %w", err)
+	}
+	return nil
+}
+
+func hashScript(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
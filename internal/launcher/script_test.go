@@ -0,0 +1,248 @@
+package launcher
+
+import (
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/providers"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files in testdata")
+
+// scriptCase is a provider setup whose generated script is checked against
+// testdata/scripts/<name>.golden.
+type scriptCase struct {
+	name  string
+	cp    *config.Provider
+	key   string
+	extra map[string]string
+	args  []string
+}
+
+var scriptCases = []scriptCase{
+	{
+		name: "builtin-tiers",
+		cp: &config.Provider{
+			Name: "zai", Type: config.ProviderTypeBuiltin, DisplayName: "Z.AI",
+			BaseURL:       "https://api.z.ai/api/anthropic",
+			Model:         "glm-5",
+			ModelMappings: map[string]string{"haiku": "glm-4.5-air", "opus": "glm-5"},
+		},
+		key:   "zai-key",
+		extra: map[string]string{"HTTPS_PROXY": "http://proxy:3128"},
+		args:  []string{"--verbose", "--append-system-prompt", "it's"},
+	},
+	{
+		name: "openrouter",
+		cp: &config.Provider{
+			Name: "openrouter", Type: config.ProviderTypeOpenRouter, DisplayName: "OpenRouter",
+			BaseURL:       "https://openrouter.ai/api",
+			Model:         "anthropic/claude-sonnet-4",
+			ModelMappings: map[string]string{"haiku": "anthropic/claude-haiku-4"},
+		},
+		key: "or-key",
+	},
+	{
+		name: "local",
+		cp: &config.Provider{
+			Name: "ollama", Type: config.ProviderTypeLocal, DisplayName: "Ollama",
+			BaseURL: "http://localhost:11434", AuthToken: "ollama", Model: "qwen3-coder",
+		},
+		args: []string{"--continue"},
+	},
+	{
+		name: "custom-openai",
+		cp: &config.Provider{
+			Name: "my-llm", Type: config.ProviderTypeCustom, DisplayName: "My LLM",
+			BaseURL: "https://llm.example.com/v1", APIType: config.APITypeOpenAI, Model: "gpt-4o",
+		},
+		key: "sk-custom",
+	},
+	{
+		name: "preserve-env",
+		cp: &config.Provider{
+			Name: "zai", Type: config.ProviderTypeBuiltin, DisplayName: "Z.AI",
+			BaseURL:     "https://api.z.ai/api/anthropic",
+			Model:       "glm-5",
+			PreserveEnv: []string{"ANTHROPIC_MODEL", "OPENAI_API_KEY"},
+		},
+		key: "zai-key",
+	},
+	{
+		name: "target-codex",
+		cp: &config.Provider{
+			Name: "my-llm", Type: config.ProviderTypeCustom, DisplayName: "My LLM",
+			BaseURL: "https://llm.example.com/v1", APIType: config.APITypeOpenAI, Model: "gpt-4o",
+			Target: config.TargetCodex,
+		},
+		key:  "sk-custom",
+		args: []string{"--full-auto"},
+	},
+}
+
+func (tc scriptCase) provider(t *testing.T) providers.Provider {
+	t.Helper()
+	p, err := providers.FromConfig(tc.cp)
+	if err != nil {
+		t.Fatalf("FromConfig: %v", err)
+	}
+	if tc.key != "" {
+		p.SetAPIKey(tc.key)
+	}
+	return p
+}
+
+// scriptKinds generate each kind of wrapper script, by golden file suffix.
+var scriptKinds = map[string]func(providers.Provider, map[string]string, []string) (string, error){
+	"": func(p providers.Provider, extra map[string]string, args []string) (string, error) {
+		return Script(p, extra, args), nil
+	},
+	".fish": func(p providers.Provider, extra map[string]string, args []string) (string, error) {
+		return FishScript(p, extra, args), nil
+	},
+	".ps1": func(p providers.Provider, extra map[string]string, args []string) (string, error) {
+		return PowerShellScript(p, extra, args), nil
+	},
+	".cmd": CmdScript,
+}
+
+func TestScriptGolden(t *testing.T) {
+	for _, tc := range scriptCases {
+		for suffix, script := range scriptKinds {
+			t.Run(tc.name+suffix, func(t *testing.T) {
+				testScriptGolden(t, tc, suffix, script)
+			})
+		}
+	}
+}
+
+func testScriptGolden(t *testing.T, tc scriptCase, suffix string, script func(providers.Provider, map[string]string, []string) (string, error)) {
+	t.Helper()
+	got, err := script(tc.provider(t), tc.extra, tc.args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden := filepath.Join("testdata", "scripts", tc.name+suffix+".golden")
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(golden), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(golden, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("reading golden file (run with -update to create): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("script differs from %s (run with -update to accept):\n%s", golden, got)
+	}
+}
+
+func TestCmdQuote(t *testing.T) {
+	tests := map[string]string{
+		"--verbose":     `"--verbose"`,
+		`say "hi"`:      `"say ""hi"""`,
+		`C:\dir\`:       `"C:\dir\\"`,
+		`a\"b`:          `"a\\""b"`,
+		"100% & <more>": `"100%% & <more>"`,
+	}
+	for arg, want := range tests {
+		if got := cmdQuote(arg); got != want {
+			t.Errorf("cmdQuote(%q) = %s, want %s", arg, got, want)
+		}
+	}
+
+	p, err := providers.FromConfig(&config.Provider{Name: "x", Type: config.ProviderTypeLocal, BaseURL: "http://localhost:1234", AuthToken: "a\nb"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CmdScript(p, nil, nil); err == nil {
+		t.Error("CmdScript accepted a value with a line break")
+	}
+}
+
+// TestScriptMatchesLaunch runs each script with a stub claude (or target
+// CLI) and checks it sees the same provider environment and arguments as
+// Launch would use.
+func TestScriptMatchesLaunch(t *testing.T) {
+	testScriptMatchesLaunch(t, "bash", Script)
+}
+
+// TestFishScriptMatchesLaunch is TestScriptMatchesLaunch for FishScript.
+func TestFishScriptMatchesLaunch(t *testing.T) {
+	testScriptMatchesLaunch(t, "fish", FishScript)
+}
+
+func testScriptMatchesLaunch(t *testing.T, shell string, script func(providers.Provider, map[string]string, []string) string) {
+	sh, err := exec.LookPath(shell)
+	if err != nil {
+		t.Skip(shell + " not available")
+	}
+
+	binDir := t.TempDir()
+	stub := "#!/usr/bin/env bash\nenv\necho '--- args'\nprintf '%s\\n' \"$@\"\n"
+	for _, name := range []string{"claude", config.TargetCodex} {
+		if err := os.WriteFile(filepath.Join(binDir, name), []byte(stub), 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Stale values from the parent environment must not leak through
+	t.Setenv("ANTHROPIC_MODEL", "stale-model")
+	t.Setenv("OPENAI_API_KEY", "stale-key")
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("SKINT_NO_BANNER", "1")
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	for _, tc := range scriptCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := tc.provider(t)
+			scriptPath := filepath.Join(t.TempDir(), "script")
+			if err := os.WriteFile(scriptPath, []byte(script(p, tc.extra, tc.args)), 0700); err != nil {
+				t.Fatal(err)
+			}
+			out, err := exec.Command(sh, scriptPath, "trailing arg").Output()
+			if err != nil {
+				t.Fatalf("running script: %v", err)
+			}
+			envOut, argsOut, _ := strings.Cut(string(out), "--- args\n")
+
+			// Compare only the variables skint manages
+			managed := map[string]bool{}
+			for _, k := range ConflictingEnvVars {
+				managed[k] = true
+			}
+			for k := range tc.extra {
+				managed[k] = true
+			}
+			var gotEnv, wantEnv []string
+			for _, e := range strings.Split(strings.TrimSpace(envOut), "\n") {
+				if name, _, _ := strings.Cut(e, "="); managed[name] {
+					gotEnv = append(gotEnv, e)
+				}
+			}
+			for _, e := range BuildEnv(p, tc.extra) {
+				if name, _, _ := strings.Cut(e, "="); managed[name] {
+					wantEnv = append(wantEnv, e)
+				}
+			}
+			slices.Sort(gotEnv)
+			slices.Sort(wantEnv)
+			envEqual(t, gotEnv, wantEnv)
+
+			gotArgs := strings.Split(strings.TrimSuffix(argsOut, "\n"), "\n")
+			wantArgs := append(append(p.Target().Args, tc.args...), "trailing arg")
+			if !slices.Equal(gotArgs, wantArgs) {
+				t.Errorf("args: got %q, want %q", gotArgs, wantArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+
+package launcher
+
+import (
+	"os"
+	"syscall"
+)
+
+// forwardedSignals are passed on to Claude while skint waits for it.
+var forwardedSignals = []os.Signal{syscall.SIGTERM, syscall.SIGHUP}
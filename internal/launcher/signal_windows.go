@@ -0,0 +1,9 @@
+//go:build windows
+
+package launcher
+
+import "os"
+
+// forwardedSignals is empty on Windows: console close and shutdown events
+// reach Claude directly, and only os.Kill can be sent to a process.
+var forwardedSignals []os.Signal
@@ -0,0 +1,76 @@
+package launcher
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// watchdogWindow is how soon after starting Claude must exit for the
+// watchdog to blame the provider; later exits are the session ending.
+const watchdogWindow = 30 * time.Second
+
+// watchdogTail is how much of Claude's error output the watchdog keeps.
+const watchdogTail = 8 << 10
+
+// providerFailurePattern matches error output that points at the provider
+// rather than Claude: rejected credentials or an endpoint that can't be
+// reached.
+var providerFailurePattern = regexp.MustCompile(`(?i)\b(401|403)\b|unauthori[sz]ed|forbidden|invalid (x-)?api[ _-]?key|authentication|auth token|econnrefused|connection refused|econnreset|connection reset|enotfound|no such host|getaddrinfo|etimedout|timed out|unable to connect|fetch failed|network error`)
+
+// ProviderFailedError is returned by Launch when the watchdog is on and
+// Claude exits soon after starting with an authentication or connection
+// error, so the caller can relaunch with another provider. It wraps Claude's
+// *exec.ExitError.
+type ProviderFailedError struct {
+	Provider string
+	// Reason is the line of Claude's error output that matched
+	Reason string
+	Err    error
+}
+
+func (e *ProviderFailedError) Error() string {
+	return fmt.Sprintf("%s failed: %s", e.Provider, e.Reason)
+}
+
+func (e *ProviderFailedError) Unwrap() error {
+	return e.Err
+}
+
+// providerFailure returns the last line of output that looks like a
+// provider failure, or "" if none does.
+func providerFailure(output string) string {
+	lines := strings.Split(output, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if providerFailurePattern.MatchString(line) {
+			return line
+		}
+	}
+	return ""
+}
+
+// tailBuffer keeps the last max bytes written to it.
+type tailBuffer struct {
+	mu  sync.Mutex
+	buf []byte
+	max int
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.buf = append(t.buf, p...)
+	if over := len(t.buf) - t.max; over > 0 {
+		t.buf = t.buf[over:]
+	}
+	return len(p), nil
+}
+
+func (t *tailBuffer) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return string(t.buf)
+}
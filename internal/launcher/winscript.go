@@ -0,0 +1,186 @@
+package launcher
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/sammcj/skint/internal/providers"
+)
+
+// scriptVars returns the variables a wrapper script sets for provider and
+// extra, and the inherited ones it clears, as BuildEnv does. Those in keep
+// are only set if the environment the script runs in doesn't have them.
+func scriptVars(provider providers.Provider, extra map[string]string) (vars map[string]string, unset []string, keep map[string]bool) {
+	vars = provider.GetEnvVars()
+	maps.Copy(vars, extra)
+	keep = map[string]bool{}
+	for _, k := range provider.PreserveEnv() {
+		if _, ok := extra[k]; !ok {
+			keep[k] = true
+		}
+	}
+	for _, k := range conflictingEnvVars {
+		if _, ok := vars[k]; !ok && !keep[k] {
+			unset = append(unset, k)
+		}
+	}
+	return vars, unset, keep
+}
+
+// PowerShellScript returns a PowerShell wrapper script that launches claude
+// (or the provider's target) with provider the same way Launch does.
+// PowerShell runs scripts in the calling session, so the variables are put
+// back as they were when claude exits.
+func PowerShellScript(provider providers.Provider, extra map[string]string, args []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `# Generated by Skint - Multi-provider launcher for Claude CLI
+if ($env:SKINT_NO_BANNER -ne '1') {
+  Write-Host '    + %s'
+  Write-Host
+}
+
+# Environment variables to set ($null clears them)
+$vars = [ordered]@{
+`, psQuote(provider.DisplayName()))
+
+	vars, unset, keep := scriptVars(provider, extra)
+	for _, k := range unset {
+		fmt.Fprintf(&b, "  '%s' = $null\n", k)
+	}
+	var kept []string
+	for _, k := range slices.Sorted(maps.Keys(vars)) {
+		if keep[k] {
+			kept = append(kept, "'"+k+"'")
+		}
+		if vars[k] == "" {
+			// Windows has no empty variables: setting one removes it
+			fmt.Fprintf(&b, "  '%s' = $null\n", k)
+			continue
+		}
+		fmt.Fprintf(&b, "  '%s' = '%s'\n", k, psQuote(vars[k]))
+	}
+	b.WriteString("}\n")
+	if len(kept) > 0 {
+		fmt.Fprintf(&b, `# Preserved variables keep the values they already have
+foreach ($name in @(%s)) {
+  if ($null -ne [Environment]::GetEnvironmentVariable($name)) { $vars.Remove($name) }
+}
+`, strings.Join(kept, ", "))
+	}
+
+	b.WriteString(`$saved = @{}
+foreach ($name in $vars.Keys) {
+  $saved[$name] = [Environment]::GetEnvironmentVariable($name)
+  [Environment]::SetEnvironmentVariable($name, $vars[$name])
+}
+try {
+  & `)
+	command, targetArgs := scriptCommand(provider, func(s string) string { return "'" + psQuote(s) + "'" })
+	b.WriteString(command)
+	for _, arg := range append(targetArgs, args...) {
+		fmt.Fprintf(&b, " '%s'", psQuote(arg))
+	}
+	b.WriteString(` @args
+} finally {
+  foreach ($name in $saved.Keys) {
+    [Environment]::SetEnvironmentVariable($name, $saved[$name])
+  }
+}
+exit $LASTEXITCODE
+`)
+	return b.String()
+}
+
+// CmdScript returns a batch file that launches claude (or the provider's
+// target) with provider the same way Launch does, for cmd.exe and anything
+// else that runs .cmd files. It fails if a value has a line break, which
+// batch files can't hold.
+func CmdScript(provider providers.Provider, extra map[string]string, args []string) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, `@echo off
+rem Generated by Skint - Multi-provider launcher for Claude CLI
+setlocal
+
+if not "%%SKINT_NO_BANNER%%"=="1" (
+  echo(    + %s
+  echo(
+)
+
+rem Set environment variables
+`, cmdEscape(provider.DisplayName()))
+
+	vars, unset, keep := scriptVars(provider, extra)
+	for _, k := range unset {
+		fmt.Fprintf(&b, "set \"%s=\"\n", k)
+	}
+	for _, k := range slices.Sorted(maps.Keys(vars)) {
+		v := vars[k]
+		if strings.ContainsAny(v, "\r\n") {
+			return "", fmt.Errorf("%s has a line break, which batch files can't hold", k)
+		}
+		if keep[k] {
+			b.WriteString("if not defined " + k + " ")
+		}
+		fmt.Fprintf(&b, "set \"%s=%s\"\n", k, strings.ReplaceAll(v, "%", "%%"))
+	}
+
+	command, targetArgs := scriptCommand(provider, cmdQuote)
+	b.WriteString("\n" + command)
+	for _, arg := range append(targetArgs, args...) {
+		if strings.ContainsAny(arg, "\r\n") {
+			return "", fmt.Errorf("argument %q has a line break, which batch files can't hold", arg)
+		}
+		b.WriteString(" " + cmdQuote(arg))
+	}
+	b.WriteString(" %*\nexit /b %ERRORLEVEL%\n")
+	return b.String(), nil
+}
+
+// psQuote escapes s for a single-quoted PowerShell string.
+func psQuote(s string) string {
+	return strings.ReplaceAll(s, "'", "''")
+}
+
+// cmdEscape escapes s for an unquoted echo in a batch file.
+func cmdEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '^', '&', '|', '<', '>', '(', ')':
+			b.WriteRune('^')
+		case '%':
+			b.WriteRune('%')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// cmdQuote quotes arg for a command line in a batch file, so the program
+// receives it unchanged under the usual Windows argument rules: quotes are
+// doubled, backslashes before them too, and % is escaped for cmd.
+func cmdQuote(arg string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	backslashes := 0
+	for _, r := range arg {
+		switch r {
+		case '\\':
+			backslashes++
+			b.WriteRune(r)
+			continue
+		case '"':
+			b.WriteString(strings.Repeat(`\`, backslashes) + `""`)
+		case '%':
+			b.WriteString("%%")
+		default:
+			b.WriteRune(r)
+		}
+		backslashes = 0
+	}
+	// A closing quote after backslashes would be escaped by them
+	b.WriteString(strings.Repeat(`\`, backslashes) + `"`)
+	return b.String()
+}
@@ -0,0 +1,99 @@
+// Package logging sets up skint's debug log, for finding out what happened
+// during a failed launch or fetch. It is off unless SKINT_LOG or --log-file
+// turns it on, and is written through log/slog's default logger, so packages
+// log with slog.Debug, slog.Info and slog.Warn without depending on this one.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Levels are the values SKINT_LOG accepts.
+var Levels = []string{"debug", "info", "warn", "error", "off"}
+
+// file is the open --log-file, if any.
+var file *os.File
+
+// terminal is whether the log is being written to stderr.
+var terminal bool
+
+// ParseLevel parses a SKINT_LOG value. ok is false for "off".
+func ParseLevel(s string) (level slog.Level, ok bool, err error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return slog.LevelDebug, true, nil
+	case "info":
+		return slog.LevelInfo, true, nil
+	case "warn", "warning":
+		return slog.LevelWarn, true, nil
+	case "error":
+		return slog.LevelError, true, nil
+	case "off", "":
+		return 0, false, nil
+	}
+	return 0, false, fmt.Errorf("invalid log level %q: use %s", s, strings.Join(Levels, ", "))
+}
+
+// Setup points slog's default logger at path, appending to it, or at stderr
+// if path is "". An empty level logs nothing to stderr and everything
+// (debug) to a file. Calling it again replaces the previous setup.
+func Setup(level, path string) error {
+	Close()
+
+	if level == "" && path != "" {
+		level = "debug"
+	}
+	lvl, ok, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		slog.SetDefault(slog.New(slog.DiscardHandler))
+		return nil
+	}
+
+	var w io.Writer = os.Stderr
+	if path != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return fmt.Errorf("failed to create log directory: %w", err)
+		}
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+		if err != nil {
+			return fmt.Errorf("failed to open log file: %w", err)
+		}
+		file, w = f, f
+	}
+	terminal = path == ""
+
+	logger := slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: lvl}))
+	if file != nil {
+		// Runs append to the same file, sometimes at the same time
+		logger = logger.With("pid", os.Getpid())
+	}
+	slog.SetDefault(logger)
+	return nil
+}
+
+// StopTerminal turns the log off if it is being written to stderr, for
+// screens such as the TUI that it would garble. A log file is kept.
+func StopTerminal() {
+	if terminal {
+		terminal = false
+		slog.SetDefault(slog.New(slog.DiscardHandler))
+	}
+}
+
+// Close closes the log file, if any, and turns the log off.
+func Close() {
+	slog.SetDefault(slog.New(slog.DiscardHandler))
+	terminal = false
+	if file != nil {
+		_ = file.Close()
+		file = nil
+	}
+}
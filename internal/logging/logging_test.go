@@ -0,0 +1,78 @@
+package logging
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    slog.Level
+		ok      bool
+		wantErr bool
+	}{
+		{in: "debug", want: slog.LevelDebug, ok: true},
+		{in: "INFO", want: slog.LevelInfo, ok: true},
+		{in: "warning", want: slog.LevelWarn, ok: true},
+		{in: "error", want: slog.LevelError, ok: true},
+		{in: "off"},
+		{in: ""},
+		{in: "loud", wantErr: true},
+	}
+	for _, tc := range tests {
+		got, ok, err := ParseLevel(tc.in)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("ParseLevel(%q) error = %v, wantErr %v", tc.in, err, tc.wantErr)
+			continue
+		}
+		if ok != tc.ok || (ok && got != tc.want) {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v, %v", tc.in, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestSetupFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "skint.log")
+
+	// A file with no level logs everything
+	if err := Setup("", path); err != nil {
+		t.Fatal(err)
+	}
+	slog.Debug("first")
+	Close()
+
+	// A level filters, and runs append
+	if err := Setup("warn", path); err != nil {
+		t.Fatal(err)
+	}
+	slog.Info("dropped")
+	slog.Warn("second")
+	Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log := string(data)
+	for _, want := range []string{"msg=first", "msg=second", "pid="} {
+		if !strings.Contains(log, want) {
+			t.Errorf("log missing %q:\n%s", want, log)
+		}
+	}
+	if strings.Contains(log, "dropped") {
+		t.Errorf("log has a message below its level:\n%s", log)
+	}
+	if info, err := os.Stat(path); err == nil && info.Mode().Perm() != 0o600 {
+		t.Errorf("log file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	// Logging after Close goes nowhere
+	slog.Error("after close")
+	if data, _ := os.ReadFile(path); strings.Contains(string(data), "after close") {
+		t.Error("log written after Close")
+	}
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// cacheEntry is a provider's model list as cached on disk.
+type cacheEntry struct {
+	BaseURL string      `json:"base_url"`
+	Fetched time.Time   `json:"fetched"`
+	Models  []ModelInfo `json:"models"`
+}
+
+// cachePath returns where provider's models are cached in dir.
+func cachePath(dir, provider string) string {
+	return filepath.Join(dir, "models", url.PathEscape(provider)+".json")
+}
+
+// LoadCache returns provider's models cached in dir, and when they were
+// fetched, if they were fetched from baseURL less than maxAge ago.
+func LoadCache(dir, provider, baseURL string, maxAge time.Duration) ([]ModelInfo, time.Time, bool) {
+	models, fetched, ok := ReadCache(dir, provider, baseURL)
+	if !ok || time.Since(fetched) >= maxAge {
+		return nil, time.Time{}, false
+	}
+	return models, fetched, true
+}
+
+// ReadCache returns provider's models cached in dir, however old, and when
+// they were fetched, if they were fetched from baseURL.
+func ReadCache(dir, provider, baseURL string) ([]ModelInfo, time.Time, bool) {
+	data, err := os.ReadFile(cachePath(dir, provider))
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.BaseURL != baseURL {
+		return nil, time.Time{}, false
+	}
+	return entry.Models, entry.Fetched, true
+}
+
+// SaveCache caches provider's models, fetched from baseURL, in dir.
+func SaveCache(dir, provider, baseURL string, models []ModelInfo) error {
+	path := cachePath(dir, provider)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	data, err := json.Marshal(cacheEntry{BaseURL: baseURL, Fetched: time.Now(), Models: models})
+	if err != nil {
+		return err
+	}
+	// Write then rename, so a concurrent reader never sees half a file
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write model cache: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write model cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	dir := t.TempDir()
+	const base = "http://localhost:11434"
+
+	if _, _, ok := LoadCache(dir, "ollama", base, time.Hour); ok {
+		t.Fatal("empty cache should miss")
+	}
+
+	want := []ModelInfo{{ID: "qwen3", ContextLength: 32768, Pricing: &Pricing{Input: 1, Output: 2}}}
+	if err := SaveCache(dir, "ollama", base, want); err != nil {
+		t.Fatal(err)
+	}
+	got, fetched, ok := LoadCache(dir, "ollama", base, time.Hour)
+	if !ok || len(got) != 1 || got[0].ID != "qwen3" || got[0].Pricing == nil || got[0].Pricing.Output != 2 {
+		t.Fatalf("LoadCache = %+v, %v; want the saved models", got, ok)
+	}
+	if time.Since(fetched) > time.Minute {
+		t.Errorf("fetched = %v, want about now", fetched)
+	}
+
+	if _, _, ok := LoadCache(dir, "ollama", "http://other:11434", time.Hour); ok {
+		t.Error("a different base URL should miss")
+	}
+	if _, _, ok := LoadCache(dir, "ollama", base, 0); ok {
+		t.Error("an expired entry should miss")
+	}
+	if _, _, ok := LoadCache(dir, "lmstudio", base, time.Hour); ok {
+		t.Error("another provider should miss")
+	}
+
+	// Expired entries can still be read, to show while fetching
+	if got, _, ok := ReadCache(dir, "ollama", base); !ok || len(got) != 1 {
+		t.Errorf("ReadCache = %+v, %v; want the saved models", got, ok)
+	}
+	if _, _, ok := ReadCache(dir, "ollama", "http://other:11434"); ok {
+		t.Error("ReadCache from a different base URL should miss")
+	}
+}
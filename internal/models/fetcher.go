@@ -2,20 +2,59 @@ package models
 
 import (
 	"cmp"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
+	"math"
 	"net/http"
+	"net/url"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/sammcj/skint/internal/httpretry"
 )
 
 // ModelInfo represents a model available from a provider.
 type ModelInfo struct {
-	ID          string
-	DisplayName string // optional, falls back to ID
-	Created     int64  // unix timestamp, 0 if unknown
+	ID          string `json:"id"`
+	DisplayName string `json:"display_name,omitempty"` // optional, falls back to ID
+	Created     int64  `json:"created,omitempty"`      // unix timestamp, 0 if unknown
+
+	// Metadata, only reported by some providers (OpenRouter)
+	ContextLength int      `json:"context_length,omitempty"` // tokens, 0 if unknown
+	Pricing       *Pricing `json:"pricing,omitempty"`        // nil if unknown
+	Modality      string   `json:"modality,omitempty"`       // e.g. "text+image->text", "" if unknown
+
+	Capabilities *Capabilities `json:"capabilities,omitempty"` // nil if unknown
+
+	// Local server details, only reported by some providers (LM Studio, Ollama)
+	ParameterSize string `json:"parameter_size,omitempty"` // e.g. "30.5B", "" if unknown
+	Quantization  string `json:"quantization,omitempty"`   // e.g. "Q4_K_M", "" if unknown
+	State         string `json:"state,omitempty"`          // StateLoaded or StateNotLoaded, "" if unknown
+}
+
+// Model load states reported by local servers
+const (
+	StateLoaded    = "loaded"
+	StateNotLoaded = "not-loaded"
+)
+
+// Capabilities are the features a model supports, as its provider reports
+// them.
+type Capabilities struct {
+	Tools     bool `json:"tools"`     // tool calling, which Claude Code needs
+	Vision    bool `json:"vision"`    // image input
+	Reasoning bool `json:"reasoning"` // extended thinking
+}
+
+// Pricing is a model's price in USD per million tokens.
+type Pricing struct {
+	Input  float64 `json:"input"`
+	Output float64 `json:"output"`
 }
 
 // Label returns the display name if set, otherwise the ID.
@@ -26,34 +65,116 @@ func (m ModelInfo) Label() string {
 	return m.ID
 }
 
+// NotLoaded reports whether the server is known not to have the model
+// loaded.
+func (m ModelInfo) NotLoaded() bool {
+	return m.State == StateNotLoaded
+}
+
+// LacksTools reports whether the model is known not to support tool
+// calling. Models whose capabilities are unknown may support it.
+func (m ModelInfo) LacksTools() bool {
+	return m.Capabilities != nil && !m.Capabilities.Tools
+}
+
+// ToolCapable returns the models in list that aren't known to lack tool
+// calling.
+func ToolCapable(list []ModelInfo) []ModelInfo {
+	return slices.DeleteFunc(slices.Clone(list), ModelInfo.LacksTools)
+}
+
+// FormatCapabilities lists capabilities, e.g. "tools,vision", or "" if
+// they are unknown or there are none.
+func FormatCapabilities(c *Capabilities) string {
+	if c == nil {
+		return ""
+	}
+	var names []string
+	for _, f := range []struct {
+		name string
+		ok   bool
+	}{{"tools", c.Tools}, {"vision", c.Vision}, {"reasoning", c.Reasoning}} {
+		if f.ok {
+			names = append(names, f.name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// FormatState describes a load state, e.g. "not loaded".
+func FormatState(state string) string {
+	return strings.ReplaceAll(state, "-", " ")
+}
+
+// FormatContext formats a context length in tokens, e.g. "128k" or "1M".
+func FormatContext(tokens int) string {
+	switch {
+	case tokens <= 0:
+		return ""
+	case tokens >= 1_000_000:
+		return strconv.FormatFloat(math.Round(float64(tokens)/1e5)/10, 'f', -1, 64) + "M"
+	case tokens >= 1000:
+		return strconv.Itoa((tokens+500)/1000) + "k"
+	}
+	return strconv.Itoa(tokens)
+}
+
+// FormatPricing formats input and output prices per million tokens, e.g.
+// "$3/$15", or "free".
+func FormatPricing(p *Pricing) string {
+	if p == nil {
+		return ""
+	}
+	if p.Input == 0 && p.Output == 0 {
+		return "free"
+	}
+	price := func(v float64) string {
+		return "$" + strconv.FormatFloat(math.Round(v*1000)/1000, 'f', -1, 64)
+	}
+	return price(p.Input) + "/" + price(p.Output)
+}
+
 // FetchResult holds the result of a model fetch operation.
 type FetchResult struct {
 	Models []ModelInfo
 	Err    error
 }
 
-// fetchTimeout is the HTTP client timeout for model fetches.
+// fetchTimeout bounds model fetches whose context has no deadline.
 const fetchTimeout = 5 * time.Second
 
-// FetchModels fetches available models from a provider endpoint.
-// The strategy is determined by provider name and type.
-func FetchModels(baseURL, apiKey, providerName string) FetchResult {
+// FetchModels fetches available models from a provider endpoint, giving up
+// when ctx is done, or after fetchTimeout if ctx has no deadline. The
+// strategy is determined by provider name and type.
+func FetchModels(ctx context.Context, baseURL, apiKey, providerName string) FetchResult {
 	strategy := selectStrategy(baseURL, providerName)
 	if strategy == nil {
 		return FetchResult{}
 	}
-	return strategy(baseURL, apiKey)
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, fetchTimeout)
+		defer cancel()
+	}
+	start := time.Now()
+	result := strategy(ctx, baseURL, apiKey)
+	slog.Debug("fetched models", "provider", providerName, "base_url", baseURL, "models", len(result.Models), "duration", time.Since(start), "err", result.Err)
+	return result
 }
 
-type fetchFunc func(baseURL, apiKey string) FetchResult
+type fetchFunc func(ctx context.Context, baseURL, apiKey string) FetchResult
 
 func selectStrategy(baseURL, providerName string) fetchFunc {
 	switch providerName {
-	case "native", "anthropic":
-		// Anthropic models are well known; no listing endpoint needed.
+	case "native":
+		// The Claude subscription has no API key to list models with
 		return nil
+	case "anthropic":
+		return fetchAnthropic
 	case "ollama":
 		return fetchOllama
+	case "lmstudio":
+		return fetchLMStudio
 	case "openrouter":
 		return fetchOpenRouter
 	case "llamacpp":
@@ -68,7 +189,7 @@ func selectStrategy(baseURL, providerName string) fetchFunc {
 }
 
 // fetchOpenAICompatible fetches models from an OpenAI-compatible /v1/models endpoint.
-func fetchOpenAICompatible(baseURL, apiKey string) FetchResult {
+func fetchOpenAICompatible(ctx context.Context, baseURL, apiKey string) FetchResult {
 	trimmed := strings.TrimRight(baseURL, "/")
 	var url string
 	if strings.HasSuffix(trimmed, "/v1") {
@@ -76,7 +197,7 @@ func fetchOpenAICompatible(baseURL, apiKey string) FetchResult {
 	} else {
 		url = trimmed + "/v1/models"
 	}
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return FetchResult{Err: fmt.Errorf("creating request: %w", err)}
 	}
@@ -89,8 +210,8 @@ func fetchOpenAICompatible(baseURL, apiKey string) FetchResult {
 
 // fetchOpenAICompatibleSilent is like fetchOpenAICompatible but returns empty on error
 // instead of propagating the error (for providers that may not support the endpoint).
-func fetchOpenAICompatibleSilent(baseURL, apiKey string) FetchResult {
-	result := fetchOpenAICompatible(baseURL, apiKey)
+func fetchOpenAICompatibleSilent(ctx context.Context, baseURL, apiKey string) FetchResult {
+	result := fetchOpenAICompatible(ctx, baseURL, apiKey)
 	if result.Err != nil {
 		return FetchResult{}
 	}
@@ -98,8 +219,7 @@ func fetchOpenAICompatibleSilent(baseURL, apiKey string) FetchResult {
 }
 
 func doOpenAIModelsRequest(req *http.Request) FetchResult {
-	client := &http.Client{Timeout: fetchTimeout}
-	resp, err := client.Do(req)
+	resp, err := httpretry.Do(http.DefaultClient, req)
 	if err != nil {
 		return FetchResult{Err: fmt.Errorf("fetching models: %w", err)}
 	}
@@ -135,11 +255,145 @@ func doOpenAIModelsRequest(req *http.Request) FetchResult {
 	return FetchResult{Models: models}
 }
 
+// anthropicBaseURL is where the Anthropic API is when no base URL is given.
+const anthropicBaseURL = "https://api.anthropic.com"
+
+// fetchAnthropic fetches models from the Anthropic /v1/models endpoint,
+// following its pages. Without an API key there is nothing to list with.
+func fetchAnthropic(ctx context.Context, baseURL, apiKey string) FetchResult {
+	if apiKey == "" {
+		return FetchResult{}
+	}
+	if baseURL == "" {
+		baseURL = anthropicBaseURL
+	}
+	endpoint := strings.TrimSuffix(strings.TrimRight(baseURL, "/"), "/v1") + "/v1/models?limit=1000"
+
+	var models []ModelInfo
+	afterID := ""
+	for range 10 {
+		pageURL := endpoint
+		if afterID != "" {
+			pageURL += "&after_id=" + afterID
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+		if err != nil {
+			return FetchResult{Err: fmt.Errorf("creating request: %w", err)}
+		}
+		req.Header.Set("x-api-key", apiKey)
+		req.Header.Set("anthropic-version", "2023-06-01")
+		resp, err := httpretry.Do(http.DefaultClient, req)
+		if err != nil {
+			return FetchResult{Err: fmt.Errorf("fetching anthropic models: %w", err)}
+		}
+		body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return FetchResult{Err: fmt.Errorf("anthropic models endpoint returned status %d", resp.StatusCode)}
+		}
+		if err != nil {
+			return FetchResult{Err: fmt.Errorf("reading anthropic response: %w", err)}
+		}
+
+		var response struct {
+			Data []struct {
+				ID          string `json:"id"`
+				DisplayName string `json:"display_name"`
+				CreatedAt   string `json:"created_at"`
+			} `json:"data"`
+			HasMore bool   `json:"has_more"`
+			LastID  string `json:"last_id"`
+		}
+		if err := json.Unmarshal(body, &response); err != nil {
+			return FetchResult{Err: fmt.Errorf("parsing anthropic response: %w", err)}
+		}
+		for _, m := range response.Data {
+			if m.ID == "" {
+				continue
+			}
+			var created int64
+			if t, err := time.Parse(time.RFC3339, m.CreatedAt); err == nil {
+				created = t.Unix()
+			}
+			models = append(models, ModelInfo{ID: m.ID, DisplayName: m.DisplayName, Created: created})
+		}
+		if !response.HasMore || response.LastID == "" {
+			break
+		}
+		afterID = url.QueryEscape(response.LastID)
+	}
+
+	sortModels(models)
+	return FetchResult{Models: models}
+}
+
+// fetchLMStudio fetches models from LM Studio's native /api/v0/models
+// endpoint, which also reports their load state, quantisation and context
+// length. Embedding models are left out. Versions of LM Studio without the
+// endpoint are asked through the OpenAI-compatible one instead.
+func fetchLMStudio(ctx context.Context, baseURL, apiKey string) FetchResult {
+	url := strings.TrimSuffix(strings.TrimRight(baseURL, "/"), "/v1") + "/api/v0/models"
+	resp, err := get(ctx, url)
+	if err != nil {
+		return FetchResult{Err: fmt.Errorf("fetching lmstudio models: %w", err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fetchOpenAICompatible(ctx, baseURL, apiKey)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return FetchResult{Err: fmt.Errorf("lmstudio models endpoint returned status %d", resp.StatusCode)}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return FetchResult{Err: fmt.Errorf("reading lmstudio response: %w", err)}
+	}
+
+	var response struct {
+		Data []struct {
+			ID               string   `json:"id"`
+			Type             string   `json:"type"`
+			Quantization     string   `json:"quantization"`
+			State            string   `json:"state"`
+			MaxContextLength int      `json:"max_context_length"`
+			Capabilities     []string `json:"capabilities"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return FetchResult{Err: fmt.Errorf("parsing lmstudio response: %w", err)}
+	}
+
+	models := make([]ModelInfo, 0, len(response.Data))
+	for _, m := range response.Data {
+		if m.ID == "" || m.Type == "embeddings" {
+			continue
+		}
+		info := ModelInfo{
+			ID:            m.ID,
+			ContextLength: m.MaxContextLength,
+			Quantization:  m.Quantization,
+			State:         m.State,
+		}
+		if m.Capabilities != nil {
+			// Only newer versions list capabilities
+			info.Capabilities = &Capabilities{
+				Tools:  slices.Contains(m.Capabilities, "tool_use"),
+				Vision: m.Type == "vlm",
+			}
+		}
+		models = append(models, info)
+	}
+
+	sortModels(models)
+	return FetchResult{Models: models}
+}
+
 // fetchOllama fetches models from the Ollama /api/tags endpoint.
-func fetchOllama(baseURL, _ string) FetchResult {
+func fetchOllama(ctx context.Context, baseURL, _ string) FetchResult {
 	url := strings.TrimRight(baseURL, "/") + "/api/tags"
-	client := &http.Client{Timeout: fetchTimeout}
-	resp, err := client.Get(url)
+	resp, err := get(ctx, url)
 	if err != nil {
 		return FetchResult{Err: fmt.Errorf("fetching ollama models: %w", err)}
 	}
@@ -158,6 +412,10 @@ func fetchOllama(baseURL, _ string) FetchResult {
 		Models []struct {
 			Name       string `json:"name"`
 			ModifiedAt string `json:"modified_at"`
+			Details    struct {
+				ParameterSize     string `json:"parameter_size"`
+				QuantizationLevel string `json:"quantization_level"`
+			} `json:"details"`
 		} `json:"models"`
 	}
 	if err := json.Unmarshal(body, &response); err != nil {
@@ -171,7 +429,12 @@ func fetchOllama(baseURL, _ string) FetchResult {
 			if t, err := time.Parse(time.RFC3339Nano, m.ModifiedAt); err == nil {
 				created = t.Unix()
 			}
-			models = append(models, ModelInfo{ID: m.Name, Created: created})
+			models = append(models, ModelInfo{
+				ID:            m.Name,
+				Created:       created,
+				ParameterSize: m.Details.ParameterSize,
+				Quantization:  m.Details.QuantizationLevel,
+			})
 		}
 	}
 
@@ -181,13 +444,12 @@ func fetchOllama(baseURL, _ string) FetchResult {
 
 // fetchOpenRouter fetches models from the OpenRouter models endpoint.
 // Falls back to the public endpoint if baseURL is empty.
-func fetchOpenRouter(baseURL string, _ string) FetchResult {
+func fetchOpenRouter(ctx context.Context, baseURL string, _ string) FetchResult {
 	url := "https://openrouter.ai/api/v1/models"
 	if baseURL != "" {
 		url = strings.TrimRight(baseURL, "/") + "/v1/models"
 	}
-	client := &http.Client{Timeout: fetchTimeout}
-	resp, err := client.Get(url)
+	resp, err := get(ctx, url)
 	if err != nil {
 		return FetchResult{Err: fmt.Errorf("fetching openrouter models: %w", err)}
 	}
@@ -204,9 +466,19 @@ func fetchOpenRouter(baseURL string, _ string) FetchResult {
 
 	var response struct {
 		Data []struct {
-			ID      string `json:"id"`
-			Name    string `json:"name"`
-			Created int64  `json:"created"`
+			ID            string `json:"id"`
+			Name          string `json:"name"`
+			Created       int64  `json:"created"`
+			ContextLength int    `json:"context_length"`
+			Pricing       struct {
+				Prompt     string `json:"prompt"`
+				Completion string `json:"completion"`
+			} `json:"pricing"`
+			Architecture struct {
+				Modality        string   `json:"modality"`
+				InputModalities []string `json:"input_modalities"`
+			} `json:"architecture"`
+			SupportedParameters []string `json:"supported_parameters"`
 		} `json:"data"`
 	}
 	if err := json.Unmarshal(body, &response); err != nil {
@@ -216,7 +488,15 @@ func fetchOpenRouter(baseURL string, _ string) FetchResult {
 	models := make([]ModelInfo, 0, len(response.Data))
 	for _, m := range response.Data {
 		if m.ID != "" {
-			models = append(models, ModelInfo{ID: m.ID, DisplayName: m.Name, Created: m.Created})
+			models = append(models, ModelInfo{
+				ID:            m.ID,
+				DisplayName:   m.Name,
+				Created:       m.Created,
+				ContextLength: m.ContextLength,
+				Pricing:       perMillionTokens(m.Pricing.Prompt, m.Pricing.Completion),
+				Modality:      m.Architecture.Modality,
+				Capabilities:  openRouterCapabilities(m.SupportedParameters, m.Architecture.InputModalities),
+			})
 		}
 	}
 
@@ -224,6 +504,45 @@ func fetchOpenRouter(baseURL string, _ string) FetchResult {
 	return FetchResult{Models: models}
 }
 
+// get sends a GET request for url, bounded by ctx and retried if it fails
+// transiently.
+func get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return httpretry.Do(http.DefaultClient, req)
+}
+
+// openRouterCapabilities returns a model's capabilities from OpenRouter's
+// supported request parameters and input modalities, or nil if it doesn't
+// list the parameters.
+func openRouterCapabilities(params, inputs []string) *Capabilities {
+	if len(params) == 0 {
+		return nil
+	}
+	return &Capabilities{
+		Tools:     slices.Contains(params, "tools"),
+		Vision:    slices.Contains(inputs, "image"),
+		Reasoning: slices.Contains(params, "reasoning"),
+	}
+}
+
+// perMillionTokens converts OpenRouter's per-token prices, given as decimal
+// strings, to a Pricing. Prices that are missing or negative (OpenRouter's
+// "varies", e.g. for its auto router) give nil.
+func perMillionTokens(input, output string) *Pricing {
+	in, err := strconv.ParseFloat(input, 64)
+	if err != nil || in < 0 {
+		return nil
+	}
+	out, err := strconv.ParseFloat(output, 64)
+	if err != nil || out < 0 {
+		return nil
+	}
+	return &Pricing{Input: in * 1e6, Output: out * 1e6}
+}
+
 // sortModels sorts by most recent first when timestamps are available,
 // falling back to alphabetical by ID.
 func sortModels(models []ModelInfo) {
@@ -1,10 +1,13 @@
 package models
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestFetchModels_OpenAICompatible(t *testing.T) {
@@ -29,7 +32,7 @@ func TestFetchModels_OpenAICompatible(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	result := FetchModels(srv.URL, "test-key", "some-provider")
+	result := FetchModels(context.Background(), srv.URL, "test-key", "some-provider")
 	if result.Err != nil {
 		t.Fatalf("unexpected error: %v", result.Err)
 	}
@@ -58,7 +61,7 @@ func TestFetchModels_OpenAICompatible_NoAuth(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	result := FetchModels(srv.URL, "", "lmstudio")
+	result := FetchModels(context.Background(), srv.URL, "", "lmstudio")
 	if result.Err != nil {
 		t.Fatalf("unexpected error: %v", result.Err)
 	}
@@ -67,6 +70,69 @@ func TestFetchModels_OpenAICompatible_NoAuth(t *testing.T) {
 	}
 }
 
+func TestFetchModels_LMStudio(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v0/models" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		resp := map[string]any{
+			"data": []map[string]any{
+				{"id": "qwen3-coder-30b", "type": "llm", "quantization": "Q4_K_M", "state": "loaded", "max_context_length": 262144, "capabilities": []string{"tool_use"}},
+				{"id": "gemma-3-12b", "type": "vlm", "quantization": "Q8_0", "state": "not-loaded", "max_context_length": 131072},
+				{"id": "nomic-embed-text", "type": "embeddings", "state": "not-loaded"},
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	// The configured base URL usually ends in /v1
+	result := FetchModels(context.Background(), srv.URL+"/v1", "", "lmstudio")
+	if result.Err != nil {
+		t.Fatalf("unexpected error: %v", result.Err)
+	}
+	byID := map[string]ModelInfo{}
+	for _, m := range result.Models {
+		byID[m.ID] = m
+	}
+	if len(byID) != 2 {
+		t.Fatalf("models = %v, want the two chat models", result.Models)
+	}
+	qwen, gemma := byID["qwen3-coder-30b"], byID["gemma-3-12b"]
+	if qwen.Quantization != "Q4_K_M" || qwen.ContextLength != 262144 || qwen.NotLoaded() {
+		t.Errorf("qwen3-coder-30b = %+v", qwen)
+	}
+	if qwen.Capabilities == nil || !qwen.Capabilities.Tools {
+		t.Errorf("qwen3-coder-30b capabilities = %v, want tools", qwen.Capabilities)
+	}
+	if !gemma.NotLoaded() || gemma.Quantization != "Q8_0" || gemma.Capabilities != nil {
+		t.Errorf("gemma-3-12b = %+v, want not loaded without capabilities", gemma)
+	}
+}
+
+func TestFetchModels_LMStudioFallback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/models" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[{"id":"local-model"}]}`))
+	}))
+	defer srv.Close()
+
+	result := FetchModels(context.Background(), srv.URL+"/v1", "", "lmstudio")
+	if result.Err != nil {
+		t.Fatalf("unexpected error: %v", result.Err)
+	}
+	if len(result.Models) != 1 || result.Models[0].ID != "local-model" || result.Models[0].State != "" {
+		t.Errorf("unexpected models: %v", result.Models)
+	}
+}
+
 func TestFetchModels_Ollama(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/api/tags" {
@@ -76,7 +142,7 @@ func TestFetchModels_Ollama(t *testing.T) {
 		}
 		resp := map[string]any{
 			"models": []map[string]any{
-				{"name": "qwen3-coder:latest", "modified_at": "2025-06-15T10:30:00.123456789Z"},
+				{"name": "qwen3-coder:latest", "modified_at": "2025-06-15T10:30:00.123456789Z", "details": map[string]string{"parameter_size": "30.5B", "quantization_level": "Q4_K_M"}},
 				{"name": "llama3.1:latest", "modified_at": "2025-07-01T08:00:00.5Z"},
 			},
 		}
@@ -85,7 +151,7 @@ func TestFetchModels_Ollama(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	result := FetchModels(srv.URL, "", "ollama")
+	result := FetchModels(context.Background(), srv.URL, "", "ollama")
 	if result.Err != nil {
 		t.Fatalf("unexpected error: %v", result.Err)
 	}
@@ -106,10 +172,13 @@ func TestFetchModels_Ollama(t *testing.T) {
 	if result.Models[1].Created == 0 {
 		t.Error("expected non-zero Created timestamp for qwen3-coder")
 	}
+	if got := result.Models[1]; got.ParameterSize != "30.5B" || got.Quantization != "Q4_K_M" {
+		t.Errorf("qwen3-coder details = %q %q, want 30.5B Q4_K_M", got.ParameterSize, got.Quantization)
+	}
 }
 
 func TestFetchModels_NativeSkipped(t *testing.T) {
-	result := FetchModels("", "", "native")
+	result := FetchModels(context.Background(), "", "", "native")
 	if result.Err != nil {
 		t.Errorf("unexpected error: %v", result.Err)
 	}
@@ -118,13 +187,58 @@ func TestFetchModels_NativeSkipped(t *testing.T) {
 	}
 }
 
-func TestFetchModels_AnthropicSkipped(t *testing.T) {
-	result := FetchModels("", "some-key", "anthropic")
+func TestFetchModels_AnthropicNoKey(t *testing.T) {
+	result := FetchModels(context.Background(), "", "", "anthropic")
 	if result.Err != nil {
 		t.Errorf("unexpected error: %v", result.Err)
 	}
 	if len(result.Models) != 0 {
-		t.Errorf("expected empty models for anthropic, got %v", result.Models)
+		t.Errorf("expected empty models for anthropic without a key, got %v", result.Models)
+	}
+}
+
+func TestFetchModels_Anthropic(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/models" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if got := r.Header.Get("x-api-key"); got != "sk-ant-test" {
+			t.Errorf("x-api-key = %q, want sk-ant-test", got)
+		}
+		if r.Header.Get("anthropic-version") == "" {
+			t.Error("anthropic-version header missing")
+		}
+		// Two pages
+		resp := map[string]any{
+			"data": []map[string]string{
+				{"id": "claude-opus-4-1-20250805", "display_name": "Claude Opus 4.1", "created_at": "2025-08-05T00:00:00Z"},
+			},
+			"has_more": true,
+			"last_id":  "claude-opus-4-1-20250805",
+		}
+		if r.URL.Query().Get("after_id") == "claude-opus-4-1-20250805" {
+			resp = map[string]any{
+				"data": []map[string]string{
+					{"id": "claude-sonnet-4-5-20250929", "display_name": "Claude Sonnet 4.5", "created_at": "2025-09-29T00:00:00Z"},
+				},
+				"has_more": false,
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	result := FetchModels(context.Background(), srv.URL, "sk-ant-test", "anthropic")
+	if result.Err != nil {
+		t.Fatalf("unexpected error: %v", result.Err)
+	}
+	if len(result.Models) != 2 {
+		t.Fatalf("got %d models, want 2", len(result.Models))
+	}
+	// Newest first
+	if m := result.Models[0]; m.ID != "claude-sonnet-4-5-20250929" || m.DisplayName != "Claude Sonnet 4.5" || m.Created == 0 {
+		t.Errorf("first model = %+v, want Claude Sonnet 4.5 with its date", m)
 	}
 }
 
@@ -135,7 +249,7 @@ func TestFetchModels_LlamaCppSilentFailure(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	result := FetchModels(srv.URL, "", "llamacpp")
+	result := FetchModels(context.Background(), srv.URL, "", "llamacpp")
 	if result.Err != nil {
 		t.Errorf("llamacpp should silently fail, got error: %v", result.Err)
 	}
@@ -150,15 +264,57 @@ func TestFetchModels_HTTPError(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	result := FetchModels(srv.URL, "bad-key", "some-provider")
+	result := FetchModels(context.Background(), srv.URL, "bad-key", "some-provider")
 	if result.Err == nil {
 		t.Error("expected error for 401 response")
 	}
 }
 
+func TestFetchModels_Timeout(t *testing.T) {
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(done)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	result := FetchModels(ctx, srv.URL, "", "some-provider")
+	if result.Err == nil {
+		t.Error("expected a timeout error")
+	}
+	if elapsed := time.Since(start); elapsed > fetchTimeout/2 {
+		t.Errorf("fetch took %v, want it to give up after the timeout given", elapsed)
+	}
+}
+
+func TestFetchModels_Cancel(t *testing.T) {
+	started := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+	result := FetchModels(ctx, srv.URL, "", "ollama")
+	if !errors.Is(result.Err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", result.Err)
+	}
+}
+
 func TestFetchModels_EmptyBaseURL(t *testing.T) {
 	// Unknown provider with no base URL should return empty
-	result := FetchModels("", "", "unknown-provider")
+	result := FetchModels(context.Background(), "", "", "unknown-provider")
 	if result.Err != nil {
 		t.Errorf("unexpected error: %v", result.Err)
 	}
@@ -180,7 +336,7 @@ func TestFetchModels_EmptyIDsFiltered(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	result := FetchModels(srv.URL, "", "minimax")
+	result := FetchModels(context.Background(), srv.URL, "", "minimax")
 	if result.Err != nil {
 		t.Fatalf("unexpected error: %v", result.Err)
 	}
@@ -224,7 +380,7 @@ func TestFetchModels_BaseURLWithV1Suffix(t *testing.T) {
 	defer srv.Close()
 
 	// Pass baseURL with /v1 suffix, as NVIDIA NIM and similar providers use.
-	result := FetchModels(srv.URL+"/v1", "nvapi-test-key", "nvidia")
+	result := FetchModels(context.Background(), srv.URL+"/v1", "nvapi-test-key", "nvidia")
 	if result.Err != nil {
 		t.Fatalf("unexpected error: %v", result.Err)
 	}
@@ -232,3 +388,70 @@ func TestFetchModels_BaseURLWithV1Suffix(t *testing.T) {
 		t.Errorf("unexpected models: %v", result.Models)
 	}
 }
+
+func TestFetchModels_OpenRouterMetadata(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data": [
+			{"id": "anthropic/claude-sonnet-4", "name": "Claude Sonnet 4", "context_length": 200000,
+			 "pricing": {"prompt": "0.000003", "completion": "0.000015"},
+			 "architecture": {"modality": "text+image->text", "input_modalities": ["text", "image"]},
+			 "supported_parameters": ["max_tokens", "tools", "reasoning"]},
+			{"id": "openrouter/auto", "pricing": {"prompt": "-1", "completion": "-1"}}
+		]}`))
+	}))
+	defer srv.Close()
+
+	result := FetchModels(context.Background(), srv.URL, "", "openrouter")
+	if result.Err != nil {
+		t.Fatalf("unexpected error: %v", result.Err)
+	}
+	if len(result.Models) != 2 {
+		t.Fatalf("got %d models, want 2", len(result.Models))
+	}
+	m := result.Models[0]
+	if m.ContextLength != 200000 || m.Modality != "text+image->text" {
+		t.Errorf("context = %d, modality = %q", m.ContextLength, m.Modality)
+	}
+	if m.Pricing == nil || m.Pricing.Input != 3 || m.Pricing.Output != 15 {
+		t.Errorf("pricing = %+v, want $3/$15 per million tokens", m.Pricing)
+	}
+	if result.Models[1].Pricing != nil {
+		t.Errorf("variable pricing = %+v, want nil", result.Models[1].Pricing)
+	}
+	if got := FormatCapabilities(m.Capabilities); got != "tools,vision,reasoning" {
+		t.Errorf("capabilities = %q, want tools,vision,reasoning", got)
+	}
+	if result.Models[1].Capabilities != nil {
+		t.Errorf("unlisted capabilities = %+v, want nil", result.Models[1].Capabilities)
+	}
+}
+
+func TestToolCapable(t *testing.T) {
+	list := []ModelInfo{
+		{ID: "tools", Capabilities: &Capabilities{Tools: true}},
+		{ID: "no-tools", Capabilities: &Capabilities{Vision: true}},
+		{ID: "unknown"},
+	}
+	got := ToolCapable(list)
+	if len(got) != 2 || got[0].ID != "tools" || got[1].ID != "unknown" {
+		t.Errorf("ToolCapable = %+v, want tools and unknown", got)
+	}
+	if len(list) != 3 || list[1].ID != "no-tools" {
+		t.Error("ToolCapable should not change its argument")
+	}
+}
+
+func TestFormatMetadata(t *testing.T) {
+	for tokens, want := range map[int]string{0: "", 512: "512", 131072: "131k", 200000: "200k", 1048576: "1M", 2000000: "2M"} {
+		if got := FormatContext(tokens); got != want {
+			t.Errorf("FormatContext(%d) = %q, want %q", tokens, got, want)
+		}
+	}
+	if got := FormatPricing(&Pricing{Input: 0.15, Output: 0.6}); got != "$0.15/$0.6" {
+		t.Errorf("FormatPricing = %q, want $0.15/$0.6", got)
+	}
+	if got := FormatPricing(&Pricing{}); got != "free" {
+		t.Errorf("FormatPricing(zero) = %q, want free", got)
+	}
+}
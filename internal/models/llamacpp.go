@@ -0,0 +1,99 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// LlamaCppStatus is what llama.cpp's llama-server reports about its model.
+type LlamaCppStatus struct {
+	// Loaded is set once the model is loaded and the server is ready
+	Loaded bool `json:"model_loaded"`
+	// Model is the loaded model's file name, "" if unknown
+	Model string `json:"model,omitempty"`
+	// ContextSize is the context size in tokens, 0 if unknown
+	ContextSize int `json:"context_size,omitempty"`
+	// StatusCode is the HTTP status of the /health endpoint
+	StatusCode int `json:"status_code"`
+}
+
+// String describes the status, e.g. "qwen3-coder.gguf loaded, 32k context".
+func (s LlamaCppStatus) String() string {
+	if !s.Loaded {
+		return "model not loaded yet"
+	}
+	desc := "model loaded"
+	if s.Model != "" {
+		desc = s.Model + " loaded"
+	}
+	if s.ContextSize > 0 {
+		desc += ", " + FormatContext(s.ContextSize) + " context"
+	}
+	return desc
+}
+
+// LlamaCppServerStatus asks the llama-server at baseURL whether its model is
+// loaded, from its /health endpoint, and what it is, from /props. It
+// returns an error if the server doesn't answer as llama-server does.
+func LlamaCppServerStatus(ctx context.Context, baseURL string) (LlamaCppStatus, error) {
+	base := strings.TrimSuffix(strings.TrimRight(baseURL, "/"), "/v1")
+	resp, err := getNoRetry(ctx, base+"/health")
+	if err != nil {
+		return LlamaCppStatus{}, fmt.Errorf("checking llama.cpp health: %w", err)
+	}
+	resp.Body.Close()
+
+	status := LlamaCppStatus{StatusCode: resp.StatusCode}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		status.Loaded = true
+	case http.StatusServiceUnavailable:
+		// Still loading the model
+		return status, nil
+	default:
+		return status, fmt.Errorf("llama.cpp health endpoint returned status %d", resp.StatusCode)
+	}
+
+	resp, err = getNoRetry(ctx, base+"/props")
+	if err != nil {
+		// Ready without details
+		return status, nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return status, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return status, nil
+	}
+	var props struct {
+		ModelPath string `json:"model_path"`
+		NCtx      int    `json:"n_ctx"` // older servers
+		Defaults  struct {
+			NCtx int `json:"n_ctx"`
+		} `json:"default_generation_settings"`
+	}
+	if err := json.Unmarshal(body, &props); err == nil {
+		if props.ModelPath != "" {
+			status.Model = filepath.Base(props.ModelPath)
+		}
+		status.ContextSize = max(props.Defaults.NCtx, props.NCtx)
+	}
+	return status, nil
+}
+
+// getNoRetry sends a GET request to url without retrying, for endpoints
+// whose errors, such as a 503 while loading, are answers.
+func getNoRetry(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return http.DefaultClient.Do(req)
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestLlamaCppServerStatus(t *testing.T) {
+	var loading atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/health":
+			if loading.Load() {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				fmt.Fprint(w, `{"error":{"code":503,"message":"Loading model","type":"unavailable_error"}}`)
+				return
+			}
+			fmt.Fprint(w, `{"status":"ok"}`)
+		case "/props":
+			fmt.Fprint(w, `{"model_path":"/models/qwen3-coder-30b-Q4_K_M.gguf","default_generation_settings":{"n_ctx":32768}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	// The configured base URL may end in /v1
+	status, err := LlamaCppServerStatus(context.Background(), srv.URL+"/v1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := LlamaCppStatus{Loaded: true, Model: "qwen3-coder-30b-Q4_K_M.gguf", ContextSize: 32768, StatusCode: http.StatusOK}
+	if status != want {
+		t.Errorf("status = %+v, want %+v", status, want)
+	}
+	if got := status.String(); got != "qwen3-coder-30b-Q4_K_M.gguf loaded, 33k context" {
+		t.Errorf("String() = %q", got)
+	}
+
+	loading.Store(true)
+	status, err = LlamaCppServerStatus(context.Background(), srv.URL)
+	if err != nil || status.Loaded || status.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("while loading: status %+v, err %v; want not loaded", status, err)
+	}
+}
+
+func TestLlamaCppServerStatus_NotLlamaServer(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	if _, err := LlamaCppServerStatus(context.Background(), srv.URL); err == nil {
+		t.Error("expected an error from a server without /health")
+	}
+}
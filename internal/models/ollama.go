@@ -0,0 +1,94 @@
+package models
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/sammcj/skint/internal/httpretry"
+)
+
+// OllamaHas reports whether list, fetched from Ollama, includes model. A
+// name without a tag means its latest tag, as it does for ollama run.
+func OllamaHas(list []ModelInfo, model string) bool {
+	model = ollamaName(model)
+	return slices.ContainsFunc(list, func(m ModelInfo) bool { return ollamaName(m.ID) == model })
+}
+
+// ollamaName returns model with the latest tag added when it has none.
+func ollamaName(model string) string {
+	model = strings.TrimSpace(model)
+	if i := strings.LastIndex(model, "/"); !strings.Contains(model[i+1:], ":") {
+		return model + ":latest"
+	}
+	return model
+}
+
+// PullProgress is an update Ollama streams while pulling a model.
+type PullProgress struct {
+	Status    string `json:"status"`
+	Total     int64  `json:"total"`     // bytes in the layer being pulled, 0 if none
+	Completed int64  `json:"completed"` // bytes of it pulled so far
+}
+
+// String describes the update, e.g. "pulling 6a0746a1ec1a 42%".
+func (p PullProgress) String() string {
+	if p.Total <= 0 {
+		return p.Status
+	}
+	return fmt.Sprintf("%s %d%%", p.Status, p.Completed*100/p.Total)
+}
+
+// PullOllama asks the Ollama server at baseURL to pull model, calling
+// progress with each update it streams. It returns once the pull is done,
+// fails or ctx is cancelled.
+func PullOllama(ctx context.Context, baseURL, model string, progress func(PullProgress)) error {
+	body, err := json.Marshal(map[string]any{"model": model, "stream": true})
+	if err != nil {
+		return err
+	}
+	url := strings.TrimRight(baseURL, "/") + "/api/pull"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := httpretry.Do(http.DefaultClient, req)
+	if err != nil {
+		return fmt.Errorf("pulling %s: %w", model, err)
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64<<10), 1<<20)
+	for scanner.Scan() {
+		var update struct {
+			PullProgress
+			Error string `json:"error"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &update); err != nil {
+			continue
+		}
+		if update.Error != "" {
+			return fmt.Errorf("pulling %s: %s", model, update.Error)
+		}
+		if progress != nil {
+			progress(update.PullProgress)
+		}
+		if update.Status == "success" {
+			return nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("pulling %s: %w", model, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("ollama pull endpoint returned status %d", resp.StatusCode)
+	}
+	return fmt.Errorf("pulling %s: ollama stopped before finishing", model)
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestOllamaHas(t *testing.T) {
+	list := []ModelInfo{{ID: "qwen3-coder:latest"}, {ID: "gpt-oss:20b"}, {ID: "hf.co/unsloth/glm-4.7:Q4_K_M"}}
+	for model, want := range map[string]bool{
+		"qwen3-coder":                  true,
+		"qwen3-coder:latest":           true,
+		"gpt-oss:20b":                  true,
+		"gpt-oss":                      false,
+		"gpt-oss:120b":                 false,
+		"hf.co/unsloth/glm-4.7:Q4_K_M": true,
+		"hf.co/unsloth/glm-4.7":        false,
+	} {
+		if got := OllamaHas(list, model); got != want {
+			t.Errorf("OllamaHas(%q) = %v, want %v", model, got, want)
+		}
+	}
+}
+
+func TestPullOllama(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/pull" || r.Method != http.MethodPost {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var req struct {
+			Model string `json:"model"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.Model == "missing" {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintln(w, `{"error":"pull model manifest: file does not exist"}`)
+			return
+		}
+		for _, line := range []string{
+			`{"status":"pulling manifest"}`,
+			`{"status":"pulling 6a0746a1ec1a","total":200,"completed":50}`,
+			`{"status":"pulling 6a0746a1ec1a","total":200,"completed":200}`,
+			`{"status":"success"}`,
+		} {
+			fmt.Fprintln(w, line)
+		}
+	}))
+	defer srv.Close()
+
+	var got []string
+	err := PullOllama(context.Background(), srv.URL, "qwen3-coder", func(p PullProgress) { got = append(got, p.String()) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"pulling manifest", "pulling 6a0746a1ec1a 25%", "pulling 6a0746a1ec1a 100%", "success"}
+	if !slices.Equal(got, want) {
+		t.Errorf("progress = %q, want %q", got, want)
+	}
+
+	err = PullOllama(context.Background(), srv.URL, "missing", nil)
+	if err == nil || !strings.Contains(err.Error(), "file does not exist") {
+		t.Errorf("err = %v, want Ollama's error", err)
+	}
+}
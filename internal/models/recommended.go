@@ -0,0 +1,101 @@
+package models
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/sammcj/skint/internal/httpretry"
+)
+
+// RecommendedURL is where RefreshRecommendations gets the latest
+// recommendations: recommended.json in this package, on the main branch.
+const RecommendedURL = "https://raw.githubusercontent.com/sammcj/skint/main/internal/models/recommended.json"
+
+// recommendedFile is where refreshed recommendations are cached.
+const recommendedFile = "recommended.json"
+
+// builtinRecommendations are the recommendations skint was built with.
+//
+//go:embed recommended.json
+var builtinRecommendations []byte
+
+// Recommendations maps provider names to the models recommended for use
+// with Claude Code, best first.
+type Recommendations struct {
+	Providers map[string][]string `json:"providers"`
+}
+
+// For returns the models recommended for provider, best first.
+func (r Recommendations) For(provider string) []string {
+	return r.Providers[provider]
+}
+
+// LoadRecommendations returns the recommendations last refreshed into the
+// cache dir, or the built-in ones if they were never refreshed.
+func LoadRecommendations(dir string) Recommendations {
+	if dir != "" {
+		if data, err := os.ReadFile(filepath.Join(dir, recommendedFile)); err == nil {
+			if r, err := parseRecommendations(data); err == nil {
+				return r
+			}
+		}
+	}
+	r, _ := parseRecommendations(builtinRecommendations)
+	return r
+}
+
+// RefreshRecommendations fetches the recommendations from url and caches
+// them in dir for LoadRecommendations.
+func RefreshRecommendations(ctx context.Context, dir, url string) (Recommendations, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Recommendations{}, fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := httpretry.Do(http.DefaultClient, req)
+	if err != nil {
+		return Recommendations{}, fmt.Errorf("fetching recommendations: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Recommendations{}, fmt.Errorf("recommendations endpoint returned status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return Recommendations{}, fmt.Errorf("reading recommendations: %w", err)
+	}
+	r, err := parseRecommendations(data)
+	if err != nil {
+		return Recommendations{}, err
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return r, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	path := filepath.Join(dir, recommendedFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return r, fmt.Errorf("failed to cache recommendations: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return r, fmt.Errorf("failed to cache recommendations: %w", err)
+	}
+	return r, nil
+}
+
+func parseRecommendations(data []byte) (Recommendations, error) {
+	var r Recommendations
+	if err := json.Unmarshal(data, &r); err != nil {
+		return Recommendations{}, fmt.Errorf("parsing recommendations: %w", err)
+	}
+	if len(r.Providers) == 0 {
+		return Recommendations{}, fmt.Errorf("parsing recommendations: no providers listed")
+	}
+	return r, nil
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestRecommendations(t *testing.T) {
+	dir := t.TempDir()
+	if got := LoadRecommendations(dir).For("ollama"); len(got) == 0 {
+		t.Fatal("built-in recommendations should cover ollama")
+	}
+
+	body := `{"providers": {"ollama": ["qwen3.5-coder"]}}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	r, err := RefreshRecommendations(context.Background(), dir, srv.URL)
+	if err != nil {
+		t.Fatalf("RefreshRecommendations: %v", err)
+	}
+	if got := r.For("ollama"); !slices.Equal(got, []string{"qwen3.5-coder"}) {
+		t.Errorf("refreshed = %v, want [qwen3.5-coder]", got)
+	}
+	if got := LoadRecommendations(dir).For("ollama"); !slices.Equal(got, []string{"qwen3.5-coder"}) {
+		t.Errorf("loaded after refresh = %v, want the refreshed list", got)
+	}
+
+	// A bad response leaves the cached recommendations alone
+	body = `{"providers": {}}`
+	if _, err := RefreshRecommendations(context.Background(), dir, srv.URL); err == nil {
+		t.Error("an empty list should be an error")
+	}
+	if got := LoadRecommendations(dir).For("ollama"); !slices.Equal(got, []string{"qwen3.5-coder"}) {
+		t.Errorf("loaded after a failed refresh = %v, want the cached list", got)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"sync"
 
 	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/errcode"
 )
 
 // Provider interface defines the methods all providers must implement
@@ -41,6 +42,13 @@ type Provider interface {
 
 	// Validate checks if the provider is properly configured
 	Validate() error
+
+	// Target returns the CLI launched with the provider
+	Target() Target
+
+	// PreserveEnv returns the variables kept from the environment rather
+	// than removed or replaced
+	PreserveEnv() []string
 }
 
 // baseProvider contains common provider functionality
@@ -55,6 +63,7 @@ type baseProvider struct {
 	modelMappings map[string]string
 	needsAPIKey   bool
 	keyEnvVar     string // env var name for API key (default: ANTHROPIC_AUTH_TOKEN)
+	preserveEnv   []string
 }
 
 func (p *baseProvider) Name() string {
@@ -96,12 +105,20 @@ func (p *baseProvider) GetModel() string {
 	return p.model
 }
 
+func (p *baseProvider) Target() Target {
+	return ClaudeTarget
+}
+
+func (p *baseProvider) PreserveEnv() []string {
+	return p.preserveEnv
+}
+
 func (p *baseProvider) Validate() error {
 	if p.name == "" {
-		return fmt.Errorf("provider name is required")
+		return errcode.New(errcode.ConfigInvalid, "provider name is required")
 	}
 	if p.needsAPIKey && p.apiKey == "" {
-		return fmt.Errorf("API key is required for %s", p.name)
+		return errcode.New(errcode.KeyMissing, "API key is required for %s", p.name)
 	}
 	return nil
 }
@@ -137,22 +154,28 @@ func (p *BuiltinProvider) GetEnvVars() map[string]string {
 	if p.model != "" {
 		env["ANTHROPIC_MODEL"] = p.model
 	}
+	p.addTierOverrides(env)
+
+	return env
+}
+
+// TierEnvVars maps model tier names (as used in model_mappings) to the env
+// vars Claude Code reads them from.
+var TierEnvVars = map[string]string{
+	"haiku":  "ANTHROPIC_DEFAULT_HAIKU_MODEL",
+	"sonnet": "ANTHROPIC_DEFAULT_SONNET_MODEL",
+	"opus":   "ANTHROPIC_DEFAULT_OPUS_MODEL",
+	"small":  "ANTHROPIC_SMALL_FAST_MODEL",
+}
 
-	// Add model mappings
+// addTierOverrides sets the env var for each tier in modelMappings. Unknown
+// tier names are ignored.
+func (p *baseProvider) addTierOverrides(env map[string]string) {
 	for tier, model := range p.modelMappings {
-		switch tier {
-		case "haiku":
-			env["ANTHROPIC_DEFAULT_HAIKU_MODEL"] = model
-		case "sonnet":
-			env["ANTHROPIC_DEFAULT_SONNET_MODEL"] = model
-		case "opus":
-			env["ANTHROPIC_DEFAULT_OPUS_MODEL"] = model
-		case "small":
-			env["ANTHROPIC_SMALL_FAST_MODEL"] = model
+		if envVar, ok := TierEnvVars[tier]; ok && model != "" {
+			env[envVar] = model
 		}
 	}
-
-	return env
 }
 
 // OpenRouterProvider is an OpenRouter model provider
@@ -165,20 +188,20 @@ func (p *OpenRouterProvider) GetEnvVars() map[string]string {
 	env := make(map[string]string)
 
 	// OpenRouter uses native Anthropic API format
-	env["ANTHROPIC_BASE_URL"] = "https://openrouter.ai/api"
+	env["ANTHROPIC_BASE_URL"] = config.OpenRouterBaseURL
 	env["ANTHROPIC_AUTH_TOKEN"] = p.apiKey
 	// ANTHROPIC_API_KEY must be explicitly set to empty so Claude Code doesn't
 	// use a real Anthropic key from the user's environment, which would bypass
 	// the OpenRouter proxy.
 	env["ANTHROPIC_API_KEY"] = ""
 
-	// Override all model tiers to use the selected model
+	// Every tier uses the selected model unless overridden
 	if p.model != "" {
-		env["ANTHROPIC_DEFAULT_OPUS_MODEL"] = p.model
-		env["ANTHROPIC_DEFAULT_SONNET_MODEL"] = p.model
-		env["ANTHROPIC_DEFAULT_HAIKU_MODEL"] = p.model
-		env["ANTHROPIC_SMALL_FAST_MODEL"] = p.model
+		for _, envVar := range TierEnvVars {
+			env[envVar] = p.model
+		}
 	}
+	p.addTierOverrides(env)
 
 	return env
 }
@@ -207,6 +230,7 @@ func (p *LocalProvider) GetEnvVars() map[string]string {
 	if p.model != "" {
 		env["ANTHROPIC_MODEL"] = p.model
 	}
+	p.addTierOverrides(env)
 
 	return env
 }
@@ -239,18 +263,23 @@ func (p *CustomProvider) GetEnvVars() map[string]string {
 			env["ANTHROPIC_BASE_URL"] = p.baseURL
 		}
 		if p.apiKey != "" {
-			env["ANTHROPIC_AUTH_TOKEN"] = p.apiKey
+			envVar := "ANTHROPIC_AUTH_TOKEN"
+			if p.keyEnvVar != "" {
+				envVar = p.keyEnvVar
+			}
+			env[envVar] = p.apiKey
 		}
 		if p.model != "" {
 			env["ANTHROPIC_MODEL"] = p.model
 		}
+		p.addTierOverrides(env)
 	}
 
 	return env
 }
 
-// FromConfig creates a Provider from a config.Provider.
-// Returns an error if the provider type is unknown.
+// FromConfig creates a Provider from a config.Provider, launching its
+// target. Returns an error if the provider type is unknown.
 func FromConfig(cp *config.Provider) (Provider, error) {
 	bp := baseProvider{
 		name:          cp.Name,
@@ -263,26 +292,29 @@ func FromConfig(cp *config.Provider) (Provider, error) {
 		modelMappings: cp.ModelMappings,
 		needsAPIKey:   cp.NeedsAPIKey(),
 		keyEnvVar:     cp.KeyEnvVar,
+		preserveEnv:   cp.PreserveEnv,
 	}
 
+	var provider Provider
 	switch cp.Type {
 	case config.ProviderTypeBuiltin:
-		return &BuiltinProvider{baseProvider: bp}, nil
+		provider = &BuiltinProvider{baseProvider: bp}
 	case config.ProviderTypeOpenRouter:
-		return &OpenRouterProvider{baseProvider: bp}, nil
+		provider = &OpenRouterProvider{baseProvider: bp}
 	case config.ProviderTypeLocal:
-		return &LocalProvider{
+		provider = &LocalProvider{
 			baseProvider: bp,
 			authToken:    cp.AuthToken,
-		}, nil
+		}
 	case config.ProviderTypeCustom:
-		return &CustomProvider{
+		provider = &CustomProvider{
 			baseProvider: bp,
 			apiType:      cp.APIType,
-		}, nil
+		}
 	default:
 		return nil, fmt.Errorf("unknown provider type: %s", cp.Type)
 	}
+	return withTarget(provider, cp), nil
 }
 
 // Registry contains all built-in provider definitions
@@ -380,7 +412,7 @@ func (r *Registry) registerBuiltins() {
 			DisplayName: "OpenRouter",
 			Description: "OpenRouter API gateway (access multiple models)",
 			Type:        config.ProviderTypeOpenRouter,
-			BaseURL:     "https://openrouter.ai/api",
+			BaseURL:     config.OpenRouterBaseURL,
 			KeyVar:      "OPENROUTER_API_KEY",
 		},
 		{
@@ -483,7 +515,7 @@ func (r *Registry) CreateProvider(name string, apiKey string) (Provider, error)
 		DisplayName:   def.DisplayName,
 		Description:   def.Description,
 		BaseURL:       def.BaseURL,
-		DefaultModel:  def.DefaultModel,
+		Model:         def.DefaultModel,
 		ModelMappings: def.ModelMappings,
 		AuthToken:     def.AuthToken,
 		KeyEnvVar:     def.KeyEnvVar,
@@ -2,6 +2,7 @@ package providers
 
 import (
 	"fmt"
+	"slices"
 	"testing"
 
 	"github.com/sammcj/skint/internal/config"
@@ -270,6 +271,22 @@ func TestCustomProvider_GetEnvVars(t *testing.T) {
 				"ANTHROPIC_MODEL":      "claude-3-sonnet",
 			},
 		},
+		{
+			name: "anthropic api type honours key env var",
+			provider: &CustomProvider{
+				baseProvider: baseProvider{
+					name:      "custom-api-key",
+					baseURL:   "https://api.anthropic.com",
+					apiKey:    "sk-ant-key",
+					keyEnvVar: "ANTHROPIC_API_KEY",
+				},
+				apiType: "anthropic",
+			},
+			want: map[string]string{
+				"ANTHROPIC_BASE_URL": "https://api.anthropic.com",
+				"ANTHROPIC_API_KEY":  "sk-ant-key",
+			},
+		},
 		{
 			name: "empty api type defaults to anthropic behaviour",
 			provider: &CustomProvider{
@@ -551,6 +568,65 @@ func TestFromConfig_BuiltinModelOverridesDefault(t *testing.T) {
 	}
 }
 
+func TestFromConfig_TierOverrides(t *testing.T) {
+	// Tier overrides apply on top of the model for every Anthropic-style
+	// provider type; OpenRouter fills the remaining tiers with the model.
+	mappings := map[string]string{"haiku": "small-model", "bogus": "ignored"}
+	tests := []struct {
+		name string
+		cp   *config.Provider
+		want map[string]string
+	}{
+		{
+			name: "openrouter",
+			cp:   &config.Provider{Name: "or", Type: config.ProviderTypeOpenRouter, Model: "big-model", ModelMappings: mappings},
+			want: map[string]string{
+				"ANTHROPIC_BASE_URL":             "https://openrouter.ai/api",
+				"ANTHROPIC_AUTH_TOKEN":           "key",
+				"ANTHROPIC_API_KEY":              "",
+				"ANTHROPIC_DEFAULT_OPUS_MODEL":   "big-model",
+				"ANTHROPIC_DEFAULT_SONNET_MODEL": "big-model",
+				"ANTHROPIC_DEFAULT_HAIKU_MODEL":  "small-model",
+				"ANTHROPIC_SMALL_FAST_MODEL":     "big-model",
+			},
+		},
+		{
+			name: "local",
+			cp:   &config.Provider{Name: "ollama", Type: config.ProviderTypeLocal, BaseURL: "http://localhost:11434", Model: "big-model", ModelMappings: mappings},
+			want: map[string]string{
+				"ANTHROPIC_BASE_URL":            "http://localhost:11434",
+				"ANTHROPIC_AUTH_TOKEN":          "",
+				"ANTHROPIC_API_KEY":             "",
+				"ANTHROPIC_MODEL":               "big-model",
+				"ANTHROPIC_DEFAULT_HAIKU_MODEL": "small-model",
+			},
+		},
+		{
+			name: "custom anthropic",
+			cp:   &config.Provider{Name: "c", Type: config.ProviderTypeCustom, BaseURL: "https://api.example.com", Model: "big-model", ModelMappings: mappings},
+			want: map[string]string{
+				"ANTHROPIC_BASE_URL":            "https://api.example.com",
+				"ANTHROPIC_AUTH_TOKEN":          "key",
+				"ANTHROPIC_MODEL":               "big-model",
+				"ANTHROPIC_DEFAULT_HAIKU_MODEL": "small-model",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.cp.NeedsAPIKey() {
+				tc.cp.SetResolvedAPIKey("key")
+			}
+			p, err := FromConfig(tc.cp)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertEnvVars(t, p.GetEnvVars(), tc.want)
+		})
+	}
+}
+
 func TestFromConfig_LocalExportsUserModel(t *testing.T) {
 	// A local provider with Model set must export ANTHROPIC_MODEL.
 	cp := &config.Provider{
@@ -591,3 +667,72 @@ func typeName(v any) string {
 	}
 	return fmt.Sprintf("%T", v)
 }
+
+func TestFromConfig_Target(t *testing.T) {
+	tests := []struct {
+		name     string
+		cp       *config.Provider
+		wantCmd  []string
+		wantVars map[string]string
+	}{
+		{
+			name:     "claude by default",
+			cp:       &config.Provider{Name: "zai", Type: config.ProviderTypeBuiltin, BaseURL: "https://api.z.ai/api/anthropic", Model: "glm-5"},
+			wantCmd:  []string{"claude"},
+			wantVars: map[string]string{"ANTHROPIC_BASE_URL": "https://api.z.ai/api/anthropic", "ANTHROPIC_MODEL": "glm-5"},
+		},
+		{
+			name:    "codex gets OpenAI variables",
+			cp:      &config.Provider{Name: "my-llm", Type: config.ProviderTypeCustom, APIType: config.APITypeOpenAI, BaseURL: "https://llm.example.com/v1", Model: "gpt-4o", Target: config.TargetCodex},
+			wantCmd: []string{"codex", "--model", "gpt-4o"},
+			wantVars: map[string]string{
+				"OPENAI_API_KEY": "key", "OPENAI_BASE_URL": "https://llm.example.com/v1",
+				"ANTHROPIC_API_KEY": "", "ANTHROPIC_AUTH_TOKEN": "",
+			},
+		},
+		{
+			name:    "opencode gets Anthropic variables and a provider/model name",
+			cp:      &config.Provider{Name: "zai", Type: config.ProviderTypeBuiltin, BaseURL: "https://api.z.ai/api/anthropic", Model: "glm-5", Target: config.TargetOpenCode},
+			wantCmd: []string{"opencode", "--model", "anthropic/glm-5"},
+			wantVars: map[string]string{
+				"ANTHROPIC_API_KEY": "key", "ANTHROPIC_BASE_URL": "https://api.z.ai/api/anthropic",
+				"ANTHROPIC_AUTH_TOKEN": "", "OPENAI_API_KEY": "",
+			},
+		},
+		{
+			name:    "aider uses LiteLLM's base URL variables",
+			cp:      &config.Provider{Name: "my-llm", Type: config.ProviderTypeCustom, APIType: config.APITypeOpenAI, BaseURL: "https://llm.example.com/v1", Model: "gpt-4o", Target: config.TargetAider},
+			wantCmd: []string{"aider", "--model", "openai/gpt-4o"},
+			wantVars: map[string]string{
+				"OPENAI_API_KEY": "key", "OPENAI_API_BASE": "https://llm.example.com/v1",
+				"ANTHROPIC_API_KEY": "", "ANTHROPIC_AUTH_TOKEN": "",
+			},
+		},
+		{
+			name:     "custom passes the provider's variables as they are",
+			cp:       &config.Provider{Name: "zai", Type: config.ProviderTypeBuiltin, BaseURL: "https://api.z.ai/api/anthropic", Target: config.TargetCustom, TargetCommand: "my-agent"},
+			wantCmd:  []string{"my-agent"},
+			wantVars: map[string]string{"ANTHROPIC_BASE_URL": "https://api.z.ai/api/anthropic"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := FromConfig(tt.cp)
+			if err != nil {
+				t.Fatalf("FromConfig: %v", err)
+			}
+			p.SetAPIKey("key")
+			target := p.Target()
+			if got := append([]string{target.Command}, target.Args...); !slices.Equal(got, tt.wantCmd) {
+				t.Errorf("command = %q, want %q", got, tt.wantCmd)
+			}
+			vars := p.GetEnvVars()
+			for k, want := range tt.wantVars {
+				if got, ok := vars[k]; !ok || got != want {
+					t.Errorf("%s = %q (set %v), want %q", k, got, ok, want)
+				}
+			}
+		})
+	}
+}
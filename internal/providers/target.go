@@ -0,0 +1,118 @@
+package providers
+
+import (
+	"github.com/sammcj/skint/internal/config"
+)
+
+// Target is the coding agent CLI launched with a provider.
+type Target struct {
+	// Name is one of config.Targets
+	Name string
+	// Command is the executable to run
+	Command string
+	// Args come before any others, e.g. to pick the model for CLIs that
+	// don't read it from the environment
+	Args []string
+}
+
+// ClaudeTarget is Claude Code, which providers' variables are written for.
+var ClaudeTarget = Target{Name: config.TargetClaude, Command: "claude"}
+
+// targetProvider launches another CLI than claude with a provider, mapping
+// its variables to the ones that CLI reads.
+type targetProvider struct {
+	Provider
+	target Target
+	openAI bool
+}
+
+func (p *targetProvider) Target() Target {
+	t := p.target
+	if model := p.GetModel(); model != "" {
+		t.Args = append(t.Args, targetModelArgs(t.Name, model, p.openAI)...)
+	}
+	return t
+}
+
+// GetEnvVars returns the provider's variables as the target reads them.
+// Anthropic-compatible providers give codex an OpenAI key and base URL, so
+// codex needs a provider with api_type openai to reach a working endpoint.
+func (p *targetProvider) GetEnvVars() map[string]string {
+	vars := p.Provider.GetEnvVars()
+	if p.target.Name == config.TargetCustom {
+		return vars
+	}
+
+	key := firstNonEmpty(vars["ANTHROPIC_AUTH_TOKEN"], vars["ANTHROPIC_API_KEY"], vars["OPENAI_API_KEY"])
+	baseURL := firstNonEmpty(vars["ANTHROPIC_BASE_URL"], vars["OPENAI_BASE_URL"])
+	env := map[string]string{}
+	set := func(name, value string) {
+		if value != "" {
+			env[name] = value
+		}
+	}
+	switch {
+	case p.target.Name == config.TargetCodex, p.openAI && p.target.Name == config.TargetOpenCode:
+		set("OPENAI_API_KEY", key)
+		set("OPENAI_BASE_URL", baseURL)
+	case p.openAI && p.target.Name == config.TargetAider:
+		set("OPENAI_API_KEY", key)
+		set("OPENAI_API_BASE", baseURL)
+	case p.target.Name == config.TargetAider:
+		set("ANTHROPIC_API_KEY", key)
+		set("ANTHROPIC_API_BASE", baseURL)
+	default:
+		set("ANTHROPIC_API_KEY", key)
+		set("ANTHROPIC_BASE_URL", baseURL)
+	}
+	// Keep keys in the user's environment from reaching another endpoint
+	for _, name := range []string{"ANTHROPIC_API_KEY", "ANTHROPIC_AUTH_TOKEN", "OPENAI_API_KEY"} {
+		if _, ok := env[name]; !ok {
+			env[name] = ""
+		}
+	}
+	return env
+}
+
+// targetModelArgs returns the arguments that make target use model.
+// opencode and aider take provider/model names.
+func targetModelArgs(target, model string, openAI bool) []string {
+	prefix := "anthropic/"
+	if openAI {
+		prefix = "openai/"
+	}
+	switch target {
+	case config.TargetCodex:
+		return []string{"--model", model}
+	case config.TargetOpenCode, config.TargetAider:
+		return []string{"--model", prefix + model}
+	}
+	return nil
+}
+
+// withTarget returns provider launching cp's target, or provider itself for
+// claude.
+func withTarget(provider Provider, cp *config.Provider) Provider {
+	name := cp.TargetName()
+	if name == config.TargetClaude {
+		return provider
+	}
+	command := name
+	if name == config.TargetCustom {
+		command = cp.TargetCommand
+	}
+	return &targetProvider{
+		Provider: provider,
+		target:   Target{Name: name, Command: command},
+		openAI:   cp.Type == config.ProviderTypeCustom && cp.APIType == config.APITypeOpenAI,
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
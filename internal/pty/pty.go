@@ -0,0 +1,54 @@
+// Package pty runs commands in a pseudo-terminal connected to skint's own
+// terminal, so a wrapped program sees a terminal of its own: resizes reach
+// it, and it draws and prompts as if it had been run directly.
+package pty
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/muesli/cancelreader"
+)
+
+// ErrUnsupported is returned by Start on platforms without pseudo-terminal
+// support (currently anything but Linux and macOS).
+var ErrUnsupported = errors.New("pseudo-terminals aren't supported on this platform")
+
+// outputGrace is how long Wait lets the command's last output drain after
+// it exits. A process it left running can keep the terminal open, so this
+// is bounded rather than waiting for the end of output.
+const outputGrace = time.Second
+
+// Session is a command running in a pseudo-terminal.
+type Session struct {
+	cmd    *exec.Cmd
+	ptmx   *os.File
+	input  cancelreader.CancelReader
+	output chan struct{}
+
+	// cleanup undoes what Start did to skint's terminal: raw mode and
+	// resize handling
+	cleanup []func()
+}
+
+// Wait waits for the command to exit and its output to be copied, then
+// restores skint's terminal. It returns the command's error, as
+// exec.Cmd.Wait does.
+func (s *Session) Wait() error {
+	err := s.cmd.Wait()
+	select {
+	case <-s.output:
+	case <-time.After(outputGrace):
+	}
+	if s.input != nil {
+		// Stops the input copy, so it doesn't swallow what's typed next
+		s.input.Cancel()
+	}
+	for i := len(s.cleanup) - 1; i >= 0; i-- {
+		s.cleanup[i]()
+	}
+	_ = s.ptmx.Close()
+	return err
+}
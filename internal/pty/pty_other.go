@@ -0,0 +1,14 @@
+//go:build !linux && !darwin
+
+package pty
+
+import (
+	"io"
+	"os/exec"
+)
+
+// Start returns ErrUnsupported: this platform has no pseudo-terminal
+// support.
+func Start(cmd *exec.Cmd, out io.Writer) (*Session, error) {
+	return nil, ErrUnsupported
+}
@@ -0,0 +1,57 @@
+package pty
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// lockedBuffer is a bytes.Buffer safe to read while Start's goroutine writes.
+type lockedBuffer struct {
+	mu sync.Mutex
+	b  bytes.Buffer
+}
+
+func (l *lockedBuffer) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.b.Write(p)
+}
+
+func (l *lockedBuffer) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.b.String()
+}
+
+func TestStart(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		if _, err := Start(exec.Command("claude"), nil); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("Start: got %v, want ErrUnsupported", err)
+		}
+		return
+	}
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+
+	var out lockedBuffer
+	s, err := Start(exec.Command(sh, "-c", "test -t 0 && test -t 1 && echo on a terminal; echo oops >&2; exit 3"), &out)
+	if err != nil {
+		t.Skipf("no pseudo-terminals here: %v", err)
+	}
+	err = s.Wait()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+		t.Errorf("Wait: got %v, want exit status 3", err)
+	}
+	// The terminal turns \n into \r\n, and stderr shares it
+	if got := out.String(); !strings.Contains(got, "on a terminal\r\n") || !strings.Contains(got, "oops") {
+		t.Errorf("output = %q", got)
+	}
+}
@@ -0,0 +1,72 @@
+//go:build linux || darwin
+
+package pty
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+
+	"github.com/creack/pty"
+	"github.com/muesli/cancelreader"
+	"golang.org/x/term"
+)
+
+// Start starts cmd in a new pseudo-terminal the size of skint's, with
+// skint's terminal in raw mode so keys (Ctrl-C included) go straight to it.
+// Input is copied to the command and its output to out until Wait.
+func Start(cmd *exec.Cmd, out io.Writer) (*Session, error) {
+	ptmx, tty, err := pty.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open a pseudo-terminal: %w", err)
+	}
+	defer tty.Close()
+	_ = pty.InheritSize(os.Stdin, ptmx)
+
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = tty, tty, tty
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	// A new session with the pseudo-terminal (its stdin) as the controlling
+	// terminal, so job control and Ctrl-C work inside it
+	cmd.SysProcAttr.Setsid = true
+	cmd.SysProcAttr.Setctty = true
+	cmd.SysProcAttr.Ctty = 0
+	if err := cmd.Start(); err != nil {
+		_ = ptmx.Close()
+		return nil, err
+	}
+
+	s := &Session{cmd: cmd, ptmx: ptmx, output: make(chan struct{})}
+	if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+		if state, err := term.MakeRaw(fd); err == nil {
+			s.cleanup = append(s.cleanup, func() { _ = term.Restore(fd, state) })
+		}
+	}
+
+	winch := make(chan os.Signal, 1)
+	signal.Notify(winch, syscall.SIGWINCH)
+	s.cleanup = append(s.cleanup, func() {
+		signal.Stop(winch)
+		close(winch)
+	})
+	go func() {
+		for range winch {
+			_ = pty.InheritSize(os.Stdin, ptmx)
+		}
+	}()
+
+	if input, err := cancelreader.NewReader(os.Stdin); err == nil {
+		s.input = input
+		go func() { _, _ = io.Copy(ptmx, input) }()
+	}
+	go func() {
+		// Ends with EIO once the command and its children close the terminal
+		_, _ = io.Copy(out, ptmx)
+		close(s.output)
+	}()
+	return s, nil
+}
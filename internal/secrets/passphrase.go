@@ -0,0 +1,70 @@
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+// ErrBadPassphrase is returned by OpenWithPassphrase when the passphrase is
+// wrong or the data has been tampered with.
+var ErrBadPassphrase = errors.New("wrong passphrase or corrupted data")
+
+// SealWithPassphrase encrypts plaintext to passphrase in the age format
+// (scrypt recipient), for data that leaves this machine (e.g. config
+// backups). The output can also be decrypted with 'age -d'.
+func SealWithPassphrase(plaintext, passphrase []byte) ([]byte, error) {
+	if len(passphrase) == 0 {
+		return nil, fmt.Errorf("passphrase is required")
+	}
+
+	recipient, err := age.NewScryptRecipient(string(passphrase))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recipient: %w", err)
+	}
+
+	var buf bytes.Buffer
+	w, err := age.Encrypt(&buf, recipient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt: %w", err)
+	}
+	if _, err := w.Write(plaintext); err != nil {
+		return nil, fmt.Errorf("failed to encrypt: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encrypt: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// OpenWithPassphrase decrypts age data encrypted to passphrase, binary or
+// ASCII armored.
+func OpenWithPassphrase(data, passphrase []byte) ([]byte, error) {
+	identity, err := age.NewScryptIdentity(string(passphrase))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create identity: %w", err)
+	}
+
+	var in io.Reader = bytes.NewReader(data)
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte(armor.Header)) {
+		in = armor.NewReader(bytes.NewReader(bytes.TrimSpace(data)))
+	}
+
+	r, err := age.Decrypt(in, identity)
+	var noMatch *age.NoIdentityMatchError
+	if errors.As(err, &noMatch) {
+		return nil, ErrBadPassphrase
+	}
+	if err != nil {
+		return nil, fmt.Errorf("not an age encrypted file: %w", err)
+	}
+	plaintext, err := io.ReadAll(r)
+	if err != nil {
+		return nil, ErrBadPassphrase
+	}
+	return plaintext, nil
+}
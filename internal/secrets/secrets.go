@@ -2,6 +2,7 @@ package secrets
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"sort"
@@ -41,6 +42,7 @@ func NewManager() (*Manager, error) {
 
 	// Test if keyring is available
 	useKeyring := testKeyring()
+	slog.Debug("secrets store", "keyring", useKeyring, "data_dir", dataDir)
 
 	m := &Manager{
 		useKeyring: useKeyring,
@@ -101,11 +103,16 @@ func (m *Manager) StoreWithReference(providerName, apiKey string) (string, error
 	if err := m.Store(providerName, apiKey); err != nil {
 		return "", err
 	}
+	return m.Reference(providerName), nil
+}
 
+// Reference returns the reference string for a key stored under
+// providerName in the current backend
+func (m *Manager) Reference(providerName string) string {
 	if m.useKeyring {
-		return fmt.Sprintf("%s:%s", StorageTypeKeyring, providerName), nil
+		return fmt.Sprintf("%s:%s", StorageTypeKeyring, providerName)
 	}
-	return fmt.Sprintf("%s:%s", StorageTypeFile, providerName), nil
+	return fmt.Sprintf("%s:%s", StorageTypeFile, providerName)
 }
 
 // RetrieveByReference retrieves a key using a reference string
@@ -313,21 +320,34 @@ func (fs *FileStore) saveAll(secrets map[string]string) error {
 		return fmt.Errorf("failed to encrypt secrets: %w", err)
 	}
 
-	// Write with secure permissions
+	// Write to a temp file and rename it into place, so a crash mid-write
+	// can't leave a truncated secrets file that no longer decrypts
 	file := fs.secretsFile()
-	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".secrets-*.tmp")
 	if err != nil {
-		return fmt.Errorf("failed to open secrets file: %w", err)
+		return fmt.Errorf("failed to create temp secrets file: %w", err)
 	}
+	tmpPath := tmp.Name()
+	defer func() { _ = os.Remove(tmpPath) }() // no-op after a successful rename
 
-	if _, err := f.Write(encrypted); err != nil {
-		f.Close()
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to set secrets file permissions: %w", err)
+	}
+	if _, err := tmp.Write(encrypted); err != nil {
+		tmp.Close()
 		return fmt.Errorf("failed to write secrets file: %w", err)
 	}
-
-	if err := f.Close(); err != nil {
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync secrets file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
 		return fmt.Errorf("failed to close secrets file: %w", err)
 	}
+	if err := os.Rename(tmpPath, file); err != nil {
+		return fmt.Errorf("failed to replace secrets file: %w", err)
+	}
 
 	return nil
 }
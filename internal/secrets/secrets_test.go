@@ -1,6 +1,8 @@
 package secrets
 
 import (
+	"bytes"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -151,6 +153,37 @@ func TestFileStorePersistence(t *testing.T) {
 	}
 }
 
+func TestFileStoreSaveIsAtomic(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+
+	fs, err := NewFileStore(tmpDir)
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+	for _, key := range []string{"first-key", "second-key"} {
+		if err := fs.Store("provider", key); err != nil {
+			t.Fatalf("Store: %v", err)
+		}
+	}
+
+	matches, err := filepath.Glob(filepath.Join(tmpDir, ".secrets-*.tmp"))
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("leftover temp files after Store: %v", matches)
+	}
+
+	info, err := os.Stat(filepath.Join(tmpDir, "secrets.enc"))
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("secrets file mode = %o, want 0600", perm)
+	}
+}
+
 func TestFileStoreNoLegacyKeyFile(t *testing.T) {
 	t.Parallel()
 	tmpDir := t.TempDir()
@@ -192,3 +225,37 @@ func TestRetrieveByReferenceFormat(t *testing.T) {
 		})
 	}
 }
+
+func TestSealWithPassphraseRoundTrip(t *testing.T) {
+	t.Parallel()
+	plaintext := []byte("config and keys")
+
+	sealed, err := SealWithPassphrase(plaintext, []byte("correct horse"))
+	if err != nil {
+		t.Fatalf("SealWithPassphrase: %v", err)
+	}
+	if bytes.Contains(sealed, plaintext) {
+		t.Fatal("sealed data contains the plaintext")
+	}
+	if !bytes.HasPrefix(sealed, []byte("age-encryption.org/v1\n")) {
+		t.Errorf("sealed data is not in the age format: %q", sealed[:min(len(sealed), 32)])
+	}
+
+	got, err := OpenWithPassphrase(sealed, []byte("correct horse"))
+	if err != nil {
+		t.Fatalf("OpenWithPassphrase: %v", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Errorf("OpenWithPassphrase = %q, want %q", got, plaintext)
+	}
+
+	if _, err := OpenWithPassphrase(sealed, []byte("wrong")); !errors.Is(err, ErrBadPassphrase) {
+		t.Errorf("wrong passphrase: got %v, want ErrBadPassphrase", err)
+	}
+	if _, err := OpenWithPassphrase([]byte("plain text"), []byte("correct horse")); err == nil {
+		t.Error("expected an error for unsealed data")
+	}
+	if _, err := SealWithPassphrase(plaintext, nil); err == nil {
+		t.Error("expected an error for an empty passphrase")
+	}
+}
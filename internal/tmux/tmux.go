@@ -0,0 +1,169 @@
+// Package tmux runs launches in named tmux sessions, which can be detached
+// from and found again later, recording the provider each was started with.
+package tmux
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sammcj/skint/internal/errcode"
+)
+
+// providerOption is the session option holding the provider a session was
+// started with; sessions without it weren't started by skint.
+const providerOption = "@skint_provider"
+
+// validName matches the session names skint accepts: tmux itself rejects
+// '.' and ':', and the rest keeps names easy to type.
+var validName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Session is a tmux session started by skint.
+type Session struct {
+	Name     string    `json:"name"`
+	Provider string    `json:"provider"`
+	Dir      string    `json:"dir"`
+	Created  time.Time `json:"created"`
+	// Attached is the number of clients attached to the session
+	Attached int `json:"attached"`
+}
+
+// ValidateName checks name can be used for a session.
+func ValidateName(name string) error {
+	if !validName.MatchString(name) {
+		return errcode.New(errcode.Usage, "invalid session name %q: use letters, digits, '-' and '_'", name)
+	}
+	return nil
+}
+
+// lookTmux returns the path to tmux.
+func lookTmux() (string, error) {
+	path, err := exec.LookPath("tmux")
+	if err != nil {
+		return "", errors.New("tmux not found: install it to use sessions")
+	}
+	return path, nil
+}
+
+// Start starts a detached session called name in dir, running argv with env
+// (NAME=value entries) added to its environment, and records provider on
+// it. It fails if the session already exists.
+func Start(name, provider, dir string, env, argv []string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	tmux, err := lookTmux()
+	if err != nil {
+		return err
+	}
+	if exec.Command(tmux, "has-session", "-t", "="+name).Run() == nil {
+		return errcode.New(errcode.Usage, "session %s already exists: attach with 'skint sessions attach %s'", name, name)
+	}
+
+	// One shell command, as tmux before 3.2 joins arguments with spaces;
+	// env is set with env(1) as new-session -e needs 3.2 too
+	if len(env) > 0 {
+		argv = append(append([]string{"env"}, env...), argv...)
+	}
+	args := []string{"new-session", "-d", "-s", name, "-c", dir, shellJoin(argv)}
+	slog.Info("starting tmux session", "session", name, "provider", provider, "dir", dir)
+	if out, err := exec.Command(tmux, args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to start tmux session %s: %s", name, strings.TrimSpace(string(out)))
+	}
+	if out, err := exec.Command(tmux, "set-option", "-t", "="+name+":", providerOption, provider).CombinedOutput(); err != nil {
+		if exec.Command(tmux, "has-session", "-t", "="+name).Run() != nil {
+			return fmt.Errorf("session %s ended as soon as it started: run the launch without --session to see why", name)
+		}
+		return fmt.Errorf("failed to record the provider of session %s: %s", name, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Attach attaches the terminal to session name, or switches to it from
+// inside tmux, returning when the client detaches or the session ends.
+func Attach(name string) error {
+	tmux, err := lookTmux()
+	if err != nil {
+		return err
+	}
+	verb := "attach-session"
+	if os.Getenv("TMUX") != "" {
+		verb = "switch-client"
+	}
+	cmd := exec.Command(tmux, verb, "-t", "="+name)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// listFormat is the list-sessions format parseSessions reads.
+const listFormat = "#{session_name}\t#{" + providerOption + "}\t#{session_attached}\t#{session_created}\t#{pane_current_path}"
+
+// List returns the running sessions skint started, in tmux's order. No tmux
+// server, or no tmux at all, means no sessions.
+func List() ([]Session, error) {
+	tmux, err := lookTmux()
+	if err != nil {
+		return nil, nil
+	}
+	out, err := exec.Command(tmux, "list-sessions", "-F", listFormat).Output()
+	if err != nil {
+		// tmux exits 1 when no server is running
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			slog.Debug("tmux list-sessions failed", "err", err, "stderr", string(exitErr.Stderr))
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list tmux sessions: %w", err)
+	}
+	return parseSessions(string(out)), nil
+}
+
+// Find returns the session skint started called name, or nil.
+func Find(name string) (*Session, error) {
+	sessions, err := List()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range sessions {
+		if s.Name == name {
+			return &s, nil
+		}
+	}
+	return nil, nil
+}
+
+// parseSessions reads list-sessions output in listFormat, skipping sessions
+// without a provider.
+func parseSessions(out string) []Session {
+	var sessions []Session
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) < 5 || fields[1] == "" {
+			continue
+		}
+		s := Session{Name: fields[0], Provider: fields[1], Dir: fields[4]}
+		s.Attached, _ = strconv.Atoi(fields[2])
+		if secs, err := strconv.ParseInt(fields[3], 10, 64); err == nil {
+			s.Created = time.Unix(secs, 0)
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions
+}
+
+// shellJoin quotes args for the shell tmux runs commands with.
+func shellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = "'" + strings.ReplaceAll(a, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
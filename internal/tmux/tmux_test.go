@@ -0,0 +1,55 @@
+package tmux
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestParseSessions(t *testing.T) {
+	out := "api-work\tzai\t1\t1760000000\t/src/api\n" +
+		"scratch\t\t0\t1760000100\t/tmp\n" +
+		"local\tollama\t0\t1760000200\t/src/app\n"
+	got := parseSessions(out)
+	if len(got) != 2 {
+		t.Fatalf("got %d sessions, want 2 (sessions without a provider skipped): %+v", len(got), got)
+	}
+	want := Session{Name: "api-work", Provider: "zai", Dir: "/src/api", Created: time.Unix(1760000000, 0), Attached: 1}
+	if got[0] != want {
+		t.Errorf("got %+v, want %+v", got[0], want)
+	}
+	if got[1].Name != "local" || got[1].Attached != 0 {
+		t.Errorf("got %+v", got[1])
+	}
+	if parseSessions("") != nil {
+		t.Error("empty output should give no sessions")
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	for _, name := range []string{"api-work", "a_1"} {
+		if err := ValidateName(name); err != nil {
+			t.Errorf("ValidateName(%q) = %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "a.b", "a:b", "a b"} {
+		if ValidateName(name) == nil {
+			t.Errorf("ValidateName(%q) accepted an invalid name", name)
+		}
+	}
+}
+
+func TestShellJoin(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+	args := []string{"it's", "$HOME", "a b", ""}
+	out, err := exec.Command(sh, "-c", "printf '%s|' "+shellJoin(args)).Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "it's|$HOME|a b||"; string(out) != want {
+		t.Errorf("got %q, want %q", out, want)
+	}
+}
@@ -0,0 +1,265 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sammcj/skint/internal/config"
+)
+
+// openClaudeArgs opens the argument editor for the global claude_args, or
+// for a configured provider's when provider is not empty. Changes apply as
+// they are made and are saved when the TUI exits.
+func (m *Model) openClaudeArgs(provider string) (tea.Model, tea.Cmd) {
+	m.argsProvider = provider
+	m.argsIdx = 0
+	m.argsEditing = false
+	m.argsReturn = m.screen
+	m.inputError = ""
+	m.screen = ScreenClaudeArgs
+	return m, nil
+}
+
+// claudeArgsChange describes a change to the argument list being edited,
+// for undo.
+func (m *Model) claudeArgsChange() string {
+	if m.argsProvider == "" {
+		return "change claude_args"
+	}
+	return "change claude_args for " + m.argsProvider
+}
+
+// claudeArgs returns the argument list being edited, or nil if its provider
+// no longer exists.
+func (m *Model) claudeArgs() *[]string {
+	if m.argsProvider == "" {
+		return &m.cfg.ClaudeArgs
+	}
+	if p := m.cfg.GetProvider(m.argsProvider); p != nil {
+		return &p.ClaudeArgs
+	}
+	return nil
+}
+
+func (m *Model) updateClaudeArgs(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.argsEditing {
+		return m.updateClaudeArgInput(msg)
+	}
+
+	args := m.claudeArgs()
+	if args == nil {
+		m.screen = m.argsReturn
+		return m, nil
+	}
+
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		m.done = true
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.screen = m.argsReturn
+	case tea.KeyUp:
+		m.argsIdx = max(m.argsIdx-1, 0)
+	case tea.KeyDown:
+		m.argsIdx = min(m.argsIdx+1, max(len(*args)-1, 0))
+	case tea.KeyShiftUp:
+		m.moveClaudeArg(args, -1)
+	case tea.KeyShiftDown:
+		m.moveClaudeArg(args, 1)
+	case tea.KeyEnter:
+		if len(*args) == 0 {
+			m.editClaudeArg(-1, "")
+		} else {
+			m.editClaudeArg(m.argsIdx, config.JoinArgs((*args)[m.argsIdx:m.argsIdx+1]))
+		}
+	case tea.KeyDelete:
+		m.removeClaudeArg(args)
+	case tea.KeyRunes:
+		switch msg.String() {
+		case "k":
+			m.argsIdx = max(m.argsIdx-1, 0)
+		case "j":
+			m.argsIdx = min(m.argsIdx+1, max(len(*args)-1, 0))
+		case "K":
+			m.moveClaudeArg(args, -1)
+		case "J":
+			m.moveClaudeArg(args, 1)
+		case "a", "n":
+			m.editClaudeArg(-1, "")
+		case "e":
+			if len(*args) > 0 {
+				m.editClaudeArg(m.argsIdx, config.JoinArgs((*args)[m.argsIdx:m.argsIdx+1]))
+			}
+		case "d", "x":
+			m.removeClaudeArg(args)
+		case "q":
+			m.screen = m.argsReturn
+		case "?":
+			return m.openHelp()
+		}
+	}
+	return m, nil
+}
+
+// editClaudeArg opens the input to replace the argument at idx, or to add
+// arguments after the selected one when idx is -1.
+func (m *Model) editClaudeArg(idx int, value string) {
+	m.argsEditIdx = idx
+	m.argsInput = newInput()
+	setInput(&m.argsInput, value)
+	m.inputError = ""
+	m.argsEditing = true
+}
+
+// moveClaudeArg swaps the selected argument with its neighbour above (step
+// -1) or below (step 1).
+func (m *Model) moveClaudeArg(args *[]string, step int) {
+	to := m.argsIdx + step
+	if to < 0 || to >= len(*args) {
+		return
+	}
+	m.pushUndo(m.claudeArgsChange())
+	(*args)[m.argsIdx], (*args)[to] = (*args)[to], (*args)[m.argsIdx]
+	m.argsIdx = to
+}
+
+// removeClaudeArg deletes the selected argument.
+func (m *Model) removeClaudeArg(args *[]string) {
+	if len(*args) == 0 {
+		return
+	}
+	m.pushUndo(m.claudeArgsChange())
+	*args = slices.Delete(*args, m.argsIdx, m.argsIdx+1)
+	if len(*args) == 0 {
+		*args = nil
+	}
+	m.argsIdx = min(m.argsIdx, max(len(*args)-1, 0))
+}
+
+// updateClaudeArgInput handles keys while an argument is being added or
+// edited. The input is split like a shell command line, so one entry can add
+// several arguments and quoting keeps spaces within one.
+func (m *Model) updateClaudeArgInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		m.done = true
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.argsEditing = false
+		m.inputError = ""
+		return m, nil
+	case tea.KeyEnter:
+		parsed, err := config.SplitArgs(m.argsInput.Value())
+		if err != nil {
+			m.inputError = err.Error()
+			return m, nil
+		}
+		args := m.claudeArgs()
+		if args == nil {
+			m.argsEditing = false
+			m.screen = m.argsReturn
+			return m, nil
+		}
+		m.pushUndo(m.claudeArgsChange())
+		switch {
+		case m.argsEditIdx >= 0:
+			*args = slices.Replace(*args, m.argsEditIdx, m.argsEditIdx+1, parsed...)
+		case len(*args) == 0:
+			*args = parsed
+		default:
+			*args = slices.Insert(*args, m.argsIdx+1, parsed...)
+			m.argsIdx++
+		}
+		if len(*args) == 0 {
+			*args = nil
+		}
+		m.argsIdx = min(m.argsIdx, max(len(*args)-1, 0))
+		m.argsEditing = false
+		m.inputError = ""
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.argsInput, cmd = m.argsInput.Update(msg)
+	m.inputError = ""
+	return m, cmd
+}
+
+func (m *Model) viewClaudeArgs() string {
+	var b strings.Builder
+
+	title := "Claude Arguments"
+	info := "Passed to claude on every launch, before any provider's own arguments."
+	if m.argsProvider != "" {
+		displayName := m.argsProvider
+		if p := m.cfg.GetProvider(m.argsProvider); p != nil && p.DisplayName != "" {
+			displayName = p.DisplayName
+		}
+		title = "Claude Arguments for " + displayName
+		global := "(none)"
+		if len(m.cfg.ClaudeArgs) > 0 {
+			global = config.JoinArgs(m.cfg.ClaudeArgs)
+		}
+		info = fmt.Sprintf("Passed to claude when launching %s, after the global arguments: %s", displayName, global)
+	}
+
+	// Compact header with breadcrumb
+	breadcrumbText := m.styles.Subtitle.UnsetMarginBottom().Render(title)
+	header := m.styles.HeaderLine.Render("Skint") +
+		m.styles.HeaderSep.Render(" › ") + breadcrumbText
+	b.WriteString(header)
+	b.WriteString("\n")
+	b.WriteString(m.styles.Dimmed.Render(info))
+	b.WriteString("\n\n")
+
+	var args []string
+	if p := m.claudeArgs(); p != nil {
+		args = *p
+	}
+	if len(args) == 0 && !m.argsEditing {
+		b.WriteString(m.styles.Dimmed.Render("No arguments - press a to add one"))
+		b.WriteString("\n")
+	}
+	for i, arg := range args {
+		line := fmt.Sprintf("%d. %s", i+1, config.JoinArgs([]string{arg}))
+		if i == m.argsIdx && !m.argsEditing {
+			b.WriteString(focusMark + m.styles.ListSelected.Render("> "+line))
+		} else {
+			b.WriteString(m.styles.Normal.Render("    " + line))
+		}
+		b.WriteString("\n")
+	}
+
+	if m.argsEditing {
+		label := "Add arguments"
+		if m.argsEditIdx >= 0 {
+			label = fmt.Sprintf("Edit argument %d", m.argsEditIdx+1)
+		}
+		in := m.argsInput
+		in.Placeholder = `e.g. --continue or --append-system-prompt "be brief"`
+		in.PlaceholderStyle = m.styles.Dimmed
+		inputWidth := max(m.width-20, 30)
+		in.Width = inputWidth - 3
+		b.WriteString("\n")
+		b.WriteString(m.styles.InputPrompt.Render(label))
+		b.WriteString("\n")
+		b.WriteString(m.styles.Input.Width(inputWidth).Render(in.View()))
+		b.WriteString("\n")
+	}
+
+	if m.inputError != "" {
+		b.WriteString(m.styles.Error.Render("✗ " + m.inputError))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	help := m.styles.Help.Render("a add  e/enter edit  d delete  K/J move  esc done")
+	if m.argsEditing {
+		help = m.styles.Help.Render("enter save  esc cancel  quote arguments containing spaces")
+	}
+	b.WriteString(m.styles.Footer.Render(help))
+
+	return b.String()
+}
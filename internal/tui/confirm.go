@@ -0,0 +1,245 @@
+package tui
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sammcj/skint/internal/config"
+)
+
+// confirm opens the confirmation screen. action runs if the user confirms;
+// cancelling returns to the current screen.
+func (m *Model) confirm(message, button string, action func() (tea.Model, tea.Cmd)) (tea.Model, tea.Cmd) {
+	m.confirmReturn = m.screen
+	m.confirmMessage = message
+	m.confirmDetail = ""
+	m.confirmButton = button
+	m.confirmAction = action
+	m.confirmYes = false
+	m.screen = ScreenConfirm
+	return m, nil
+}
+
+func (m *Model) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		m.done = true
+		return m, tea.Quit
+	case tea.KeyEsc:
+		return m.finishConfirm(false)
+	case tea.KeyLeft, tea.KeyRight, tea.KeyTab, tea.KeyUp, tea.KeyDown:
+		m.confirmYes = !m.confirmYes
+	case tea.KeyEnter:
+		return m.finishConfirm(m.confirmYes)
+	case tea.KeyRunes:
+		switch msg.String() {
+		case "y":
+			return m.finishConfirm(true)
+		case "n", "q":
+			return m.finishConfirm(false)
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) finishConfirm(yes bool) (tea.Model, tea.Cmd) {
+	action := m.confirmAction
+	m.confirmAction = nil
+	if yes && action != nil {
+		return action()
+	}
+	m.screen = m.confirmReturn
+	return m, nil
+}
+
+func (m *Model) viewConfirm() string {
+	var b strings.Builder
+
+	// Compact header
+	header := m.styles.HeaderLine.Render("Skint") +
+		m.styles.HeaderSep.Render(" › ") +
+		m.styles.Warning.Render("Confirm")
+	b.WriteString(header)
+	b.WriteString("\n\n")
+
+	b.WriteString(m.styles.Warning.Render(m.confirmMessage))
+	b.WriteString("\n\n")
+	if m.confirmDetail != "" {
+		b.WriteString(m.confirmDetail)
+		b.WriteString("\n\n")
+	}
+
+	var cancelBtn, confirmBtn string
+	if m.confirmYes {
+		cancelBtn = m.styles.ButtonInactive.Render("Cancel")
+		confirmBtn = m.styles.ButtonActive.Render(m.confirmButton)
+	} else {
+		cancelBtn = m.styles.ButtonActive.Render("Cancel")
+		confirmBtn = m.styles.ButtonInactive.Render(m.confirmButton)
+	}
+	b.WriteString(zoneMark(zoneCancel, cancelBtn) + "  " + zoneMark(zoneConfirm, confirmBtn))
+	b.WriteString("\n\n")
+
+	help := m.styles.Help.Render("←/→ select  enter confirm  y yes  n/esc cancel")
+	b.WriteString(m.styles.Footer.Render(help))
+
+	return b.String()
+}
+
+// confirmDeleteProvider asks before deleting the selected provider.
+func (m *Model) confirmDeleteProvider(item ProviderItem) (tea.Model, tea.Cmd) {
+	p := m.cfg.GetProvider(item.definition.Name)
+	if p == nil {
+		return m, nil
+	}
+	message := fmt.Sprintf("Delete %s? Its settings and stored API key will be removed.", item.definition.DisplayName)
+	return m.confirm(message, "Delete", func() (tea.Model, tea.Cmd) {
+		return m.deleteProvider(p.Name, item.definition.DisplayName)
+	})
+}
+
+// deleteProvider removes a provider from the config along with its stored
+// API key and the settings that name it (see dropProviderRefs).
+func (m *Model) deleteProvider(name, displayName string) (tea.Model, tea.Cmd) {
+	p := m.cfg.GetProvider(name)
+	if p == nil {
+		m.screen = ScreenMain
+		return m, nil
+	}
+	m.pushUndo("delete " + displayName)
+	m.cfg.RemoveProvider(name)
+
+	m.deleteUnusedKey(p.APIKeyRef)
+	m.dropProviderRefs(name)
+
+	m.refreshProviderList()
+	m.resetCustomProviderForm() // also clears the selection, so no launch option
+	m.message = fmt.Sprintf("✓ %s deleted", displayName)
+	m.messageType = "success"
+	m.screen = ScreenSuccess
+	m.successOption = successContinue
+	return m, nil
+}
+
+// deleteUnusedKey removes the stored API key ref points to, unless another
+// provider still uses it (e.g. the OpenRouter key shared by or-* providers).
+// The key is kept in memory in case the change is undone.
+func (m *Model) deleteUnusedKey(ref string) {
+	if ref == "" || m.secretsMgr == nil {
+		return
+	}
+	for _, p := range m.cfg.Providers {
+		if p.APIKeyRef == ref {
+			return
+		}
+	}
+	if _, keyName, ok := strings.Cut(ref, ":"); ok && keyName != "" {
+		if value, err := m.secretsMgr.Retrieve(keyName); err == nil {
+			m.rememberKey(savedKey{name: keyName, value: value, existed: true})
+		}
+		_ = m.secretsMgr.Delete(keyName)
+	}
+}
+
+// confirmOverwrite asks before saving updated over the configured provider of
+// the same name, listing what changes. keyReplaced is whether a new API key
+// was typed. save runs straight away for a new provider or when nothing
+// changes, and after confirming otherwise, back on the form.
+func (m *Model) confirmOverwrite(updated *config.Provider, keyReplaced bool, save func() (tea.Model, tea.Cmd)) (tea.Model, tea.Cmd) {
+	existing := m.cfg.GetProvider(updated.Name)
+	if existing == nil {
+		return save()
+	}
+	changes := providerChanges(existing, updated, keyReplaced)
+	if len(changes) == 0 {
+		return save()
+	}
+
+	form := m.screen
+	name := existing.DisplayName
+	if name == "" {
+		name = existing.Name
+	}
+	message := fmt.Sprintf("%s is already configured. Overwrite it?", name)
+	m.confirm(message, "Overwrite", func() (tea.Model, tea.Cmd) {
+		m.screen = form
+		return save()
+	})
+
+	lines := make([]string, len(changes))
+	for i, c := range changes {
+		line := m.styles.Label.Render(c.field+": ") + m.styles.Error.Render(c.from)
+		if c.to != "" {
+			line += m.styles.Dimmed.Render(" → ") + m.styles.Success.Render(c.to)
+		}
+		lines[i] = "  " + line
+	}
+	m.confirmDetail = strings.Join(lines, "\n")
+	return m, nil
+}
+
+// providerChange is one field an overwrite changes. Secrets show what
+// happens to them in from, with no to.
+type providerChange struct {
+	field, from, to string
+}
+
+// providerChanges lists the differences between old and updated, including
+// settings the forms don't edit that saving would clear.
+func providerChanges(old, updated *config.Provider, keyReplaced bool) []providerChange {
+	var changes []providerChange
+	value := func(field, from, to string) {
+		if from == to {
+			return
+		}
+		if from == "" {
+			from = "(none)"
+		}
+		if to == "" {
+			to = "(none)"
+		}
+		changes = append(changes, providerChange{field, from, to})
+	}
+	secret := func(field string, had, has, replaced bool) {
+		switch {
+		case replaced && had:
+			changes = append(changes, providerChange{field: field, from: "replaced"})
+		case replaced || !had && has:
+			changes = append(changes, providerChange{field: field, from: "added"})
+		case had && !has:
+			changes = append(changes, providerChange{field: field, from: "removed"})
+		}
+	}
+
+	value("Type", old.Type, updated.Type)
+	value("Display name", old.DisplayName, updated.DisplayName)
+	value("Base URL", old.BaseURL, updated.BaseURL)
+	value("Model", old.Model, updated.Model)
+	value("API type", old.APIType, updated.APIType)
+	secret("Auth token", old.AuthToken != "", updated.AuthToken != "", old.AuthToken != "" && updated.AuthToken != "" && old.AuthToken != updated.AuthToken)
+	secret("API key", old.APIKeyRef != "", updated.APIKeyRef != "", keyReplaced)
+	value("Model mappings", formatMappings(old.ModelMappings), formatMappings(updated.ModelMappings))
+	value("Env presets", strings.Join(old.EnvPresets, ", "), strings.Join(updated.EnvPresets, ", "))
+	value("Claude args", config.JoinArgs(old.ClaudeArgs), config.JoinArgs(updated.ClaudeArgs))
+	return changes
+}
+
+// formatMappings renders tier mappings as "tier=model" pairs, the editable
+// tiers first.
+func formatMappings(mappings map[string]string) string {
+	var pairs []string
+	for _, tier := range mappingTiers {
+		if model, ok := mappings[tier]; ok {
+			pairs = append(pairs, tier+"="+model)
+		}
+	}
+	for _, tier := range slices.Sorted(maps.Keys(mappings)) {
+		if !slices.Contains(mappingTiers, tier) {
+			pairs = append(pairs, tier+"="+mappings[tier])
+		}
+	}
+	return strings.Join(pairs, ", ")
+}
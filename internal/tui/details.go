@@ -0,0 +1,200 @@
+package tui
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/providers"
+	"github.com/sammcj/skint/internal/secrets"
+	"github.com/sammcj/skint/internal/ui"
+)
+
+// openDetails shows the details of the selected provider.
+func (m *Model) openDetails(item ProviderItem) (tea.Model, tea.Cmd) {
+	m.detailsItem = item
+	m.screen = ScreenDetails
+	return m, nil
+}
+
+func (m *Model) updateDetails(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		m.done = true
+		return m, tea.Quit
+	case tea.KeyEsc, tea.KeyEnter:
+		m.screen = ScreenMain
+	case tea.KeyRunes:
+		switch msg.String() {
+		case "q", "i":
+			m.screen = ScreenMain
+		case "e":
+			return m.handleProviderEdit(m.detailsItem)
+		case "A":
+			if m.cfg.GetProvider(m.detailsItem.definition.Name) != nil {
+				return m.openClaudeArgs(m.detailsItem.definition.Name)
+			}
+		case "y":
+			if m.cfg.GetProvider(m.detailsItem.definition.Name) != nil {
+				return m.openYAML(m.detailsItem.definition.Name)
+			}
+		case "?":
+			return m.openHelp()
+		}
+	}
+	return m, nil
+}
+
+// keyStorage describes where p's API key is kept.
+func keyStorage(p *config.Provider) string {
+	if !p.NeedsAPIKey() {
+		return "not needed"
+	}
+	switch p.KeyBackend() {
+	case secrets.StorageTypeKeyring:
+		return "OS keyring"
+	case secrets.StorageTypeFile:
+		return "encrypted file"
+	case "config":
+		return "config file (plain text)"
+	case "":
+		return "not set"
+	}
+	return p.APIKeyRef
+}
+
+// keyBadge renders a key backend as a badge, e.g. "[keyring]".
+func keyBadge(s Styles, backend string) string {
+	if backend == "" {
+		return ""
+	}
+	return " " + s.Dimmed.Render("["+backend+"]")
+}
+
+// providerEnv returns the variables skint sets when launching p, including
+// its env presets, with the API key and auth token masked.
+func (m *Model) providerEnv(p *config.Provider) (map[string]string, error) {
+	provider, err := providers.FromConfig(p)
+	if err != nil {
+		return nil, err
+	}
+	env := provider.GetEnvVars()
+	presetEnv, err := m.cfg.PresetEnv(p.EnvPresets...)
+	if err != nil {
+		return nil, err
+	}
+	maps.Copy(env, presetEnv)
+
+	for k, v := range env {
+		if v != "" && (v == p.GetAPIKey() || v == p.AuthToken) {
+			env[k] = ui.MaskKey(v)
+		}
+	}
+	return env, nil
+}
+
+func (m *Model) viewDetails() string {
+	var b strings.Builder
+	def := m.detailsItem.definition
+
+	// Compact header with breadcrumb
+	breadcrumbText := m.styles.Subtitle.UnsetMarginBottom().Render(def.DisplayName)
+	header := m.styles.HeaderLine.Render("Skint") +
+		m.styles.HeaderSep.Render(" › ") + breadcrumbText
+	if p := m.cfg.GetProvider(def.Name); p != nil {
+		header += keyBadge(m.styles, p.KeyBackend())
+	}
+	b.WriteString(header)
+	b.WriteString("\n\n")
+
+	row := func(label, value string) {
+		b.WriteString(m.styles.Label.Render(fmt.Sprintf("%-14s", label)))
+		b.WriteString(m.styles.Value.Render(value))
+		b.WriteString("\n")
+	}
+
+	lastUsed := m.usage.LastUsed(def.Name, time.Now())
+	if lastUsed == "" {
+		lastUsed = "never"
+	}
+
+	p := m.cfg.GetProvider(def.Name)
+	switch {
+	case def.Name == "native":
+		row("Name", def.Name)
+		row("Type", "native")
+		row("Last used", lastUsed)
+		b.WriteString("\n")
+		b.WriteString(m.styles.Dimmed.Render("Uses your Claude subscription; no environment variables are set."))
+		b.WriteString("\n")
+	case p == nil:
+		row("Name", def.Name)
+		row("Type", def.Type)
+		if def.BaseURL != "" {
+			row("Base URL", def.BaseURL)
+		}
+		if def.DefaultModel != "" {
+			row("Model", def.DefaultModel)
+		}
+		b.WriteString("\n")
+		b.WriteString(m.styles.Warning.Render("Not configured"))
+		b.WriteString(m.styles.Dimmed.Render(" - press e to set it up"))
+		b.WriteString("\n")
+	default:
+		row("Name", p.Name)
+		row("Type", p.Type)
+		if p.BaseURL != "" {
+			row("Base URL", p.BaseURL)
+		}
+		if model := p.EffectiveModel(); model != "" {
+			row("Model", model)
+		}
+		for _, tier := range slices.Sorted(maps.Keys(p.ModelMappings)) {
+			row("  "+tier, p.ModelMappings[tier])
+		}
+		row("API key", keyStorage(p))
+		if len(p.EnvPresets) > 0 {
+			row("Env presets", strings.Join(p.EnvPresets, ", "))
+		}
+		if target := p.TargetName(); target != config.TargetClaude {
+			if target == config.TargetCustom {
+				target = p.TargetCommand
+			}
+			row("Launches", target)
+		}
+		if args := m.cfg.LaunchArgs(p); len(args) > 0 {
+			row("Claude args", config.JoinArgs(args))
+		}
+		if len(p.MCPServers) > 0 {
+			servers := strings.Join(slices.Sorted(maps.Keys(p.MCPServers)), ", ")
+			if p.MCPStrict {
+				servers += " (only these)"
+			}
+			row("MCP servers", servers)
+		}
+		row("Last used", lastUsed)
+
+		b.WriteString("\n")
+		b.WriteString(m.styles.Label.Render("Environment"))
+		b.WriteString("\n")
+		env, err := m.providerEnv(p)
+		if err != nil {
+			b.WriteString(m.styles.Error.Render(err.Error()))
+			b.WriteString("\n")
+		}
+		for _, k := range slices.Sorted(maps.Keys(env)) {
+			b.WriteString(m.styles.Info.Render("  "+k) + m.styles.Dimmed.Render("=") + m.styles.Normal.Render(env[k]))
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString("\n")
+
+	help := m.styles.Help.Render("e edit  A claude args  y yaml  ? help  esc back")
+	b.WriteString(m.styles.Footer.Render(help))
+
+	return b.String()
+}
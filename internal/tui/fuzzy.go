@@ -0,0 +1,173 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Fuzzy match scoring. Every matched character scores fuzzyMatchScore, with
+// bonuses for runs of consecutive characters and for characters that start a
+// word (after '/', '-', '.', ':', '_' or a space, or at a lower-to-upper case
+// change), and a penalty for each character skipped between matches. A
+// contiguous match beats any scattered one.
+const (
+	fuzzyMatchScore       = 16
+	fuzzyConsecutiveBonus = 12
+	fuzzyBoundaryBonus    = 10
+	fuzzyGapPenalty       = 1
+	fuzzySubstringBonus   = 100
+)
+
+// fuzzyMatch reports whether the characters of pattern appear in s in order,
+// ignoring case, with a score for ranking matches (higher is better) and the
+// rune indices of s that matched. An empty pattern matches with score 0.
+func fuzzyMatch(pattern, s string) (score int, matched []int, ok bool) {
+	pat := []rune(strings.ToLower(pattern))
+	if len(pat) == 0 {
+		return 0, nil, true
+	}
+	runes := []rune(s)
+	lower := []rune(strings.ToLower(s))
+	if len(lower) != len(runes) {
+		// Lower-casing changed the length; match the original runes instead
+		lower = runes
+	}
+
+	// Prefer a contiguous occurrence, at a word boundary if there is one
+	if idx := fuzzySubstring(lower, runes, pat); idx >= 0 {
+		matched = make([]int, len(pat))
+		for i := range pat {
+			matched[i] = idx + i
+		}
+		return fuzzyScore(runes, matched) + fuzzySubstringBonus, matched, true
+	}
+
+	// Otherwise take each pattern character at its first occurrence,
+	// preferring one that starts a word while the rest can still match
+	matched = make([]int, 0, len(pat))
+	from := 0
+	for pi, c := range pat {
+		first := -1
+		for i := from; i < len(lower); i++ {
+			if lower[i] != c {
+				continue
+			}
+			if first < 0 {
+				first = i
+			}
+			if isWordStart(runes, i) && fuzzyCanMatch(lower, pat[pi+1:], i+1) {
+				first = i
+				break
+			}
+		}
+		if first < 0 {
+			return 0, nil, false
+		}
+		matched = append(matched, first)
+		from = first + 1
+	}
+	return fuzzyScore(runes, matched), matched, true
+}
+
+// fuzzySubstring returns the index of pat in lower, preferring an occurrence
+// that starts a word, or -1.
+func fuzzySubstring(lower, runes, pat []rune) int {
+	found := -1
+	for i := 0; i+len(pat) <= len(lower); i++ {
+		if !slices.Equal(lower[i:i+len(pat)], pat) {
+			continue
+		}
+		if isWordStart(runes, i) {
+			return i
+		}
+		if found < 0 {
+			found = i
+		}
+	}
+	return found
+}
+
+// fuzzyCanMatch reports whether pat is a subsequence of lower[from:].
+func fuzzyCanMatch(lower, pat []rune, from int) bool {
+	for _, c := range pat {
+		for from < len(lower) && lower[from] != c {
+			from++
+		}
+		if from == len(lower) {
+			return false
+		}
+		from++
+	}
+	return true
+}
+
+// fuzzyScore scores the matched rune indices of runes.
+func fuzzyScore(runes []rune, matched []int) int {
+	score := 0
+	for i, idx := range matched {
+		score += fuzzyMatchScore
+		if isWordStart(runes, idx) {
+			score += fuzzyBoundaryBonus
+		}
+		if i > 0 {
+			if gap := idx - matched[i-1] - 1; gap == 0 {
+				score += fuzzyConsecutiveBonus
+			} else {
+				score -= gap * fuzzyGapPenalty
+			}
+		}
+	}
+	// Shorter strings rank ahead of longer ones with the same matches
+	return score - len(runes)/8
+}
+
+// isWordStart reports whether runes[i] starts a word.
+func isWordStart(runes []rune, i int) bool {
+	if i == 0 {
+		return true
+	}
+	switch runes[i-1] {
+	case '/', '-', '.', ':', '_', ' ':
+		return true
+	}
+	return unicode.IsLower(runes[i-1]) && unicode.IsUpper(runes[i])
+}
+
+// highlightMatches renders s with the runes at the matched indices in match
+// and the rest in base.
+func highlightMatches(s string, matched []int, base, match lipgloss.Style) string {
+	if len(matched) == 0 {
+		return base.Render(s)
+	}
+	var b strings.Builder
+	var run strings.Builder
+	runMatched := false
+	flush := func() {
+		if run.Len() == 0 {
+			return
+		}
+		if runMatched {
+			b.WriteString(match.Render(run.String()))
+		} else {
+			b.WriteString(base.Render(run.String()))
+		}
+		run.Reset()
+	}
+	next := 0
+	for i, r := range []rune(s) {
+		isMatch := next < len(matched) && matched[next] == i
+		if isMatch {
+			next++
+		}
+		if isMatch != runMatched {
+			flush()
+			runMatched = isMatch
+		}
+		run.WriteRune(r)
+	}
+	flush()
+	return b.String()
+}
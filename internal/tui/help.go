@@ -0,0 +1,180 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// helpSection is one screen's key bindings on the help screen.
+type helpSection struct {
+	title string
+	keys  [][2]string // key, description
+}
+
+// helpSections are the key bindings shown on the help screen, by screen.
+var helpSections = []helpSection{
+	{"Provider list", [][2]string{
+		{"↑/k ↓/j", "move"},
+		{"g/home G/end", "first / last provider"},
+		{"enter", "set active, or configure"},
+		{"1-9", "set active, or configure, the numbered provider"},
+		{"/", "filter providers (enter keeps, esc clears)"},
+		{"i", "provider details"},
+		{"y", "provider YAML, to copy"},
+		{"e", "edit provider"},
+		{"m", "edit model tiers"},
+		{"A", "edit claude arguments"},
+		{"d/delete", "delete provider"},
+		{"ctrl+z", "undo last change"},
+		{"a/c", "add custom provider"},
+		{"o", "OpenRouter models"},
+		{"l", "set up detected server"},
+		{"T", "test selected provider"},
+		{"t", "test all providers"},
+		{"u", "launch Claude Code"},
+		{"s", "settings"},
+		{"?", "this help"},
+		{"q/esc", "quit"},
+	}},
+	{"Forms", [][2]string{
+		{"tab/↓ shift+tab/↑", "next / previous field"},
+		{"enter", "save"},
+		{"esc", "cancel"},
+		{"←/→", "move cursor"},
+		{"ctrl+a ctrl+e", "start / end of field"},
+		{"alt+←/→", "move by word"},
+		{"ctrl+w", "delete word"},
+		{"ctrl+u ctrl+k", "delete to start / end"},
+		{"ctrl+v", "paste"},
+		{"ctrl+f", "fetch models (model fields)"},
+		{"ctrl+p", "pull the model into Ollama"},
+		{"pgup/pgdn", "scroll a screen taller than the terminal"},
+	}},
+	{"Model picker", [][2]string{
+		{"type", "filter models (fuzzy)"},
+		{"↑/↓", "select model"},
+		{"enter", "use model"},
+		{"ctrl+t", "only models with tool calling"},
+		{"esc", "close picker"},
+	}},
+	{"Provider tests", [][2]string{
+		{"r", "re-run tests"},
+		{"esc/enter", "back"},
+	}},
+	{"Provider details", [][2]string{
+		{"e", "edit provider"},
+		{"A", "edit claude arguments"},
+		{"y", "provider YAML"},
+		{"esc/i", "back"},
+	}},
+	{"Provider YAML", [][2]string{
+		{"c", "copy to the clipboard"},
+		{"esc/y", "back"},
+	}},
+	{"Claude arguments", [][2]string{
+		{"a", "add after selected"},
+		{"e/enter", "edit"},
+		{"d/delete", "remove"},
+		{"K/J shift+↑/↓", "move up / down"},
+		{"esc", "done"},
+	}},
+	{"Settings", [][2]string{
+		{"↑/k ↓/j", "move"},
+		{"space/enter/x", "toggle, or open editor"},
+		{"←/→ h/l", "cycle option"},
+		{"esc", "back"},
+	}},
+	{"Confirm", [][2]string{
+		{"y", "confirm"},
+		{"n/esc", "cancel"},
+		{"←/→ enter", "choose / press button"},
+	}},
+}
+
+// openHelp shows the help screen, returning to the current screen after.
+func (m *Model) openHelp() (tea.Model, tea.Cmd) {
+	m.helpReturn = m.screen
+	m.screen = ScreenHelp
+	return m, nil
+}
+
+func (m *Model) updateHelp(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		m.done = true
+		return m, tea.Quit
+	case tea.KeyEsc, tea.KeyEnter:
+		m.screen = m.helpReturn
+	case tea.KeyRunes:
+		switch msg.String() {
+		case "?", "q":
+			m.screen = m.helpReturn
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) viewHelp() string {
+	var b strings.Builder
+
+	// Compact header
+	header := m.styles.HeaderLine.Render("Skint") +
+		m.styles.HeaderSep.Render(" › ") +
+		m.styles.Title.UnsetMarginBottom().UnsetBorderStyle().UnsetPadding().Render("Help")
+	b.WriteString(header)
+	b.WriteString("\n\n")
+
+	sections := make([]string, len(helpSections))
+	for i, s := range helpSections {
+		sections[i] = m.renderHelpSection(s)
+	}
+
+	// Two columns when there is room, split where the halves are closest
+	// in height
+	if m.width >= 90 {
+		split := helpColumnSplit(sections)
+		left := strings.Join(sections[:split], "\n")
+		right := strings.Join(sections[split:], "\n")
+		b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top,
+			lipgloss.NewStyle().Width(m.width/2-2).Render(left), right))
+	} else {
+		b.WriteString(strings.Join(sections, "\n"))
+	}
+	b.WriteString("\n")
+
+	help := m.styles.Help.Render("?/esc close")
+	b.WriteString(m.styles.Footer.Render(help))
+
+	return b.String()
+}
+
+// renderHelpSection renders a section title and its key bindings.
+func (m *Model) renderHelpSection(s helpSection) string {
+	var b strings.Builder
+	b.WriteString(m.styles.Subtitle.UnsetMarginBottom().Render(s.title))
+	b.WriteString("\n")
+	for _, k := range s.keys {
+		b.WriteString(m.styles.Info.Render(fmt.Sprintf("  %-20s", k[0])))
+		b.WriteString(m.styles.Normal.Render(k[1]))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// helpColumnSplit returns the index splitting sections into two columns of
+// the most similar height.
+func helpColumnSplit(sections []string) int {
+	total := 0
+	for _, s := range sections {
+		total += lipgloss.Height(s)
+	}
+	split, left := 0, 0
+	for split < len(sections)-1 && left+lipgloss.Height(sections[split]) <= total/2 {
+		left += lipgloss.Height(sections[split])
+		split++
+	}
+	return max(split, 1)
+}
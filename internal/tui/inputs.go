@@ -0,0 +1,105 @@
+package tui
+
+import (
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newInput returns a text input for a form field. Forms draw their own label
+// and border, so there is no prompt. Inputs stay focused: only the focused
+// field receives keys, and only it is drawn with a cursor.
+func newInput() textinput.Model {
+	in := textinput.New()
+	in.Prompt = ""
+	in.Cursor.SetMode(cursor.CursorStatic)
+	in.Focus()
+	return in
+}
+
+// newSecretInput returns a text input that masks what is typed.
+func newSecretInput() textinput.Model {
+	in := newInput()
+	in.EchoMode = textinput.EchoPassword
+	in.EchoCharacter = '•'
+	return in
+}
+
+// setInput replaces the value of in, leaving the cursor at the end.
+func setInput(in *textinput.Model, value string) {
+	in.SetValue(value)
+	in.CursorEnd()
+}
+
+// focusedInput returns the text input with focus on the current screen, or
+// nil if the focused field isn't text (e.g. the API type toggle).
+func (m *Model) focusedInput() *textinput.Model {
+	return m.inputAt(m.inputFocus)
+}
+
+// inputAt returns the text input for field idx on the current screen, or nil
+// if that field isn't text.
+func (m *Model) inputAt(idx int) *textinput.Model {
+	switch m.screen {
+	case ScreenAPIKeyInput:
+		switch idx {
+		case 0:
+			return &m.apiKeyInput
+		case 1:
+			return &m.modelInput
+		}
+	case ScreenProviderConfig:
+		switch idx {
+		case 0:
+			return &m.localProviderURL
+		case 1:
+			return &m.localProviderAuthToken
+		case 2:
+			return &m.localProviderModel
+		}
+	case ScreenCustomProvider:
+		switch idx {
+		case 0:
+			return &m.customProviderName
+		case 1:
+			return &m.customProviderDisplay
+		case 2:
+			return &m.customProviderURL
+		case 3:
+			return &m.apiKeyInput
+		case 4:
+			return &m.customProviderModel
+		}
+	case ScreenModelMappings:
+		return &m.mappingInputs[idx]
+	case ScreenOpenRouter:
+		if !m.orEditing {
+			return nil
+		}
+		switch idx {
+		case 0:
+			return &m.orModelInput
+		case 1:
+			return &m.orNameInput
+		case 2:
+			return &m.apiKeyInput
+		}
+	}
+	return nil
+}
+
+// updateFocusedInput passes msg to the focused input for typing, cursor
+// movement, word deletion and pasting. Editing clears any input error.
+func (m *Model) updateFocusedInput(msg tea.Msg) tea.Cmd {
+	in := m.focusedInput()
+	if in == nil {
+		return nil
+	}
+	before := in.Value()
+	var cmd tea.Cmd
+	*in, cmd = in.Update(msg)
+	if in.Value() != before {
+		m.inputError = ""
+	}
+	return cmd
+}
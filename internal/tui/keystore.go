@@ -0,0 +1,59 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// keyStoredMsg is sent when an API key has been stored in the background,
+// with the key it replaced.
+type keyStoredMsg struct {
+	ref      string
+	previous savedKey
+	err      error
+}
+
+// storeKey stores an API key under name in the background, since the OS
+// keyring can take seconds (e.g. while it prompts to unlock). The form shows
+// a spinner and ignores keys until then; done saves the provider with the
+// key's reference.
+func (m *Model) storeKey(name, key string, done func(ref string) (tea.Model, tea.Cmd)) (tea.Model, tea.Cmd) {
+	m.keyStoring = true
+	m.keyStoreDone = done
+	m.inputError = ""
+	secretsMgr := m.secretsMgr
+	return m, tea.Batch(m.spinner.Tick, func() tea.Msg {
+		previous := savedKey{name: name}
+		if value, err := secretsMgr.Retrieve(name); err == nil {
+			previous.value, previous.existed = value, true
+		}
+		ref, err := secretsMgr.StoreWithReference(name, key)
+		return keyStoredMsg{ref: ref, previous: previous, err: err}
+	})
+}
+
+// keyStored finishes the submission storeKey started.
+func (m *Model) keyStored(msg keyStoredMsg) (tea.Model, tea.Cmd) {
+	done := m.keyStoreDone
+	m.keyStoring = false
+	m.keyStoreDone = nil
+	if msg.err != nil {
+		m.inputError = fmt.Sprintf("Failed to store API key: %v", msg.err)
+		return m, nil
+	}
+	// The replaced key is restored if the change is undone
+	m.replacedKeys = append(m.replacedKeys, msg.previous)
+	if done == nil {
+		return m, nil
+	}
+	return done(msg.ref)
+}
+
+// renderKeyStoring shows progress while an API key is being stored.
+func (m *Model) renderKeyStoring() string {
+	if !m.keyStoring {
+		return ""
+	}
+	return m.spinner.View() + m.styles.Dimmed.Render(" Storing API key...") + "\n"
+}
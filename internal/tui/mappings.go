@@ -0,0 +1,157 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// mappingTiers are the model tiers editable on the mappings screen, in
+// display order.
+var mappingTiers = []string{"opus", "sonnet", "haiku", "small"}
+
+// openMappings opens the model mappings editor for a configured provider.
+func (m *Model) openMappings(item ProviderItem) (tea.Model, tea.Cmd) {
+	p := m.cfg.GetProvider(item.definition.Name)
+	if p == nil {
+		return m, nil
+	}
+
+	m.selectedProvider = item.definition
+	m.mappingInputs = make([]textinput.Model, len(mappingTiers))
+	for i, tier := range mappingTiers {
+		m.mappingInputs[i] = newInput()
+		setInput(&m.mappingInputs[i], p.ModelMappings[tier])
+	}
+	m.inputFocus = 0
+	m.inputError = ""
+	m.screen = ScreenModelMappings
+	m.resetModelPicker()
+	return m, m.fetchOnModelFocus()
+}
+
+func (m *Model) updateMappings(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Model picker intercepts input when open
+	if consumed, cmd := m.updateModelPicker(msg); consumed {
+		return m, cmd
+	}
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.screen = ScreenMain
+		m.resetModelPicker()
+		return m, nil
+	case tea.KeyCtrlC:
+		m.done = true
+		return m, tea.Quit
+	case tea.KeyCtrlF:
+		return m, m.triggerModelFetch()
+	case tea.KeyTab, tea.KeyDown:
+		m.inputFocus = (m.inputFocus + 1) % len(mappingTiers)
+		return m, m.fetchOnModelFocus()
+	case tea.KeyShiftTab, tea.KeyUp:
+		m.inputFocus = (m.inputFocus + len(mappingTiers) - 1) % len(mappingTiers)
+		return m, m.fetchOnModelFocus()
+	case tea.KeyEnter:
+		return m.submitMappings()
+	}
+
+	// Typing and editing go to the focused field
+	return m, m.updateFocusedInput(msg)
+}
+
+// submitMappings saves the edited tiers to the provider. Empty tiers are
+// dropped so they fall back to the provider's model.
+func (m *Model) submitMappings() (tea.Model, tea.Cmd) {
+	p := m.cfg.GetProvider(m.selectedProvider.Name)
+	if p == nil {
+		m.screen = ScreenMain
+		return m, nil
+	}
+
+	mappings := make(map[string]string)
+	for i, tier := range mappingTiers {
+		if model := strings.TrimSpace(m.mappingInputs[i].Value()); model != "" {
+			mappings[tier] = model
+		}
+	}
+	// Keep any entries this screen doesn't edit
+	for tier, model := range p.ModelMappings {
+		if !slices.Contains(mappingTiers, tier) {
+			mappings[tier] = model
+		}
+	}
+	if len(mappings) == 0 {
+		mappings = nil
+	}
+	if formatMappings(mappings) != formatMappings(p.ModelMappings) {
+		m.pushUndo("edit model tiers for " + m.selectedProvider.DisplayName)
+		p.ModelMappings = mappings
+		for _, tier := range slices.Backward(mappingTiers) {
+			m.rememberModels(p.Name, mappings[tier])
+		}
+	}
+
+	m.resetModelPicker()
+	m.message = fmt.Sprintf("✓ Model mappings for %s updated", m.selectedProvider.DisplayName)
+	m.messageType = "success"
+	m.screen = ScreenSuccess
+	m.successOption = successContinue
+	return m, nil
+}
+
+func (m *Model) viewMappings() string {
+	var b strings.Builder
+
+	// Compact header with breadcrumb
+	breadcrumbText := m.styles.Subtitle.UnsetMarginBottom().Render(
+		fmt.Sprintf("Model Mappings for %s", m.selectedProvider.DisplayName))
+	header := m.styles.HeaderLine.Render("Skint") +
+		m.styles.HeaderSep.Render(" › ") + breadcrumbText
+	b.WriteString(header)
+	b.WriteString("\n")
+
+	model := "(provider default)"
+	if p := m.cfg.GetProvider(m.selectedProvider.Name); p != nil && p.EffectiveModel() != "" {
+		model = p.EffectiveModel()
+	}
+	info := m.styles.Box.Width(m.width - 8).Render(
+		m.styles.Label.Render("Model: ") + m.styles.Info.Render(model) + "\n" +
+			m.styles.Dimmed.Render("Each tier Claude Code asks for uses its mapping here. Leave a tier empty to use the model above."),
+	)
+	b.WriteString(info)
+	b.WriteString("\n\n")
+
+	inputWidth := m.width - 20
+	inputWidth = max(inputWidth, 30)
+
+	for i, tier := range mappingTiers {
+		hint := "uses " + model
+		if def := m.selectedProvider.ModelMappings[tier]; def != "" {
+			hint = "default: " + def
+		}
+		label := strings.ToUpper(tier[:1]) + tier[1:]
+		b.WriteString(m.renderInputField(label, m.mappingInputs[i], hint, i, false, inputWidth))
+
+		// Render model picker under the focused tier
+		if i == m.inputFocus {
+			if pickerView := m.renderModelPicker(); pickerView != "" {
+				b.WriteString(pickerView)
+			}
+		}
+	}
+	b.WriteString("\n")
+
+	// Two-line help
+	navHelp := m.styles.Help.Render("↑/↓/tab navigate  enter save  esc cancel")
+	helpContent := navHelp
+	if hint := m.modelPickerHelpHint(); hint != "" {
+		helpContent += "\n" + m.styles.Help.Render(hint)
+	}
+	b.WriteString(m.styles.Footer.Render(helpContent))
+
+	return b.String()
+}
@@ -1,19 +1,26 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/detect"
 	"github.com/sammcj/skint/internal/models"
 	"github.com/sammcj/skint/internal/providers"
 	"github.com/sammcj/skint/internal/secrets"
+	"github.com/sammcj/skint/internal/ui"
 )
 
 // Screen represents the current screen in the TUI
@@ -26,6 +33,15 @@ const (
 	ScreenCustomProvider
 	ScreenSuccess
 	ScreenError
+	ScreenSettings
+	ScreenModelMappings
+	ScreenConfirm
+	ScreenTest
+	ScreenHelp
+	ScreenDetails
+	ScreenClaudeArgs
+	ScreenOpenRouter
+	ScreenYAML
 )
 
 // customFormFieldCount is the number of fields in the custom provider form
@@ -55,25 +71,31 @@ type Model struct {
 	list         list.Model
 	providerList []ProviderItem
 
+	// Viewport for screens taller than the terminal, the screen it last
+	// showed, and where the focus was when it last scrolled to it
+	viewport     viewport.Model
+	scrollScreen Screen
+	scrollFocus  string
+
 	// Form state
 	selectedProvider *providers.Definition
-	apiKeyInput      string
-	modelInput       string
+	apiKeyInput      textinput.Model
+	modelInput       textinput.Model
 	inputFocus       int
 	inputError       string
 	hasExistingKey   bool
 
 	// Custom provider form fields
-	customProviderName    string
-	customProviderDisplay string
-	customProviderURL     string
-	customProviderModel   string
+	customProviderName    textinput.Model
+	customProviderDisplay textinput.Model
+	customProviderURL     textinput.Model
+	customProviderModel   textinput.Model
 	customProviderAPIType string // "anthropic" or "openai"
 
 	// Local provider form fields
-	localProviderURL       string
-	localProviderAuthToken string
-	localProviderModel     string
+	localProviderURL       textinput.Model
+	localProviderAuthToken textinput.Model
+	localProviderModel     textinput.Model
 
 	// Model picker state
 	fetchedModels   []models.ModelInfo
@@ -81,17 +103,120 @@ type Model struct {
 	modelPickerIdx  int
 	modelFetching   bool
 	modelFetchErr   string
+	// modelToolsOnly hides models known not to support tool calling
+	modelToolsOnly bool
 	// fetchGeneration tags each async model fetch. Results whose generation no
 	// longer matches (a newer fetch started, or the picker was reset) are
 	// discarded so a late-arriving fetch cannot hijack a different screen.
 	fetchGeneration int
+	// cancelFetch cancels the in-flight model fetch, if any
+	cancelFetch context.CancelFunc
+
+	// Ollama pull started from the local provider form: the model, its
+	// latest progress or error, and how to cancel it
+	pull       ollamaPull
+	cancelPull context.CancelFunc
+
+	// Settings screen cursor
+	settingsIdx int
+
+	// Claude argument editor: the provider whose arguments are edited ("" for
+	// the global ones), the selected argument, and the input while adding
+	// (argsEditIdx -1) or editing one
+	argsProvider string
+	argsIdx      int
+	argsEditing  bool
+	argsEditIdx  int
+	argsInput    textinput.Model
+	argsReturn   Screen
+
+	// OpenRouter screen: the selected or-* provider, the form while adding
+	// (orEditName "") or editing one, and the result of the last change
+	orIdx        int
+	orEditing    bool
+	orEditName   string
+	orModelInput textinput.Model
+	orNameInput  textinput.Model
+	orStatus     string
+
+	// Model mappings form, one field per mappingTiers entry
+	mappingInputs []textinput.Model
+
+	// Confirmation screen: the question, any detail shown below it (e.g.
+	// what an overwrite changes), the confirm button label, what to do on
+	// confirm, whether confirm (rather than cancel) is selected, and the
+	// screen to return to on cancel
+	confirmMessage string
+	confirmDetail  string
+	confirmButton  string
+	confirmAction  func() (tea.Model, tea.Cmd)
+	confirmYes     bool
+	confirmReturn  Screen
+
+	// Provider connectivity tests, run from the test screen
+	tests          []providerTest
+	testGeneration int
+	spinner        spinner.Model
+
+	// Changes that can be undone, newest last, and stored keys replaced by
+	// a change that hasn't been recorded yet
+	undoStack    []undoEntry
+	replacedKeys []savedKey
+
+	// Where the clickable parts of the last view were drawn
+	zones []zoneRect
+
+	// Status line shown above the provider list (e.g. what was undone),
+	// cleared by the next key
+	listStatus string
+
+	// API key being stored in the background by storeKey, and what to do
+	// with its reference once stored
+	keyStoring   bool
+	keyStoreDone func(ref string) (tea.Model, tea.Cmd)
+
+	// Single-provider tests started from the list, by provider name
+	itemTests map[string]providerTest
+
+	// Screen to return to when the help screen closes
+	helpReturn Screen
+
+	// Provider shown on the details screen
+	detailsItem ProviderItem
+
+	// Models recently saved for each provider, newest first, shown at the
+	// top of the model picker
+	recentModels map[string][]string
+	// Models recommended for each provider, shown after the recent ones
+	recommendations models.Recommendations
+
+	// Provider shown on the YAML screen, the screen to return to, and the
+	// result of copying it
+	yamlProvider string
+	yamlReturn   Screen
+	yamlStatus   string
+	yamlErr      string
+
+	// Command for the screen the TUI was opened on, run at startup
+	startCmd tea.Cmd
+
+	// Local inference servers found running at startup
+	detectedServers []detect.Server
+
+	// Provider last-used times, loaded at startup
+	usage config.Usage
 
 	// Results
 	message       string
 	messageType   string // "success", "error", "info"
 	done          bool
 	resultAction  string
-	successOption int // 0 = continue, 1 = launch claude
+	successOption int // successContinue, successLaunch...
+
+	// Results of the success screen's test and script buttons
+	successTesting bool
+	successStatus  string
+	successErr     string
 
 	// Callbacks
 	onProviderSelect func(string) error
@@ -105,6 +230,9 @@ type ProviderItem struct {
 	active     bool
 	category   string
 	isAddNew   bool
+	lastUsed   string        // e.g. "3h ago", or "" if never used
+	keyBackend string        // where its API key is kept, see config.Provider.KeyBackend
+	test       *providerTest // test started from the list, if any
 }
 
 func (p ProviderItem) FilterValue() string {
@@ -129,6 +257,9 @@ func (p ProviderItem) Description() string {
 	if p.isAddNew {
 		return "Configure a custom API endpoint (OpenAI or Anthropic compatible)"
 	}
+	if p.lastUsed != "" {
+		return p.definition.Description + " · used " + p.lastUsed
+	}
 	return p.definition.Description
 }
 
@@ -154,20 +285,25 @@ func (d itemDelegate) Render(w io.Writer, m list.Model, index int, listItem list
 	case item.active && isSelected:
 		// Active + selected: combine both indicators
 		title = d.styles.ListActive.Foreground(d.styles.PrimaryColor)
-		desc = d.styles.Dimmed.PaddingLeft(4)
+		desc = d.styles.Dimmed.PaddingLeft(6)
 	case item.active:
 		title = d.styles.ListActive
-		desc = d.styles.Dimmed.PaddingLeft(4)
+		desc = d.styles.Dimmed.PaddingLeft(6)
 	case isSelected:
 		title = d.styles.ListSelected
-		desc = d.styles.Dimmed.PaddingLeft(4)
+		desc = d.styles.Dimmed.PaddingLeft(6)
 	default:
 		title = d.styles.ListItem.Foreground(d.styles.Normal.GetForeground())
-		desc = d.styles.Dimmed.PaddingLeft(4)
+		desc = d.styles.Dimmed.PaddingLeft(6)
 	}
 
 	// Color the status indicators / add-new styling
 	titleStr := item.Title()
+	// The first nine providers are numbered for quick selection
+	number := "  "
+	if index < 9 && !item.isAddNew {
+		number = d.styles.Dimmed.Render(fmt.Sprint(index+1)) + " "
+	}
 	if item.isAddNew {
 		titleStr = strings.Replace(titleStr, "+", d.styles.Info.Render("+"), 1)
 	} else if item.configured {
@@ -176,14 +312,37 @@ func (d itemDelegate) Render(w io.Writer, m list.Model, index int, listItem list
 		titleStr = strings.Replace(titleStr, "○", d.styles.Dimmed.Render("○"), 1)
 	}
 
-	fmt.Fprint(w, title.Render(titleStr)+"\n")
-	fmt.Fprint(w, desc.Render(item.Description()))
+	titleStr = number + titleStr + keyBadge(d.styles, item.keyBackend)
+
+	// Result of a test started with T
+	if t := item.test; t != nil {
+		switch {
+		case !t.done:
+			titleStr += "  " + d.styles.Dimmed.Render("testing...")
+		case t.reachable:
+			titleStr += "  " + d.styles.Success.Render("✓ "+t.latency.Round(time.Millisecond).String())
+		default:
+			titleStr += "  " + d.styles.Error.Render("✗ unreachable")
+		}
+	}
+
+	fmt.Fprint(w, zoneMark(zoneListItem+index, title.Render(titleStr))+"\n")
+	fmt.Fprint(w, zoneMark(zoneListItem+index, desc.Render(item.Description())))
 }
 
 // NewModel creates a new TUI model
 func NewModel(cfg *config.Config, secretsMgr *secrets.Manager) *Model {
 	registry := providers.NewRegistry()
-	styles := DefaultStyles()
+
+	// Detect the terminal background for adaptive colours now: once the
+	// program is running, the terminal's reply would be read as key presses
+	lipgloss.HasDarkBackground()
+	styles := DefaultStyles(cfg.Theme)
+
+	// Last-used times shown against each provider; a missing or unreadable
+	// usage file just means none are shown
+	usage, _ := config.LoadUsage()
+	now := time.Now()
 
 	// Build provider list
 	var items []list.Item
@@ -206,6 +365,7 @@ func NewModel(cfg *config.Config, secretsMgr *secrets.Manager) *Model {
 				configured: configured,
 				active:     cfg.DefaultProvider == def.Name || (cfg.DefaultProvider == "" && def.Name == "native"),
 				category:   "Native",
+				lastUsed:   usage.LastUsed(def.Name, now),
 			}
 			items = append(items, item)
 			providerItems = append(providerItems, item)
@@ -222,6 +382,7 @@ func NewModel(cfg *config.Config, secretsMgr *secrets.Manager) *Model {
 				configured: configured,
 				active:     cfg.DefaultProvider == def.Name,
 				category:   "International",
+				lastUsed:   usage.LastUsed(def.Name, now),
 			}
 			items = append(items, item)
 			providerItems = append(providerItems, item)
@@ -238,6 +399,7 @@ func NewModel(cfg *config.Config, secretsMgr *secrets.Manager) *Model {
 				configured: configured,
 				active:     cfg.DefaultProvider == def.Name,
 				category:   "Local",
+				lastUsed:   usage.LastUsed(def.Name, now),
 			}
 			items = append(items, item)
 			providerItems = append(providerItems, item)
@@ -260,47 +422,15 @@ func NewModel(cfg *config.Config, secretsMgr *secrets.Manager) *Model {
 				configured: true,
 				active:     cfg.DefaultProvider == p.Name,
 				category:   "Custom",
+				lastUsed:   usage.LastUsed(p.Name, now),
 			}
 			items = append(items, item)
 			providerItems = append(providerItems, item)
 		}
 	}
 
-	// Sort items: native always first, then active, then configured, then by category, then by name
-	sort.Slice(items, func(i, j int) bool {
-		itemI := items[i].(ProviderItem)
-		itemJ := items[j].(ProviderItem)
-
-		// Native provider is always pinned to the top
-		iNative := itemI.definition != nil && itemI.definition.Name == "native"
-		jNative := itemJ.definition != nil && itemJ.definition.Name == "native"
-		if iNative != jNative {
-			return iNative
-		}
-
-		// Active provider comes next
-		if itemI.active != itemJ.active {
-			return itemI.active && !itemJ.active
-		}
-		// Configured providers come next
-		if itemI.configured != itemJ.configured {
-			return itemI.configured && !itemJ.configured
-		}
-		// Then sort by category priority
-		categoryPriority := map[string]int{
-			"Custom":        0,
-			"Native":        1,
-			"International": 2,
-			"Local":         3,
-		}
-		pi := categoryPriority[itemI.category]
-		pj := categoryPriority[itemJ.category]
-		if pi != pj {
-			return pi < pj
-		}
-		// Finally sort by name
-		return itemI.definition.Name < itemJ.definition.Name
-	})
+	sortProviderItems(items, cfg)
+	setKeyBackends(items, cfg)
 
 	// Add "Add New Provider" item at the end
 	addNewItem := ProviderItem{isAddNew: true}
@@ -313,7 +443,11 @@ func NewModel(cfg *config.Config, secretsMgr *secrets.Manager) *Model {
 	l.Title = ""
 	l.SetShowTitle(false)
 	l.SetShowStatusBar(false)
-	l.SetFilteringEnabled(false)
+	l.SetStatusBarItemName("provider", "providers")
+	// The filter is shown by viewMainScreen rather than in the list's title
+	l.SetShowFilter(false)
+	l.FilterInput.Prompt = "/ "
+	l.FilterInput.PromptStyle = styles.Info
 	l.SetShowHelp(false)
 	l.KeyMap = list.KeyMap{
 		CursorUp:             key.NewBinding(key.WithKeys("up", "k")),
@@ -331,22 +465,69 @@ func NewModel(cfg *config.Config, secretsMgr *secrets.Manager) *Model {
 	}
 
 	return &Model{
-		screen:       ScreenMain,
-		styles:       styles,
-		cfg:          cfg,
-		registry:     registry,
-		secretsMgr:   secretsMgr,
-		list:         l,
-		providerList: providerItems,
+		screen:          ScreenMain,
+		styles:          styles,
+		cfg:             cfg,
+		registry:        registry,
+		secretsMgr:      secretsMgr,
+		list:            l,
+		providerList:    providerItems,
+		viewport:        viewport.New(0, 0),
+		usage:           usage,
+		recommendations: loadRecommendations(),
+		spinner:         spinner.New(spinner.WithSpinner(spinner.Dot), spinner.WithStyle(styles.Info)),
+
+		apiKeyInput:            newSecretInput(),
+		modelInput:             newInput(),
+		customProviderName:     newInput(),
+		customProviderDisplay:  newInput(),
+		customProviderURL:      newInput(),
+		customProviderModel:    newInput(),
+		localProviderURL:       newInput(),
+		localProviderAuthToken: newInput(),
+		localProviderModel:     newInput(),
+	}
+}
+
+// bannerMinHeight is the shortest terminal the main screen shows the banner
+// in; bannerHeight is the lines it takes, including the blank line after it.
+const bannerMinHeight = 30
+
+var bannerHeight = strings.Count(ui.Banner, "\n") + 1
+
+// showBanner reports whether the main screen shows the banner: unless it is
+// turned off, when the terminal is tall enough and not compact.
+func (m *Model) showBanner() bool {
+	return !m.cfg.NoBanner && !m.compact && m.height >= bannerMinHeight
+}
+
+// sizeList fits the provider list to the terminal, leaving room for the
+// banner when it is shown.
+func (m *Model) sizeList() {
+	listWidth := m.width - 4
+	listHeight := m.height - 8
+	if m.showBanner() {
+		listHeight -= bannerHeight
+	}
+	if m.list.SettingFilter() || m.list.IsFiltered() {
+		listHeight-- // for the filter line
+	}
+	if listWidth < 20 {
+		listWidth = 20
 	}
+	if listHeight < 10 {
+		listHeight = 10
+	}
+	m.list.SetSize(listWidth, listHeight)
 }
 
 // SetCompact enables compact mode for smaller terminals
 func (m *Model) SetCompact(compact bool) {
 	m.compact = compact
 	if compact {
-		m.styles = CompactStyles()
+		m.styles = CompactStyles(m.cfg.Theme)
 	}
+	m.sizeList()
 }
 
 // SetOnProviderSelect sets the callback for provider selection
@@ -359,11 +540,20 @@ func (m *Model) SetOnConfigDone(fn func() error) {
 	m.onConfigDone = fn
 }
 
-// refreshProviderList rebuilds the list items from current config state
+// refreshProviderList rebuilds the list items from current config state,
+// keeping the selected provider selected wherever it moves to
 func (m *Model) refreshProviderList() {
+	selected, _ := m.list.SelectedItem().(ProviderItem)
+	defer func() {
+		if selected.definition != nil {
+			m.selectProvider(selected.definition.Name)
+		}
+	}()
+
 	var items []list.Item
 	providerItems := []ProviderItem{}
 	grouped := m.registry.GroupedList()
+	now := time.Now()
 
 	// Native group
 	if native, ok := grouped["Native"]; ok {
@@ -379,6 +569,7 @@ func (m *Model) refreshProviderList() {
 				configured: configured,
 				active:     m.cfg.DefaultProvider == def.Name || (m.cfg.DefaultProvider == "" && def.Name == "native"),
 				category:   "Native",
+				lastUsed:   m.usage.LastUsed(def.Name, now),
 			}
 			items = append(items, item)
 			providerItems = append(providerItems, item)
@@ -395,6 +586,7 @@ func (m *Model) refreshProviderList() {
 				configured: configured,
 				active:     m.cfg.DefaultProvider == def.Name,
 				category:   "International",
+				lastUsed:   m.usage.LastUsed(def.Name, now),
 			}
 			items = append(items, item)
 			providerItems = append(providerItems, item)
@@ -411,6 +603,7 @@ func (m *Model) refreshProviderList() {
 				configured: configured,
 				active:     m.cfg.DefaultProvider == def.Name,
 				category:   "Local",
+				lastUsed:   m.usage.LastUsed(def.Name, now),
 			}
 			items = append(items, item)
 			providerItems = append(providerItems, item)
@@ -432,53 +625,127 @@ func (m *Model) refreshProviderList() {
 				configured: true,
 				active:     m.cfg.DefaultProvider == p.Name,
 				category:   "Custom",
+				lastUsed:   m.usage.LastUsed(p.Name, now),
 			}
 			items = append(items, item)
 			providerItems = append(providerItems, item)
 		}
 	}
 
-	// Sort: native always first, then active, then configured, then by category, then by name
+	sortProviderItems(items, m.cfg)
+	setKeyBackends(items, m.cfg)
+
+	// Keep results of tests started from the list
+	for i, li := range items {
+		item := li.(ProviderItem)
+		if t, ok := m.itemTests[item.definition.Name]; ok {
+			item.test = &t
+			items[i] = item
+		}
+	}
+
+	// Add "Add New Provider" at the end
+	addNewItem := ProviderItem{isAddNew: true}
+	items = append(items, addNewItem)
+	providerItems = append(providerItems, addNewItem)
+
+	m.list.SetItems(items)
+	if m.list.IsFiltered() {
+		// Filter now, rather than when the command SetItems returns runs
+		m.list.SetFilterText(m.list.FilterValue())
+	}
+	m.providerList = providerItems
+}
+
+// setKeyBackends records where each configured provider's API key is kept,
+// for its badge in the list.
+func setKeyBackends(items []list.Item, cfg *config.Config) {
+	for i, li := range items {
+		item := li.(ProviderItem)
+		if p := cfg.GetProvider(item.definition.Name); p != nil {
+			item.keyBackend = p.KeyBackend()
+			items[i] = item
+		}
+	}
+}
+
+// sortProviderItems orders the provider list: providers in provider_order
+// first, in that order, then native, active, configured, by category and
+// finally by name.
+func sortProviderItems(items []list.Item, cfg *config.Config) {
+	categoryPriority := map[string]int{
+		"Custom":        0,
+		"Native":        1,
+		"International": 2,
+		"Local":         3,
+	}
+
 	sort.Slice(items, func(i, j int) bool {
 		itemI := items[i].(ProviderItem)
 		itemJ := items[j].(ProviderItem)
 
-		// Native provider is always pinned to the top
+		// The user's preferred order beats everything else
+		ri, rj := cfg.ProviderRank(itemI.definition.Name), cfg.ProviderRank(itemJ.definition.Name)
+		if ri >= 0 || rj >= 0 {
+			if ri < 0 || rj < 0 {
+				return ri >= 0
+			}
+			return ri < rj
+		}
+
+		// Native provider is pinned to the top
 		iNative := itemI.definition != nil && itemI.definition.Name == "native"
 		jNative := itemJ.definition != nil && itemJ.definition.Name == "native"
 		if iNative != jNative {
 			return iNative
 		}
 
+		// Active provider comes next
 		if itemI.active != itemJ.active {
 			return itemI.active && !itemJ.active
 		}
+		// Configured providers come next
 		if itemI.configured != itemJ.configured {
 			return itemI.configured && !itemJ.configured
 		}
-		categoryPriority := map[string]int{
-			"Custom": 0, "Native": 1, "International": 2, "Local": 3,
-		}
+		// Then sort by category priority
 		pi := categoryPriority[itemI.category]
 		pj := categoryPriority[itemJ.category]
 		if pi != pj {
 			return pi < pj
 		}
+		// Finally sort by name
 		return itemI.definition.Name < itemJ.definition.Name
 	})
-
-	// Add "Add New Provider" at the end
-	addNewItem := ProviderItem{isAddNew: true}
-	items = append(items, addNewItem)
-	providerItems = append(providerItems, addNewItem)
-
-	m.list.SetItems(items)
-	m.providerList = providerItems
 }
 
 // Init initialises the model
 func (m *Model) Init() tea.Cmd {
-	return nil
+	return tea.Batch(detectLocalServersCmd(), m.startCmd)
+}
+
+// localServersDetectedMsg is sent when the startup probe for local inference
+// servers completes.
+type localServersDetectedMsg struct {
+	servers []detect.Server
+}
+
+// detectLocalServersCmd probes for local inference servers in the background.
+func detectLocalServersCmd() tea.Cmd {
+	return func() tea.Msg {
+		return localServersDetectedMsg{servers: detect.Detect()}
+	}
+}
+
+// unconfiguredServer returns the first detected local server that has no
+// provider configured yet.
+func (m *Model) unconfiguredServer() (detect.Server, bool) {
+	for _, s := range m.detectedServers {
+		if m.cfg.GetProvider(s.Provider) == nil {
+			return s, true
+		}
+	}
+	return detect.Server{}, false
 }
 
 // Update handles messages
@@ -488,21 +755,47 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 
-		// Adjust list size
-		listWidth := msg.Width - 4
-		listHeight := msg.Height - 8
-		if listWidth < 20 {
-			listWidth = 20
-		}
-		if listHeight < 10 {
-			listHeight = 10
-		}
-		m.list.SetSize(listWidth, listHeight)
-
 		// Switch to compact mode for small terminals
 		if msg.Height < 24 {
 			m.SetCompact(true)
 		}
+		m.sizeList()
+
+	case localServersDetectedMsg:
+		m.detectedServers = msg.servers
+		return m, nil
+
+	case providerTestedMsg:
+		m.recordTestResult(msg)
+		return m, nil
+
+	case itemTestedMsg:
+		return m, m.setItemTest(msg.result)
+
+	case spinner.TickMsg:
+		// Stop ticking once every test has finished and no key is being
+		// stored
+		if !m.testsRunning() && !m.keyStoring {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case keyStoredMsg:
+		return m.keyStored(msg)
+
+	case keysRestoredMsg:
+		return m.keysRestored(msg)
+
+	case clipboardMsg:
+		return m.copied(msg)
+
+	case scriptGeneratedMsg:
+		return m.scriptGenerated(msg)
+
+	case pullProgressMsg:
+		return m.pullProgressed(msg)
 
 	case modelsFetchedMsg:
 		// Discard stale results: a newer fetch started or the picker was reset
@@ -511,20 +804,42 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		m.modelFetching = false
+		m.cancelFetch = nil
 		if msg.err != nil {
+			// Cached models already shown stay
 			m.modelFetchErr = msg.err.Error()
-		} else {
-			m.fetchedModels = msg.models
+			return m, nil
+		}
+		m.fetchedModels = msg.models
+		switch {
+		case len(msg.models) == 0:
+			m.modelPickerOpen = false
+		case m.modelPickerOpen:
+			// A background refresh of the cached models: keep the cursor
+			m.modelPickerIdx = min(m.modelPickerIdx, max(len(m.filteredModels())-1, 0))
+		case m.isOnModelField():
 			// Only open the picker if focus is still on the model field, so a
 			// completed fetch never grabs keystrokes on the API key field.
-			if len(msg.models) > 0 && m.isOnModelField() {
-				m.modelPickerOpen = true
-				m.modelPickerIdx = 0
-			}
+			m.modelPickerOpen = true
+			m.modelPickerIdx = 0
 		}
 		return m, nil
 
+	case tea.MouseMsg:
+		return m.updateMouse(msg)
+
 	case tea.KeyMsg:
+		// Ignore keys while an API key is being stored, so the form can't
+		// be changed or submitted twice
+		if m.keyStoring {
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+		if m.updateScroll(msg) {
+			return m, nil
+		}
 		switch m.screen {
 		case ScreenMain:
 			return m.updateMainScreen(msg)
@@ -536,6 +851,24 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateCustomProvider(msg)
 		case ScreenSuccess:
 			return m.updateSuccessScreen(msg)
+		case ScreenSettings:
+			return m.updateSettings(msg)
+		case ScreenModelMappings:
+			return m.updateMappings(msg)
+		case ScreenConfirm:
+			return m.updateConfirm(msg)
+		case ScreenTest:
+			return m.updateTestScreen(msg)
+		case ScreenHelp:
+			return m.updateHelp(msg)
+		case ScreenDetails:
+			return m.updateDetails(msg)
+		case ScreenClaudeArgs:
+			return m.updateClaudeArgs(msg)
+		case ScreenOpenRouter:
+			return m.updateOpenRouter(msg)
+		case ScreenYAML:
+			return m.updateYAML(msg)
 		case ScreenError:
 			// Any key returns to main screen
 			m.refreshProviderList()
@@ -544,9 +877,15 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	// Other messages for a form field, e.g. a clipboard paste
+	if m.focusedInput() != nil {
+		return m, m.updateFocusedInput(msg)
+	}
+
 	// Update list
 	var cmd tea.Cmd
 	m.list, cmd = m.list.Update(msg)
+	m.sizeList()
 	return m, cmd
 }
 
@@ -571,11 +910,29 @@ func (m *Model) View() string {
 		content = m.viewSuccess()
 	case ScreenError:
 		content = m.viewError()
+	case ScreenSettings:
+		content = m.viewSettings()
+	case ScreenModelMappings:
+		content = m.viewMappings()
+	case ScreenConfirm:
+		content = m.viewConfirm()
+	case ScreenTest:
+		content = m.viewTestScreen()
+	case ScreenHelp:
+		content = m.viewHelp()
+	case ScreenDetails:
+		content = m.viewDetails()
+	case ScreenClaudeArgs:
+		content = m.viewClaudeArgs()
+	case ScreenOpenRouter:
+		content = m.viewOpenRouter()
+	case ScreenYAML:
+		content = m.viewYAML()
 	default:
 		content = m.viewMainScreen()
 	}
 
-	return m.styles.App.Render(content)
+	return m.scanZones(m.styles.App.Render(m.scrollView(content)))
 }
 
 // IsDone returns true if the TUI is done
@@ -1,9 +1,15 @@
 package tui
 
 import (
+	"cmp"
+	"context"
+	"slices"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sammcj/skint/internal/config"
 	"github.com/sammcj/skint/internal/models"
 )
 
@@ -16,6 +22,14 @@ func (m *Model) modelFieldIndex() int {
 		return 2
 	case ScreenCustomProvider:
 		return 4
+	case ScreenModelMappings:
+		// Every field is a model
+		return m.inputFocus
+	case ScreenOpenRouter:
+		if m.orEditing {
+			return 0
+		}
+		return -1
 	default:
 		return -1
 	}
@@ -30,11 +44,15 @@ func (m *Model) isOnModelField() bool {
 func (m *Model) getModelValue() string {
 	switch m.screen {
 	case ScreenAPIKeyInput:
-		return m.modelInput
+		return m.modelInput.Value()
 	case ScreenProviderConfig:
-		return m.localProviderModel
+		return m.localProviderModel.Value()
 	case ScreenCustomProvider:
-		return m.customProviderModel
+		return m.customProviderModel.Value()
+	case ScreenModelMappings:
+		return m.mappingInputs[m.inputFocus].Value()
+	case ScreenOpenRouter:
+		return m.orModelInput.Value()
 	default:
 		return ""
 	}
@@ -44,19 +62,24 @@ func (m *Model) getModelValue() string {
 func (m *Model) setModelValue(value string) {
 	switch m.screen {
 	case ScreenAPIKeyInput:
-		m.modelInput = value
+		setInput(&m.modelInput, value)
 	case ScreenProviderConfig:
-		m.localProviderModel = value
+		setInput(&m.localProviderModel, value)
 	case ScreenCustomProvider:
-		m.customProviderModel = value
+		setInput(&m.customProviderModel, value)
+	case ScreenModelMappings:
+		setInput(&m.mappingInputs[m.inputFocus], value)
+	case ScreenOpenRouter:
+		setInput(&m.orModelInput, value)
 	}
 }
 
 // updateModelPicker handles key events when the model picker is open.
-// Returns true if the event was consumed by the picker.
-func (m *Model) updateModelPicker(msg tea.KeyMsg) bool {
+// Returns true if the event was consumed by the picker. Other keys edit the
+// model field, which filters the list.
+func (m *Model) updateModelPicker(msg tea.KeyMsg) (bool, tea.Cmd) {
 	if !m.modelPickerOpen {
-		return false
+		return false, nil
 	}
 
 	filtered := m.filteredModels()
@@ -64,7 +87,7 @@ func (m *Model) updateModelPicker(msg tea.KeyMsg) bool {
 	switch msg.Type {
 	case tea.KeyCtrlC:
 		// Don't consume Ctrl+C -- let the parent handler quit the app
-		return false
+		return false, nil
 	case tea.KeyEsc:
 		m.modelPickerOpen = false
 	case tea.KeyEnter:
@@ -80,29 +103,26 @@ func (m *Model) updateModelPicker(msg tea.KeyMsg) bool {
 		if m.modelPickerIdx < len(filtered)-1 {
 			m.modelPickerIdx++
 		}
-	case tea.KeyBackspace:
-		current := m.getModelValue()
-		if len(current) > 0 {
-			m.setModelValue(current[:len(current)-1])
+	case tea.KeyCtrlT:
+		m.modelToolsOnly = !m.modelToolsOnly
+		m.modelPickerIdx = 0
+	default:
+		before := m.getModelValue()
+		cmd := m.updateFocusedInput(msg)
+		if m.getModelValue() != before {
 			m.modelPickerIdx = 0
 		}
-	case tea.KeyRunes:
-		current := m.getModelValue()
-		for _, r := range msg.Runes {
-			if r >= 32 && r < 127 {
-				current += string(r)
-			}
-		}
-		m.setModelValue(current)
-		m.modelPickerIdx = 0
+		return true, cmd
 	}
-	return true
+	return true, nil
 }
 
-// fetchOnModelFocus triggers a model fetch if the focus just landed on the model
-// field and models haven't been fetched yet. Returns nil if no fetch is needed.
+// fetchOnModelFocus loads the models if the focus just landed on the model
+// field and they haven't been loaded yet, and cancels the fetch if the focus
+// left it. Returns nil if no fetch is needed.
 func (m *Model) fetchOnModelFocus() tea.Cmd {
 	if !m.isOnModelField() {
+		m.cancelModelFetch()
 		return nil
 	}
 	if m.fetchedModels != nil || m.modelFetching {
@@ -113,10 +133,33 @@ func (m *Model) fetchOnModelFocus() tea.Cmd {
 		}
 		return nil
 	}
-	return m.triggerModelFetch()
+	return m.loadModels()
 }
 
-// triggerModelFetch starts an async model fetch if not already fetching.
+// loadModels shows the provider's cached models straight away, and fetches
+// them in the background when there are none or they are older than the
+// config's model_cache_ttl.
+func (m *Model) loadModels() tea.Cmd {
+	baseURL, apiKey, providerName := m.resolveProviderForFetch()
+	if providerName == "" {
+		return nil
+	}
+	if dir, err := config.GetCacheDir(); err == nil {
+		if list, fetched, ok := models.ReadCache(dir, providerName, baseURL); ok {
+			m.fetchedModels = list
+			m.modelFetchErr = ""
+			m.modelPickerOpen = len(list) > 0
+			m.modelPickerIdx = 0
+			if time.Since(fetched) < m.cfg.ModelCacheMaxAge() {
+				return nil
+			}
+		}
+	}
+	return m.fetchModels(baseURL, apiKey, providerName)
+}
+
+// triggerModelFetch starts an async model fetch, bypassing the cache, if not
+// already fetching.
 func (m *Model) triggerModelFetch() tea.Cmd {
 	if m.modelFetching {
 		return nil
@@ -125,13 +168,35 @@ func (m *Model) triggerModelFetch() tea.Cmd {
 	if providerName == "" {
 		return nil
 	}
-	m.modelFetching = true
-	m.modelFetchErr = ""
 	m.fetchedModels = nil
 	m.modelPickerOpen = false
 	m.modelPickerIdx = 0
+	return m.fetchModels(baseURL, apiKey, providerName)
+}
+
+// fetchModels starts an async fetch of providerName's models, keeping any
+// models already shown until it completes.
+func (m *Model) fetchModels(baseURL, apiKey, providerName string) tea.Cmd {
+	m.cancelModelFetch()
+	ctx, cancel := context.WithTimeout(context.Background(), m.cfg.NetworkTimeout())
+	m.cancelFetch = cancel
+	m.modelFetching = true
+	m.modelFetchErr = ""
 	m.fetchGeneration++
-	return fetchModelsCmd(baseURL, apiKey, providerName, m.fetchGeneration)
+	return fetchModelsCmd(ctx, cancel, baseURL, apiKey, providerName, m.fetchGeneration)
+}
+
+// cancelModelFetch cancels the in-flight model fetch, if any, and discards
+// its result. Models already shown stay.
+func (m *Model) cancelModelFetch() {
+	if m.cancelFetch != nil {
+		m.cancelFetch()
+		m.cancelFetch = nil
+	}
+	if m.modelFetching {
+		m.modelFetching = false
+		m.fetchGeneration++
+	}
 }
 
 // modelsFetchedMsg is sent when an async model fetch completes.
@@ -141,10 +206,18 @@ type modelsFetchedMsg struct {
 	generation int
 }
 
-// fetchModelsCmd returns a Bubble Tea command that fetches models asynchronously.
-func fetchModelsCmd(baseURL, apiKey, providerName string, generation int) tea.Cmd {
+// fetchModelsCmd returns a Bubble Tea command that fetches models
+// asynchronously, until ctx is done, and caches them. cancel releases ctx
+// once the fetch is over.
+func fetchModelsCmd(ctx context.Context, cancel context.CancelFunc, baseURL, apiKey, providerName string, generation int) tea.Cmd {
 	return func() tea.Msg {
-		result := models.FetchModels(baseURL, apiKey, providerName)
+		defer cancel()
+		result := models.FetchModels(ctx, baseURL, apiKey, providerName)
+		if result.Err == nil && len(result.Models) > 0 {
+			if dir, err := config.GetCacheDir(); err == nil {
+				_ = models.SaveCache(dir, providerName, baseURL, result.Models)
+			}
+		}
 		return modelsFetchedMsg{models: result.Models, err: result.Err, generation: generation}
 	}
 }
@@ -152,26 +225,182 @@ func fetchModelsCmd(baseURL, apiKey, providerName string, generation int) tea.Cm
 // maxPickerVisible is the maximum number of models to show in the picker at once.
 const maxPickerVisible = 10
 
-// filteredModels returns the subset of fetched models matching the current model input.
-// The model input field doubles as the typeahead filter.
-func (m *Model) filteredModels() []models.ModelInfo {
-	filter := strings.ToLower(m.getModelValue())
-	if filter == "" {
-		return m.fetchedModels
+// maxRecentModels is how many recently used models are kept per provider.
+const maxRecentModels = 5
+
+// rememberModels records models as the most recently used with provider,
+// for the top of its model picker.
+func (m *Model) rememberModels(provider string, used ...string) {
+	if m.recentModels == nil {
+		m.recentModels = make(map[string][]string)
+	}
+	recent := slices.Clone(m.recentModels[provider])
+	for _, model := range used {
+		model = strings.TrimSpace(model)
+		if model == "" {
+			continue
+		}
+		recent = slices.DeleteFunc(recent, func(r string) bool { return r == model })
+		recent = slices.Insert(recent, 0, model)
+	}
+	if len(recent) > 0 {
+		m.recentModels[provider] = recent[:min(len(recent), maxRecentModels)]
 	}
-	var filtered []models.ModelInfo
+}
+
+// modelMatch is a model matching the picker filter, with the rune indices
+// of its label that matched and the picker section it is listed in.
+type modelMatch struct {
+	models.ModelInfo
+	score   int
+	matched []int
+	section string
+}
+
+// Model picker sections, in the order they are listed
+const (
+	sectionRecent      = "Recent"
+	sectionRecommended = "★ Recommended"
+	sectionAll         = "All models"
+)
+
+// filteredModels returns the models matching the current model input: the
+// provider's recent models, most recent first, then the recommended models
+// it lists, best first, then the other fetched models, best match first.
+// The model input field doubles as the typeahead filter, matched fuzzily
+// against each model's label and ID. Models known not to support tool
+// calling are left out while modelToolsOnly is on.
+func (m *Model) filteredModels() []modelMatch {
+	filter := strings.TrimSpace(m.getModelValue())
+	match := func(mi models.ModelInfo) (modelMatch, bool) {
+		score, matched, ok := fuzzyMatch(filter, mi.Label())
+		if idScore, _, idOK := fuzzyMatch(filter, mi.ID); idOK && (!ok || idScore > score) {
+			// Matched on the ID, which isn't what is shown
+			score, matched, ok = idScore, nil, true
+		}
+		if m.modelToolsOnly && mi.LacksTools() {
+			return modelMatch{}, false
+		}
+		return modelMatch{ModelInfo: mi, score: score, matched: matched, section: sectionAll}, ok
+	}
+
+	_, _, provider := m.resolveProviderForFetch()
+	recent := m.recentModels[provider]
+	recommended := slices.DeleteFunc(slices.Clone(m.recommendations.For(provider)), func(id string) bool {
+		return slices.Contains(recent, id)
+	})
+	var top []modelMatch
+	for _, section := range []struct {
+		name string
+		ids  []string
+	}{{sectionRecent, recent}, {sectionRecommended, recommended}} {
+		for _, id := range section.ids {
+			// Fetched details when the provider lists it. Recent models are
+			// kept when it no longer does.
+			mi := models.ModelInfo{ID: id}
+			if i := slices.IndexFunc(m.fetchedModels, func(f models.ModelInfo) bool { return f.ID == id }); i >= 0 {
+				mi = m.fetchedModels[i]
+			} else if section.name == sectionRecommended {
+				continue
+			}
+			if mm, ok := match(mi); ok {
+				mm.section = section.name
+				top = append(top, mm)
+			}
+		}
+	}
+
+	var filtered []modelMatch
 	for _, mi := range m.fetchedModels {
-		if strings.Contains(strings.ToLower(mi.ID), filter) ||
-			strings.Contains(strings.ToLower(mi.DisplayName), filter) {
-			filtered = append(filtered, mi)
+		if slices.Contains(recent, mi.ID) || slices.Contains(recommended, mi.ID) {
+			continue
+		}
+		if mm, ok := match(mi); ok {
+			filtered = append(filtered, mm)
 		}
 	}
-	return filtered
+	if filter != "" {
+		slices.SortStableFunc(filtered, func(a, b modelMatch) int {
+			return cmp.Compare(b.score, a.score)
+		})
+	}
+	return append(top, filtered...)
+}
+
+// pickerColumn is a metadata column in the model picker.
+type pickerColumn struct {
+	title string
+	value func(models.ModelInfo) string
+	width int
 }
 
-// resetModelPicker clears all model picker state. Bumping the fetch generation
-// invalidates any in-flight fetch so its result is discarded on arrival.
+// pickerColumns are the metadata columns the picker can show, most useful
+// first.
+var pickerColumns = []pickerColumn{
+	{title: "context", value: func(mi models.ModelInfo) string { return models.FormatContext(mi.ContextLength) }},
+	{title: "$/M in/out", value: func(mi models.ModelInfo) string { return models.FormatPricing(mi.Pricing) }},
+	{title: "state", value: func(mi models.ModelInfo) string { return models.FormatState(mi.State) }},
+	{title: "supports", value: func(mi models.ModelInfo) string { return models.FormatCapabilities(mi.Capabilities) }},
+	{title: "params", value: func(mi models.ModelInfo) string { return mi.ParameterSize }},
+	{title: "quant", value: func(mi models.ModelInfo) string { return mi.Quantization }},
+	{title: "modality", value: func(mi models.ModelInfo) string { return mi.Modality }},
+}
+
+// minPickerLabelWidth is the narrowest the picker lets model labels get to
+// make room for metadata columns.
+const minPickerLabelWidth = 24
+
+// pickerLayout returns the label width and the metadata columns, with their
+// widths, that fit width cells for rows. Columns no row has a value for are
+// left out, and the least useful are dropped first when space is short.
+func pickerLayout(rows []modelMatch, width int) (int, []pickerColumn) {
+	labelWidth := 0
+	for _, mi := range rows {
+		labelWidth = max(labelWidth, lipgloss.Width(mi.Label()))
+	}
+
+	var columns []pickerColumn
+	for _, c := range pickerColumns {
+		for _, mi := range rows {
+			c.width = max(c.width, lipgloss.Width(c.value(mi.ModelInfo)))
+		}
+		if c.width > 0 {
+			c.width = max(c.width, lipgloss.Width(c.title))
+			columns = append(columns, c)
+		}
+	}
+
+	for ; len(columns) > 0; columns = columns[:len(columns)-1] {
+		metaWidth := 0
+		for _, c := range columns {
+			metaWidth += c.width + 2
+		}
+		if width-metaWidth >= min(labelWidth, minPickerLabelWidth) {
+			return min(labelWidth, width-metaWidth), columns
+		}
+	}
+	return min(labelWidth, width), nil
+}
+
+// modelLoadWarning returns a warning when the model field names a fetched
+// model the server reports as not loaded, as LM Studio does, or "".
+func (m *Model) modelLoadWarning() string {
+	value := strings.TrimSpace(m.getModelValue())
+	i := slices.IndexFunc(m.fetchedModels, func(mi models.ModelInfo) bool { return mi.ID == value })
+	if value == "" || i < 0 || !m.fetchedModels[i].NotLoaded() {
+		return ""
+	}
+	return value + " isn't loaded in " + m.selectedProvider.DisplayName + ": load it there, or turn on JIT (just-in-time) model loading"
+}
+
+// resetModelPicker clears all model picker state. Any in-flight fetch is
+// cancelled, and bumping the fetch generation discards its result on
+// arrival.
 func (m *Model) resetModelPicker() {
+	if m.cancelFetch != nil {
+		m.cancelFetch()
+		m.cancelFetch = nil
+	}
 	m.fetchedModels = nil
 	m.modelPickerOpen = false
 	m.modelPickerIdx = 0
@@ -188,7 +417,7 @@ func (m *Model) resolveProviderForFetch() (baseURL, apiKey, providerName string)
 		// Local provider config screen
 		if m.selectedProvider != nil {
 			providerName = m.selectedProvider.Name
-			baseURL = m.localProviderURL
+			baseURL = m.localProviderURL.Value()
 		}
 	case ScreenAPIKeyInput:
 		// Built-in / OpenRouter provider
@@ -196,7 +425,7 @@ func (m *Model) resolveProviderForFetch() (baseURL, apiKey, providerName string)
 			providerName = m.selectedProvider.Name
 			baseURL = m.selectedProvider.BaseURL
 			// Use the key being entered, or fall back to existing resolved key
-			apiKey = m.apiKeyInput
+			apiKey = m.apiKeyInput.Value()
 			if apiKey == "" {
 				if p := m.cfg.GetProvider(m.selectedProvider.Name); p != nil {
 					apiKey = p.GetAPIKey()
@@ -204,9 +433,22 @@ func (m *Model) resolveProviderForFetch() (baseURL, apiKey, providerName string)
 			}
 		}
 	case ScreenCustomProvider:
-		providerName = m.customProviderName
-		baseURL = m.customProviderURL
-		apiKey = m.apiKeyInput
+		providerName = m.customProviderName.Value()
+		baseURL = m.customProviderURL.Value()
+		apiKey = m.apiKeyInput.Value()
+	case ScreenModelMappings:
+		// Editing an already configured provider
+		if m.selectedProvider != nil {
+			if p := m.cfg.GetProvider(m.selectedProvider.Name); p != nil {
+				providerName = p.Name
+				baseURL = p.BaseURL
+				apiKey = p.GetAPIKey()
+			}
+		}
+	case ScreenOpenRouter:
+		// OpenRouter lists its models without a key
+		providerName = "openrouter"
+		baseURL = config.OpenRouterBaseURL
 	}
 	return baseURL, apiKey, providerName
 }
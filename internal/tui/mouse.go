@@ -0,0 +1,170 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Clickable parts of the view. List item i is zoneListItem+i.
+const (
+	zoneContinue = 1 + iota
+	zoneLaunch
+	zoneTest
+	zoneScript
+	zoneCancel
+	zoneConfirm
+	zonePicker
+	zoneListItem
+)
+
+// zoneEnd ends a zone started by zoneMark.
+const zoneEnd = "\x1b[0z"
+
+// zoneMarkRe matches the marks zoneMark writes around a zone.
+var zoneMarkRe = regexp.MustCompile(`\x1b\[(\d+)z`)
+
+// zoneMark marks each line of s as part of zone id, so a click on it can be
+// found. The marks take no space and are removed before the view is drawn.
+func zoneMark(id int, s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = fmt.Sprintf("\x1b[%dz", id) + line + zoneEnd
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// zoneRect is where part of a zone is on screen: columns x0 to x1 (not
+// included) of row y.
+type zoneRect struct {
+	id, y, x0, x1 int
+}
+
+// scanZones removes the zone marks from view, recording where each zone is
+// for zoneAt.
+func (m *Model) scanZones(view string) string {
+	m.zones = m.zones[:0]
+	lines := strings.Split(view, "\n")
+	for y, line := range lines {
+		if !strings.Contains(line, "\x1b[") {
+			continue
+		}
+		var b strings.Builder
+		open, x0 := 0, 0
+		rest := line
+		for {
+			loc := zoneMarkRe.FindStringSubmatchIndex(rest)
+			if loc == nil {
+				b.WriteString(rest)
+				break
+			}
+			b.WriteString(rest[:loc[0]])
+			x := lipgloss.Width(b.String())
+			id, _ := strconv.Atoi(rest[loc[2]:loc[3]])
+			if open > 0 {
+				m.zones = append(m.zones, zoneRect{id: open, y: y, x0: x0, x1: x})
+			}
+			open, x0 = id, x
+			rest = rest[loc[1]:]
+		}
+		if open > 0 {
+			m.zones = append(m.zones, zoneRect{id: open, y: y, x0: x0, x1: lipgloss.Width(b.String())})
+		}
+		lines[y] = b.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// zoneAt returns the zone at column x of row y, or 0 if there is none.
+func (m *Model) zoneAt(x, y int) int {
+	for _, z := range m.zones {
+		if z.y == y && x >= z.x0 && x < z.x1 {
+			return z.id
+		}
+	}
+	return 0
+}
+
+// rowZone returns the first zone on row y, or 0 if there is none.
+func (m *Model) rowZone(y int) int {
+	for _, z := range m.zones {
+		if z.y == y {
+			return z.id
+		}
+	}
+	return 0
+}
+
+// updateMouse handles clicks on list items and buttons, and the mouse wheel:
+// over the model picker it moves the selection; elsewhere it scrolls the
+// list or a screen taller than the terminal.
+func (m *Model) updateMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.keyStoring {
+		return m, nil
+	}
+	zone := m.zoneAt(msg.X, msg.Y)
+
+	up := msg.Button == tea.MouseButtonWheelUp
+	if msg.Action == tea.MouseActionPress && (up || msg.Button == tea.MouseButtonWheelDown) {
+		switch {
+		case zone == zonePicker && m.modelPickerOpen:
+			key := tea.KeyMsg{Type: tea.KeyDown}
+			if up {
+				key.Type = tea.KeyUp
+			}
+			_, cmd := m.updateModelPicker(key)
+			return m, cmd
+		case m.screen == ScreenMain && !m.list.SettingFilter():
+			if up {
+				m.list.CursorUp()
+			} else {
+				m.list.CursorDown()
+			}
+			return m, nil
+		}
+		m.updateScroll(msg)
+		return m, nil
+	}
+
+	if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
+		return m, nil
+	}
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	switch m.screen {
+	case ScreenMain:
+		// Anywhere on an item's rows selects it
+		if zone := m.rowZone(msg.Y); zone >= zoneListItem && !m.list.SettingFilter() {
+			m.listStatus = ""
+			m.list.Select(zone - zoneListItem)
+		}
+	case ScreenSuccess:
+		switch zone {
+		case zoneContinue:
+			m.successOption = successContinue
+			return m.updateSuccessScreen(enter)
+		case zoneLaunch:
+			m.successOption = successLaunch
+			return m.updateSuccessScreen(enter)
+		case zoneTest:
+			m.successOption = successTest
+			return m.updateSuccessScreen(enter)
+		case zoneScript:
+			m.successOption = successScript
+			return m.updateSuccessScreen(enter)
+		}
+	case ScreenConfirm:
+		switch zone {
+		case zoneCancel:
+			return m.finishConfirm(false)
+		case zoneConfirm:
+			return m.finishConfirm(true)
+		}
+	}
+	return m, nil
+}
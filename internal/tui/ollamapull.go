@@ -0,0 +1,128 @@
+package tui
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sammcj/skint/internal/models"
+)
+
+// ollamaPull is the state of a model pull started from the local provider
+// form.
+type ollamaPull struct {
+	model    string
+	progress string // the latest update, e.g. "pulling 6a0746a1ec1a 42%"
+	err      string
+	done     bool
+}
+
+// pullProgressMsg is an update from an Ollama pull. The last one for a pull
+// has done set, and err if it failed.
+type pullProgressMsg struct {
+	model    string
+	progress models.PullProgress
+	done     bool
+	err      error
+	updates  <-chan pullProgressMsg
+}
+
+// modelNotPulled reports whether the model field names a model that Ollama,
+// having listed its models, doesn't have.
+func (m *Model) modelNotPulled() bool {
+	value := m.localProviderModel.Value()
+	if m.screen != ScreenProviderConfig || m.selectedProvider == nil || m.selectedProvider.Name != "ollama" {
+		return false
+	}
+	return value != "" && m.fetchedModels != nil && !m.modelFetching && !models.OllamaHas(m.fetchedModels, value)
+}
+
+// startPull pulls the model in the model field into Ollama, streaming its
+// progress to the form until it's done or the form is left.
+func (m *Model) startPull() tea.Cmd {
+	if m.cancelPull != nil || !m.modelNotPulled() {
+		return nil
+	}
+	model := m.localProviderModel.Value()
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancelPull = cancel
+	m.pull = ollamaPull{model: model, progress: "starting"}
+
+	updates := make(chan pullProgressMsg, 16)
+	baseURL := m.localProviderURL.Value()
+	go func() {
+		defer close(updates)
+		err := models.PullOllama(ctx, baseURL, model, func(p models.PullProgress) {
+			select {
+			case updates <- pullProgressMsg{model: model, progress: p, updates: updates}:
+			case <-ctx.Done():
+			}
+		})
+		updates <- pullProgressMsg{model: model, done: true, err: err, updates: updates}
+	}()
+	return waitForPull(updates)
+}
+
+// waitForPull returns a command that waits for the next update from a pull.
+func waitForPull(updates <-chan pullProgressMsg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-updates
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+// stopPull cancels the pull in progress, if any, and forgets its outcome.
+func (m *Model) stopPull() {
+	if m.cancelPull != nil {
+		m.cancelPull()
+		m.cancelPull = nil
+	}
+	m.pull = ollamaPull{}
+}
+
+// pullProgressed shows an update from the pull, and once it succeeds
+// fetches the models again so the pulled one is listed.
+func (m *Model) pullProgressed(msg pullProgressMsg) (tea.Model, tea.Cmd) {
+	if m.cancelPull == nil || msg.model != m.pull.model {
+		// Cancelled: drain the rest
+		if msg.done {
+			return m, nil
+		}
+		return m, waitForPull(msg.updates)
+	}
+	if !msg.done {
+		m.pull.progress = msg.progress.String()
+		return m, waitForPull(msg.updates)
+	}
+	m.cancelPull()
+	m.cancelPull = nil
+	m.pull.done = true
+	if msg.err != nil {
+		m.pull.err = msg.err.Error()
+		return m, nil
+	}
+	if m.screen != ScreenProviderConfig {
+		// Saved while pulling
+		return m, nil
+	}
+	return m, m.triggerModelFetch()
+}
+
+// pullView returns the form's line about the pull or the model not being
+// pulled, or "".
+func (m *Model) pullView() string {
+	pulled := m.pull.model != "" && m.pull.model == m.localProviderModel.Value()
+	switch {
+	case m.pull.model != "" && !m.pull.done:
+		return m.styles.Info.Render("⟳ Pulling " + m.pull.model + ": " + m.pull.progress + " (esc cancels)")
+	case pulled && m.pull.err != "":
+		return m.styles.Error.Render("✗ Pulling " + m.pull.model + " failed: " + m.pull.err)
+	case pulled:
+		return m.styles.Success.Render("✓ Pulled " + m.pull.model)
+	case m.modelNotPulled():
+		return m.styles.Warning.Render("⚠ " + m.localProviderModel.Value() + " hasn't been pulled into Ollama: ctrl+p pulls it")
+	}
+	return ""
+}
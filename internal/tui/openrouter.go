@@ -0,0 +1,434 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sammcj/skint/internal/config"
+)
+
+// openRouterFormFieldCount is the number of fields in the OpenRouter model
+// form (model, short name, API key)
+const openRouterFormFieldCount = 3
+
+// openOpenRouter shows the OpenRouter screen, which manages the or-*
+// providers: one per OpenRouter model, all sharing the OpenRouter API key.
+func (m *Model) openOpenRouter() (tea.Model, tea.Cmd) {
+	m.orIdx = 0
+	m.orEditing = false
+	m.orStatus = ""
+	m.inputError = ""
+	m.screen = ScreenOpenRouter
+	return m, nil
+}
+
+// openRouterProviders returns the configured OpenRouter model providers,
+// sorted by name.
+func (m *Model) openRouterProviders() []*config.Provider {
+	var ps []*config.Provider
+	for _, p := range m.cfg.Providers {
+		if p.Type == config.ProviderTypeOpenRouter && strings.HasPrefix(p.Name, config.OpenRouterPrefix) {
+			ps = append(ps, p)
+		}
+	}
+	slices.SortFunc(ps, func(a, b *config.Provider) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return ps
+}
+
+// openRouterKeyRef returns the reference to the stored OpenRouter API key,
+// or "" if none is stored yet. The key is kept under "openrouter" and shared
+// by the openrouter provider and every or-* provider.
+func (m *Model) openRouterKeyRef() string {
+	if p := m.cfg.GetProvider("openrouter"); p != nil && p.APIKeyRef != "" {
+		return p.APIKeyRef
+	}
+	for _, p := range m.openRouterProviders() {
+		if p.APIKeyRef != "" {
+			return p.APIKeyRef
+		}
+	}
+	return ""
+}
+
+// openRouterShortName derives a provider short name from a model ID, e.g.
+// "gpt-4o" from "openai/gpt-4o".
+func openRouterShortName(modelID string) string {
+	if idx := strings.LastIndex(modelID, "/"); idx >= 0 {
+		modelID = modelID[idx+1:]
+	}
+	var b strings.Builder
+	for _, r := range strings.ToLower(modelID) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func (m *Model) updateOpenRouter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.orEditing {
+		return m.updateOpenRouterForm(msg)
+	}
+
+	ps := m.openRouterProviders()
+	m.orIdx = min(m.orIdx, max(len(ps)-1, 0))
+
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		m.done = true
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.refreshProviderList()
+		m.screen = ScreenMain
+	case tea.KeyUp:
+		m.orIdx = max(m.orIdx-1, 0)
+	case tea.KeyDown:
+		m.orIdx = min(m.orIdx+1, max(len(ps)-1, 0))
+	case tea.KeyEnter:
+		if len(ps) == 0 {
+			return m.editOpenRouterModel(nil, 0)
+		}
+		return m.editOpenRouterModel(ps[m.orIdx], 0)
+	case tea.KeyDelete:
+		if len(ps) > 0 {
+			return m.confirmDeleteOpenRouterModel(ps[m.orIdx])
+		}
+	case tea.KeyRunes:
+		switch msg.String() {
+		case "k":
+			m.orIdx = max(m.orIdx-1, 0)
+		case "j":
+			m.orIdx = min(m.orIdx+1, max(len(ps)-1, 0))
+		case "a", "n":
+			return m.editOpenRouterModel(nil, 0)
+		case "e":
+			if len(ps) > 0 {
+				return m.editOpenRouterModel(ps[m.orIdx], 0)
+			}
+		case "r":
+			if len(ps) > 0 {
+				return m.editOpenRouterModel(ps[m.orIdx], 1)
+			}
+		case "d", "x":
+			if len(ps) > 0 {
+				return m.confirmDeleteOpenRouterModel(ps[m.orIdx])
+			}
+		case "q":
+			m.refreshProviderList()
+			m.screen = ScreenMain
+		case "?":
+			return m.openHelp()
+		}
+	}
+	return m, nil
+}
+
+// editOpenRouterModel opens the form to add a model (p nil) or to edit p,
+// with focus on the given field: 0 for the model, 1 for the short name.
+func (m *Model) editOpenRouterModel(p *config.Provider, focus int) (tea.Model, tea.Cmd) {
+	m.orEditName = ""
+	m.orModelInput = newInput()
+	m.orNameInput = newInput()
+	if p != nil {
+		m.orEditName = p.Name
+		setInput(&m.orModelInput, p.Model)
+		setInput(&m.orNameInput, strings.TrimPrefix(p.Name, config.OpenRouterPrefix))
+	}
+	m.apiKeyInput.Reset()
+	m.hasExistingKey = m.openRouterKeyRef() != ""
+	m.inputFocus = focus
+	m.inputError = ""
+	m.orStatus = ""
+	m.orEditing = true
+	m.resetModelPicker()
+	return m, m.fetchOnModelFocus()
+}
+
+func (m *Model) updateOpenRouterForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Model picker intercepts input when open
+	if consumed, cmd := m.updateModelPicker(msg); consumed {
+		return m, cmd
+	}
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.orEditing = false
+		m.apiKeyInput.Reset()
+		m.inputError = ""
+		m.resetModelPicker()
+		return m, nil
+	case tea.KeyCtrlC:
+		m.done = true
+		return m, tea.Quit
+	case tea.KeyCtrlF:
+		if m.isOnModelField() {
+			return m, m.triggerModelFetch()
+		}
+	case tea.KeyTab, tea.KeyDown:
+		if !m.leaveField() {
+			return m, nil
+		}
+		m.inputFocus = (m.inputFocus + 1) % openRouterFormFieldCount
+		return m, m.fetchOnModelFocus()
+	case tea.KeyShiftTab, tea.KeyUp:
+		if !m.leaveField() {
+			return m, nil
+		}
+		m.inputFocus = (m.inputFocus + openRouterFormFieldCount - 1) % openRouterFormFieldCount
+		return m, m.fetchOnModelFocus()
+	case tea.KeyEnter:
+		return m.submitOpenRouterModel()
+	}
+
+	// Typing and editing go to the focused field
+	return m, m.updateFocusedInput(msg)
+}
+
+// submitOpenRouterModel saves the form as an or-* provider. Renaming keeps
+// the provider's other settings and moves the default, provider order and
+// directory rules to the new name.
+func (m *Model) submitOpenRouterModel() (tea.Model, tea.Cmd) {
+	modelID := strings.TrimSpace(m.orModelInput.Value())
+	if modelID == "" {
+		m.inputError = "Model ID is required"
+		m.inputFocus = 0
+		return m, nil
+	}
+
+	shortName := strings.TrimPrefix(strings.TrimSpace(m.orNameInput.Value()), config.OpenRouterPrefix)
+	if shortName == "" {
+		shortName = openRouterShortName(modelID)
+	}
+	if shortName == "" {
+		m.inputError = "Short name is required"
+		m.inputFocus = 1
+		return m, nil
+	}
+	if err := validateName(shortName); err != "" {
+		m.inputError = err
+		m.inputFocus = 1
+		return m, nil
+	}
+	name := config.OpenRouterPrefix + shortName
+	if name != m.orEditName && m.cfg.GetProvider(name) != nil {
+		m.inputError = fmt.Sprintf("%s already exists", name)
+		m.inputFocus = 1
+		return m, nil
+	}
+
+	save := func(ref string) (tea.Model, tea.Cmd) {
+		return m.saveOpenRouterModel(name, shortName, modelID, ref)
+	}
+
+	// Store a new key in the background, or share the existing one
+	if key := m.apiKeyInput.Value(); key != "" {
+		if err := validateAPIKey(key); err != "" {
+			m.inputError = err
+			m.inputFocus = 2
+			return m, nil
+		}
+		return m.storeKey("openrouter", key, save)
+	}
+	ref := m.openRouterKeyRef()
+	if ref == "" {
+		m.inputError = "API key is required"
+		m.inputFocus = 2
+		return m, nil
+	}
+	return save(ref)
+}
+
+// saveOpenRouterModel saves the form as the or-<shortName> provider name,
+// with ref pointing at the shared key.
+func (m *Model) saveOpenRouterModel(name, shortName, modelID, ref string) (tea.Model, tea.Cmd) {
+	m.pushUndo("save " + name)
+	provider := &config.Provider{Type: config.ProviderTypeOpenRouter}
+	if existing := m.cfg.GetProvider(m.orEditName); m.orEditName != "" && existing != nil {
+		copied := *existing
+		provider = &copied
+		m.cfg.RemoveProvider(existing.Name)
+	}
+	provider.Name = name
+	provider.DisplayName = "OpenRouter " + shortName
+	provider.BaseURL = config.OpenRouterBaseURL
+	provider.Model = modelID
+	provider.APIKeyRef = ref
+	if err := m.cfg.AddProvider(provider); err != nil {
+		m.inputError = err.Error()
+		return m, nil
+	}
+	m.rememberModels("openrouter", modelID)
+	if m.orEditName != "" && m.orEditName != name {
+		m.renameProviderRefs(m.orEditName, name)
+	}
+
+	m.orEditing = false
+	m.apiKeyInput.Reset()
+	m.inputError = ""
+	m.resetModelPicker()
+	m.orIdx = slices.IndexFunc(m.openRouterProviders(), func(p *config.Provider) bool { return p.Name == name })
+	m.orStatus = fmt.Sprintf("✓ %s saved", name)
+	return m, nil
+}
+
+// renameProviderRefs points the default provider, provider order and
+// directory rules that name from at to instead.
+func (m *Model) renameProviderRefs(from, to string) {
+	if m.cfg.DefaultProvider == from {
+		m.cfg.DefaultProvider = to
+	}
+	for i, n := range m.cfg.ProviderOrder {
+		if n == from {
+			m.cfg.ProviderOrder[i] = to
+		}
+	}
+	for i := range m.cfg.DirectoryRules {
+		if m.cfg.DirectoryRules[i].Provider == from {
+			m.cfg.DirectoryRules[i].Provider = to
+		}
+	}
+}
+
+// dropProviderRefs clears the default provider, and removes the provider order
+// entries and directory rules, that name a deleted provider.
+func (m *Model) dropProviderRefs(name string) {
+	if m.cfg.DefaultProvider == name {
+		m.cfg.DefaultProvider = ""
+	}
+	m.cfg.ProviderOrder = slices.DeleteFunc(m.cfg.ProviderOrder, func(n string) bool { return n == name })
+	m.cfg.DirectoryRules = slices.DeleteFunc(m.cfg.DirectoryRules, func(r config.DirectoryRule) bool { return r.Provider == name })
+}
+
+// confirmDeleteOpenRouterModel asks before deleting an or-* provider. The
+// shared API key is kept while other providers use it.
+func (m *Model) confirmDeleteOpenRouterModel(p *config.Provider) (tea.Model, tea.Cmd) {
+	message := fmt.Sprintf("Delete %s (%s)?", p.Name, p.Model)
+	return m.confirm(message, "Delete", func() (tea.Model, tea.Cmd) {
+		if m.cfg.GetProvider(p.Name) != nil {
+			m.pushUndo("delete " + p.Name)
+			m.cfg.RemoveProvider(p.Name)
+			m.deleteUnusedKey(p.APIKeyRef)
+			m.dropProviderRefs(p.Name)
+			m.orStatus = fmt.Sprintf("✓ %s deleted", p.Name)
+		}
+		m.orIdx = max(m.orIdx-1, 0)
+		m.screen = ScreenOpenRouter
+		return m, nil
+	})
+}
+
+func (m *Model) viewOpenRouter() string {
+	var b strings.Builder
+
+	title := "OpenRouter"
+	if m.orEditing {
+		title = "OpenRouter › Add Model"
+		if m.orEditName != "" {
+			title = "OpenRouter › " + m.orEditName
+		}
+	}
+
+	// Compact header with breadcrumb
+	breadcrumbText := m.styles.Subtitle.UnsetMarginBottom().Render(title)
+	header := m.styles.HeaderLine.Render("Skint") +
+		m.styles.HeaderSep.Render(" › ") + breadcrumbText
+	b.WriteString(header)
+	b.WriteString("\n")
+	b.WriteString(m.styles.Dimmed.Render("Each model is a provider of its own, launched with skint use or-<name>. They share one API key."))
+	b.WriteString("\n\n")
+
+	if m.orEditing {
+		b.WriteString(m.viewOpenRouterForm())
+		return b.String()
+	}
+
+	key := m.styles.Warning.Render("not set - add a model to set it")
+	if ref := m.openRouterKeyRef(); ref != "" {
+		key = m.styles.Value.Render(keyStorage(&config.Provider{Type: config.ProviderTypeOpenRouter, APIKeyRef: ref}))
+	}
+	b.WriteString(m.styles.Label.Render(fmt.Sprintf("%-10s", "API key")) + key)
+	b.WriteString("\n\n")
+
+	ps := m.openRouterProviders()
+	if len(ps) == 0 {
+		b.WriteString(m.styles.Dimmed.Render("No OpenRouter models - press a to add one"))
+		b.WriteString("\n")
+	}
+	nameWidth := 0
+	for _, p := range ps {
+		nameWidth = max(nameWidth, len(p.Name))
+	}
+	for i, p := range ps {
+		line := fmt.Sprintf("%-*s  %s", nameWidth, p.Name, p.Model)
+		if i == m.orIdx {
+			b.WriteString(focusMark + m.styles.ListSelected.Render("> "+line))
+		} else {
+			b.WriteString(m.styles.Normal.Render("  " + line))
+		}
+		if m.cfg.DefaultProvider == p.Name {
+			b.WriteString(m.styles.Dimmed.Render(" (default)"))
+		}
+		b.WriteString("\n")
+	}
+
+	if m.orStatus != "" {
+		b.WriteString("\n")
+		b.WriteString(m.styles.Success.Render(m.orStatus))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	help := m.styles.Help.Render("a add  e/enter edit  r rename  d delete  ? help  esc back")
+	b.WriteString(m.styles.Footer.Render(help))
+
+	return b.String()
+}
+
+func (m *Model) viewOpenRouterForm() string {
+	var b strings.Builder
+
+	inputWidth := m.width - 20
+	inputWidth = max(inputWidth, 30)
+
+	b.WriteString(m.renderInputField("Model ID", m.orModelInput, "e.g. openai/gpt-4o", 0, true, inputWidth))
+
+	// Model picker, above the fields below the model
+	if pickerView := m.renderModelPicker(); pickerView != "" {
+		b.WriteString(strings.TrimSuffix(pickerView, "\n"))
+		b.WriteString("\n")
+	}
+
+	nameHint := "derived from the model ID"
+	if derived := openRouterShortName(m.orModelInput.Value()); derived != "" {
+		nameHint = derived
+	}
+	b.WriteString(m.renderInputField("Short name (or-…)", m.orNameInput, nameHint, 1, false, inputWidth))
+
+	keyHint := "Type your OpenRouter API key..."
+	if m.hasExistingKey {
+		keyHint = "Key saved - leave blank to keep, or type to replace"
+	}
+	b.WriteString(m.renderInputField("API Key (shared)", m.apiKeyInput, keyHint, 2, !m.hasExistingKey, inputWidth))
+	b.WriteString("\n")
+	b.WriteString(m.renderKeyStoring())
+
+	// Error message
+	if msg := m.formError(); msg != "" {
+		b.WriteString(m.styles.Error.Render("✗ " + msg))
+		b.WriteString("\n")
+	}
+
+	// Two-line help
+	navHelp := m.styles.Help.Render("↑/↓/tab navigate  enter save  esc cancel")
+	helpContent := navHelp
+	if hint := m.modelPickerHelpHint(); hint != "" {
+		helpContent += "\n" + m.styles.Help.Render(hint)
+	}
+	b.WriteString(m.styles.Footer.Render(helpContent))
+
+	return b.String()
+}
@@ -0,0 +1,282 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/httpretry"
+	"github.com/sammcj/skint/internal/models"
+)
+
+// providerTest is the state of one provider's connectivity test.
+type providerTest struct {
+	name        string
+	displayName string
+	done        bool
+	reachable   bool
+	statusCode  int
+	latency     time.Duration
+	err         string
+	// server is what llama-server reported, for llama.cpp
+	server *models.LlamaCppStatus
+}
+
+// summary describes a finished test, e.g. "HTTP 200 · 84ms".
+func (t providerTest) summary() string {
+	if !t.reachable {
+		return t.err
+	}
+	summary := fmt.Sprintf("HTTP %d · %s", t.statusCode, t.latency.Round(time.Millisecond))
+	if t.server != nil {
+		summary += " · " + t.server.String()
+	}
+	return summary
+}
+
+// providerTestedMsg is sent when a provider test completes. Results from an
+// earlier run (generation) are discarded.
+type providerTestedMsg struct {
+	generation int
+	result     providerTest
+}
+
+// newProviderTest returns a pending test for p.
+func newProviderTest(p *config.Provider) providerTest {
+	t := providerTest{name: p.Name, displayName: p.DisplayName}
+	if t.displayName == "" {
+		t.displayName = p.Name
+	}
+	return t
+}
+
+// testURL returns the URL to probe for p, or "" if it has none.
+func testURL(p *config.Provider) string {
+	if p.BaseURL != "" {
+		return p.BaseURL
+	}
+	if p.Name == "native" {
+		return "https://api.anthropic.com"
+	}
+	return ""
+}
+
+// testProviderCmd probes the provider's endpoint in the background for the
+// test screen.
+func testProviderCmd(p *config.Provider, timeout time.Duration, generation int) tea.Cmd {
+	test := newProviderTest(p)
+	url := testURL(p)
+	return func() tea.Msg {
+		return providerTestedMsg{generation: generation, result: probe(url, test, timeout)}
+	}
+}
+
+// itemTestedMsg is sent when a test started from the provider list completes.
+type itemTestedMsg struct {
+	result providerTest
+}
+
+// testItemCmd probes the provider's endpoint in the background for the
+// provider list.
+func testItemCmd(p *config.Provider, timeout time.Duration) tea.Cmd {
+	test := newProviderTest(p)
+	url := testURL(p)
+	return func() tea.Msg {
+		return itemTestedMsg{result: probe(url, test, timeout)}
+	}
+}
+
+// probe requests url, giving up after timeout, and completes test with the
+// outcome. Any HTTP response counts as reachable. For llama.cpp its health
+// endpoint is asked instead when it answers, for the model's state.
+func probe(url string, test providerTest, timeout time.Duration) providerTest {
+	if test.name == "llamacpp" {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		start := time.Now()
+		if status, err := models.LlamaCppServerStatus(ctx, url); err == nil {
+			test.latency = time.Since(start)
+			test.done = true
+			test.reachable = true
+			test.statusCode = status.StatusCode
+			test.server = &status
+			return test
+		}
+	}
+
+	client := &http.Client{
+		Timeout: timeout,
+		CheckRedirect: func(_ *http.Request, _ []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	start := time.Now()
+	var resp *http.Response
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err == nil {
+		resp, err = httpretry.Do(client, req)
+	}
+	test.latency = time.Since(start)
+	test.done = true
+	if err != nil {
+		test.err = err.Error()
+		return test
+	}
+	resp.Body.Close()
+	test.reachable = true
+	test.statusCode = resp.StatusCode
+	return test
+}
+
+// testSelectedItem tests the selected provider, showing the result next to
+// it in the list.
+func (m *Model) testSelectedItem() (tea.Model, tea.Cmd) {
+	item, ok := m.list.SelectedItem().(ProviderItem)
+	if !ok || item.isAddNew {
+		return m, nil
+	}
+	if t, ok := m.itemTests[item.definition.Name]; ok && !t.done {
+		return m, nil // already testing
+	}
+
+	p := m.cfg.GetProvider(item.definition.Name)
+	if p == nil {
+		// Not configured yet, but the endpoint can still be checked
+		p = &config.Provider{Name: item.definition.Name, DisplayName: item.definition.DisplayName, BaseURL: item.definition.BaseURL}
+	}
+	if testURL(p) == "" {
+		return m, nil
+	}
+
+	return m, tea.Batch(m.setItemTest(newProviderTest(p)), testItemCmd(p, m.cfg.NetworkTimeout()))
+}
+
+// setItemTest records a list test and shows it on the provider's item. The
+// command it returns filters the list again when a filter is applied.
+func (m *Model) setItemTest(t providerTest) tea.Cmd {
+	if m.itemTests == nil {
+		m.itemTests = make(map[string]providerTest)
+	}
+	m.itemTests[t.name] = t
+	var cmds []tea.Cmd
+	for i, li := range m.list.Items() {
+		if item, ok := li.(ProviderItem); ok && !item.isAddNew && item.definition.Name == t.name {
+			item.test = &t
+			cmds = append(cmds, m.list.SetItem(i, item))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// startProviderTests opens the test screen and tests every configured
+// provider concurrently.
+func (m *Model) startProviderTests() (tea.Model, tea.Cmd) {
+	m.testGeneration++
+	m.tests = nil
+	m.screen = ScreenTest
+
+	cmds := []tea.Cmd{m.spinner.Tick}
+	for _, p := range m.cfg.Providers {
+		if !p.IsConfigured() || testURL(p) == "" {
+			continue
+		}
+		m.tests = append(m.tests, newProviderTest(p))
+		cmds = append(cmds, testProviderCmd(p, m.cfg.NetworkTimeout(), m.testGeneration))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// testsRunning reports whether any test is still waiting for a result.
+func (m *Model) testsRunning() bool {
+	for _, t := range m.tests {
+		if !t.done {
+			return true
+		}
+	}
+	return false
+}
+
+// recordTestResult stores a finished test.
+func (m *Model) recordTestResult(msg providerTestedMsg) {
+	if msg.generation != m.testGeneration {
+		return
+	}
+	for i := range m.tests {
+		if m.tests[i].name == msg.result.name {
+			m.tests[i] = msg.result
+		}
+	}
+}
+
+func (m *Model) updateTestScreen(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		m.done = true
+		return m, tea.Quit
+	case tea.KeyEsc, tea.KeyEnter:
+		m.screen = ScreenMain
+	case tea.KeyRunes:
+		switch msg.String() {
+		case "q":
+			m.screen = ScreenMain
+		case "r":
+			if !m.testsRunning() {
+				return m.startProviderTests()
+			}
+		case "?":
+			return m.openHelp()
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) viewTestScreen() string {
+	var b strings.Builder
+
+	// Compact header
+	header := m.styles.HeaderLine.Render("Skint") +
+		m.styles.HeaderSep.Render(" › ") +
+		m.styles.Title.Render("Provider Tests")
+	b.WriteString(header)
+	b.WriteString("\n\n")
+
+	if len(m.tests) == 0 {
+		b.WriteString(m.styles.Dimmed.Render("No configured providers to test."))
+		b.WriteString("\n\n")
+	}
+
+	reachable, failed := 0, 0
+	for _, t := range m.tests {
+		name := m.styles.Normal.Render(fmt.Sprintf("%-24s", t.displayName))
+		switch {
+		case !t.done:
+			b.WriteString(m.spinner.View() + " " + name + m.styles.Dimmed.Render("testing..."))
+		case t.reachable:
+			reachable++
+			b.WriteString(m.styles.Success.Render("✓") + " " + name + m.styles.Dimmed.Render(t.summary()))
+		default:
+			failed++
+			b.WriteString(m.styles.Error.Render("✗") + " " + name + m.styles.Error.Render(t.summary()))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(m.tests) > 0 && !m.testsRunning() {
+		b.WriteString("\n")
+		b.WriteString(m.styles.Success.Render(fmt.Sprintf("%d reachable", reachable)) +
+			m.styles.Dimmed.Render(", ") +
+			m.styles.Error.Render(fmt.Sprintf("%d failed", failed)))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	help := m.styles.Help.Render("r re-run  ? help  esc back")
+	b.WriteString(m.styles.Footer.Render(help))
+
+	return b.String()
+}
@@ -2,24 +2,26 @@ package tui
 
 import (
 	"fmt"
-	"net/http"
 	"os"
-	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/sammcj/skint/internal/config"
 	"github.com/sammcj/skint/internal/secrets"
 )
 
-// RunConfigTUI runs the configuration TUI and returns the result
-func RunConfigTUI(cfg *config.Config, secretsMgr *secrets.Manager) (*ConfigResult, error) {
+// RunConfigTUI runs the configuration TUI and returns the result. The TUI
+// opens on provider's config form if provider is not empty, or otherwise on
+// the provider that was selected when it last closed. With inline, the TUI is
+// drawn in the terminal's normal screen instead of the alternate one, so it
+// stays in the scrollback.
+func RunConfigTUI(cfg *config.Config, secretsMgr *secrets.Manager, provider string, inline bool) (*ConfigResult, error) {
 	model := NewModel(cfg, secretsMgr)
+	model.Restore(LoadState())
+	if provider != "" {
+		model.OpenProvider(provider)
+	}
 
-	p := tea.NewProgram(
-		model,
-		tea.WithAltScreen(),
-		tea.WithMouseCellMotion(),
-	)
+	p := tea.NewProgram(model, programOptions(inline)...)
 
 	finalModel, err := p.Run()
 	if err != nil {
@@ -30,6 +32,8 @@ func RunConfigTUI(cfg *config.Config, secretsMgr *secrets.Manager) (*ConfigResul
 	if !ok {
 		return nil, fmt.Errorf("TUI returned unexpected model type: %T", finalModel)
 	}
+	// Losing the state only costs the selection next time
+	_ = SaveState(m.State())
 
 	return &ConfigResult{
 		Done:             m.IsDone(),
@@ -38,10 +42,21 @@ func RunConfigTUI(cfg *config.Config, secretsMgr *secrets.Manager) (*ConfigResul
 	}, nil
 }
 
+// programOptions returns the options for the TUI's program. It runs inline
+// when asked to, or when stdout isn't a terminal (e.g. skint run from a
+// script). The mouse is only enabled on the alternate screen, where clicks
+// can be matched to the view.
+func programOptions(inline bool) []tea.ProgramOption {
+	if inline || !isTerminal(os.Stdout) {
+		return nil
+	}
+	return []tea.ProgramOption{tea.WithAltScreen(), tea.WithMouseCellMotion()}
+}
+
 // ConfigResult holds the result of the TUI
 type ConfigResult struct {
 	Done             bool
-	Action           string // "", "test", "launch"
+	Action           string // "", "launch"
 	SelectedProvider string
 }
 
@@ -49,98 +64,31 @@ type ConfigResult struct {
 // The caller (root command) provides this, wiring up the launcher and secrets.
 type LaunchFunc func(providerName string) error
 
-// RunInteractive runs the full interactive TUI for configuration.
-// Loops back to the TUI after test actions; exits on quit or launch.
-func RunInteractive(cfg *config.Config, secretsMgr *secrets.Manager, saveFn func() error, launchFn LaunchFunc) error {
-	for {
-		result, err := RunConfigTUI(cfg, secretsMgr)
-		if err != nil {
-			return err
-		}
-
-		// Save config if modified
-		if saveFn != nil && result.Done {
-			if err := saveFn(); err != nil {
-				return fmt.Errorf("failed to save config: %w", err)
-			}
-		}
-
-		switch result.Action {
-		case "launch":
-			providerName := cfg.DefaultProvider
-			if providerName == "" || providerName == "native" {
-				return launchFn("")
-			}
-			return launchFn(providerName)
-
-		case "test":
-			runProviderTests(cfg)
-			// Loop back to TUI
-			continue
-
-		default:
-			// Normal quit
-			return nil
-		}
+// RunInteractive runs the full interactive TUI for configuration, then
+// launches Claude if that is how the user left it. See RunConfigTUI for
+// inline.
+func RunInteractive(cfg *config.Config, secretsMgr *secrets.Manager, saveFn func() error, launchFn LaunchFunc, inline bool) error {
+	result, err := RunConfigTUI(cfg, secretsMgr, "", inline)
+	if err != nil {
+		return err
 	}
-}
-
-// runProviderTests tests connectivity to all configured providers
-func runProviderTests(cfg *config.Config) {
-	fmt.Print("\033[H\033[2J")
-	fmt.Println("Testing Provider Connectivity")
-	fmt.Println("-----------------------------")
-	fmt.Println()
-
-	tested := 0
-	ok := 0
-	failed := 0
 
-	for _, p := range cfg.Providers {
-		if !p.IsConfigured() {
-			continue
+	// Save config if modified
+	if saveFn != nil && result.Done {
+		if err := saveFn(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
 		}
-
-		testURL := p.BaseURL
-		if testURL == "" {
-			if p.Name == "native" {
-				testURL = "https://api.anthropic.com"
-			} else {
-				continue
-			}
-		}
-
-		tested++
-		fmt.Printf("  %-20s ", p.DisplayName)
-
-		client := &http.Client{
-			Timeout: 5 * time.Second,
-			CheckRedirect: func(_ *http.Request, _ []*http.Request) error {
-				return http.ErrUseLastResponse
-			},
-		}
-
-		resp, err := client.Get(testURL)
-		if err != nil {
-			fmt.Printf("✗ unreachable (%v)\n", err)
-			failed++
-			continue
-		}
-		resp.Body.Close()
-
-		fmt.Printf("✓ reachable (HTTP %d)\n", resp.StatusCode)
-		ok++
 	}
 
-	if tested == 0 {
-		fmt.Println("  No configured providers to test.")
+	if result.Action != "launch" {
+		// Normal quit
+		return nil
 	}
-
-	fmt.Println()
-	fmt.Printf("Results: %d reachable, %d failed\n", ok, failed)
-	fmt.Println()
-	fmt.Println("Press Enter to return to Skint...")
-	_, _ = fmt.Scanln()
+	providerName := cfg.DefaultProvider
+	if providerName == "" || providerName == "native" {
+		return launchFn("")
+	}
+	return launchFn(providerName)
 }
 
 // RunProviderPicker runs a simple provider picker and returns the selected provider
@@ -170,14 +118,14 @@ func CheckTerminal() bool {
 		return false
 	}
 
-	stat, err := os.Stdin.Stat()
-	if err != nil {
-		return false
-	}
+	return isTerminal(os.Stdin)
+}
 
-	if (stat.Mode() & os.ModeCharDevice) == 0 {
+// isTerminal reports whether f is a terminal.
+func isTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	if err != nil {
 		return false
 	}
-
-	return true
+	return stat.Mode()&os.ModeCharDevice != 0
 }
@@ -2,18 +2,23 @@ package tui
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"unicode/utf8"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/models"
+	"github.com/sammcj/skint/internal/ui"
 )
 
 // renderModelPicker renders the model picker as a bordered overlay.
 func (m *Model) renderModelPicker() string {
-	if m.modelFetching {
+	if m.modelFetching && len(m.fetchedModels) == 0 {
 		return m.styles.Dimmed.Render("  Fetching models...")
 	}
-	if m.modelFetchErr != "" {
+	if m.modelFetchErr != "" && len(m.fetchedModels) == 0 {
 		return m.styles.Dimmed.Render("  Could not fetch models: " + m.modelFetchErr)
 	}
 	if !m.modelPickerOpen || len(m.fetchedModels) == 0 {
@@ -23,6 +28,9 @@ func (m *Model) renderModelPicker() string {
 	filtered := m.filteredModels()
 	if len(filtered) == 0 {
 		content := m.styles.Dimmed.Render("No models match filter")
+		if m.modelToolsOnly {
+			content = m.styles.Dimmed.Render("No models match filter (ctrl+t shows models without tool calling)")
+		}
 		pickerWidth := m.width - 16
 		pickerWidth = max(pickerWidth, 30)
 		return m.styles.PickerBox.Width(pickerWidth).Render(content) + "\n"
@@ -45,13 +53,46 @@ func (m *Model) renderModelPicker() string {
 		}
 	}
 
+	pickerWidth := m.width - 16
+	pickerWidth = max(pickerWidth, 30)
+
+	// Label and metadata column widths for the visible rows. The row prefix,
+	// border, padding and selected row's margin take 10 cells.
+	labelWidth, columns := pickerLayout(filtered[start:end], pickerWidth-10)
+	if len(columns) > 0 {
+		header := fmt.Sprintf("    %-*s", labelWidth, "")
+		for _, c := range columns {
+			header += fmt.Sprintf("  %-*s", c.width, c.title)
+		}
+		inner.WriteString(m.styles.Dimmed.Render(strings.TrimRight(header, " ")))
+		inner.WriteString("\n")
+	}
+
 	for i := start; i < end; i++ {
 		mi := filtered[i]
-		label := mi.Label()
+		// Recent and recommended models get their own sections
+		if filtered[0].section != sectionAll && (i == start || filtered[i-1].section != mi.section) {
+			inner.WriteString(m.styles.Dimmed.Render("  "+mi.section) + "\n")
+		}
+		base := m.styles.Dimmed
 		if i == m.modelPickerIdx {
-			inner.WriteString(m.styles.ListSelected.Render("> " + label))
+			base = lipgloss.NewStyle().Foreground(m.styles.PrimaryColor).Bold(true)
+		}
+		label := lipgloss.NewStyle().MaxWidth(labelWidth).Render(
+			highlightMatches(mi.Label(), mi.matched, base, m.styles.PickerMatch))
+		row := label
+		if len(columns) > 0 {
+			row += strings.Repeat(" ", labelWidth-lipgloss.Width(label))
+			var meta strings.Builder
+			for _, c := range columns {
+				fmt.Fprintf(&meta, "  %-*s", c.width, c.value(mi.ModelInfo))
+			}
+			row += m.styles.Dimmed.Render(strings.TrimRight(meta.String(), " "))
+		}
+		if i == m.modelPickerIdx {
+			inner.WriteString(m.styles.ListSelected.Render(base.Render("> ") + row))
 		} else {
-			inner.WriteString(m.styles.Dimmed.Render("  " + label))
+			inner.WriteString(m.styles.Dimmed.Render("    ") + row)
 		}
 		if i < end-1 {
 			inner.WriteString("\n")
@@ -68,16 +109,48 @@ func (m *Model) renderModelPicker() string {
 	if filterVal := m.getModelValue(); filterVal != "" {
 		titleLine += m.styles.Dimmed.Render(fmt.Sprintf(" [filter: %s]", filterVal))
 	}
+	if m.modelToolsOnly {
+		titleLine += m.styles.Dimmed.Render(" [tools only]")
+	}
+	if m.modelFetching {
+		titleLine += m.styles.Dimmed.Render(" (refreshing...)")
+	}
 
-	pickerWidth := m.width - 16
-	pickerWidth = max(pickerWidth, 30)
-	return m.styles.PickerBox.Width(pickerWidth).Render(titleLine+"\n"+inner.String()) + "\n"
+	return zoneMark(zonePicker, m.styles.PickerBox.Width(pickerWidth).Render(titleLine+"\n"+inner.String())) + "\n"
 }
 
 // renderFormField renders a single form field with consistent container styling.
 // When focused: primary-coloured border. When unfocused: dim border container.
-// For masked fields (isMasked=true), if value equals hint the field is treated as empty.
-func (m *Model) renderFormField(label, value, hint string, focusIdx int, required, isMasked bool, inputWidth int) string {
+// An empty value shows the hint instead.
+func (m *Model) renderFormField(label, value, hint string, focusIdx int, required bool, inputWidth int) string {
+	displayValue := m.styles.Value.Render(value)
+	if value == "" {
+		displayValue = m.styles.Dimmed.Render(hint)
+	}
+	return m.renderFieldBox(label, displayValue, focusIdx, required, inputWidth)
+}
+
+// renderInputField renders a form field backed by a text input. The focused
+// field shows the input with its cursor; others show the value, masked for
+// password inputs.
+func (m *Model) renderInputField(label string, in textinput.Model, hint string, focusIdx int, required bool, inputWidth int) string {
+	if m.inputFocus != focusIdx {
+		value := in.Value()
+		if in.EchoMode == textinput.EchoPassword {
+			value = strings.Repeat(string(in.EchoCharacter), utf8.RuneCountInString(value))
+		}
+		return m.renderFormField(label, value, hint, focusIdx, required, inputWidth)
+	}
+
+	in.Placeholder = hint
+	in.PlaceholderStyle = m.styles.Dimmed
+	in.Width = max(inputWidth-3, 1) // padding and the cursor cell
+	return m.renderFieldBox(label, in.View(), focusIdx, required, inputWidth)
+}
+
+// renderFieldBox renders a field's label above its bordered content, and any
+// validation error below it.
+func (m *Model) renderFieldBox(label, content string, focusIdx int, required bool, inputWidth int) string {
 	var b strings.Builder
 
 	labelStyle := m.styles.Label
@@ -90,39 +163,38 @@ func (m *Model) renderFormField(label, value, hint string, focusIdx int, require
 		reqIndicator = m.styles.Error.Render("*")
 	}
 
+	if m.inputFocus == focusIdx {
+		b.WriteString(focusMark)
+	}
 	b.WriteString(labelStyle.Render(label) + reqIndicator)
 	b.WriteString("\n")
 
-	displayValue := value
-	isEmpty := value == "" || (isMasked && value == hint)
-	if isEmpty {
-		displayValue = hint
-	}
-
 	if m.inputFocus == focusIdx {
 		// Focused: primary border
-		b.WriteString(m.styles.Input.Width(inputWidth).Render(displayValue))
+		b.WriteString(m.styles.Input.Width(inputWidth).Render(content))
 	} else {
 		// Unfocused: dim border container
-		if isEmpty {
-			b.WriteString(m.styles.InputInactive.Width(inputWidth).Render(
-				m.styles.Dimmed.Render(displayValue),
-			))
-		} else {
-			b.WriteString(m.styles.InputInactive.Width(inputWidth).Render(
-				m.styles.Value.Render(displayValue),
-			))
-		}
+		b.WriteString(m.styles.InputInactive.Width(inputWidth).Render(content))
 	}
 	b.WriteString("\n")
 
+	// Problems with what has been typed show under the field
+	if msg := m.fieldMessage(focusIdx); msg != "" {
+		b.WriteString(m.styles.Error.Render("✗ " + msg))
+		b.WriteString("\n")
+	}
+
 	return b.String()
 }
 
 // modelPickerHelpHint returns help text for the model picker based on current state.
 func (m *Model) modelPickerHelpHint() string {
 	if m.modelPickerOpen {
-		return "↑/↓: select model • enter: confirm • esc: close • type: filter"
+		hint := "↑/↓: select model • enter: confirm • esc: close • type: filter"
+		if slices.ContainsFunc(m.fetchedModels, func(mi models.ModelInfo) bool { return mi.Capabilities != nil }) {
+			hint += " • ctrl+t: tools only"
+		}
+		return hint
 	}
 	if m.isOnModelField() && len(m.fetchedModels) > 0 {
 		return "ctrl+f: re-fetch models"
@@ -136,6 +208,11 @@ func (m *Model) modelPickerHelpHint() string {
 func (m *Model) viewMainScreen() string {
 	var b strings.Builder
 
+	if m.showBanner() {
+		b.WriteString(lipgloss.NewStyle().Foreground(m.styles.PrimaryColor).Render(strings.TrimSuffix(ui.Banner, "\n")))
+		b.WriteString("\n\n")
+	}
+
 	// Compact single-line header
 	configuredCount := 0
 	for _, pi := range m.providerList {
@@ -158,20 +235,40 @@ func (m *Model) viewMainScreen() string {
 	sep := m.styles.HeaderSep.Render(" · ")
 	header := m.styles.HeaderLine.Render("Skint") +
 		sep + m.styles.Dimmed.Render("active: ") +
-		lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Bold(true).Render(activeDisplayName) +
+		lipgloss.NewStyle().Foreground(m.styles.BrightColor).Bold(true).Render(activeDisplayName) +
 		sep + m.styles.Dimmed.Render(fmt.Sprintf("%d configured", configuredCount)) +
 		sep + m.styles.Success.Render("✓") + m.styles.Dimmed.Render(" configured  ") +
-		lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Bold(true).Render("█") + m.styles.Dimmed.Render(" active")
+		lipgloss.NewStyle().Foreground(m.styles.BrightColor).Bold(true).Render("█") + m.styles.Dimmed.Render(" active")
 	b.WriteString(header)
-	b.WriteString("\n\n")
+	b.WriteString("\n")
+
+	// Prompt to set up a local server that is running but not configured
+	if server, ok := m.unconfiguredServer(); ok {
+		b.WriteString(m.styles.Info.Render(fmt.Sprintf("%s detected at %s but not configured", server.Name, server.BaseURL)) +
+			m.styles.Dimmed.Render(" - press l to set it up"))
+		b.WriteString("\n")
+	}
+	if m.listStatus != "" {
+		b.WriteString(m.styles.Info.Render(m.listStatus))
+		b.WriteString("\n")
+	}
+	switch {
+	case m.list.SettingFilter():
+		b.WriteString(m.list.FilterInput.View())
+		b.WriteString("\n")
+	case m.list.IsFiltered():
+		b.WriteString(m.styles.Info.Render("/ "+m.list.FilterValue()) + m.styles.Dimmed.Render("  esc to clear"))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
 
 	// List
 	b.WriteString(m.styles.List.Render(m.list.View()))
 	b.WriteString("\n")
 
 	// Two-line help bar
-	navHelp := m.styles.Help.Render("↑/k ↓/j navigate  enter select  esc back")
-	actHelp := m.styles.Help.Render("e edit  a/c add custom  u launch  t test  q quit")
+	navHelp := m.styles.Help.Render("↑/k ↓/j navigate  enter/1-9 select  / filter  esc back")
+	actHelp := m.styles.Help.Render("i details  y yaml  e edit  m model tiers  d delete  ctrl+z undo  a/c add custom  u launch  t test all  T test  s settings  ? help  q quit")
 	b.WriteString(m.styles.Footer.Render(navHelp + "\n" + actHelp))
 
 	return b.String()
@@ -210,18 +307,18 @@ func (m *Model) viewProviderConfig() string {
 
 	fields := []struct {
 		label string
-		value string
+		input textinput.Model
 		focus int
 		hint  string
 		req   bool
 	}{
 		{"Base URL", m.localProviderURL, 0, m.selectedProvider.BaseURL, true},
 		{"Auth Token", m.localProviderAuthToken, 1, "optional", false},
-		{"Model", m.localProviderModel, 2, "e.g., qwen3-coder", false},
+		{"Model", m.localProviderModel, 2, m.modelHint(m.selectedProvider.Name), false},
 	}
 
 	for _, f := range fields {
-		b.WriteString(m.renderFormField(f.label, f.value, f.hint, f.focus, f.req, false, inputWidth))
+		b.WriteString(m.renderInputField(f.label, f.input, f.hint, f.focus, f.req, inputWidth))
 
 		// Render model picker after the model field
 		if f.focus == 2 {
@@ -234,8 +331,14 @@ func (m *Model) viewProviderConfig() string {
 	}
 
 	// Error message
-	if m.inputError != "" {
-		b.WriteString(m.styles.Error.Render("✗ " + m.inputError))
+	if msg := m.formError(); msg != "" {
+		b.WriteString(m.styles.Error.Render("✗ " + msg))
+		b.WriteString("\n")
+	} else if msg := m.modelLoadWarning(); msg != "" {
+		b.WriteString(m.styles.Warning.Render("⚠ " + msg))
+		b.WriteString("\n")
+	} else if pull := m.pullView(); pull != "" {
+		b.WriteString(pull)
 		b.WriteString("\n")
 	}
 
@@ -254,21 +357,42 @@ func (m *Model) viewProviderConfig() string {
 	return b.String()
 }
 
+// modelHint returns the placeholder for provider's model field: its first
+// recommended model, if it has any.
+func (m *Model) modelHint(provider string) string {
+	if recommended := m.recommendations.For(provider); len(recommended) > 0 {
+		return "e.g., " + recommended[0]
+	}
+	return "model name"
+}
+
 func (m *Model) getLocalProviderInstructions() string {
+	recommended := m.recommendations.For(m.selectedProvider.Name)
+	var list strings.Builder
+	if len(recommended) > 0 {
+		list.WriteString("\n\nRecommended models:")
+		for _, id := range recommended {
+			list.WriteString("\n  • " + id)
+		}
+	}
+
 	switch m.selectedProvider.Name {
 	case "ollama":
+		pull := "<model>"
+		if len(recommended) > 0 {
+			pull = recommended[0]
+		}
 		return `Ollama serves local models with an Anthropic-compatible API.
 
 Setup:
   1. Install Ollama: https://ollama.com
-  2. Pull a model: ollama pull qwen3-coder
-  3. Start serving: ollama serve
-
-Recommended models:
-  • qwen3-coder
-  • glm-5
-  • gpt-oss:20b`
+  2. Pull a model: ollama pull ` + pull + `
+  3. Start serving: ollama serve` + list.String()
 	case "lmstudio":
+		model := "<model-name>"
+		if len(recommended) > 0 {
+			model = recommended[0]
+		}
 		return `LM Studio runs local models with an Anthropic-compatible API.
 
 Setup:
@@ -277,7 +401,7 @@ Setup:
   3. Start the server (port 1234)
 
 Usage:
-  skint use lmstudio --model <model-name>`
+  skint use lmstudio --model ` + model + list.String()
 	case "llamacpp":
 		return `llama.cpp's llama-server with Anthropic-compatible API.
 
@@ -325,19 +449,15 @@ func (m *Model) viewAPIKeyInput() string {
 	if m.hasExistingKey {
 		emptyPlaceholder = "Key saved - leave blank to keep, or type to replace"
 	}
-	masked := strings.Repeat("•", len(m.apiKeyInput))
-	if masked == "" {
-		masked = emptyPlaceholder
-	}
-	b.WriteString(m.renderFormField("API Key", masked, emptyPlaceholder, 0, apiKeyRequired, true, inputWidth))
+	b.WriteString(m.renderInputField("API Key", m.apiKeyInput, emptyPlaceholder, 0, apiKeyRequired, inputWidth))
 
 	// Model field
 	modelRequired := m.selectedProvider.DefaultModel == "" && len(m.selectedProvider.ModelMappings) == 0
-	modelHint := "e.g., anthropic/claude-sonnet-4"
+	modelHint := m.modelHint(m.selectedProvider.Name)
 	if m.selectedProvider.DefaultModel != "" {
 		modelHint = m.selectedProvider.DefaultModel
 	}
-	b.WriteString(m.renderFormField("Model", m.modelInput, modelHint, 1, modelRequired, false, inputWidth))
+	b.WriteString(m.renderInputField("Model", m.modelInput, modelHint, 1, modelRequired, inputWidth))
 
 	// Model picker
 	pickerView := m.renderModelPicker()
@@ -345,10 +465,11 @@ func (m *Model) viewAPIKeyInput() string {
 		b.WriteString(pickerView)
 	}
 	b.WriteString("\n")
+	b.WriteString(m.renderKeyStoring())
 
 	// Error message
-	if m.inputError != "" {
-		b.WriteString(m.styles.Error.Render("✗ " + m.inputError))
+	if msg := m.formError(); msg != "" {
+		b.WriteString(m.styles.Error.Render("✗ " + msg))
 		b.WriteString("\n")
 	}
 
@@ -384,8 +505,8 @@ func (m *Model) viewSuccess() string {
 	providerName := ""
 	if m.selectedProvider != nil {
 		providerName = m.selectedProvider.Name
-	} else if m.customProviderName != "" {
-		providerName = m.customProviderName
+	} else if m.customProviderName.Value() != "" {
+		providerName = m.customProviderName.Value()
 	}
 	if providerName != "" {
 		next := m.styles.Box.Width(m.width - 8).Render(
@@ -397,16 +518,41 @@ func (m *Model) viewSuccess() string {
 		b.WriteString("\n\n")
 
 		// Styled action buttons
-		var continueBtn, launchBtn string
-		if m.successOption == 0 {
-			continueBtn = m.styles.ButtonActive.Render("Continue")
-			launchBtn = m.styles.ButtonInactive.Render(fmt.Sprintf("Launch Claude with %s", providerName))
-		} else {
-			continueBtn = m.styles.ButtonInactive.Render("Continue")
-			launchBtn = m.styles.ButtonActive.Render(fmt.Sprintf("Launch Claude with %s", providerName))
+		buttons := []struct {
+			zone  int
+			label string
+		}{
+			{zoneContinue, "Continue"},
+			{zoneLaunch, fmt.Sprintf("Launch Claude with %s", providerName)},
+			{zoneTest, "Test now"},
+			{zoneScript, "Generate wrapper script"},
 		}
-		b.WriteString(continueBtn + "  " + launchBtn)
+		// Wrapped onto more rows when they don't fit
+		row, rowWidth := "", 0
+		for i, btn := range buttons {
+			style := m.styles.ButtonInactive
+			if i == m.successOption {
+				style = m.styles.ButtonActive
+			}
+			rendered := style.Render(btn.label)
+			if rowWidth > 0 && rowWidth+2+lipgloss.Width(rendered) > m.width-8 {
+				b.WriteString(row + "\n")
+				row, rowWidth = "", 0
+			}
+			if rowWidth > 0 {
+				row += "  "
+				rowWidth += 2
+			}
+			row += zoneMark(btn.zone, rendered)
+			rowWidth += lipgloss.Width(rendered)
+		}
+		b.WriteString(row)
 		b.WriteString("\n\n")
+
+		if result := m.successResult(providerName); result != "" {
+			b.WriteString(result)
+			b.WriteString("\n\n")
+		}
 	}
 
 	// Help
@@ -446,7 +592,7 @@ func (m *Model) viewCustomProvider() string {
 	var b strings.Builder
 
 	// Check if editing or adding
-	existingProvider := m.cfg.GetProvider(m.customProviderName)
+	existingProvider := m.cfg.GetProvider(m.customProviderName.Value())
 	isEditing := existingProvider != nil
 
 	// Compact header with breadcrumb
@@ -484,30 +630,22 @@ func (m *Model) viewCustomProvider() string {
 		apiKeyHint = "(saved - type to change)"
 	}
 
-	// Mask API key value for display
-	maskedAPIKey := m.apiKeyInput
-	if maskedAPIKey != "" {
-		maskedAPIKey = strings.Repeat("•", len(maskedAPIKey))
-	}
-
 	fields := []struct {
-		label    string
-		value    string
-		focus    int
-		hint     string
-		isMasked bool
-		req      bool
+		label string
+		input textinput.Model
+		focus int
+		hint  string
+		req   bool
 	}{
-		{"Name", m.customProviderName, 0, "lowercase-id", false, true},
-		{"Display Name", m.customProviderDisplay, 1, "optional", false, false},
-		{"Base URL", m.customProviderURL, 2, "https://api.example.com", false, true},
-		{"API Key", maskedAPIKey, 3, apiKeyHint, true, false},
-		{"Model", m.customProviderModel, 4, "e.g., gpt-4o, claude-3-sonnet", false, true},
-		{"API Type", m.customProviderAPIType, 5, "↑/↓ to change", false, true},
+		{"Name", m.customProviderName, 0, "lowercase-id", true},
+		{"Display Name", m.customProviderDisplay, 1, "optional", false},
+		{"Base URL", m.customProviderURL, 2, "https://api.example.com", true},
+		{"API Key", m.apiKeyInput, 3, apiKeyHint, false},
+		{"Model", m.customProviderModel, 4, "e.g., gpt-4o, claude-3-sonnet", true},
 	}
 
 	for _, f := range fields {
-		b.WriteString(m.renderFormField(f.label, f.value, f.hint, f.focus, f.req, f.isMasked, inputWidth))
+		b.WriteString(m.renderInputField(f.label, f.input, f.hint, f.focus, f.req, inputWidth))
 
 		// Render model picker after the model field
 		if f.focus == 4 {
@@ -517,6 +655,7 @@ func (m *Model) viewCustomProvider() string {
 			}
 		}
 	}
+	b.WriteString(m.renderFormField("API Type", m.customProviderAPIType, "↑/↓ to change", 5, true, inputWidth))
 
 	// API Type explanation
 	apiTypeBox := m.styles.Box.Width(m.width - 8).Render(
@@ -525,11 +664,15 @@ func (m *Model) viewCustomProvider() string {
 			m.styles.Success.Render("• ") + m.styles.Info.Render(config.APITypeOpenAI) + m.styles.Dimmed.Render(" (/v1/chat/completions)"),
 	)
 	b.WriteString(apiTypeBox)
+	if m.keyStoring {
+		b.WriteString("\n")
+		b.WriteString(strings.TrimSuffix(m.renderKeyStoring(), "\n"))
+	}
 
 	// Error message
-	if m.inputError != "" {
+	if msg := m.formError(); msg != "" {
 		b.WriteString("\n")
-		b.WriteString(m.styles.Error.Render("✗ " + m.inputError))
+		b.WriteString(m.styles.Error.Render("✗ " + msg))
 	}
 
 	b.WriteString("\n")
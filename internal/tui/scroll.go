@@ -0,0 +1,116 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// focusMark is written before the focused form field so the view can keep it
+// in sight when a screen is taller than the terminal. It is removed before
+// the screen is drawn.
+const focusMark = "\x1b[8m\x1b[28m"
+
+// focusFieldHeight is the height of a form field: its label and a bordered
+// input, without a validation error.
+const focusFieldHeight = 4
+
+// scrollable reports whether the current screen scrolls when it is taller
+// than the terminal. The provider list sizes itself instead.
+func (m *Model) scrollable() bool {
+	return m.screen != ScreenMain && m.height > 0
+}
+
+// updateScroll handles page keys and the mouse wheel on a screen taller than
+// the terminal. It returns false for messages it doesn't handle.
+func (m *Model) updateScroll(msg tea.Msg) bool {
+	if !m.scrollable() || m.viewport.TotalLineCount() <= m.viewport.Height {
+		return false
+	}
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyPgUp:
+			m.viewport.PageUp()
+		case tea.KeyPgDown:
+			m.viewport.PageDown()
+		default:
+			return false
+		}
+	case tea.MouseMsg:
+		m.viewport, _ = m.viewport.Update(msg)
+	default:
+		return false
+	}
+	return true
+}
+
+// scrollView fits content to the terminal height. Content that is too tall
+// is shown in a viewport, scrolled to keep the focused field (and the model
+// picker below it) in sight, with a line saying how much is out of view.
+func (m *Model) scrollView(content string) string {
+	lines := strings.Split(content, "\n")
+	focusLine := -1
+	for i, line := range lines {
+		if strings.Contains(line, focusMark) {
+			focusLine = i
+			lines[i] = strings.ReplaceAll(line, focusMark, "")
+		}
+	}
+	content = strings.Join(lines, "\n")
+
+	// Each screen starts at the top
+	if m.screen != m.scrollScreen {
+		m.scrollScreen = m.screen
+		m.scrollFocus = ""
+		m.viewport.SetYOffset(0)
+	}
+
+	height := m.height - m.styles.App.GetVerticalFrameSize()
+	if !m.scrollable() || len(lines) <= height {
+		m.viewport.SetContent("")
+		return content
+	}
+
+	// One line is kept for the scroll indicator
+	m.viewport.Width = m.width - m.styles.App.GetHorizontalFrameSize()
+	m.viewport.Height = max(height-1, 1)
+	m.viewport.SetContent(content)
+
+	// Follow the focus when it moves, leaving manual scrolling alone
+	// otherwise
+	if focusLine >= 0 {
+		block := focusFieldHeight
+		if m.fieldMessage(m.inputFocus) != "" {
+			block++
+		}
+		if m.modelPickerOpen && m.isOnModelField() {
+			block += lipgloss.Height(m.renderModelPicker())
+		}
+		block = min(block, m.viewport.Height)
+		key := fmt.Sprint(focusLine, block)
+		if key != m.scrollFocus {
+			m.scrollFocus = key
+			switch {
+			case focusLine < m.viewport.YOffset:
+				m.viewport.SetYOffset(focusLine)
+			case focusLine+block > m.viewport.YOffset+m.viewport.Height:
+				m.viewport.SetYOffset(focusLine + block - m.viewport.Height)
+			}
+		}
+	}
+
+	above := m.viewport.YOffset
+	below := max(len(lines)-above-m.viewport.Height, 0)
+	var indicator []string
+	if above > 0 {
+		indicator = append(indicator, fmt.Sprintf("↑ %d more", above))
+	}
+	if below > 0 {
+		indicator = append(indicator, fmt.Sprintf("↓ %d more", below))
+	}
+	indicator = append(indicator, "pgup/pgdn scroll")
+	return m.viewport.View() + "\n" + m.styles.Dimmed.Render(strings.Join(indicator, "  "))
+}
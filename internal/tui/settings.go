@@ -0,0 +1,194 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sammcj/skint/internal/config"
+)
+
+// setting is a config option shown on the settings screen. Exactly one of
+// field (an on/off toggle), choice (cycles through options) and args (opens
+// the argument editor) is set.
+type setting struct {
+	label string
+	hint  string
+	field func(cfg *config.Config) *bool
+
+	choice  func(cfg *config.Config) *string
+	options []string
+
+	args func(cfg *config.Config) *[]string
+}
+
+// settings lists the options on the settings screen, in display order.
+var settings = []setting{
+	{
+		label: "Launch Claude on 'skint use <provider>'",
+		hint:  "off: 'skint use <provider>' only sets the default",
+		field: func(cfg *config.Config) *bool { return &cfg.AutoLaunchAfterUse },
+	},
+	{
+		label: "Confirm before launching Claude",
+		hint:  "ask before each launch from 'skint use' or the TUI",
+		field: func(cfg *config.Config) *bool { return &cfg.ConfirmBeforeLaunch },
+	},
+	{
+		label: "Show a summary when a session ends",
+		hint:  "duration, provider, model and a resume command",
+		field: func(cfg *config.Config) *bool { return &cfg.ExitSummary },
+	},
+	{
+		label: "Hide the startup banner",
+		hint:  "shown when launching Claude and at the top of the TUI",
+		field: func(cfg *config.Config) *bool { return &cfg.NoBanner },
+	},
+	{
+		label: "Colour output",
+		hint:  "NO_COLOR still turns colour off",
+		field: func(cfg *config.Config) *bool { return &cfg.ColorEnabled },
+	},
+	{
+		label:   "Output format",
+		hint:    "for commands like 'skint list' and 'skint info'",
+		choice:  func(cfg *config.Config) *string { return &cfg.OutputFormat },
+		options: []string{config.FormatHuman, config.FormatJSON, config.FormatPlain, config.FormatTable},
+	},
+	{
+		label:   "Relaunch when a provider fails",
+		hint:    "on an auth or connection error, ask or switch to the next fallback provider",
+		choice:  func(cfg *config.Config) *string { return &cfg.Watchdog },
+		options: []string{config.WatchdogOff, config.WatchdogAsk, config.WatchdogAuto},
+	},
+	{
+		label: "Claude arguments",
+		hint:  "passed to claude on every launch; enter to edit",
+		args:  func(cfg *config.Config) *[]string { return &cfg.ClaudeArgs },
+	},
+}
+
+func (m *Model) updateSettings(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		m.done = true
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.screen = ScreenMain
+		return m, nil
+	case tea.KeyUp:
+		m.settingsIdx = (m.settingsIdx - 1 + len(settings)) % len(settings)
+	case tea.KeyDown, tea.KeyTab:
+		m.settingsIdx = (m.settingsIdx + 1) % len(settings)
+	case tea.KeyEnter, tea.KeySpace:
+		return m.changeSetting(1)
+	case tea.KeyRight:
+		m.cycleSetting(1)
+	case tea.KeyLeft:
+		m.cycleSetting(-1)
+	case tea.KeyRunes:
+		switch msg.String() {
+		case "k":
+			m.settingsIdx = (m.settingsIdx - 1 + len(settings)) % len(settings)
+		case "j":
+			m.settingsIdx = (m.settingsIdx + 1) % len(settings)
+		case " ", "x":
+			return m.changeSetting(1)
+		case "l":
+			m.cycleSetting(1)
+		case "h":
+			m.cycleSetting(-1)
+		case "q":
+			m.screen = ScreenMain
+		case "?":
+			return m.openHelp()
+		}
+	}
+	return m, nil
+}
+
+// changeSetting flips a toggle, cycles a choice by step, or opens the
+// argument editor. The config is saved when the TUI exits, like provider
+// changes.
+func (m *Model) changeSetting(step int) (tea.Model, tea.Cmd) {
+	s := settings[m.settingsIdx]
+	switch {
+	case s.field != nil:
+		m.pushUndo(fmt.Sprintf("change %q", s.label))
+		v := s.field(m.cfg)
+		*v = !*v
+		// Hiding or showing the banner changes the room for the list
+		m.sizeList()
+	case s.choice != nil:
+		m.cycleSetting(step)
+	case s.args != nil:
+		return m.openClaudeArgs("")
+	}
+	return m, nil
+}
+
+// cycleSetting moves a choice setting to the next (step 1) or previous
+// (step -1) option. Other settings are left alone.
+func (m *Model) cycleSetting(step int) {
+	s := settings[m.settingsIdx]
+	if s.choice == nil {
+		return
+	}
+	m.pushUndo(fmt.Sprintf("change %q", s.label))
+	v := s.choice(m.cfg)
+	// An unset value shows as the first option
+	i := max(slices.Index(s.options, *v), 0)
+	*v = s.options[(i+step+len(s.options))%len(s.options)]
+}
+
+func (m *Model) viewSettings() string {
+	var b strings.Builder
+
+	// Compact header
+	header := m.styles.HeaderLine.Render("Skint") +
+		m.styles.HeaderSep.Render(" › ") +
+		m.styles.Title.Render("Settings")
+	b.WriteString(header)
+	b.WriteString("\n\n")
+
+	for i, s := range settings {
+		label := s.label
+		if i == m.settingsIdx {
+			label = focusMark + m.styles.ListSelected.Render("> "+label)
+		} else {
+			label = m.styles.Normal.Render("  " + label)
+		}
+
+		switch {
+		case s.field != nil:
+			check := "[ ]"
+			if *s.field(m.cfg) {
+				check = "[" + m.styles.Success.Render("✓") + "]"
+			}
+			b.WriteString(check + " " + label + "\n")
+		case s.choice != nil:
+			v := *s.choice(m.cfg)
+			if v == "" {
+				v = s.options[0]
+			}
+			b.WriteString("    " + label + "  " + m.styles.Value.Render("‹ "+v+" ›") + "\n")
+		case s.args != nil:
+			b.WriteString("    " + label + "\n")
+			if args := *s.args(m.cfg); len(args) > 0 {
+				b.WriteString("      " + m.styles.Value.Render(config.JoinArgs(args)) + "\n")
+			} else {
+				b.WriteString("      " + m.styles.Dimmed.Render("(none)") + "\n")
+			}
+		}
+		if s.hint != "" && !m.compact {
+			b.WriteString(m.styles.Dimmed.Render("      "+s.hint) + "\n")
+		}
+	}
+	b.WriteString("\n")
+
+	help := m.styles.Help.Render("↑/k ↓/j navigate  space/enter change  ←/→ cycle  ? help  esc back")
+	b.WriteString(m.styles.Footer.Render(help))
+
+	return b.String()
+}
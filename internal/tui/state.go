@@ -0,0 +1,110 @@
+package tui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/models"
+)
+
+// stateFile holds the TUI state kept between runs, in the cache directory.
+const stateFile = "tui-state.json"
+
+// State is what the TUI restores when it is opened again: the provider that
+// was selected in the list, and the models recently used with each provider,
+// newest first.
+type State struct {
+	Provider     string              `json:"provider,omitempty"`
+	RecentModels map[string][]string `json:"recent_models,omitempty"`
+}
+
+// LoadState reads the state saved by the last run. A missing or unreadable
+// file is an empty State.
+func LoadState() State {
+	var s State
+	path, err := statePath()
+	if err != nil {
+		return s
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return s
+	}
+	_ = json.Unmarshal(data, &s)
+	return s
+}
+
+// SaveState writes s for the next run.
+func SaveState(s State) error {
+	path, err := statePath()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0600)
+}
+
+// loadRecommendations returns the recommended models, as last refreshed by
+// skint models --recommended --refresh.
+func loadRecommendations() models.Recommendations {
+	dir, _ := config.GetCacheDir()
+	return models.LoadRecommendations(dir)
+}
+
+func statePath() (string, error) {
+	dir, err := config.GetCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, stateFile), nil
+}
+
+// State returns the model's state to restore next time.
+func (m *Model) State() State {
+	s := State{RecentModels: m.recentModels}
+	if item, ok := m.list.SelectedItem().(ProviderItem); ok && !item.isAddNew {
+		s.Provider = item.definition.Name
+	}
+	return s
+}
+
+// Restore applies state saved from an earlier run. A provider that no
+// longer exists is ignored.
+func (m *Model) Restore(s State) {
+	if s.Provider != "" {
+		m.selectProvider(s.Provider)
+	}
+	m.recentModels = s.RecentModels
+}
+
+// selectProvider moves the list cursor, and page, to the named provider,
+// clearing the list's filter if it hides it. It returns false if the
+// provider isn't in the list.
+func (m *Model) selectProvider(name string) bool {
+	find := func() int {
+		return slices.IndexFunc(m.list.VisibleItems(), func(li list.Item) bool {
+			item, ok := li.(ProviderItem)
+			return ok && !item.isAddNew && item.definition.Name == name
+		})
+	}
+	i := find()
+	if i < 0 && m.list.IsFiltered() {
+		m.list.ResetFilter()
+		i = find()
+	}
+	if i < 0 {
+		return false
+	}
+	m.list.Select(i)
+	return true
+}
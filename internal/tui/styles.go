@@ -2,6 +2,7 @@ package tui
 
 import (
 	"github.com/charmbracelet/lipgloss"
+	"github.com/sammcj/skint/internal/config"
 )
 
 // Styles holds all the Lipgloss styles for the TUI
@@ -55,33 +56,166 @@ type Styles struct {
 	// Picker box (model picker overlay)
 	PickerBox      lipgloss.Style
 	PickerBoxTitle lipgloss.Style
+	PickerMatch    lipgloss.Style // characters matching the filter
 
 	// Header line (compact single-line header)
 	HeaderLine lipgloss.Style
 	HeaderSep  lipgloss.Style
 
 	// Colors
-	PrimaryColor   lipgloss.Color
-	SecondaryColor lipgloss.Color
-	SuccessColor   lipgloss.Color
-	ErrorColor     lipgloss.Color
-	WarningColor   lipgloss.Color
-	InfoColor      lipgloss.Color
-	DimColor       lipgloss.Color
-	BgColor        lipgloss.Color
+	PrimaryColor   lipgloss.TerminalColor
+	SecondaryColor lipgloss.TerminalColor
+	SuccessColor   lipgloss.TerminalColor
+	ErrorColor     lipgloss.TerminalColor
+	WarningColor   lipgloss.TerminalColor
+	InfoColor      lipgloss.TerminalColor
+	DimColor       lipgloss.TerminalColor
+	BgColor        lipgloss.TerminalColor
+	BrightColor    lipgloss.TerminalColor
 }
 
-// DefaultStyles returns the default styles for the TUI
-func DefaultStyles() Styles {
-	// Color palette
-	primary := lipgloss.Color("#7C3AED")   // Violet
-	secondary := lipgloss.Color("#EC4899") // Pink
-	success := lipgloss.Color("#10B981")   // Emerald
-	error := lipgloss.Color("#EF4444")     // Red
-	warning := lipgloss.Color("#F59E0B")   // Amber
-	info := lipgloss.Color("#3B82F6")      // Blue
-	dim := lipgloss.Color("#6B7280")       // Gray
-	bg := lipgloss.Color("#1F2937")        // Dark gray
+// palette is the set of colours the styles are built from.
+type palette struct {
+	primary, secondary, success, error, warning, info, dim, bg lipgloss.TerminalColor
+
+	text      lipgloss.TerminalColor // body text
+	bright    lipgloss.TerminalColor // emphasised text
+	muted     lipgloss.TerminalColor // inactive button text
+	onPrimary lipgloss.TerminalColor // text on a primary background
+
+	// mono has no colours, so the active button is shown reversed
+	mono bool
+}
+
+// palettes are the theme presets.
+var palettes = map[string]palette{
+	config.ThemeDark: {
+		primary:   lipgloss.Color("#7C3AED"), // Violet
+		secondary: lipgloss.Color("#EC4899"), // Pink
+		success:   lipgloss.Color("#10B981"), // Emerald
+		error:     lipgloss.Color("#EF4444"), // Red
+		warning:   lipgloss.Color("#F59E0B"), // Amber
+		info:      lipgloss.Color("#3B82F6"), // Blue
+		dim:       lipgloss.Color("#6B7280"), // Gray
+		bg:        lipgloss.Color("#1F2937"), // Dark gray
+		text:      lipgloss.Color("#E5E7EB"),
+		bright:    lipgloss.Color("#FFFFFF"),
+		muted:     lipgloss.Color("#9CA3AF"),
+		onPrimary: lipgloss.Color("#FFFFFF"),
+	},
+	// Darker shades of the dark palette, for light backgrounds
+	config.ThemeLight: {
+		primary:   lipgloss.Color("#6D28D9"),
+		secondary: lipgloss.Color("#BE185D"),
+		success:   lipgloss.Color("#047857"),
+		error:     lipgloss.Color("#B91C1C"),
+		warning:   lipgloss.Color("#B45309"),
+		info:      lipgloss.Color("#1D4ED8"),
+		dim:       lipgloss.Color("#4B5563"),
+		bg:        lipgloss.Color("#E5E7EB"),
+		text:      lipgloss.Color("#1F2937"),
+		bright:    lipgloss.Color("#000000"),
+		muted:     lipgloss.Color("#4B5563"),
+		onPrimary: lipgloss.Color("#FFFFFF"),
+	},
+	// The terminal's own bright ANSI colours
+	config.ThemeHighContrast: {
+		primary:   lipgloss.Color("13"),
+		secondary: lipgloss.Color("14"),
+		success:   lipgloss.Color("10"),
+		error:     lipgloss.Color("9"),
+		warning:   lipgloss.Color("11"),
+		info:      lipgloss.Color("12"),
+		dim:       lipgloss.Color("7"),
+		bg:        lipgloss.Color("0"),
+		text:      lipgloss.Color("15"),
+		bright:    lipgloss.Color("15"),
+		muted:     lipgloss.Color("7"),
+		onPrimary: lipgloss.Color("0"),
+	},
+	config.ThemeMono: {
+		primary:   lipgloss.NoColor{},
+		secondary: lipgloss.NoColor{},
+		success:   lipgloss.NoColor{},
+		error:     lipgloss.NoColor{},
+		warning:   lipgloss.NoColor{},
+		info:      lipgloss.NoColor{},
+		dim:       lipgloss.NoColor{},
+		bg:        lipgloss.NoColor{},
+		text:      lipgloss.NoColor{},
+		bright:    lipgloss.NoColor{},
+		muted:     lipgloss.NoColor{},
+		onPrimary: lipgloss.NoColor{},
+		mono:      true,
+	},
+}
+
+// adaptivePalette is the default palette: the light preset's colours on a
+// light terminal background and the dark preset's otherwise.
+func adaptivePalette() palette {
+	light, dark := palettes[config.ThemeLight], palettes[config.ThemeDark]
+	return palette{
+		primary:   adaptiveColor(light.primary, dark.primary),
+		secondary: adaptiveColor(light.secondary, dark.secondary),
+		success:   adaptiveColor(light.success, dark.success),
+		error:     adaptiveColor(light.error, dark.error),
+		warning:   adaptiveColor(light.warning, dark.warning),
+		info:      adaptiveColor(light.info, dark.info),
+		dim:       adaptiveColor(light.dim, dark.dim),
+		bg:        adaptiveColor(light.bg, dark.bg),
+		text:      adaptiveColor(light.text, dark.text),
+		bright:    adaptiveColor(light.bright, dark.bright),
+		muted:     adaptiveColor(light.muted, dark.muted),
+		onPrimary: adaptiveColor(light.onPrimary, dark.onPrimary),
+	}
+}
+
+// adaptiveColor picks light or dark by the terminal background. Anything but
+// two plain colours falls back to dark.
+func adaptiveColor(light, dark lipgloss.TerminalColor) lipgloss.TerminalColor {
+	l, lok := light.(lipgloss.Color)
+	d, dok := dark.(lipgloss.Color)
+	if !lok || !dok {
+		return dark
+	}
+	return lipgloss.AdaptiveColor{Light: string(l), Dark: string(d)}
+}
+
+// themePalette returns the palette for theme: its preset, with any colours
+// the theme sets replacing the preset's. A nil theme, or one without a
+// preset, adapts to the terminal background.
+func themePalette(theme *config.Theme) palette {
+	if theme == nil {
+		return adaptivePalette()
+	}
+	p, ok := palettes[theme.Preset]
+	if !ok {
+		p = adaptivePalette()
+	}
+	if theme.Primary != "" {
+		p.primary = lipgloss.Color(theme.Primary)
+	}
+	if theme.Success != "" {
+		p.success = lipgloss.Color(theme.Success)
+	}
+	if theme.Error != "" {
+		p.error = lipgloss.Color(theme.Error)
+	}
+	return p
+}
+
+// DefaultStyles returns the styles for the TUI in the given theme, which may
+// be nil for the default palette
+func DefaultStyles(theme *config.Theme) Styles {
+	p := themePalette(theme)
+	primary := p.primary
+	secondary := p.secondary
+	success := p.success
+	error := p.error
+	warning := p.warning
+	info := p.info
+	dim := p.dim
+	bg := p.bg
 
 	s := Styles{
 		PrimaryColor:   primary,
@@ -92,6 +226,7 @@ func DefaultStyles() Styles {
 		InfoColor:      info,
 		DimColor:       dim,
 		BgColor:        bg,
+		BrightColor:    p.bright,
 	}
 
 	// Container styles
@@ -143,7 +278,7 @@ func DefaultStyles() Styles {
 		PaddingRight(1)
 
 	s.Normal = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#E5E7EB"))
+		Foreground(p.text)
 
 	s.Dimmed = lipgloss.NewStyle().
 		Foreground(dim)
@@ -175,7 +310,7 @@ func DefaultStyles() Styles {
 		Bold(true)
 
 	s.Label = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#E5E7EB")).
+		Foreground(p.text).
 		Bold(true)
 
 	s.Value = lipgloss.NewStyle().
@@ -201,7 +336,7 @@ func DefaultStyles() Styles {
 		BorderForeground(primary)
 
 	s.ListActive = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFFFF")).
+		Foreground(p.bright).
 		Bold(true).
 		PaddingLeft(1).
 		PaddingRight(2).
@@ -228,17 +363,20 @@ func DefaultStyles() Styles {
 		MarginBottom(1)
 
 	s.BoxContent = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#E5E7EB"))
+		Foreground(p.text)
 
 	// Button styles
 	s.ButtonActive = lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#FFFFFF")).
+		Foreground(p.onPrimary).
 		Background(primary).
 		Padding(0, 2)
+	if p.mono {
+		s.ButtonActive = s.ButtonActive.Reverse(true)
+	}
 
 	s.ButtonInactive = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#9CA3AF")).
+		Foreground(p.muted).
 		Background(bg).
 		Padding(0, 2)
 
@@ -259,6 +397,11 @@ func DefaultStyles() Styles {
 		Foreground(info).
 		Bold(true)
 
+	s.PickerMatch = lipgloss.NewStyle().
+		Foreground(warning).
+		Bold(true).
+		Underline(true)
+
 	// Header line
 	s.HeaderLine = lipgloss.NewStyle().
 		Bold(true).
@@ -271,8 +414,8 @@ func DefaultStyles() Styles {
 }
 
 // CompactStyles returns compact styles for smaller terminals
-func CompactStyles() Styles {
-	s := DefaultStyles()
+func CompactStyles(theme *config.Theme) Styles {
+	s := DefaultStyles(theme)
 
 	// Reduce margins and padding
 	s.App = lipgloss.NewStyle().Padding(0, 1)
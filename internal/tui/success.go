@@ -0,0 +1,126 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/launcher"
+	"github.com/sammcj/skint/internal/providers"
+)
+
+// scriptGeneratedMsg is sent when a wrapper script has been written, or
+// couldn't be.
+type scriptGeneratedMsg struct {
+	path      string
+	embedsKey bool
+	err       error
+}
+
+// testSuccessProvider tests the provider just configured, showing the
+// result on the success screen.
+func (m *Model) testSuccessProvider(name string) (tea.Model, tea.Cmd) {
+	m.successStatus, m.successErr = "", ""
+	if t, ok := m.itemTests[name]; ok && !t.done {
+		return m, nil // already testing
+	}
+	p := m.cfg.GetProvider(name)
+	if p == nil {
+		p = &config.Provider{Name: name}
+	}
+	if testURL(p) == "" {
+		m.successErr = fmt.Sprintf("%s has no endpoint to test", name)
+		return m, nil
+	}
+	m.successTesting = true
+	return m, tea.Batch(m.setItemTest(newProviderTest(p)), testItemCmd(p, m.cfg.NetworkTimeout()))
+}
+
+// generateScript writes the skint-<name> wrapper script for the provider
+// just configured, as skint generate-scripts does, in the background.
+func (m *Model) generateScript(name string) (tea.Model, tea.Cmd) {
+	m.successStatus, m.successErr = "", ""
+	p := m.cfg.GetProvider(name)
+	if p == nil {
+		m.successErr = fmt.Sprintf("%s is not configured", name)
+		return m, nil
+	}
+	presetEnv, err := m.cfg.PresetEnv(p.EnvPresets...)
+	if err != nil {
+		m.successErr = err.Error()
+		return m, nil
+	}
+	args := m.cfg.LaunchArgs(p)
+	// A copy, so the key can be resolved off the UI goroutine
+	provider := *p
+	secretsMgr := m.secretsMgr
+
+	return m, func() tea.Msg {
+		if provider.NeedsAPIKey() && provider.GetAPIKey() == "" && provider.APIKeyRef != "" && secretsMgr != nil {
+			key, err := secretsMgr.RetrieveByReference(provider.APIKeyRef)
+			if err != nil {
+				return scriptGeneratedMsg{err: fmt.Errorf("API key not available: %w", err)}
+			}
+			provider.SetResolvedAPIKey(key)
+		}
+		prov, err := providers.FromConfig(&provider)
+		if err != nil {
+			return scriptGeneratedMsg{err: err}
+		}
+		binDir, err := config.GetBinDir()
+		if err != nil {
+			return scriptGeneratedMsg{err: fmt.Errorf("failed to get bin directory: %w", err)}
+		}
+		mcp, err := config.WriteMCPConfig(&provider)
+		if err != nil {
+			return scriptGeneratedMsg{err: err}
+		}
+		if err := launcher.GenerateScript(prov, binDir, presetEnv, append(args, mcp...), false); err != nil {
+			return scriptGeneratedMsg{err: err}
+		}
+		return scriptGeneratedMsg{path: launcher.ScriptPath(binDir, provider.Name), embedsKey: provider.GetAPIKey() != ""}
+	}
+}
+
+// scriptGenerated reports the result of generateScript.
+func (m *Model) scriptGenerated(msg scriptGeneratedMsg) (tea.Model, tea.Cmd) {
+	var edited *launcher.ModifiedError
+	if errors.As(msg.err, &edited) {
+		m.successErr = "Left the script alone: " + msg.err.Error() + " (skint generate-scripts --force replaces it)"
+		return m, nil
+	}
+	if msg.err != nil {
+		m.successErr = "Failed to generate the script: " + msg.err.Error()
+		return m, nil
+	}
+	m.successStatus = "Wrote " + msg.path
+	if msg.embedsKey {
+		m.successStatus += " (it contains the API key; only you can read it)"
+	}
+	return m, nil
+}
+
+// successResult renders the outcome of the success screen's test and script
+// buttons, or "" if neither has been used.
+func (m *Model) successResult(name string) string {
+	var lines []string
+	if t, ok := m.itemTests[name]; ok && m.successTesting {
+		switch {
+		case !t.done:
+			lines = append(lines, m.styles.Dimmed.Render("Testing "+t.displayName+"..."))
+		case t.reachable:
+			lines = append(lines, m.styles.Success.Render("✓ Reachable: "+t.summary()))
+		default:
+			lines = append(lines, m.styles.Error.Render("✗ Unreachable: "+t.summary()))
+		}
+	}
+	if m.successStatus != "" {
+		lines = append(lines, m.styles.Success.Render("✓ "+m.successStatus))
+	}
+	if m.successErr != "" {
+		lines = append(lines, m.styles.Error.Render("✗ "+m.successErr))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,213 @@
+package tui
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sammcj/skint/internal/config"
+)
+
+// switcherRows is the most providers the switcher shows at once.
+const switcherRows = 10
+
+// switcherItem is a provider the switcher offers. The native item has the
+// name "native".
+type switcherItem struct {
+	name, displayName, model, lastUsed string
+}
+
+// switcherMatch is an item matching the switcher's filter, with the rune
+// indices of its name that matched.
+type switcherMatch struct {
+	switcherItem
+	score   int
+	matched []int
+}
+
+// Switcher is a one-shot fuzzy provider picker: type to filter, enter to
+// pick. It draws inline and leaves nothing behind.
+type Switcher struct {
+	items   []switcherItem
+	filter  textinput.Model
+	matches []switcherMatch
+	cursor  int
+	styles  Styles
+	width   int
+
+	picked string
+	done   bool
+}
+
+// NewSwitcher returns a switcher over cfg's providers, then native. Those in
+// provider_order come first, then the most recently used, per usage.
+func NewSwitcher(cfg *config.Config, usage config.Usage, query string) *Switcher {
+	now := time.Now()
+	ordered := cfg.OrderedProviders()
+	ranked := 0
+	for ranked < len(ordered) && cfg.ProviderRank(ordered[ranked].Name) >= 0 {
+		ranked++
+	}
+	slices.SortStableFunc(ordered[ranked:], func(a, b *config.Provider) int {
+		return usage[b.Name].Compare(usage[a.Name])
+	})
+
+	s := &Switcher{styles: DefaultStyles(cfg.Theme)}
+	for _, p := range ordered {
+		s.items = append(s.items, switcherItem{
+			name:        p.Name,
+			displayName: p.DisplayName,
+			model:       p.EffectiveModel(),
+			lastUsed:    usage.LastUsed(p.Name, now),
+		})
+	}
+	s.items = append(s.items, switcherItem{name: "native", displayName: "Native Anthropic", lastUsed: usage.LastUsed("native", now)})
+
+	s.filter = newInput()
+	s.filter.Prompt = "> "
+	s.filter.PromptStyle = s.styles.Info
+	s.filter.Placeholder = "type to filter providers"
+	s.filter.SetValue(query)
+	s.filter.CursorEnd()
+	s.refilter()
+	return s
+}
+
+// Picked returns the provider picked, or "" if the switcher was cancelled.
+func (s *Switcher) Picked() string {
+	return s.picked
+}
+
+// refilter matches the items against the filter, best first, keeping the
+// list order among equals, and moves the cursor to the top.
+func (s *Switcher) refilter() {
+	filter := strings.TrimSpace(s.filter.Value())
+	s.matches = s.matches[:0]
+	for _, item := range s.items {
+		score, matched, ok := fuzzyMatch(filter, item.name)
+		if dScore, _, dOK := fuzzyMatch(filter, item.displayName); dOK && (!ok || dScore > score) {
+			// Matched on the display name, which is shown dimmed
+			score, matched, ok = dScore, nil, true
+		}
+		if ok {
+			s.matches = append(s.matches, switcherMatch{switcherItem: item, score: score, matched: matched})
+		}
+	}
+	if filter != "" {
+		slices.SortStableFunc(s.matches, func(a, b switcherMatch) int { return cmp.Compare(b.score, a.score) })
+	}
+	s.cursor = 0
+}
+
+// Init implements tea.Model.
+func (s *Switcher) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model.
+func (s *Switcher) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		s.width = msg.Width
+		return s, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			s.done = true
+			return s, tea.Quit
+		case "enter":
+			if len(s.matches) == 0 {
+				return s, nil
+			}
+			s.picked, s.done = s.matches[s.cursor].name, true
+			return s, tea.Quit
+		case "up", "ctrl+p", "ctrl+k", "shift+tab":
+			if s.cursor > 0 {
+				s.cursor--
+			}
+			return s, nil
+		case "down", "ctrl+n", "ctrl+j", "tab":
+			if s.cursor < len(s.matches)-1 {
+				s.cursor++
+			}
+			return s, nil
+		}
+	}
+
+	before := s.filter.Value()
+	var cmd tea.Cmd
+	s.filter, cmd = s.filter.Update(msg)
+	if s.filter.Value() != before {
+		s.refilter()
+	}
+	return s, cmd
+}
+
+// View implements tea.Model.
+func (s *Switcher) View() string {
+	if s.done {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(s.filter.View() + "\n")
+	if len(s.matches) == 0 {
+		b.WriteString(s.styles.Dimmed.Render("  no matching providers") + "\n")
+	}
+
+	// Scroll to keep the cursor in view
+	start := max(0, s.cursor-switcherRows+1)
+	end := min(len(s.matches), start+switcherRows)
+	nameWidth := 0
+	for _, m := range s.matches[start:end] {
+		nameWidth = max(nameWidth, len(m.name))
+	}
+	selected := s.styles.Selected.Padding(0)
+	for i := start; i < end; i++ {
+		m := s.matches[i]
+		pointer, base := "  ", s.styles.Normal
+		if i == s.cursor {
+			pointer, base = selected.Render("▸ "), selected
+		}
+		name := highlightMatches(m.name, m.matched, base, s.styles.PickerMatch)
+		line := pointer + name + strings.Repeat(" ", nameWidth-len(m.name)+2) + s.styles.Dimmed.Render(m.details())
+		if s.width > 0 {
+			line = lipgloss.NewStyle().MaxWidth(s.width).Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+	b.WriteString(s.styles.Dimmed.Render(fmt.Sprintf("  %d/%d · enter launch · esc cancel", len(s.matches), len(s.items))))
+	return b.String()
+}
+
+// details describes the item after its name: display name, model and when
+// it was last used.
+func (item switcherItem) details() string {
+	parts := []string{item.displayName}
+	if item.model != "" {
+		parts = append(parts, item.model)
+	}
+	if item.lastUsed != "" {
+		parts = append(parts, item.lastUsed)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// RunSwitcher runs the switcher and returns the provider picked, or "" if it
+// was cancelled.
+func RunSwitcher(cfg *config.Config, usage config.Usage, query string) (string, error) {
+	p := tea.NewProgram(NewSwitcher(cfg, usage, query))
+	finalModel, err := p.Run()
+	if err != nil {
+		return "", fmt.Errorf("TUI error: %w", err)
+	}
+	s, ok := finalModel.(*Switcher)
+	if !ok {
+		return "", fmt.Errorf("TUI returned unexpected model type: %T", finalModel)
+	}
+	return s.Picked(), nil
+}
@@ -1,12 +1,26 @@
 package tui
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/detect"
 	"github.com/sammcj/skint/internal/models"
 	"github.com/sammcj/skint/internal/providers"
+	"github.com/sammcj/skint/internal/ui"
 )
 
 // newAPIKeyScreenModel returns a model parked on the API key screen with a
@@ -83,6 +97,254 @@ func TestModelsFetchedStaleGenerationIgnored(t *testing.T) {
 	}
 }
 
+// TestModelsLoadFromCache covers the picker opening on cached models straight
+// away, refreshing them in the background only once they're stale.
+func TestModelsLoadFromCache(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", dir)
+	m := newAPIKeyScreenModel()
+	if err := models.SaveCache(filepath.Join(dir, "skint"), "zai", m.selectedProvider.BaseURL, []models.ModelInfo{{ID: "glm-4.6"}, {ID: "glm-5"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if cmd := m.fetchOnModelFocus(); cmd != nil || m.modelFetching {
+		t.Error("fresh cached models should not be fetched again")
+	}
+	if !m.modelPickerOpen || len(m.fetchedModels) != 2 {
+		t.Fatalf("picker open = %v with %d models, want the 2 cached", m.modelPickerOpen, len(m.fetchedModels))
+	}
+
+	// Stale: shown while fetching, and the cursor survives the refresh
+	m.resetModelPicker()
+	m.cfg.ModelCacheTTL = new(config.Duration)
+	if cmd := m.fetchOnModelFocus(); cmd == nil || !m.modelFetching {
+		t.Fatal("stale cached models should be fetched again")
+	}
+	if !m.modelPickerOpen || len(m.fetchedModels) != 2 {
+		t.Fatalf("picker open = %v with %d models, want the 2 cached while fetching", m.modelPickerOpen, len(m.fetchedModels))
+	}
+	if view := m.renderModelPicker(); !strings.Contains(view, "glm-5") || !strings.Contains(view, "refreshing") {
+		t.Errorf("picker while refreshing:\n%s", view)
+	}
+	m.modelPickerIdx = 1
+	model, _ := m.Update(modelsFetchedMsg{
+		models:     []models.ModelInfo{{ID: "glm-4.6"}, {ID: "glm-5"}, {ID: "glm-5.1"}},
+		generation: m.fetchGeneration,
+	})
+	m = model.(*Model)
+	if len(m.fetchedModels) != 3 || m.modelPickerIdx != 1 {
+		t.Errorf("after refresh: %d models, cursor %d; want 3 and 1", len(m.fetchedModels), m.modelPickerIdx)
+	}
+
+	// A failed refresh keeps the cached models
+	m.resetModelPicker()
+	_ = m.fetchOnModelFocus()
+	model, _ = m.Update(modelsFetchedMsg{err: errors.New("connection refused"), generation: m.fetchGeneration})
+	m = model.(*Model)
+	if view := m.renderModelPicker(); !strings.Contains(view, "glm-5") {
+		t.Errorf("picker after a failed refresh:\n%s", view)
+	}
+}
+
+// TestModelPickerRecommended covers the recommended models the provider lists
+// getting a section after the recent ones.
+func TestModelPickerRecommended(t *testing.T) {
+	m := newAPIKeyScreenModel()
+	m.width = 100
+	m.recommendations = models.Recommendations{Providers: map[string][]string{"zai": {"glm-5", "glm-9", "glm-4.6"}}}
+	m.recentModels = map[string][]string{"zai": {"glm-4.6"}}
+	m.fetchedModels = []models.ModelInfo{{ID: "glm-4.5-air"}, {ID: "glm-4.6"}, {ID: "glm-5"}}
+	m.modelPickerOpen = true
+
+	var got []string
+	for _, mm := range m.filteredModels() {
+		got = append(got, mm.section+": "+mm.ID)
+	}
+	want := []string{"Recent: glm-4.6", sectionRecommended + ": glm-5", "All models: glm-4.5-air"}
+	if !slices.Equal(got, want) {
+		t.Errorf("picker = %q, want %q", got, want)
+	}
+	if view := m.renderModelPicker(); !strings.Contains(view, sectionRecommended) || strings.Contains(view, "glm-9") {
+		t.Errorf("picker:\n%s", view)
+	}
+}
+
+// TestModelHintRecommended covers the model field's placeholder naming the
+// provider's first recommended model rather than a fixed one.
+func TestModelHintRecommended(t *testing.T) {
+	m := newAPIKeyScreenModel()
+	m.recommendations = models.Recommendations{Providers: map[string][]string{"zai": {"glm-5", "glm-4.6"}}}
+	if got := m.modelHint("zai"); got != "e.g., glm-5" {
+		t.Errorf("modelHint(zai) = %q, want e.g., glm-5", got)
+	}
+	if got := m.modelHint("lmstudio"); strings.Contains(got, "e.g.") {
+		t.Errorf("modelHint(lmstudio) = %q, want no example without recommendations", got)
+	}
+}
+
+// TestModelPickerToolsOnly covers ctrl+t hiding models known not to support
+// tool calling, and keeping those whose capabilities are unknown.
+func TestModelPickerToolsOnly(t *testing.T) {
+	m := newAPIKeyScreenModel()
+	m.fetchedModels = []models.ModelInfo{
+		{ID: "with-tools", Capabilities: &models.Capabilities{Tools: true}},
+		{ID: "without-tools", Capabilities: &models.Capabilities{Vision: true}},
+		{ID: "unknown"},
+	}
+	m.modelPickerOpen = true
+	m.width = 100
+	m.recommendations = models.Recommendations{}
+	ids := func() []string {
+		var out []string
+		for _, mm := range m.filteredModels() {
+			out = append(out, mm.ID)
+		}
+		return out
+	}
+
+	if got := ids(); len(got) != 3 {
+		t.Fatalf("models = %v, want all 3", got)
+	}
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	m = model.(*Model)
+	if got := ids(); !slices.Equal(got, []string{"with-tools", "unknown"}) {
+		t.Errorf("tools only = %v, want with-tools and unknown", got)
+	}
+	if view := m.renderModelPicker(); !strings.Contains(view, "tools only") || strings.Contains(view, "without-tools") {
+		t.Errorf("picker with tools only:\n%s", view)
+	}
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	m = model.(*Model)
+	if got := ids(); len(got) != 3 {
+		t.Errorf("ctrl+t again = %v, want all 3", got)
+	}
+}
+
+// TestModelNotLoadedWarning covers LM Studio's load state: the picker shows
+// it, and the form warns when the chosen model isn't loaded.
+func TestModelNotLoadedWarning(t *testing.T) {
+	m := NewModel(config.NewDefaultConfig(), nil)
+	m.screen = ScreenProviderConfig
+	m.selectedProvider = &providers.Definition{Name: "lmstudio", DisplayName: "LM Studio", BaseURL: "http://localhost:1234/v1"}
+	m.width = 120
+	m.recommendations = models.Recommendations{}
+	m.fetchedModels = []models.ModelInfo{
+		{ID: "qwen3-coder-30b", Quantization: "Q4_K_M", State: models.StateLoaded},
+		{ID: "gemma-3-12b", Quantization: "Q8_0", State: models.StateNotLoaded},
+	}
+	m.inputFocus = 2
+	m.modelPickerOpen = true
+
+	if view := m.renderModelPicker(); !strings.Contains(view, "Q4_K_M") || !strings.Contains(view, "not loaded") {
+		t.Errorf("picker should show quantisation and load state:\n%s", view)
+	}
+	setInput(&m.localProviderModel, "qwen3-coder-30b")
+	if view := m.View(); strings.Contains(view, "isn't loaded") {
+		t.Errorf("loaded model should not warn:\n%s", view)
+	}
+	setInput(&m.localProviderModel, "gemma-3-12b")
+	if view := m.View(); !strings.Contains(view, "gemma-3-12b isn't loaded in LM Studio") {
+		t.Errorf("expected a not loaded warning:\n%s", view)
+	}
+}
+
+// TestOllamaPull covers pulling a missing model from the local provider
+// form: the form warns, ctrl+p streams the pull's progress, and the models
+// are fetched again once it's done.
+func TestOllamaPull(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	pulled := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/pull":
+			pulled = true
+			fmt.Fprintln(w, `{"status":"pulling 6a0746a1ec1a","total":100,"completed":40}`)
+			fmt.Fprintln(w, `{"status":"success"}`)
+		case "/api/tags":
+			if pulled {
+				fmt.Fprintln(w, `{"models":[{"name":"llama3.1:latest"},{"name":"qwen3-coder:latest"}]}`)
+				return
+			}
+			fmt.Fprintln(w, `{"models":[{"name":"llama3.1:latest"}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	m := NewModel(config.NewDefaultConfig(), nil)
+	m.screen = ScreenProviderConfig
+	m.selectedProvider = &providers.Definition{Name: "ollama", DisplayName: "Ollama", BaseURL: srv.URL}
+	m.width = 120
+	setInput(&m.localProviderURL, srv.URL)
+	setInput(&m.localProviderModel, "qwen3-coder")
+	m.fetchedModels = []models.ModelInfo{{ID: "llama3.1:latest"}}
+
+	if view := m.View(); !strings.Contains(view, "qwen3-coder hasn't been pulled into Ollama") {
+		t.Fatalf("expected a not pulled warning:\n%s", view)
+	}
+	model, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	m = model.(*Model)
+	var progress []string
+	for cmd != nil {
+		msg := cmd()
+		if msg == nil {
+			break
+		}
+		model, cmd = m.Update(msg)
+		m = model.(*Model)
+		if m.pull.progress != "" && !slices.Contains(progress, m.pull.progress) {
+			progress = append(progress, m.pull.progress)
+		}
+	}
+	if !slices.Contains(progress, "pulling 6a0746a1ec1a 40%") {
+		t.Errorf("progress = %q, want the layer at 40%%", progress)
+	}
+	if !models.OllamaHas(m.fetchedModels, "qwen3-coder") {
+		t.Errorf("models after the pull = %v, want qwen3-coder listed", m.fetchedModels)
+	}
+	if view := m.View(); !strings.Contains(view, "Pulled qwen3-coder") || strings.Contains(view, "hasn't been pulled") {
+		t.Errorf("view after the pull:\n%s", view)
+	}
+}
+
+// TestModelFetchCancelledOnLeavingField covers cancelling an in-flight fetch
+// when the focus leaves the model field, rather than waiting out the timeout.
+func TestModelFetchCancelledOnLeavingField(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+	m := newAPIKeyScreenModel()
+	m.selectedProvider.BaseURL = srv.URL
+	m.cfg.Timeout = config.Duration(time.Minute)
+
+	cmd := m.triggerModelFetch()
+	if cmd == nil {
+		t.Fatal("expected a fetch")
+	}
+	msgs := make(chan tea.Msg, 1)
+	go func() { msgs <- cmd() }()
+
+	m.inputFocus = 0
+	if m.fetchOnModelFocus() != nil || m.modelFetching {
+		t.Error("leaving the model field should stop the fetch")
+	}
+	select {
+	case msg := <-msgs:
+		fetched := msg.(modelsFetchedMsg)
+		if !errors.Is(fetched.err, context.Canceled) {
+			t.Errorf("err = %v, want context.Canceled", fetched.err)
+		}
+		if fetched.generation == m.fetchGeneration {
+			t.Error("the cancelled fetch's result should be discarded")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("fetch was not cancelled")
+	}
+}
+
 // TestCustomProviderFlowClearsStaleSelection covers the wrong-provider bug:
 // entering the custom provider flow after configuring another provider must
 // clear the stale selection so the success screen resolves the custom provider.
@@ -103,9 +365,9 @@ func TestCustomProviderFlowClearsStaleSelection(t *testing.T) {
 	}
 
 	// Fill and submit the custom provider (no API key -> no secrets manager needed).
-	m.customProviderName = "mycustom"
-	m.customProviderURL = "https://api.example.com"
-	m.customProviderModel = "some-model"
+	m.customProviderName.SetValue("mycustom")
+	m.customProviderURL.SetValue("https://api.example.com")
+	m.customProviderModel.SetValue("some-model")
 	m.customProviderAPIType = config.APITypeAnthropic
 
 	model, _ = m.submitCustomProvider()
@@ -121,10 +383,1449 @@ func TestCustomProviderFlowClearsStaleSelection(t *testing.T) {
 	resolved := ""
 	if m.selectedProvider != nil {
 		resolved = m.selectedProvider.Name
-	} else if m.customProviderName != "" {
-		resolved = m.customProviderName
+	} else if m.customProviderName.Value() != "" {
+		resolved = m.customProviderName.Value()
 	}
 	if resolved != "mycustom" {
 		t.Errorf("resolved success provider: got %q, want %q", resolved, "mycustom")
 	}
 }
+
+// TestFormInputEditing covers text input behaviour on the forms: typing
+// mid-string, unicode, word deletion and masked API keys.
+func TestFormInputEditing(t *testing.T) {
+	m := NewModel(config.NewDefaultConfig(), nil)
+	m.width = 80
+	model, _ := m.updateMainScreen(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	m = model.(*Model)
+
+	keys := []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune("myprov")},
+		{Type: tea.KeyLeft}, {Type: tea.KeyLeft}, {Type: tea.KeyLeft}, {Type: tea.KeyLeft},
+		{Type: tea.KeyRunes, Runes: []rune("-")},
+		{Type: tea.KeyTab},
+		{Type: tea.KeyRunes, Runes: []rune("Zürich Labs")},
+		{Type: tea.KeyCtrlW},
+	}
+	for _, k := range keys {
+		model, _ = m.Update(k)
+		m = model.(*Model)
+	}
+	if got := m.customProviderName.Value(); got != "my-prov" {
+		t.Errorf("name = %q, want my-prov (typed mid-string)", got)
+	}
+	if got := m.customProviderDisplay.Value(); got != "Zürich " {
+		t.Errorf("display name = %q, want \"Zürich \" (unicode, word deleted)", got)
+	}
+
+	m.inputFocus = 3
+	for _, r := range "sk-secret" {
+		model, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = model.(*Model)
+	}
+	m.inputFocus = 4
+	if view := m.View(); strings.Contains(view, "sk-secret") || !strings.Contains(view, "•••••••••") {
+		t.Error("API key must be masked on the form")
+	}
+}
+
+// TestDetectedServerSetup covers the local-server prompt: a detected but
+// unconfigured server is offered, 'l' opens its form with the detected URL, and
+// the prompt disappears once the provider is configured.
+func TestDetectedServerSetup(t *testing.T) {
+	m := NewModel(config.NewDefaultConfig(), nil)
+
+	model, _ := m.Update(localServersDetectedMsg{servers: []detect.Server{
+		{Provider: "ollama", Name: "Ollama", BaseURL: "http://localhost:11434"},
+	}})
+	m = model.(*Model)
+
+	if _, ok := m.unconfiguredServer(); !ok {
+		t.Fatal("expected an unconfigured detected server")
+	}
+
+	model, _ = m.updateMainScreen(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'l'}})
+	m = model.(*Model)
+
+	if m.screen != ScreenProviderConfig {
+		t.Fatalf("screen: got %v, want ScreenProviderConfig", m.screen)
+	}
+	if got := m.localProviderURL.Value(); got != "http://localhost:11434" {
+		t.Errorf("localProviderURL: got %q", got)
+	}
+
+	model, _ = m.submitLocalProvider()
+	m = model.(*Model)
+
+	if _, ok := m.unconfiguredServer(); ok {
+		t.Error("configured server should no longer be offered")
+	}
+}
+
+func TestProviderItemShowsLastUsed(t *testing.T) {
+	def := &providers.Definition{Name: "zai", Description: "Z.AI GLM models"}
+
+	if got := (ProviderItem{definition: def}).Description(); got != "Z.AI GLM models" {
+		t.Errorf("never used: got %q", got)
+	}
+	if got, want := (ProviderItem{definition: def, lastUsed: "3h ago"}).Description(), "Z.AI GLM models · used 3h ago"; got != want {
+		t.Errorf("used: got %q, want %q", got, want)
+	}
+
+	// Rebuilding the list after a config change keeps the times
+	m := NewModel(config.NewDefaultConfig(), nil)
+	m.usage = config.Usage{"native": time.Now().Add(-3 * time.Hour)}
+	m.refreshProviderList()
+	for _, item := range m.providerList {
+		if item.definition != nil && item.definition.Name == "native" && item.lastUsed != "3h ago" {
+			t.Errorf("native after refresh: lastUsed = %q", item.lastUsed)
+		}
+	}
+}
+
+func TestProviderOrderPinsProviders(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.ProviderOrder = []string{"ollama", "zai"}
+	m := NewModel(cfg, nil)
+
+	var names []string
+	for _, item := range m.list.Items() {
+		if p := item.(ProviderItem); !p.isAddNew {
+			names = append(names, p.definition.Name)
+		}
+	}
+	if len(names) < 3 || names[0] != "ollama" || names[1] != "zai" || names[2] != "native" {
+		t.Errorf("order = %v, want ollama, zai, then native", names)
+	}
+}
+
+// TestModelMappingsEditor covers the tier editor: 'm' opens it for the
+// selected provider, the picker fills the focused tier, and saving drops
+// cleared tiers.
+func TestModelMappingsEditor(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Providers = []*config.Provider{{
+		Name: "ollama", Type: config.ProviderTypeLocal, BaseURL: "http://localhost:11434",
+		Model: "qwen3-coder", ModelMappings: map[string]string{"small": "qwen3:4b"},
+	}}
+	cfg.ProviderOrder = []string{"ollama"}
+	m := NewModel(cfg, nil)
+
+	model, _ := m.updateMainScreen(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	m = model.(*Model)
+	if m.screen != ScreenModelMappings {
+		t.Fatalf("screen: got %v, want ScreenModelMappings", m.screen)
+	}
+	if got := m.mappingInputs[3].Value(); got != "qwen3:4b" {
+		t.Errorf("small tier not loaded: %q", got)
+	}
+
+	// Fetched models open the picker on the opus tier; enter picks one
+	model, _ = m.Update(modelsFetchedMsg{
+		models:     []models.ModelInfo{{ID: "glm-5"}},
+		generation: m.fetchGeneration,
+	})
+	m = model.(*Model)
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = model.(*Model)
+	if got := m.mappingInputs[0].Value(); got != "glm-5" {
+		t.Fatalf("opus tier = %q, want glm-5", got)
+	}
+
+	m.mappingInputs[3].Reset()
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = model.(*Model)
+	if m.screen != ScreenSuccess {
+		t.Fatalf("screen: got %v, want ScreenSuccess", m.screen)
+	}
+	got := cfg.GetProvider("ollama").ModelMappings
+	if len(got) != 1 || got["opus"] != "glm-5" {
+		t.Errorf("ModelMappings = %v, want only opus: glm-5", got)
+	}
+}
+
+// TestDeleteProviderConfirms covers deletion: 'd' asks first, cancelling
+// keeps the provider, and confirming removes it and the settings naming it.
+func TestDeleteProviderConfirms(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Providers = []*config.Provider{{
+		Name: "ollama", Type: config.ProviderTypeLocal, DisplayName: "Ollama", BaseURL: "http://localhost:11434",
+	}}
+	cfg.DefaultProvider = "ollama"
+	cfg.ProviderOrder = []string{"ollama", "zai"}
+	cfg.DirectoryRules = []config.DirectoryRule{
+		{Path: "~/work", Provider: "ollama"},
+		{Path: "~/oss", Provider: "zai"},
+	}
+	m := NewModel(cfg, nil)
+
+	press := func(msg tea.KeyMsg) {
+		model, _ := m.Update(msg)
+		m = model.(*Model)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	if m.screen != ScreenConfirm {
+		t.Fatalf("screen: got %v, want ScreenConfirm", m.screen)
+	}
+	press(tea.KeyMsg{Type: tea.KeyEnter}) // Cancel is selected by default
+	if m.screen != ScreenMain || cfg.GetProvider("ollama") == nil {
+		t.Fatal("cancelling should keep the provider and return to the list")
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyDelete})
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if cfg.GetProvider("ollama") != nil {
+		t.Error("provider not removed")
+	}
+	if cfg.DefaultProvider != "" {
+		t.Errorf("default_provider = %q, want cleared", cfg.DefaultProvider)
+	}
+	if !slices.Equal(cfg.ProviderOrder, []string{"zai"}) {
+		t.Errorf("provider_order = %q, want only zai", cfg.ProviderOrder)
+	}
+	if len(cfg.DirectoryRules) != 1 || cfg.DirectoryRules[0].Provider != "zai" {
+		t.Errorf("directory_rules = %+v, want only the zai rule", cfg.DirectoryRules)
+	}
+	if m.screen != ScreenSuccess {
+		t.Errorf("screen: got %v, want ScreenSuccess", m.screen)
+	}
+}
+
+// TestProviderTestScreen covers in-TUI testing: 't' opens the test screen
+// with every configured provider pending, results fill in as they arrive,
+// and esc returns to the list where it was.
+func TestProviderTestScreen(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	cfg := config.NewDefaultConfig()
+	cfg.Providers = []*config.Provider{
+		{Name: "ollama", Type: config.ProviderTypeLocal, DisplayName: "Ollama", BaseURL: srv.URL},
+		{Name: "down", Type: config.ProviderTypeLocal, BaseURL: "http://127.0.0.1:1"},
+	}
+	m := NewModel(cfg, nil)
+	m.width = 80
+	m.list.Select(2)
+
+	model, _ := m.updateMainScreen(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	m = model.(*Model)
+	if m.screen != ScreenTest || len(m.tests) != 2 || !m.testsRunning() {
+		t.Fatalf("screen %v, tests %+v: want both pending on ScreenTest", m.screen, m.tests)
+	}
+
+	for _, p := range cfg.Providers {
+		model, _ = m.Update(testProviderCmd(p, m.cfg.NetworkTimeout(), m.testGeneration)())
+		m = model.(*Model)
+	}
+	if m.testsRunning() {
+		t.Fatal("tests still running after all results arrived")
+	}
+	if !m.tests[0].reachable || m.tests[0].statusCode != http.StatusNotFound {
+		t.Errorf("ollama: %+v, want reachable with HTTP 404", m.tests[0])
+	}
+	if m.tests[1].reachable || m.tests[1].err == "" {
+		t.Errorf("down: %+v, want unreachable with an error", m.tests[1])
+	}
+	if view := m.viewTestScreen(); !strings.Contains(view, "1 reachable") {
+		t.Errorf("summary missing from view:\n%s", view)
+	}
+
+	// A result from an earlier run is ignored
+	stale := providerTestedMsg{generation: m.testGeneration - 1, result: providerTest{name: "down", done: true, reachable: true}}
+	model, _ = m.Update(stale)
+	m = model.(*Model)
+	if m.tests[1].reachable {
+		t.Error("stale result was recorded")
+	}
+
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = model.(*Model)
+	if m.screen != ScreenMain || m.list.Index() != 2 {
+		t.Errorf("after esc: screen %v, index %d; want ScreenMain at 2", m.screen, m.list.Index())
+	}
+}
+
+// TestProviderTestLlamaCpp covers testing llama.cpp through llama-server's
+// health endpoint, which reports whether the model is loaded.
+func TestProviderTestLlamaCpp(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/health":
+			fmt.Fprint(w, `{"status":"ok"}`)
+		case "/props":
+			fmt.Fprint(w, `{"model_path":"/models/gpt-oss-20b.gguf","default_generation_settings":{"n_ctx":131072}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	p := &config.Provider{Name: "llamacpp", Type: config.ProviderTypeLocal, DisplayName: "llama.cpp", BaseURL: srv.URL}
+	test := probe(testURL(p), newProviderTest(p), 5*time.Second)
+	if !test.reachable || test.server == nil || !test.server.Loaded {
+		t.Fatalf("test = %+v, want reachable with the model loaded", test)
+	}
+	if got := test.summary(); !strings.Contains(got, "gpt-oss-20b.gguf loaded, 131k context") {
+		t.Errorf("summary = %q, want the model and context size", got)
+	}
+}
+
+// TestSingleProviderTest covers 'T': only the selected provider is tested,
+// and its result is shown on its list item and survives a list rebuild.
+func TestSingleProviderTest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer srv.Close()
+
+	cfg := config.NewDefaultConfig()
+	cfg.Providers = []*config.Provider{{Name: "ollama", Type: config.ProviderTypeLocal, DisplayName: "Ollama", BaseURL: srv.URL}}
+	cfg.ProviderOrder = []string{"ollama"}
+	m := NewModel(cfg, nil)
+
+	model, cmd := m.updateMainScreen(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'T'}})
+	m = model.(*Model)
+	if m.screen != ScreenMain || cmd == nil {
+		t.Fatalf("T should stay on the list and start a test (screen %v)", m.screen)
+	}
+	item := m.list.SelectedItem().(ProviderItem)
+	if item.test == nil || item.test.done {
+		t.Fatalf("selected item should show a pending test, got %+v", item.test)
+	}
+
+	model, _ = m.Update(cmd())
+	m = model.(*Model)
+	item = m.list.SelectedItem().(ProviderItem)
+	if item.test == nil || !item.test.reachable {
+		t.Fatalf("selected item should show a reachable result, got %+v", item.test)
+	}
+
+	m.refreshProviderList()
+	if item := m.list.SelectedItem().(ProviderItem); item.test == nil || !item.test.done {
+		t.Error("test result lost when the list was rebuilt")
+	}
+}
+
+// TestSettingsScreenTogglesConfig covers the settings screen: 's' opens it,
+// space toggles the selected option in the config, and esc returns.
+func TestSettingsScreenTogglesConfig(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	m := NewModel(cfg, nil)
+
+	model, _ := m.updateMainScreen(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	m = model.(*Model)
+	if m.screen != ScreenSettings {
+		t.Fatalf("screen: got %v, want ScreenSettings", m.screen)
+	}
+
+	// First option is auto-launch, on by default
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	m = model.(*Model)
+	if cfg.AutoLaunchAfterUse {
+		t.Error("space should turn auto_launch_after_use off")
+	}
+
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = model.(*Model)
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = model.(*Model)
+	if !cfg.ConfirmBeforeLaunch {
+		t.Error("enter should turn confirm_before_launch on")
+	}
+
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = model.(*Model)
+	if m.screen != ScreenMain {
+		t.Errorf("screen after esc: got %v, want ScreenMain", m.screen)
+	}
+}
+
+func TestThemePalette(t *testing.T) {
+	want := lipgloss.AdaptiveColor{Light: "#6D28D9", Dark: "#7C3AED"}
+	if got := themePalette(nil); got.primary != want {
+		t.Errorf("nil theme primary = %v, want %v", got.primary, want)
+	}
+	if got := themePalette(&config.Theme{Preset: config.ThemeDark}); got.primary != palettes[config.ThemeDark].primary {
+		t.Errorf("dark preset primary = %v, want the dark colour only", got.primary)
+	}
+
+	p := themePalette(&config.Theme{Preset: config.ThemeLight, Primary: "#112233"})
+	if p.primary != lipgloss.Color("#112233") {
+		t.Errorf("primary = %v, want the override", p.primary)
+	}
+	if p.success != palettes[config.ThemeLight].success {
+		t.Errorf("success = %v, want the light preset's", p.success)
+	}
+
+	s := DefaultStyles(&config.Theme{Preset: config.ThemeMono})
+	if _, ok := s.PrimaryColor.(lipgloss.NoColor); !ok {
+		t.Errorf("mono primary = %v, want no colour", s.PrimaryColor)
+	}
+	if !s.ButtonActive.GetReverse() {
+		t.Error("mono active button should be reversed")
+	}
+}
+
+func TestHelpScreen(t *testing.T) {
+	m := NewModel(config.NewDefaultConfig(), nil)
+	m.width = 100
+
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	m = model.(*Model)
+	if m.screen != ScreenHelp {
+		t.Fatalf("screen: got %v, want ScreenHelp", m.screen)
+	}
+	if view := m.View(); !strings.Contains(view, "Model picker") || !strings.Contains(view, "delete word") {
+		t.Error("help should list the model picker and form keys")
+	}
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = model.(*Model)
+	if m.screen != ScreenMain {
+		t.Fatalf("esc: got %v, want ScreenMain", m.screen)
+	}
+
+	// Help returns to the screen it was opened from
+	m.screen = ScreenSettings
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	m = model.(*Model)
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	m = model.(*Model)
+	if m.screen != ScreenSettings {
+		t.Errorf("closing help: got %v, want ScreenSettings", m.screen)
+	}
+}
+
+func TestProviderDetails(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	p := &config.Provider{
+		Name: "zai", Type: config.ProviderTypeBuiltin, DisplayName: "Z.AI",
+		BaseURL: "https://api.z.ai/api/anthropic", Model: "glm-5",
+		APIKeyRef: "keyring:zai", ModelMappings: map[string]string{"haiku": "glm-4.5-air"},
+	}
+	p.SetResolvedAPIKey("sk-zai-0123456789abcdef")
+	cfg.Providers = []*config.Provider{p}
+	cfg.ProviderOrder = []string{"zai"}
+	m := NewModel(cfg, nil)
+	m.width = 100
+	m.usage = config.Usage{}
+
+	model, _ := m.updateMainScreen(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'i'}})
+	m = model.(*Model)
+	if m.screen != ScreenDetails {
+		t.Fatalf("screen: got %v, want ScreenDetails", m.screen)
+	}
+
+	view := m.View()
+	for _, want := range []string{"OS keyring", "[keyring]", "glm-4.5-air", "ANTHROPIC_BASE_URL", "sk-z****cdef", "never"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("details missing %q", want)
+		}
+	}
+	if strings.Contains(view, "sk-zai-0123456789abcdef") {
+		t.Error("details must mask the API key")
+	}
+
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if model.(*Model).screen != ScreenMain {
+		t.Error("esc should return to the provider list")
+	}
+}
+
+func TestSettingsChoiceAndArgs(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	m := NewModel(cfg, nil)
+	m.width = 80
+	m.screen = ScreenSettings
+
+	for m.settingsIdx = range settings {
+		if settings[m.settingsIdx].choice != nil {
+			break
+		}
+	}
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	m = model.(*Model)
+	if cfg.OutputFormat != config.FormatJSON {
+		t.Errorf("output format = %q, want json after →", cfg.OutputFormat)
+	}
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	m = model.(*Model)
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	m = model.(*Model)
+	if cfg.OutputFormat != config.FormatTable {
+		t.Errorf("output format = %q, want table (wrapped around)", cfg.OutputFormat)
+	}
+
+	// An unset watchdog is off, so → asks
+	m.settingsIdx++
+	if !strings.Contains(m.View(), "‹ off ›") {
+		t.Error("unset watchdog should show as off")
+	}
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	m = model.(*Model)
+	if cfg.Watchdog != config.WatchdogAsk {
+		t.Errorf("watchdog = %q, want ask after →", cfg.Watchdog)
+	}
+
+	// Claude arguments open the argument editor
+	m.settingsIdx = len(settings) - 1
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = model.(*Model)
+	if m.screen != ScreenClaudeArgs || m.argsProvider != "" {
+		t.Errorf("screen = %v, provider = %q; want the global argument editor", m.screen, m.argsProvider)
+	}
+}
+
+// TestClaudeArgsEditor covers adding, editing, reordering and removing a
+// provider's claude arguments.
+func TestClaudeArgsEditor(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Providers = []*config.Provider{{
+		Name: "ollama", Type: config.ProviderTypeLocal, BaseURL: "http://localhost:11434",
+	}}
+	cfg.ProviderOrder = []string{"ollama"}
+	m := NewModel(cfg, nil)
+	m.width = 80
+
+	press := func(keys ...tea.KeyMsg) {
+		t.Helper()
+		for _, k := range keys {
+			model, _ := m.Update(k)
+			m = model.(*Model)
+		}
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+	args := func() []string { return cfg.GetProvider("ollama").ClaudeArgs }
+
+	press(runes("A"))
+	if m.screen != ScreenClaudeArgs || m.argsProvider != "ollama" {
+		t.Fatalf("screen = %v, provider = %q; want ollama's argument editor", m.screen, m.argsProvider)
+	}
+
+	// One entry can add several arguments; quoting keeps spaces
+	press(runes("a"), runes(`--continue --append-system-prompt "be brief"`), tea.KeyMsg{Type: tea.KeyEnter})
+	if want := []string{"--continue", "--append-system-prompt", "be brief"}; !slices.Equal(args(), want) {
+		t.Fatalf("args = %q, want %q", args(), want)
+	}
+
+	// Move --continue to the end, then edit it
+	press(runes("k"), runes("k"), runes("J"), runes("J"))
+	if want := []string{"--append-system-prompt", "be brief", "--continue"}; !slices.Equal(args(), want) {
+		t.Fatalf("after move: args = %q, want %q", args(), want)
+	}
+	press(runes("e"), tea.KeyMsg{Type: tea.KeyCtrlU}, runes("--resume"), tea.KeyMsg{Type: tea.KeyEnter})
+	if args()[2] != "--resume" {
+		t.Errorf("edited arg = %q, want --resume", args()[2])
+	}
+
+	// An unterminated quote is an error and keeps the input open
+	press(runes("a"), runes(`"oops`), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.inputError == "" || !m.argsEditing {
+		t.Error("unterminated quote should keep the input open with an error")
+	}
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+
+	press(runes("d"), runes("d"), runes("d"))
+	if args() != nil {
+		t.Errorf("args = %q, want none after removing all", args())
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.screen != ScreenMain {
+		t.Errorf("esc: screen = %v, want ScreenMain", m.screen)
+	}
+}
+
+// TestOpenRouterScreen covers managing or-* providers: adding one shares
+// the stored key, renaming moves the settings naming it, names must be
+// unique, and deleting removes those settings.
+func TestOpenRouterScreen(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Providers = []*config.Provider{{
+		Name: "or-gpt4o", Type: config.ProviderTypeOpenRouter, BaseURL: config.OpenRouterBaseURL,
+		Model: "openai/gpt-4o", APIKeyRef: "keyring:openrouter",
+	}}
+	cfg.DefaultProvider = "or-gpt4o"
+	cfg.ProviderOrder = []string{"or-gpt4o"}
+	cfg.DirectoryRules = []config.DirectoryRule{{Path: "~/work", Provider: "or-gpt4o"}}
+	m := NewModel(cfg, nil)
+	m.width = 80
+
+	press := func(keys ...tea.KeyMsg) {
+		t.Helper()
+		for _, k := range keys {
+			model, _ := m.Update(k)
+			m = model.(*Model)
+		}
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+
+	press(runes("o"))
+	if m.screen != ScreenOpenRouter {
+		t.Fatalf("screen = %v, want ScreenOpenRouter", m.screen)
+	}
+
+	// The short name defaults to the last part of the model ID
+	press(runes("a"), runes("deepseek/deepseek-r1"), enter)
+	p := cfg.GetProvider("or-deepseek-r1")
+	if p == nil || p.Model != "deepseek/deepseek-r1" || p.APIKeyRef != "keyring:openrouter" {
+		t.Fatalf("added provider = %+v, want or-deepseek-r1 sharing the key", p)
+	}
+
+	// Rename or-gpt4o, the default
+	press(runes("j"), runes("r"), tea.KeyMsg{Type: tea.KeyCtrlU}, runes("gpt"), enter)
+	if cfg.GetProvider("or-gpt4o") != nil || cfg.GetProvider("or-gpt") == nil {
+		t.Fatal("or-gpt4o should be renamed to or-gpt")
+	}
+	if cfg.DefaultProvider != "or-gpt" {
+		t.Errorf("default_provider = %q, want or-gpt", cfg.DefaultProvider)
+	}
+	if !slices.Equal(cfg.ProviderOrder, []string{"or-gpt"}) || cfg.DirectoryRules[0].Provider != "or-gpt" {
+		t.Errorf("provider_order %q, directory_rules %+v: want them moved to or-gpt", cfg.ProviderOrder, cfg.DirectoryRules)
+	}
+
+	press(runes("a"), runes("x/y"), tea.KeyMsg{Type: tea.KeyTab}, runes("gpt"), enter)
+	if !m.orEditing || !strings.Contains(m.inputError, "already exists") {
+		t.Errorf("duplicate name: error = %q, want it rejected", m.inputError)
+	}
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+
+	press(runes("d"), runes("y"))
+	if len(m.openRouterProviders()) != 1 || m.screen != ScreenOpenRouter {
+		t.Errorf("delete: %d providers left on screen %v, want 1 on ScreenOpenRouter", len(m.openRouterProviders()), m.screen)
+	}
+	if cfg.GetProvider("or-gpt") != nil {
+		t.Fatal("or-gpt should be deleted")
+	}
+	if cfg.DefaultProvider != "" || len(cfg.ProviderOrder) != 0 || len(cfg.DirectoryRules) != 0 {
+		t.Errorf("after delete: default %q, provider_order %q, directory_rules %+v; want all cleared",
+			cfg.DefaultProvider, cfg.ProviderOrder, cfg.DirectoryRules)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.screen != ScreenMain {
+		t.Errorf("esc: screen = %v, want ScreenMain", m.screen)
+	}
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	if _, _, ok := fuzzyMatch("gpt5", "openai/gpt-4o"); ok {
+		t.Error("gpt5 should not match openai/gpt-4o")
+	}
+	_, matched, ok := fuzzyMatch("CS4", "anthropic/claude-sonnet-4")
+	if !ok || !slices.Equal(matched, []int{10, 17, 24}) {
+		t.Errorf("cs4: matched = %v, %v; want the word starts [10 17 24]", matched, ok)
+	}
+
+	// The picker ranks contiguous and word-start matches first
+	m := newAPIKeyScreenModel()
+	m.fetchedModels = []models.ModelInfo{
+		{ID: "mistralai/codestral-2501"},
+		{ID: "qwen/qwen3-coder"},
+		{ID: "openai/gpt-4o"},
+		{ID: "deepseek/deepseek-coder"},
+	}
+	setInput(&m.modelInput, "coder")
+	var got []string
+	for _, mi := range m.filteredModels() {
+		got = append(got, mi.ID)
+	}
+	if want := []string{"qwen/qwen3-coder", "deepseek/deepseek-coder", "mistralai/codestral-2501"}; !slices.Equal(got, want) {
+		t.Errorf("filtered = %q, want %q", got, want)
+	}
+}
+
+func TestRecentModels(t *testing.T) {
+	m := newAPIKeyScreenModel()
+	name := m.selectedProvider.Name
+	for _, model := range []string{"a", "b", "c", "d", "e", "b", "f"} {
+		m.rememberModels(name, model)
+	}
+	if want := []string{"f", "b", "e", "d", "c"}; !slices.Equal(m.recentModels[name], want) {
+		t.Errorf("recent = %q, want %q: newest first, no repeats, at most %d", m.recentModels[name], want, maxRecentModels)
+	}
+
+	// Recent models come first, even ones the provider no longer lists, and
+	// aren't repeated below. Recommended models have their own test.
+	m.recommendations = models.Recommendations{}
+	m.recentModels[name] = []string{"glm-4.6", "retired-model"}
+	m.fetchedModels = []models.ModelInfo{{ID: "glm-5"}, {ID: "glm-4.6"}, {ID: "glm-4.5-air"}}
+	m.modelPickerOpen = true
+	var got []string
+	for _, mi := range m.filteredModels() {
+		got = append(got, mi.ID)
+	}
+	if want := []string{"glm-4.6", "retired-model", "glm-5", "glm-4.5-air"}; !slices.Equal(got, want) {
+		t.Errorf("picker = %q, want %q", got, want)
+	}
+	picker := m.renderModelPicker()
+	if !strings.Contains(picker, "Recent") || !strings.Contains(picker, "All models") {
+		t.Errorf("picker should have Recent and All models sections:\n%s", picker)
+	}
+
+	// They are filtered like the rest
+	setInput(&m.modelInput, "air")
+	if f := m.filteredModels(); len(f) != 1 || f[0].ID != "glm-4.5-air" {
+		t.Errorf("filtered = %v, want just glm-4.5-air", f)
+	}
+
+	// And kept between runs
+	restored := NewModel(m.cfg, nil)
+	restored.Restore(m.State())
+	if !slices.Equal(restored.recentModels[name], m.recentModels[name]) {
+		t.Errorf("restored recent = %q, want %q", restored.recentModels[name], m.recentModels[name])
+	}
+}
+
+func TestPickerMetadataColumns(t *testing.T) {
+	rows := []modelMatch{{ModelInfo: models.ModelInfo{
+		ID: "anthropic/claude-sonnet-4", ContextLength: 200000,
+		Pricing: &models.Pricing{Input: 3, Output: 15}, Modality: "text+image->text",
+	}}}
+	titles := func(columns []pickerColumn) []string {
+		var ts []string
+		for _, c := range columns {
+			ts = append(ts, c.title)
+		}
+		return ts
+	}
+	if _, columns := pickerLayout(rows, 100); len(columns) != 3 {
+		t.Errorf("wide: columns = %q, want all three", titles(columns))
+	}
+	// Narrow pickers drop modality first, then pricing
+	if _, columns := pickerLayout(rows, 40); !slices.Equal(titles(columns), []string{"context"}) {
+		t.Errorf("narrow: columns = %q, want [context]", titles(columns))
+	}
+	// Models without metadata show no columns
+	if _, columns := pickerLayout([]modelMatch{{ModelInfo: models.ModelInfo{ID: "glm-5"}}}, 100); columns != nil {
+		t.Errorf("no metadata: columns = %q, want none", titles(columns))
+	}
+}
+
+// TestScrollKeepsFocusVisible covers forms taller than the terminal: the
+// view fits the terminal and follows the focused field, and page keys scroll.
+func TestScrollKeepsFocusVisible(t *testing.T) {
+	m := NewModel(config.NewDefaultConfig(), nil)
+	press := func(msg tea.Msg) {
+		model, _ := m.Update(msg)
+		m = model.(*Model)
+	}
+	press(tea.WindowSizeMsg{Width: 80, Height: 24})
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+
+	view := m.View()
+	if h := lipgloss.Height(view); h > 24 {
+		t.Fatalf("view is %d lines, want at most 24", h)
+	}
+	if !strings.Contains(view, "↓") || strings.Contains(view, focusMark) {
+		t.Error("overflowing view should show a scroll indicator and no focus mark")
+	}
+
+	// Required fields are filled in on the way down
+	for _, value := range []string{"mine", "", "http://localhost:8000", ""} {
+		if value != "" {
+			press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(value)})
+		}
+		press(tea.KeyMsg{Type: tea.KeyTab})
+	}
+	view = m.View()
+	if !strings.Contains(view, "Model*") || strings.Contains(view, "Configuration Guide") {
+		t.Error("view should scroll down to the focused model field")
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyPgUp})
+	if view = m.View(); !strings.Contains(view, "Configuration Guide") {
+		t.Error("pgup should scroll back to the top")
+	}
+}
+
+// TestSelectionRestored covers keeping the selected provider when the list
+// is rebuilt and when the TUI is opened again.
+func TestSelectionRestored(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	cfg := config.NewDefaultConfig()
+	m := NewModel(cfg, nil)
+
+	selected := func() string {
+		item, _ := m.list.SelectedItem().(ProviderItem)
+		if item.definition == nil {
+			return ""
+		}
+		return item.definition.Name
+	}
+
+	if !m.selectProvider("ollama") {
+		t.Fatal("ollama should be in the list")
+	}
+	before := m.list.Index()
+
+	// Configuring ollama moves it up the list; the selection follows it
+	cfg.Providers = append(cfg.Providers, &config.Provider{Name: "ollama", Type: config.ProviderTypeLocal, BaseURL: "http://localhost:11434"})
+	m.refreshProviderList()
+	if m.list.Index() == before || selected() != "ollama" {
+		t.Errorf("after refresh: selected %q at %d, want ollama moved from %d", selected(), m.list.Index(), before)
+	}
+
+	if err := SaveState(m.State()); err != nil {
+		t.Fatal(err)
+	}
+	m = NewModel(cfg, nil)
+	m.Restore(LoadState())
+	if selected() != "ollama" {
+		t.Errorf("restored selection = %q, want ollama", selected())
+	}
+}
+
+// TestOpenProvider covers 'skint config <name>' opening the TUI on that
+// provider's form.
+func TestOpenProvider(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Providers = []*config.Provider{
+		{Name: "ollama", Type: config.ProviderTypeLocal, BaseURL: "http://gpu-box:11434"},
+		{Name: "or-gpt4o", Type: config.ProviderTypeOpenRouter, BaseURL: config.OpenRouterBaseURL, Model: "openai/gpt-4o", APIKeyRef: "keyring:openrouter"},
+	}
+
+	tests := []struct {
+		name   string
+		screen Screen
+	}{
+		{"zai", ScreenAPIKeyInput},
+		{"ollama", ScreenProviderConfig},
+		{"custom", ScreenCustomProvider},
+		{"openrouter", ScreenOpenRouter},
+		{"or-gpt4o", ScreenOpenRouter},
+	}
+	for _, tt := range tests {
+		m := NewModel(cfg, nil)
+		if !m.OpenProvider(tt.name) || m.screen != tt.screen {
+			t.Errorf("%s: screen = %v, want %v", tt.name, m.screen, tt.screen)
+		}
+	}
+
+	m := NewModel(cfg, nil)
+	if m.OpenProvider("ollama"); m.localProviderURL.Value() != "http://gpu-box:11434" {
+		t.Errorf("ollama form URL = %q, want the configured one", m.localProviderURL.Value())
+	}
+	m = NewModel(cfg, nil)
+	if m.OpenProvider("or-gpt4o"); !m.orEditing || m.orEditName != "or-gpt4o" {
+		t.Error("or-gpt4o should open its form on the OpenRouter screen")
+	}
+	m = NewModel(cfg, nil)
+	if m.OpenProvider("nope") || m.screen != ScreenMain {
+		t.Error("an unknown provider should leave the list open")
+	}
+}
+
+func TestAPIKeyStoredInBackground(t *testing.T) {
+	m := newAPIKeyScreenModel()
+	m.selectedProvider.DisplayName = "Z.AI"
+	m.selectedProvider.DefaultModel = "glm-5"
+	m.selectedProvider.Type = config.ProviderTypeBuiltin
+	m.inputFocus = 0
+	m.width = 80
+	press := func(k tea.KeyMsg) {
+		t.Helper()
+		model, _ := m.Update(k)
+		m = model.(*Model)
+	}
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("sk-test-key")})
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.keyStoring || m.screen != ScreenAPIKeyInput {
+		t.Fatalf("after enter: storing = %v on screen %v, want storing on ScreenAPIKeyInput", m.keyStoring, m.screen)
+	}
+	if !strings.Contains(m.View(), "Storing API key") {
+		t.Error("the form should show the key is being stored")
+	}
+
+	// Keys are ignored until the key is stored
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.screen != ScreenAPIKeyInput {
+		t.Errorf("esc while storing: screen = %v, want ScreenAPIKeyInput", m.screen)
+	}
+
+	model, _ := m.Update(keyStoredMsg{err: errors.New("keyring locked")})
+	m = model.(*Model)
+	if m.keyStoring || m.screen != ScreenAPIKeyInput || !strings.Contains(m.inputError, "keyring locked") {
+		t.Fatalf("failed store: screen %v, error %q, want the form with the error", m.screen, m.inputError)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	model, _ = m.Update(keyStoredMsg{ref: "keyring:zai"})
+	m = model.(*Model)
+	if m.screen != ScreenSuccess {
+		t.Fatalf("screen = %v (%q), want ScreenSuccess", m.screen, m.inputError)
+	}
+	if p := m.cfg.GetProvider("zai"); p == nil || p.APIKeyRef != "keyring:zai" {
+		t.Errorf("provider = %+v, want zai with the stored key's reference", p)
+	}
+}
+
+// TestInlineValidation covers checking fields while typing and refusing to
+// leave a required field empty.
+func TestInlineValidation(t *testing.T) {
+	m := NewModel(config.NewDefaultConfig(), nil)
+	m.width = 80
+	press := func(keys ...tea.KeyMsg) {
+		t.Helper()
+		for _, k := range keys {
+			model, _ := m.Update(k)
+			m = model.(*Model)
+		}
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+	tab := tea.KeyMsg{Type: tea.KeyTab}
+
+	press(runes("c"), tab)
+	if m.inputFocus != 0 || m.inputError != "Provider name is required" {
+		t.Fatalf("tab off an empty name: focus %d, error %q, want it refused", m.inputFocus, m.inputError)
+	}
+	if n := strings.Count(m.View(), "Provider name is required"); n != 1 {
+		t.Errorf("required hint shown %d times, want once", n)
+	}
+
+	press(runes("My"))
+	if m.inputError != "" || !strings.Contains(m.View(), errNameFormat) {
+		t.Error("an invalid name should be flagged while typing")
+	}
+	press(tea.KeyMsg{Type: tea.KeyBackspace}, tea.KeyMsg{Type: tea.KeyBackspace}, runes("mine"), tab, tab)
+	if m.inputFocus != 2 {
+		t.Fatalf("focus = %d, want the base URL field", m.inputFocus)
+	}
+
+	press(runes("http"))
+	if strings.Contains(m.View(), errURLScheme) {
+		t.Error("a URL part way through its scheme should not be flagged")
+	}
+	press(tea.KeyMsg{Type: tea.KeyCtrlU}, runes("ftp://host"))
+	if !strings.Contains(m.View(), errURLScheme) {
+		t.Error("a non-http URL should be flagged while typing")
+	}
+
+	// The optional API key can be left empty
+	press(tea.KeyMsg{Type: tea.KeyCtrlU}, runes("http://localhost:8000"), tab, tab)
+	if m.inputFocus != 4 {
+		t.Fatalf("focus = %d, want the model field past the empty API key", m.inputFocus)
+	}
+	press(tea.KeyMsg{Type: tea.KeyShiftTab})
+	if m.inputError == "" || m.inputFocus != 4 {
+		t.Error("shift+tab off an empty required model should be refused")
+	}
+}
+
+// TestConfirmOverwrite covers asking before a form saves over a configured
+// provider, with what changes, and keeping its saved key.
+func TestConfirmOverwrite(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Providers = []*config.Provider{
+		{Name: "mine", Type: config.ProviderTypeCustom, DisplayName: "Mine", BaseURL: "http://old:8000", Model: "m1",
+			APIKeyRef: "keyring:mine", APIType: config.APITypeAnthropic, ClaudeArgs: []string{"--verbose"}},
+		{Name: "ollama", Type: config.ProviderTypeLocal, DisplayName: "Ollama", BaseURL: "http://localhost:11434"},
+	}
+	m := NewModel(cfg, nil)
+	m.width = 80
+	press := func(keys ...tea.KeyMsg) {
+		t.Helper()
+		for _, k := range keys {
+			model, _ := m.Update(k)
+			m = model.(*Model)
+		}
+	}
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+
+	m.OpenProvider("mine")
+	m.inputFocus = 2
+	press(tea.KeyMsg{Type: tea.KeyCtrlU}, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("http://new:8000")}, enter)
+	if m.screen != ScreenConfirm {
+		t.Fatalf("screen = %v, want ScreenConfirm", m.screen)
+	}
+	view := m.View()
+	for _, want := range []string{"Base URL: http://old:8000 → http://new:8000", "Claude args: --verbose → (none)"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("confirm view missing %q", want)
+		}
+	}
+	if strings.Contains(view, "API key") || strings.Contains(view, "Model:") {
+		t.Error("unchanged fields and the kept key should not be listed")
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.screen != ScreenCustomProvider || cfg.GetProvider("mine").BaseURL != "http://old:8000" {
+		t.Fatalf("cancel: screen %v, want the form with the provider unchanged", m.screen)
+	}
+
+	press(enter, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	p := cfg.GetProvider("mine")
+	if m.screen != ScreenSuccess || p.BaseURL != "http://new:8000" || p.APIKeyRef != "keyring:mine" {
+		t.Errorf("overwrite: screen %v, provider %+v, want it saved with its key", m.screen, p)
+	}
+
+	// Saving without changes needs no confirmation
+	m.OpenProvider("ollama")
+	press(enter)
+	if m.screen != ScreenSuccess {
+		t.Errorf("unchanged save: screen = %v, want ScreenSuccess", m.screen)
+	}
+}
+
+// TestKeyBackendBadge covers the badge showing where each provider's API key
+// is kept.
+func TestKeyBackendBadge(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Providers = []*config.Provider{
+		{Name: "zai", Type: config.ProviderTypeBuiltin, APIKeyRef: "keyring:zai"},
+		{Name: "deepseek", Type: config.ProviderTypeBuiltin, APIKeyRef: "file:deepseek"},
+		{Name: "kimi", Type: config.ProviderTypeBuiltin, APIKeyRef: "env:KIMI_API_KEY"},
+		{Name: "ollama", Type: config.ProviderTypeLocal, BaseURL: "http://localhost:11434"},
+	}
+	cfg.ProviderOrder = []string{"zai", "deepseek", "kimi", "ollama"}
+	m := NewModel(cfg, nil)
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+
+	view := m.View()
+	for _, want := range []string{"[keyring]", "[file]", "[env]"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("list missing %q", want)
+		}
+	}
+	if got := cfg.GetProvider("ollama").KeyBackend(); got != "" {
+		t.Errorf("local provider badge = %q, want none", got)
+	}
+}
+
+// TestUndo covers ctrl+z on the list reverting the last change, newest
+// first, including a deleted provider and a changed setting.
+func TestUndo(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Providers = []*config.Provider{{
+		Name: "ollama", Type: config.ProviderTypeLocal, DisplayName: "Ollama", BaseURL: "http://gpu-box:11434",
+	}}
+	cfg.DefaultProvider = "ollama"
+	cfg.ProviderOrder = []string{"ollama"}
+	m := NewModel(cfg, nil)
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	press := func(keys ...tea.KeyMsg) {
+		t.Helper()
+		for _, k := range keys {
+			model, _ := m.Update(k)
+			m = model.(*Model)
+		}
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+	esc := tea.KeyMsg{Type: tea.KeyEsc}
+	undo := tea.KeyMsg{Type: tea.KeyCtrlZ}
+
+	press(undo)
+	if m.listStatus != "Nothing to undo" {
+		t.Errorf("status = %q, want nothing to undo", m.listStatus)
+	}
+
+	press(runes("d"), runes("y"), esc)
+	press(runes("s"), tea.KeyMsg{Type: tea.KeyEnter}, esc)
+	autoLaunch := config.NewDefaultConfig().AutoLaunchAfterUse
+	if cfg.GetProvider("ollama") != nil || cfg.AutoLaunchAfterUse == autoLaunch {
+		t.Fatal("the provider should be deleted and the setting changed")
+	}
+
+	press(undo)
+	if cfg.AutoLaunchAfterUse != autoLaunch || cfg.GetProvider("ollama") != nil {
+		t.Error("first undo should revert only the setting")
+	}
+	press(undo)
+	p := cfg.GetProvider("ollama")
+	if p == nil || p.BaseURL != "http://gpu-box:11434" || cfg.DefaultProvider != "ollama" {
+		t.Fatalf("second undo: provider %+v, default %q, want both restored", p, cfg.DefaultProvider)
+	}
+	if !strings.Contains(m.View(), "Undone: delete Ollama") {
+		t.Error("the list should say what was undone")
+	}
+	if item, ok := m.list.SelectedItem().(ProviderItem); !ok || !item.configured || item.definition.Name != "ollama" {
+		t.Error("the restored provider should be configured in the list")
+	}
+}
+
+// TestMouse covers clicking list items and buttons and the wheel over the
+// model picker. Zones are found from where the last view drew them.
+func TestMouse(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Providers = []*config.Provider{
+		{Name: "ollama", Type: config.ProviderTypeLocal, DisplayName: "Ollama", BaseURL: "http://localhost:11434"},
+	}
+	m := NewModel(cfg, nil)
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	click := func(id int) {
+		t.Helper()
+		for _, z := range m.zones {
+			if z.id == id {
+				model, _ := m.Update(tea.MouseMsg{X: z.x0, Y: z.y, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+				m = model.(*Model)
+				return
+			}
+		}
+		t.Fatalf("zone %d not in view", id)
+	}
+
+	if view := m.View(); zoneMarkRe.MatchString(view) {
+		t.Fatal("zone marks must be removed from the view")
+	}
+	click(zoneListItem + 2)
+	if m.list.Index() != 2 {
+		t.Errorf("clicked item 2, selected %d", m.list.Index())
+	}
+
+	m.screen = ScreenSuccess
+	m.selectedProvider = &providers.Definition{Name: "ollama", DisplayName: "Ollama"}
+	m.View()
+	click(zoneLaunch)
+	if m.GetResultAction() != "launch" || cfg.DefaultProvider != "ollama" {
+		t.Errorf("launch button: action %q, default %q, want launch with ollama", m.GetResultAction(), cfg.DefaultProvider)
+	}
+
+	m = NewModel(cfg, nil)
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	m.screen = ScreenCustomProvider
+	m.inputFocus = 4
+	m.fetchedModels = []models.ModelInfo{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+	m.modelPickerOpen = true
+	m.View()
+	for _, z := range m.zones {
+		if z.id == zonePicker {
+			m.Update(tea.MouseMsg{X: z.x0, Y: z.y, Action: tea.MouseActionPress, Button: tea.MouseButtonWheelDown})
+			break
+		}
+	}
+	if m.modelPickerIdx != 1 {
+		t.Errorf("wheel over the picker: selection %d, want 1", m.modelPickerIdx)
+	}
+}
+
+func TestNumberKeySelect(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Providers = []*config.Provider{
+		{Name: "ollama", Type: config.ProviderTypeLocal, DisplayName: "Ollama", BaseURL: "http://localhost:11434"},
+	}
+	m := NewModel(cfg, nil)
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+
+	items := m.list.VisibleItems()
+	var want int
+	for i, it := range items {
+		if it.(ProviderItem).definition != nil && it.(ProviderItem).definition.Name == "ollama" {
+			want = i
+		}
+	}
+	number := string(rune('1' + want))
+	if view := m.View(); !strings.Contains(view, number+" ✓ Ollama") {
+		t.Errorf("ollama should be numbered %d:\n%s", want+1, view)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(number)})
+	if m.list.Index() != want || m.screen != ScreenSuccess || cfg.DefaultProvider != "ollama" {
+		t.Errorf("pressing %s: index %d, screen %v, default %q; want ollama chosen", number, m.list.Index(), m.screen, cfg.DefaultProvider)
+	}
+
+	// Numbers past the end of the list do nothing
+	m = NewModel(cfg, nil)
+	before := m.list.Index()
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("9")})
+	if len(m.list.VisibleItems()) < 9 && (m.screen != ScreenMain || m.list.Index() != before) {
+		t.Error("9 should do nothing with fewer than nine providers")
+	}
+}
+
+func TestProviderYAML(t *testing.T) {
+	var copied string
+	defer func(orig func(string) error) { ui.WriteClipboard = orig }(ui.WriteClipboard)
+	ui.WriteClipboard = func(text string) error {
+		copied = text
+		return nil
+	}
+
+	cfg := config.NewDefaultConfig()
+	cfg.Providers = []*config.Provider{
+		{Name: "ollama", Type: config.ProviderTypeLocal, DisplayName: "Ollama", BaseURL: "http://localhost:11434", AuthToken: "tok-secret"},
+	}
+	m := NewModel(cfg, nil)
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	for i, it := range m.list.VisibleItems() {
+		if item := it.(ProviderItem); !item.isAddNew && item.definition.Name == "ollama" {
+			m.list.Select(i)
+		}
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	if m.screen != ScreenYAML {
+		t.Fatalf("y should open the YAML screen, got %v", m.screen)
+	}
+	view := m.View()
+	if !strings.Contains(view, "base_url: http://localhost:11434") || !strings.Contains(view, "auth_token: "+config.Redacted) {
+		t.Errorf("YAML screen should show the provider with its token redacted:\n%s", view)
+	}
+	if strings.Contains(view, "tok-secret") {
+		t.Error("YAML screen must not show the auth token")
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if cmd == nil {
+		t.Fatal("c should copy the YAML")
+	}
+	m.Update(cmd())
+	if !strings.Contains(copied, "name: ollama") || strings.Contains(copied, "tok-secret") {
+		t.Errorf("copied %q, want the redacted YAML", copied)
+	}
+	if !strings.Contains(m.View(), "Copied to the clipboard") {
+		t.Error("copying should be confirmed")
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.screen != ScreenMain {
+		t.Errorf("esc should return to the list, got %v", m.screen)
+	}
+}
+
+func TestSuccessScreenActions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer srv.Close()
+	binDir := t.TempDir()
+	t.Setenv("SKINT_BIN", binDir)
+
+	cfg := config.NewDefaultConfig()
+	cfg.Providers = []*config.Provider{
+		{Name: "ollama", Type: config.ProviderTypeLocal, DisplayName: "Ollama", BaseURL: srv.URL},
+	}
+	m := NewModel(cfg, nil)
+	m.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	m.screen = ScreenSuccess
+	m.selectedProvider = &providers.Definition{Name: "ollama", DisplayName: "Ollama"}
+	press := func(key tea.KeyType) tea.Cmd {
+		t.Helper()
+		_, cmd := m.Update(tea.KeyMsg{Type: key})
+		return cmd
+	}
+
+	press(tea.KeyLeft)
+	if m.successOption != successScript {
+		t.Fatalf("left from Continue should wrap to the last button, got %d", m.successOption)
+	}
+	press(tea.KeyLeft)
+	cmd := press(tea.KeyEnter)
+	if cmd == nil || !strings.Contains(m.View(), "Testing Ollama...") {
+		t.Fatal("Test now should start a test")
+	}
+	m.Update(cmd())
+	if view := m.View(); !strings.Contains(view, "✓ Reachable: HTTP 200") {
+		t.Errorf("test result missing:\n%s", view)
+	}
+
+	press(tea.KeyRight)
+	cmd = press(tea.KeyEnter)
+	if cmd == nil {
+		t.Fatal("Generate wrapper script should write the script")
+	}
+	m.Update(cmd())
+	if _, err := os.Stat(filepath.Join(binDir, "skint-ollama")); err != nil {
+		t.Errorf("script not written: %v", err)
+	}
+	if view := m.View(); !strings.Contains(view, "Wrote "+filepath.Join(binDir, "skint-ollama")) {
+		t.Errorf("script result missing:\n%s", view)
+	}
+	if m.screen != ScreenSuccess {
+		t.Error("the actions should stay on the success screen")
+	}
+
+	press(tea.KeyEsc)
+	if m.screen != ScreenMain || m.successResult("ollama") != "" {
+		t.Error("leaving the success screen should clear the results")
+	}
+}
+
+func TestBanner(t *testing.T) {
+	logo := strings.Split(ui.Banner, "\n")[4]
+	tests := []struct {
+		name     string
+		height   int
+		noBanner bool
+		want     bool
+	}{
+		{"tall terminal", 40, false, true},
+		{"turned off", 40, true, false},
+		{"short terminal", 26, false, false},
+		{"compact", 20, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewDefaultConfig()
+			cfg.NoBanner = tt.noBanner
+			m := NewModel(cfg, nil)
+			m.Update(tea.WindowSizeMsg{Width: 100, Height: tt.height})
+			view := m.View()
+			if got := strings.Contains(view, logo); got != tt.want {
+				t.Errorf("banner shown = %v, want %v", got, tt.want)
+			}
+			if lines := strings.Count(view, "\n") + 1; tt.height >= 24 && lines > tt.height {
+				t.Errorf("view is %d lines, taller than the %d-line terminal", lines, tt.height)
+			}
+		})
+	}
+}
+
+// TestBannerResizesList checks hiding the banner on the settings screen, and
+// undoing that, resize the provider list without waiting for a redraw.
+func TestBannerResizesList(t *testing.T) {
+	m := NewModel(config.NewDefaultConfig(), nil)
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	withBanner := m.list.Height()
+
+	m.settingsIdx = slices.IndexFunc(settings, func(s setting) bool { return s.label == "Hide the startup banner" })
+	m.changeSetting(1)
+	if got, want := m.list.Height(), withBanner+bannerHeight; got != want {
+		t.Errorf("list height with the banner hidden = %d, want %d", got, want)
+	}
+	m.undo()
+	if got := m.list.Height(); got != withBanner {
+		t.Errorf("list height after undo = %d, want %d", got, withBanner)
+	}
+}
+
+func TestFilterProviders(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Providers = []*config.Provider{
+		{Name: "ollama", Type: config.ProviderTypeLocal, DisplayName: "Ollama", BaseURL: "http://localhost:11434"},
+		{Name: "lmstudio", Type: config.ProviderTypeLocal, DisplayName: "LM Studio", BaseURL: "http://localhost:1234"},
+	}
+	m := NewModel(cfg, nil)
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	all := len(m.list.VisibleItems())
+
+	// The list filters in the background; run its commands as the program would
+	var run func(cmd tea.Cmd)
+	run = func(cmd tea.Cmd) {
+		if cmd == nil {
+			return
+		}
+		switch msg := cmd().(type) {
+		case tea.BatchMsg:
+			for _, c := range msg {
+				run(c)
+			}
+		case list.FilterMatchesMsg:
+			_, cmd := m.Update(msg)
+			run(cmd)
+		}
+	}
+	press := func(k tea.KeyMsg) {
+		_, cmd := m.Update(k)
+		run(cmd)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	if !m.list.SettingFilter() {
+		t.Fatal("/ should start filtering")
+	}
+	// Shortcut keys are typed into the filter rather than acted on
+	for _, r := range "ollqu" {
+		press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if m.done || m.list.FilterValue() != "ollqu" {
+		t.Fatalf("typing a filter: done %v, filter %q", m.done, m.list.FilterValue())
+	}
+	for range 2 {
+		press(tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	if view := m.View(); !strings.Contains(view, "/ oll") {
+		t.Errorf("the filter being typed should be shown:\n%s", view)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.list.IsFiltered() || m.screen != ScreenMain {
+		t.Fatalf("enter should keep the filter: state %v, screen %v", m.list.FilterState(), m.screen)
+	}
+	items := m.list.VisibleItems()
+	if len(items) != 1 || items[0].(ProviderItem).definition.Name != "ollama" {
+		t.Fatalf("filtered to %d of %d items, want only ollama", len(items), all)
+	}
+
+	// Testing a provider refilters the list with its updated item
+	run(m.setItemTest(providerTest{name: "ollama", done: true, reachable: true}))
+	if items := m.list.VisibleItems(); len(items) != 1 || items[0].(ProviderItem).test == nil {
+		t.Errorf("after a test the filtered list should hold only the tested ollama, got %v", items)
+	}
+
+	// Esc clears the filter rather than quitting
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.done || m.list.IsFiltered() || len(m.list.VisibleItems()) != all {
+		t.Fatalf("esc: done %v, filtered %v, %d items", m.done, m.list.IsFiltered(), len(m.list.VisibleItems()))
+	}
+
+	// Number keys choose from the filtered list
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	for _, r := range "lmst" {
+		press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("1")})
+	if m.screen != ScreenSuccess || cfg.DefaultProvider != "lmstudio" {
+		t.Errorf("1 on the filtered list: screen %v, default %q; want lmstudio chosen", m.screen, cfg.DefaultProvider)
+	}
+
+	// Selecting a provider the filter hides clears it
+	if !m.selectProvider("ollama") || m.list.IsFiltered() {
+		t.Errorf("selectProvider should clear a filter hiding the provider")
+	}
+}
+
+func TestSwitcher(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Providers = []*config.Provider{
+		{Name: "ollama", Type: config.ProviderTypeLocal, DisplayName: "Ollama", BaseURL: "http://localhost:11434"},
+		{Name: "zai", Type: config.ProviderTypeBuiltin, DisplayName: "Z.AI", Model: "glm-4.7"},
+		{Name: "lmstudio", Type: config.ProviderTypeLocal, DisplayName: "LM Studio", BaseURL: "http://localhost:1234"},
+		{Name: "kimi", Type: config.ProviderTypeBuiltin, DisplayName: "Kimi"},
+	}
+	cfg.ProviderOrder = []string{"kimi"}
+	usage := config.Usage{"lmstudio": time.Now().Add(-time.Hour), "zai": time.Now().Add(-time.Minute)}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+	names := func(s *Switcher) []string {
+		var out []string
+		for _, m := range s.matches {
+			out = append(out, m.name)
+		}
+		return out
+	}
+
+	// provider_order first, then the most recently used, then native last
+	s := NewSwitcher(cfg, usage, "")
+	if got, want := names(s), []string{"kimi", "zai", "lmstudio", "ollama", "native"}; !slices.Equal(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+
+	// Typing filters fuzzily, on the display name too; enter picks
+	s.Update(runes("lms"))
+	if got := names(s); !slices.Equal(got, []string{"lmstudio"}) {
+		t.Errorf("filtered = %v", got)
+	}
+	s.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	s.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	s.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	s.Update(runes("z.a"))
+	if got := names(s); len(got) == 0 || got[0] != "zai" {
+		t.Errorf("display name match = %v", got)
+	}
+	if _, cmd := s.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd == nil || s.Picked() != "zai" {
+		t.Errorf("picked %q, want zai and quit", s.Picked())
+	}
+	if s.View() != "" {
+		t.Error("the switcher should clear its view when done")
+	}
+
+	// A query pre-fills the filter; the cursor moves; esc cancels
+	s = NewSwitcher(cfg, usage, "o")
+	s.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if s.cursor != 1 || !strings.Contains(s.View(), "▸ ") {
+		t.Errorf("cursor = %d", s.cursor)
+	}
+	s.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if s.Picked() != "" {
+		t.Errorf("cancelled switcher picked %q", s.Picked())
+	}
+}
@@ -0,0 +1,108 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sammcj/skint/internal/config"
+)
+
+// maxUndo is how many changes can be undone.
+const maxUndo = 50
+
+// undoEntry is the config as it was before a change, and the stored API
+// keys the change replaced or removed.
+type undoEntry struct {
+	label string
+	cfg   *config.Config
+	keys  []savedKey
+}
+
+// savedKey is a stored API key as it was before a change: its value, or
+// that there was none.
+type savedKey struct {
+	name    string
+	value   string
+	existed bool
+}
+
+// keysRestoredMsg is sent when the keys of an undone change are restored.
+type keysRestoredMsg struct {
+	err error
+}
+
+// pushUndo records the config before a change, described by label (e.g.
+// "delete Z.AI"), so it can be undone. Call it before changing m.cfg. The
+// config is only saved when the TUI exits, so undoing needs no save.
+func (m *Model) pushUndo(label string) {
+	entry := undoEntry{label: label, cfg: m.cfg.Clone(), keys: m.replacedKeys}
+	m.replacedKeys = nil
+	m.undoStack = append(m.undoStack, entry)
+	if len(m.undoStack) > maxUndo {
+		m.undoStack = m.undoStack[len(m.undoStack)-maxUndo:]
+	}
+}
+
+// setDefaultProvider makes name the default provider.
+func (m *Model) setDefaultProvider(name string) {
+	if m.cfg.DefaultProvider == name {
+		return
+	}
+	m.pushUndo("set the active provider to " + name)
+	m.cfg.DefaultProvider = name
+}
+
+// rememberKey records a stored key a change is about to replace or remove,
+// for the most recent undo entry.
+func (m *Model) rememberKey(key savedKey) {
+	if n := len(m.undoStack); n > 0 {
+		m.undoStack[n-1].keys = append(m.undoStack[n-1].keys, key)
+	}
+}
+
+// undo reverts the last change, restoring its stored keys in the background.
+func (m *Model) undo() (tea.Model, tea.Cmd) {
+	n := len(m.undoStack)
+	if n == 0 {
+		m.listStatus = "Nothing to undo"
+		return m, nil
+	}
+	entry := m.undoStack[n-1]
+	m.undoStack = m.undoStack[:n-1]
+
+	*m.cfg = *entry.cfg
+	m.refreshProviderList()
+	m.sizeList() // the banner may be back
+	m.listStatus = "Undone: " + entry.label
+
+	if len(entry.keys) == 0 || m.secretsMgr == nil {
+		return m, nil
+	}
+	secretsMgr := m.secretsMgr
+	return m, func() tea.Msg {
+		var errs []error
+		// Newest first, so a key changed twice ends up as it started
+		for i := len(entry.keys) - 1; i >= 0; i-- {
+			key := entry.keys[i]
+			var err error
+			if key.existed {
+				err = secretsMgr.Store(key.name, key.value)
+			} else {
+				err = secretsMgr.Delete(key.name)
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", key.name, err))
+			}
+		}
+		return keysRestoredMsg{err: errors.Join(errs...)}
+	}
+}
+
+// keysRestored reports keys an undo couldn't restore.
+func (m *Model) keysRestored(msg keysRestoredMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.listStatus = fmt.Sprintf("Undone, but failed to restore API keys: %v", msg.err)
+	}
+	return m, nil
+}
@@ -2,14 +2,17 @@ package tui
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/detect"
 	"github.com/sammcj/skint/internal/providers"
 )
 
 func (m *Model) updateMainScreen(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.listStatus = ""
 	switch msg.Type {
 	case tea.KeyRunes:
 		switch msg.String() {
@@ -20,9 +23,11 @@ func (m *Model) updateMainScreen(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		case "t":
 			if !m.list.SettingFilter() {
-				m.resultAction = "test"
-				m.done = true
-				return m, tea.Quit
+				return m.startProviderTests()
+			}
+		case "T":
+			if !m.list.SettingFilter() {
+				return m.testSelectedItem()
 			}
 		case "u":
 			if !m.list.SettingFilter() {
@@ -32,18 +37,7 @@ func (m *Model) updateMainScreen(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		case "o":
 			if !m.list.SettingFilter() {
-				if def, ok := m.registry.Get("openrouter"); ok {
-					p := m.cfg.GetProvider(def.Name)
-					configured := p != nil && p.IsConfigured()
-					item := ProviderItem{
-						definition: def,
-						configured: configured,
-						active:     m.cfg.DefaultProvider == def.Name,
-						category:   "International",
-					}
-					m.selectedProvider = def
-					return m.handleProviderSelect(item)
-				}
+				return m.openOpenRouter()
 			}
 		case "c", "a":
 			if !m.list.SettingFilter() {
@@ -58,8 +52,68 @@ func (m *Model) updateMainScreen(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					return m.handleProviderEdit(item)
 				}
 			}
+		case "d":
+			if !m.list.SettingFilter() {
+				if item, ok := m.list.SelectedItem().(ProviderItem); ok && !item.isAddNew {
+					return m.confirmDeleteProvider(item)
+				}
+			}
+		case "l":
+			if !m.list.SettingFilter() {
+				if server, ok := m.unconfiguredServer(); ok {
+					return m.setupDetectedServer(server)
+				}
+			}
+		case "m":
+			if !m.list.SettingFilter() {
+				if item, ok := m.list.SelectedItem().(ProviderItem); ok && !item.isAddNew && item.configured {
+					return m.openMappings(item)
+				}
+			}
+		case "s":
+			if !m.list.SettingFilter() {
+				m.screen = ScreenSettings
+				m.settingsIdx = 0
+				return m, nil
+			}
+		case "i":
+			if !m.list.SettingFilter() {
+				if item, ok := m.list.SelectedItem().(ProviderItem); ok && !item.isAddNew {
+					return m.openDetails(item)
+				}
+			}
+		case "y":
+			if !m.list.SettingFilter() {
+				if item, ok := m.list.SelectedItem().(ProviderItem); ok && !item.isAddNew && m.cfg.GetProvider(item.definition.Name) != nil {
+					return m.openYAML(item.definition.Name)
+				}
+			}
+		case "A":
+			if !m.list.SettingFilter() {
+				if item, ok := m.list.SelectedItem().(ProviderItem); ok && !item.isAddNew && m.cfg.GetProvider(item.definition.Name) != nil {
+					return m.openClaudeArgs(item.definition.Name)
+				}
+			}
+		case "?":
+			if !m.list.SettingFilter() {
+				return m.openHelp()
+			}
+		case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+			// Choose one of the numbered providers, like the old menu
+			idx := int(msg.Runes[0] - '1')
+			if !m.list.SettingFilter() && idx < len(m.list.VisibleItems()) {
+				if item, ok := m.list.VisibleItems()[idx].(ProviderItem); ok && !item.isAddNew {
+					m.list.Select(idx)
+					return m.updateMainScreen(tea.KeyMsg{Type: tea.KeyEnter})
+				}
+			}
 		}
 	case tea.KeyEsc:
+		if m.list.IsFiltered() {
+			m.list.ResetFilter()
+			m.sizeList()
+			return m, nil
+		}
 		if !m.list.SettingFilter() {
 			m.done = true
 			return m, tea.Quit
@@ -67,7 +121,20 @@ func (m *Model) updateMainScreen(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case tea.KeyCtrlC:
 		m.done = true
 		return m, tea.Quit
+	case tea.KeyCtrlZ:
+		if !m.list.SettingFilter() {
+			return m.undo()
+		}
+	case tea.KeyDelete:
+		if !m.list.SettingFilter() {
+			if item, ok := m.list.SelectedItem().(ProviderItem); ok && !item.isAddNew {
+				return m.confirmDeleteProvider(item)
+			}
+		}
 	case tea.KeyEnter:
+		if m.list.SettingFilter() {
+			break // the list applies the filter
+		}
 		if item, ok := m.list.SelectedItem().(ProviderItem); ok {
 			if item.isAddNew {
 				m.screen = ScreenCustomProvider
@@ -82,6 +149,7 @@ func (m *Model) updateMainScreen(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	var cmd tea.Cmd
 	m.list, cmd = m.list.Update(msg)
+	m.sizeList() // the filter line may have come or gone
 	return m, cmd
 }
 
@@ -95,21 +163,21 @@ func (m *Model) handleProviderSelect(item ProviderItem) (tea.Model, tea.Cmd) {
 	// If already configured, set as active and show confirmation
 	if isConfigured {
 		m.selectedProvider = def
-		m.cfg.DefaultProvider = def.Name
+		m.setDefaultProvider(def.Name)
 		m.message = fmt.Sprintf("✓ %s is now the active provider", def.DisplayName)
 		m.messageType = "success"
 		m.screen = ScreenSuccess
-		m.successOption = 0
+		m.successOption = successContinue
 		return m, nil
 	}
 
 	// Native provider needs no configuration -- just set as active
 	if def.Name == "native" {
-		m.cfg.DefaultProvider = def.Name
+		m.setDefaultProvider(def.Name)
 		m.message = fmt.Sprintf("✓ %s is now the active provider", def.DisplayName)
 		m.messageType = "success"
 		m.screen = ScreenSuccess
-		m.successOption = 0
+		m.successOption = successContinue
 		return m, nil
 	}
 
@@ -123,9 +191,9 @@ func (m *Model) handleProviderSelect(item ProviderItem) (tea.Model, tea.Cmd) {
 
 	// Built-in/OpenRouter providers need API key (and optionally model)
 	m.screen = ScreenAPIKeyInput
-	m.apiKeyInput = ""
+	m.apiKeyInput.Reset()
 	m.hasExistingKey = false
-	m.modelInput = def.DefaultModel
+	setInput(&m.modelInput, def.DefaultModel)
 	m.inputError = ""
 	m.inputFocus = 0
 	m.resetModelPicker()
@@ -157,33 +225,33 @@ func (m *Model) handleProviderEdit(item ProviderItem) (tea.Model, tea.Cmd) {
 	switch def.Type {
 	case config.ProviderTypeLocal:
 		// Local providers - show config form with existing values
-		m.localProviderURL = p.BaseURL
-		m.localProviderAuthToken = p.AuthToken
-		m.localProviderModel = p.EffectiveModel()
+		setInput(&m.localProviderURL, p.BaseURL)
+		setInput(&m.localProviderAuthToken, p.AuthToken)
+		setInput(&m.localProviderModel, p.EffectiveModel())
 		m.inputFocus = 0
 		m.inputError = ""
 		m.screen = ScreenProviderConfig
 	case config.ProviderTypeCustom:
 		// Custom providers - open custom provider form with existing values
-		m.customProviderName = p.Name
-		m.customProviderDisplay = p.DisplayName
-		m.customProviderURL = p.BaseURL
-		m.customProviderModel = p.Model
+		setInput(&m.customProviderName, p.Name)
+		setInput(&m.customProviderDisplay, p.DisplayName)
+		setInput(&m.customProviderURL, p.BaseURL)
+		setInput(&m.customProviderModel, p.Model)
 		m.customProviderAPIType = p.APIType
 		if m.customProviderAPIType == "" {
 			m.customProviderAPIType = config.APITypeAnthropic
 		}
 		// Don't show API key (it's masked), but allow editing
-		m.apiKeyInput = ""
+		m.apiKeyInput.Reset()
 		m.inputFocus = 0
 		m.inputError = ""
 		m.screen = ScreenCustomProvider
 	default:
 		// Built-in/OpenRouter providers - open API key + model input
 		m.screen = ScreenAPIKeyInput
-		m.apiKeyInput = ""
+		m.apiKeyInput.Reset()
 		m.hasExistingKey = p.IsConfigured()
-		m.modelInput = p.EffectiveModel()
+		setInput(&m.modelInput, p.EffectiveModel())
 		m.inputError = ""
 		m.inputFocus = 0
 	}
@@ -191,32 +259,81 @@ func (m *Model) handleProviderEdit(item ProviderItem) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// OpenProvider opens the TUI on a provider's config form, for 'skint config
+// <name>': the edit form when it is configured, or the setup form. "custom"
+// opens the custom provider form, "openrouter" the OpenRouter screen, and an
+// or-* provider its form on that screen. It returns false, leaving the
+// provider list open, for an unknown name.
+func (m *Model) OpenProvider(name string) bool {
+	switch name {
+	case "custom":
+		m.screen = ScreenCustomProvider
+		m.inputFocus = 0
+		m.resetCustomProviderForm()
+		return true
+	case "openrouter":
+		m.openOpenRouter()
+		return true
+	}
+	if p := m.cfg.GetProvider(name); p != nil && p.Type == config.ProviderTypeOpenRouter && strings.HasPrefix(name, config.OpenRouterPrefix) {
+		m.openOpenRouter()
+		m.orIdx = slices.Index(m.openRouterProviders(), p)
+		_, m.startCmd = m.editOpenRouterModel(p, 0)
+		return true
+	}
+	if !m.selectProvider(name) {
+		return false
+	}
+	item, _ := m.list.SelectedItem().(ProviderItem)
+	_, m.startCmd = m.handleProviderEdit(item)
+	return true
+}
+
+// setupDetectedServer opens the local provider form for a detected server,
+// pre-filled with the URL it was found on.
+func (m *Model) setupDetectedServer(server detect.Server) (tea.Model, tea.Cmd) {
+	def, ok := m.registry.Get(server.Provider)
+	if !ok {
+		return m, nil
+	}
+	m.selectedProvider = def
+	m.initLocalProviderForm(def)
+	setInput(&m.localProviderURL, server.BaseURL)
+	m.screen = ScreenProviderConfig
+	m.resetModelPicker()
+	return m, nil
+}
+
 func (m *Model) initLocalProviderForm(def *providers.Definition) {
 	// Pre-populate from existing config if available, otherwise use definition defaults
 	p := m.cfg.GetProvider(def.Name)
 	if p != nil {
-		m.localProviderURL = p.BaseURL
-		m.localProviderAuthToken = p.AuthToken
-		m.localProviderModel = p.EffectiveModel()
+		setInput(&m.localProviderURL, p.BaseURL)
+		setInput(&m.localProviderAuthToken, p.AuthToken)
+		setInput(&m.localProviderModel, p.EffectiveModel())
 	} else {
-		m.localProviderURL = def.BaseURL
-		m.localProviderAuthToken = def.AuthToken
-		m.localProviderModel = def.DefaultModel
+		setInput(&m.localProviderURL, def.BaseURL)
+		setInput(&m.localProviderAuthToken, def.AuthToken)
+		setInput(&m.localProviderModel, def.DefaultModel)
 	}
 	m.inputFocus = 0
 	m.inputError = ""
 }
 
 func (m *Model) updateProviderConfig(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.Type == tea.KeyCtrlP && m.modelNotPulled() {
+		return m, m.startPull()
+	}
 	// Model picker intercepts input when open
-	if m.updateModelPicker(msg) {
-		return m, nil
+	if consumed, cmd := m.updateModelPicker(msg); consumed {
+		return m, cmd
 	}
 
 	switch msg.Type {
 	case tea.KeyEsc:
 		m.screen = ScreenMain
 		m.resetModelPicker()
+		m.stopPull()
 		return m, nil
 	case tea.KeyCtrlC:
 		m.done = true
@@ -226,61 +343,34 @@ func (m *Model) updateProviderConfig(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, m.triggerModelFetch()
 		}
 	case tea.KeyTab, tea.KeyDown:
+		if !m.leaveField() {
+			return m, nil
+		}
 		m.inputFocus = (m.inputFocus + 1) % localFormFieldCount
 		return m, m.fetchOnModelFocus()
 	case tea.KeyShiftTab, tea.KeyUp:
+		if !m.leaveField() {
+			return m, nil
+		}
 		m.inputFocus = (m.inputFocus + localFormFieldCount - 1) % localFormFieldCount
 		return m, m.fetchOnModelFocus()
 	case tea.KeyEnter:
 		// Validate and submit
-		if m.localProviderURL == "" {
+		if m.localProviderURL.Value() == "" {
 			m.inputError = "Base URL is required"
 			m.inputFocus = 0
 			return m, nil
 		}
-		if !strings.HasPrefix(m.localProviderURL, "http://") && !strings.HasPrefix(m.localProviderURL, "https://") {
-			m.inputError = "URL must start with http:// or https://"
+		if err := validateURL(m.localProviderURL.Value()); err != "" {
+			m.inputError = err
 			m.inputFocus = 0
 			return m, nil
 		}
 		return m.submitLocalProvider()
-	case tea.KeyBackspace:
-		m.inputError = ""
-		switch m.inputFocus {
-		case 0:
-			if len(m.localProviderURL) > 0 {
-				m.localProviderURL = m.localProviderURL[:len(m.localProviderURL)-1]
-			}
-		case 1:
-			if len(m.localProviderAuthToken) > 0 {
-				m.localProviderAuthToken = m.localProviderAuthToken[:len(m.localProviderAuthToken)-1]
-			}
-		case 2:
-			if len(m.localProviderModel) > 0 {
-				m.localProviderModel = m.localProviderModel[:len(m.localProviderModel)-1]
-			}
-		}
-		return m, nil
 	}
 
-	// Handle rune input
-	if msg.Type == tea.KeyRunes && len(msg.Runes) > 0 {
-		m.inputError = ""
-		for _, r := range msg.Runes {
-			if r >= 32 && r < 127 {
-				switch m.inputFocus {
-				case 0:
-					m.localProviderURL += string(r)
-				case 1:
-					m.localProviderAuthToken += string(r)
-				case 2:
-					m.localProviderModel += string(r)
-				}
-			}
-		}
-	}
-
-	return m, nil
+	// Typing and editing go to the focused field
+	return m, m.updateFocusedInput(msg)
 }
 
 func (m *Model) submitLocalProvider() (tea.Model, tea.Cmd) {
@@ -293,36 +383,44 @@ func (m *Model) submitLocalProvider() (tea.Model, tea.Cmd) {
 		Type:        m.selectedProvider.Type,
 		DisplayName: m.selectedProvider.DisplayName,
 		Description: m.selectedProvider.Description,
-		BaseURL:     m.localProviderURL,
-		AuthToken:   m.localProviderAuthToken,
-		Model:       m.localProviderModel,
+		BaseURL:     m.localProviderURL.Value(),
+		AuthToken:   m.localProviderAuthToken.Value(),
+		Model:       m.localProviderModel.Value(),
 	}
+	return m.confirmOverwrite(provider, false, func() (tea.Model, tea.Cmd) {
+		return m.saveLocalProvider(provider)
+	})
+}
 
+// saveLocalProvider saves the provider from the local provider form.
+func (m *Model) saveLocalProvider(provider *config.Provider) (tea.Model, tea.Cmd) {
+	m.pushUndo("configure " + provider.DisplayName)
 	m.cfg.RemoveProvider(provider.Name)
 	if err := m.cfg.AddProvider(provider); err != nil {
 		m.message = err.Error()
 		m.messageType = "error"
 		m.screen = ScreenError
 	} else {
+		m.rememberModels(provider.Name, provider.Model)
 		m.message = fmt.Sprintf("✓ %s configured", m.selectedProvider.DisplayName)
 		m.messageType = "success"
 		m.screen = ScreenSuccess
-		m.successOption = 0
+		m.successOption = successContinue
 	}
 	return m, nil
 }
 
 func (m *Model) updateAPIKeyInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Model picker intercepts input when open
-	if m.updateModelPicker(msg) {
-		return m, nil
+	if consumed, cmd := m.updateModelPicker(msg); consumed {
+		return m, cmd
 	}
 
 	switch msg.Type {
 	case tea.KeyEsc:
 		m.screen = ScreenMain
-		m.apiKeyInput = ""
-		m.modelInput = ""
+		m.apiKeyInput.Reset()
+		m.modelInput.Reset()
 		m.inputError = ""
 		m.resetModelPicker()
 		return m, nil
@@ -334,122 +432,105 @@ func (m *Model) updateAPIKeyInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, m.triggerModelFetch()
 		}
 	case tea.KeyTab, tea.KeyDown:
+		if !m.leaveField() {
+			return m, nil
+		}
 		m.inputFocus = (m.inputFocus + 1) % apiKeyFormFieldCount
 		return m, m.fetchOnModelFocus()
 	case tea.KeyShiftTab, tea.KeyUp:
+		if !m.leaveField() {
+			return m, nil
+		}
 		m.inputFocus = (m.inputFocus + apiKeyFormFieldCount - 1) % apiKeyFormFieldCount
 		return m, m.fetchOnModelFocus()
 	case tea.KeyEnter:
-		if m.apiKeyInput == "" && !m.hasExistingKey {
+		if m.apiKeyInput.Value() == "" && !m.hasExistingKey {
 			m.inputError = "API key is required"
 			m.inputFocus = 0
 			return m, nil
 		}
-		if m.apiKeyInput != "" && len(m.apiKeyInput) < 8 {
-			m.inputError = "API key too short (minimum 8 characters)"
+		if m.apiKeyInput.Value() != "" && validateAPIKey(m.apiKeyInput.Value()) != "" {
+			m.inputError = errKeyTooShort
 			m.inputFocus = 0
 			return m, nil
 		}
 		// Model is required if provider has no default model or model mappings
 		modelRequired := m.selectedProvider.DefaultModel == "" && len(m.selectedProvider.ModelMappings) == 0
-		if modelRequired && m.modelInput == "" {
+		if modelRequired && m.modelInput.Value() == "" {
 			m.inputError = "Model name is required for this provider"
 			m.inputFocus = 1
 			return m, nil
 		}
 
 		// If editing existing provider and no new key provided, just update model
-		if m.apiKeyInput == "" && m.hasExistingKey {
+		if m.apiKeyInput.Value() == "" && m.hasExistingKey {
 			existing := m.cfg.GetProvider(m.selectedProvider.Name)
-			if existing != nil && m.modelInput != "" {
-				existing.Model = m.modelInput
+			if existing != nil && m.modelInput.Value() != "" && m.modelInput.Value() != existing.Model {
+				m.pushUndo("edit " + m.selectedProvider.DisplayName)
+				existing.Model = m.modelInput.Value()
+				m.rememberModels(existing.Name, existing.Model)
 			}
 			m.message = fmt.Sprintf("✓ %s updated successfully", m.selectedProvider.DisplayName)
 			m.messageType = "success"
 			m.screen = ScreenSuccess
-			m.successOption = 0
-			m.apiKeyInput = ""
-			m.modelInput = ""
+			m.successOption = successContinue
+			m.apiKeyInput.Reset()
+			m.modelInput.Reset()
 			return m, nil
 		}
 
-		// Store API key
-		ref, err := m.secretsMgr.StoreWithReference(m.selectedProvider.Name, m.apiKeyInput)
-		if err != nil {
-			m.inputError = fmt.Sprintf("Failed to store API key: %v", err)
-			return m, nil
-		}
-
-		// Create or update provider config
-		provider := &config.Provider{
-			Name:          m.selectedProvider.Name,
-			Type:          m.selectedProvider.Type,
-			DisplayName:   m.selectedProvider.DisplayName,
-			Description:   m.selectedProvider.Description,
-			BaseURL:       m.selectedProvider.BaseURL,
-			DefaultModel:  m.selectedProvider.DefaultModel,
-			ModelMappings: m.selectedProvider.ModelMappings,
-			APIKeyRef:     ref,
-			KeyEnvVar:     m.selectedProvider.KeyEnvVar,
-			APIType:       m.selectedProvider.APIType,
-		}
+		// Store the API key in the background, then save the provider
+		return m.storeKey(m.selectedProvider.Name, m.apiKeyInput.Value(), m.saveAPIKeyProvider)
+	}
 
-		// Set model if user provided one (e.g. for OpenRouter)
-		if m.modelInput != "" {
-			provider.Model = m.modelInput
-		}
+	// Typing and editing go to the focused field
+	return m, m.updateFocusedInput(msg)
+}
 
-		m.cfg.RemoveProvider(provider.Name)
-		if err := m.cfg.AddProvider(provider); err != nil {
-			m.inputError = err.Error()
-			return m, nil
-		}
+// saveAPIKeyProvider saves the provider from the API key form, with ref
+// pointing at its stored key.
+func (m *Model) saveAPIKeyProvider(ref string) (tea.Model, tea.Cmd) {
+	// Create or update provider config
+	provider := &config.Provider{
+		Name:          m.selectedProvider.Name,
+		Type:          m.selectedProvider.Type,
+		DisplayName:   m.selectedProvider.DisplayName,
+		Description:   m.selectedProvider.Description,
+		BaseURL:       m.selectedProvider.BaseURL,
+		Model:         m.selectedProvider.DefaultModel,
+		ModelMappings: m.selectedProvider.ModelMappings,
+		APIKeyRef:     ref,
+		KeyEnvVar:     m.selectedProvider.KeyEnvVar,
+		APIType:       m.selectedProvider.APIType,
+	}
 
-		m.message = fmt.Sprintf("✓ %s configured successfully", m.selectedProvider.DisplayName)
-		m.messageType = "success"
-		m.screen = ScreenSuccess
-		m.successOption = 0
-		m.apiKeyInput = ""
-		m.modelInput = ""
-		return m, nil
-	case tea.KeyBackspace:
-		m.inputError = ""
-		switch m.inputFocus {
-		case 0:
-			if len(m.apiKeyInput) > 0 {
-				m.apiKeyInput = m.apiKeyInput[:len(m.apiKeyInput)-1]
-			}
-		case 1:
-			if len(m.modelInput) > 0 {
-				m.modelInput = m.modelInput[:len(m.modelInput)-1]
-			}
-		}
-		return m, nil
+	// The user's choice replaces the registry default
+	if m.modelInput.Value() != "" {
+		provider.Model = m.modelInput.Value()
 	}
 
-	// Handle rune input
-	if msg.Type == tea.KeyRunes && len(msg.Runes) > 0 {
-		m.inputError = ""
-		for _, r := range msg.Runes {
-			if r >= 32 && r < 127 {
-				switch m.inputFocus {
-				case 0:
-					m.apiKeyInput += string(r)
-				case 1:
-					m.modelInput += string(r)
-				}
-			}
-		}
+	m.pushUndo("configure " + m.selectedProvider.DisplayName)
+	m.cfg.RemoveProvider(provider.Name)
+	if err := m.cfg.AddProvider(provider); err != nil {
+		m.inputError = err.Error()
+		return m, nil
 	}
+	m.rememberModels(provider.Name, m.modelInput.Value())
 
+	m.message = fmt.Sprintf("✓ %s configured successfully", m.selectedProvider.DisplayName)
+	m.messageType = "success"
+	m.screen = ScreenSuccess
+	m.successOption = successContinue
+	m.apiKeyInput.Reset()
+	m.modelInput.Reset()
 	return m, nil
 }
 
 // updateCustomProvider handles input for the custom provider form
 func (m *Model) updateCustomProvider(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Model picker intercepts input when open
-	if m.updateModelPicker(msg) {
-		return m, nil
+	if consumed, cmd := m.updateModelPicker(msg); consumed {
+		return m, cmd
 	}
 
 	switch msg.Type {
@@ -466,10 +547,16 @@ func (m *Model) updateCustomProvider(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, m.triggerModelFetch()
 		}
 	case tea.KeyTab, tea.KeyDown:
+		if !m.leaveField() {
+			return m, nil
+		}
 		// Cycle through form fields
 		m.inputFocus = (m.inputFocus + 1) % customFormFieldCount
 		return m, m.fetchOnModelFocus()
 	case tea.KeyShiftTab, tea.KeyUp:
+		if !m.leaveField() {
+			return m, nil
+		}
 		// Cycle backwards
 		m.inputFocus = (m.inputFocus + customFormFieldCount - 1) % customFormFieldCount
 		return m, m.fetchOnModelFocus()
@@ -484,93 +571,49 @@ func (m *Model) updateCustomProvider(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		// Try to submit if all fields filled
-		if m.customProviderName != "" && m.customProviderURL != "" && m.customProviderModel != "" {
+		if m.customProviderName.Value() != "" && m.customProviderURL.Value() != "" && m.customProviderModel.Value() != "" {
 			return m.submitCustomProvider()
 		}
-		m.inputFocus = (m.inputFocus + 1) % customFormFieldCount
-		return m, nil
-	case tea.KeyBackspace:
-		m.inputError = ""
-		switch m.inputFocus {
-		case 0:
-			if len(m.customProviderName) > 0 {
-				m.customProviderName = m.customProviderName[:len(m.customProviderName)-1]
-			}
-		case 1:
-			if len(m.customProviderDisplay) > 0 {
-				m.customProviderDisplay = m.customProviderDisplay[:len(m.customProviderDisplay)-1]
-			}
-		case 2:
-			if len(m.customProviderURL) > 0 {
-				m.customProviderURL = m.customProviderURL[:len(m.customProviderURL)-1]
-			}
-		case 3:
-			if len(m.apiKeyInput) > 0 {
-				m.apiKeyInput = m.apiKeyInput[:len(m.apiKeyInput)-1]
-			}
-		case 4:
-			if len(m.customProviderModel) > 0 {
-				m.customProviderModel = m.customProviderModel[:len(m.customProviderModel)-1]
-			}
+		if !m.leaveField() {
+			return m, nil
 		}
+		m.inputFocus = (m.inputFocus + 1) % customFormFieldCount
 		return m, nil
 	}
 
-	// Handle rune input
-	if msg.Type == tea.KeyRunes && len(msg.Runes) > 0 {
-		m.inputError = ""
-		for _, r := range msg.Runes {
-			if r >= 32 && r < 127 {
-				switch m.inputFocus {
-				case 0:
-					m.customProviderName += string(r)
-				case 1:
-					m.customProviderDisplay += string(r)
-				case 2:
-					m.customProviderURL += string(r)
-				case 3:
-					m.apiKeyInput += string(r)
-				case 4:
-					m.customProviderModel += string(r)
-				}
-			}
-		}
-	}
-
-	return m, nil
+	// Typing and editing go to the focused field
+	return m, m.updateFocusedInput(msg)
 }
 
 func (m *Model) submitCustomProvider() (tea.Model, tea.Cmd) {
 	// Validate inputs
-	if m.customProviderName == "" {
+	if m.customProviderName.Value() == "" {
 		m.inputError = "Provider name is required"
 		m.inputFocus = 0
 		return m, nil
 	}
 
 	// Validate name format (lowercase, alphanumeric, hyphens only)
-	for _, r := range m.customProviderName {
-		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' && r != '_' {
-			m.inputError = "Name must be lowercase alphanumeric with hyphens/underscores only"
-			m.inputFocus = 0
-			return m, nil
-		}
+	if err := validateName(m.customProviderName.Value()); err != "" {
+		m.inputError = err
+		m.inputFocus = 0
+		return m, nil
 	}
 
-	if m.customProviderURL == "" {
+	if m.customProviderURL.Value() == "" {
 		m.inputError = "Base URL is required"
 		m.inputFocus = 2
 		return m, nil
 	}
 
 	// Validate URL format
-	if !strings.HasPrefix(m.customProviderURL, "http://") && !strings.HasPrefix(m.customProviderURL, "https://") {
-		m.inputError = "URL must start with http:// or https://"
+	if err := validateURL(m.customProviderURL.Value()); err != "" {
+		m.inputError = err
 		m.inputFocus = 2
 		return m, nil
 	}
 
-	if m.customProviderModel == "" {
+	if m.customProviderModel.Value() == "" {
 		m.inputError = "Model name is required"
 		m.inputFocus = 4
 		return m, nil
@@ -582,35 +625,49 @@ func (m *Model) submitCustomProvider() (tea.Model, tea.Cmd) {
 	}
 
 	// Set default display name if not provided
-	displayName := m.customProviderDisplay
+	displayName := m.customProviderDisplay.Value()
 	if displayName == "" {
-		displayName = m.customProviderName
+		displayName = m.customProviderName.Value()
 	}
 
-	// Store API key if provided
-	var apiKeyRef string
-	if m.apiKeyInput != "" {
-		ref, err := m.secretsMgr.StoreWithReference(m.customProviderName, m.apiKeyInput)
-		if err != nil {
-			m.inputError = fmt.Sprintf("Failed to store API key: %v", err)
-			return m, nil
-		}
-		apiKeyRef = ref
+	// A blank key keeps the one already saved
+	key := m.apiKeyInput.Value()
+	apiKeyRef := ""
+	if existing := m.cfg.GetProvider(m.customProviderName.Value()); existing != nil && key == "" {
+		apiKeyRef = existing.APIKeyRef
 	}
 
-	// Create provider config
-	provider := &config.Provider{
-		Name:        m.customProviderName,
+	save := func(ref string) (tea.Model, tea.Cmd) {
+		return m.saveCustomProvider(m.customProvider(displayName, ref))
+	}
+	return m.confirmOverwrite(m.customProvider(displayName, apiKeyRef), key != "", func() (tea.Model, tea.Cmd) {
+		// Store API key in the background if provided
+		if key != "" {
+			return m.storeKey(m.customProviderName.Value(), key, save)
+		}
+		return save(apiKeyRef)
+	})
+}
+
+// customProvider builds the provider from the custom provider form, with
+// apiKeyRef pointing at its stored key ("" for none).
+func (m *Model) customProvider(displayName, apiKeyRef string) *config.Provider {
+	return &config.Provider{
+		Name:        m.customProviderName.Value(),
 		Type:        config.ProviderTypeCustom,
 		DisplayName: displayName,
 		Description: fmt.Sprintf("Custom %s provider", m.customProviderAPIType),
-		BaseURL:     m.customProviderURL,
-		Model:       m.customProviderModel,
+		BaseURL:     m.customProviderURL.Value(),
+		Model:       m.customProviderModel.Value(),
 		APIKeyRef:   apiKeyRef,
 		APIType:     m.customProviderAPIType,
 	}
+}
 
+// saveCustomProvider saves the provider from the custom provider form.
+func (m *Model) saveCustomProvider(provider *config.Provider) (tea.Model, tea.Cmd) {
 	// Remove existing if present
+	m.pushUndo("save " + provider.DisplayName)
 	m.cfg.RemoveProvider(provider.Name)
 
 	// Add provider
@@ -618,21 +675,31 @@ func (m *Model) submitCustomProvider() (tea.Model, tea.Cmd) {
 		m.inputError = err.Error()
 		return m, nil
 	}
+	m.rememberModels(provider.Name, provider.Model)
 
-	m.message = fmt.Sprintf("✓ Custom provider '%s' added", displayName)
+	m.message = fmt.Sprintf("✓ Custom provider '%s' added", provider.DisplayName)
 	m.messageType = "success"
 	m.screen = ScreenSuccess
-	m.successOption = 0
+	m.successOption = successContinue
 	return m, nil
 }
 
+// Buttons on the success screen, in order.
+const (
+	successContinue = iota
+	successLaunch
+	successTest
+	successScript
+	successButtons // number of buttons
+)
+
 func (m *Model) updateSuccessScreen(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Determine if we have a provider to launch with
 	providerName := ""
 	if m.selectedProvider != nil {
 		providerName = m.selectedProvider.Name
-	} else if m.customProviderName != "" {
-		providerName = m.customProviderName
+	} else if m.customProviderName.Value() != "" {
+		providerName = m.customProviderName.Value()
 	}
 	hasLaunchOption := providerName != ""
 
@@ -641,7 +708,9 @@ func (m *Model) updateSuccessScreen(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.refreshProviderList()
 		m.resetCustomProviderForm()
 		m.screen = ScreenMain
-		m.successOption = 0
+		m.successOption = successContinue
+		m.successTesting = false
+		m.successStatus, m.successErr = "", ""
 		return m, nil
 	}
 
@@ -649,18 +718,30 @@ func (m *Model) updateSuccessScreen(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case tea.KeyCtrlC:
 		m.done = true
 		return m, tea.Quit
-	case tea.KeyUp, tea.KeyDown, tea.KeyLeft, tea.KeyRight, tea.KeyTab:
+	case tea.KeyLeft, tea.KeyUp, tea.KeyShiftTab:
 		if hasLaunchOption {
-			m.successOption = 1 - m.successOption // toggle between 0 and 1
+			m.successOption = (m.successOption + successButtons - 1) % successButtons
+		}
+		return m, nil
+	case tea.KeyRight, tea.KeyDown, tea.KeyTab:
+		if hasLaunchOption {
+			m.successOption = (m.successOption + 1) % successButtons
 		}
 		return m, nil
 	case tea.KeyEnter:
-		if hasLaunchOption && m.successOption == 1 {
-			// Launch Claude with the configured provider
-			m.cfg.DefaultProvider = providerName
-			m.resultAction = "launch"
-			m.done = true
-			return m, tea.Quit
+		if hasLaunchOption {
+			switch m.successOption {
+			case successLaunch:
+				// Launch Claude with the configured provider
+				m.setDefaultProvider(providerName)
+				m.resultAction = "launch"
+				m.done = true
+				return m, tea.Quit
+			case successTest:
+				return m.testSuccessProvider(providerName)
+			case successScript:
+				return m.generateScript(providerName)
+			}
 		}
 		if m.done {
 			return m, tea.Quit
@@ -681,12 +762,12 @@ func (m *Model) updateSuccessScreen(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m *Model) resetCustomProviderForm() {
-	m.customProviderName = ""
-	m.customProviderDisplay = ""
-	m.customProviderURL = ""
-	m.customProviderModel = ""
+	m.customProviderName.Reset()
+	m.customProviderDisplay.Reset()
+	m.customProviderURL.Reset()
+	m.customProviderModel.Reset()
 	m.customProviderAPIType = config.APITypeAnthropic
-	m.apiKeyInput = ""
+	m.apiKeyInput.Reset()
 	m.inputFocus = 0
 	m.inputError = ""
 	// Clear any provider selected from an earlier flow so the success screen
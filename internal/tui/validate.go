@@ -0,0 +1,159 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/sammcj/skint/internal/config"
+)
+
+// Field validation messages. The same checks run as the user types, showing
+// the message under the field, and again on submit.
+const (
+	errNameFormat  = "Name must be lowercase alphanumeric with hyphens/underscores only"
+	errURLScheme   = "URL must start with http:// or https://"
+	errKeyTooShort = "API key too short (minimum 8 characters)"
+)
+
+// minAPIKeyLength is the shortest API key accepted.
+const minAPIKeyLength = 8
+
+// validateName checks a provider name: lowercase letters, digits, hyphens
+// and underscores.
+func validateName(name string) string {
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+			return errNameFormat
+		}
+	}
+	return ""
+}
+
+// validateURL checks that a base URL is http or https.
+func validateURL(url string) string {
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return errURLScheme
+	}
+	return ""
+}
+
+// validatePartialURL is validateURL for a URL still being typed, which may
+// stop part way through the scheme.
+func validatePartialURL(url string) string {
+	if strings.HasPrefix("http://", url) || strings.HasPrefix("https://", url) {
+		return ""
+	}
+	return validateURL(url)
+}
+
+// validateAPIKey checks that an API key isn't too short to be real.
+func validateAPIKey(key string) string {
+	if len(key) < minAPIKeyLength {
+		return errKeyTooShort
+	}
+	return ""
+}
+
+// fieldRule is how a form field is validated while typing: the message when
+// it is required and left empty ("" if optional), and a check of a non-empty
+// value.
+type fieldRule struct {
+	required string
+	validate func(string) string
+}
+
+// fieldRule returns the rule for field idx on the current form, and false
+// for fields that aren't validated.
+func (m *Model) fieldRule(idx int) (fieldRule, bool) {
+	switch m.screen {
+	case ScreenAPIKeyInput:
+		switch idx {
+		case 0:
+			rule := fieldRule{validate: validateAPIKey}
+			if !m.hasExistingKey {
+				rule.required = "API key is required"
+			}
+			return rule, true
+		case 1:
+			if m.selectedProvider != nil && m.selectedProvider.DefaultModel == "" && len(m.selectedProvider.ModelMappings) == 0 {
+				return fieldRule{required: "Model name is required for this provider"}, true
+			}
+		}
+	case ScreenProviderConfig:
+		if idx == 0 {
+			return fieldRule{required: "Base URL is required", validate: validatePartialURL}, true
+		}
+	case ScreenCustomProvider:
+		switch idx {
+		case 0:
+			return fieldRule{required: "Provider name is required", validate: validateName}, true
+		case 2:
+			return fieldRule{required: "Base URL is required", validate: validatePartialURL}, true
+		case 3:
+			return fieldRule{validate: validateAPIKey}, true
+		case 4:
+			return fieldRule{required: "Model name is required"}, true
+		}
+	case ScreenOpenRouter:
+		if !m.orEditing {
+			break
+		}
+		switch idx {
+		case 0:
+			return fieldRule{required: "Model ID is required"}, true
+		case 1:
+			return fieldRule{validate: func(name string) string {
+				return validateName(strings.TrimPrefix(strings.TrimSpace(name), config.OpenRouterPrefix))
+			}}, true
+		case 2:
+			rule := fieldRule{validate: validateAPIKey}
+			if !m.hasExistingKey {
+				rule.required = "API key is required"
+			}
+			return rule, true
+		}
+	}
+	return fieldRule{}, false
+}
+
+// fieldMessage returns the error to show under field idx: a problem with
+// what has been typed so far, or, on the focused field, that it is required
+// once leaving it empty has been refused.
+func (m *Model) fieldMessage(idx int) string {
+	rule, ok := m.fieldRule(idx)
+	in := m.inputAt(idx)
+	if !ok || in == nil {
+		return ""
+	}
+	value := in.Value()
+	if strings.TrimSpace(value) == "" {
+		if idx == m.inputFocus && rule.required != "" && m.inputError == rule.required {
+			return rule.required
+		}
+		return ""
+	}
+	if rule.validate == nil {
+		return ""
+	}
+	return rule.validate(value)
+}
+
+// leaveField reports whether focus may move off the focused field. A
+// required field can't be left empty; it says so instead.
+func (m *Model) leaveField() bool {
+	rule, ok := m.fieldRule(m.inputFocus)
+	in := m.focusedInput()
+	if !ok || in == nil || rule.required == "" || strings.TrimSpace(in.Value()) != "" {
+		return true
+	}
+	m.inputError = rule.required
+	return false
+}
+
+// formError returns the form's error for the line below the fields, leaving
+// out one already shown under the focused field.
+func (m *Model) formError() string {
+	if m.inputError == m.fieldMessage(m.inputFocus) {
+		return ""
+	}
+	return m.inputError
+}
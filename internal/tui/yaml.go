@@ -0,0 +1,115 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sammcj/skint/internal/ui"
+)
+
+// clipboardMsg is sent when the YAML has been copied: to the system
+// clipboard, or through the terminal when there is no clipboard tool.
+type clipboardMsg struct {
+	viaTerminal bool
+	err         error
+}
+
+// openYAML shows provider name's config as YAML, returning to the current
+// screen after.
+func (m *Model) openYAML(name string) (tea.Model, tea.Cmd) {
+	m.yamlReturn = m.screen
+	m.yamlProvider = name
+	m.yamlStatus, m.yamlErr = "", ""
+	m.screen = ScreenYAML
+	return m, nil
+}
+
+func (m *Model) updateYAML(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		m.done = true
+		return m, tea.Quit
+	case tea.KeyEsc, tea.KeyEnter:
+		m.screen = m.yamlReturn
+	case tea.KeyRunes:
+		switch msg.String() {
+		case "q", "y":
+			m.screen = m.yamlReturn
+		case "c":
+			m.yamlStatus, m.yamlErr = "", ""
+			data, err := m.cfg.ExportProvider(m.yamlProvider)
+			if err != nil {
+				m.yamlErr = err.Error()
+				return m, nil
+			}
+			return m, copyToClipboard(string(data))
+		case "?":
+			return m.openHelp()
+		}
+	}
+	return m, nil
+}
+
+// copyToClipboard copies text with ui.CopyToClipboard.
+func copyToClipboard(text string) tea.Cmd {
+	return func() tea.Msg {
+		viaTerminal, err := ui.CopyToClipboard(text)
+		return clipboardMsg{viaTerminal: viaTerminal, err: err}
+	}
+}
+
+// copied reports the result of copyToClipboard.
+func (m *Model) copied(msg clipboardMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.err != nil:
+		m.yamlErr = "Failed to copy: " + msg.err.Error()
+	case msg.viaTerminal:
+		m.yamlStatus = "Sent to the terminal's clipboard"
+	default:
+		m.yamlStatus = "Copied to the clipboard"
+	}
+	return m, nil
+}
+
+func (m *Model) viewYAML() string {
+	var b strings.Builder
+
+	name := m.yamlProvider
+	if p := m.cfg.GetProvider(name); p != nil && p.DisplayName != "" {
+		name = p.DisplayName
+	}
+	header := m.styles.HeaderLine.Render("Skint") +
+		m.styles.HeaderSep.Render(" › ") +
+		m.styles.Subtitle.UnsetMarginBottom().Render(name) +
+		m.styles.HeaderSep.Render(" › ") +
+		m.styles.Title.UnsetMarginBottom().UnsetBorderStyle().UnsetPadding().Render("YAML")
+	b.WriteString(header)
+	b.WriteString("\n\n")
+
+	data, err := m.cfg.ExportProvider(m.yamlProvider)
+	if err != nil {
+		b.WriteString(m.styles.Error.Render(err.Error()))
+		b.WriteString("\n")
+	} else {
+		for line := range strings.Lines(string(data)) {
+			b.WriteString("  " + m.styles.Normal.Render(strings.TrimSuffix(line, "\n")))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+		b.WriteString(m.styles.Dimmed.Render("API keys and key references are left out; auth tokens and MCP server env and header values are shown as REDACTED."))
+		b.WriteString("\n")
+	}
+
+	if m.yamlStatus != "" {
+		b.WriteString("\n" + m.styles.Success.Render("✓ "+m.yamlStatus) + "\n")
+	}
+	if m.yamlErr != "" {
+		b.WriteString("\n" + m.styles.Error.Render(m.yamlErr) + "\n")
+	}
+	b.WriteString("\n")
+
+	help := m.styles.Help.Render("c copy  ? help  esc back")
+	b.WriteString(m.styles.Footer.Render(help))
+
+	return b.String()
+}
@@ -0,0 +1,29 @@
+package ui
+
+import (
+	"os"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+)
+
+// WriteClipboard copies text to the system clipboard. Tests replace it.
+var WriteClipboard = clipboard.WriteAll
+
+// CopyToClipboard copies text to the system clipboard, falling back to
+// asking the terminal to copy it (OSC 52), which also works over SSH.
+// viaTerminal reports that the fallback was used; whether the terminal
+// honoured it can't be known.
+func CopyToClipboard(text string) (viaTerminal bool, err error) {
+	if WriteClipboard(text) == nil {
+		return false, nil
+	}
+	seq := osc52.New(text)
+	if os.Getenv("TMUX") != "" {
+		seq = seq.Tmux()
+	}
+	if _, err := seq.WriteTo(os.Stderr); err != nil {
+		return false, err
+	}
+	return true, nil
+}
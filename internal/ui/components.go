@@ -2,12 +2,24 @@ package ui
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"sync/atomic"
 	"time"
+
+	"github.com/charmbracelet/lipgloss"
 )
 
+// Banner is the Skint logo, shown when launching Claude and at the top of
+// the TUI.
+const Banner = ` ____  _    _       _
+/ ___|| | _(_)_ __ | |_
+\___ \| |/ / | '_ \| __|
+ ___) |   <| | | | | |_
+|____/|_|\_\_|_| |_|\__|
+`
+
 // Box draws a box around content
 func Box(title string, width int) {
 	if width < 10 {
@@ -58,8 +70,22 @@ func ListItem(checked bool, format string, a ...interface{}) {
 	fmt.Fprintf(os.Stderr, format+"\n", a...)
 }
 
+// inputDisabled is set by DisableInput.
+var inputDisabled atomic.Bool
+
+// DisableInput stops the prompts reading stdin, for --no-input. Commands
+// should fail with a clear error before prompting; this is the backstop for
+// any that don't: Prompt returns its default, Confirm and ConfirmDanger say
+// no, and PromptSecret fails.
+func DisableInput() {
+	inputDisabled.Store(true)
+}
+
 // Prompt prints a prompt and returns user input
 func Prompt(message, defaultValue string) string {
+	if inputDisabled.Load() {
+		return defaultValue
+	}
 	promptText := message
 	if defaultValue != "" {
 		promptText = fmt.Sprintf("%s [%s]", message, defaultValue)
@@ -83,6 +109,9 @@ func Prompt(message, defaultValue string) string {
 
 // Confirm asks for yes/no confirmation
 func Confirm(message string, defaultYes bool) bool {
+	if inputDisabled.Load() {
+		return false
+	}
 	hint := "[y/N]"
 	if defaultYes {
 		hint = "[Y/n]"
@@ -104,8 +133,31 @@ func Confirm(message string, defaultYes bool) bool {
 	return strings.EqualFold(response, "y") || strings.EqualFold(response, "yes")
 }
 
+// PromptSecret asks for a value without echoing it, e.g. a passphrase. It
+// fails rather than echoing when stdin is not a terminal.
+func PromptSecret(message string) (string, error) {
+	if inputDisabled.Load() {
+		return "", fmt.Errorf("%s: input is disabled (--no-input)", strings.ToLower(message))
+	}
+	if Colors.Enabled {
+		Colors.Cyan.Fprintf(os.Stderr, "%s: ", message)
+	} else {
+		fmt.Fprintf(os.Stderr, "%s: ", message)
+	}
+
+	b, err := readSecret()
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("unable to read hidden input (%w)", err)
+	}
+	return string(b), nil
+}
+
 // ConfirmDanger asks for dangerous confirmation with phrase
 func ConfirmDanger(action, phrase string) bool {
+	if inputDisabled.Load() {
+		return false
+	}
 	fmt.Fprintln(os.Stderr)
 	Box("DANGER", 40)
 	fmt.Fprintln(os.Stderr)
@@ -188,42 +240,57 @@ func (s *Spinner) Stop(success bool) {
 	}
 }
 
-// Table prints a simple table
+// Table prints a simple table to stderr.
 func Table(headers []string, rows [][]string) {
+	TableTo(os.Stderr, headers, rows)
+}
+
+// TableTo prints a simple table to w. Column widths are measured without
+// colour codes, so coloured cells still line up.
+func TableTo(w io.Writer, headers []string, rows [][]string) {
 	// Calculate column widths
 	widths := make([]int, len(headers))
 	for i, h := range headers {
-		widths[i] = len(h)
+		widths[i] = lipgloss.Width(h)
 	}
 
 	for _, row := range rows {
 		for i, cell := range row {
-			if i < len(widths) && len(cell) > widths[i] {
-				widths[i] = len(cell)
+			if i < len(widths) && lipgloss.Width(cell) > widths[i] {
+				widths[i] = lipgloss.Width(cell)
+			}
+		}
+	}
+
+	printRow := func(cells []string) {
+		var b strings.Builder
+		for i, cell := range cells {
+			if i >= len(widths) {
+				break
 			}
+			b.WriteString(cell)
+			b.WriteString(strings.Repeat(" ", widths[i]-lipgloss.Width(cell)+2))
 		}
+		fmt.Fprintln(w, strings.TrimRight(b.String(), " "))
 	}
 
 	// Print headers
+	bold := make([]string, len(headers))
 	for i, h := range headers {
-		fmt.Fprintf(os.Stderr, "%-*s  ", widths[i], Bold(h))
+		bold[i] = Bold(h)
 	}
-	fmt.Fprintln(os.Stderr)
+	printRow(bold)
 
 	// Print separator
+	sep := make([]string, len(headers))
 	for i := range headers {
-		fmt.Fprintf(os.Stderr, "%-*s  ", widths[i], strings.Repeat("-", widths[i]))
+		sep[i] = strings.Repeat("-", widths[i])
 	}
-	fmt.Fprintln(os.Stderr)
+	printRow(sep)
 
 	// Print rows
 	for _, row := range rows {
-		for i, cell := range row {
-			if i < len(widths) {
-				fmt.Fprintf(os.Stderr, "%-*s  ", widths[i], cell)
-			}
-		}
-		fmt.Fprintln(os.Stderr)
+		printRow(row)
 	}
 }
 
@@ -1,6 +1,9 @@
 package ui
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestMaskKey(t *testing.T) {
 	tests := []struct {
@@ -25,3 +28,37 @@ func TestMaskKey(t *testing.T) {
 		})
 	}
 }
+
+func TestTableTo(t *testing.T) {
+	var b strings.Builder
+	TableTo(&b, []string{"NAME", "TYPE"}, [][]string{
+		{"\x1b[32mollama\x1b[0m", "local"},
+		{"openrouter", "openrouter"},
+	})
+
+	want := "NAME        TYPE\n" +
+		"----------  ----------\n" +
+		"\x1b[32mollama\x1b[0m      local\n" +
+		"openrouter  openrouter\n"
+	if got := b.String(); got != want {
+		t.Errorf("TableTo() =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestDisableInput(t *testing.T) {
+	DisableInput()
+	defer inputDisabled.Store(false)
+
+	if got := Prompt("Name", "default"); got != "default" {
+		t.Errorf("Prompt() = %q, want the default", got)
+	}
+	if Confirm("Proceed?", true) {
+		t.Error("Confirm() = true with input disabled")
+	}
+	if ConfirmDanger("Remove everything", "delete") {
+		t.Error("ConfirmDanger() = true with input disabled")
+	}
+	if _, err := PromptSecret("Passphrase"); err == nil {
+		t.Error("PromptSecret() succeeded with input disabled")
+	}
+}
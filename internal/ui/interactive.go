@@ -6,12 +6,11 @@ import (
 	"fmt"
 	"os"
 	"strings"
-	"syscall"
 
 	"github.com/sammcj/skint/internal/config"
+	"github.com/sammcj/skint/internal/models"
 	"github.com/sammcj/skint/internal/providers"
 	"github.com/sammcj/skint/internal/secrets"
-	"golang.org/x/term"
 )
 
 // ConfigForm handles interactive provider configuration
@@ -115,7 +114,7 @@ func (f *ConfigForm) ConfigureBuiltin(cfg *config.Config, name string) error {
 		DisplayName:   def.DisplayName,
 		Description:   def.Description,
 		BaseURL:       def.BaseURL,
-		DefaultModel:  def.DefaultModel,
+		Model:         def.DefaultModel,
 		ModelMappings: def.ModelMappings,
 		APIKeyRef:     ref,
 	}
@@ -146,20 +145,28 @@ func (f *ConfigForm) configureLocal(cfg *config.Config, name string) error {
 	Log("%s", Bold(fmt.Sprintf("Configure: %s", def.DisplayName)))
 	Dim("Endpoint: %s\n\n", def.BaseURL)
 
+	cacheDir, _ := config.GetCacheDir()
+	recommended := models.LoadRecommendations(cacheDir).For(name)
+
 	switch name {
 	case "ollama":
+		pull := "<model>"
+		if len(recommended) > 0 {
+			pull = recommended[0]
+		}
 		Log("Ollama serves local models with Anthropic-compatible API.")
 		fmt.Println()
 		Log("%s:", Bold("Setup"))
 		fmt.Println("  1. Install Ollama: https://ollama.com")
-		fmt.Println("  2. Pull a model: ollama pull qwen3-coder")
+		fmt.Println("  2. Pull a model: ollama pull " + pull)
 		fmt.Println("  3. Start serving: ollama serve")
-		fmt.Println()
-		Log("%s:", Bold("Recommended models"))
-		Dim("  → qwen3-coder\n")
-		Dim("  → glm-5\n")
-		Dim("  → gpt-oss:20b\n")
-		Dim("  → gpt-oss:120b\n")
+		if len(recommended) > 0 {
+			fmt.Println()
+			Log("%s:", Bold("Recommended models"))
+			for _, id := range recommended {
+				Dim("  → %s\n", id)
+			}
+		}
 	case "lmstudio":
 		Log("LM Studio runs local models with Anthropic-compatible API.")
 		fmt.Println()
@@ -169,7 +176,18 @@ func (f *ConfigForm) configureLocal(cfg *config.Config, name string) error {
 		fmt.Println("  3. Start the server (port 1234)")
 		fmt.Println()
 		Log("%s:", Bold("Usage"))
-		Dim("  skint use lmstudio --model <model-name>\n")
+		model := "<model-name>"
+		if len(recommended) > 0 {
+			model = recommended[0]
+		}
+		Dim("  skint use lmstudio --model %s\n", model)
+		if len(recommended) > 0 {
+			fmt.Println()
+			Log("%s:", Bold("Recommended models"))
+			for _, id := range recommended {
+				Dim("  → %s\n", id)
+			}
+		}
 	case "llamacpp":
 		Log("llama.cpp's llama-server with Anthropic-compatible API.")
 		fmt.Println()
@@ -291,7 +309,7 @@ func (f *ConfigForm) addOpenRouterModel(cfg *config.Config) error {
 	}
 
 	// Create full name
-	fullName := "or-" + shortName
+	fullName := config.OpenRouterPrefix + shortName
 
 	// Remove existing if present
 	cfg.RemoveProvider(fullName)
@@ -307,7 +325,7 @@ func (f *ConfigForm) addOpenRouterModel(cfg *config.Config) error {
 		Name:        fullName,
 		Type:        config.ProviderTypeOpenRouter,
 		DisplayName: fmt.Sprintf("OpenRouter %s", shortName),
-		BaseURL:     "https://openrouter.ai/api",
+		BaseURL:     config.OpenRouterBaseURL,
 		Model:       modelID,
 		APIKeyRef:   ref,
 	}
@@ -391,10 +409,10 @@ func (f *ConfigForm) promptSecret(prompt string) string {
 	fmt.Printf("%s: ", prompt)
 
 	// Try to use terminal for hidden input
-	bytePassword, err := term.ReadPassword(int(syscall.Stdin))
+	bytePassword, err := readSecret()
 	if err != nil {
 		// Do not fall back to echoing input -- that would display the secret
-		fmt.Fprintln(os.Stderr, "\nWarning: unable to read secret input (no terminal available)")
+		fmt.Fprintf(os.Stderr, "\nWarning: unable to read secret input (%v)\n", err)
 		return ""
 	}
 
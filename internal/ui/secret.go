@@ -0,0 +1,23 @@
+package ui
+
+import (
+	"errors"
+	"os"
+	"runtime"
+
+	"golang.org/x/term"
+)
+
+// readSecret reads a line from the terminal on stdin without echoing it,
+// using the console's own input mode on Windows.
+func readSecret() ([]byte, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		if runtime.GOOS == "windows" {
+			// mintty (Git Bash, MSYS2) gives programs pipes, not the console
+			return nil, errors.New("no console available; in Git Bash, run skint through winpty")
+		}
+		return nil, errors.New("no terminal available")
+	}
+	return term.ReadPassword(fd)
+}
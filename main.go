@@ -1,7 +1,6 @@
 package main
 
 import (
-	"fmt"
 	"os"
 
 	"github.com/sammcj/skint/internal/commands"
@@ -17,19 +16,36 @@ func main() {
 	// Add subcommands
 	rootCmd.AddCommand(commands.NewConfigCmd())
 	rootCmd.AddCommand(commands.NewUseCmd())
+	rootCmd.AddCommand(commands.NewSwitchCmd())
+	rootCmd.AddCommand(commands.NewRunCmd())
+	rootCmd.AddCommand(commands.NewAliasCmd())
 	rootCmd.AddCommand(commands.NewEnvCmd())
 	rootCmd.AddCommand(commands.NewExecCmd())
+	rootCmd.AddCommand(commands.NewExportEnvCmd())
 	rootCmd.AddCommand(commands.NewListCmd())
 	rootCmd.AddCommand(commands.NewInfoCmd())
+	rootCmd.AddCommand(commands.NewModelsCmd())
 	rootCmd.AddCommand(commands.NewTestCmd())
 	rootCmd.AddCommand(commands.NewStatusCmd())
+	rootCmd.AddCommand(commands.NewCurrentCmd())
+	rootCmd.AddCommand(commands.NewHistoryCmd())
+	rootCmd.AddCommand(commands.NewSessionsCmd())
+	rootCmd.AddCommand(commands.NewDoctorCmd())
+	rootCmd.AddCommand(commands.NewDetectCmd())
+	rootCmd.AddCommand(commands.NewGenerateScriptsCmd())
+	rootCmd.AddCommand(commands.NewSetupPathCmd())
 	rootCmd.AddCommand(commands.NewGenerateCmd())
 	rootCmd.AddCommand(commands.NewMigrateCmd())
+	rootCmd.AddCommand(commands.NewUpgradeConfigCmd())
+	rootCmd.AddCommand(commands.NewInitCmd())
+	rootCmd.AddCommand(commands.NewExportCmd())
+	rootCmd.AddCommand(commands.NewSyncCmd())
 	rootCmd.AddCommand(commands.NewUninstallCmd())
+	rootCmd.AddCommand(commands.NewGenDocsCmd())
+
+	// Aliases last, so they can't shadow a command
+	rootCmd.AddAliasCommands()
 
 	// Execute
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
-	}
+	os.Exit(rootCmd.Run())
 }